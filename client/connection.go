@@ -17,6 +17,7 @@ import (
 
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/oci"
 	"github.com/lxc/incus/v6/shared/simplestreams"
 	"github.com/lxc/incus/v6/shared/util"
 )
@@ -59,6 +60,9 @@ type ConnectionArgs struct {
 	// OpenID Connect tokens
 	OIDCTokens *oidc.Tokens[*oidc.IDTokenClaims]
 
+	// Scoped API token (passed as a bearer token)
+	BearerToken string
+
 	// Skip automatic GetServer request upon connection
 	SkipGetServer bool
 
@@ -347,6 +351,12 @@ func ConnectOCI(uri string, args *ConnectionArgs) (ImageServer, error) {
 
 	server.http = httpClient
 
+	// Setup the registry client
+	server.registry, err = oci.NewClient(httpClient, uri, args.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+
 	return &server, nil
 }
 
@@ -381,6 +391,11 @@ func httpsIncus(ctx context.Context, requestURL string, args *ConnectionArgs) (I
 		server.RequireAuthenticated(true)
 	}
 
+	if args.BearerToken != "" {
+		server.bearerToken = args.BearerToken
+		server.RequireAuthenticated(true)
+	}
+
 	// Setup the HTTP client
 	httpClient, err := tlsHTTPClient(args.HTTPClient, args.TLSClientCert, args.TLSClientKey, args.TLSCA, args.TLSServerCert, args.InsecureSkipVerify, args.Proxy, args.TransportWrapper)
 	if err != nil {
@@ -3,6 +3,7 @@ package incus
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/lxc/incus/v6/shared/api"
@@ -19,6 +20,10 @@ type EventListener struct {
 	projectName string
 	targets     []*EventTarget
 	targetsLock sync.Mutex
+
+	// buffering indicates that the events are held in pending until the first handler is added.
+	buffering bool
+	pending   []api.Event
 }
 
 // The EventTarget struct is returned to the caller of AddHandler and used in RemoveHandler.
@@ -46,9 +51,43 @@ func (e *EventListener) AddHandler(types []string, function func(api.Event)) (*E
 	// And add it to the targets
 	e.targets = append(e.targets, &target)
 
+	// Send the held events in order.
+	if e.buffering {
+		pending := e.pending
+		e.buffering = false
+		e.pending = nil
+
+		go func() {
+			for _, event := range pending {
+				if target.types == nil || slices.Contains(target.types, event.Type) {
+					target.function(event)
+				}
+			}
+		}()
+	}
+
 	return &target, nil
 }
 
+// dispatch sends the event to the matching handlers.
+func (e *EventListener) dispatch(event api.Event) {
+	e.targetsLock.Lock()
+	defer e.targetsLock.Unlock()
+
+	if e.buffering {
+		e.pending = append(e.pending, event)
+		return
+	}
+
+	for _, target := range e.targets {
+		if target.types != nil && !slices.Contains(target.types, event.Type) {
+			continue
+		}
+
+		go target.function(event)
+	}
+}
+
 // RemoveHandler removes a function to be called whenever an event is received.
 func (e *EventListener) RemoveHandler(target *EventTarget) error {
 	if target == nil {
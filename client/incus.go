@@ -50,6 +50,8 @@ type ProtocolIncus struct {
 	project       string
 
 	oidcClient *oidcClient
+
+	bearerToken string
 }
 
 // Disconnect gets rid of any background goroutines.
@@ -183,6 +185,7 @@ func (r *ProtocolIncus) DoWebsocket(dialer websocket.Dialer, uri string, req *ht
 // User-Agent (if r.httpUserAgent is set).
 // X-Incus-authenticated (if r.requireAuthenticated is set).
 // OIDC Authorization header (if r.oidcClient is set).
+// API token Authorization header (if r.bearerToken is set).
 func (r *ProtocolIncus) addClientHeaders(req *http.Request) {
 	if r.httpUserAgent != "" {
 		req.Header.Set("User-Agent", r.httpUserAgent)
@@ -194,6 +197,8 @@ func (r *ProtocolIncus) addClientHeaders(req *http.Request) {
 
 	if r.oidcClient != nil {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.oidcClient.getAccessToken()))
+	} else if r.bearerToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.bearerToken))
 	}
 }
 
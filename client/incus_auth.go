@@ -0,0 +1,273 @@
+package incus
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetAuthGroupNames returns a list of authorization group names.
+func (r *ProtocolIncus) GetAuthGroupNames() ([]string, error) {
+	if !r.HasExtension("auth_rbac") {
+		return nil, fmt.Errorf(`The server is missing the required "auth_rbac" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/auth/groups"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetAuthGroups returns a list of authorization group structs.
+func (r *ProtocolIncus) GetAuthGroups() ([]api.AuthGroup, error) {
+	if !r.HasExtension("auth_rbac") {
+		return nil, fmt.Errorf(`The server is missing the required "auth_rbac" API extension`)
+	}
+
+	groups := []api.AuthGroup{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/auth/groups?recursion=1", nil, "", &groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// GetAuthGroup returns an authorization group for the provided name.
+func (r *ProtocolIncus) GetAuthGroup(name string) (*api.AuthGroup, string, error) {
+	if !r.HasExtension("auth_rbac") {
+		return nil, "", fmt.Errorf(`The server is missing the required "auth_rbac" API extension`)
+	}
+
+	group := api.AuthGroup{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), nil, "", &group)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &group, etag, nil
+}
+
+// CreateAuthGroup defines a new authorization group using the provided struct.
+func (r *ProtocolIncus) CreateAuthGroup(group api.AuthGroupsPost) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf(`The server is missing the required "auth_rbac" API extension`)
+	}
+
+	if len(group.IdentityProviderGroups) > 0 && !r.HasExtension("oidc_groups_claim") {
+		return fmt.Errorf(`The server is missing the required "oidc_groups_claim" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", "/auth/groups", group, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateAuthGroup updates the authorization group to match the provided struct.
+func (r *ProtocolIncus) UpdateAuthGroup(name string, group api.AuthGroupPut, ETag string) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf(`The server is missing the required "auth_rbac" API extension`)
+	}
+
+	if len(group.IdentityProviderGroups) > 0 && !r.HasExtension("oidc_groups_claim") {
+		return fmt.Errorf(`The server is missing the required "oidc_groups_claim" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("PUT", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), group, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameAuthGroup renames an existing authorization group.
+func (r *ProtocolIncus) RenameAuthGroup(name string, group api.AuthGroupPost) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf(`The server is missing the required "auth_rbac" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), group, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAuthGroup deletes an existing authorization group.
+func (r *ProtocolIncus) DeleteAuthGroup(name string) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf(`The server is missing the required "auth_rbac" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAuthIdentities returns a list of identity structs.
+func (r *ProtocolIncus) GetAuthIdentities() ([]api.AuthIdentity, error) {
+	if !r.HasExtension("auth_rbac") {
+		return nil, fmt.Errorf(`The server is missing the required "auth_rbac" API extension`)
+	}
+
+	identities := []api.AuthIdentity{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/auth/identities?recursion=1", nil, "", &identities)
+	if err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// GetAuthIdentity returns the identity for the provided authentication method and identifier.
+func (r *ProtocolIncus) GetAuthIdentity(authMethod string, identifier string) (*api.AuthIdentity, string, error) {
+	if !r.HasExtension("auth_rbac") {
+		return nil, "", fmt.Errorf(`The server is missing the required "auth_rbac" API extension`)
+	}
+
+	identity := api.AuthIdentity{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/auth/identities/%s/%s", url.PathEscape(authMethod), url.PathEscape(identifier)), nil, "", &identity)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &identity, etag, nil
+}
+
+// CreateAuthIdentity defines a new identity using the provided struct.
+func (r *ProtocolIncus) CreateAuthIdentity(identity api.AuthIdentitiesPost) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf(`The server is missing the required "auth_rbac" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", "/auth/identities", identity, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateAuthIdentity updates the identity to match the provided struct.
+func (r *ProtocolIncus) UpdateAuthIdentity(authMethod string, identifier string, identity api.AuthIdentityPut, ETag string) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf(`The server is missing the required "auth_rbac" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("PUT", fmt.Sprintf("/auth/identities/%s/%s", url.PathEscape(authMethod), url.PathEscape(identifier)), identity, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAuthIdentity deletes an existing identity.
+func (r *ProtocolIncus) DeleteAuthIdentity(authMethod string, identifier string) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf(`The server is missing the required "auth_rbac" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/auth/identities/%s/%s", url.PathEscape(authMethod), url.PathEscape(identifier)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAuthTokens returns a list of scoped API token structs.
+func (r *ProtocolIncus) GetAuthTokens() ([]api.AuthToken, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, fmt.Errorf(`The server is missing the required "auth_tokens" API extension`)
+	}
+
+	tokens := []api.AuthToken{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/auth/tokens?recursion=1", nil, "", &tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// GetAuthToken returns the scoped API token with the provided identifier.
+func (r *ProtocolIncus) GetAuthToken(id string) (*api.AuthToken, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, fmt.Errorf(`The server is missing the required "auth_tokens" API extension`)
+	}
+
+	token := api.AuthToken{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/auth/tokens/%s", url.PathEscape(id)), nil, "", &token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// CreateAuthToken creates a new scoped API token and returns its secret.
+func (r *ProtocolIncus) CreateAuthToken(token api.AuthTokensPost) (*api.AuthTokenSecret, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, fmt.Errorf(`The server is missing the required "auth_tokens" API extension`)
+	}
+
+	secret := api.AuthTokenSecret{}
+
+	// Send the request.
+	_, err := r.queryStruct("POST", "/auth/tokens", token, "", &secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secret, nil
+}
+
+// DeleteAuthToken revokes the scoped API token with the provided identifier.
+func (r *ProtocolIncus) DeleteAuthToken(id string) error {
+	if !r.HasExtension("auth_tokens") {
+		return fmt.Errorf(`The server is missing the required "auth_tokens" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/auth/tokens/%s", url.PathEscape(id)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"slices"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -141,19 +140,87 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 			// Send the message to all handlers
 			r.eventListenersLock.Lock()
 			for _, listener := range r.eventListeners[listener.projectName] {
-				listener.targetsLock.Lock()
-				for _, target := range listener.targets {
-					if target.types != nil && !slices.Contains(target.types, event.Type) {
-						continue
-					}
+				listener.dispatch(event)
+			}
+
+			r.eventListenersLock.Unlock()
+		}
+	}()
+
+	return &listener, nil
+}
+
+// getEventsSince connects to the Incus monitoring interface, replaying the events recorded after the given event ID.
+// Replaying requires a dedicated connection which isn't shared with the other listeners.
+func (r *ProtocolIncus) getEventsSince(allProjects bool, since uint64) (*EventListener, error) {
+	if !r.HasExtension("event_history") {
+		return nil, fmt.Errorf("The server is missing the required \"event_history\" API extension")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Setup a new listener, holding the events until a handler is added.
+	listener := EventListener{
+		r:         r,
+		ctx:       ctx,
+		ctxCancel: cancel,
+		buffering: true,
+	}
+
+	var url string
+	var err error
+	if allProjects {
+		url, err = r.setQueryAttributes(fmt.Sprintf("/events?all-projects=true&since=%d", since))
+	} else {
+		url, err = r.setQueryAttributes(fmt.Sprintf("/events?since=%d", since))
+	}
+
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	wsConn, err := r.websocket(url)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// Close the connection once the listener is disconnected.
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-r.ctxConnected.Done():
+			cancel()
+		}
+
+		_ = wsConn.Close()
+	}()
 
-					go target.function(event)
+	// Spawn the listener
+	go func() {
+		for {
+			_, data, err := wsConn.ReadMessage()
+			if err != nil {
+				r.eventListenersLock.Lock()
+				if listener.ctx.Err() == nil {
+					listener.err = err
+					listener.ctxCancel()
 				}
 
-				listener.targetsLock.Unlock()
+				r.eventListenersLock.Unlock()
+
+				return
 			}
 
-			r.eventListenersLock.Unlock()
+			// Attempt to unpack the message
+			event := api.Event{}
+			err = json.Unmarshal(data, &event)
+			if err != nil || event.Type == "" {
+				continue
+			}
+
+			listener.dispatch(event)
 		}
 	}()
 
@@ -170,6 +237,16 @@ func (r *ProtocolIncus) GetEventsAllProjects() (*EventListener, error) {
 	return r.getEvents(true)
 }
 
+// GetEventsSince gets the events for the project defined on the client, starting with the events recorded after the given event ID.
+func (r *ProtocolIncus) GetEventsSince(id uint64) (*EventListener, error) {
+	return r.getEventsSince(false, id)
+}
+
+// GetEventsAllProjectsSince gets events for all projects, starting with the events recorded after the given event ID.
+func (r *ProtocolIncus) GetEventsAllProjectsSince(id uint64) (*EventListener, error) {
+	return r.getEventsSince(true, id)
+}
+
 // SendEvent send an event to the server via the client's event listener connection.
 func (r *ProtocolIncus) SendEvent(event api.Event) error {
 	r.eventConnsLock.Lock()
@@ -187,6 +187,32 @@ func (r *ProtocolIncus) GetPrivateImageFile(fingerprint string, secret string, r
 	return incusDownloadImage(fingerprint, uri, r.httpUserAgent, r.DoHTTP, req)
 }
 
+// GetImageOCIFile downloads a container image converted to an OCI image layout tarball.
+func (r *ProtocolIncus) GetImageOCIFile(fingerprint string, req ImageFileRequest) (*ImageFileResponse, error) {
+	if !r.HasExtension("images_oci") {
+		return nil, fmt.Errorf("The server is missing the required \"images_oci\" API extension")
+	}
+
+	// Quick checks.
+	if req.MetaFile == nil {
+		return nil, fmt.Errorf("No file requested")
+	}
+
+	if req.RootfsFile != nil {
+		return nil, fmt.Errorf("OCI images are always exported as a single file")
+	}
+
+	uri := fmt.Sprintf("/1.0/images/%s/export?format=oci", url.PathEscape(fingerprint))
+
+	var err error
+	uri, err = r.setQueryAttributes(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return incusDownloadImage(fingerprint, r.httpBaseURL.String()+uri, r.httpUserAgent, r.DoHTTP, req)
+}
+
 func incusDownloadImage(fingerprint string, uri string, userAgent string, do func(*http.Request) (*http.Response, error), req ImageFileRequest) (*ImageFileResponse, error) {
 	// Prepare the response
 	resp := ImageFileResponse{}
@@ -1055,6 +1081,10 @@ func (r *ProtocolIncus) ExportImage(fingerprint string, image api.ImageExportPos
 		return nil, fmt.Errorf("The server is missing the required \"images_push_relay\" API extension")
 	}
 
+	if image.Protocol == "oci" && !r.HasExtension("images_oci") {
+		return nil, fmt.Errorf("The server is missing the required \"images_oci\" API extension")
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("/images/%s/export", url.PathEscape(fingerprint)), &image, "")
 	if err != nil {
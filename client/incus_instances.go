@@ -521,6 +521,38 @@ func (r *ProtocolIncus) CreateInstanceFromBackup(args InstanceBackupArgs) (Opera
 		return nil, err
 	}
 
+	if args.Bucket != nil {
+		if !r.HasExtension("backup_bucket") {
+			return nil, fmt.Errorf(`The server is missing the required "backup_bucket" API extension`)
+		}
+
+		req := api.InstancesPost{
+			Name: args.Name,
+			Source: api.InstanceSource{
+				Type:   "backup",
+				Bucket: args.Bucket,
+			},
+		}
+
+		if args.PoolName != "" {
+			req.Devices = map[string]map[string]string{
+				"root": {
+					"type": "disk",
+					"path": "/",
+					"pool": args.PoolName,
+				},
+			}
+		}
+
+		// Send the request
+		op, _, err := r.queryOperation("POST", path, req, "")
+		if err != nil {
+			return nil, err
+		}
+
+		return op, nil
+	}
+
 	if args.PoolName == "" && args.Name == "" {
 		// Send the request
 		op, _, err := r.queryOperation("POST", path, args.BackupFile, "")
@@ -2830,6 +2862,14 @@ func (r *ProtocolIncus) CreateInstanceBackup(instanceName string, backup api.Ins
 		return nil, fmt.Errorf("The server is missing the required \"container_backup\" API extension")
 	}
 
+	if (backup.IncrementalFrom != "" || backup.IncrementalBase) && !r.HasExtension("instance_backup_incremental") {
+		return nil, fmt.Errorf("The server is missing the required \"instance_backup_incremental\" API extension")
+	}
+
+	if backup.Target != nil && !r.HasExtension("backup_bucket") {
+		return nil, fmt.Errorf("The server is missing the required \"backup_bucket\" API extension")
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/backups", path, url.PathEscape(instanceName)), backup, "")
 	if err != nil {
@@ -118,6 +118,7 @@ func (r *ProtocolIncus) UseProject(name string) InstanceServer {
 		eventConns:           make(map[string]*websocket.Conn),  // New project specific listener conns.
 		eventListeners:       make(map[string][]*EventListener), // New project specific listeners.
 		oidcClient:           r.oidcClient,
+		bearerToken:          r.bearerToken,
 	}
 }
 
@@ -141,6 +142,7 @@ func (r *ProtocolIncus) UseTarget(name string) InstanceServer {
 		eventConns:           make(map[string]*websocket.Conn),  // New target specific listener conns.
 		eventListeners:       make(map[string][]*EventListener), // New target specific listeners.
 		oidcClient:           r.oidcClient,
+		bearerToken:          r.bearerToken,
 		clusterTarget:        name,
 	}
 }
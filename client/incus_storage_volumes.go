@@ -876,6 +876,10 @@ func (r *ProtocolIncus) CreateStorageVolumeBackup(pool string, volName string, b
 		return nil, fmt.Errorf("The server is missing the required \"custom_volume_backup\" API extension")
 	}
 
+	if backup.Target != nil && !r.HasExtension("backup_bucket") {
+		return nil, fmt.Errorf("The server is missing the required \"backup_bucket\" API extension")
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("/storage-pools/%s/volumes/custom/%s/backups", url.PathEscape(pool), url.PathEscape(volName)), backup, "")
 	if err != nil {
@@ -1051,6 +1055,29 @@ func (r *ProtocolIncus) CreateStoragePoolVolumeFromBackup(pool string, args Stor
 
 	path := fmt.Sprintf("/storage-pools/%s/volumes/custom", url.PathEscape(pool))
 
+	if args.Bucket != nil {
+		if !r.HasExtension("backup_bucket") {
+			return nil, fmt.Errorf(`The server is missing the required "backup_bucket" API extension`)
+		}
+
+		req := api.StorageVolumesPost{
+			Name: args.Name,
+			Type: "custom",
+			Source: api.StorageVolumeSource{
+				Type:   "backup",
+				Bucket: args.Bucket,
+			},
+		}
+
+		// Send the request.
+		op, _, err := r.queryOperation("POST", path, req, "")
+		if err != nil {
+			return nil, err
+		}
+
+		return op, nil
+	}
+
 	// Prepare the HTTP request.
 	reqURL, err := r.setQueryAttributes(fmt.Sprintf("%s/1.0%s", r.httpBaseURL.String(), path))
 	if err != nil {
@@ -0,0 +1,120 @@
+package incus
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetWebhookNames returns a list of webhook names.
+func (r *ProtocolIncus) GetWebhookNames() ([]string, error) {
+	if !r.HasExtension("webhooks") {
+		return nil, fmt.Errorf(`The server is missing the required "webhooks" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/webhooks"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetWebhooks returns a list of webhook structs.
+func (r *ProtocolIncus) GetWebhooks() ([]api.Webhook, error) {
+	if !r.HasExtension("webhooks") {
+		return nil, fmt.Errorf(`The server is missing the required "webhooks" API extension`)
+	}
+
+	webhooks := []api.Webhook{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/webhooks?recursion=1", nil, "", &webhooks)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhook returns a webhook entry.
+func (r *ProtocolIncus) GetWebhook(name string) (*api.Webhook, string, error) {
+	if !r.HasExtension("webhooks") {
+		return nil, "", fmt.Errorf(`The server is missing the required "webhooks" API extension`)
+	}
+
+	webhook := api.Webhook{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/webhooks/%s", url.PathEscape(name)), nil, "", &webhook)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &webhook, etag, nil
+}
+
+// CreateWebhook defines a new webhook using the provided struct.
+func (r *ProtocolIncus) CreateWebhook(webhook api.WebhooksPost) error {
+	if !r.HasExtension("webhooks") {
+		return fmt.Errorf(`The server is missing the required "webhooks" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", "/webhooks", webhook, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateWebhook updates the webhook to match the provided struct.
+func (r *ProtocolIncus) UpdateWebhook(name string, webhook api.WebhookPut, ETag string) error {
+	if !r.HasExtension("webhooks") {
+		return fmt.Errorf(`The server is missing the required "webhooks" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("PUT", fmt.Sprintf("/webhooks/%s", url.PathEscape(name)), webhook, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameWebhook renames an existing webhook entry.
+func (r *ProtocolIncus) RenameWebhook(name string, webhook api.WebhookPost) error {
+	if !r.HasExtension("webhooks") {
+		return fmt.Errorf(`The server is missing the required "webhooks" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", fmt.Sprintf("/webhooks/%s", url.PathEscape(name)), webhook, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteWebhook deletes an existing webhook.
+func (r *ProtocolIncus) DeleteWebhook(name string) error {
+	if !r.HasExtension("webhooks") {
+		return fmt.Errorf(`The server is missing the required "webhooks" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/webhooks/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
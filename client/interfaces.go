@@ -94,6 +94,24 @@ type InstanceServer interface {
 	DeleteCertificate(fingerprint string) (err error)
 	CreateCertificateToken(certificate api.CertificatesPost) (op Operation, err error)
 
+	// Authorization functions
+	GetAuthGroupNames() (names []string, err error)
+	GetAuthGroups() (groups []api.AuthGroup, err error)
+	GetAuthGroup(name string) (group *api.AuthGroup, ETag string, err error)
+	CreateAuthGroup(group api.AuthGroupsPost) (err error)
+	UpdateAuthGroup(name string, group api.AuthGroupPut, ETag string) (err error)
+	RenameAuthGroup(name string, group api.AuthGroupPost) (err error)
+	DeleteAuthGroup(name string) (err error)
+	GetAuthIdentities() (identities []api.AuthIdentity, err error)
+	GetAuthIdentity(authMethod string, identifier string) (identity *api.AuthIdentity, ETag string, err error)
+	CreateAuthIdentity(identity api.AuthIdentitiesPost) (err error)
+	UpdateAuthIdentity(authMethod string, identifier string, identity api.AuthIdentityPut, ETag string) (err error)
+	DeleteAuthIdentity(authMethod string, identifier string) (err error)
+	GetAuthTokens() (tokens []api.AuthToken, err error)
+	GetAuthToken(id string) (token *api.AuthToken, err error)
+	CreateAuthToken(token api.AuthTokensPost) (secret *api.AuthTokenSecret, err error)
+	DeleteAuthToken(id string) (err error)
+
 	// Instance functions.
 	GetInstanceNames(instanceType api.InstanceType) (names []string, err error)
 	GetInstanceNamesAllProjects(instanceType api.InstanceType) (names map[string][]string, err error)
@@ -173,6 +191,8 @@ type InstanceServer interface {
 	// Event handling functions
 	GetEvents() (listener *EventListener, err error)
 	GetEventsAllProjects() (listener *EventListener, err error)
+	GetEventsSince(id uint64) (listener *EventListener, err error)
+	GetEventsAllProjectsSince(id uint64) (listener *EventListener, err error)
 	SendEvent(event api.Event) error
 
 	// Image functions
@@ -181,6 +201,7 @@ type InstanceServer interface {
 	UpdateImage(fingerprint string, image api.ImagePut, ETag string) (err error)
 	DeleteImage(fingerprint string) (op Operation, err error)
 	RefreshImage(fingerprint string) (op Operation, err error)
+	GetImageOCIFile(fingerprint string, req ImageFileRequest) (resp *ImageFileResponse, err error)
 	CreateImageSecret(fingerprint string) (op Operation, err error)
 	CreateImageAlias(alias api.ImageAliasesPost) (err error)
 	UpdateImageAlias(name string, alias api.ImageAliasesEntryPut, ETag string) (err error)
@@ -238,6 +259,16 @@ type InstanceServer interface {
 	RenameNetworkACL(name string, acl api.NetworkACLPost) (err error)
 	DeleteNetworkACL(name string) (err error)
 
+	// Network address set functions ("network_address_set" API extension)
+	GetNetworkAddressSetNames() (names []string, err error)
+	GetNetworkAddressSets() (sets []api.NetworkAddressSet, err error)
+	GetNetworkAddressSetsAllProjects() (sets []api.NetworkAddressSet, err error)
+	GetNetworkAddressSet(name string) (set *api.NetworkAddressSet, ETag string, err error)
+	CreateNetworkAddressSet(set api.NetworkAddressSetsPost) (err error)
+	UpdateNetworkAddressSet(name string, set api.NetworkAddressSetPut, ETag string) (err error)
+	RenameNetworkAddressSet(name string, set api.NetworkAddressSetPost) (err error)
+	DeleteNetworkAddressSet(name string) (err error)
+
 	// Network allocations functions ("network_allocations" API extension)
 	GetNetworkAllocations() (allocations []api.NetworkAllocations, err error)
 	GetNetworkAllocationsAllProjects() (allocations []api.NetworkAllocations, err error)
@@ -396,6 +427,15 @@ type InstanceServer interface {
 	UpdateWarning(UUID string, warning api.WarningPut, ETag string) (err error)
 	DeleteWarning(UUID string) (err error)
 
+	// Webhook functions ("webhooks" API extension)
+	GetWebhookNames() (names []string, err error)
+	GetWebhooks() (webhooks []api.Webhook, err error)
+	GetWebhook(name string) (webhook *api.Webhook, ETag string, err error)
+	CreateWebhook(webhook api.WebhooksPost) (err error)
+	UpdateWebhook(name string, webhook api.WebhookPut, ETag string) (err error)
+	RenameWebhook(name string, webhook api.WebhookPost) (err error)
+	DeleteWebhook(name string) (err error)
+
 	// Internal functions (for internal use)
 	RawQuery(method string, path string, data any, queryETag string) (resp *api.Response, ETag string, err error)
 	RawWebsocket(path string) (conn *websocket.Conn, err error)
@@ -546,6 +586,10 @@ type StorageVolumeBackupArgs struct {
 
 	// Name to import backup as
 	Name string
+
+	// Storage bucket holding the backup file (instead of BackupFile)
+	// API extension: backup_bucket
+	Bucket *api.BackupBucket
 }
 
 // The InstanceBackupArgs struct is used when creating a instance from a backup.
@@ -558,6 +602,10 @@ type InstanceBackupArgs struct {
 
 	// Name to import backup as
 	Name string
+
+	// Storage bucket holding the backup file (instead of BackupFile)
+	// API extension: backup_bucket
+	Bucket *api.BackupBucket
 }
 
 // The InstanceCopyArgs struct is used to pass additional options during instance copy.
@@ -3,6 +3,8 @@ package incus
 import (
 	"fmt"
 	"net/http"
+
+	"github.com/lxc/incus/v6/shared/oci"
 )
 
 // ProtocolOCI implements an OCI registry API client.
@@ -12,6 +14,9 @@ type ProtocolOCI struct {
 	httpUserAgent   string
 	httpCertificate string
 
+	// Registry client.
+	registry *oci.Client
+
 	// Cache for images.
 	cache map[string]ociInfo
 }
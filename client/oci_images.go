@@ -6,10 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -17,35 +15,17 @@ import (
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/ioprogress"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/oci"
 	"github.com/lxc/incus/v6/shared/osarch"
 	"github.com/lxc/incus/v6/shared/subprocess"
 	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 type ociInfo struct {
 	Alias        string
-	Name         string    `json:"Name"`
-	Digest       string    `json:"Digest"`
-	Created      time.Time `json:"Created"`
-	Architecture string    `json:"Architecture"`
-	LayersData   []struct {
-		Size int64 `json:"Size"`
-	} `json:"LayersData"`
-}
-
-// Get the proxy host value.
-func (r *ProtocolOCI) getProxyHost() (*url.URL, error) {
-	req, err := http.NewRequest("GET", r.httpHost, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	proxy, err := r.http.Transport.(*http.Transport).Proxy(req)
-	if err != nil {
-		return nil, err
-	}
-
-	return proxy, nil
+	Architecture string
+	Image        *oci.Image
 }
 
 // Image handling functions
@@ -74,21 +54,21 @@ func (r *ProtocolOCI) GetImagesWithFilter(filters []string) ([]api.Image, error)
 func (r *ProtocolOCI) GetImage(fingerprint string) (*api.Image, string, error) {
 	info, ok := r.cache[fingerprint]
 	if !ok {
-		_, err := exec.LookPath("skopeo")
-		if err != nil {
-			return nil, "", fmt.Errorf("OCI container handling requires \"skopeo\" be present on the system")
-		}
-
 		return nil, "", fmt.Errorf("Image not found")
 	}
 
+	var created time.Time
+	if info.Image.Config.Created != nil {
+		created = *info.Image.Config.Created
+	}
+
 	img := api.Image{
 		ImagePut: api.ImagePut{
 			Public: true,
 			Properties: map[string]string{
 				"architecture": info.Architecture,
 				"type":         "oci",
-				"description":  fmt.Sprintf("%s (OCI)", info.Name),
+				"description":  fmt.Sprintf("%s (OCI)", info.Image.Repository),
 				"id":           info.Alias,
 			},
 		},
@@ -98,17 +78,11 @@ func (r *ProtocolOCI) GetImage(fingerprint string) (*api.Image, string, error) {
 		Architecture: info.Architecture,
 		Fingerprint:  fingerprint,
 		Type:         string(api.InstanceTypeContainer),
-		CreatedAt:    info.Created,
-		UploadedAt:   info.Created,
+		CreatedAt:    created,
+		UploadedAt:   created,
+		Size:         info.Image.Size(),
 	}
 
-	var size int64
-	for _, layer := range info.LayersData {
-		size += layer.Size
-	}
-
-	img.Size = size
-
 	return &img, "", nil
 }
 
@@ -116,28 +90,9 @@ func (r *ProtocolOCI) GetImage(fingerprint string) (*api.Image, string, error) {
 func (r *ProtocolOCI) GetImageFile(fingerprint string, req ImageFileRequest) (*ImageFileResponse, error) {
 	ctx := context.Background()
 
-	// Get proxy details.
-	proxy, err := r.getProxyHost()
-	if err != nil {
-		return nil, err
-	}
-
-	var env []string
-	if proxy != nil {
-		env = []string{
-			fmt.Sprintf("HTTPS_PROXY=%s", proxy),
-			fmt.Sprintf("HTTP_PROXY=%s", proxy),
-		}
-	}
-
 	// Get the cached entry.
 	info, ok := r.cache[fingerprint]
 	if !ok {
-		_, err := exec.LookPath("skopeo")
-		if err != nil {
-			return nil, fmt.Errorf("OCI container handling requires \"skopeo\" be present on the system")
-		}
-
 		return nil, fmt.Errorf("Image not found")
 	}
 
@@ -150,11 +105,6 @@ func (r *ProtocolOCI) GetImageFile(fingerprint string, req ImageFileRequest) (*I
 		return nil, fmt.Errorf("OCI image export currently requires root access")
 	}
 
-	_, err = exec.LookPath("umoci")
-	if err != nil {
-		return nil, fmt.Errorf("OCI container handling requires \"umoci\" be present on the system")
-	}
-
 	// Get some temporary storage.
 	ociPath, err := os.MkdirTemp("", "incus-oci-")
 	if err != nil {
@@ -163,52 +113,86 @@ func (r *ProtocolOCI) GetImageFile(fingerprint string, req ImageFileRequest) (*I
 
 	defer func() { _ = os.RemoveAll(ociPath) }()
 
-	err = os.Mkdir(filepath.Join(ociPath, "oci"), 0700)
+	rootfsPath := filepath.Join(ociPath, "image", "rootfs")
+	err = os.MkdirAll(rootfsPath, 0755)
 	if err != nil {
 		return nil, err
 	}
 
-	err = os.Mkdir(filepath.Join(ociPath, "image"), 0700)
-	if err != nil {
-		return nil, err
+	// Retrieve and unpack the layers.
+	layers := info.Image.Manifest.Layers
+	for i, layer := range layers {
+		body, err := r.registry.GetBlob(ctx, info.Image.Repository, layer)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve layer %q: %w", layer.Digest, err)
+		}
+
+		var reader io.ReadCloser = body
+		if req.ProgressHandler != nil {
+			reader = &ioprogress.ProgressReader{
+				ReadCloser: body,
+				Tracker: &ioprogress.ProgressTracker{
+					Length: layer.Size,
+					Handler: func(percent int64, speed int64) {
+						req.ProgressHandler(ioprogress.ProgressData{Text: fmt.Sprintf("Retrieving OCI layer %d/%d: %d%% (%s/s)", i+1, len(layers), percent, units.GetByteSizeString(speed, 2))})
+					},
+				},
+			}
+		}
+
+		err = oci.UnpackLayer(reader, rootfsPath)
+		_ = reader.Close()
+		if err != nil {
+			logger.Debug("Error unpacking OCI layer", logger.Ctx{"image": info.Alias, "layer": layer.Digest, "err": err})
+			return nil, fmt.Errorf("Failed to unpack layer %q: %w", layer.Digest, err)
+		}
 	}
 
-	// Copy the image.
+	// Generate the runtime configuration.
 	if req.ProgressHandler != nil {
-		req.ProgressHandler(ioprogress.ProgressData{Text: "Retrieving OCI image from registry"})
-	}
-
-	stdout, _, err := subprocess.RunCommandSplit(
-		ctx,
-		env,
-		nil,
-		"skopeo",
-		"--insecure-policy",
-		"copy",
-		"--remove-signatures",
-		fmt.Sprintf("%s/%s", strings.Replace(r.httpHost, "https://", "docker://", -1), info.Alias),
-		fmt.Sprintf("oci:%s:latest", filepath.Join(ociPath, "oci")))
+		req.ProgressHandler(ioprogress.ProgressData{Text: "Generating image configuration"})
+	}
+
+	spec, err := oci.RuntimeSpec(info.Image.Config, rootfsPath)
 	if err != nil {
-		logger.Debug("Error copying remote image to local", logger.Ctx{"image": info.Alias, "stdout": stdout, "stderr": err})
 		return nil, err
 	}
 
-	// Convert to something usable.
-	if req.ProgressHandler != nil {
-		req.ProgressHandler(ioprogress.ProgressData{Text: "Unpacking the OCI image"})
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
 	}
 
-	stdout, err = subprocess.RunCommand(
-		"umoci",
-		"unpack",
-		"--keep-dirlinks",
-		"--image", filepath.Join(ociPath, "oci"),
-		filepath.Join(ociPath, "image"))
+	err = os.WriteFile(filepath.Join(ociPath, "image", "config.json"), data, 0644)
 	if err != nil {
-		logger.Debug("Error unpacking OCI image", logger.Ctx{"image": filepath.Join(ociPath, "oci"), "stdout": stdout, "stderr": err})
 		return nil, err
 	}
 
+	// Record the source of the image so its layers can be re-used when publishing it back to a registry.
+	if len(info.Image.Manifest.Layers) == len(info.Image.Config.RootFS.DiffIDs) {
+		registryURL, err := url.Parse(r.httpHost)
+		if err != nil {
+			return nil, err
+		}
+
+		source := oci.Source{
+			Registry:   (&url.URL{Scheme: registryURL.Scheme, Host: registryURL.Host}).String(),
+			Repository: info.Image.Repository,
+			Layers:     info.Image.Manifest.Layers,
+			DiffIDs:    info.Image.Config.RootFS.DiffIDs,
+		}
+
+		data, err = json.Marshal(source)
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.WriteFile(filepath.Join(ociPath, "image", oci.SourceFile), data, 0644)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Generate a metadata.yaml.
 	if req.ProgressHandler != nil {
 		req.ProgressHandler(ioprogress.ProgressData{Text: "Generating image metadata"})
@@ -216,10 +200,13 @@ func (r *ProtocolOCI) GetImageFile(fingerprint string, req ImageFileRequest) (*I
 
 	metadata := api.ImageMetadata{
 		Architecture: info.Architecture,
-		CreationDate: info.Created.Unix(),
 	}
 
-	data, err := json.Marshal(metadata)
+	if info.Image.Config.Created != nil {
+		metadata.CreationDate = info.Image.Config.Created.Unix()
+	}
+
+	data, err = json.Marshal(metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -256,7 +243,12 @@ func (r *ProtocolOCI) GetImageFile(fingerprint string, req ImageFileRequest) (*I
 	}
 
 	compressWrite := gzip.NewWriter(pipeWrite)
-	metadataProcess := subprocess.NewProcessWithFds("tar", []string{"-cf", "-", "-C", filepath.Join(ociPath, "image"), "config.json", "metadata.yaml"}, nil, compressWrite, os.Stderr)
+	metadataFiles := []string{"config.json", "metadata.yaml"}
+	if util.PathExists(filepath.Join(ociPath, "image", oci.SourceFile)) {
+		metadataFiles = append(metadataFiles, oci.SourceFile)
+	}
+
+	metadataProcess := subprocess.NewProcessWithFds("tar", append([]string{"-cf", "-", "-C", filepath.Join(ociPath, "image")}, metadataFiles...), nil, compressWrite, os.Stderr)
 	err = metadataProcess.Start(ctx)
 	if err != nil {
 		return nil, err
@@ -341,63 +333,40 @@ func (r *ProtocolOCI) GetImageAliasNames() ([]string, error) {
 
 // GetImageAlias returns an existing alias as an ImageAliasesEntry struct.
 func (r *ProtocolOCI) GetImageAlias(name string) (*api.ImageAliasesEntry, string, error) {
-	// Get proxy details.
-	proxy, err := r.getProxyHost()
-	if err != nil {
-		return nil, "", err
-	}
-
-	var env []string
-	if proxy != nil {
-		env = []string{
-			fmt.Sprintf("HTTPS_PROXY=%s", proxy),
-			fmt.Sprintf("HTTP_PROXY=%s", proxy),
-		}
-	}
-
-	// Get the image information from skopeo.
-	stdout, _, err := subprocess.RunCommandSplit(
-		context.TODO(),
-		env,
-		nil,
-		"skopeo",
-		"inspect",
-		fmt.Sprintf("%s/%s", strings.Replace(r.httpHost, "https://", "docker://", -1), name))
-
+	// Resolve the image for the local architecture.
+	localArch, err := osarch.ArchitectureGetLocal()
 	if err != nil {
-		logger.Debug("Error getting image alias", logger.Ctx{"name": name, "stdout": stdout, "stderr": err})
 		return nil, "", err
 	}
 
-	// Parse the image info.
-	var info ociInfo
-	err = json.Unmarshal([]byte(stdout), &info)
+	platforms, err := oci.ArchitecturePlatforms(localArch)
 	if err != nil {
 		return nil, "", err
 	}
 
-	info.Alias = name
-	info.Digest = strings.Replace(info.Digest, "sha256:", "", -1)
-
-	archID, err := osarch.ArchitectureId(info.Architecture)
+	img, err := r.registry.Resolve(context.TODO(), name, platforms)
 	if err != nil {
+		logger.Debug("Error getting image alias", logger.Ctx{"name": name, "err": err})
 		return nil, "", err
 	}
 
-	archName, err := osarch.ArchitectureName(archID)
+	architecture, err := oci.PlatformArchitecture(oci.Platform{Architecture: img.Config.Architecture, Variant: img.Config.Variant})
 	if err != nil {
 		return nil, "", err
 	}
 
-	info.Architecture = archName
-
 	// Store it in the cache.
-	r.cache[info.Digest] = info
+	fingerprint := strings.TrimPrefix(img.Digest, "sha256:")
+	r.cache[fingerprint] = ociInfo{
+		Alias:        name,
+		Architecture: architecture,
+		Image:        img,
+	}
 
 	// Prepare the alias entry.
 	alias := api.ImageAliasesEntry{
 		ImageAliasesEntryPut: api.ImageAliasesEntryPut{
-			Target: info.Digest,
+			Target: fingerprint,
 		},
 		Name: name,
 		Type: string(api.InstanceTypeContainer),
@@ -31,6 +31,7 @@ var api10 = []APIEndpoint{
 	api10Cmd,
 	execCmd,
 	eventsCmd,
+	fsfreezeCmd,
 	metricsCmd,
 	operationsCmd,
 	operationCmd,
@@ -2,6 +2,7 @@ package main
 
 import (
 	"sync"
+	"time"
 
 	"github.com/lxc/incus/v6/internal/server/events"
 )
@@ -22,6 +23,11 @@ type Daemon struct {
 	DevIncusRunning bool
 	DevIncusMu      sync.Mutex
 	DevIncusEnabled bool
+
+	// File systems currently frozen through the fsfreeze endpoint.
+	fsfreezeMounts []string
+	fsfreezeTimer  *time.Timer
+	fsfreezeMu     sync.Mutex
 }
 
 // newDaemon returns a new Daemon object with the given configuration.
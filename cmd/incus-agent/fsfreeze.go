@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// fsfreezeHookPath is the directory holding the scripts run around freezing the file systems.
+// Each executable is called with "freeze" before the file systems are frozen and with "thaw" once they're thawed.
+const fsfreezeHookPath = "/etc/incus-agent/fsfreeze-hook.d"
+
+// fsfreezeTimeout is how long the file systems may stay frozen before they're thawed automatically,
+// so the guest doesn't hang if the host never asks for it, for example because it crashed.
+var fsfreezeTimeout = 5 * time.Minute
+
+// fsfreezeHookTimeout is how long a single hook may run, kept well below fsfreezeTimeout so a stuck hook
+// can't hold up the freeze or the thaw.
+var fsfreezeHookTimeout = 30 * time.Second
+
+var fsfreezeCmd = APIEndpoint{
+	Name: "fsfreeze",
+	Path: "fsfreeze",
+
+	Post:   APIEndpointAction{Handler: fsfreezePost},
+	Delete: APIEndpointAction{Handler: fsfreezeDelete},
+}
+
+func fsfreezePost(d *Daemon, r *http.Request) response.Response {
+	d.fsfreezeMu.Lock()
+	defer d.fsfreezeMu.Unlock()
+
+	if d.fsfreezeMounts != nil {
+		return response.BadRequest(fmt.Errorf("File systems are already frozen"))
+	}
+
+	f, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return response.InternalError(fmt.Errorf("Failed to open /proc/self/mounts: %w", err))
+	}
+
+	defer func() { _ = f.Close() }()
+
+	mounts, err := fsfreezeMountPoints(f)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	hooks, err := fsfreezeHooks()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	// Let the guest applications prepare, undoing it right away if any of them fail or time out.
+	// The failed hook is thawed too as it may have got partway through.
+	for i, hook := range hooks {
+		err = fsfreezeRunHook(hook, "freeze")
+		if err != nil {
+			fsfreezeRunHooks(hooks[:i+1], "thaw")
+			return response.InternalError(fmt.Errorf("Failed running freeze hook %q: %w", hook, err))
+		}
+	}
+
+	frozen := []string{}
+	for _, mount := range mounts {
+		_, err = subprocess.RunCommand("fsfreeze", "--freeze", mount)
+		if err != nil {
+			fsfreezeThaw(frozen)
+			fsfreezeRunHooks(hooks, "thaw")
+			return response.InternalError(fmt.Errorf("Failed freezing file system %q: %w", mount, err))
+		}
+
+		frozen = append(frozen, mount)
+	}
+
+	d.fsfreezeMounts = frozen
+
+	// Thaw the file systems on our own if the host doesn't do it in time.
+	var timer *time.Timer
+	timer = time.AfterFunc(fsfreezeTimeout, func() {
+		d.fsfreezeMu.Lock()
+		defer d.fsfreezeMu.Unlock()
+
+		// Skip if the file systems were thawed (and possibly frozen again) in the meantime.
+		if d.fsfreezeTimer != timer {
+			return
+		}
+
+		logger.Warn("Thawing file systems frozen for too long", logger.Ctx{"timeout": fsfreezeTimeout})
+
+		err := fsfreezeRelease(d)
+		if err != nil {
+			logger.Warn("Failed thawing file systems", logger.Ctx{"err": err})
+		}
+	})
+
+	d.fsfreezeTimer = timer
+
+	return response.SyncResponse(true, frozen)
+}
+
+func fsfreezeDelete(d *Daemon, r *http.Request) response.Response {
+	d.fsfreezeMu.Lock()
+	defer d.fsfreezeMu.Unlock()
+
+	if d.fsfreezeMounts == nil {
+		return response.EmptySyncResponse
+	}
+
+	err := fsfreezeRelease(d)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// fsfreezeRelease thaws the file systems frozen through the API and runs the thaw hooks.
+// The caller must hold the fsfreeze lock.
+func fsfreezeRelease(d *Daemon) error {
+	if d.fsfreezeTimer != nil {
+		d.fsfreezeTimer.Stop()
+		d.fsfreezeTimer = nil
+	}
+
+	fsfreezeThaw(d.fsfreezeMounts)
+	d.fsfreezeMounts = nil
+
+	hooks, err := fsfreezeHooks()
+	if err != nil {
+		return err
+	}
+
+	fsfreezeRunHooks(hooks, "thaw")
+
+	return nil
+}
+
+// fsfreezeThaw thaws the given file systems in the reverse order they were frozen in.
+func fsfreezeThaw(mounts []string) {
+	for _, mount := range slices.Backward(mounts) {
+		_, err := subprocess.RunCommand("fsfreeze", "--unfreeze", mount)
+		if err != nil {
+			logger.Warn("Failed thawing file system", logger.Ctx{"path": mount, "err": err})
+		}
+	}
+}
+
+// fsfreezeRunHooks runs the given hooks in reverse order, logging any failure.
+func fsfreezeRunHooks(hooks []string, action string) {
+	for _, hook := range slices.Backward(hooks) {
+		err := fsfreezeRunHook(hook, action)
+		if err != nil {
+			logger.Warn("Failed running fsfreeze hook", logger.Ctx{"hook": hook, "action": action, "err": err})
+		}
+	}
+}
+
+// fsfreezeRunHook runs a single hook, killing it if it doesn't complete within fsfreezeHookTimeout.
+func fsfreezeRunHook(hook string, action string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), fsfreezeHookTimeout)
+	defer cancel()
+
+	_, err := subprocess.RunCommandContext(ctx, hook, action)
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("Timed out after %s: %w", fsfreezeHookTimeout, err)
+	}
+
+	return err
+}
+
+// fsfreezeHooks returns the executable hooks, sorted by name.
+func fsfreezeHooks() ([]string, error) {
+	entries, err := os.ReadDir(fsfreezeHookPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Failed listing fsfreeze hooks: %w", err)
+	}
+
+	hooks := []string{}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+
+		hooks = append(hooks, filepath.Join(fsfreezeHookPath, entry.Name()))
+	}
+
+	return hooks, nil
+}
+
+// fsfreezeMountPoints returns the writable block-backed file systems listed in the given mount table,
+// in the order they should be frozen. That's the reverse of the mount order, so that a file system is never
+// frozen ahead of one mounted below it.
+func fsfreezeMountPoints(r io.Reader) ([]string, error) {
+	devices := []string{}
+	mounts := []string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("Invalid mount entry: %q", line)
+		}
+
+		// Only consider writable file systems backed by a block device, once each.
+		if !strings.HasPrefix(fields[0], "/dev/") || slices.Contains(strings.Split(fields[3], ","), "ro") || slices.Contains(devices, fields[0]) {
+			continue
+		}
+
+		mountPoint, err := unescapeMountPath(fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		devices = append(devices, fields[0])
+		mounts = append(mounts, mountPoint)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading mount table: %w", err)
+	}
+
+	slices.Reverse(mounts)
+
+	return mounts, nil
+}
+
+// unescapeMountPath decodes the octal escapes the kernel uses for whitespace in mount paths.
+func unescapeMountPath(path string) (string, error) {
+	if !strings.Contains(path, `\`) {
+		return path, nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			value, err := strconv.ParseUint(path[i+1:i+4], 8, 8)
+			if err != nil {
+				return "", fmt.Errorf("Invalid mount path %q: %w", path, err)
+			}
+
+			sb.WriteByte(byte(value))
+			i += 3
+			continue
+		}
+
+		sb.WriteByte(path[i])
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_unescapeMountPath(t *testing.T) {
+	tests := []struct {
+		path      string
+		expected  string
+		expectErr bool
+	}{
+		{path: "/", expected: "/"},
+		{path: "/var/lib", expected: "/var/lib"},
+		{path: `/mnt/my\040data`, expected: "/mnt/my data"},
+		{path: `/mnt/a\011b\012c\134d`, expected: "/mnt/a\tb\nc\\d"},
+		{path: `/mnt/end\040`, expected: "/mnt/end "},
+		{path: `/mnt/short\04`, expected: `/mnt/short\04`},
+		{path: `/mnt/bad\999`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := unescapeMountPath(tt.path)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, path)
+		})
+	}
+}
+
+func Test_fsfreezeMountPoints(t *testing.T) {
+	mounts := `/dev/sda2 / ext4 rw,relatime 0 0
+proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sda1 /boot/efi vfat rw,relatime 0 0
+/dev/sdb1 /var/lib/postgresql xfs rw,relatime 0 0
+/dev/sdb1 /srv/postgresql xfs rw,relatime 0 0
+/dev/sdc1 /mnt/my\040data ext4 rw,relatime 0 0
+/dev/sr0 /media/cdrom iso9660 ro,relatime 0 0
+/dev/sdd1 /mnt/archive ext4 rw,noatime 0 0
+/dev/sdd1 /mnt/archive ext4 ro,noatime 0 0
+tmpfs /run tmpfs rw,nosuid,nodev 0 0
+`
+
+	// Nested mounts come before their parents, each device is only frozen once and read-only mounts are skipped.
+	result, err := fsfreezeMountPoints(strings.NewReader(mounts))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/mnt/archive", "/mnt/my data", "/var/lib/postgresql", "/boot/efi", "/"}, result)
+
+	result, err = fsfreezeMountPoints(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Empty(t, result)
+
+	_, err = fsfreezeMountPoints(strings.NewReader("/dev/sda2 /\n"))
+	assert.Error(t, err)
+}
+
+func Test_fsfreezeRunHook(t *testing.T) {
+	oldTimeout := fsfreezeHookTimeout
+	fsfreezeHookTimeout = 200 * time.Millisecond
+	t.Cleanup(func() { fsfreezeHookTimeout = oldTimeout })
+
+	dir := t.TempDir()
+
+	writeHook := func(name string, script string) string {
+		path := filepath.Join(dir, name)
+		err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755)
+		require.NoError(t, err)
+
+		return path
+	}
+
+	err := fsfreezeRunHook(writeHook("ok", `[ "$1" = "freeze" ]`), "freeze")
+	assert.NoError(t, err)
+
+	err = fsfreezeRunHook(writeHook("fail", "exit 1"), "freeze")
+	assert.Error(t, err)
+
+	// A stuck hook is killed once the timeout expires.
+	start := time.Now()
+	err = fsfreezeRunHook(writeHook("stuck", "exec sleep 60"), "freeze")
+	assert.ErrorContains(t, err, "Timed out")
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
@@ -645,6 +645,12 @@ func (c *cmdAdminInit) askStoragePool(config *api.InitPreseed, d incus.InstanceS
 				if err != nil {
 					return err
 				}
+			} else if pool.Driver == "netfs" {
+				// Ask for the network share
+				pool.Config["source"], err = c.global.asker.AskString(i18n.G("Network share to use (HOST:/PATH for NFS):")+" ", "", nil)
+				if err != nil {
+					return err
+				}
 			} else if pool.Driver == "lvmcluster" {
 				// Ask for the volume group
 				pool.Config["source"], err = c.global.asker.AskString(i18n.G("Name of the shared LVM volume group:")+" ", "", nil)
@@ -0,0 +1,1433 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdAuth struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAuth) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("auth")
+	cmd.Short = i18n.G("Manage authorization groups and identities")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage authorization groups and identities
+
+Groups grant entitlements on authorization objects to the identities they contain.
+They're used when the server has authorization.rbac enabled.`))
+
+	// Group.
+	authGroupCmd := cmdAuthGroup{global: c.global}
+	cmd.AddCommand(authGroupCmd.Command())
+
+	// Identity.
+	authIdentityCmd := cmdAuthIdentity{global: c.global}
+	cmd.AddCommand(authIdentityCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Group.
+type cmdAuthGroup struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAuthGroup) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("group")
+	cmd.Short = i18n.G("Manage authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Manage authorization groups"))
+
+	// List.
+	authGroupListCmd := cmdAuthGroupList{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupListCmd.Command())
+
+	// Show.
+	authGroupShowCmd := cmdAuthGroupShow{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupShowCmd.Command())
+
+	// Create.
+	authGroupCreateCmd := cmdAuthGroupCreate{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupCreateCmd.Command())
+
+	// Edit.
+	authGroupEditCmd := cmdAuthGroupEdit{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupEditCmd.Command())
+
+	// Rename.
+	authGroupRenameCmd := cmdAuthGroupRename{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupRenameCmd.Command())
+
+	// Delete.
+	authGroupDeleteCmd := cmdAuthGroupDelete{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupDeleteCmd.Command())
+
+	// Permission.
+	authGroupPermissionCmd := cmdAuthGroupPermission{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupPermissionCmd.Command())
+
+	// Identity provider group.
+	authGroupIdentityProviderGroupCmd := cmdAuthGroupIdentityProviderGroup{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupIdentityProviderGroupCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// List.
+type cmdAuthGroupList struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+
+	flagFormat string
+}
+
+func (c *cmdAuthGroupList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("List authorization groups"))
+
+	cmd.RunE = c.Run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdAuthGroupList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name != "" {
+		return fmt.Errorf(i18n.G("Filtering isn't supported yet"))
+	}
+
+	groups, err := resource.server.GetAuthGroups()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, group := range groups {
+		data = append(data, []string{
+			group.Name,
+			group.Description,
+			fmt.Sprintf("%d", len(group.Permissions)),
+			fmt.Sprintf("%d", len(group.Identities)),
+		})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("PERMISSIONS"),
+		i18n.G("IDENTITIES"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, groups)
+}
+
+// Show.
+type cmdAuthGroupShow struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+func (c *cmdAuthGroupShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<group>"))
+	cmd.Short = i18n.G("Show authorization group configurations")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Show authorization group configurations"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdAuthGroupShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// Show the group.
+	group, _, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&group)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Create.
+type cmdAuthGroupCreate struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+
+	flagDescription string
+}
+
+func (c *cmdAuthGroupCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<group>"))
+	cmd.Short = i18n.G("Create authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Create authorization groups"))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus auth group create contractors
+
+incus auth group create contractors < group.yaml
+    Create an authorization group with the permissions from group.yaml`))
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Group description")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdAuthGroupCreate) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// If stdin isn't a terminal, read yaml from it.
+	var groupPut api.AuthGroupPut
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.UnmarshalStrict(contents, &groupPut)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Create the group.
+	group := api.AuthGroupsPost{
+		AuthGroupPost: api.AuthGroupPost{
+			Name: resource.name,
+		},
+		AuthGroupPut: groupPut,
+	}
+
+	if c.flagDescription != "" {
+		group.Description = c.flagDescription
+	}
+
+	err = resource.server.CreateAuthGroup(group)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Authorization group %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdAuthGroupEdit struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+func (c *cmdAuthGroupEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<group>"))
+	cmd.Short = i18n.G("Edit authorization groups as YAML")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Edit authorization groups as YAML"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdAuthGroupEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the authorization group.
+### Any line starting with a '# will be ignored.
+###
+### A group grants a list of entitlements on authorization objects to its identities.
+###
+### An example would look like:
+### name: contractors
+### description: External contractors
+### permissions:
+### - entitlement: viewer
+###   object: project:default
+### - entitlement: can_exec
+###   object: instance:default/c1
+### identity_provider_groups:
+### - incus-contractors
+###
+### Note that only the description, permissions and identity provider groups can be changed.`)
+}
+
+func (c *cmdAuthGroupEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		// Allow output of `incus auth group show` command to be passed in here, but only take the contents
+		// of the AuthGroupPut fields when updating the group. The other fields are silently discarded.
+		newdata := api.AuthGroup{}
+		err = yaml.UnmarshalStrict(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateAuthGroup(resource.name, newdata.Writable(), "")
+	}
+
+	// Get the current config.
+	group, etag, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&group)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor.
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor.
+		newdata := api.AuthGroup{} // We show the full group info, but only send the writable fields.
+		err = yaml.UnmarshalStrict(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateAuthGroup(resource.name, newdata.Writable(), etag)
+		}
+
+		// Respawn the editor.
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Rename.
+type cmdAuthGroupRename struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+func (c *cmdAuthGroupRename) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rename", i18n.G("[<remote>:]<group> <new-name>"))
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = i18n.G("Rename authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Rename authorization groups"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdAuthGroupRename) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// Rename the group.
+	err = resource.server.RenameAuthGroup(resource.name, api.AuthGroupPost{Name: args[1]})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Authorization group %s renamed to %s")+"\n", resource.name, args[1])
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdAuthGroupDelete struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+func (c *cmdAuthGroupDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<group>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Delete authorization groups"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdAuthGroupDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// Delete the group.
+	err = resource.server.DeleteAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Authorization group %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Permission.
+type cmdAuthGroupPermission struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+func (c *cmdAuthGroupPermission) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("permission")
+	cmd.Short = i18n.G("Manage authorization group permissions")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Manage authorization group permissions"))
+
+	// Add.
+	authGroupPermissionAddCmd := cmdAuthGroupPermissionAdd{global: c.global, authGroup: c.authGroup}
+	cmd.AddCommand(authGroupPermissionAddCmd.Command())
+
+	// Remove.
+	authGroupPermissionRemoveCmd := cmdAuthGroupPermissionRemove{global: c.global, authGroup: c.authGroup}
+	cmd.AddCommand(authGroupPermissionRemoveCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Add permission.
+type cmdAuthGroupPermissionAdd struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+func (c *cmdAuthGroupPermissionAdd) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("add", i18n.G("[<remote>:]<group> <entitlement> <object>"))
+	cmd.Short = i18n.G("Add permissions to authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Add permissions to authorization groups"))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus auth group permission add contractors viewer project:default
+    Grant read-only access to the default project to the contractors group`))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdAuthGroupPermissionAdd) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 3, 3)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// Get the group.
+	group, etag, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	writable := group.Writable()
+
+	permission := api.AuthPermission{Entitlement: args[1], Object: args[2]}
+	if slices.Contains(writable.Permissions, permission) {
+		return fmt.Errorf(i18n.G("Permission %q on %q is already granted to the group"), permission.Entitlement, permission.Object)
+	}
+
+	writable.Permissions = append(writable.Permissions, permission)
+
+	return resource.server.UpdateAuthGroup(resource.name, writable, etag)
+}
+
+// Remove permission.
+type cmdAuthGroupPermissionRemove struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+func (c *cmdAuthGroupPermissionRemove) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("remove", i18n.G("[<remote>:]<group> <entitlement> <object>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Remove permissions from authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Remove permissions from authorization groups"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdAuthGroupPermissionRemove) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 3, 3)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// Get the group.
+	group, etag, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	writable := group.Writable()
+
+	permission := api.AuthPermission{Entitlement: args[1], Object: args[2]}
+	if !slices.Contains(writable.Permissions, permission) {
+		return fmt.Errorf(i18n.G("Permission %q on %q isn't granted to the group"), permission.Entitlement, permission.Object)
+	}
+
+	writable.Permissions = slices.DeleteFunc(writable.Permissions, func(entry api.AuthPermission) bool { return entry == permission })
+
+	return resource.server.UpdateAuthGroup(resource.name, writable, etag)
+}
+
+// Identity provider group.
+type cmdAuthGroupIdentityProviderGroup struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+func (c *cmdAuthGroupIdentityProviderGroup) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("identity-provider-group")
+	cmd.Short = i18n.G("Manage identity provider groups mapped to authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage identity provider groups mapped to authorization groups
+
+OIDC users that are members of a mapped identity provider group get the permissions of the authorization group.
+The identity provider groups are read from the claim set in oidc.groups.claim.`))
+
+	// Add.
+	authGroupIdentityProviderGroupAddCmd := cmdAuthGroupIdentityProviderGroupAdd{global: c.global, authGroup: c.authGroup}
+	cmd.AddCommand(authGroupIdentityProviderGroupAddCmd.Command())
+
+	// Remove.
+	authGroupIdentityProviderGroupRemoveCmd := cmdAuthGroupIdentityProviderGroupRemove{global: c.global, authGroup: c.authGroup}
+	cmd.AddCommand(authGroupIdentityProviderGroupRemoveCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Add identity provider group.
+type cmdAuthGroupIdentityProviderGroupAdd struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+func (c *cmdAuthGroupIdentityProviderGroupAdd) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("add", i18n.G("[<remote>:]<group> <identity provider group>"))
+	cmd.Short = i18n.G("Map identity provider groups to authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Map identity provider groups to authorization groups"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdAuthGroupIdentityProviderGroupAdd) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// Get the group.
+	group, etag, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	writable := group.Writable()
+
+	if slices.Contains(writable.IdentityProviderGroups, args[1]) {
+		return fmt.Errorf(i18n.G("Identity provider group %q is already mapped to the group"), args[1])
+	}
+
+	writable.IdentityProviderGroups = append(writable.IdentityProviderGroups, args[1])
+
+	return resource.server.UpdateAuthGroup(resource.name, writable, etag)
+}
+
+// Remove identity provider group.
+type cmdAuthGroupIdentityProviderGroupRemove struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+func (c *cmdAuthGroupIdentityProviderGroupRemove) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("remove", i18n.G("[<remote>:]<group> <identity provider group>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Unmap identity provider groups from authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Unmap identity provider groups from authorization groups"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpAuthGroups(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdAuthGroupIdentityProviderGroupRemove) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// Get the group.
+	group, etag, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	writable := group.Writable()
+
+	if !slices.Contains(writable.IdentityProviderGroups, args[1]) {
+		return fmt.Errorf(i18n.G("Identity provider group %q isn't mapped to the group"), args[1])
+	}
+
+	writable.IdentityProviderGroups = slices.DeleteFunc(writable.IdentityProviderGroups, func(entry string) bool { return entry == args[1] })
+
+	return resource.server.UpdateAuthGroup(resource.name, writable, etag)
+}
+
+// Identity.
+type cmdAuthIdentity struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAuthIdentity) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("identity")
+	cmd.Short = i18n.G("Manage identities")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage identities
+
+Identities are referred to as <auth method>/<identifier>, using either the "tls" authentication
+method with a certificate fingerprint or the "oidc" authentication method with a user name.`))
+
+	// List.
+	authIdentityListCmd := cmdAuthIdentityList{global: c.global, authIdentity: c}
+	cmd.AddCommand(authIdentityListCmd.Command())
+
+	// Show.
+	authIdentityShowCmd := cmdAuthIdentityShow{global: c.global, authIdentity: c}
+	cmd.AddCommand(authIdentityShowCmd.Command())
+
+	// Create.
+	authIdentityCreateCmd := cmdAuthIdentityCreate{global: c.global, authIdentity: c}
+	cmd.AddCommand(authIdentityCreateCmd.Command())
+
+	// Edit.
+	authIdentityEditCmd := cmdAuthIdentityEdit{global: c.global, authIdentity: c}
+	cmd.AddCommand(authIdentityEditCmd.Command())
+
+	// Delete.
+	authIdentityDeleteCmd := cmdAuthIdentityDelete{global: c.global, authIdentity: c}
+	cmd.AddCommand(authIdentityDeleteCmd.Command())
+
+	// Group.
+	authIdentityGroupCmd := cmdAuthIdentityGroup{global: c.global, authIdentity: c}
+	cmd.AddCommand(authIdentityGroupCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// parseIdentity splits an identity name into its authentication method and identifier.
+func (c *cmdAuthIdentity) parseIdentity(name string) (string, string, error) {
+	authMethod, identifier, ok := strings.Cut(name, "/")
+	if !ok || authMethod == "" || identifier == "" {
+		return "", "", fmt.Errorf(i18n.G("Invalid identity %q, must be <auth method>/<identifier>"), name)
+	}
+
+	return authMethod, identifier, nil
+}
+
+// List.
+type cmdAuthIdentityList struct {
+	global       *cmdGlobal
+	authIdentity *cmdAuthIdentity
+
+	flagFormat string
+}
+
+func (c *cmdAuthIdentityList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List identities")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("List identities"))
+
+	cmd.RunE = c.Run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name != "" {
+		return fmt.Errorf(i18n.G("Filtering isn't supported yet"))
+	}
+
+	identities, err := resource.server.GetAuthIdentities()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, identity := range identities {
+		data = append(data, []string{
+			identity.AuthMethod,
+			identity.Identifier,
+			strings.Join(identity.Groups, "\n"),
+		})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("AUTH METHOD"),
+		i18n.G("IDENTIFIER"),
+		i18n.G("GROUPS"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, identities)
+}
+
+// Show.
+type cmdAuthIdentityShow struct {
+	global       *cmdGlobal
+	authIdentity *cmdAuthIdentity
+}
+
+func (c *cmdAuthIdentityShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<auth method>/<identifier>"))
+	cmd.Short = i18n.G("Show identities")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Show identities"))
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	authMethod, identifier, err := c.authIdentity.parseIdentity(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Show the identity.
+	identity, _, err := resource.server.GetAuthIdentity(authMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&identity)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Create.
+type cmdAuthIdentityCreate struct {
+	global       *cmdGlobal
+	authIdentity *cmdAuthIdentity
+}
+
+func (c *cmdAuthIdentityCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<auth method>/<identifier> [<group>...]"))
+	cmd.Short = i18n.G("Create identities")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Create identities"))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus auth identity create oidc/jane@example.com contractors
+    Create an identity for the OIDC user jane@example.com as a member of the contractors group`))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityCreate) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	authMethod, identifier, err := c.authIdentity.parseIdentity(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Create the identity.
+	identity := api.AuthIdentitiesPost{
+		AuthIdentityPut: api.AuthIdentityPut{
+			Groups: args[1:],
+		},
+		AuthMethod: authMethod,
+		Identifier: identifier,
+	}
+
+	err = resource.server.CreateAuthIdentity(identity)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Identity %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdAuthIdentityEdit struct {
+	global       *cmdGlobal
+	authIdentity *cmdAuthIdentity
+}
+
+func (c *cmdAuthIdentityEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<auth method>/<identifier>"))
+	cmd.Short = i18n.G("Edit identities as YAML")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Edit identities as YAML"))
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the identity.
+### Any line starting with a '# will be ignored.
+###
+### An example would look like:
+### auth_method: oidc
+### identifier: jane@example.com
+### groups:
+### - contractors
+###
+### Note that only the groups can be changed.`)
+}
+
+func (c *cmdAuthIdentityEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	authMethod, identifier, err := c.authIdentity.parseIdentity(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.AuthIdentity{}
+		err = yaml.UnmarshalStrict(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateAuthIdentity(authMethod, identifier, newdata.Writable(), "")
+	}
+
+	// Get the current config.
+	identity, etag, err := resource.server.GetAuthIdentity(authMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&identity)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor.
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor.
+		newdata := api.AuthIdentity{} // We show the full identity info, but only send the writable fields.
+		err = yaml.UnmarshalStrict(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateAuthIdentity(authMethod, identifier, newdata.Writable(), etag)
+		}
+
+		// Respawn the editor.
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdAuthIdentityDelete struct {
+	global       *cmdGlobal
+	authIdentity *cmdAuthIdentity
+}
+
+func (c *cmdAuthIdentityDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<auth method>/<identifier>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete identities")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Delete identities"))
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	authMethod, identifier, err := c.authIdentity.parseIdentity(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Delete the identity.
+	err = resource.server.DeleteAuthIdentity(authMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Identity %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Group.
+type cmdAuthIdentityGroup struct {
+	global       *cmdGlobal
+	authIdentity *cmdAuthIdentity
+}
+
+func (c *cmdAuthIdentityGroup) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("group")
+	cmd.Short = i18n.G("Manage identity group membership")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Manage identity group membership"))
+
+	// Add.
+	authIdentityGroupAddCmd := cmdAuthIdentityGroupAdd{global: c.global, authIdentity: c.authIdentity}
+	cmd.AddCommand(authIdentityGroupAddCmd.Command())
+
+	// Remove.
+	authIdentityGroupRemoveCmd := cmdAuthIdentityGroupRemove{global: c.global, authIdentity: c.authIdentity}
+	cmd.AddCommand(authIdentityGroupRemoveCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Add group.
+type cmdAuthIdentityGroupAdd struct {
+	global       *cmdGlobal
+	authIdentity *cmdAuthIdentity
+}
+
+func (c *cmdAuthIdentityGroupAdd) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("add", i18n.G("[<remote>:]<auth method>/<identifier> <group>..."))
+	cmd.Short = i18n.G("Add identities to authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Add identities to authorization groups"))
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityGroupAdd) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	authMethod, identifier, err := c.authIdentity.parseIdentity(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Get the identity.
+	identity, etag, err := resource.server.GetAuthIdentity(authMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	writable := identity.Writable()
+
+	for _, group := range args[1:] {
+		if slices.Contains(writable.Groups, group) {
+			return fmt.Errorf(i18n.G("Identity is already a member of group %q"), group)
+		}
+
+		writable.Groups = append(writable.Groups, group)
+	}
+
+	return resource.server.UpdateAuthIdentity(authMethod, identifier, writable, etag)
+}
+
+// Remove group.
+type cmdAuthIdentityGroupRemove struct {
+	global       *cmdGlobal
+	authIdentity *cmdAuthIdentity
+}
+
+func (c *cmdAuthIdentityGroupRemove) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("remove", i18n.G("[<remote>:]<auth method>/<identifier> <group>..."))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Remove identities from authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Remove identities from authorization groups"))
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAuthIdentityGroupRemove) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	authMethod, identifier, err := c.authIdentity.parseIdentity(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Get the identity.
+	identity, etag, err := resource.server.GetAuthIdentity(authMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	writable := identity.Writable()
+
+	for _, group := range args[1:] {
+		if !slices.Contains(writable.Groups, group) {
+			return fmt.Errorf(i18n.G("Identity isn't a member of group %q"), group)
+		}
+
+		writable.Groups = slices.DeleteFunc(writable.Groups, func(entry string) bool { return entry == group })
+	}
+
+	return resource.server.UpdateAuthIdentity(authMethod, identifier, writable, etag)
+}
@@ -68,6 +68,10 @@ func (c *cmdConfigTrust) Command() *cobra.Command {
 	configTrustShowCmd := cmdConfigTrustShow{global: c.global, config: c.config, configTrust: c}
 	cmd.AddCommand(configTrustShowCmd.Command())
 
+	// Token
+	configTrustTokenCmd := cmdConfigTrustToken{global: c.global, configTrust: c}
+	cmd.AddCommand(configTrustTokenCmd.Command())
+
 	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
 	cmd.Args = cobra.NoArgs
 	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type cmdConfigTrustToken struct {
+	global      *cmdGlobal
+	configTrust *cmdConfigTrust
+}
+
+func (c *cmdConfigTrustToken) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("token")
+	cmd.Short = i18n.G("Manage scoped API tokens")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage scoped API tokens
+
+Scoped API tokens are short-lived bearer tokens restricted to a set of entitlements within a single project.`))
+
+	// Create
+	configTrustTokenCreateCmd := cmdConfigTrustTokenCreate{global: c.global, configTrustToken: c}
+	cmd.AddCommand(configTrustTokenCreateCmd.Command())
+
+	// List
+	configTrustTokenListCmd := cmdConfigTrustTokenList{global: c.global, configTrustToken: c}
+	cmd.AddCommand(configTrustTokenListCmd.Command())
+
+	// Revoke
+	configTrustTokenRevokeCmd := cmdConfigTrustTokenRevoke{global: c.global, configTrustToken: c}
+	cmd.AddCommand(configTrustTokenRevokeCmd.Command())
+
+	// Show
+	configTrustTokenShowCmd := cmdConfigTrustTokenShow{global: c.global, configTrustToken: c}
+	cmd.AddCommand(configTrustTokenShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdConfigTrustTokenCreate struct {
+	global           *cmdGlobal
+	configTrustToken *cmdConfigTrustToken
+
+	flagDescription string
+	flagExpiry      string
+}
+
+func (c *cmdConfigTrustTokenCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<project> <entitlement>..."))
+	cmd.Short = i18n.G("Create scoped API tokens")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create scoped API tokens
+
+The token is only displayed once and must be passed as a bearer token in the Authorization header.
+Entitlements are of the form <object type>:<entitlement> (for example instance:can_exec) and are granted
+on the objects of that type in the project until the token expires or is revoked.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus config trust token create ci instance:can_view instance:can_exec --expiry 2h --description "CI runner"
+    Create a token valid for two hours allowing to list and run commands in the instances of the ci project`))
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Token description")+"``")
+	cmd.Flags().StringVar(&c.flagExpiry, "expiry", "24h", i18n.G("Token validity (duration such as 30m or 12h)")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpProjects(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdConfigTrustTokenCreate) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	expiry, err := time.ParseDuration(c.flagExpiry)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Invalid expiry %q: %w"), c.flagExpiry, err)
+	}
+
+	if expiry <= 0 {
+		return fmt.Errorf(i18n.G("The expiry must be a positive duration"))
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project name"))
+	}
+
+	// Create the token.
+	token := api.AuthTokensPost{
+		Description:  c.flagDescription,
+		Project:      resource.name,
+		Entitlements: args[1:],
+		ExpiresAt:    time.Now().Add(expiry),
+	}
+
+	secret, err := resource.server.CreateAuthToken(token)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("API token %s created:")+"\n", secret.ID)
+	}
+
+	fmt.Println(secret.Token)
+
+	return nil
+}
+
+// List.
+type cmdConfigTrustTokenList struct {
+	global           *cmdGlobal
+	configTrustToken *cmdConfigTrustToken
+
+	flagFormat string
+}
+
+func (c *cmdConfigTrustTokenList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List scoped API tokens")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("List scoped API tokens"))
+
+	cmd.RunE = c.Run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdConfigTrustTokenList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name != "" {
+		return fmt.Errorf(i18n.G("Filtering isn't supported yet"))
+	}
+
+	tokens, err := resource.server.GetAuthTokens()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, token := range tokens {
+		data = append(data, []string{
+			token.ID,
+			token.Description,
+			token.Project,
+			strings.Join(token.Entitlements, "\n"),
+			token.ExpiresAt.Local().Format(dateLayout),
+		})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("ID"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("PROJECT"),
+		i18n.G("ENTITLEMENTS"),
+		i18n.G("EXPIRES AT"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, tokens)
+}
+
+// Revoke.
+type cmdConfigTrustTokenRevoke struct {
+	global           *cmdGlobal
+	configTrustToken *cmdConfigTrustToken
+}
+
+func (c *cmdConfigTrustTokenRevoke) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("revoke", i18n.G("[<remote>:]<id>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Revoke scoped API tokens")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Revoke scoped API tokens"))
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdConfigTrustTokenRevoke) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing token ID"))
+	}
+
+	// Revoke the token.
+	err = resource.server.DeleteAuthToken(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("API token %s revoked")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Show.
+type cmdConfigTrustTokenShow struct {
+	global           *cmdGlobal
+	configTrustToken *cmdConfigTrustToken
+}
+
+func (c *cmdConfigTrustTokenShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<id>"))
+	cmd.Short = i18n.G("Show scoped API tokens")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Show scoped API tokens"))
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdConfigTrustTokenShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing token ID"))
+	}
+
+	// Show the token.
+	token, err := resource.server.GetAuthToken(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&token)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
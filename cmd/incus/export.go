@@ -23,6 +23,10 @@ type cmdExport struct {
 	flagInstanceOnly         bool
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
+	flagIncrementalFrom      string
+	flagKeep                 bool
+	flagBucket               string
+	flagS3URL                string
 }
 
 func (c *cmdExport) Command() *cobra.Command {
@@ -33,7 +37,19 @@ func (c *cmdExport) Command() *cobra.Command {
 		`Export instances as backup tarballs.`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`incus export u1 backup0.tar.gz
-    Download a backup tarball of the u1 instance.`))
+    Download a backup tarball of the u1 instance.
+
+incus export u1 full.tar.gz --optimized-storage --instance-only --keep
+    Download an optimized backup of the u1 instance and keep it on the server as the base for incremental backups.
+
+incus export u1 incr1.tar.gz --incremental-from backup0
+    Download only the changes since backup0, the new backup then replaces backup0 on the server.
+
+incus export u1 backups/u1.tar.gz --bucket default/backups
+    Upload a backup of the u1 instance to the "backups" storage bucket of the "default" pool.
+
+incus export u1 u1.tar.gz --bucket backups --s3-url https://s3.example.com
+    Upload a backup of the u1 instance to an S3 bucket, using the keys from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().BoolVar(&c.flagInstanceOnly, "instance-only", false,
@@ -41,6 +57,11 @@ func (c *cmdExport) Command() *cobra.Command {
 	cmd.Flags().BoolVar(&c.flagOptimizedStorage, "optimized-storage", false,
 		i18n.G("Use storage driver optimized format (can only be restored on a similar pool)"))
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", i18n.G("Compression algorithm to use (none for uncompressed)")+"``")
+	cmd.Flags().StringVar(&c.flagIncrementalFrom, "incremental-from", "", i18n.G("Only export the changes since an existing backup kept on the server")+"``")
+	cmd.Flags().BoolVar(&c.flagKeep, "keep", false,
+		i18n.G("Keep the backup on the server so it can be the base of incremental backups"))
+	cmd.Flags().StringVar(&c.flagBucket, "bucket", "", i18n.G("Upload the backup to a storage bucket (<pool>/<bucket>, or bucket name with --s3-url)")+"``")
+	cmd.Flags().StringVar(&c.flagS3URL, "s3-url", "", i18n.G("URL of the S3 endpoint to upload the backup to")+"``")
 
 	return cmd
 }
@@ -66,13 +87,47 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	instanceOnly := c.flagInstanceOnly
+	optimizedStorage := c.flagOptimizedStorage
+	keep := c.flagKeep
+
+	// Incremental backups are always optimized, only contain the instance and are kept for the next run.
+	if c.flagIncrementalFrom != "" {
+		instanceOnly = true
+		optimizedStorage = true
+		keep = true
+	}
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	if keep {
+		expiresAt = time.Time{}
+	}
 
 	req := api.InstanceBackupsPost{
 		Name:                 "",
-		ExpiresAt:            time.Now().Add(24 * time.Hour),
+		ExpiresAt:            expiresAt,
 		InstanceOnly:         instanceOnly,
-		OptimizedStorage:     c.flagOptimizedStorage,
+		OptimizedStorage:     optimizedStorage,
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		IncrementalFrom:      c.flagIncrementalFrom,
+		IncrementalBase:      keep && optimizedStorage,
+	}
+
+	if c.flagBucket != "" {
+		if c.flagIncrementalFrom != "" || c.flagKeep {
+			return fmt.Errorf(i18n.G("--bucket can't be used with --incremental-from or --keep"))
+		}
+
+		var bucketPath string
+		if len(args) > 1 {
+			bucketPath = args[1]
+		}
+
+		req.Target, err = backupBucket(c.flagBucket, c.flagS3URL, bucketPath)
+		if err != nil {
+			return err
+		}
+	} else if c.flagS3URL != "" {
+		return fmt.Errorf(i18n.G("--s3-url requires --bucket"))
 	}
 
 	op, err := d.CreateInstanceBackup(name, req)
@@ -106,6 +161,12 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Uploaded backups aren't kept on the server.
+	if req.Target != nil {
+		progress.Done(i18n.G("Backup uploaded successfully!"))
+		return nil
+	}
+
 	// Get name of backup
 	uStr := op.Get().Resources["backups"][0]
 	u, err := url.Parse(uStr)
@@ -119,8 +180,13 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	defer func() {
+		// Keep the backup around if requested.
+		if keep {
+			return
+		}
+
 		// Delete backup after we're done
-		op, err = d.DeleteInstanceBackup(name, backupName)
+		op, err := d.DeleteInstanceBackup(name, backupName)
 		if err == nil {
 			_ = op.Wait()
 		}
@@ -188,6 +254,24 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(i18n.G("Failed to close export file: %w"), err)
 	}
 
+	// The new backup replaces the one it's based on.
+	if c.flagIncrementalFrom != "" {
+		op, err := d.DeleteInstanceBackup(name, c.flagIncrementalFrom)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed deleting backup %q: %w"), c.flagIncrementalFrom, err)
+		}
+
+		err = op.Wait()
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed deleting backup %q: %w"), c.flagIncrementalFrom, err)
+		}
+	}
+
+	if keep {
+		progress.Done(fmt.Sprintf(i18n.G("Backup exported successfully and kept on the server as %q"), backupName))
+		return nil
+	}
+
 	progress.Done(i18n.G("Backup exported successfully!"))
 	return nil
 }
@@ -18,6 +18,8 @@ type cmdImport struct {
 	global *cmdGlobal
 
 	flagStorage string
+	flagBucket  string
+	flagS3URL   string
 }
 
 func (c *cmdImport) Command() *cobra.Command {
@@ -25,13 +27,24 @@ func (c *cmdImport) Command() *cobra.Command {
 	cmd.Use = usage("import", i18n.G("[<remote>:] <backup file> [<instance name>]"))
 	cmd.Short = i18n.G("Import instance backups")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
-		`Import backups of instances including their snapshots.`))
+		`Import backups of instances including their snapshots.
+
+Incremental backups are applied on top of the existing (stopped) instance,
+which must have been restored from the backup they're based on.`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`incus import backup0.tar.gz
-    Create a new instance using backup0.tar.gz as the source.`))
+    Create a new instance using backup0.tar.gz as the source.
+
+incus import incr1.tar.gz u1
+    Apply the incremental backup incr1.tar.gz on top of the u1 instance.
+
+incus import backups/u1.tar.gz --bucket default/backups
+    Create a new instance from a backup stored in the "backups" storage bucket of the "default" pool.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().StringVarP(&c.flagStorage, "storage", "s", "", i18n.G("Storage pool name")+"``")
+	cmd.Flags().StringVar(&c.flagBucket, "bucket", "", i18n.G("Import the backup from a storage bucket (<pool>/<bucket>, or bucket name with --s3-url)")+"``")
+	cmd.Flags().StringVar(&c.flagS3URL, "s3-url", "", i18n.G("URL of the S3 endpoint to import the backup from")+"``")
 
 	return cmd
 }
@@ -71,41 +84,61 @@ func (c *cmdImport) Run(cmd *cobra.Command, args []string) error {
 
 	resource := resources[0]
 
-	var file *os.File
-	if srcFile == "-" {
-		file = os.Stdin
-		c.global.flagQuiet = true
-	} else {
-		file, err = os.Open(srcFile)
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Importing instance: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	var createArgs incus.InstanceBackupArgs
+
+	if c.flagBucket != "" {
+		bucket, err := backupBucket(c.flagBucket, c.flagS3URL, srcFile)
 		if err != nil {
 			return err
 		}
 
-		defer func() { _ = file.Close() }()
-	}
+		createArgs = incus.InstanceBackupArgs{
+			Bucket:   bucket,
+			PoolName: c.flagStorage,
+			Name:     instanceName,
+		}
+	} else {
+		if c.flagS3URL != "" {
+			return fmt.Errorf(i18n.G("--s3-url requires --bucket"))
+		}
 
-	fstat, err := file.Stat()
-	if err != nil {
-		return err
-	}
+		var file *os.File
+		if srcFile == "-" {
+			file = os.Stdin
+			c.global.flagQuiet = true
+			progress.Quiet = true
+		} else {
+			file, err = os.Open(srcFile)
+			if err != nil {
+				return err
+			}
+
+			defer func() { _ = file.Close() }()
+		}
 
-	progress := cli.ProgressRenderer{
-		Format: i18n.G("Importing instance: %s"),
-		Quiet:  c.global.flagQuiet,
-	}
+		fstat, err := file.Stat()
+		if err != nil {
+			return err
+		}
 
-	createArgs := incus.InstanceBackupArgs{
-		BackupFile: &ioprogress.ProgressReader{
-			ReadCloser: file,
-			Tracker: &ioprogress.ProgressTracker{
-				Length: fstat.Size(),
-				Handler: func(percent int64, speed int64) {
-					progress.UpdateProgress(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
+		createArgs = incus.InstanceBackupArgs{
+			BackupFile: &ioprogress.ProgressReader{
+				ReadCloser: file,
+				Tracker: &ioprogress.ProgressTracker{
+					Length: fstat.Size(),
+					Handler: func(percent int64, speed int64) {
+						progress.UpdateProgress(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
+					},
 				},
 			},
-		},
-		PoolName: c.flagStorage,
-		Name:     instanceName,
+			PoolName: c.flagStorage,
+			Name:     instanceName,
+		}
 	}
 
 	op, err := resource.server.CreateInstanceFromBackup(createArgs)
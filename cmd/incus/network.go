@@ -104,6 +104,10 @@ func (c *cmdNetwork) Command() *cobra.Command {
 	networkACLCmd := cmdNetworkACL{global: c.global}
 	cmd.AddCommand(networkACLCmd.Command())
 
+	// Address set
+	networkAddressSetCmd := cmdNetworkAddressSet{global: c.global}
+	cmd.AddCommand(networkAddressSetCmd.Command())
+
 	// Forward
 	networkForwardCmd := cmdNetworkForward{global: c.global}
 	cmd.AddCommand(networkForwardCmd.Command())
@@ -1047,6 +1051,33 @@ func (c *cmdNetworkInfo) Run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// WireGuard information.
+	if state.Wireguard != nil {
+		fmt.Println("")
+		fmt.Println(i18n.G("WireGuard:"))
+		fmt.Printf("  %s: %s\n", i18n.G("Public key"), state.Wireguard.PublicKey)
+		fmt.Printf("  %s: %d\n", i18n.G("Listen port"), state.Wireguard.ListenPort)
+
+		if len(state.Wireguard.Peers) > 0 {
+			fmt.Printf("  %s:\n", i18n.G("Peers"))
+			for _, peer := range state.Wireguard.Peers {
+				fmt.Printf("    %s:\n", peer.Name)
+				fmt.Printf("      %s: %s\n", i18n.G("Public key"), peer.PublicKey)
+
+				if peer.Endpoint != "" {
+					fmt.Printf("      %s: %s\n", i18n.G("Endpoint"), peer.Endpoint)
+				}
+
+				if !peer.LatestHandshake.IsZero() {
+					fmt.Printf("      %s: %s\n", i18n.G("Latest handshake"), peer.LatestHandshake.Local().Format(dateLayout))
+				}
+
+				fmt.Printf("      %s: %s\n", i18n.G("Bytes received"), units.GetByteSizeString(int64(peer.BytesReceived), 2))
+				fmt.Printf("      %s: %s\n", i18n.G("Bytes sent"), units.GetByteSizeString(int64(peer.BytesSent), 2))
+			}
+		}
+	}
+
 	return nil
 }
 
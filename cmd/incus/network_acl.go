@@ -653,6 +653,9 @@ func (c *cmdNetworkACLEdit) helpTemplate() string {
 ###   destination_port: ""
 ###   icmp_type: ""
 ###   icmp_code: ""
+###   rate_limit: ""
+###   connection_rate: ""
+###   connection_limit: ""
 ### config:
 ###  user.foo: bah
 ###
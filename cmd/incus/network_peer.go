@@ -320,7 +320,7 @@ type cmdNetworkPeerCreate struct {
 
 func (c *cmdNetworkPeerCreate) Command() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Use = usage("create", i18n.G("[<remote>:]<network> <peer_name> <[target project/]<target network or integration> [key=value...]"))
+	cmd.Use = usage("create", i18n.G("[<remote>:]<network> <peer_name> [[target project/]<target network or integration>] [key=value...]"))
 	cmd.Short = i18n.G("Create new network peering")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Create new network peering"))
 	cmd.Example = cli.FormatSection("", i18n.G(`incus network peer create default peer1 web/default
@@ -331,11 +331,14 @@ incus network peer create default peer2 ovn-ic --type=remote
 
 incus network peer create default peer3 web/default < config.yaml
 	Create a new peering between network default in the current project and network default in the web project using the configuration
-	in the file config.yaml`))
+	in the file config.yaml
+
+incus network peer create wg0 site2 --type=wireguard wireguard.public_key=<key> wireguard.endpoint=192.0.2.10:51820 wireguard.allowed_ips=10.0.2.0/24
+    Create a new WireGuard peer on network "wg0" routing 10.0.2.0/24 to the remote endpoint`))
 
 	cmd.RunE = c.Run
 
-	cmd.Flags().StringVar(&c.flagType, "type", "local", i18n.G("Type of peer (local or remote)")+"``")
+	cmd.Flags().StringVar(&c.flagType, "type", "local", i18n.G("Type of peer (local, remote or wireguard)")+"``")
 	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Peer description")+"``")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -351,15 +354,24 @@ incus network peer create default peer3 web/default < config.yaml
 
 func (c *cmdNetworkPeerCreate) Run(cmd *cobra.Command, args []string) error {
 	// Quick checks.
-	exit, err := c.global.CheckArgs(cmd, args, 3, -1)
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
 	if exit {
 		return err
 	}
 
-	if !slices.Contains([]string{"local", "remote"}, c.flagType) {
+	if !slices.Contains([]string{"local", "remote", "wireguard"}, c.flagType) {
 		return fmt.Errorf(i18n.G("Invalid peer type"))
 	}
 
+	// WireGuard peers are entirely described by their configuration and don't have a target.
+	configStart := 3
+	if c.flagType == "wireguard" {
+		configStart = 2
+	} else if len(args) < 3 {
+		_ = cmd.Usage()
+		return fmt.Errorf(i18n.G("Missing target network or integration"))
+	}
+
 	// Parse remote.
 	resources, err := c.global.ParseServers(args[0])
 	if err != nil {
@@ -376,18 +388,19 @@ func (c *cmdNetworkPeerCreate) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(i18n.G("Missing peer name"))
 	}
 
-	if args[2] == "" {
-		return fmt.Errorf(i18n.G("Missing target network or integration"))
-	}
-
-	targetParts := strings.SplitN(args[2], "/", 2)
-
 	var targetProject, target string
-	if len(targetParts) == 2 {
-		targetProject = targetParts[0]
-		target = targetParts[1]
-	} else {
-		target = targetParts[0]
+	if configStart == 3 {
+		if args[2] == "" {
+			return fmt.Errorf(i18n.G("Missing target network or integration"))
+		}
+
+		targetParts := strings.SplitN(args[2], "/", 2)
+		if len(targetParts) == 2 {
+			targetProject = targetParts[0]
+			target = targetParts[1]
+		} else {
+			target = targetParts[0]
+		}
 	}
 
 	// If stdin isn't a terminal, read yaml from it.
@@ -409,7 +422,7 @@ func (c *cmdNetworkPeerCreate) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get config filters from arguments.
-	for i := 3; i < len(args); i++ {
+	for i := configStart; i < len(args); i++ {
 		entry := strings.SplitN(args[i], "=", 2)
 		if len(entry) < 2 {
 			return fmt.Errorf(i18n.G("Bad key/value pair: %s"), args[i])
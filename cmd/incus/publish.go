@@ -1,7 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,6 +26,8 @@ type cmdPublish struct {
 	flagMakePublic           bool
 	flagForce                bool
 	flagReuse                bool
+	flagFormat               string
+	flagOutput               string
 }
 
 func (c *cmdPublish) Command() *cobra.Command {
@@ -30,7 +35,17 @@ func (c *cmdPublish) Command() *cobra.Command {
 	cmd.Use = usage("publish", i18n.G("[<remote>:]<instance>[/<snapshot>] [<remote>:] [flags] [key=value...]"))
 	cmd.Short = i18n.G("Publish instances as images")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
-		`Publish instances as images`))
+		`Publish instances as images
+
+With --format=oci, container images are converted to OCI images and either
+pushed to an OCI registry remote under the names given with --alias or written
+as an OCI image layout tarball with --output.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus publish c1 registry: --format=oci --alias=myorg/c1:latest
+    Push the c1 container as an OCI image to the "registry" remote.
+
+incus publish c1 --format=oci --output=c1.tar
+    Write the c1 container as an OCI image layout tarball.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().BoolVar(&c.flagMakePublic, "public", false, i18n.G("Make the image public"))
@@ -39,6 +54,8 @@ func (c *cmdPublish) Command() *cobra.Command {
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", i18n.G("Compression algorithm to use (`none` for uncompressed)"))
 	cmd.Flags().StringVar(&c.flagExpiresAt, "expire", "", i18n.G("Image expiration date (format: rfc3339)")+"``")
 	cmd.Flags().BoolVar(&c.flagReuse, "reuse", false, i18n.G("If the image alias already exists, delete and create a new one"))
+	cmd.Flags().StringVar(&c.flagFormat, "format", "", i18n.G("Format of the image (incus or oci)")+"``")
+	cmd.Flags().StringVar(&c.flagOutput, "output", "", i18n.G("Write an OCI image layout tarball to the given file")+"``")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -95,13 +112,36 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(i18n.G("There is no \"image name\".  Did you want an alias?"))
 	}
 
-	d, err := conf.GetInstanceServer(iRemote)
-	if err != nil {
-		return err
+	if c.flagFormat != "" && !slices.Contains([]string{"incus", "oci"}, c.flagFormat) {
+		return fmt.Errorf(i18n.G("Invalid format %q"), c.flagFormat)
+	}
+
+	ociFormat := c.flagFormat == "oci"
+	if c.flagOutput != "" && !ociFormat {
+		return errors.New(i18n.G("--output can only be used with --format=oci"))
+	}
+
+	var d incus.InstanceServer
+	if ociFormat {
+		if c.flagOutput == "" {
+			remote, ok := conf.Remotes[iRemote]
+			if !ok || remote.Protocol != "oci" {
+				return errors.New(i18n.G("The target remote must be an OCI registry, use --output to write the image to a file instead"))
+			}
+
+			if len(c.flagAliases) == 0 {
+				return errors.New(i18n.G("At least one --alias is required to push to an OCI registry"))
+			}
+		}
+	} else {
+		d, err = conf.GetInstanceServer(iRemote)
+		if err != nil {
+			return err
+		}
 	}
 
 	s := d
-	if cRemote != iRemote {
+	if cRemote != iRemote || ociFormat {
 		s, err = conf.GetInstanceServer(cRemote)
 		if err != nil {
 			return err
@@ -227,7 +267,7 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 		req.Source.Type = "container"
 	}
 
-	if cRemote == iRemote {
+	if cRemote == iRemote && !ociFormat {
 		req.Public = c.flagMakePublic
 	}
 
@@ -240,6 +280,10 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 		req.ExpiresAt = expiresAt
 	}
 
+	if ociFormat {
+		return c.publishOCI(s, req, aliases, iRemote)
+	}
+
 	existingAliases, err := GetCommonAliases(d, aliases...)
 	if err != nil {
 		return fmt.Errorf(i18n.G("Error retrieving aliases: %w"), err)
@@ -254,11 +298,62 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(i18n.G("Aliases already exists: %s"), strings.Join(names, ", "))
 	}
 
-	op, err := s.CreateImage(req, nil)
+	fingerprint, err := c.createImage(s, req)
+	if err != nil {
+		return err
+	}
+
+	// For remote publish, copy to target now
+	if cRemote != iRemote {
+		defer func() { _, _ = s.DeleteImage(fingerprint) }()
+
+		// Get the source image
+		image, _, err := s.GetImage(fingerprint)
+		if err != nil {
+			return err
+		}
+
+		// Image copy arguments
+		args := incus.ImageCopyArgs{
+			Public: c.flagMakePublic,
+		}
+
+		// Copy the image to the destination host
+		op, err := d.CopyImage(s, *image, &args)
+		if err != nil {
+			return err
+		}
+
+		err = op.Wait()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Delete images if necessary
+	if c.flagReuse {
+		err = deleteImagesByAliases(d, aliases)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = ensureImageAliases(d, aliases, fingerprint)
 	if err != nil {
 		return err
 	}
 
+	fmt.Printf(i18n.G("Instance published with fingerprint: %s")+"\n", fingerprint)
+	return nil
+}
+
+// createImage publishes the instance as an image and returns its fingerprint.
+func (c *cmdPublish) createImage(s incus.InstanceServer, req api.ImagesPost) (string, error) {
+	op, err := s.CreateImage(req, nil)
+	if err != nil {
+		return "", err
+	}
+
 	// Watch the background operation
 	progress := cli.ProgressRenderer{
 		Format: i18n.G("Publishing instance: %s"),
@@ -268,14 +363,14 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 	_, err = op.AddHandler(progress.UpdateOp)
 	if err != nil {
 		progress.Done("")
-		return err
+		return "", err
 	}
 
 	// Wait for the copy to complete
 	err = cli.CancelableWait(op, &progress)
 	if err != nil {
 		progress.Done("")
-		return err
+		return "", err
 	}
 
 	progress.Done("")
@@ -283,48 +378,92 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 	opAPI := op.Get()
 
 	// Grab the fingerprint
-	fingerprint := opAPI.Metadata["fingerprint"].(string)
+	return opAPI.Metadata["fingerprint"].(string), nil
+}
 
-	// For remote publish, copy to target now
-	if cRemote != iRemote {
-		defer func() { _, _ = s.DeleteImage(fingerprint) }()
+// publishOCI publishes the instance as a temporary image, then converts it to an
+// OCI image which is either pushed to an OCI registry or written to a file.
+func (c *cmdPublish) publishOCI(s incus.InstanceServer, req api.ImagesPost, aliases []api.ImageAlias, iRemote string) error {
+	if !s.HasExtension("images_oci") {
+		return errors.New(i18n.G("The server doesn't support publishing OCI images"))
+	}
 
-		// Get the source image
-		image, _, err := s.GetImage(fingerprint)
+	fingerprint, err := c.createImage(s, req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _, _ = s.DeleteImage(fingerprint) }()
+
+	// Write the image layout to a file.
+	if c.flagOutput != "" {
+		target, err := os.Create(c.flagOutput)
 		if err != nil {
 			return err
 		}
 
-		// Image copy arguments
-		args := incus.ImageCopyArgs{
-			Public: c.flagMakePublic,
+		defer func() { _ = target.Close() }()
+
+		progress := cli.ProgressRenderer{
+			Format: i18n.G("Exporting the image: %s"),
+			Quiet:  c.global.flagQuiet,
 		}
 
-		// Copy the image to the destination host
-		op, err := d.CopyImage(s, *image, &args)
+		_, err = s.GetImageOCIFile(fingerprint, incus.ImageFileRequest{
+			MetaFile:        target,
+			ProgressHandler: progress.UpdateProgress,
+		})
 		if err != nil {
+			progress.Done("")
+			_ = target.Close()
+			_ = os.Remove(c.flagOutput)
 			return err
 		}
 
-		err = op.Wait()
+		err = target.Close()
 		if err != nil {
+			progress.Done("")
 			return err
 		}
+
+		progress.Done(fmt.Sprintf(i18n.G("Image exported to: %s"), c.flagOutput))
+		return nil
 	}
 
-	// Delete images if necessary
-	if c.flagReuse {
-		err = deleteImagesByAliases(d, aliases)
-		if err != nil {
-			return err
-		}
+	// Push the image to the registry.
+	op, err := s.ExportImage(fingerprint, api.ImageExportPost{
+		Target:   c.global.conf.Remotes[iRemote].Addr,
+		Aliases:  aliases,
+		Protocol: "oci",
+	})
+	if err != nil {
+		return err
 	}
 
-	err = ensureImageAliases(d, aliases, fingerprint)
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Pushing the image: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
 	if err != nil {
+		progress.Done("")
 		return err
 	}
 
-	fmt.Printf(i18n.G("Instance published with fingerprint: %s")+"\n", fingerprint)
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	names := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		names = append(names, alias.Name)
+	}
+
+	fmt.Printf(i18n.G("Instance published to %s: %s")+"\n", iRemote, strings.Join(names, ", "))
 	return nil
 }
@@ -2973,6 +2973,8 @@ type cmdStorageVolumeExport struct {
 	flagVolumeOnly           bool
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
+	flagBucket               string
+	flagS3URL                string
 }
 
 func (c *cmdStorageVolumeExport) Command() *cobra.Command {
@@ -2986,6 +2988,8 @@ func (c *cmdStorageVolumeExport) Command() *cobra.Command {
 	cmd.Flags().BoolVar(&c.flagOptimizedStorage, "optimized-storage", false,
 		i18n.G("Use storage driver optimized format (can only be restored on a similar pool)"))
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", i18n.G("Define a compression algorithm: for backup or none")+"``")
+	cmd.Flags().StringVar(&c.flagBucket, "bucket", "", i18n.G("Upload the backup to a storage bucket (<pool>/<bucket>, or bucket name with --s3-url)")+"``")
+	cmd.Flags().StringVar(&c.flagS3URL, "s3-url", "", i18n.G("URL of the S3 endpoint to upload the backup to")+"``")
 	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
 	cmd.RunE = c.Run
 
@@ -3044,6 +3048,20 @@ func (c *cmdStorageVolumeExport) Run(cmd *cobra.Command, args []string) error {
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
 	}
 
+	if c.flagBucket != "" {
+		var bucketPath string
+		if len(args) > 2 {
+			bucketPath = args[2]
+		}
+
+		req.Target, err = backupBucket(c.flagBucket, c.flagS3URL, bucketPath)
+		if err != nil {
+			return err
+		}
+	} else if c.flagS3URL != "" {
+		return fmt.Errorf(i18n.G("--s3-url requires --bucket"))
+	}
+
 	op, err := d.CreateStorageVolumeBackup(name, volName, req)
 	if err != nil {
 		return fmt.Errorf(i18n.G("Failed to create storage volume backup: %w"), err)
@@ -3075,6 +3093,12 @@ func (c *cmdStorageVolumeExport) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Uploaded backups aren't kept on the server.
+	if req.Target != nil {
+		progress.Done(i18n.G("Backup uploaded successfully!"))
+		return nil
+	}
+
 	// Get name of backup
 	uStr := op.Get().Resources["backups"][0]
 	u, err := url.Parse(uStr)
@@ -3138,7 +3162,9 @@ type cmdStorageVolumeImport struct {
 	storage       *cmdStorage
 	storageVolume *cmdStorageVolume
 
-	flagType string
+	flagType   string
+	flagBucket string
+	flagS3URL  string
 }
 
 func (c *cmdStorageVolumeImport) Command() *cobra.Command {
@@ -3151,10 +3177,15 @@ func (c *cmdStorageVolumeImport) Command() *cobra.Command {
     Create a new custom volume using backup0.tar.gz as the source
 
 incus storage volume import default some-installer.iso installer --type=iso
-    Create a new custom volume storing some-installer.iso for use as a CD-ROM image`))
+    Create a new custom volume storing some-installer.iso for use as a CD-ROM image
+
+incus storage volume import default backups/vol1.tar.gz vol1 --bucket default/backups
+    Create a new custom volume from a backup stored in the "backups" storage bucket of the "default" pool`))
 	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
 	cmd.RunE = c.Run
 	cmd.Flags().StringVar(&c.flagType, "type", "", i18n.G("Import type, backup or iso (default \"backup\")")+"``")
+	cmd.Flags().StringVar(&c.flagBucket, "bucket", "", i18n.G("Import the backup from a storage bucket (<pool>/<bucket>, or bucket name with --s3-url)")+"``")
+	cmd.Flags().StringVar(&c.flagS3URL, "s3-url", "", i18n.G("URL of the S3 endpoint to import the backup from")+"``")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -3192,6 +3223,50 @@ func (c *cmdStorageVolumeImport) Run(cmd *cobra.Command, args []string) error {
 		d = d.UseTarget(c.storage.flagTarget)
 	}
 
+	if c.flagBucket != "" {
+		if c.flagType != "" && c.flagType != "backup" {
+			return fmt.Errorf(i18n.G("Only backups can be imported from a storage bucket"))
+		}
+
+		bucket, err := backupBucket(c.flagBucket, c.flagS3URL, args[1])
+		if err != nil {
+			return err
+		}
+
+		volName := ""
+		if len(args) >= 3 {
+			volName = args[2]
+		}
+
+		progress := cli.ProgressRenderer{
+			Format: i18n.G("Importing custom volume: %s"),
+			Quiet:  c.global.flagQuiet,
+		}
+
+		op, err := d.CreateStoragePoolVolumeFromBackup(pool, incus.StorageVolumeBackupArgs{Bucket: bucket, Name: volName})
+		if err != nil {
+			return err
+		}
+
+		_, err = op.AddHandler(progress.UpdateOp)
+		if err != nil {
+			progress.Done("")
+			return err
+		}
+
+		err = cli.CancelableWait(op, &progress)
+		if err != nil {
+			progress.Done("")
+			return err
+		}
+
+		progress.Done("")
+
+		return nil
+	} else if c.flagS3URL != "" {
+		return fmt.Errorf(i18n.G("--s3-url requires --bucket"))
+	}
+
 	file, err := os.Open(args[1])
 	if err != nil {
 		return err
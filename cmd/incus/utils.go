@@ -336,6 +336,36 @@ func usage(name string, args ...string) string {
 	return name + " " + args[0]
 }
 
+// backupBucket returns the storage bucket location of a backup file.
+// Without an S3 URL, the bucket is an Incus storage bucket in the form <pool>/<bucket>.
+// For external S3 endpoints, the keys are read from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.
+func backupBucket(bucket string, s3URL string, path string) (*api.BackupBucket, error) {
+	if path == "" {
+		return nil, fmt.Errorf(i18n.G("A path in the bucket is required"))
+	}
+
+	if s3URL != "" {
+		return &api.BackupBucket{
+			URL:       s3URL,
+			Bucket:    bucket,
+			Path:      path,
+			AccessKey: os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		}, nil
+	}
+
+	poolName, bucketName, ok := strings.Cut(bucket, "/")
+	if !ok || poolName == "" || bucketName == "" {
+		return nil, fmt.Errorf(i18n.G("Invalid storage bucket %q, expected <pool>/<bucket>"), bucket)
+	}
+
+	return &api.BackupBucket{
+		Pool:   poolName,
+		Bucket: bucketName,
+		Path:   path,
+	}, nil
+}
+
 // instancesExist iterates over a list of instances (or snapshots) and checks that they exist.
 func instancesExist(resources []remoteResource) error {
 	for _, resource := range resources {
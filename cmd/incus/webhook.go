@@ -0,0 +1,767 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdWebhook struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdWebhook) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("webhook")
+	cmd.Short = i18n.G("Manage webhooks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage webhooks`))
+
+	// Create
+	webhookCreateCmd := cmdWebhookCreate{global: c.global, webhook: c}
+	cmd.AddCommand(webhookCreateCmd.Command())
+
+	// Delete
+	webhookDeleteCmd := cmdWebhookDelete{global: c.global, webhook: c}
+	cmd.AddCommand(webhookDeleteCmd.Command())
+
+	// Edit
+	webhookEditCmd := cmdWebhookEdit{global: c.global, webhook: c}
+	cmd.AddCommand(webhookEditCmd.Command())
+
+	// Get
+	webhookGetCmd := cmdWebhookGet{global: c.global, webhook: c}
+	cmd.AddCommand(webhookGetCmd.Command())
+
+	// List
+	webhookListCmd := cmdWebhookList{global: c.global, webhook: c}
+	cmd.AddCommand(webhookListCmd.Command())
+
+	// Rename
+	webhookRenameCmd := cmdWebhookRename{global: c.global, webhook: c}
+	cmd.AddCommand(webhookRenameCmd.Command())
+
+	// Set
+	webhookSetCmd := cmdWebhookSet{global: c.global, webhook: c}
+	cmd.AddCommand(webhookSetCmd.Command())
+
+	// Unset
+	webhookUnsetCmd := cmdWebhookUnset{global: c.global, webhook: c, webhookSet: &webhookSetCmd}
+	cmd.AddCommand(webhookUnsetCmd.Command())
+
+	// Show
+	webhookShowCmd := cmdWebhookShow{global: c.global, webhook: c}
+	cmd.AddCommand(webhookShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdWebhookCreate struct {
+	global     *cmdGlobal
+	webhook    *cmdWebhook
+	flagConfig []string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdWebhookCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<webhook> [<key>=<value>...]"))
+	cmd.Short = i18n.G("Create webhooks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create webhooks`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus webhook create ci url=https://ci.example.net/hooks/incus secret=s3cr3t
+    Create webhook ci sending the lifecycle events to the given URL, signed with the given secret
+
+incus webhook create ci < config.yaml
+    Create webhook ci with configuration from config.yaml`))
+
+	cmd.Flags().StringArrayVarP(&c.flagConfig, "config", "c", nil, i18n.G("Config key/value to apply to the new webhook")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdWebhookCreate) Run(cmd *cobra.Command, args []string) error {
+	var stdinData api.WebhookPut
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, -1)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.Unmarshal(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	// Create the webhook
+	webhook := api.WebhooksPost{}
+	webhook.Name = resource.name
+	webhook.Description = stdinData.Description
+
+	if stdinData.Config == nil {
+		webhook.Config = map[string]string{}
+		for _, entry := range append(c.flagConfig, args[1:]...) {
+			key, value, found := strings.Cut(entry, "=")
+			if !found {
+				return fmt.Errorf(i18n.G("Bad key=value pair: %q"), entry)
+			}
+
+			webhook.Config[key] = value
+		}
+	} else {
+		webhook.Config = stdinData.Config
+	}
+
+	err = resource.server.CreateWebhook(webhook)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Webhook %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdWebhookDelete struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdWebhookDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<webhook>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete webhooks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete webhooks`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdWebhookDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Get the webhook.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	// Delete the webhook
+	err = resource.server.DeleteWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Webhook %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdWebhookEdit struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdWebhookEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<webhook>"))
+	cmd.Short = i18n.G("Edit webhook configurations as YAML")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit webhook configurations as YAML`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus webhook edit <webhook> < webhook.yaml
+    Update a webhook using the content of webhook.yaml`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdWebhookEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the webhook.
+### Any line starting with a '# will be ignored.
+###
+### Note that the name is shown but cannot be changed`)
+}
+
+// Run actually performs the action.
+func (c *cmdWebhookEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.WebhookPut{}
+		err = yaml.Unmarshal(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateWebhook(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	webhook, etag, err := resource.server.GetWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&webhook)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.WebhookPut{}
+		err = yaml.Unmarshal(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateWebhook(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Get.
+type cmdWebhookGet struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+
+	flagIsProperty bool
+}
+
+type webhookColumn struct {
+	Name string
+	Data func(api.Webhook) string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdWebhookGet) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("get", i18n.G("[<remote>:]<webhook> <key>"))
+	cmd.Short = i18n.G("Get values for webhook configuration keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Get values for webhook configuration keys`))
+
+	cmd.RunE = c.Run
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Get the key as a webhook property"))
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdWebhookGet) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	// Get the configuration key
+	webhook, _, err := resource.server.GetWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if c.flagIsProperty {
+		w := webhook.Writable()
+		res, err := getFieldByJsonTag(&w, args[1])
+		if err != nil {
+			return fmt.Errorf(i18n.G("The property %q does not exist on the webhook %q: %v"), args[1], resource.name, err)
+		}
+
+		fmt.Printf("%v\n", res)
+	} else {
+		fmt.Printf("%s\n", webhook.Config[args[1]])
+	}
+
+	return nil
+}
+
+// List.
+type cmdWebhookList struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+
+	flagFormat  string
+	flagColumns string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdWebhookList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List webhooks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List webhooks
+
+Default column layout: ndU
+
+== Columns ==
+The -c option takes a comma separated list of arguments that control
+which webhook attributes to output when displaying in table or csv
+format.
+
+Column arguments are either pre-defined shorthand chars (see below),
+or (extended) config keys.
+
+Commas between consecutive shorthand chars are optional.
+
+Pre-defined column shorthand chars:
+	n - Name
+	d - Description
+	U - URL`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", defaultWebhookColumns, i18n.G("Columns")+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+const defaultWebhookColumns = "ndU"
+
+func (c *cmdWebhookList) parseColumns() ([]webhookColumn, error) {
+	columnsShorthandMap := map[rune]webhookColumn{
+		'n': {i18n.G("NAME"), c.nameColumnData},
+		'd': {i18n.G("DESCRIPTION"), c.descriptionColumnData},
+		'U': {i18n.G("URL"), c.urlColumnData},
+	}
+
+	columnList := strings.Split(c.flagColumns, ",")
+	columns := []webhookColumn{}
+
+	for _, columnEntry := range columnList {
+		if columnEntry == "" {
+			return nil, fmt.Errorf(i18n.G("Empty column entry (redundant, leading or trailing command) in '%s'"), c.flagColumns)
+		}
+
+		for _, columnRune := range columnEntry {
+			column, ok := columnsShorthandMap[columnRune]
+			if !ok {
+				return nil, fmt.Errorf(i18n.G("Unknown column shorthand char '%c' in '%s'"), columnRune, columnEntry)
+			}
+
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, nil
+}
+
+func (c *cmdWebhookList) nameColumnData(webhook api.Webhook) string {
+	return webhook.Name
+}
+
+func (c *cmdWebhookList) descriptionColumnData(webhook api.Webhook) string {
+	return webhook.Description
+}
+
+func (c *cmdWebhookList) urlColumnData(webhook api.Webhook) string {
+	return webhook.Config["url"]
+}
+
+// Run actually performs the action.
+func (c *cmdWebhookList) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := conf.DefaultRemote
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List webhooks
+	webhooks, err := resource.server.GetWebhooks()
+	if err != nil {
+		return err
+	}
+
+	// Parse column flags.
+	columns, err := c.parseColumns()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, webhook := range webhooks {
+		line := []string{}
+		for _, column := range columns {
+			line = append(line, column.Data(webhook))
+		}
+
+		data = append(data, line)
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{}
+	for _, column := range columns {
+		header = append(header, column.Name)
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, webhooks)
+}
+
+// Rename.
+type cmdWebhookRename struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdWebhookRename) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rename", i18n.G("[<remote>:]<webhook> <new-name>"))
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = i18n.G("Rename webhooks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Rename webhooks`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdWebhookRename) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	// Rename the webhook
+	err = resource.server.RenameWebhook(resource.name, api.WebhookPost{Name: args[1]})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Webhook %s renamed to %s")+"\n", resource.name, args[1])
+	}
+
+	return nil
+}
+
+// Set.
+type cmdWebhookSet struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+
+	flagIsProperty bool
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdWebhookSet) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("set", i18n.G("[<remote>:]<webhook> <key>=<value>..."))
+	cmd.Short = i18n.G("Set webhook configuration keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Set webhook configuration keys
+
+For backward compatibility, a single configuration key may still be set with:
+    incus webhook set [<remote>:]<webhook> <key> <value>`))
+
+	cmd.RunE = c.Run
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Set the key as a webhook property"))
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdWebhookSet) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	// Get the webhook
+	webhook, etag, err := resource.server.GetWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Set the configuration key
+	keys, err := getConfig(args[1:]...)
+	if err != nil {
+		return err
+	}
+
+	writable := webhook.Writable()
+	if c.flagIsProperty {
+		if cmd.Name() == "unset" {
+			for k := range keys {
+				err := unsetFieldByJsonTag(&writable, k)
+				if err != nil {
+					return fmt.Errorf(i18n.G("Error unsetting property: %v"), err)
+				}
+			}
+		} else {
+			err := unpackKVToWritable(&writable, keys)
+			if err != nil {
+				return fmt.Errorf(i18n.G("Error setting properties: %v"), err)
+			}
+		}
+	} else {
+		for k, v := range keys {
+			writable.Config[k] = v
+		}
+	}
+
+	return resource.server.UpdateWebhook(resource.name, writable, etag)
+}
+
+// Unset.
+type cmdWebhookUnset struct {
+	global     *cmdGlobal
+	webhook    *cmdWebhook
+	webhookSet *cmdWebhookSet
+
+	flagIsProperty bool
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdWebhookUnset) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("unset", i18n.G("[<remote>:]<webhook> <key>"))
+	cmd.Short = i18n.G("Unset webhook configuration keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Unset webhook configuration keys`))
+
+	cmd.RunE = c.Run
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Unset the key as a webhook property"))
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdWebhookUnset) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	c.webhookSet.flagIsProperty = c.flagIsProperty
+
+	args = append(args, "")
+	return c.webhookSet.Run(cmd, args)
+}
+
+// Show.
+type cmdWebhookShow struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdWebhookShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<webhook>"))
+	cmd.Short = i18n.G("Show webhook options")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show webhook options`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdWebhookShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	// Show the webhook
+	webhook, _, err := resource.server.GetWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&webhook)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -41,6 +41,12 @@ var api10Cmd = APIEndpoint{
 var api10 = []APIEndpoint{
 	api10Cmd,
 	api10ResourcesCmd,
+	authGroupCmd,
+	authGroupsCmd,
+	authIdentitiesCmd,
+	authIdentityCmd,
+	authTokenCmd,
+	authTokensCmd,
 	certificateCmd,
 	certificatesCmd,
 	clusterCmd,
@@ -87,6 +93,8 @@ var api10 = []APIEndpoint{
 	networkACLCmd,
 	networkACLsCmd,
 	networkACLLogCmd,
+	networkAddressSetCmd,
+	networkAddressSetsCmd,
 	networkAllocationsCmd,
 	networkForwardCmd,
 	networkForwardsCmd,
@@ -132,6 +140,8 @@ var api10 = []APIEndpoint{
 	storagePoolVolumeTypeStateCmd,
 	warningsCmd,
 	warningCmd,
+	webhooksCmd,
+	webhookCmd,
 	metricsCmd,
 }
 
@@ -227,7 +237,7 @@ func api10Get(d *Daemon, r *http.Request) response.Response {
 	// Get the authentication methods.
 	authMethods := []string{api.AuthenticationMethodTLS}
 
-	oidcIssuer, oidcClientID, _, _, _ := s.GlobalConfig.OIDCServer()
+	oidcIssuer, oidcClientID, _, _, _, _ := s.GlobalConfig.OIDCServer()
 	if oidcIssuer != "" && oidcClientID != "" {
 		authMethods = append(authMethods, api.AuthenticationMethodOIDC)
 	}
@@ -743,7 +753,7 @@ func doApi10Update(d *Daemon, r *http.Request, req api.ServerPut, patch bool) re
 	})
 
 	// Notify the other nodes about changes
-	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+	notifier, err := cluster.NewNotifierWithContext(r.Context(), s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -788,11 +798,13 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	s := d.State()
 
 	acmeChanged := false
+	auditChanged := false
 	bgpChanged := false
 	dnsChanged := false
 	lokiChanged := false
 	oidcChanged := false
 	openFGAChanged := false
+	otlpChanged := false
 	ovnChanged := false
 	ovsChanged := false
 	syslogChanged := false
@@ -802,6 +814,9 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		case "acme.agree_tos", "acme.ca_url", "acme.challenge", "acme.domain", "acme.email", "acme.provider", "acme.provider.environment", "acme.provider.resolvers":
 			acmeChanged = true
 
+		case "audit.enabled", "audit.file.max_files", "audit.file.max_size", "audit.syslog":
+			auditChanged = true
+
 		case "cluster.images_minimal_replica":
 			err := autoSyncImages(s.ShutdownCtx, s)
 			if err != nil {
@@ -829,10 +844,13 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		case "loki.api.url", "loki.auth.username", "loki.auth.password", "loki.api.ca_cert", "loki.instance", "loki.labels", "loki.loglevel", "loki.types":
 			lokiChanged = true
 
+		case "otlp.api.url", "otlp.api.ca_cert", "otlp.api.headers", "otlp.instance", "otlp.loglevel", "otlp.metrics.interval", "otlp.traces.enabled", "otlp.types":
+			otlpChanged = true
+
 		case "network.ovn.northbound_connection", "network.ovn.ca_cert", "network.ovn.client_cert", "network.ovn.client_key":
 			ovnChanged = true
 
-		case "oidc.issuer", "oidc.client.id", "oidc.audience", "oidc.claim":
+		case "oidc.issuer", "oidc.client.id", "oidc.audience", "oidc.claim", "oidc.groups.claim":
 			oidcChanged = true
 
 		case "openfga.api.url", "openfga.api.token", "openfga.store.id":
@@ -928,6 +946,15 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	if auditChanged {
+		enabled, maxSize, maxFiles, useSyslog := clusterConfig.Audit()
+
+		err := d.setupAudit(enabled, maxSize, maxFiles, useSyslog)
+		if err != nil {
+			return err
+		}
+	}
+
 	if bgpChanged {
 		address := nodeConfig.BGPAddress()
 		asn := clusterConfig.BGPASN()
@@ -953,6 +980,7 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 
 		if lokiURL == "" || lokiLoglevel == "" || len(lokiTypes) == 0 {
 			d.internalListener.RemoveHandler("loki")
+			d.audit.RemoveHandler("loki")
 		} else {
 			err := d.setupLoki(lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes)
 			if err != nil {
@@ -961,14 +989,23 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	if otlpChanged {
+		otlpURL, otlpCACert, otlpHeaders, otlpInstance, otlpLoglevel, otlpTypes, otlpMetricsInterval, otlpTraces := clusterConfig.OTLPServer()
+
+		err := d.setupOTLP(otlpURL, otlpCACert, otlpHeaders, otlpInstance, otlpLoglevel, otlpTypes, otlpMetricsInterval, otlpTraces)
+		if err != nil {
+			return err
+		}
+	}
+
 	if oidcChanged {
-		oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim := clusterConfig.OIDCServer()
+		oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim := clusterConfig.OIDCServer()
 
 		if oidcIssuer == "" || oidcClientID == "" {
 			d.oidcVerifier = nil
 		} else {
 			var err error
-			d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim)
+			d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim)
 			if err != nil {
 				return fmt.Errorf("Failed creating verifier: %w", err)
 			}
@@ -1022,5 +1059,14 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	// Setup the built-in role-based access control.
+	_, ok = clusterChanged["authorization.rbac"]
+	if ok {
+		err := d.setupAuthorizationRBAC(clusterConfig.AuthorizationRBAC())
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
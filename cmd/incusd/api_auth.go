@@ -0,0 +1,1334 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+var authGroupsCmd = APIEndpoint{
+	Path: "auth/groups",
+
+	Get:  APIEndpointAction{Handler: authGroupsGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authGroupsPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authGroupCmd = APIEndpoint{
+	Path: "auth/groups/{name}",
+
+	Get:    APIEndpointAction{Handler: authGroupGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post:   APIEndpointAction{Handler: authGroupPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Put:    APIEndpointAction{Handler: authGroupPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Patch:  APIEndpointAction{Handler: authGroupPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Delete: APIEndpointAction{Handler: authGroupDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authIdentitiesCmd = APIEndpoint{
+	Path: "auth/identities",
+
+	Get:  APIEndpointAction{Handler: authIdentitiesGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authIdentitiesPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authIdentityCmd = APIEndpoint{
+	Path: "auth/identities/{authMethod}/{identifier}",
+
+	Get:    APIEndpointAction{Handler: authIdentityGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Put:    APIEndpointAction{Handler: authIdentityPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Patch:  APIEndpointAction{Handler: authIdentityPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Delete: APIEndpointAction{Handler: authIdentityDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authTokensCmd = APIEndpoint{
+	Path: "auth/tokens",
+
+	Get:  APIEndpointAction{Handler: authTokensGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authTokensPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authTokenCmd = APIEndpoint{
+	Path: "auth/tokens/{id}",
+
+	Get:    APIEndpointAction{Handler: authTokenGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Delete: APIEndpointAction{Handler: authTokenDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// authGroupValidateName checks the name of an authorization group.
+func authGroupValidateName(name string) error {
+	if name == "" {
+		return errors.New("Group name is required")
+	}
+
+	err := validate.IsURLSegmentSafe(name)
+	if err != nil {
+		return fmt.Errorf("Invalid group name %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// authGroupValidatePermissions checks the permissions of an authorization group.
+func authGroupValidatePermissions(permissions []api.AuthPermission) error {
+	for _, permission := range permissions {
+		err := auth.ValidatePermission(permission)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// authGroupValidateIdentityProviderGroups checks the identity provider groups mapped to an authorization group.
+func authGroupValidateIdentityProviderGroups(identityProviderGroups []string) error {
+	for _, name := range identityProviderGroups {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("Identity provider group names cannot be empty")
+		}
+	}
+
+	return nil
+}
+
+// authIdentityURL returns the URL of an identity.
+func authIdentityURL(authMethod string, identifier string) string {
+	return api.NewURL().Path(version.APIVersion, "auth", "identities", authMethod, identifier).String()
+}
+
+// authGroupLoad returns the authorization group with its member identities.
+func authGroupLoad(ctx context.Context, tx *db.ClusterTx, name string) (*api.AuthGroup, error) {
+	id, group, err := tx.GetAuthGroup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := tx.GetAuthGroupIdentities(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Identities = make([]string, 0, len(identities))
+	for _, identity := range identities {
+		group.Identities = append(group.Identities, authIdentityURL(identity.AuthMethod, identity.Identifier))
+	}
+
+	return group, nil
+}
+
+// swagger:operation GET /1.0/auth/groups auth auth_groups_get
+//
+//	Get the authorization groups
+//
+//	Returns a list of authorization groups (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/groups/contractors",
+//	              "/1.0/auth/groups/operators"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/groups?recursion=1 auth auth_groups_get_recursion1
+//
+//	Get the authorization groups
+//
+//	Returns a list of authorization groups (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of authorization groups
+//	          items:
+//	            $ref: "#/definitions/AuthGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var groupNames []string
+	groups := []api.AuthGroup{}
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		groupNames, err = tx.GetAuthGroups(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !recursion {
+			return nil
+		}
+
+		for _, groupName := range groupNames {
+			group, err := authGroupLoad(ctx, tx, groupName)
+			if err != nil {
+				return err
+			}
+
+			groups = append(groups, *group)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion {
+		return response.SyncResponse(true, groups)
+	}
+
+	urls := make([]string, 0, len(groupNames))
+	for _, groupName := range groupNames {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "groups", groupName).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/groups auth auth_groups_post
+//
+//	Add an authorization group
+//
+//	Creates a new authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Group
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.AuthGroupsPost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = authGroupValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = authGroupValidatePermissions(req.Permissions)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = authGroupValidateIdentityProviderGroups(req.IdentityProviderGroups)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, _, err := tx.GetAuthGroup(ctx, req.Name)
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "Authorization group %q already exists", req.Name)
+		} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		_, err = tx.CreateAuthGroup(ctx, &req)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.AuthGroupCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/groups/{name} auth auth_group_get
+//
+//	Get the authorization group
+//
+//	Gets a specific authorization group.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Authorization group
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var group *api.AuthGroup
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		group, err = authGroupLoad(ctx, tx, name)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, group, group.Writable())
+}
+
+// swagger:operation POST /1.0/auth/groups/{name} auth auth_group_post
+//
+//	Rename the authorization group
+//
+//	Renames an existing authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Group rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.AuthGroupPost{}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = authGroupValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		id, _, err := tx.GetAuthGroup(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		_, _, err = tx.GetAuthGroup(ctx, req.Name)
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "Authorization group %q already exists", req.Name)
+		} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		return tx.RenameAuthGroup(ctx, id, req.Name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.AuthGroupRenamed.Event(req.Name, request.CreateRequestor(r), map[string]any{"old_name": name})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation PUT /1.0/auth/groups/{name} auth auth_group_put
+//
+//	Update the authorization group
+//
+//	Updates the entire authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Group configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation PATCH /1.0/auth/groups/{name} auth auth_group_patch
+//
+//	Partially update the authorization group
+//
+//	Updates a subset of the authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Group configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		id, group, err := tx.GetAuthGroup(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		// Validate the ETag.
+		err = localUtil.EtagCheck(r, group.Writable())
+		if err != nil {
+			return api.StatusErrorf(http.StatusPreconditionFailed, "%v", err)
+		}
+
+		req := api.AuthGroupPut{}
+
+		// Parse the request.
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "%v", err)
+		}
+
+		// If fields aren't provided in a "patch" request, then keep the existing ones.
+		if r.Method == http.MethodPatch {
+			if req.Description == "" {
+				req.Description = group.Description
+			}
+
+			if req.Permissions == nil {
+				req.Permissions = group.Permissions
+			}
+
+			if req.IdentityProviderGroups == nil {
+				req.IdentityProviderGroups = group.IdentityProviderGroups
+			}
+		}
+
+		err = authGroupValidatePermissions(req.Permissions)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "%v", err)
+		}
+
+		err = authGroupValidateIdentityProviderGroups(req.IdentityProviderGroups)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "%v", err)
+		}
+
+		return tx.UpdateAuthGroup(ctx, id, &req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthGroupUpdated.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/auth/groups/{name} auth auth_group_delete
+//
+//	Delete the authorization group
+//
+//	Removes the authorization group.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		id, _, err := tx.GetAuthGroup(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		return tx.DeleteAuthGroup(ctx, id)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthGroupDeleted.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/auth/identities auth auth_identities_get
+//
+//	Get the identities
+//
+//	Returns a list of identities (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/identities/oidc/jane@example.com",
+//	              "/1.0/auth/identities/tls/4b6fdc2c3d4b1a0f5e2c6e1bb1c2e8d7a9f0c3b2d1e4f5a6b7c8d9e0f1a2b3c4"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/identities?recursion=1 auth auth_identities_get_recursion1
+//
+//	Get the identities
+//
+//	Returns a list of identities (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of identities
+//	          items:
+//	            $ref: "#/definitions/AuthIdentity"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentitiesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var identities []api.AuthIdentity
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		identities, err = tx.GetAuthIdentities(ctx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, identities)
+	}
+
+	urls := make([]string, 0, len(identities))
+	for _, identity := range identities {
+		urls = append(urls, authIdentityURL(identity.AuthMethod, identity.Identifier))
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/identities auth auth_identities_post
+//
+//	Add an identity
+//
+//	Creates a new identity.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: identity
+//	    description: Identity
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthIdentitiesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentitiesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.AuthIdentitiesPost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	if !slices.Contains([]string{api.AuthenticationMethodTLS, api.AuthenticationMethodOIDC}, req.AuthMethod) {
+		return response.BadRequest(fmt.Errorf("Invalid authentication method %q", req.AuthMethod))
+	}
+
+	if req.Identifier == "" {
+		return response.BadRequest(errors.New("Identifier is required"))
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, _, err := tx.GetAuthIdentity(ctx, req.AuthMethod, req.Identifier)
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "Identity %q already exists", req.Identifier)
+		} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		_, err = tx.CreateAuthIdentity(ctx, &req)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.IdentityCreated.Event(req.AuthMethod, req.Identifier, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/identities/{authMethod}/{identifier} auth auth_identity_get
+//
+//	Get the identity
+//
+//	Gets a specific identity.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Identity
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthIdentity"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentityGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	authMethod, identifier, err := authIdentityFromRequest(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var identity *api.AuthIdentity
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, identity, err = tx.GetAuthIdentity(ctx, authMethod, identifier)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, identity, identity.Writable())
+}
+
+// swagger:operation PUT /1.0/auth/identities/{authMethod}/{identifier} auth auth_identity_put
+//
+//	Update the identity
+//
+//	Updates the groups of the identity.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: identity
+//	    description: Identity configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthIdentityPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation PATCH /1.0/auth/identities/{authMethod}/{identifier} auth auth_identity_patch
+//
+//	Partially update the identity
+//
+//	Updates a subset of the identity.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: identity
+//	    description: Identity configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthIdentityPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentityPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	authMethod, identifier, err := authIdentityFromRequest(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		id, identity, err := tx.GetAuthIdentity(ctx, authMethod, identifier)
+		if err != nil {
+			return err
+		}
+
+		// Validate the ETag.
+		err = localUtil.EtagCheck(r, identity.Writable())
+		if err != nil {
+			return api.StatusErrorf(http.StatusPreconditionFailed, "%v", err)
+		}
+
+		req := api.AuthIdentityPut{}
+
+		// Parse the request.
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "%v", err)
+		}
+
+		// If groups aren't provided in a "patch" request, then keep the existing ones.
+		if r.Method == http.MethodPatch && req.Groups == nil {
+			req.Groups = identity.Groups
+		}
+
+		return tx.UpdateAuthIdentity(ctx, id, &req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.IdentityUpdated.Event(authMethod, identifier, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/auth/identities/{authMethod}/{identifier} auth auth_identity_delete
+//
+//	Delete the identity
+//
+//	Removes the identity.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentityDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	authMethod, identifier, err := authIdentityFromRequest(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		id, _, err := tx.GetAuthIdentity(ctx, authMethod, identifier)
+		if err != nil {
+			return err
+		}
+
+		return tx.DeleteAuthIdentity(ctx, id)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.IdentityDeleted.Event(authMethod, identifier, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// authIdentityFromRequest returns the authentication method and identifier from the request path.
+func authIdentityFromRequest(r *http.Request) (string, string, error) {
+	authMethod, err := url.PathUnescape(mux.Vars(r)["authMethod"])
+	if err != nil {
+		return "", "", err
+	}
+
+	identifier, err := url.PathUnescape(mux.Vars(r)["identifier"])
+	if err != nil {
+		return "", "", err
+	}
+
+	return authMethod, identifier, nil
+}
+
+// swagger:operation GET /1.0/auth/tokens auth auth_tokens_get
+//
+//	Get the API tokens
+//
+//	Returns a list of scoped API tokens (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/tokens/3b6e4a4f-1c1f-4b0e-9a54-0b3ec9d2a1a7"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/tokens?recursion=1 auth auth_tokens_get_recursion1
+//
+//	Get the API tokens
+//
+//	Returns a list of scoped API tokens (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of API tokens
+//	          items:
+//	            $ref: "#/definitions/AuthToken"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokensGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var tokens []api.AuthToken
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		tokens, err = tx.GetAuthTokens(ctx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, tokens)
+	}
+
+	urls := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "tokens", token.ID).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/tokens auth auth_tokens_post
+//
+//	Add an API token
+//
+//	Creates a new scoped API token and returns its secret.
+//	The secret can't be retrieved later on.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: token
+//	    description: API token
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthTokensPost"
+//	responses:
+//	  "200":
+//	    description: API token secret
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthTokenSecret"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokensPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.AuthTokensPost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	if req.Project == "" {
+		return response.BadRequest(errors.New("Project is required"))
+	}
+
+	if len(req.Entitlements) == 0 {
+		return response.BadRequest(errors.New("At least one entitlement is required"))
+	}
+
+	for _, entitlement := range req.Entitlements {
+		err = auth.ValidateTokenEntitlement(entitlement)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	if req.ExpiresAt.IsZero() {
+		return response.BadRequest(errors.New("Expiry date is required"))
+	}
+
+	if req.ExpiresAt.Before(time.Now()) {
+		return response.BadRequest(errors.New("Expiry date must be in the future"))
+	}
+
+	id, secretHash, token, err := auth.NewAPIToken()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.CreateAuthToken(ctx, id, secretHash, &req)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.AuthTokenCreated.Event(id, request.CreateRequestor(r), map[string]any{"project": req.Project})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, api.AuthTokenSecret{ID: id, Token: token}, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/tokens/{id} auth auth_token_get
+//
+//	Get the API token
+//
+//	Gets a specific scoped API token.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API token
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthToken"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokenGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	id, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var token *api.AuthToken
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, token, err = tx.GetAuthToken(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, token)
+}
+
+// swagger:operation DELETE /1.0/auth/tokens/{id} auth auth_token_delete
+//
+//	Revoke the API token
+//
+//	Removes the scoped API token, immediately rejecting any further request made with it.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokenDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	id, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		tokenID, _, err := tx.GetAuthToken(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return tx.DeleteAuthToken(ctx, tokenID)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthTokenDeleted.Event(id, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
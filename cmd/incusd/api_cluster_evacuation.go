@@ -230,8 +230,8 @@ func evacuateInstancesFunc(ctx context.Context, inst instance.Instance, opts eva
 	// Find a new location for the instance.
 	sourceMemberInfo, targetMemberInfo, err := evacuateClusterSelectTarget(ctx, opts.s, inst)
 	if err != nil {
-		if api.StatusErrorCheck(err, http.StatusNotFound) {
-			// Skip migration if no target is available.
+		if api.StatusErrorCheck(err, http.StatusNotFound, http.StatusConflict) {
+			// Skip migration if no target is available or allowed by the placement rules.
 			l.Warn("No migration target available for instance")
 			return nil
 		}
@@ -561,6 +561,12 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 			return err
 		}
 
+		// Apply the instance placement rules.
+		candidateMembers, err = instance.PlacementCandidates(ctx, tx, inst.Project().Name, inst.Name(), inst.ExpandedConfig(), candidateMembers)
+		if err != nil {
+			return fmt.Errorf("Failed placing instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/http"
 	"sort"
 	"strconv"
 	"time"
@@ -238,6 +239,21 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 			continue
 		}
 
+		// Check that the placement rules allow for the instance to be moved to the target.
+		// This is done right before the migration to account for the instances moved so far.
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			_, err := instance.PlacementCandidates(ctx, tx, inst.Project().Name, inst.Name(), inst.ExpandedConfig(), []db.NodeInfo{dstServer.NodeInfo})
+			return err
+		})
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusConflict) {
+				// Skip the instance as it can't be placed on the target.
+				continue
+			}
+
+			return -1, fmt.Errorf("Failed to check instance placement rules: %w", err)
+		}
+
 		// Prepare for live migration.
 		req := api.InstancePost{
 			Migration: true,
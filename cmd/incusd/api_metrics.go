@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"net/http"
 	"runtime"
@@ -13,15 +14,19 @@ import (
 	"time"
 
 	"github.com/lxc/incus/v6/internal/server/auth"
+	clusterRequest "github.com/lxc/incus/v6/internal/server/cluster/request"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
 	"github.com/lxc/incus/v6/internal/server/locking"
 	"github.com/lxc/incus/v6/internal/server/metrics"
+	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 )
@@ -95,12 +100,24 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	// Wait until daemon is fully started.
 	<-d.waitReady.Done()
 
-	// Prepare response.
+	metricSet, err := gatherMetrics(r.Context(), s, projectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return getFilteredMetrics(s, r, compress, metricSet)
+}
+
+// gatherMetrics returns the metrics of the local server for the given project, or for all projects if empty.
+// Instance metrics are cached for a few seconds to limit the load caused by frequent scrapes.
+func gatherMetrics(ctx context.Context, s *state.State, projectName string) (*metrics.MetricSet, error) {
+	// Prepare the metric set.
 	metricSet := metrics.NewMetricSet(nil)
 
 	var projectNames []string
+	var poolNames []string
 
-	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		// Figure out the projects to retrieve.
 		if projectName != "" {
 			projectNames = []string{projectName}
@@ -120,12 +137,28 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 		// Add internal metrics.
 		metricSet.Merge(internalMetrics(ctx, s.StartTime, tx))
 
+		var err error
+
+		poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+		if err != nil && !response.IsNotFoundError(err) {
+			return fmt.Errorf("Failed loading storage pools: %w", err)
+		}
+
 		return nil
 	})
 	if err != nil {
-		return response.SmartError(err)
+		return nil, err
 	}
 
+	// Cluster-wide values are only reported by the leader to avoid duplicate samples across cluster members.
+	isLeader, err := metricsIsLeader(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add storage pool metrics.
+	metricSet.Merge(storagePoolMetrics(s, poolNames, isLeader))
+
 	// invalidProjectFilters returns project filters which are either not in cache or have expired.
 	invalidProjectFilters := func(projectNames []string) []dbCluster.InstanceFilter {
 		metricsCacheLock.Lock()
@@ -158,23 +191,23 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 
 	// If all valid, return immediately.
 	if len(projectsToFetch) == 0 {
-		return getFilteredMetrics(s, r, compress, metricSet)
+		return metricSet, nil
 	}
 
 	cacheDuration := time.Duration(8) * time.Second
 
 	// Acquire update lock.
-	lockCtx, lockCtxCancel := context.WithTimeout(r.Context(), cacheDuration)
+	lockCtx, lockCtxCancel := context.WithTimeout(ctx, cacheDuration)
 	defer lockCtxCancel()
 
 	unlock, err := locking.Lock(lockCtx, "metricsGet")
 	if err != nil {
-		return response.SmartError(api.StatusErrorf(http.StatusLocked, "Metrics are currently being built by another request: %s", err))
+		return nil, api.StatusErrorf(http.StatusLocked, "Metrics are currently being built by another request: %s", err)
 	}
 
 	defer unlock()
 
-	// Setup a new response.
+	// Setup a new metric set.
 	metricSet = metrics.NewMetricSet(nil)
 
 	// Check if any of the missing data has been filled in since acquiring the lock.
@@ -183,15 +216,16 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 
 	// If all valid, return immediately.
 	if len(projectsToFetch) == 0 {
-		return getFilteredMetrics(s, r, compress, metricSet)
+		return metricSet, nil
 	}
 
 	// Gather information about host interfaces once.
 	hostInterfaces, _ := net.Interfaces()
 
 	var instances []instance.Instance
-	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
-		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+	var customVolumes map[string][]db.StorageVolumeArgs
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		err := tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
 			inst, err := instance.Load(s, dbInst, p)
 			if err != nil {
 				return fmt.Errorf("Failed loading instance %q in project %q: %w", dbInst.Name, dbInst.Project, err)
@@ -201,9 +235,22 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 
 			return nil
 		}, projectsToFetch...)
+		if err != nil {
+			return err
+		}
+
+		// Get the custom volumes available on the local server for all projects at once.
+		volumes, err := tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+		if err != nil {
+			return fmt.Errorf("Failed getting custom volumes: %w", err)
+		}
+
+		customVolumes = volumesByProject(volumes)
+
+		return nil
 	})
 	if err != nil {
-		return response.SmartError(err)
+		return nil, err
 	}
 
 	// Prepare temporary metrics storage.
@@ -230,20 +277,25 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 					if !errors.Is(err, instanceDrivers.ErrInstanceIsStopped) {
 						logger.Warn("Failed getting instance metrics", logger.Ctx{"instance": inst.Name(), "project": projectName, "err": err})
 					}
-				} else {
-					// Add the metrics.
-					newMetricsLock.Lock()
 
-					// Initialize metrics set for project if needed.
-					if newMetrics[projectName] == nil {
-						newMetrics[projectName] = metrics.NewMetricSet(nil)
-					}
+					instanceMetrics = metrics.NewMetricSet(nil)
+				}
+
+				// The volume usage is also available for stopped instances.
+				instanceMetrics.Merge(instanceVolumeMetrics(s, inst))
 
-					newMetrics[projectName].Merge(instanceMetrics)
+				// Add the metrics.
+				newMetricsLock.Lock()
 
-					newMetricsLock.Unlock()
+				// Initialize metrics set for project if needed.
+				if newMetrics[projectName] == nil {
+					newMetrics[projectName] = metrics.NewMetricSet(nil)
 				}
 
+				newMetrics[projectName].Merge(instanceMetrics)
+
+				newMetricsLock.Unlock()
+
 				wg.Done()
 			}
 		}(instMetricsCh)
@@ -258,7 +310,18 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	wg.Wait()
 	close(instMetricsCh)
 
-	// Put the new data in the global cache and in response.
+	// Add the project, custom volume and network metrics.
+	for _, filter := range projectsToFetch {
+		projectMetrics := projectResourceMetrics(ctx, s, *filter.Project, customVolumes[*filter.Project], isLeader)
+
+		if newMetrics[*filter.Project] == nil {
+			newMetrics[*filter.Project] = metrics.NewMetricSet(nil)
+		}
+
+		newMetrics[*filter.Project].Merge(projectMetrics)
+	}
+
+	// Put the new data in the global cache and in the metric set.
 	metricsCacheLock.Lock()
 
 	if metricsCache == nil {
@@ -288,7 +351,7 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 
 	metricsCacheLock.Unlock()
 
-	return getFilteredMetrics(s, r, compress, metricSet)
+	return metricSet, nil
 }
 
 func getFilteredMetrics(s *state.State, r *http.Request, compress bool, metricSet *metrics.MetricSet) response.Response {
@@ -367,3 +430,204 @@ func internalMetrics(ctx context.Context, daemonStartTime time.Time, tx *db.Clus
 
 	return out
 }
+
+// metricsIsLeader returns whether the local server reports the cluster-wide metrics, that is whether it's either
+// standalone or the cluster leader.
+func metricsIsLeader(s *state.State) (bool, error) {
+	if !s.ServerClustered {
+		return true, nil
+	}
+
+	leader, err := s.Cluster.LeaderAddress()
+	if err != nil {
+		return false, fmt.Errorf("Failed getting cluster leader address: %w", err)
+	}
+
+	return leader == s.LocalConfig.ClusterAddress(), nil
+}
+
+// storagePoolMetrics returns the capacity and usage of the storage pools on the local server.
+// Remote storage pools are shared by all cluster members, so they're only reported by the leader.
+func storagePoolMetrics(s *state.State, poolNames []string, isLeader bool) *metrics.MetricSet {
+	out := metrics.NewMetricSet(nil)
+
+	for _, poolName := range poolNames {
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			logger.Warn("Failed loading storage pool", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		if pool.Driver().Info().Remote && !isLeader {
+			continue
+		}
+
+		res, err := pool.GetResources()
+		if err != nil {
+			logger.Debug("Failed getting storage pool resources", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		out.Merge(storagePoolResourceMetrics(poolName, pool.Driver().Info().Name, res))
+	}
+
+	return out
+}
+
+// storagePoolResourceMetrics returns the metrics matching the resources of a storage pool.
+func storagePoolResourceMetrics(poolName string, driverName string, res *api.ResourcesStoragePool) *metrics.MetricSet {
+	out := metrics.NewMetricSet(nil)
+
+	labels := map[string]string{"pool": poolName, "driver": driverName}
+
+	out.AddSamples(metrics.StoragePoolSpaceBytes, metrics.Sample{Labels: labels, Value: float64(res.Space.Total)})
+	out.AddSamples(metrics.StoragePoolSpaceUsedBytes, metrics.Sample{Labels: maps.Clone(labels), Value: float64(res.Space.Used)})
+
+	// Not all drivers report inodes.
+	if res.Inodes.Total > 0 {
+		out.AddSamples(metrics.StoragePoolInodes, metrics.Sample{Labels: maps.Clone(labels), Value: float64(res.Inodes.Total)})
+		out.AddSamples(metrics.StoragePoolInodesUsed, metrics.Sample{Labels: maps.Clone(labels), Value: float64(res.Inodes.Used)})
+	}
+
+	return out
+}
+
+// instanceVolumeMetrics returns the usage of the root volume of the instance.
+func instanceVolumeMetrics(s *state.State, inst instance.Instance) *metrics.MetricSet {
+	out := metrics.NewMetricSet(nil)
+
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return out
+	}
+
+	usage, err := pool.GetInstanceUsage(inst)
+	if err != nil {
+		return out
+	}
+
+	addVolumeSamples(out, usage, map[string]string{"project": inst.Project().Name, "pool": pool.Name(), "type": inst.Type().String(), "volume": inst.Name()})
+
+	return out
+}
+
+// projectResourceMetrics returns the resource usage and limits of the project along with the usage of its
+// custom volumes and the leases of its networks on the local server.
+// The project usage and the custom volumes on remote storage pools are only reported by the leader.
+func projectResourceMetrics(ctx context.Context, s *state.State, projectName string, volumes []db.StorageVolumeArgs, isLeader bool) *metrics.MetricSet {
+	out := metrics.NewMetricSet(nil)
+
+	var allocations map[string]api.ProjectStateResource
+	var networkNames []string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		if isLeader {
+			allocations, err = project.GetCurrentAllocations(ctx, tx, projectName)
+			if err != nil {
+				return fmt.Errorf("Failed getting project allocations: %w", err)
+			}
+		}
+
+		networkNames, err = tx.GetCreatedNetworkNamesByProject(ctx, projectName)
+		if err != nil && !response.IsNotFoundError(err) {
+			return fmt.Errorf("Failed getting networks: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Warn("Failed getting project metrics", logger.Ctx{"project": projectName, "err": err})
+		return out
+	}
+
+	out.Merge(projectAllocationMetrics(projectName, allocations))
+
+	for _, volume := range volumes {
+		pool, err := storagePools.LoadByName(s, volume.PoolName)
+		if err != nil {
+			continue
+		}
+
+		if pool.Driver().Info().Remote && !isLeader {
+			continue
+		}
+
+		usage, err := pool.GetCustomVolumeUsage(projectName, volume.Name)
+		if err != nil {
+			continue
+		}
+
+		addVolumeSamples(out, usage, map[string]string{"project": projectName, "pool": volume.PoolName, "type": db.StoragePoolVolumeTypeNameCustom, "volume": volume.Name})
+	}
+
+	for _, networkName := range networkNames {
+		n, err := network.LoadByName(s, projectName, networkName)
+		if err != nil {
+			continue
+		}
+
+		// Only consider the leases known to the local server.
+		leases, err := n.Leases(projectName, clusterRequest.ClientTypeNotifier)
+		if err != nil {
+			continue
+		}
+
+		out.Merge(networkLeaseMetrics(projectName, networkName, leases))
+	}
+
+	return out
+}
+
+// projectAllocationMetrics returns the usage of the project resources and the limits which are set.
+func projectAllocationMetrics(projectName string, allocations map[string]api.ProjectStateResource) *metrics.MetricSet {
+	out := metrics.NewMetricSet(nil)
+
+	for resource, allocation := range allocations {
+		out.AddSamples(metrics.ProjectUsage, metrics.Sample{Labels: map[string]string{"project": projectName, "resource": resource}, Value: float64(allocation.Usage)})
+
+		// Only report the limits which are set.
+		if allocation.Limit >= 0 {
+			out.AddSamples(metrics.ProjectLimit, metrics.Sample{Labels: map[string]string{"project": projectName, "resource": resource}, Value: float64(allocation.Limit)})
+		}
+	}
+
+	return out
+}
+
+// networkLeaseMetrics returns the number of leases of a network by lease type.
+func networkLeaseMetrics(projectName string, networkName string, leases []api.NetworkLease) *metrics.MetricSet {
+	out := metrics.NewMetricSet(nil)
+
+	counts := map[string]int{}
+	for _, lease := range leases {
+		counts[lease.Type]++
+	}
+
+	for leaseType, count := range counts {
+		out.AddSamples(metrics.NetworkLeases, metrics.Sample{Labels: map[string]string{"project": projectName, "network": networkName, "type": leaseType}, Value: float64(count)})
+	}
+
+	return out
+}
+
+// volumesByProject groups storage volumes by project.
+func volumesByProject(volumes []db.StorageVolumeArgs) map[string][]db.StorageVolumeArgs {
+	out := map[string][]db.StorageVolumeArgs{}
+
+	for _, volume := range volumes {
+		out[volume.ProjectName] = append(out[volume.ProjectName], volume)
+	}
+
+	return out
+}
+
+// addVolumeSamples adds the used space and, if known, the size of a volume.
+func addVolumeSamples(out *metrics.MetricSet, usage *storagePools.VolumeUsage, labels map[string]string) {
+	out.AddSamples(metrics.StorageVolumeUsedBytes, metrics.Sample{Labels: labels, Value: float64(usage.Used)})
+
+	if usage.Total > 0 {
+		out.AddSamples(metrics.StorageVolumeSizeBytes, metrics.Sample{Labels: maps.Clone(labels), Value: float64(usage.Total)})
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/metrics"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func Test_projectAllocationMetrics(t *testing.T) {
+	allocations := map[string]api.ProjectStateResource{
+		"instances":    {Limit: 10, Usage: 2},
+		"memory":       {Limit: -1, Usage: 1073741824},
+		"disk.default": {Limit: -1, Usage: 0},
+	}
+
+	out := projectAllocationMetrics("ci", allocations)
+
+	usage := out.Samples(metrics.ProjectUsage)
+	require.Len(t, usage, 3)
+	assert.ElementsMatch(t, []metrics.Sample{
+		{Labels: map[string]string{"project": "ci", "resource": "instances"}, Value: 2},
+		{Labels: map[string]string{"project": "ci", "resource": "memory"}, Value: 1073741824},
+		{Labels: map[string]string{"project": "ci", "resource": "disk.default"}, Value: 0},
+	}, usage)
+
+	// Only the limits which are set are reported.
+	assert.Equal(t, []metrics.Sample{
+		{Labels: map[string]string{"project": "ci", "resource": "instances"}, Value: 10},
+	}, out.Samples(metrics.ProjectLimit))
+
+	// Non-leader members don't load the allocations.
+	assert.Empty(t, projectAllocationMetrics("ci", nil).Types())
+}
+
+func Test_networkLeaseMetrics(t *testing.T) {
+	leases := []api.NetworkLease{
+		{Hostname: "c1", Address: "10.0.0.2", Type: "dynamic"},
+		{Hostname: "c1", Address: "fd42::2", Type: "dynamic"},
+		{Hostname: "c2", Address: "10.0.0.3", Type: "static"},
+		{Hostname: "gw", Address: "10.0.0.1", Type: "gateway"},
+	}
+
+	out := networkLeaseMetrics("ci", "incusbr0", leases)
+
+	assert.ElementsMatch(t, []metrics.Sample{
+		{Labels: map[string]string{"project": "ci", "network": "incusbr0", "type": "dynamic"}, Value: 2},
+		{Labels: map[string]string{"project": "ci", "network": "incusbr0", "type": "static"}, Value: 1},
+		{Labels: map[string]string{"project": "ci", "network": "incusbr0", "type": "gateway"}, Value: 1},
+	}, out.Samples(metrics.NetworkLeases))
+
+	assert.Empty(t, networkLeaseMetrics("ci", "incusbr0", nil).Types())
+}
+
+func Test_storagePoolResourceMetrics(t *testing.T) {
+	res := &api.ResourcesStoragePool{}
+	res.Space.Total = 1000
+	res.Space.Used = 250
+
+	out := storagePoolResourceMetrics("default", "zfs", res)
+
+	labels := map[string]string{"pool": "default", "driver": "zfs"}
+	assert.Equal(t, []metrics.Sample{{Labels: labels, Value: 1000}}, out.Samples(metrics.StoragePoolSpaceBytes))
+	assert.Equal(t, []metrics.Sample{{Labels: labels, Value: 250}}, out.Samples(metrics.StoragePoolSpaceUsedBytes))
+
+	// Inodes are only reported when known to the driver.
+	assert.Empty(t, out.Samples(metrics.StoragePoolInodes))
+	assert.Empty(t, out.Samples(metrics.StoragePoolInodesUsed))
+
+	res.Inodes.Total = 100
+	res.Inodes.Used = 10
+
+	out = storagePoolResourceMetrics("default", "dir", res)
+
+	labels = map[string]string{"pool": "default", "driver": "dir"}
+	assert.Equal(t, []metrics.Sample{{Labels: labels, Value: 100}}, out.Samples(metrics.StoragePoolInodes))
+	assert.Equal(t, []metrics.Sample{{Labels: labels, Value: 10}}, out.Samples(metrics.StoragePoolInodesUsed))
+}
+
+func Test_volumesByProject(t *testing.T) {
+	volumes := []db.StorageVolumeArgs{
+		{Name: "vol1", PoolName: "default", ProjectName: "default"},
+		{Name: "vol2", PoolName: "ceph", ProjectName: "ci"},
+		{Name: "vol3", PoolName: "default", ProjectName: "ci"},
+	}
+
+	assert.Equal(t, map[string][]db.StorageVolumeArgs{
+		"default": {volumes[0]},
+		"ci":      {volumes[1], volumes[2]},
+	}, volumesByProject(volumes))
+
+	assert.Empty(t, volumesByProject(nil))
+}
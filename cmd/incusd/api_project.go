@@ -267,6 +267,13 @@ func projectUsedBy(ctx context.Context, tx *db.ClusterTx, project *cluster.Proje
 
 	usedBy = append(usedBy, networkACLs...)
 
+	networkAddressSets, err := tx.GetNetworkAddressSetURIs(ctx, project.ID, project.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	usedBy = append(usedBy, networkAddressSets...)
+
 	networkZones, err := tx.GetNetworkZoneURIs(ctx, project.ID, project.Name)
 	if err != nil {
 		return nil, err
@@ -1167,6 +1174,17 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 			count--
 		}
 
+		// Delete network address sets.
+		for _, networkAddressSetName := range entries["network-address-sets"] {
+			err := target.DeleteNetworkAddressSet(networkAddressSetName)
+			if err != nil {
+				return response.InternalError(err)
+			}
+
+			// Done deleting the network address set.
+			count--
+		}
+
 		// Delete network zones.
 		for _, networkZoneName := range entries["network-zones"] {
 			err := target.DeleteNetworkZone(networkZoneName)
@@ -1499,6 +1517,15 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Whether to prevent creating instance or volume backups
 		"restricted.backups": isEitherAllowOrBlock,
 
+		// gendoc:generate(entity=project, group=restricted, key=restricted.backups.external)
+		// Possible values are `allow` or `block`.
+		// When set to `allow`, backups can be uploaded to and imported from buckets on arbitrary S3-compatible endpoints, which lets the server connect to any address.
+		// ---
+		//  type: string
+		//  defaultdesc: `block`
+		//  shortdesc: Whether to prevent using backups on external S3 endpoints
+		"restricted.backups.external": isEitherAllowOrBlock,
+
 		// gendoc:generate(entity=project, group=restricted, key=restricted.cluster.groups)
 		// If specified, this option prevents targeting cluster groups other than the provided ones.
 		// ---
@@ -5,12 +5,21 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"gopkg.in/yaml.v2"
 
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/instancewriter"
+	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
@@ -22,7 +31,9 @@ import (
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/internal/server/storage/s3"
 	"github.com/lxc/incus/v6/internal/server/task"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/idmap"
@@ -53,6 +64,41 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		args.OptimizedStorage = false
 	}
 
+	// Resolve the storage anchor of the backup an incremental backup is based on.
+	var parentAnchor string
+	if args.IncrementalFrom != "" {
+		if !pool.Driver().Info().IncrementalBackups {
+			return fmt.Errorf("Storage pool %q doesn't support incremental backups", pool.Name())
+		}
+
+		parent, err := instance.BackupLoadByName(s, sourceInst.Project().Name, args.IncrementalFrom)
+		if err != nil {
+			return fmt.Errorf("Failed loading backup %q: %w", args.IncrementalFrom, err)
+		}
+
+		if parent.Anchor() == "" {
+			return fmt.Errorf("Backup %q cannot be used as the base of an incremental backup", args.IncrementalFrom)
+		}
+
+		parentAnchor = parent.Anchor()
+		args.OptimizedStorage = true
+		args.InstanceOnly = true
+
+		// Incremental backups are in turn the base of the next one.
+		if args.Anchor == "" {
+			args.Anchor = uuid.New().String()
+		}
+	}
+
+	// Backups meant as the base of later incremental backups keep a storage anchor.
+	if args.Anchor != "" {
+		if !pool.Driver().Info().IncrementalBackups {
+			return fmt.Errorf("Storage pool %q doesn't support incremental backups", pool.Name())
+		}
+
+		args.OptimizedStorage = true
+	}
+
 	// Create the database entry.
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		return tx.CreateInstanceBackup(ctx, args)
@@ -78,31 +124,10 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 	}
 
 	// Detect compression method.
-	var compress string
 	b.SetCompressionAlgorithm(args.CompressionAlgorithm)
-	if b.CompressionAlgorithm() != "" {
-		compress = b.CompressionAlgorithm()
-	} else {
-		var p *api.Project
-		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			project, err := dbCluster.GetProject(ctx, tx.Tx(), sourceInst.Project().Name)
-			if err != nil {
-				return err
-			}
-
-			p, err = project.ToAPI(ctx, tx.Tx())
-
-			return err
-		})
-		if err != nil {
-			return err
-		}
-
-		if p.Config["backups.compression_algorithm"] != "" {
-			compress = p.Config["backups.compression_algorithm"]
-		} else {
-			compress = s.GlobalConfig.BackupsCompressionAlgorithm()
-		}
+	compress, err := backupCompressionAlgorithm(s, sourceInst.Project().Name, b.CompressionAlgorithm())
+	if err != nil {
+		return err
 	}
 
 	// Create the target path if needed.
@@ -128,7 +153,221 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 	defer func() { _ = tarFileWriter.Close() }()
 	revert.Add(func() { _ = os.Remove(target) })
 
+	if args.Anchor != "" {
+		revert.Add(func() { _ = pool.DeleteInstanceBackupAnchor(sourceInst, args.Anchor, nil) })
+	}
+
+	err = backupWriteTarball(sourceInst, pool, b.OptimizedStorage(), b.InstanceOnly(), args.Anchor, parentAnchor, compress, tarFileWriter, op)
+	if err != nil {
+		return err
+	}
+
+	err = tarFileWriter.Close()
+	if err != nil {
+		return fmt.Errorf("Error closing tar file: %w", err)
+	}
+
+	revert.Success()
+	s.Events.SendLifecycle(sourceInst.Project().Name, lifecycle.InstanceBackupCreated.Event(args.Name, b.Instance(), nil))
+
+	return nil
+}
+
+// backupUpload generates an instance backup and uploads it straight to a storage bucket.
+func backupUpload(s *state.State, args db.InstanceBackup, sourceInst instance.Instance, location *api.BackupBucket, op *operations.Operation) error {
+	l := logger.AddContext(logger.Ctx{"project": sourceInst.Project().Name, "instance": sourceInst.Name(), "name": args.Name, "bucket": location.Bucket, "path": location.Path})
+	l.Debug("Instance backup upload started")
+	defer l.Debug("Instance backup upload finished")
+
+	transferManager, err := backupBucketTransferManager(s, sourceInst.Project().Name, location)
+	if err != nil {
+		return err
+	}
+
+	// Get storage pool.
+	pool, err := storagePools.LoadByInstance(s, sourceInst)
+	if err != nil {
+		return fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	// Ignore requests for optimized backups when pool driver doesn't support it.
+	if args.OptimizedStorage && !pool.Driver().Info().OptimizedBackups {
+		args.OptimizedStorage = false
+	}
+
+	// Detect compression method.
+	compress, err := backupCompressionAlgorithm(s, sourceInst.Project().Name, args.CompressionAlgorithm)
+	if err != nil {
+		return err
+	}
+
+	err = backupStreamToBucket(transferManager, location, func(writer io.WriteCloser) error {
+		return backupWriteTarball(sourceInst, pool, args.OptimizedStorage, args.InstanceOnly, "", "", compress, writer, op)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed uploading backup to bucket %q: %w", location.Bucket, err)
+	}
+
+	s.Events.SendLifecycle(sourceInst.Project().Name, lifecycle.InstanceBackupExported.Event(args.Name, sourceInst, map[string]any{"target": backupBucketTarget(location)}))
+
+	return nil
+}
+
+// backupBucketTransferManager returns a transfer manager for the storage bucket holding a backup file.
+func backupBucketTransferManager(s *state.State, projectName string, location *api.BackupBucket) (*s3.TransferManager, error) {
+	if location.Bucket == "" {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "No bucket name provided")
+	}
+
+	if location.Path == "" {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "No path in the bucket provided")
+	}
+
+	// Handle Incus storage buckets.
+	if location.Pool != "" {
+		if location.URL != "" || location.AccessKey != "" || location.SecretKey != "" {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "The URL and keys can't be set for Incus storage buckets")
+		}
+
+		bucketProjectName, err := project.StorageBucketProject(context.TODO(), s.DB.Cluster, projectName)
+		if err != nil {
+			return nil, err
+		}
+
+		pool, err := storagePools.LoadByName(s, location.Pool)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading storage pool %q: %w", location.Pool, err)
+		}
+
+		return pool.GetBucketTransferManager(bucketProjectName, location.Bucket)
+	}
+
+	// Handle external S3 endpoints.
+	if location.URL == "" {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Either a storage pool or an S3 URL must be provided")
+	}
+
+	s3URL, err := url.Parse(location.URL)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid S3 URL %q: %v", location.URL, err)
+	}
+
+	if !slices.Contains([]string{"http", "https"}, s3URL.Scheme) || s3URL.Host == "" {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid S3 URL %q", location.URL)
+	}
+
+	transferManager := s3.NewRemoteTransferManager(s3URL, location.AccessKey, location.SecretKey)
+
+	return &transferManager, nil
+}
+
+// backupBucketCheckAccess checks that the requestor has the given entitlement on the Incus storage bucket holding a backup file.
+func backupBucketCheckAccess(s *state.State, r *http.Request, projectName string, location *api.BackupBucket, entitlement auth.Entitlement) error {
+	// External S3 endpoints are accessed with the credentials provided in the request, but as those
+	// make the server connect to arbitrary addresses, restricted projects must explicitly allow them.
+	if location.Pool == "" {
+		var p *api.Project
+		err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+			if err != nil {
+				return err
+			}
+
+			p, err = dbProject.ToAPI(ctx, tx.Tx())
+
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed loading project %q: %w", projectName, err)
+		}
+
+		err = project.AllowExternalBackupBucket(p)
+		if err != nil {
+			return api.StatusErrorf(http.StatusForbidden, "%w", err)
+		}
+
+		return nil
+	}
+
+	bucketProjectName, err := project.StorageBucketProject(r.Context(), s.DB.Cluster, projectName)
+	if err != nil {
+		return err
+	}
+
+	pool, err := storagePools.LoadByName(s, location.Pool)
+	if err != nil {
+		return fmt.Errorf("Failed loading storage pool %q: %w", location.Pool, err)
+	}
+
+	var bucketLocation string
+	if s.ServerClustered && !pool.Driver().Info().Remote {
+		bucketLocation = s.ServerName
+	}
+
+	return s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectStorageBucket(bucketProjectName, location.Pool, location.Bucket, bucketLocation), entitlement)
+}
+
+// backupBucketTarget returns a string identifying a backup file in a storage bucket, for use in events.
+func backupBucketTarget(location *api.BackupBucket) string {
+	if location.Pool != "" {
+		return fmt.Sprintf("%s/%s/%s", location.Pool, location.Bucket, location.Path)
+	}
+
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(location.URL, "/"), location.Bucket, location.Path)
+}
+
+// backupStreamToBucket streams the data produced by the write function into a file in a storage bucket.
+func backupStreamToBucket(transferManager *s3.TransferManager, location *api.BackupBucket, write func(writer io.WriteCloser) error) error {
+	pipeReader, pipeWriter := io.Pipe()
+
+	uploadRes := make(chan error, 1)
+	go func() {
+		err := transferManager.UploadFile(location.Bucket, location.Path, pipeReader)
+
+		// Unblock the writer if the upload failed.
+		_ = pipeReader.CloseWithError(err)
+		uploadRes <- err
+	}()
+
+	err := write(pipeWriter)
+	if err != nil {
+		_ = pipeWriter.CloseWithError(err)
+
+		// Report the upload error if that's what caused the write to fail.
+		uploadErr := <-uploadRes
+		if uploadErr != nil {
+			return uploadErr
+		}
+
+		return err
+	}
+
+	_ = pipeWriter.Close()
+
+	return <-uploadRes
+}
+
+// backupBucketReader returns a reader for a backup file stored in a storage bucket.
+func backupBucketReader(s *state.State, projectName string, location *api.BackupBucket) (io.ReadCloser, error) {
+	transferManager, err := backupBucketTransferManager(s, projectName, location)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := transferManager.DownloadFile(location.Bucket, location.Path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed downloading backup from bucket %q: %w", location.Bucket, err)
+	}
+
+	return reader, nil
+}
+
+// backupWriteTarball generates the backup tarball of an instance and writes it to the provided writer.
+func backupWriteTarball(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, instanceOnly bool, anchor string, parentAnchor string, compress string, writer io.WriteCloser, op *operations.Operation) error {
+	l := logger.AddContext(logger.Ctx{"project": sourceInst.Project().Name, "instance": sourceInst.Name()})
+
 	// Get IDMap to unshift container as the tarball is created.
+	var err error
 	var idmapSet *idmap.Set
 	if sourceInst.Type() == instancetype.Container {
 		c := sourceInst.(instance.Container)
@@ -166,7 +405,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		l.Debug("Started backup tarball writer")
 		defer l.Debug("Finished backup tarball writer")
 		if compress != "none" {
-			backupProgressWriter.WriteCloser = tarFileWriter
+			backupProgressWriter.WriteCloser = writer
 			compressErr = compressFile(compress, tarPipeReader, backupProgressWriter)
 
 			// If a compression error occurred, close the tarPipeWriter to end the export.
@@ -174,7 +413,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 				_ = tarPipeWriter.Close()
 			}
 		} else {
-			backupProgressWriter.WriteCloser = tarFileWriter
+			backupProgressWriter.WriteCloser = writer
 			_, err = io.Copy(backupProgressWriter, tarPipeReader)
 		}
 
@@ -183,7 +422,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 
 	// Write index file.
 	l.Debug("Adding backup index file")
-	err = backupWriteIndex(sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), tarWriter)
+	err = backupWriteIndex(sourceInst, pool, optimized, !instanceOnly, anchor, parentAnchor, tarWriter)
 
 	// Check compression errors.
 	if compressErr != nil {
@@ -195,7 +434,12 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Error writing backup index file: %w", err)
 	}
 
-	err = pool.BackupInstance(sourceInst, tarWriter, b.OptimizedStorage(), !b.InstanceOnly(), nil)
+	if anchor != "" {
+		err = pool.BackupInstanceIncremental(sourceInst, tarWriter, !instanceOnly, anchor, parentAnchor, nil)
+	} else {
+		err = pool.BackupInstance(sourceInst, tarWriter, optimized, !instanceOnly, nil)
+	}
+
 	if err != nil {
 		return fmt.Errorf("Backup create: %w", err)
 	}
@@ -217,19 +461,39 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Error writing tarball: %w", err)
 	}
 
-	err = tarFileWriter.Close()
+	return nil
+}
+
+// backupCompressionAlgorithm returns the compression algorithm to use for a backup in a project.
+func backupCompressionAlgorithm(s *state.State, projectName string, requested string) (string, error) {
+	if requested != "" {
+		return requested, nil
+	}
+
+	var p *api.Project
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		project, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		p, err = project.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("Error closing tar file: %w", err)
+		return "", err
 	}
 
-	revert.Success()
-	s.Events.SendLifecycle(sourceInst.Project().Name, lifecycle.InstanceBackupCreated.Event(args.Name, b.Instance(), nil))
+	if p.Config["backups.compression_algorithm"] != "" {
+		return p.Config["backups.compression_algorithm"], nil
+	}
 
-	return nil
+	return s.GlobalConfig.BackupsCompressionAlgorithm(), nil
 }
 
 // backupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
-func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, tarWriter *instancewriter.InstanceTarWriter) error {
+func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, anchor string, incrementalFrom string, tarWriter *instancewriter.InstanceTarWriter) error {
 	// Indicate whether the driver will include a driver-specific optimized header.
 	poolDriverOptimizedHeader := false
 	if optimized {
@@ -264,6 +528,8 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 		OptimizedStorage: &optimized,
 		OptimizedHeader:  &poolDriverOptimizedHeader,
 		Config:           config,
+		Anchor:           anchor,
+		IncrementalFrom:  incrementalFrom,
 	}
 
 	if snapshots {
@@ -378,8 +644,12 @@ func pruneExpiredInstanceBackups(ctx context.Context, s *state.State) error {
 			return fmt.Errorf("Error loading instance for deleting backup %q: %w", b.Name, err)
 		}
 
-		instBackup := backup.NewInstanceBackup(s, inst, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.InstanceOnly, b.OptimizedStorage)
-		err = instBackup.Delete()
+		instBackup, err := instance.BackupLoadByName(s, inst.Project().Name, b.Name)
+		if err != nil {
+			return fmt.Errorf("Error loading instance backup %q: %w", b.Name, err)
+		}
+
+		err = backupDelete(s, inst, instBackup)
 		if err != nil {
 			return fmt.Errorf("Error deleting instance backup %q: %w", b.Name, err)
 		}
@@ -388,6 +658,23 @@ func pruneExpiredInstanceBackups(ctx context.Context, s *state.State) error {
 	return nil
 }
 
+// backupDelete removes an instance backup along with the storage anchor kept for incremental backups.
+func backupDelete(s *state.State, inst instance.Instance, b *backup.InstanceBackup) error {
+	if b.Anchor() != "" {
+		pool, err := storagePools.LoadByInstance(s, inst)
+		if err != nil {
+			return fmt.Errorf("Failed loading instance storage pool: %w", err)
+		}
+
+		err = pool.DeleteInstanceBackupAnchor(inst, b.Anchor(), nil)
+		if err != nil {
+			return fmt.Errorf("Failed deleting backup anchor: %w", err)
+		}
+	}
+
+	return b.Delete()
+}
+
 func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, projectName string, poolName string, volumeName string) error {
 	l := logger.AddContext(logger.Ctx{"project": projectName, "storage_volume": volumeName, "name": args.Name})
 	l.Debug("Volume backup started")
@@ -469,6 +756,64 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 	defer func() { _ = tarFileWriter.Close() }()
 	revert.Add(func() { _ = os.Remove(target) })
 
+	err = volumeBackupWriteTarball(s, projectName, volumeName, pool, backupRow.OptimizedStorage, backupRow.VolumeOnly, compress, tarFileWriter)
+	if err != nil {
+		return err
+	}
+
+	err = tarFileWriter.Close()
+	if err != nil {
+		return fmt.Errorf("Error closing tar file: %w", err)
+	}
+
+	revert.Success()
+	return nil
+}
+
+// volumeBackupUpload generates a custom volume backup and uploads it straight to a storage bucket.
+func volumeBackupUpload(s *state.State, args db.StoragePoolVolumeBackup, projectName string, poolName string, volumeName string, location *api.BackupBucket) error {
+	l := logger.AddContext(logger.Ctx{"project": projectName, "storage_volume": volumeName, "name": args.Name, "bucket": location.Bucket, "path": location.Path})
+	l.Debug("Volume backup upload started")
+	defer l.Debug("Volume backup upload finished")
+
+	transferManager, err := backupBucketTransferManager(s, projectName, location)
+	if err != nil {
+		return err
+	}
+
+	// Get storage pool.
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return fmt.Errorf("Failed loading storage pool %q: %w", poolName, err)
+	}
+
+	// Ignore requests for optimized backups when pool driver doesn't support it.
+	if args.OptimizedStorage && !pool.Driver().Info().OptimizedBackups {
+		args.OptimizedStorage = false
+	}
+
+	// Detect compression method.
+	compress := args.CompressionAlgorithm
+	if compress == "" {
+		compress = s.GlobalConfig.BackupsCompressionAlgorithm()
+	}
+
+	err = backupStreamToBucket(transferManager, location, func(writer io.WriteCloser) error {
+		return volumeBackupWriteTarball(s, projectName, volumeName, pool, args.OptimizedStorage, args.VolumeOnly, compress, writer)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed uploading backup to bucket %q: %w", location.Bucket, err)
+	}
+
+	return nil
+}
+
+// volumeBackupWriteTarball generates the backup tarball of a custom volume and writes it to the provided writer.
+func volumeBackupWriteTarball(s *state.State, projectName string, volumeName string, pool storagePools.Pool, optimized bool, volumeOnly bool, compress string, writer io.Writer) error {
+	l := logger.AddContext(logger.Ctx{"project": projectName, "storage_volume": volumeName})
+
+	var err error
+
 	// Create the tarball.
 	tarPipeReader, tarPipeWriter := io.Pipe()
 	defer func() { _ = tarPipeWriter.Close() }() // Ensure that go routine below always ends.
@@ -482,14 +827,14 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		l.Debug("Started backup tarball writer")
 		defer l.Debug("Finished backup tarball writer")
 		if compress != "none" {
-			compressErr = compressFile(compress, tarPipeReader, tarFileWriter)
+			compressErr = compressFile(compress, tarPipeReader, writer)
 
 			// If a compression error occurred, close the tarPipeWriter to end the export.
 			if compressErr != nil {
 				_ = tarPipeWriter.Close()
 			}
 		} else {
-			_, err = io.Copy(tarFileWriter, tarPipeReader)
+			_, err = io.Copy(writer, tarPipeReader)
 		}
 
 		resCh <- err
@@ -497,7 +842,7 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 
 	// Write index file.
 	l.Debug("Adding backup index file")
-	err = volumeBackupWriteIndex(s, projectName, volumeName, pool, backupRow.OptimizedStorage, !backupRow.VolumeOnly, tarWriter)
+	err = volumeBackupWriteIndex(s, projectName, volumeName, pool, optimized, !volumeOnly, tarWriter)
 
 	// Check compression errors.
 	if compressErr != nil {
@@ -509,7 +854,7 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		return fmt.Errorf("Error writing backup index file: %w", err)
 	}
 
-	err = pool.BackupCustomVolume(projectName, volumeName, tarWriter, backupRow.OptimizedStorage, !backupRow.VolumeOnly, nil)
+	err = pool.BackupCustomVolume(projectName, volumeName, tarWriter, optimized, !volumeOnly, nil)
 	if err != nil {
 		return fmt.Errorf("Backup create: %w", err)
 	}
@@ -531,12 +876,6 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		return fmt.Errorf("Error writing tarball: %w", err)
 	}
 
-	err = tarFileWriter.Close()
-	if err != nil {
-		return fmt.Errorf("Error closing tar file: %w", err)
-	}
-
-	revert.Success()
 	return nil
 }
 
@@ -864,3 +1203,545 @@ func pruneExpiredStorageBucketBackups(ctx context.Context, s *state.State) error
 
 	return nil
 }
+
+// scheduledBackupTimeFormat is the time format used in the name of scheduled backups.
+const scheduledBackupTimeFormat = "20060102-150405"
+
+// scheduledBackupName returns the name of a scheduled backup created at the given time.
+func scheduledBackupName(creationDate time.Time) string {
+	return "scheduled-" + creationDate.UTC().Format(scheduledBackupTimeFormat)
+}
+
+func autoCreateBackupsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+		var instances []instance.Instance
+		var volumes, remoteVolumes []db.StorageVolumeArgs
+		var memberCount int
+		var onlineMemberIDs []int64
+
+		// Get list of instances on the local member that are due to have backups created.
+		filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			err := tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return fmt.Errorf("Failed loading instance %q (project %q) for backup task: %w", dbInst.Name, dbInst.Project, err)
+				}
+
+				// Check if instance has backup schedule enabled.
+				schedule, ok := inst.ExpandedConfig()["backups.schedule"]
+				if !ok || schedule == "" {
+					return nil
+				}
+
+				// Check if backup is scheduled.
+				if !snapshotIsScheduledNow(schedule, int64(inst.ID())) {
+					return nil
+				}
+
+				err = project.AllowBackupCreation(tx, p.Name)
+				if err != nil {
+					return nil
+				}
+
+				logger.Debug("Scheduling auto instance backup", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name})
+				instances = append(instances, inst)
+
+				return nil
+			}, filter)
+			if err != nil {
+				return err
+			}
+
+			allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+			if err != nil {
+				return fmt.Errorf("Failed getting volumes for auto custom volume backup task: %w", err)
+			}
+
+			for _, v := range allVolumes {
+				schedule, ok := v.Config["backups.schedule"]
+				if !ok || schedule == "" {
+					continue
+				}
+
+				// Check if backup is scheduled.
+				if !snapshotIsScheduledNow(schedule, v.ID) {
+					continue
+				}
+
+				err = project.AllowBackupCreation(tx, v.ProjectName)
+				if err != nil {
+					continue
+				}
+
+				if v.NodeID < 0 {
+					// Keep a separate list of remote volumes in order to select a member to
+					// perform the backup later.
+					remoteVolumes = append(remoteVolumes, v)
+				} else {
+					logger.Debug("Scheduling local auto custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+					volumes = append(volumes, v) // Always include local volumes.
+				}
+			}
+
+			if len(remoteVolumes) > 0 {
+				// Get list of cluster members.
+				members, err := tx.GetNodes(ctx)
+				if err != nil {
+					return fmt.Errorf("Failed getting cluster members: %w", err)
+				}
+
+				memberCount = len(members)
+
+				// Filter to online members.
+				for _, member := range members {
+					if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+						continue
+					}
+
+					onlineMemberIDs = append(onlineMemberIDs, member.ID)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Error("Failed getting backup schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(remoteVolumes) > 0 {
+			// Skip backing up remote custom volumes if there are no online members, as we can't be
+			// sure that the cluster isn't partitioned and we may end up attempting the backup on
+			// multiple members.
+			if memberCount > 1 && len(onlineMemberIDs) <= 0 {
+				logger.Error("Skipping remote volumes for auto custom volume backup task due to no online members")
+			} else {
+				localMemberID := s.DB.Cluster.GetNodeID()
+
+				for _, v := range remoteVolumes {
+					// If there are multiple cluster members, a stable random member is chosen
+					// to perform the backup from.
+					if memberCount > 1 {
+						selectedMemberID, err := localUtil.GetStableRandomInt64FromList(int64(v.ID), onlineMemberIDs)
+						if err != nil {
+							logger.Error("Failed scheduling remote auto custom volume backup task", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+							continue
+						}
+
+						// Don't backup, if we're not the chosen one.
+						if localMemberID != selectedMemberID {
+							continue
+						}
+					}
+
+					logger.Debug("Scheduling remote auto custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+					volumes = append(volumes, v)
+				}
+			}
+		}
+
+		// Handle instance backups.
+		if len(instances) > 0 {
+			opRun := func(op *operations.Operation) error {
+				return autoCreateInstanceBackups(ctx, s, instances, op)
+			}
+
+			op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.BackupCreate, nil, nil, opRun, nil, nil, nil)
+			if err != nil {
+				logger.Error("Failed creating scheduled instance backup operation", logger.Ctx{"err": err})
+			} else {
+				logger.Info("Creating scheduled instance backups")
+
+				err = op.Start()
+				if err != nil {
+					logger.Error("Failed starting scheduled instance backup operation", logger.Ctx{"err": err})
+				} else {
+					err = op.Wait(ctx)
+					if err != nil {
+						logger.Error("Failed scheduled instance backups", logger.Ctx{"err": err})
+					} else {
+						logger.Info("Done creating scheduled instance backups")
+					}
+				}
+			}
+		}
+
+		// Handle custom volume backups.
+		if len(volumes) > 0 {
+			opRun := func(op *operations.Operation) error {
+				return autoCreateCustomVolumeBackups(ctx, s, volumes, op)
+			}
+
+			op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.CustomVolumeBackupCreate, nil, nil, opRun, nil, nil, nil)
+			if err != nil {
+				logger.Error("Failed creating scheduled volume backup operation", logger.Ctx{"err": err})
+			} else {
+				logger.Info("Creating scheduled volume backups")
+
+				err = op.Start()
+				if err != nil {
+					logger.Error("Failed starting scheduled volume backup operation", logger.Ctx{"err": err})
+				} else {
+					err = op.Wait(ctx)
+					if err != nil {
+						logger.Error("Failed scheduled volume backups", logger.Ctx{"err": err})
+					} else {
+						logger.Info("Done creating scheduled volume backups")
+					}
+				}
+			}
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+func autoCreateInstanceBackups(ctx context.Context, s *state.State, instances []instance.Instance, op *operations.Operation) error {
+	for _, inst := range instances {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		// Failing to back up one instance shouldn't prevent backing up the others.
+		err = autoCreateInstanceBackup(s, inst, op)
+		if err != nil {
+			logger.Warn("Failed creating scheduled instance backup", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+		}
+	}
+
+	return nil
+}
+
+// autoCreateInstanceBackup creates a scheduled backup of an instance and stores it in the configured target.
+func autoCreateInstanceBackup(s *state.State, inst instance.Instance, op *operations.Operation) error {
+	now := time.Now()
+	expiry := inst.ExpandedConfig()["backups.expiry"]
+
+	target, err := internalInstance.ParseBackupTarget(inst.ExpandedConfig()["backups.target"])
+	if err != nil {
+		return fmt.Errorf("Invalid backups.target: %w", err)
+	}
+
+	args := db.InstanceBackup{
+		InstanceID:   inst.ID(),
+		Name:         inst.Name() + internalInstance.SnapshotDelimiter + scheduledBackupName(now),
+		CreationDate: now,
+	}
+
+	prefix := project.Instance(inst.Project().Name, inst.Name()) + "-"
+
+	// Backups written to a storage bucket are streamed straight to it.
+	if target.Bucket != "" {
+		location := &api.BackupBucket{
+			Pool:   target.Pool,
+			Bucket: target.Bucket,
+			Path:   path.Join(target.Prefix, prefix+scheduledBackupName(now)+".backup"),
+		}
+
+		err = backupUpload(s, args, inst, location, op)
+		if err != nil {
+			return fmt.Errorf("Failed uploading backup %q: %w", args.Name, err)
+		}
+
+		err = pruneExpiredBackupBucket(s, inst.Project().Name, target, prefix, expiry)
+		if err != nil {
+			return fmt.Errorf("Failed pruning expired backups: %w", err)
+		}
+
+		return nil
+	}
+
+	// Backups kept on the server are expired by the backup pruning task, those written to a
+	// target directory are rotated below.
+	if target.Path == "" {
+		args.ExpiryDate, err = internalInstance.GetExpiry(now, expiry)
+		if err != nil {
+			return fmt.Errorf("Failed getting backups.expiry date: %w", err)
+		}
+	}
+
+	err = backupCreate(s, args, inst, op)
+	if err != nil {
+		return fmt.Errorf("Failed creating backup %q: %w", args.Name, err)
+	}
+
+	if target.Path == "" {
+		return nil
+	}
+
+	b, err := instance.BackupLoadByName(s, inst.Project().Name, args.Name)
+	if err != nil {
+		return fmt.Errorf("Failed loading instance backup %q: %w", args.Name, err)
+	}
+
+	source := internalUtil.VarPath("backups", "instances", project.Instance(inst.Project().Name, b.Name()))
+
+	targetPath, err := backupExportToTarget(source, target.Path, prefix, now)
+	if err != nil {
+		_ = backupDelete(s, inst, b)
+		return fmt.Errorf("Failed exporting backup %q: %w", args.Name, err)
+	}
+
+	err = backupDelete(s, inst, b)
+	if err != nil {
+		return fmt.Errorf("Failed deleting instance backup %q: %w", args.Name, err)
+	}
+
+	s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceBackupExported.Event(args.Name, inst, map[string]any{"target": targetPath}))
+
+	err = pruneExpiredBackupTarget(target.Path, prefix, expiry)
+	if err != nil {
+		return fmt.Errorf("Failed pruning expired backups: %w", err)
+	}
+
+	return nil
+}
+
+func autoCreateCustomVolumeBackups(ctx context.Context, s *state.State, volumes []db.StorageVolumeArgs, op *operations.Operation) error {
+	for _, v := range volumes {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		// Failing to back up one volume shouldn't prevent backing up the others.
+		err = autoCreateCustomVolumeBackup(ctx, s, v, op)
+		if err != nil {
+			logger.Warn("Failed creating scheduled volume backup", logger.Ctx{"project": v.ProjectName, "pool": v.PoolName, "volume": v.Name, "err": err})
+		}
+	}
+
+	return nil
+}
+
+// autoCreateCustomVolumeBackup creates a scheduled backup of a custom volume and stores it in the configured target.
+func autoCreateCustomVolumeBackup(ctx context.Context, s *state.State, v db.StorageVolumeArgs, op *operations.Operation) error {
+	now := time.Now()
+	expiry := v.Config["backups.expiry"]
+
+	target, err := internalInstance.ParseBackupTarget(v.Config["backups.target"])
+	if err != nil {
+		return fmt.Errorf("Invalid backups.target: %w", err)
+	}
+
+	args := db.StoragePoolVolumeBackup{
+		Name:         v.Name + internalInstance.SnapshotDelimiter + scheduledBackupName(now),
+		VolumeID:     v.ID,
+		CreationDate: now,
+	}
+
+	prefix := v.PoolName + "_" + project.StorageVolume(v.ProjectName, v.Name) + "-"
+
+	// Backups written to a storage bucket are streamed straight to it.
+	if target.Bucket != "" {
+		location := &api.BackupBucket{
+			Pool:   target.Pool,
+			Bucket: target.Bucket,
+			Path:   path.Join(target.Prefix, prefix+scheduledBackupName(now)+".backup"),
+		}
+
+		err = volumeBackupUpload(s, args, v.ProjectName, v.PoolName, v.Name, location)
+		if err != nil {
+			return fmt.Errorf("Failed uploading backup %q: %w", args.Name, err)
+		}
+
+		s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupExported.Event(v.PoolName, db.StoragePoolVolumeTypeNameCustom, args.Name, v.ProjectName, op.Requestor(), logger.Ctx{"target": backupBucketTarget(location)}))
+
+		err = pruneExpiredBackupBucket(s, v.ProjectName, target, prefix, expiry)
+		if err != nil {
+			return fmt.Errorf("Failed pruning expired backups: %w", err)
+		}
+
+		return nil
+	}
+
+	// Backups kept on the server are expired by the backup pruning task, those written to a
+	// target directory are rotated below.
+	if target.Path == "" {
+		args.ExpiryDate, err = internalInstance.GetExpiry(now, expiry)
+		if err != nil {
+			return fmt.Errorf("Failed getting backups.expiry date: %w", err)
+		}
+	}
+
+	err = volumeBackupCreate(s, args, v.ProjectName, v.PoolName, v.Name)
+	if err != nil {
+		return fmt.Errorf("Failed creating backup %q: %w", args.Name, err)
+	}
+
+	s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupCreated.Event(v.PoolName, db.StoragePoolVolumeTypeNameCustom, args.Name, v.ProjectName, op.Requestor(), logger.Ctx{"type": db.StoragePoolVolumeTypeNameCustom}))
+
+	if target.Path == "" {
+		return nil
+	}
+
+	b, err := storagePoolVolumeBackupLoadByName(ctx, s, v.ProjectName, v.PoolName, args.Name)
+	if err != nil {
+		return fmt.Errorf("Failed loading storage volume backup %q: %w", args.Name, err)
+	}
+
+	source := internalUtil.VarPath("backups", "custom", v.PoolName, project.StorageVolume(v.ProjectName, b.Name()))
+
+	targetPath, err := backupExportToTarget(source, target.Path, prefix, now)
+	if err != nil {
+		_ = b.Delete()
+		return fmt.Errorf("Failed exporting backup %q: %w", args.Name, err)
+	}
+
+	err = b.Delete()
+	if err != nil {
+		return fmt.Errorf("Failed deleting storage volume backup %q: %w", args.Name, err)
+	}
+
+	s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupExported.Event(v.PoolName, db.StoragePoolVolumeTypeNameCustom, args.Name, v.ProjectName, op.Requestor(), logger.Ctx{"target": targetPath}))
+
+	err = pruneExpiredBackupTarget(target.Path, prefix, expiry)
+	if err != nil {
+		return fmt.Errorf("Failed pruning expired backups: %w", err)
+	}
+
+	return nil
+}
+
+// backupExportToTarget copies a backup file into the target directory and returns the path of the new file.
+func backupExportToTarget(source string, target string, prefix string, creationDate time.Time) (string, error) {
+	err := os.MkdirAll(target, 0700)
+	if err != nil {
+		return "", fmt.Errorf("Failed creating backup target %q: %w", target, err)
+	}
+
+	targetPath := filepath.Join(target, prefix+scheduledBackupName(creationDate)+".backup")
+
+	// Copy to a temporary file first so that partial backups never show up in the target.
+	err = internalUtil.FileCopy(source, targetPath+".tmp")
+	if err != nil {
+		_ = os.Remove(targetPath + ".tmp")
+		return "", fmt.Errorf("Failed copying backup to %q: %w", target, err)
+	}
+
+	err = os.Rename(targetPath+".tmp", targetPath)
+	if err != nil {
+		_ = os.Remove(targetPath + ".tmp")
+		return "", fmt.Errorf("Failed moving backup into place in %q: %w", target, err)
+	}
+
+	return targetPath, nil
+}
+
+// scheduledBackupExpired returns whether the file name is the one of a scheduled backup with the given
+// prefix which is past the expiry. Files not named like scheduled backups are never expired.
+func scheduledBackupExpired(name string, prefix string, expiry string, now time.Time) (bool, error) {
+	if expiry == "" || !strings.HasPrefix(name, prefix+"scheduled-") || !strings.HasSuffix(name, ".backup") {
+		return false, nil
+	}
+
+	creationDate, err := time.Parse(scheduledBackupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix+"scheduled-"), ".backup"))
+	if err != nil {
+		return false, nil
+	}
+
+	expiryDate, err := internalInstance.GetExpiry(creationDate, expiry)
+	if err != nil {
+		return false, err
+	}
+
+	return !expiryDate.After(now), nil
+}
+
+// pruneExpiredBackupTarget removes the scheduled backup files with the given prefix from the target
+// directory once they're past the expiry.
+func pruneExpiredBackupTarget(target string, prefix string, expiry string) error {
+	if expiry == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(target)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		expired, err := scheduledBackupExpired(entry.Name(), prefix, expiry, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if !expired {
+			continue
+		}
+
+		err = os.Remove(filepath.Join(target, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("Failed removing expired backup %q: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// pruneExpiredBackupBucket removes the scheduled backup files with the given prefix from the target
+// storage bucket once they're past the expiry.
+func pruneExpiredBackupBucket(s *state.State, projectName string, target *internalInstance.BackupTarget, prefix string, expiry string) error {
+	if expiry == "" {
+		return nil
+	}
+
+	location := &api.BackupBucket{
+		Pool:   target.Pool,
+		Bucket: target.Bucket,
+		Path:   path.Join(target.Prefix, prefix),
+	}
+
+	transferManager, err := backupBucketTransferManager(s, projectName, location)
+	if err != nil {
+		return err
+	}
+
+	objectNames, err := transferManager.ListFiles(location.Bucket, location.Path)
+	if err != nil {
+		return fmt.Errorf("Failed listing files in bucket %q: %w", location.Bucket, err)
+	}
+
+	for _, objectName := range objectNames {
+		// Only consider files directly under the prefix.
+		if path.Dir(objectName) != path.Dir(location.Path) {
+			continue
+		}
+
+		expired, err := scheduledBackupExpired(path.Base(objectName), prefix, expiry, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if !expired {
+			continue
+		}
+
+		err = transferManager.DeleteFile(location.Bucket, objectName)
+		if err != nil {
+			return fmt.Errorf("Failed removing expired backup %q: %w", objectName, err)
+		}
+	}
+
+	return nil
+}
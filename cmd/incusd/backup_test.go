@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledBackupExpired(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	prefix := "c1-"
+
+	tests := []struct {
+		name     string
+		file     string
+		expiry   string
+		expected bool
+	}{
+		{name: "Expired", file: prefix + scheduledBackupName(now.Add(-48*time.Hour)) + ".backup", expiry: "1d", expected: true},
+		{name: "Not expired", file: prefix + scheduledBackupName(now.Add(-time.Hour)) + ".backup", expiry: "1d", expected: false},
+		{name: "No expiry", file: prefix + scheduledBackupName(now.Add(-48*time.Hour)) + ".backup", expiry: "", expected: false},
+		{name: "Other instance", file: "c1-foo-" + scheduledBackupName(now.Add(-48*time.Hour)) + ".backup", expiry: "1d", expected: false},
+		{name: "Temporary file", file: prefix + scheduledBackupName(now.Add(-48*time.Hour)) + ".backup.tmp", expiry: "1d", expected: false},
+		{name: "Unrelated file", file: prefix + "scheduled-notadate.backup", expiry: "1d", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expired, err := scheduledBackupExpired(tt.file, prefix, tt.expiry, now)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, expired)
+		})
+	}
+}
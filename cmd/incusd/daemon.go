@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/x509"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -32,6 +33,7 @@ import (
 	"github.com/lxc/incus/v6/internal/rsync"
 	"github.com/lxc/incus/v6/internal/server/acme"
 	"github.com/lxc/incus/v6/internal/server/apparmor"
+	"github.com/lxc/incus/v6/internal/server/audit"
 	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/auth/oidc"
 	"github.com/lxc/incus/v6/internal/server/bgp"
@@ -52,10 +54,13 @@ import (
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/loki"
+	"github.com/lxc/incus/v6/internal/server/metrics"
 	"github.com/lxc/incus/v6/internal/server/network/ovn"
 	"github.com/lxc/incus/v6/internal/server/network/ovs"
 	networkZone "github.com/lxc/incus/v6/internal/server/network/zone"
 	"github.com/lxc/incus/v6/internal/server/node"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/otlp"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
@@ -68,9 +73,11 @@ import (
 	"github.com/lxc/incus/v6/internal/server/sys"
 	"github.com/lxc/incus/v6/internal/server/syslog"
 	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	"github.com/lxc/incus/v6/internal/server/ucred"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/server/warnings"
+	"github.com/lxc/incus/v6/internal/server/webhooks"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
@@ -155,6 +162,14 @@ type Daemon struct {
 	serverClustered bool
 
 	lokiClient *loki.Client
+	otlpClient *otlp.Client
+
+	// Audit log.
+	audit            *audit.Logger
+	auditSyslogClose func() error
+
+	// Webhooks.
+	webhooks *webhooks.Manager
 
 	// HTTP-01 challenge provider for ACME
 	http01Provider acme.HTTP01Provider
@@ -206,6 +221,7 @@ func newDaemon(config *DaemonConfig, os *sys.OS) *Daemon {
 		shutdownCancel: shutdownCancel,
 		shutdownDoneCh: make(chan error),
 		apiExtensions:  len(version.APIExtensions),
+		audit:          audit.NewLogger(),
 	}
 
 	d.serverCert = func() *localtls.CertInfo { return d.serverCertInt }
@@ -348,7 +364,7 @@ func allowPermission(objectType auth.ObjectType, entitlement auth.Entitlement, m
 				projectName = project.NetworkZoneProjectFromRecord(p)
 			} else if slices.Contains([]auth.ObjectType{auth.ObjectTypeImage, auth.ObjectTypeImageAlias}, objectType) {
 				projectName = project.ImageProjectFromRecord(p)
-			} else if slices.Contains([]auth.ObjectType{auth.ObjectTypeNetwork, auth.ObjectTypeNetworkACL}, objectType) {
+			} else if slices.Contains([]auth.ObjectType{auth.ObjectTypeNetwork, auth.ObjectTypeNetworkACL, auth.ObjectTypeNetworkAddressSet}, objectType) {
 				projectName = project.NetworkProjectFromRecord(p)
 			}
 
@@ -416,7 +432,7 @@ func allowPermission(objectType auth.ObjectType, entitlement auth.Entitlement, m
 
 // Convenience function around Authenticate.
 func (d *Daemon) checkTrustedClient(r *http.Request) error {
-	trusted, _, _, err := d.Authenticate(nil, r)
+	trusted, _, _, _, err := d.Authenticate(nil, r)
 	if !trusted || err != nil {
 		if err != nil {
 			return err
@@ -474,12 +490,12 @@ func (d *Daemon) getTrustedCertificates() (map[certificate.Type]map[string]x509.
 // will validate the TLS certificate.
 //
 // This does not perform authorization, only validates authentication.
-// Returns whether trusted or not, the username (or certificate fingerprint) of the trusted client, and the type of
-// client that has been authenticated (cluster, unix, or tls).
-func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, string, string, error) {
+// Returns whether trusted or not, the username (or certificate fingerprint) of the trusted client, the type of
+// client that has been authenticated (cluster, unix, tls or oidc) and the identity provider groups of OIDC users.
+func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, string, string, []string, error) {
 	trustedCerts, err := d.getTrustedCertificates()
 	if err != nil {
-		return false, "", "", err
+		return false, "", "", nil, err
 	}
 
 	// Allow internal cluster traffic by checking against the trusted certfificates.
@@ -487,7 +503,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		for _, i := range r.TLS.PeerCertificates {
 			trusted, fingerprint := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeServer], d.endpoints.NetworkCert(), false)
 			if trusted {
-				return true, fingerprint, "cluster", nil
+				return true, fingerprint, "cluster", nil, nil
 			}
 		}
 	}
@@ -497,38 +513,38 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		if w != nil {
 			cred, err := ucred.GetCredFromContext(r.Context())
 			if err != nil {
-				return false, "", "", err
+				return false, "", "", nil, err
 			}
 
 			u, err := user.LookupId(fmt.Sprintf("%d", cred.Uid))
 			if err != nil {
-				return true, fmt.Sprintf("uid=%d", cred.Uid), "unix", nil
+				return true, fmt.Sprintf("uid=%d", cred.Uid), "unix", nil, nil
 			}
 
-			return true, u.Username, "unix", nil
+			return true, u.Username, "unix", nil, nil
 		}
 
-		return true, "", "unix", nil
+		return true, "", "unix", nil, nil
 	}
 
 	// DevIncus unix socket credentials on main API.
 	if r.RemoteAddr == "@dev_incus" {
-		return false, "", "", fmt.Errorf("Main API query can't come from /dev/incus socket")
+		return false, "", "", nil, fmt.Errorf("Main API query can't come from /dev/incus socket")
 	}
 
 	// Cluster notification with wrong certificate.
 	if isClusterNotification(r) {
-		return false, "", "", fmt.Errorf("Cluster notification isn't using trusted server certificate")
+		return false, "", "", nil, fmt.Errorf("Cluster notification isn't using trusted server certificate")
 	}
 
 	// Cluster internal client with wrong certificate.
 	if isClusterInternal(r) {
-		return false, "", "", fmt.Errorf("Cluster internal client isn't using trusted server certificate")
+		return false, "", "", nil, fmt.Errorf("Cluster internal client isn't using trusted server certificate")
 	}
 
 	// Bad query, no TLS found.
 	if r.TLS == nil {
-		return false, "", "", fmt.Errorf("Bad/missing TLS on network query")
+		return false, "", "", nil, fmt.Errorf("Bad/missing TLS on network query")
 	}
 
 	// Load the certificates.
@@ -539,18 +555,55 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 	if jwtOk {
 		trusted, username := localUtil.CheckTrustState(*cert, trustedCerts[certificate.TypeClient], d.endpoints.NetworkCert(), trustCACertificates)
 		if trusted {
-			return true, username, api.AuthenticationMethodTLS, nil
+			return true, username, api.AuthenticationMethodTLS, nil, nil
 		}
 	}
 
+	// Check for a scoped API token.
+	tokenID, tokenSecret, ok := auth.ParseAPIToken(r)
+	if ok {
+		var secretHash string
+		var token *api.AuthToken
+
+		err := d.db.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			secretHash, err = tx.GetAuthTokenSecretHash(ctx, tokenID)
+			if err != nil {
+				return err
+			}
+
+			_, token, err = tx.GetAuthToken(ctx, tokenID)
+
+			return err
+		})
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				return false, "", "", nil, errors.New("Invalid API token")
+			}
+
+			return false, "", "", nil, err
+		}
+
+		if !auth.CheckAPITokenSecret(tokenSecret, secretHash) {
+			return false, "", "", nil, errors.New("Invalid API token")
+		}
+
+		if time.Now().After(token.ExpiresAt) {
+			return false, "", "", nil, errors.New("API token has expired")
+		}
+
+		return true, tokenID, api.AuthenticationMethodToken, nil, nil
+	}
+
 	// Check for JWT token signed by an OpenID Connect provider.
 	if d.oidcVerifier != nil && d.oidcVerifier.IsRequest(r) {
-		userName, err := d.oidcVerifier.Auth(d.shutdownCtx, w, r)
+		userName, groups, err := d.oidcVerifier.Auth(d.shutdownCtx, w, r)
 		if err != nil {
-			return false, "", "", err
+			return false, "", "", nil, err
 		}
 
-		return true, userName, api.AuthenticationMethodOIDC, nil
+		return true, userName, api.AuthenticationMethodOIDC, groups, nil
 	}
 
 	// Validate metrics TLS certificates.
@@ -558,7 +611,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		for _, i := range r.TLS.PeerCertificates {
 			trusted, username := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeMetrics], d.endpoints.NetworkCert(), trustCACertificates)
 			if trusted {
-				return true, username, api.AuthenticationMethodTLS, nil
+				return true, username, api.AuthenticationMethodTLS, nil, nil
 			}
 		}
 	}
@@ -567,12 +620,34 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 	for _, i := range r.TLS.PeerCertificates {
 		trusted, username := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeClient], d.endpoints.NetworkCert(), trustCACertificates)
 		if trusted {
-			return true, username, api.AuthenticationMethodTLS, nil
+			return true, username, api.AuthenticationMethodTLS, nil, nil
 		}
 	}
 
 	// Reject unauthorized.
-	return false, "", "", nil
+	return false, "", "", nil, nil
+}
+
+// authToken returns the scoped API token with the given identifier.
+func (d *Daemon) authToken(ctx context.Context, id string) (*api.AuthToken, error) {
+	if d.db == nil || d.db.Cluster == nil {
+		return nil, errors.New("The database isn't available yet")
+	}
+
+	var token *api.AuthToken
+
+	err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		_, token, err = tx.GetAuthToken(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
 }
 
 // State creates a new State instance linked to our internal db and os.
@@ -646,7 +721,46 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 		}
 
 		// Authentication
-		trusted, username, protocol, err := d.Authenticate(w, r)
+		trusted, username, protocol, identityProviderGroups, err := d.Authenticate(w, r)
+
+		// Audit mutating requests, leaving out internal calls and requests forwarded by other cluster members.
+		if d.audit.Enabled() && r.Method != http.MethodGet && r.Method != http.MethodHead && version != "internal" && protocol != "cluster" {
+			record := audit.Record{
+				Timestamp: time.Now().UTC(),
+				Username:  username,
+				Protocol:  protocol,
+				Address:   r.RemoteAddr,
+				Method:    r.Method,
+				URL:       r.URL.RequestURI(),
+				Project:   request.ProjectParam(r),
+			}
+
+			if d.serverClustered {
+				record.Location = d.serverName
+			}
+
+			var auditReq *audit.Request
+			auditReq, w = audit.NewRequest(w, r)
+
+			defer func() {
+				record.BodyDigest = auditReq.Digest()
+				record.StatusCode = auditReq.StatusCode()
+				record.Operation = auditReq.Operation()
+
+				// Requests returning an operation are recorded once the operation completes.
+				if record.Operation != "" {
+					go d.auditOperation(record)
+					return
+				}
+
+				record.Outcome = audit.OutcomeFromStatusCode(record.StatusCode)
+
+				err := d.audit.Log(record)
+				if err != nil {
+					logger.Warn("Failed recording audit record", logger.Ctx{"url": record.URL, "err": err})
+				}
+			}()
+		}
 		if err != nil {
 			_, ok := err.(*oidc.AuthError)
 			if ok {
@@ -684,6 +798,7 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			// Add authentication/authorization context data.
 			ctx := context.WithValue(r.Context(), request.CtxUsername, username)
 			ctx = context.WithValue(ctx, request.CtxProtocol, protocol)
+			ctx = context.WithValue(ctx, request.CtxIdentityProviderGroups, identityProviderGroups)
 
 			// Add forwarded requestor data.
 			if protocol == "cluster" {
@@ -691,6 +806,15 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 				ctx = context.WithValue(ctx, request.CtxForwardedAddress, r.Header.Get(request.HeaderForwardedAddress))
 				ctx = context.WithValue(ctx, request.CtxForwardedUsername, r.Header.Get(request.HeaderForwardedUsername))
 				ctx = context.WithValue(ctx, request.CtxForwardedProtocol, r.Header.Get(request.HeaderForwardedProtocol))
+
+				forwardedGroups := r.Header.Get(request.HeaderForwardedIdentityProviderGroups)
+				if forwardedGroups != "" {
+					var groups []string
+					err := json.Unmarshal([]byte(forwardedGroups), &groups)
+					if err == nil {
+						ctx = context.WithValue(ctx, request.CtxForwardedIdentityProviderGroups, groups)
+					}
+				}
 			}
 
 			r = r.WithContext(ctx)
@@ -778,6 +902,15 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			return action.Handler(d, r)
 		}
 
+		// Record the request as a span, continuing the trace of the caller if any.
+		ctx, span := tracing.Start(tracing.Extract(r.Context(), r.Header), fmt.Sprintf("%s %s", r.Method, uri), tracing.SpanKindServer, map[string]string{
+			"http.request.method": r.Method,
+			"url.path":            r.URL.Path,
+			"incus.protocol":      protocol,
+		})
+
+		r = r.WithContext(ctx)
+
 		switch r.Method {
 		case "GET":
 			resp = handleRequest(c.Get)
@@ -808,6 +941,13 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 				logger.Error("Failed writing error for HTTP response", logger.Ctx{"url": uri, "err": err, "writeErr": writeErr})
 			}
 		}
+
+		span.SetAttribute("http.response.status_code", fmt.Sprintf("%d", resp.Code()))
+		if resp.Code() >= http.StatusInternalServerError {
+			span.Finish(errors.New(http.StatusText(resp.Code())))
+		} else {
+			span.Finish(nil)
+		}
 	})
 
 	// If the endpoint has a canonical name then record it so it can be used to build URLS
@@ -876,6 +1016,7 @@ func (d *Daemon) Init() error {
 func (d *Daemon) setupLoki(URL string, cert string, key string, caCert string, instanceName string, logLevel string, labels []string, types []string) error {
 	// Stop any existing loki client.
 	if d.lokiClient != nil {
+		d.audit.RemoveHandler("loki")
 		d.lokiClient.Stop()
 	}
 
@@ -909,8 +1050,74 @@ func (d *Daemon) setupLoki(URL string, cert string, key string, caCert string, i
 	// Start a new client.
 	d.lokiClient = loki.NewClient(d.shutdownCtx, u, cert, key, caCert, instanceName, location, logLevel, labels, types)
 
-	// Attach the new client to the log handler.
+	// Attach the new client to the log handler and the audit logger.
 	d.internalListener.AddHandler("loki", d.lokiClient.HandleEvent)
+	d.audit.AddHandler("loki", d.lokiClient.HandleAuditRecord)
+
+	return nil
+}
+
+func (d *Daemon) setupOTLP(URL string, caCert string, headers map[string]string, instanceName string, logLevel string, types []string, metricsInterval time.Duration, traces bool) error {
+	// Stop any existing OpenTelemetry client.
+	if d.otlpClient != nil {
+		d.internalListener.RemoveHandler("otlp")
+		tracing.SetExporter(nil)
+		d.otlpClient.Stop()
+		d.otlpClient = nil
+	}
+
+	// Check basic requirements for starting a new client.
+	if URL == "" {
+		return nil
+	}
+
+	// Validate the URL.
+	u, err := url.Parse(URL)
+	if err != nil {
+		return err
+	}
+
+	// Handle standalone systems.
+	location := d.serverName
+	if !d.serverClustered {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return err
+		}
+
+		location = hostname
+	}
+
+	if instanceName == "" {
+		instanceName = location
+	}
+
+	// Start a new client.
+	d.otlpClient, err = otlp.NewClient(d.shutdownCtx, u, caCert, headers, instanceName, location, logLevel, types)
+	if err != nil {
+		return err
+	}
+
+	// Attach the new client to the log handler.
+	if len(types) > 0 {
+		d.internalListener.AddHandler("otlp", d.otlpClient.HandleEvent)
+	}
+
+	// Record the spans of the API requests and operations.
+	if traces {
+		tracing.SetExporter(d.otlpClient)
+	}
+
+	// Periodically export the same metrics as the metrics endpoint.
+	d.otlpClient.RunMetrics(metricsInterval, func(ctx context.Context) (*metrics.MetricSet, error) {
+		select {
+		case <-d.waitReady.Done():
+		default:
+			return nil, errors.New("The daemon isn't ready yet")
+		}
+
+		return gatherMetrics(ctx, d.State(), "")
+	})
 
 	return nil
 }
@@ -921,7 +1128,7 @@ func (d *Daemon) init() error {
 	var dbWarnings []dbCluster.Warning
 
 	// Set default authorizer.
-	d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts)
+	d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts, auth.WithAuthTokenFunc(d.authToken))
 	if err != nil {
 		return err
 	}
@@ -961,6 +1168,14 @@ func (d *Daemon) init() error {
 		return err
 	}
 
+	// Load the event history.
+	eventHistory, err := events.NewHistory(internalUtil.VarPath("events.log"), eventHistorySize)
+	if err != nil {
+		return err
+	}
+
+	d.events.SetHistory(eventHistory)
+
 	// Initialize apparmor.
 	if d.os.AppArmorAvailable {
 		err := apparmor.Init()
@@ -1473,11 +1688,14 @@ func (d *Daemon) init() error {
 
 	d.gateway.HeartbeatOfflineThreshold = d.globalConfig.OfflineThreshold()
 	lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes := d.globalConfig.LokiServer()
-	oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim := d.globalConfig.OIDCServer()
+	otlpURL, otlpCACert, otlpHeaders, otlpInstance, otlpLoglevel, otlpTypes, otlpMetricsInterval, otlpTraces := d.globalConfig.OTLPServer()
+	oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim := d.globalConfig.OIDCServer()
 	syslogSocketEnabled := d.localConfig.SyslogSocket()
+	auditEnabled, auditMaxSize, auditMaxFiles, auditSyslog := d.globalConfig.Audit()
 	openfgaAPIURL, openfgaAPIToken, openfgaStoreID := d.globalConfig.OpenFGA()
 	instancePlacementScriptlet := d.globalConfig.InstancesPlacementScriptlet()
 	authorizationScriptlet := d.globalConfig.AuthorizationScriptlet()
+	authorizationRBAC := d.globalConfig.AuthorizationRBAC()
 
 	d.endpoints.NetworkUpdateTrustedProxy(d.globalConfig.HTTPSTrustedProxy())
 	d.globalConfigMu.Unlock()
@@ -1490,6 +1708,26 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup OpenTelemetry exporter.
+	if otlpURL != "" {
+		err = d.setupOTLP(otlpURL, otlpCACert, otlpHeaders, otlpInstance, otlpLoglevel, otlpTypes, otlpMetricsInterval, otlpTraces)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Setup the audit log.
+	err = d.setupAudit(auditEnabled, auditMaxSize, auditMaxFiles, auditSyslog)
+	if err != nil {
+		return err
+	}
+
+	// Setup the webhooks.
+	err = d.setupWebhooks()
+	if err != nil {
+		return err
+	}
+
 	// Setup syslog listener.
 	if syslogSocketEnabled {
 		err = d.setupSyslogSocket(true)
@@ -1500,7 +1738,7 @@ func (d *Daemon) init() error {
 
 	// Setup OIDC authentication.
 	if oidcIssuer != "" && oidcClientID != "" {
-		d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim)
+		d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim)
 		if err != nil {
 			return err
 		}
@@ -1522,6 +1760,14 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup the built-in role-based access control.
+	if authorizationRBAC {
+		err = d.setupAuthorizationRBAC(true)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Setup BGP listener.
 	d.bgp = bgp.NewServer()
 	if bgpAddress != "" && bgpASN != 0 && bgpRouterID != "" {
@@ -1722,12 +1968,18 @@ func (d *Daemon) init() error {
 		// Remove expired backups (hourly)
 		d.tasks.Add(pruneExpiredBackupsTask(d))
 
+		// Take backups of instances and custom volumes (minutely check of configurable cron expression)
+		d.tasks.Add(autoCreateBackupsTask(d))
+
 		// Prune expired instance snapshots and take snapshot of instances (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateInstanceSnapshotsTask(d))
 
 		// Prune expired custom volume snapshots and take snapshots of custom volumes (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d))
 
+		// Start scrubbing storage pools (minutely check of configurable cron expression)
+		d.tasks.Add(autoScrubStoragePoolsTask(d))
+
 		// Remove resolved warnings (daily)
 		d.tasks.Add(pruneResolvedWarningsTask(d))
 
@@ -1991,7 +2243,7 @@ func (d *Daemon) setupOpenFGA(apiURL string, apiToken string, storeID string) er
 
 	if apiURL == "" || apiToken == "" || storeID == "" {
 		// Reset to default authorizer.
-		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts)
+		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts, auth.WithAuthTokenFunc(d.authToken))
 		if err != nil {
 			return err
 		}
@@ -2010,7 +2262,7 @@ func (d *Daemon) setupOpenFGA(apiURL string, apiToken string, storeID string) er
 
 	revert.Add(func() {
 		// Reset to default authorizer.
-		d.authorizer, _ = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts)
+		d.authorizer, _ = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts, auth.WithAuthTokenFunc(d.authToken))
 	})
 
 	// Build the list of resources to update the model.
@@ -2153,6 +2405,21 @@ func (d *Daemon) setupOpenFGA(apiURL string, apiToken string, storeID string) er
 				return err
 			}
 
+			err = query.Scan(ctx, tx.Tx(), "SELECT networks_address_sets.name, projects.name FROM networks_address_sets JOIN projects ON projects.id=networks_address_sets.project_id", func(scan func(dest ...any) error) error {
+				var networkAddressSetName string
+				var projectName string
+				err := scan(&networkAddressSetName, &projectName)
+				if err != nil {
+					return err
+				}
+
+				resources.NetworkAddressSetObjects = append(resources.NetworkAddressSetObjects, auth.ObjectNetworkAddressSet(projectName, networkAddressSetName))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
 			err = query.Scan(ctx, tx.Tx(), "SELECT networks_zones.name, projects.name FROM networks_zones JOIN projects ON projects.id=networks_zones.project_id", func(scan func(dest ...any) error) error {
 				var networkZoneName string
 				var projectName string
@@ -2242,7 +2509,7 @@ func (d *Daemon) setupOpenFGA(apiURL string, apiToken string, storeID string) er
 		return &resources, nil
 	}
 
-	openfgaAuthorizer, err := auth.LoadAuthorizer(d.shutdownCtx, auth.DriverOpenFGA, logger.Log, d.clientCerts, auth.WithConfig(config), auth.WithResourcesFunc(refreshResources))
+	openfgaAuthorizer, err := auth.LoadAuthorizer(d.shutdownCtx, auth.DriverOpenFGA, logger.Log, d.clientCerts, auth.WithAuthTokenFunc(d.authToken), auth.WithConfig(config), auth.WithResourcesFunc(refreshResources))
 	if err != nil {
 		return err
 	}
@@ -2262,7 +2529,7 @@ func (d *Daemon) setupAuthorizationScriptlet(scriptlet string) error {
 
 	if scriptlet == "" {
 		// Reset to default authorizer.
-		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts)
+		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts, auth.WithAuthTokenFunc(d.authToken))
 		if err != nil {
 			return err
 		}
@@ -2273,7 +2540,7 @@ func (d *Daemon) setupAuthorizationScriptlet(scriptlet string) error {
 	// Fail if not using the default tls or scriptlet authorizer.
 	switch d.authorizer.(type) {
 	case *auth.TLS, *auth.Scriptlet:
-		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverScriptlet, logger.Log, d.clientCerts)
+		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverScriptlet, logger.Log, d.clientCerts, auth.WithAuthTokenFunc(d.authToken))
 		if err != nil {
 			return err
 		}
@@ -2285,6 +2552,110 @@ func (d *Daemon) setupAuthorizationScriptlet(scriptlet string) error {
 	return nil
 }
 
+// Setup role-based access control.
+func (d *Daemon) setupAuthorizationRBAC(enabled bool) error {
+	var err error
+
+	if !enabled {
+		// Reset to default authorizer.
+		_, ok := d.authorizer.(*auth.RBAC)
+		if ok {
+			d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts, auth.WithAuthTokenFunc(d.authToken))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Load the identities and their permissions.
+	identities := func(ctx context.Context, authMethod string, identifier string) ([]auth.Identity, error) {
+		var identities []auth.Identity
+
+		err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var dbIdentities []api.AuthIdentity
+			if authMethod == "" && identifier == "" {
+				var err error
+				dbIdentities, err = tx.GetAuthIdentities(ctx)
+				if err != nil {
+					return err
+				}
+			} else {
+				_, dbIdentity, err := tx.GetAuthIdentity(ctx, authMethod, identifier)
+				if err != nil {
+					if api.StatusErrorCheck(err, http.StatusNotFound) {
+						return nil
+					}
+
+					return err
+				}
+
+				dbIdentities = []api.AuthIdentity{*dbIdentity}
+			}
+
+			for _, dbIdentity := range dbIdentities {
+				permissions, err := tx.GetAuthIdentityPermissions(ctx, dbIdentity.AuthMethod, dbIdentity.Identifier)
+				if err != nil {
+					return err
+				}
+
+				identities = append(identities, auth.Identity{
+					AuthMethod:  dbIdentity.AuthMethod,
+					Identifier:  dbIdentity.Identifier,
+					Permissions: permissions,
+				})
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return identities, nil
+	}
+
+	// Keep the permissions in sync with renamed and deleted objects.
+	rewritePermissions := func(ctx context.Context, rewrite func(object string) (string, bool)) error {
+		return d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.RewriteAuthPermissions(ctx, rewrite)
+		})
+	}
+
+	// Load the permissions granted through identity provider groups.
+	identityProviderGroups := func(ctx context.Context, identityProviderGroups []string) ([]api.AuthPermission, error) {
+		var permissions []api.AuthPermission
+
+		err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			permissions, err = tx.GetIdentityProviderGroupsPermissions(ctx, identityProviderGroups)
+
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return permissions, nil
+	}
+
+	// Fail if not using the default tls or rbac authorizer.
+	switch d.authorizer.(type) {
+	case *auth.TLS, *auth.RBAC:
+		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverRBAC, logger.Log, d.clientCerts, auth.WithAuthTokenFunc(d.authToken), auth.WithIdentitiesFunc(identities), auth.WithPermissionsRewriteFunc(rewritePermissions), auth.WithIdentityProviderGroupsFunc(identityProviderGroups))
+		if err != nil {
+			return err
+		}
+
+	default:
+		return errors.New("Attempting to setup role-based access control while another authorizer is already set")
+	}
+
+	return nil
+}
+
 // Syslog listener.
 func (d *Daemon) setupSyslogSocket(enable bool) error {
 	// Always cancel the context to ensure that no goroutines leak.
@@ -2311,6 +2682,138 @@ func (d *Daemon) setupSyslogSocket(enable bool) error {
 	return nil
 }
 
+// setupAudit configures the audit log of mutating API requests.
+func (d *Daemon) setupAudit(enabled bool, maxSize int64, maxFiles int64, useSyslog bool) error {
+	// Always close the syslog connection to ensure it doesn't leak.
+	if d.auditSyslogClose != nil {
+		d.audit.RemoveHandler("syslog")
+		_ = d.auditSyslogClose()
+		d.auditSyslogClose = nil
+	}
+
+	if !enabled {
+		return d.audit.Disable()
+	}
+
+	err := d.audit.Configure(internalUtil.LogPath("audit.log"), maxSize, int(maxFiles))
+	if err != nil {
+		return err
+	}
+
+	if useSyslog {
+		handler, closeFunc, err := audit.NewSyslogHandler()
+		if err != nil {
+			return err
+		}
+
+		d.audit.AddHandler("syslog", handler)
+		d.auditSyslogClose = closeFunc
+	}
+
+	return nil
+}
+
+// auditOperation waits for the operation of an audited request to complete and records it with the final outcome.
+func (d *Daemon) auditOperation(record audit.Record) {
+	status, err := d.waitOperation(record.Operation)
+	if err != nil {
+		logger.Warn("Failed waiting for audited operation", logger.Ctx{"url": record.URL, "operation": record.Operation, "err": err})
+		record.Outcome = audit.OutcomeUnknown
+	} else {
+		record.Outcome = audit.OutcomeFromOperationStatus(status)
+	}
+
+	err = d.audit.Log(record)
+	if err != nil {
+		logger.Warn("Failed recording audit record", logger.Ctx{"url": record.URL, "err": err})
+	}
+}
+
+// waitOperation waits for an operation running on this server or on another cluster member and returns its final status.
+func (d *Daemon) waitOperation(id string) (api.StatusCode, error) {
+	op, err := operations.OperationGetInternal(id)
+	if err == nil {
+		_ = op.Wait(d.shutdownCtx)
+		if d.shutdownCtx.Err() != nil {
+			return -1, d.shutdownCtx.Err()
+		}
+
+		return op.Status(), nil
+	}
+
+	var address string
+	err = d.db.Cluster.Transaction(d.shutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		ops, err := dbCluster.GetOperations(ctx, tx.Tx(), dbCluster.OperationFilter{UUID: &id})
+		if err != nil {
+			return err
+		}
+
+		if len(ops) != 1 {
+			return api.StatusErrorf(http.StatusNotFound, "Operation not found")
+		}
+
+		address = ops[0].NodeAddress
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	client, err := cluster.Connect(address, d.endpoints.NetworkCert(), d.serverCert(), nil, true)
+	if err != nil {
+		return -1, err
+	}
+
+	apiOp, _, err := client.GetOperationWait(id, -1)
+	if err != nil {
+		return -1, err
+	}
+
+	return apiOp.StatusCode, nil
+}
+
+// setupWebhooks starts delivering the events to the configured webhooks.
+func (d *Daemon) setupWebhooks() error {
+	proxy := func(req *http.Request) (*url.URL, error) {
+		return d.proxy(req)
+	}
+
+	d.webhooks = webhooks.NewManager(d.shutdownCtx, proxy, d.webhookDeadLetter)
+	d.internalListener.AddHandler("webhooks", d.webhooks.HandleEvent)
+
+	return d.reloadWebhooks(d.shutdownCtx)
+}
+
+// reloadWebhooks loads the webhooks from the database into the webhook manager.
+func (d *Daemon) reloadWebhooks(ctx context.Context) error {
+	var hooks []api.Webhook
+
+	err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		hooks, err = tx.GetWebhooks(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading webhooks: %w", err)
+	}
+
+	d.webhooks.Load(hooks)
+
+	return nil
+}
+
+// webhookDeadLetter records the events which couldn't be delivered to a webhook as a warning.
+func (d *Daemon) webhookDeadLetter(name string, event api.Event, deliveryErr error) {
+	err := d.db.Cluster.Transaction(d.shutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertWarningLocalNode(ctx, "", -1, -1, warningtype.WebhookDeliveryFailure, fmt.Sprintf("Webhook %q: %v", name, deliveryErr))
+	})
+	if err != nil {
+		logger.Warn("Failed to create warning", logger.Ctx{"err": err})
+	}
+}
+
 // Create a database connection and perform any updates needed.
 func initializeDbObject(d *Daemon) error {
 	logger.Info("Initializing local database")
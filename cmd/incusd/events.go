@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/lxc/incus/v6/internal/server/auth"
@@ -23,6 +24,9 @@ import (
 var eventTypes = []string{api.EventTypeLogging, api.EventTypeOperation, api.EventTypeLifecycle, api.EventTypeNetworkACL}
 var privilegedEventTypes = []string{api.EventTypeLogging}
 
+// eventHistorySize is the number of events kept in the local event history for replay.
+const eventHistorySize = 10000
+
 var eventsCmd = APIEndpoint{
 	Path: "events",
 
@@ -104,6 +108,22 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 		return api.StatusErrorf(http.StatusForbidden, "Forbidden")
 	}
 
+	// Check that the events following the requested cursor can still be replayed.
+	var since *uint64
+	if request.QueryParam(r, "since") != "" {
+		id, err := strconv.ParseUint(request.QueryParam(r, "since"), 10, 64)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid event identifier %q", request.QueryParam(r, "since"))
+		}
+
+		err = s.Events.CheckHistory(id)
+		if err != nil {
+			return err
+		}
+
+		since = &id
+	}
+
 	l := logger.AddContext(logger.Ctx{"remote": r.RemoteAddr})
 
 	var excludeLocations []string
@@ -160,7 +180,13 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 	defer func() { _ = conn.Close() }() // Ensure listener below ends when this function ends.
 
 	listenerConnection := events.NewWebsocketListenerConnection(conn)
-	listener, err := s.Events.AddListener(projectName, allProjects, projectPermissionFunc, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
+	var listener *events.Listener
+	if since != nil {
+		listener, err = s.Events.AddListenerSince(*since, projectName, allProjects, projectPermissionFunc, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
+	} else {
+		listener, err = s.Events.AddListener(projectName, allProjects, projectPermissionFunc, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
+	}
+
 	if err != nil {
 		l.Warn("Failed to add event listener", logger.Ctx{"err": err})
 		return nil
@@ -195,6 +221,11 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 //	    name: all-projects
 //	    description: Retrieve instances from all projects
 //	    type: boolean
+//	  - in: query
+//	    name: since
+//	    description: Replay the recorded events following this event ID first
+//	    type: integer
+//	    example: 1234
 //	responses:
 //	  "200":
 //	    description: Websocket message (JSON)
@@ -2775,7 +2775,7 @@ func imageDelete(d *Daemon, r *http.Request) response.Response {
 			}
 
 			// Notify the other nodes about the removed image so they can remove it from disk too.
-			notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAll)
+			notifier, err := cluster.NewNotifierWithContext(r.Context(), s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAll)
 			if err != nil {
 				return err
 			}
@@ -4056,6 +4056,11 @@ func imageAliasPost(d *Daemon, r *http.Request) response.Response {
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: format
+//	    description: Export format (incus or oci)
+//	    type: string
+//	    example: oci
 //	responses:
 //	  "200":
 //	    description: Raw image data
@@ -4138,6 +4143,11 @@ func imageExport(d *Daemon, r *http.Request) response.Response {
 		return response.ForwardedResponse(client, r)
 	}
 
+	// Convert the image if requested.
+	if r.FormValue("format") == "oci" {
+		return imageExportOCI(s, r, projectName, imgInfo)
+	}
+
 	// Set image type header.
 	headers := map[string]string{}
 
@@ -4232,9 +4242,11 @@ func imageExportPost(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	var imgInfo *api.Image
+
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Check if the image exists
-		_, _, err = tx.GetImage(ctx, fingerprint, dbCluster.ImageFilter{Project: &projectName})
+		_, imgInfo, err = tx.GetImage(ctx, fingerprint, dbCluster.ImageFilter{Project: &projectName})
 
 		return err
 	})
@@ -4249,6 +4261,11 @@ func imageExportPost(d *Daemon, r *http.Request) response.Response {
 		response.SmartError(err)
 	}
 
+	// Push to an OCI registry.
+	if req.Protocol == "oci" {
+		return imageExportPostOCI(s, r, projectName, imgInfo, req)
+	}
+
 	// Connect to the target and push the image
 	args := &incus.ConnectionArgs{
 		TLSServerCert: req.Certificate,
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/archive"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/oci"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// imageExportOCI returns the image converted to an OCI image layout tarball.
+func imageExportOCI(s *state.State, r *http.Request, projectName string, imgInfo *api.Image) response.Response {
+	if imgInfo.Type != instancetype.Container.String() {
+		return response.BadRequest(errors.New("Only container images can be exported as OCI images"))
+	}
+
+	refName := r.FormValue("name")
+	if refName == "" {
+		refName = "latest"
+	}
+
+	buildDir, err := os.MkdirTemp(internalUtil.VarPath("images"), "incus_build_oci_")
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	img, err := imageBuildOCI(r.Context(), s, imgInfo, buildDir)
+	if err != nil {
+		_ = os.RemoveAll(buildDir)
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(projectName, lifecycle.ImageRetrieved.Event(imgInfo.Fingerprint, projectName, requestor, logger.Ctx{"format": "oci"}))
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		defer func() { _ = os.RemoveAll(buildDir) }()
+
+		w.Header().Set("Content-Type", "application/x-tar")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.oci.tar", imgInfo.Fingerprint))
+		w.Header().Set("X-Incus-Type", "oci")
+		w.WriteHeader(http.StatusOK)
+
+		return img.WriteLayout(w, refName)
+	})
+}
+
+// imageExportPostOCI pushes the image to an OCI registry under each of the requested aliases.
+func imageExportPostOCI(s *state.State, r *http.Request, projectName string, imgInfo *api.Image, req api.ImageExportPost) response.Response {
+	if imgInfo.Type != instancetype.Container.String() {
+		return response.BadRequest(errors.New("Only container images can be pushed to OCI registries"))
+	}
+
+	if len(req.Aliases) == 0 {
+		return response.BadRequest(errors.New("At least one alias is required to push to an OCI registry"))
+	}
+
+	for _, alias := range req.Aliases {
+		_, _, err := oci.ParseReference(alias.Name)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid image name %q: %w", alias.Name, err))
+		}
+	}
+
+	httpClient, err := localUtil.HTTPClient(req.Certificate, s.Proxy)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	registry, err := oci.NewClient(httpClient, req.Target, version.UserAgent)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Don't leak registry credentials in events.
+	target := req.Target
+	targetURL, err := url.Parse(req.Target)
+	if err == nil {
+		target = targetURL.Redacted()
+	}
+
+	run := func(op *operations.Operation) error {
+		buildDir, err := os.MkdirTemp(internalUtil.VarPath("images"), "incus_build_oci_")
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = os.RemoveAll(buildDir) }()
+
+		img, err := imageBuildOCI(s.ShutdownCtx, s, imgInfo, buildDir)
+		if err != nil {
+			return err
+		}
+
+		for _, alias := range req.Aliases {
+			err = registry.Push(s.ShutdownCtx, alias.Name, img)
+			if err != nil {
+				return fmt.Errorf("Failed to push %q: %w", alias.Name, err)
+			}
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.ImageRetrieved.Event(imgInfo.Fingerprint, projectName, op.Requestor(), logger.Ctx{"target": target, "format": "oci"}))
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.ImageDownload, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// imageBuildOCI converts a container image into an OCI image whose blobs are stored in dir.
+//
+// Images converted from an OCI image keep the layers of that image, the changes made
+// to the root filesystem being added as a new layer. Other images get their root
+// filesystem as a single layer. The entry point and environment come from the OCI
+// configuration of the image if it has one.
+func imageBuildOCI(ctx context.Context, s *state.State, imgInfo *api.Image, dir string) (*oci.LocalImage, error) {
+	if imgInfo.Type != instancetype.Container.String() {
+		return nil, errors.New("Only container images can be converted to OCI images")
+	}
+
+	platforms, err := oci.ArchitecturePlatforms(imgInfo.Architecture)
+	if err != nil {
+		return nil, err
+	}
+
+	imagePath := internalUtil.VarPath("images", imgInfo.Fingerprint)
+	rootfsPath := imagePath + ".rootfs"
+	split := util.PathExists(rootfsPath)
+
+	img := oci.NewLocalImage(dir)
+
+	var spec *ociSpecs.Spec
+	var source *oci.Source
+	var differ *oci.Differ
+	var layer *oci.LayerWriter
+
+	defer func() {
+		if layer != nil {
+			_ = layer.Close()
+		}
+	}()
+
+	// startLayer adds the layers of the source image, if any, and starts the layer holding the root filesystem.
+	// The metadata files always come before the root filesystem in the image tarballs.
+	startLayer := func() error {
+		if layer != nil {
+			return nil
+		}
+
+		if source != nil {
+			differ, err = imageAddSourceLayers(ctx, s, img, source)
+			if err != nil {
+				logger.Warn("Failed retrieving the layers of the source OCI image, using a single layer", logger.Ctx{"fingerprint": imgInfo.Fingerprint, "registry": source.Registry, "repository": source.Repository, "err": err})
+
+				img = oci.NewLocalImage(dir)
+				differ = nil
+			}
+		}
+
+		layer, err = img.AddLayer()
+		return err
+	}
+
+	// addEntry adds an entry of the root filesystem to the layer, unless it's unchanged from the source image.
+	addEntry := func(hdr *tar.Header, r io.Reader, name string) error {
+		err := startLayer()
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = name
+		if hdr.Typeflag == tar.TypeLink {
+			hdr.Linkname = strings.TrimPrefix(strings.TrimPrefix(hdr.Linkname, "./"), "rootfs/")
+		}
+
+		if differ != nil && !differ.Changed(hdr) {
+			return nil
+		}
+
+		err = layer.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(layer, r)
+		return err
+	}
+
+	// Go through the metadata (and root filesystem of unified images).
+	err = imageWalkTarball(imagePath, func(hdr *tar.Header, r io.Reader) error {
+		name := strings.TrimPrefix(hdr.Name, "./")
+
+		switch name {
+		case "config.json":
+			spec = &ociSpecs.Spec{}
+			return json.NewDecoder(r).Decode(spec)
+		case oci.SourceFile:
+			source = &oci.Source{}
+			return json.NewDecoder(r).Decode(source)
+		}
+
+		rootfsName, ok := strings.CutPrefix(name, "rootfs/")
+		if split || !ok || rootfsName == "" {
+			return nil
+		}
+
+		return addEntry(hdr, r, rootfsName)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to convert image: %w", err)
+	}
+
+	// Go through the root filesystem of split images.
+	if split {
+		err = imageWalkTarball(rootfsPath, func(hdr *tar.Header, r io.Reader) error {
+			name := strings.TrimPrefix(hdr.Name, "./")
+			if name == "" || name == "." {
+				return nil
+			}
+
+			return addEntry(hdr, r, name)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to convert image root filesystem: %w", err)
+		}
+	}
+
+	err = startLayer()
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the content of the source image which isn't in the root filesystem anymore.
+	if differ != nil {
+		for _, whiteout := range differ.Whiteouts() {
+			err = layer.WriteHeader(&tar.Header{Name: whiteout, Typeflag: tar.TypeReg, Mode: 0644, ModTime: imgInfo.CreatedAt})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = layer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate the image configuration.
+	created := imgInfo.CreatedAt
+	config := oci.ImageConfig{
+		Created:      &created,
+		Architecture: platforms[0].Architecture,
+		OS:           platforms[0].OS,
+		Variant:      platforms[0].Variant,
+		Config: oci.ContainerConfig{
+			Entrypoint: []string{"/sbin/init"},
+			WorkingDir: "/",
+			Labels:     map[string]string{},
+		},
+	}
+
+	if spec != nil && spec.Process != nil {
+		config.Config.Entrypoint = spec.Process.Args
+		config.Config.Env = spec.Process.Env
+		config.Config.WorkingDir = spec.Process.Cwd
+		config.Config.User = fmt.Sprintf("%d:%d", spec.Process.User.UID, spec.Process.User.GID)
+	}
+
+	if imgInfo.Properties["description"] != "" {
+		config.Config.Labels[oci.AnnotationDescription] = imgInfo.Properties["description"]
+	}
+
+	config.Config.Labels[oci.AnnotationCreated] = created.UTC().Format(time.RFC3339)
+
+	err = img.Finalize(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// imageAddSourceLayers retrieves the layers of the OCI image an image was converted from and adds them to img.
+// It returns a Differ indexing the content of those layers.
+func imageAddSourceLayers(ctx context.Context, s *state.State, img *oci.LocalImage, source *oci.Source) (*oci.Differ, error) {
+	if len(source.Layers) != len(source.DiffIDs) {
+		return nil, errors.New("Invalid OCI image source")
+	}
+
+	httpClient, err := localUtil.HTTPClient("", s.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	registry, err := oci.NewClient(httpClient, source.Registry, version.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	differ := oci.NewDiffer()
+
+	for i, descriptor := range source.Layers {
+		rc, err := registry.GetBlob(ctx, source.Repository, descriptor)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve layer %q: %w", descriptor.Digest, err)
+		}
+
+		err = img.AddExistingLayer(rc, descriptor, source.DiffIDs[i])
+		_ = rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := img.Open(descriptor)
+		if err != nil {
+			return nil, err
+		}
+
+		err = differ.AddLayer(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to index layer %q: %w", descriptor.Digest, err)
+		}
+	}
+
+	return differ, nil
+}
+
+// imageWalkTarball calls the handler for each entry of a, possibly compressed, image tarball.
+func imageWalkTarball(path string, handler func(hdr *tar.Header, r io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	_, ext, unpacker, err := archive.DetectCompressionFile(f)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader = f
+	if len(unpacker) > 0 {
+		if ext == ".squashfs" {
+			// sqfs2tar can only read from a file.
+			unpacker = append(unpacker, path)
+		}
+
+		cmd := exec.Command(unpacker[0], unpacker[1:]...)
+		cmd.Stdin = f
+
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return err
+		}
+
+		err = cmd.Start()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = stdout.Close()
+			_ = cmd.Wait()
+		}()
+
+		reader = stdout
+	}
+
+	tr := tar.NewReader(reader)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return err
+		}
+
+		err = handler(hdr, tr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
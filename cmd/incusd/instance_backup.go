@@ -9,10 +9,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/jmap"
+	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/instance"
@@ -318,6 +320,34 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 	fullName := name + internalInstance.SnapshotDelimiter + req.Name
 	instanceOnly := req.InstanceOnly
 
+	// Validate the backup to base an incremental backup on.
+	var incrementalFrom string
+	if req.IncrementalFrom != "" {
+		incrementalFrom = name + internalInstance.SnapshotDelimiter + req.IncrementalFrom
+
+		_, err := instance.BackupLoadByName(s, projectName, incrementalFrom)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed loading backup %q: %w", req.IncrementalFrom, err))
+		}
+	}
+
+	// Validate the storage bucket to upload the backup to.
+	if req.Target != nil {
+		if req.IncrementalFrom != "" || req.IncrementalBase {
+			return response.BadRequest(fmt.Errorf("Incremental backups can't be uploaded to a storage bucket"))
+		}
+
+		_, err := backupBucketTransferManager(s, projectName, req.Target)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = backupBucketCheckAccess(s, r, projectName, req.Target, auth.EntitlementCanEdit)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	backup := func(op *operations.Operation) error {
 		args := db.InstanceBackup{
 			Name:                 fullName,
@@ -327,6 +357,21 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 			InstanceOnly:         instanceOnly,
 			OptimizedStorage:     req.OptimizedStorage,
 			CompressionAlgorithm: req.CompressionAlgorithm,
+			IncrementalFrom:      incrementalFrom,
+		}
+
+		// Only keep a storage anchor when asked to, as it holds on to the backed up data.
+		if req.IncrementalBase {
+			args.Anchor = uuid.New().String()
+		}
+
+		if req.Target != nil {
+			err := backupUpload(s, args, inst, req.Target, op)
+			if err != nil {
+				return fmt.Errorf("Upload backup: %w", err)
+			}
+
+			return nil
 		}
 
 		err := backupCreate(s, args, inst, op)
@@ -339,7 +384,11 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 
 	resources := map[string][]api.URL{}
 	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name)}
-	resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name, "backups", req.Name)}
+
+	// Uploaded backups aren't kept on the server.
+	if req.Target == nil {
+		resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name, "backups", req.Name)}
+	}
 
 	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask,
 		operationtype.BackupCreate, resources, nil, backup, nil, nil, r)
@@ -603,7 +652,12 @@ func instanceBackupDelete(d *Daemon, r *http.Request) response.Response {
 	}
 
 	remove := func(op *operations.Operation) error {
-		err := backup.Delete()
+		inst, ok := backup.Instance().(instance.Instance)
+		if !ok {
+			return fmt.Errorf("Unexpected instance type for backup %q", backup.Name())
+		}
+
+		err := backupDelete(s, inst, backup)
 		if err != nil {
 			return err
 		}
@@ -15,6 +15,7 @@ import (
 	"github.com/gorilla/websocket"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db"
@@ -704,6 +705,15 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		return response.BadRequest(fmt.Errorf("Backup file is missing required information"))
 	}
 
+	// Incremental backups are applied on top of an existing instance.
+	if bInfo.IncrementalFrom != "" {
+		if instanceName != "" {
+			bInfo.Name = instanceName
+		}
+
+		return applyIncrementalBackup(s, r, projectName, bInfo, backupFile, revert)
+	}
+
 	// Check project permissions.
 	var req api.InstancesPost
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -847,6 +857,65 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 	return operations.OperationResponse(op)
 }
 
+// applyIncrementalBackup applies an incremental backup file on top of an existing instance.
+func applyIncrementalBackup(s *state.State, r *http.Request, projectName string, bInfo *backup.Info, backupFile *os.File, revert *revert.Reverter) response.Response {
+	// Applying the backup modifies the existing instance.
+	err := s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectInstance(projectName, bInfo.Name), auth.EntitlementCanEdit)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, bInfo.Name)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading instance to apply incremental backup to: %w", err))
+	}
+
+	if s.ServerClustered && inst.Location() != s.ServerName {
+		return response.BadRequest(fmt.Errorf("Incremental backups must be applied on the cluster member hosting the instance (%q)", inst.Location()))
+	}
+
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if pool.Driver().Info().Name != bInfo.Backend {
+		return response.BadRequest(fmt.Errorf("Optimized backup storage driver %q differs from the instance storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name))
+	}
+
+	if inst.IsRunning() {
+		return response.BadRequest(fmt.Errorf("Incremental backups can only be applied to stopped instances"))
+	}
+
+	// Copy reverter so far so we can use it inside run after this function has finished.
+	runRevert := revert.Clone()
+
+	run := func(op *operations.Operation) error {
+		defer func() { _ = backupFile.Close() }()
+		defer runRevert.Fail()
+
+		err := pool.ApplyInstanceBackup(inst, *bInfo, backupFile, op)
+		if err != nil {
+			return fmt.Errorf("Apply incremental backup: %w", err)
+		}
+
+		runRevert.Success()
+
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", bInfo.Name)}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.BackupRestore, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	revert.Success()
+	return operations.OperationResponse(op)
+}
+
 // swagger:operation POST /1.0/instances instances instances_post
 //
 //	Create a new instance
@@ -926,6 +995,34 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		req.Type = api.InstanceTypeContainer // Default to container if not specified.
 	}
 
+	// Handle importing a backup file stored in a storage bucket.
+	if req.Source.Type == "backup" {
+		if req.Source.Bucket == nil {
+			return response.BadRequest(fmt.Errorf("No storage bucket provided"))
+		}
+
+		err := backupBucketCheckAccess(s, r, targetProjectName, req.Source.Bucket, auth.EntitlementCanView)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		reader, err := backupBucketReader(s, targetProjectName, req.Source.Bucket)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		defer func() { _ = reader.Close() }()
+
+		// Use the pool of the root disk device if one is provided.
+		var poolName string
+		_, rootDiskDevice, _ := internalInstance.GetRootDiskDevice(req.Devices)
+		if rootDiskDevice != nil {
+			poolName = rootDiskDevice["pool"]
+		}
+
+		return createFromBackup(s, r, targetProjectName, reader, poolName, req.Name)
+	}
+
 	if req.Devices == nil {
 		req.Devices = map[string]map[string]string{}
 	}
@@ -1145,6 +1242,12 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			if err != nil {
 				return err
 			}
+
+			// Apply the instance placement rules.
+			candidateMembers, err = instance.PlacementCandidates(ctx, tx, targetProjectName, req.Name, db.ExpandInstanceConfig(req.Config, profiles), candidateMembers)
+			if err != nil {
+				return err
+			}
 		}
 
 		if !clusterNotification {
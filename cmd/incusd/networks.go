@@ -714,7 +714,7 @@ func networksPostCluster(ctx context.Context, s *state.State, projectName string
 	}
 
 	// Create notifier for other nodes to create the network.
-	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAll)
+	notifier, err := cluster.NewNotifierWithContext(ctx, s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAll)
 	if err != nil {
 		return err
 	}
@@ -1113,7 +1113,7 @@ func networkDelete(d *Daemon, r *http.Request) response.Response {
 
 	// If we are clustered, also notify all other nodes, if any.
 	if s.ServerClustered {
-		notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAll)
+		notifier, err := cluster.NewNotifierWithContext(r.Context(), s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAll)
 		if err != nil {
 			return response.SmartError(err)
 		}
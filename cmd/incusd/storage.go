@@ -17,6 +17,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/internal/server/warnings"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
@@ -218,3 +219,68 @@ func storagePoolDriversCacheUpdate(ctx context.Context, s *state.State) {
 	storagePoolSupportedDriversCacheVal.Store(supportedDrivers)
 	storagePoolDriversCacheLock.Unlock()
 }
+
+// autoScrubStoragePoolsTask starts the scrub of the local storage pools whose scrub.schedule is due.
+func autoScrubStoragePoolsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		var poolNames []string
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+
+			return err
+		})
+		if err != nil {
+			if !response.IsNotFoundError(err) {
+				logger.Error("Failed loading storage pools for scrub task", logger.Ctx{"err": err})
+			}
+
+			return
+		}
+
+		for _, poolName := range poolNames {
+			pool, err := storagePools.LoadByName(s, poolName)
+			if err != nil {
+				logger.Error("Failed loading storage pool for scrub task", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			// Check if the pool is due to be scrubbed.
+			schedule := pool.Driver().Config()["scrub.schedule"]
+			if schedule == "" || !snapshotIsScheduledNow(schedule, pool.ID()) {
+				continue
+			}
+
+			started, err := pool.Scrub()
+			if err != nil {
+				logger.Error("Failed starting scheduled storage pool scrub", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			if !started {
+				logger.Debug("Skipping scheduled storage pool scrub as one is still running", logger.Ctx{"pool": poolName})
+				continue
+			}
+
+			logger.Info("Started scheduled storage pool scrub", logger.Ctx{"pool": poolName})
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
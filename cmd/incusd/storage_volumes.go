@@ -658,6 +658,27 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// Handle importing a backup file stored in a storage bucket.
+	if req.Source.Type == "backup" {
+		if req.Source.Bucket == nil {
+			return response.BadRequest(fmt.Errorf("No storage bucket provided"))
+		}
+
+		err := backupBucketCheckAccess(s, r, projectName, req.Source.Bucket, auth.EntitlementCanView)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		reader, err := backupBucketReader(s, projectName, req.Source.Bucket)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		defer func() { _ = reader.Close() }()
+
+		return createStoragePoolVolumeFromBackup(s, r, request.ProjectParam(r), projectName, reader, poolName, req.Name)
+	}
+
 	// Quick checks.
 	if req.Name == "" {
 		return response.BadRequest(fmt.Errorf("No name provided"))
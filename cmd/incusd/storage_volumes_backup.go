@@ -431,6 +431,19 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 	fullName := volumeName + internalInstance.SnapshotDelimiter + req.Name
 	volumeOnly := req.VolumeOnly
 
+	// Validate the storage bucket to upload the backup to.
+	if req.Target != nil {
+		_, err := backupBucketTransferManager(s, projectName, req.Target)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = backupBucketCheckAccess(s, r, projectName, req.Target, auth.EntitlementCanEdit)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	backup := func(op *operations.Operation) error {
 		args := db.StoragePoolVolumeBackup{
 			Name:                 fullName,
@@ -442,6 +455,17 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 			CompressionAlgorithm: req.CompressionAlgorithm,
 		}
 
+		if req.Target != nil {
+			err := volumeBackupUpload(s, args, projectName, poolName, volumeName, req.Target)
+			if err != nil {
+				return fmt.Errorf("Upload volume backup: %w", err)
+			}
+
+			s.Events.SendLifecycle(projectName, lifecycle.StorageVolumeBackupExported.Event(poolName, volumeTypeName, args.Name, projectName, op.Requestor(), logger.Ctx{"target": backupBucketTarget(req.Target)}))
+
+			return nil
+		}
+
 		err := volumeBackupCreate(s, args, projectName, poolName, volumeName)
 		if err != nil {
 			return fmt.Errorf("Create volume backup: %w", err)
@@ -454,7 +478,11 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 
 	resources := map[string][]api.URL{}
 	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", volumeTypeName, volumeName)}
-	resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", volumeTypeName, volumeName, "backups", req.Name)}
+
+	// Uploaded backups aren't kept on the server.
+	if req.Target == nil {
+		resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", volumeTypeName, volumeName, "backups", req.Name)}
+	}
 
 	op, err := operations.OperationCreate(s, request.ProjectParam(r), operations.OperationClassTask, operationtype.CustomVolumeBackupCreate, resources, nil, backup, nil, nil, r)
 	if err != nil {
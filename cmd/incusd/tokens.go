@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/state"
@@ -66,9 +67,30 @@ func autoRemoveExpiredTokens(ctx context.Context, s *state.State) {
 	logger.Debug("Done removing expired tokens")
 }
 
+func autoRemoveExpiredAuthTokens(ctx context.Context, s *state.State) {
+	var count int
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		count, err = tx.DeleteExpiredAuthTokens(ctx, time.Now())
+
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed removing expired API tokens", logger.Ctx{"err": err})
+		return
+	}
+
+	if count > 0 {
+		logger.Info("Removed expired API tokens", logger.Ctx{"count": count})
+	}
+}
+
 func autoRemoveExpiredTokensTask(d *Daemon) (task.Func, task.Schedule) {
 	f := func(ctx context.Context) {
 		autoRemoveExpiredTokens(ctx, d.State())
+		autoRemoveExpiredAuthTokens(ctx, d.State())
 	}
 
 	return f, task.Every(time.Minute)
@@ -0,0 +1,631 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/server/webhooks"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+var webhooksCmd = APIEndpoint{
+	Path: "webhooks",
+
+	Get:  APIEndpointAction{Handler: webhooksGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: webhooksPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var webhookCmd = APIEndpoint{
+	Path: "webhooks/{name}",
+
+	Delete: APIEndpointAction{Handler: webhookDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: webhookGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Put:    APIEndpointAction{Handler: webhookPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Patch:  APIEndpointAction{Handler: webhookPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Post:   APIEndpointAction{Handler: webhookPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// webhookValidateName checks the name of a webhook.
+func webhookValidateName(name string) error {
+	if name == "" {
+		return errors.New("Webhook name is required")
+	}
+
+	err := validate.IsURLSegmentSafe(name)
+	if err != nil {
+		return fmt.Errorf("Invalid webhook name %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// webhooksNotify reloads the webhooks on the local member and, unless the request is itself a
+// notification, notifies the other cluster members so they reload theirs.
+func webhooksNotify(d *Daemon, r *http.Request, hook func(client incus.InstanceServer) error) error {
+	s := d.State()
+
+	err := d.reloadWebhooks(r.Context())
+	if err != nil {
+		return err
+	}
+
+	if isClusterNotification(r) {
+		return nil
+	}
+
+	notifier, err := cluster.NewNotifierWithContext(r.Context(), s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+	if err != nil {
+		return err
+	}
+
+	return notifier(hook)
+}
+
+// swagger:operation GET /1.0/webhooks webhooks webhooks_get
+//
+//	Get the webhooks
+//
+//	Returns a list of webhooks (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/webhooks/ci",
+//	              "/1.0/webhooks/chat"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/webhooks?recursion=1 webhooks webhooks_get_recursion1
+//
+//	Get the webhooks
+//
+//	Returns a list of webhooks (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of webhooks
+//	          items:
+//	            $ref: "#/definitions/Webhook"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhooksGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var names []string
+	var hooks []api.Webhook
+
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		if recursion {
+			hooks, err = tx.GetWebhooks(ctx)
+		} else {
+			names, err = tx.GetWebhookNames(ctx)
+		}
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion {
+		return response.SyncResponse(true, hooks)
+	}
+
+	urls := make([]string, 0, len(names))
+	for _, name := range names {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "webhooks", name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/webhooks webhooks webhooks_post
+//
+//	Add a webhook
+//
+//	Creates a new webhook.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: webhook
+//	    description: Webhook
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/WebhooksPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhooksPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.WebhooksPost{}
+
+	// Parse the request into a record.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !isClusterNotification(r) {
+		err = webhookValidateName(req.Name)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		// Validate the config.
+		err = webhooks.Validate(req.Config)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		// Create the DB record.
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			_, _, err := tx.GetWebhook(ctx, req.Name)
+			if err == nil {
+				return api.StatusErrorf(http.StatusConflict, "Webhook %q already exists", req.Name)
+			} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return err
+			}
+
+			_, err = tx.CreateWebhook(ctx, req)
+
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = webhooksNotify(d, r, func(client incus.InstanceServer) error {
+		return client.CreateWebhook(req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if isClusterNotification(r) {
+		return response.EmptySyncResponse
+	}
+
+	// Emit the lifecycle event.
+	lc := lifecycle.WebhookCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/webhooks/{name} webhooks webhook_delete
+//
+//	Delete the webhook
+//
+//	Removes the webhook.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isClusterNotification(r) {
+		// Delete the DB record.
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			id, _, err := tx.GetWebhook(ctx, name)
+			if err != nil {
+				return err
+			}
+
+			return tx.DeleteWebhook(ctx, id)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = webhooksNotify(d, r, func(client incus.InstanceServer) error {
+		return client.DeleteWebhook(name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isClusterNotification(r) {
+		// Emit the lifecycle event.
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.WebhookDeleted.Event(name, request.CreateRequestor(r), nil))
+	}
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/webhooks/{name} webhooks webhook_get
+//
+//	Get the webhook
+//
+//	Gets a specific webhook.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Webhook
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/Webhook"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var info *api.Webhook
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, info, err = tx.GetWebhook(ctx, name)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, info, info.Writable())
+}
+
+// swagger:operation PATCH /1.0/webhooks/{name} webhooks webhook_patch
+//
+//	Partially update the webhook
+//
+//	Updates a subset of the webhook configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: webhook
+//	    description: Webhook configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/WebhookPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation PUT /1.0/webhooks/{name} webhooks webhook_put
+//
+//	Update the webhook
+//
+//	Updates the entire webhook configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: webhook
+//	    description: Webhook configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/WebhookPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Decode the request.
+	req := api.WebhookPut{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !isClusterNotification(r) {
+		// Get the existing webhook.
+		var id int64
+		var info *api.Webhook
+
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			id, info, err = tx.GetWebhook(ctx, name)
+
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Validate the ETag.
+		err = localUtil.EtagCheck(r, info.Writable())
+		if err != nil {
+			return response.PreconditionFailed(err)
+		}
+
+		if r.Method == http.MethodPatch {
+			// If config being updated via "patch" method, then merge all existing config with the keys that
+			// are present in the request config.
+			if req.Config == nil {
+				req.Config = map[string]string{}
+			}
+
+			for k, v := range info.Config {
+				_, ok := req.Config[k]
+				if !ok {
+					req.Config[k] = v
+				}
+			}
+
+			if req.Description == "" {
+				req.Description = info.Description
+			}
+		}
+
+		// Validate the resulting config.
+		err = webhooks.Validate(req.Config)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		// Update the database record.
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateWebhook(ctx, id, req)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = webhooksNotify(d, r, func(client incus.InstanceServer) error {
+		return client.UpdateWebhook(name, req, "")
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isClusterNotification(r) {
+		// Emit the lifecycle event.
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.WebhookUpdated.Event(name, request.CreateRequestor(r), nil))
+	}
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/webhooks/{name} webhooks webhook_post
+//
+//	Rename the webhook
+//
+//	Renames the webhook.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: webhook
+//	    description: Webhook rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/WebhookPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Decode the request.
+	req := api.WebhookPost{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !isClusterNotification(r) {
+		err = webhookValidateName(req.Name)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		// Rename the DB record.
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			id, _, err := tx.GetWebhook(ctx, name)
+			if err != nil {
+				return err
+			}
+
+			_, _, err = tx.GetWebhook(ctx, req.Name)
+			if err == nil {
+				return api.StatusErrorf(http.StatusConflict, "Webhook %q already exists", req.Name)
+			} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return err
+			}
+
+			return tx.RenameWebhook(ctx, id, req.Name)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = webhooksNotify(d, r, func(client incus.InstanceServer) error {
+		return client.RenameWebhook(name, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isClusterNotification(r) {
+		// Emit the lifecycle event.
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.WebhookRenamed.Event(req.Name, request.CreateRequestor(r), logger.Ctx{"old_name": name}))
+	}
+
+	return response.EmptySyncResponse
+}
@@ -0,0 +1,59 @@
+package instance
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// BackupTarget represents the location scheduled backups are written to.
+type BackupTarget struct {
+	// Absolute path of a directory on the host.
+	Path string
+
+	// Storage pool, bucket and path prefix within the bucket of an Incus storage bucket.
+	Pool   string
+	Bucket string
+	Prefix string
+}
+
+// ParseBackupTarget parses the value of a "backups.target" config key.
+// The value is either an absolute path on the host or a storage bucket in the form "<pool>/<bucket>[/<prefix>]".
+// An empty value returns an empty BackupTarget, meaning that the backups are kept on the server.
+func ParseBackupTarget(value string) (*BackupTarget, error) {
+	if value == "" {
+		return &BackupTarget{}, nil
+	}
+
+	if filepath.IsAbs(value) {
+		return &BackupTarget{Path: value}, nil
+	}
+
+	fields := strings.SplitN(value, "/", 3)
+	if len(fields) < 2 || fields[0] == "" || fields[1] == "" {
+		return nil, fmt.Errorf("Must be an absolute path or a storage bucket in the form <pool>/<bucket>[/<prefix>]")
+	}
+
+	target := &BackupTarget{
+		Pool:   fields[0],
+		Bucket: fields[1],
+	}
+
+	if len(fields) == 3 {
+		target.Prefix = strings.Trim(fields[2], "/")
+
+		for _, element := range strings.Split(target.Prefix, "/") {
+			if element == "." || element == ".." {
+				return nil, fmt.Errorf("Invalid storage bucket prefix %q", fields[2])
+			}
+		}
+	}
+
+	return target, nil
+}
+
+// IsBackupTarget validates the value of a "backups.target" config key.
+func IsBackupTarget(value string) error {
+	_, err := ParseBackupTarget(value)
+	return err
+}
@@ -0,0 +1,75 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBackupTarget(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  *BackupTarget
+		expectErr bool
+	}{
+		{value: "", expected: &BackupTarget{}},
+		{value: "/mnt/backups", expected: &BackupTarget{Path: "/mnt/backups"}},
+		{value: "default/backups", expected: &BackupTarget{Pool: "default", Bucket: "backups"}},
+		{value: "default/backups/", expected: &BackupTarget{Pool: "default", Bucket: "backups"}},
+		{value: "default/backups/daily/instances", expected: &BackupTarget{Pool: "default", Bucket: "backups", Prefix: "daily/instances"}},
+		{value: "default", expectErr: true},
+		{value: "default/", expectErr: true},
+		{value: "/backups", expected: &BackupTarget{Path: "/backups"}},
+		{value: "default//daily", expectErr: true},
+		{value: "default/backups/../other", expectErr: true},
+		{value: "mnt/backups/.", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			target, err := ParseBackupTarget(tt.value)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, target)
+		})
+	}
+}
+
+func TestBackupsConfigKeys(t *testing.T) {
+	tests := []struct {
+		key       string
+		value     string
+		expectErr bool
+	}{
+		{key: "backups.schedule", value: ""},
+		{key: "backups.schedule", value: "@daily"},
+		{key: "backups.schedule", value: "@never"},
+		{key: "backups.schedule", value: "@hourly, @weekly"},
+		{key: "backups.schedule", value: "0 6 * * *"},
+		{key: "backups.schedule", value: "@startup", expectErr: true},
+		{key: "backups.schedule", value: "0 6 * *", expectErr: true},
+		{key: "backups.expiry", value: ""},
+		{key: "backups.expiry", value: "1d 3H"},
+		{key: "backups.expiry", value: "3 days", expectErr: true},
+		{key: "backups.target", value: ""},
+		{key: "backups.target", value: "/mnt/backups"},
+		{key: "backups.target", value: "default/backups/daily"},
+		{key: "backups.target", value: "backups", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			err := InstanceConfigKeysAny[tt.key](tt.value)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
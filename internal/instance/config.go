@@ -29,6 +29,37 @@ var HugePageSizeSuffix = [...]string{"64KB", "1MB", "2MB", "1GB"}
 
 // InstanceConfigKeysAny is a map of config key to validator. (keys applying to containers AND virtual machines).
 var InstanceConfigKeysAny = map[string]func(value string) error{
+	// gendoc:generate(entity=instance, group=backups, key=backups.expiry)
+	// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: When scheduled backups are to be deleted
+	"backups.expiry": func(value string) error {
+		// Validate expression
+		_, err := GetExpiry(time.Time{}, value)
+		return err
+	},
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-and-space-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic backups.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Schedule for automatic instance backups
+	"backups.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"})),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.target)
+	// Specify an absolute path on the host or a storage bucket of the project (`<pool>/<bucket>[/<prefix>]`) to store the backup files in, or leave empty to keep the backups on the server.
+	// Backups written to a target are not listed as instance backups, and are rotated based on `backups.expiry`.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Where to store scheduled backups
+	"backups.target": validate.Optional(IsBackupTarget),
+
 	// gendoc:generate(entity=instance, group=boot, key=boot.autorestart)
 	// If set to `true` will attempt up to 10 restarts over a 1 minute period upon unexpected instance exit.
 	// ---
@@ -259,6 +290,47 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Whether to allow for stateful stop/start and snapshots
 	"migration.stateful": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=placement, key=placement.group)
+	// Other instances of the project can refer to this group in their affinity, anti-affinity and spread rules.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Placement group the instance belongs to
+	"placement.group": validate.Optional(validate.IsHostname),
+
+	// gendoc:generate(entity=instance, group=placement, key=placement.affinity)
+	// Specify a comma-separated list of placement groups.
+	// The instance is placed on a cluster member that hosts instances of each of those groups (if any exist yet).
+	//
+	// See {ref}`clustering-instance-placement-rules` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Placement groups to place the instance with
+	"placement.affinity": validate.Optional(validate.IsListOf(validate.IsHostname)),
+
+	// gendoc:generate(entity=instance, group=placement, key=placement.anti_affinity)
+	// Specify a comma-separated list of placement groups.
+	// The instance is never placed on a cluster member that hosts instances of any of those groups.
+	//
+	// See {ref}`clustering-instance-placement-rules` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Placement groups to keep the instance away from
+	"placement.anti_affinity": validate.Optional(validate.IsListOf(validate.IsHostname)),
+
+	// gendoc:generate(entity=instance, group=placement, key=placement.spread)
+	// Specify a comma-separated list of cluster groups.
+	// The instance is placed within those cluster groups, favoring the one with the fewest instances of the same placement group.
+	//
+	// See {ref}`clustering-instance-placement-rules` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Cluster groups to spread the placement group across
+	"placement.spread": validate.Optional(validate.IsListOf(validate.IsAny)),
+
 	// Caller is responsible for full validation of any raw.* value.
 
 	// gendoc:generate(entity=instance, group=raw, key=raw.apparmor)
@@ -925,6 +997,31 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 
 // InstanceConfigKeysVM is a map of config key to validator. (keys applying to VM only).
 var InstanceConfigKeysVM = map[string]func(value string) error{
+	// gendoc:generate(entity=instance, group=miscellaneous, key=agent.fsfreeze)
+	// When set to `true`, the guest file systems are frozen through the agent while snapshots and backups of the running VM are taken.
+	//
+	// See {ref}`instances-snapshots-fsfreeze` for details.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Whether to freeze the guest file systems during snapshots and backups
+	"agent.fsfreeze": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=miscellaneous, key=agent.qemu_guest_agent)
+	// When set to `true`, a channel for the QEMU guest agent is added to the VM.
+	// If the `incus-agent` isn't running, Incus then falls back to the QEMU guest agent for commands, file transfers and network information.
+	//
+	// See {ref}`instances-qemu-guest-agent` for details.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Whether to use the QEMU guest agent when `incus-agent` isn't available
+	"agent.qemu_guest_agent": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=resource-limits, key=limits.memory.hugepages)
 	// If this option is set to `false`, regular system memory is used.
 	// ---
@@ -935,6 +1032,52 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to back the instance using huge pages
 	"limits.memory.hugepages": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=resource-limits, key=limits.memory.hotplug)
+	// When set, {config:option}`instance-resource-limits:limits.memory` can be increased up to this size while the VM is running.
+	// The additional memory is provided through a `virtio-mem` device, which the guest kernel must support.
+	//
+	// See {ref}`instance-options-limits-memory-vm` for details.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Maximum memory size the instance can be grown to while running
+	"limits.memory.hotplug": validate.Optional(validate.IsSize),
+
+	// gendoc:generate(entity=instance, group=migration, key=migration.live.compression)
+	// Possible values are `none` and `zstd`.
+	// Compression is performed by the multifd channels, so a single channel is used if {config:option}`instance-migration:migration.live.multifd` isn't set.
+	// ---
+	//  type: string
+	//  defaultdesc: `none`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Compression of the memory transferred during live migration
+	"migration.live.compression": validate.Optional(validate.IsOneOf("none", "zstd")),
+
+	// gendoc:generate(entity=instance, group=migration, key=migration.live.multifd)
+	// Transferring memory over multiple channels can speed up the migration of VMs with a lot of memory.
+	// ---
+	//  type: integer
+	//  defaultdesc: `0` (disabled)
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Number of parallel channels used to transfer memory during live migration
+	"migration.live.multifd": validate.Optional(validate.IsInRange(0, 255)),
+
+	// gendoc:generate(entity=instance, group=migration, key=migration.live.postcopy)
+	// When enabled, the VM switches over to the target after a first pass over its memory and the remaining memory is then fetched from the source as the guest accesses it.
+	// This ensures that the migration of busy VMs completes, but if the connection between the servers fails during that phase, the VM is lost.
+	//
+	// See {ref}`live-migration-vms` for details.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Whether to switch to post-copy mode during live migration
+	"migration.live.postcopy": validate.Optional(validate.IsBool),
+
 	// Caller is responsible for full validation of any raw.* value.
 
 	// gendoc:generate(entity=instance, group=raw, key=raw.qemu)
@@ -1089,6 +1232,13 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to regenerate VM NVRAM the next time the instance starts
 	"volatile.apply_nvram": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.migration.broken)
+	//
+	// ---
+	//  type: bool
+	//  shortdesc: Whether the instance state was lost during a failed post-copy live migration
+	"volatile.migration.broken": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.vsock_id)
 	//
 	// ---
@@ -1347,6 +1497,61 @@ func ConfigKeyChecker(key string, instanceType api.InstanceType) (func(value str
 		return validate.IsAny, nil
 	}
 
+	if strings.HasPrefix(key, "limits.disk.group.") {
+		fields := strings.Split(strings.TrimPrefix(key, "limits.disk.group."), ".")
+		if len(fields) != 2 || validate.IsHostname(fields[0]) != nil {
+			return nil, fmt.Errorf("Unknown configuration key: %s", key)
+		}
+
+		// validateLimit checks for either a bytes/s value or an iops value.
+		validateLimit := func(value string) error {
+			if strings.HasSuffix(value, "iops") {
+				return validate.IsInt64(strings.TrimSuffix(value, "iops"))
+			}
+
+			_, err := units.ParseByteSizeString(value)
+			return err
+		}
+
+		// gendoc:generate(entity=instance, group=resource-limits, key=limits.disk.group.<name>.read)
+		// I/O limit in byte/s (various suffixes supported, see {ref}`instances-limit-units`) or in IOPS (must be suffixed with `iops`),
+		// shared by all disk devices of the instance that have their `limits.group` property set to `<name>`.
+		//
+		// See also {ref}`storage-configure-IO-groups`.
+		// ---
+		//  type: string
+		//  liveupdate: yes
+		//  shortdesc: Shared read I/O limit of a disk group
+		if fields[1] == "read" {
+			return validate.Optional(validateLimit), nil
+		}
+
+		// gendoc:generate(entity=instance, group=resource-limits, key=limits.disk.group.<name>.write)
+		// I/O limit in byte/s (various suffixes supported, see {ref}`instances-limit-units`) or in IOPS (must be suffixed with `iops`),
+		// shared by all disk devices of the instance that have their `limits.group` property set to `<name>`.
+		//
+		// See also {ref}`storage-configure-IO-groups`.
+		// ---
+		//  type: string
+		//  liveupdate: yes
+		//  shortdesc: Shared write I/O limit of a disk group
+		if fields[1] == "write" {
+			return validate.Optional(validateLimit), nil
+		}
+
+		// gendoc:generate(entity=instance, group=resource-limits, key=limits.disk.group.<name>.max)
+		// Same as setting both `limits.disk.group.<name>.read` and `limits.disk.group.<name>.write`.
+		// ---
+		//  type: string
+		//  liveupdate: yes
+		//  shortdesc: Shared read and write I/O limit of a disk group
+		if fields[1] == "max" {
+			return validate.Optional(validateLimit), nil
+		}
+
+		return nil, fmt.Errorf("Unknown configuration key: %s", key)
+	}
+
 	// gendoc:generate(entity=instance, group=miscellaneous, key=smbios11.*)
 	// `SMBIOS Type 11` configuration keys.
 	// ---
@@ -0,0 +1,51 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestConfigKeyChecker_diskLimitsGroup(t *testing.T) {
+	tests := []struct {
+		key           string
+		value         string
+		expectErr     bool
+		expectUnknown bool
+	}{
+		{key: "limits.disk.group.db.read", value: "100MB"},
+		{key: "limits.disk.group.db.write", value: "500iops"},
+		{key: "limits.disk.group.db.max", value: "1GiB"},
+		{key: "limits.disk.group.db.max", value: ""},
+		{key: "limits.disk.group.db.read", value: "fast", expectErr: true},
+		{key: "limits.disk.group.db.write", value: "manyiops", expectErr: true},
+		{key: "limits.disk.group.db.total", expectUnknown: true},
+		{key: "limits.disk.group.db", expectUnknown: true},
+		{key: "limits.disk.group.db.read.extra", expectUnknown: true},
+		{key: "limits.disk.group.-db.read", expectUnknown: true},
+	}
+
+	for _, instanceType := range []api.InstanceType{api.InstanceTypeContainer, api.InstanceTypeVM} {
+		for _, tt := range tests {
+			t.Run(string(instanceType)+"/"+tt.key+"="+tt.value, func(t *testing.T) {
+				checker, err := ConfigKeyChecker(tt.key, instanceType)
+				if tt.expectUnknown {
+					assert.Error(t, err)
+					return
+				}
+
+				require.NoError(t, err)
+
+				err = checker(tt.value)
+				if tt.expectErr {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+				}
+			})
+		}
+	}
+}
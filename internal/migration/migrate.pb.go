@@ -655,6 +655,69 @@ func (x *BtrfsFeatures) GetHeaderSubvolumeUuids() bool {
 	return false
 }
 
+type QemuFeatures struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MultifdChannels    *uint32 `protobuf:"varint,1,opt,name=multifd_channels,json=multifdChannels" json:"multifd_channels,omitempty"`
+	MultifdCompression *string `protobuf:"bytes,2,opt,name=multifd_compression,json=multifdCompression" json:"multifd_compression,omitempty"`
+	Postcopy           *bool   `protobuf:"varint,3,opt,name=postcopy" json:"postcopy,omitempty"`
+}
+
+func (x *QemuFeatures) Reset() {
+	*x = QemuFeatures{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_migration_migrate_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QemuFeatures) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QemuFeatures) ProtoMessage() {}
+
+func (x *QemuFeatures) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_migration_migrate_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QemuFeatures.ProtoReflect.Descriptor instead.
+func (*QemuFeatures) Descriptor() ([]byte, []int) {
+	return file_internal_migration_migrate_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *QemuFeatures) GetMultifdChannels() uint32 {
+	if x != nil && x.MultifdChannels != nil {
+		return *x.MultifdChannels
+	}
+	return 0
+}
+
+func (x *QemuFeatures) GetMultifdCompression() string {
+	if x != nil && x.MultifdCompression != nil {
+		return *x.MultifdCompression
+	}
+	return ""
+}
+
+func (x *QemuFeatures) GetPostcopy() bool {
+	if x != nil && x.Postcopy != nil {
+		return *x.Postcopy
+	}
+	return false
+}
+
 type MigrationHeader struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -672,12 +735,13 @@ type MigrationHeader struct {
 	VolumeSize         *int64           `protobuf:"varint,11,opt,name=volumeSize" json:"volumeSize,omitempty"`
 	BtrfsFeatures      *BtrfsFeatures   `protobuf:"bytes,12,opt,name=btrfsFeatures" json:"btrfsFeatures,omitempty"`
 	IndexHeaderVersion *uint32          `protobuf:"varint,13,opt,name=indexHeaderVersion" json:"indexHeaderVersion,omitempty"`
+	QemuFeatures       *QemuFeatures    `protobuf:"bytes,14,opt,name=qemuFeatures" json:"qemuFeatures,omitempty"`
 }
 
 func (x *MigrationHeader) Reset() {
 	*x = MigrationHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_migration_migrate_proto_msgTypes[7]
+		mi := &file_internal_migration_migrate_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -690,7 +754,7 @@ func (x *MigrationHeader) String() string {
 func (*MigrationHeader) ProtoMessage() {}
 
 func (x *MigrationHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_migration_migrate_proto_msgTypes[7]
+	mi := &file_internal_migration_migrate_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -703,7 +767,7 @@ func (x *MigrationHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MigrationHeader.ProtoReflect.Descriptor instead.
 func (*MigrationHeader) Descriptor() ([]byte, []int) {
-	return file_internal_migration_migrate_proto_rawDescGZIP(), []int{7}
+	return file_internal_migration_migrate_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *MigrationHeader) GetFs() MigrationFSType {
@@ -790,6 +854,13 @@ func (x *MigrationHeader) GetIndexHeaderVersion() uint32 {
 	return 0
 }
 
+func (x *MigrationHeader) GetQemuFeatures() *QemuFeatures {
+	if x != nil {
+		return x.QemuFeatures
+	}
+	return nil
+}
+
 type MigrationControl struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -803,7 +874,7 @@ type MigrationControl struct {
 func (x *MigrationControl) Reset() {
 	*x = MigrationControl{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_migration_migrate_proto_msgTypes[8]
+		mi := &file_internal_migration_migrate_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -816,7 +887,7 @@ func (x *MigrationControl) String() string {
 func (*MigrationControl) ProtoMessage() {}
 
 func (x *MigrationControl) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_migration_migrate_proto_msgTypes[8]
+	mi := &file_internal_migration_migrate_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -829,7 +900,7 @@ func (x *MigrationControl) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MigrationControl.ProtoReflect.Descriptor instead.
 func (*MigrationControl) Descriptor() ([]byte, []int) {
-	return file_internal_migration_migrate_proto_rawDescGZIP(), []int{8}
+	return file_internal_migration_migrate_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *MigrationControl) GetSuccess() bool {
@@ -857,7 +928,7 @@ type MigrationSync struct {
 func (x *MigrationSync) Reset() {
 	*x = MigrationSync{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_migration_migrate_proto_msgTypes[9]
+		mi := &file_internal_migration_migrate_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -870,7 +941,7 @@ func (x *MigrationSync) String() string {
 func (*MigrationSync) ProtoMessage() {}
 
 func (x *MigrationSync) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_migration_migrate_proto_msgTypes[9]
+	mi := &file_internal_migration_migrate_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -883,7 +954,7 @@ func (x *MigrationSync) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MigrationSync.ProtoReflect.Descriptor instead.
 func (*MigrationSync) Descriptor() ([]byte, []int) {
-	return file_internal_migration_migrate_proto_rawDescGZIP(), []int{9}
+	return file_internal_migration_migrate_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *MigrationSync) GetFinalPreDump() bool {
@@ -963,60 +1034,72 @@ var file_internal_migration_migrate_proto_rawDesc = []byte{
 	0x6d, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x16, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x73, 0x75,
 	0x62, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20,
 	0x01, 0x28, 0x08, 0x52, 0x14, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x53, 0x75, 0x62, 0x76, 0x6f,
-	0x6c, 0x75, 0x6d, 0x65, 0x55, 0x75, 0x69, 0x64, 0x73, 0x22, 0xa9, 0x04, 0x0a, 0x0f, 0x4d, 0x69,
-	0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x2a, 0x0a,
-	0x02, 0x66, 0x73, 0x18, 0x01, 0x20, 0x02, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6d, 0x69, 0x67, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46,
-	0x53, 0x54, 0x79, 0x70, 0x65, 0x52, 0x02, 0x66, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x63, 0x72, 0x69,
-	0x75, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x2e, 0x43, 0x52, 0x49, 0x55, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x63, 0x72,
-	0x69, 0x75, 0x12, 0x2a, 0x0a, 0x05, 0x69, 0x64, 0x6d, 0x61, 0x70, 0x18, 0x03, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x49, 0x44,
-	0x4d, 0x61, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x69, 0x64, 0x6d, 0x61, 0x70, 0x12, 0x24,
-	0x0a, 0x0d, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x18,
-	0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e,
-	0x61, 0x6d, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
-	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x09, 0x73, 0x6e,
-	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x64, 0x75,
-	0x6d, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x70, 0x72, 0x65, 0x64, 0x75, 0x6d,
-	0x70, 0x12, 0x3e, 0x0a, 0x0d, 0x72, 0x73, 0x79, 0x6e, 0x63, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72,
-	0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x69, 0x67, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x72, 0x73, 0x79, 0x6e, 0x63, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72,
-	0x65, 0x73, 0x52, 0x0d, 0x72, 0x73, 0x79, 0x6e, 0x63, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
-	0x73, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x18, 0x09, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x12, 0x38, 0x0a, 0x0b, 0x7a,
-	0x66, 0x73, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x16, 0x2e, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x7a, 0x66, 0x73,
-	0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x0b, 0x7a, 0x66, 0x73, 0x46, 0x65, 0x61,
-	0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x53,
-	0x69, 0x7a, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x76, 0x6f, 0x6c, 0x75, 0x6d,
-	0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x3e, 0x0a, 0x0d, 0x62, 0x74, 0x72, 0x66, 0x73, 0x46, 0x65,
-	0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d,
-	0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x62, 0x74, 0x72, 0x66, 0x73, 0x46, 0x65,
-	0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x0d, 0x62, 0x74, 0x72, 0x66, 0x73, 0x46, 0x65, 0x61,
-	0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x12, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x48, 0x65,
-	0x61, 0x64, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x12, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x56, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x46, 0x0a, 0x10, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x02, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63,
-	0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x33, 0x0a,
-	0x0d, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x22,
-	0x0a, 0x0c, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x72, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x18, 0x01,
-	0x20, 0x02, 0x28, 0x08, 0x52, 0x0c, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x72, 0x65, 0x44, 0x75,
-	0x6d, 0x70, 0x2a, 0x4e, 0x0a, 0x0f, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46,
-	0x53, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x53, 0x59, 0x4e, 0x43, 0x10, 0x00,
-	0x12, 0x09, 0x0a, 0x05, 0x42, 0x54, 0x52, 0x46, 0x53, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x5a,
-	0x46, 0x53, 0x10, 0x02, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x42, 0x44, 0x10, 0x03, 0x12, 0x13, 0x0a,
-	0x0f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x41, 0x4e, 0x44, 0x5f, 0x52, 0x53, 0x59, 0x4e, 0x43,
-	0x10, 0x04, 0x2a, 0x3c, 0x0a, 0x08, 0x43, 0x52, 0x49, 0x55, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0e,
-	0x0a, 0x0a, 0x43, 0x52, 0x49, 0x55, 0x5f, 0x52, 0x53, 0x59, 0x4e, 0x43, 0x10, 0x00, 0x12, 0x09,
-	0x0a, 0x05, 0x50, 0x48, 0x41, 0x55, 0x4c, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x4e, 0x4f, 0x4e,
-	0x45, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x56, 0x4d, 0x5f, 0x51, 0x45, 0x4d, 0x55, 0x10, 0x03,
-	0x42, 0x14, 0x5a, 0x12, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x6d, 0x69, 0x67,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x6c, 0x75, 0x6d, 0x65, 0x55, 0x75, 0x69, 0x64, 0x73, 0x22, 0x86, 0x01, 0x0a, 0x0c, 0x71, 0x65,
+	0x6d, 0x75, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x6d, 0x75,
+	0x6c, 0x74, 0x69, 0x66, 0x64, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x66, 0x64, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x2f, 0x0a, 0x13, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x66, 0x64,
+	0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x12, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x66, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x74, 0x63, 0x6f,
+	0x70, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x74, 0x63, 0x6f,
+	0x70, 0x79, 0x22, 0xe6, 0x04, 0x0a, 0x0f, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x02, 0x66, 0x73, 0x18, 0x01, 0x20, 0x02,
+	0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x4d,
+	0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x53, 0x54, 0x79, 0x70, 0x65, 0x52, 0x02,
+	0x66, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x63, 0x72, 0x69, 0x75, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x13, 0x2e, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x43, 0x52, 0x49,
+	0x55, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x63, 0x72, 0x69, 0x75, 0x12, 0x2a, 0x0a, 0x05, 0x69,
+	0x64, 0x6d, 0x61, 0x70, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x69, 0x67,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x49, 0x44, 0x4d, 0x61, 0x70, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x05, 0x69, 0x64, 0x6d, 0x61, 0x70, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d,
+	0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x31, 0x0a,
+	0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x64, 0x75, 0x6d, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x70, 0x72, 0x65, 0x64, 0x75, 0x6d, 0x70, 0x12, 0x3e, 0x0a, 0x0d, 0x72, 0x73,
+	0x79, 0x6e, 0x63, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x72, 0x73,
+	0x79, 0x6e, 0x63, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x0d, 0x72, 0x73, 0x79,
+	0x6e, 0x63, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65,
+	0x66, 0x72, 0x65, 0x73, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x12, 0x38, 0x0a, 0x0b, 0x7a, 0x66, 0x73, 0x46, 0x65, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x69, 0x67, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x7a, 0x66, 0x73, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x52, 0x0b, 0x7a, 0x66, 0x73, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1e,
+	0x0a, 0x0a, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0a, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x3e,
+	0x0a, 0x0d, 0x62, 0x74, 0x72, 0x66, 0x73, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2e, 0x62, 0x74, 0x72, 0x66, 0x73, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52,
+	0x0d, 0x62, 0x74, 0x72, 0x66, 0x73, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x2e,
+	0x0a, 0x12, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3b,
+	0x0a, 0x0c, 0x71, 0x65, 0x6d, 0x75, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x71, 0x65, 0x6d, 0x75, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x0c, 0x71,
+	0x65, 0x6d, 0x75, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x22, 0x46, 0x0a, 0x10, 0x4d,
+	0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x02, 0x28, 0x08,
+	0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x22, 0x33, 0x0a, 0x0d, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x53, 0x79, 0x6e, 0x63, 0x12, 0x22, 0x0a, 0x0c, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x72, 0x65,
+	0x44, 0x75, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x02, 0x28, 0x08, 0x52, 0x0c, 0x66, 0x69, 0x6e, 0x61,
+	0x6c, 0x50, 0x72, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x2a, 0x4e, 0x0a, 0x0f, 0x4d, 0x69, 0x67, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x53, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x52,
+	0x53, 0x59, 0x4e, 0x43, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x42, 0x54, 0x52, 0x46, 0x53, 0x10,
+	0x01, 0x12, 0x07, 0x0a, 0x03, 0x5a, 0x46, 0x53, 0x10, 0x02, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x42,
+	0x44, 0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x41, 0x4e, 0x44,
+	0x5f, 0x52, 0x53, 0x59, 0x4e, 0x43, 0x10, 0x04, 0x2a, 0x3c, 0x0a, 0x08, 0x43, 0x52, 0x49, 0x55,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x0a, 0x43, 0x52, 0x49, 0x55, 0x5f, 0x52, 0x53, 0x59,
+	0x4e, 0x43, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x50, 0x48, 0x41, 0x55, 0x4c, 0x10, 0x01, 0x12,
+	0x08, 0x0a, 0x04, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x56, 0x4d, 0x5f,
+	0x51, 0x45, 0x4d, 0x55, 0x10, 0x03, 0x42, 0x14, 0x5a, 0x12, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x2f, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
 }
 
 var (
@@ -1032,7 +1115,7 @@ func file_internal_migration_migrate_proto_rawDescGZIP() []byte {
 }
 
 var file_internal_migration_migrate_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_internal_migration_migrate_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_internal_migration_migrate_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_internal_migration_migrate_proto_goTypes = []interface{}{
 	(MigrationFSType)(0),     // 0: migration.MigrationFSType
 	(CRIUType)(0),            // 1: migration.CRIUType
@@ -1043,9 +1126,10 @@ var file_internal_migration_migrate_proto_goTypes = []interface{}{
 	(*RsyncFeatures)(nil),    // 6: migration.rsyncFeatures
 	(*ZfsFeatures)(nil),      // 7: migration.zfsFeatures
 	(*BtrfsFeatures)(nil),    // 8: migration.btrfsFeatures
-	(*MigrationHeader)(nil),  // 9: migration.MigrationHeader
-	(*MigrationControl)(nil), // 10: migration.MigrationControl
-	(*MigrationSync)(nil),    // 11: migration.MigrationSync
+	(*QemuFeatures)(nil),     // 9: migration.qemuFeatures
+	(*MigrationHeader)(nil),  // 10: migration.MigrationHeader
+	(*MigrationControl)(nil), // 11: migration.MigrationControl
+	(*MigrationSync)(nil),    // 12: migration.MigrationSync
 }
 var file_internal_migration_migrate_proto_depIdxs = []int32{
 	3,  // 0: migration.Device.config:type_name -> migration.Config
@@ -1058,11 +1142,12 @@ var file_internal_migration_migrate_proto_depIdxs = []int32{
 	6,  // 7: migration.MigrationHeader.rsyncFeatures:type_name -> migration.rsyncFeatures
 	7,  // 8: migration.MigrationHeader.zfsFeatures:type_name -> migration.zfsFeatures
 	8,  // 9: migration.MigrationHeader.btrfsFeatures:type_name -> migration.btrfsFeatures
-	10, // [10:10] is the sub-list for method output_type
-	10, // [10:10] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	9,  // 10: migration.MigrationHeader.qemuFeatures:type_name -> migration.qemuFeatures
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_internal_migration_migrate_proto_init() }
@@ -1156,7 +1241,7 @@ func file_internal_migration_migrate_proto_init() {
 			}
 		}
 		file_internal_migration_migrate_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MigrationHeader); i {
+			switch v := v.(*QemuFeatures); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1168,7 +1253,7 @@ func file_internal_migration_migrate_proto_init() {
 			}
 		}
 		file_internal_migration_migrate_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MigrationControl); i {
+			switch v := v.(*MigrationHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1180,6 +1265,18 @@ func file_internal_migration_migrate_proto_init() {
 			}
 		}
 		file_internal_migration_migrate_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MigrationControl); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_migration_migrate_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*MigrationSync); i {
 			case 0:
 				return &v.state
@@ -1198,7 +1295,7 @@ func file_internal_migration_migrate_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_internal_migration_migrate_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   10,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,218 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Outcome values of an audit record.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeDenied  = "denied"
+	OutcomeUnknown = "unknown"
+)
+
+// Record represents a single audited API request.
+type Record struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Location   string    `json:"location,omitempty"`
+	Username   string    `json:"username"`
+	Protocol   string    `json:"protocol"`
+	Address    string    `json:"address"`
+	Method     string    `json:"method"`
+	URL        string    `json:"url"`
+	Project    string    `json:"project,omitempty"`
+	BodyDigest string    `json:"body_digest,omitempty"`
+	Operation  string    `json:"operation,omitempty"`
+	Outcome    string    `json:"outcome"`
+	StatusCode int       `json:"status_code"`
+}
+
+// OutcomeFromStatusCode returns the outcome matching an HTTP status code.
+func OutcomeFromStatusCode(statusCode int) string {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return OutcomeDenied
+	case statusCode >= http.StatusBadRequest:
+		return OutcomeFailure
+	default:
+		return OutcomeSuccess
+	}
+}
+
+// OutcomeFromOperationStatus returns the outcome matching the final status of an operation.
+func OutcomeFromOperationStatus(status api.StatusCode) string {
+	if status == api.Success {
+		return OutcomeSuccess
+	}
+
+	return OutcomeFailure
+}
+
+// Handler is a function called for every audit record.
+type Handler func(record Record)
+
+// Logger writes audit records to a size-rotated file and passes them to the registered handlers.
+type Logger struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	file     *os.File
+	size     int64
+	handlers map[string]Handler
+	lock     sync.Mutex
+}
+
+// NewLogger returns a disabled Logger.
+func NewLogger() *Logger {
+	return &Logger{
+		handlers: map[string]Handler{},
+	}
+}
+
+// Configure enables the logger, writing to the given path.
+// The file is rotated once it reaches maxSize bytes and at most maxFiles rotated files are kept.
+func (l *Logger) Configure(path string, maxSize int64, maxFiles int) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.file != nil {
+		_ = l.file.Close()
+		l.file = nil
+	}
+
+	l.path = path
+	l.maxSize = maxSize
+	l.maxFiles = maxFiles
+
+	return l.open()
+}
+
+// Disable closes the audit file and stops recording.
+func (l *Logger) Disable() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+
+	err := l.file.Close()
+	l.file = nil
+
+	return err
+}
+
+// Enabled returns whether records are currently being recorded.
+func (l *Logger) Enabled() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.file != nil
+}
+
+// AddHandler registers a handler which is passed all future audit records.
+func (l *Logger) AddHandler(name string, handler Handler) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.handlers[name] = handler
+}
+
+// RemoveHandler removes a handler.
+func (l *Logger) RemoveHandler(name string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	delete(l.handlers, name)
+}
+
+// Log records an audit record.
+func (l *Logger) Log(record Record) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+
+	for _, handler := range l.handlers {
+		go handler(record)
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	line = append(line, '\n')
+
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		err = l.rotate()
+		if err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("Failed writing audit record: %w", err)
+	}
+
+	return nil
+}
+
+// open opens the audit file for appending.
+func (l *Logger) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed opening audit file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	l.file = f
+	l.size = info.Size()
+
+	return nil
+}
+
+// rotate shifts the existing files (audit.log becoming audit.log.1 and so on) and opens a new file.
+func (l *Logger) rotate() error {
+	err := l.file.Close()
+	l.file = nil
+	if err != nil {
+		return err
+	}
+
+	if l.maxFiles > 0 {
+		_ = os.Remove(fmt.Sprintf("%s.%d", l.path, l.maxFiles))
+
+		for i := l.maxFiles - 1; i > 0; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+		}
+
+		err = os.Rename(l.path, l.path+".1")
+	} else {
+		err = os.Remove(l.path)
+	}
+
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Failed rotating audit file: %w", err)
+	}
+
+	return l.open()
+}
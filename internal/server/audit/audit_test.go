@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestLoggerRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l := NewLogger()
+	assert.False(t, l.Enabled())
+
+	// Records are dropped while disabled.
+	require.NoError(t, l.Log(Record{URL: "/1.0/instances"}))
+
+	require.NoError(t, l.Configure(path, 200, 2))
+	assert.True(t, l.Enabled())
+
+	handled := make(chan Record, 10)
+	l.AddHandler("test", func(record Record) { handled <- record })
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, l.Log(Record{Username: "user", Method: http.MethodPost, URL: "/1.0/instances", StatusCode: http.StatusAccepted}))
+	}
+
+	require.NoError(t, l.Disable())
+
+	for i := 0; i < 5; i++ {
+		<-handled
+	}
+
+	// Each record is larger than half the maximum size, so every record ends up in its own file.
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		f, err := os.Open(name)
+		require.NoError(t, err)
+
+		scanner := bufio.NewScanner(f)
+		require.True(t, scanner.Scan())
+
+		var record Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		assert.Equal(t, "/1.0/instances", record.URL)
+		assert.False(t, scanner.Scan())
+
+		_ = f.Close()
+	}
+
+	_, err := os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/1.0/instances", strings.NewReader(`{"name": "c1"}`))
+	rec := httptest.NewRecorder()
+
+	req, w := NewRequest(rec, r)
+	assert.Equal(t, "", req.Digest())
+
+	_, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+
+	w.WriteHeader(http.StatusForbidden)
+
+	assert.Equal(t, "1798293093ea2637b37bd8c53c79e48de5ea73810c46b16c99fe2ea26296dc50", req.Digest())
+	assert.Equal(t, http.StatusForbidden, req.StatusCode())
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, OutcomeDenied, OutcomeFromStatusCode(req.StatusCode()))
+	assert.Equal(t, OutcomeFailure, OutcomeFromStatusCode(http.StatusNotFound))
+	assert.Equal(t, OutcomeSuccess, OutcomeFromStatusCode(http.StatusAccepted))
+}
+
+func TestRequestOperation(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		location   string
+		expected   string
+	}{
+		{
+			name:       "Operation",
+			statusCode: http.StatusAccepted,
+			location:   "/1.0/operations/0e3b9f2a-3f2c-4b4c-9d2a-6b7c1e5f8a90",
+			expected:   "0e3b9f2a-3f2c-4b4c-9d2a-6b7c1e5f8a90",
+		},
+		{
+			name:       "Operation in a project",
+			statusCode: http.StatusAccepted,
+			location:   "/1.0/operations/0e3b9f2a-3f2c-4b4c-9d2a-6b7c1e5f8a90?project=ci",
+			expected:   "0e3b9f2a-3f2c-4b4c-9d2a-6b7c1e5f8a90",
+		},
+		{
+			name:       "Synchronous response",
+			statusCode: http.StatusCreated,
+			location:   "/1.0/projects/ci",
+		},
+		{
+			name:       "Accepted without operation",
+			statusCode: http.StatusAccepted,
+			location:   "/1.0/instances/c1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/1.0/instances", nil)
+			req, w := NewRequest(httptest.NewRecorder(), r)
+
+			w.Header().Set("Location", tt.location)
+			w.WriteHeader(tt.statusCode)
+
+			assert.Equal(t, tt.expected, req.Operation())
+		})
+	}
+}
+
+func TestOutcomeFromOperationStatus(t *testing.T) {
+	assert.Equal(t, OutcomeSuccess, OutcomeFromOperationStatus(api.Success))
+	assert.Equal(t, OutcomeFailure, OutcomeFromOperationStatus(api.Failure))
+	assert.Equal(t, OutcomeFailure, OutcomeFromOperationStatus(api.Cancelled))
+}
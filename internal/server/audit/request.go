@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/lxc/incus/v6/internal/version"
+)
+
+// Request tracks the request body digest and the response status code of an audited API request.
+type Request struct {
+	http.ResponseWriter
+
+	hash       hash.Hash
+	body       io.ReadCloser
+	read       bool
+	statusCode int
+}
+
+// NewRequest wraps the response writer and the body of the request so they can be audited.
+func NewRequest(w http.ResponseWriter, r *http.Request) (*Request, http.ResponseWriter) {
+	req := &Request{
+		ResponseWriter: w,
+		hash:           sha256.New(),
+		body:           r.Body,
+	}
+
+	if r.Body != nil {
+		r.Body = &bodyReader{req: req}
+	}
+
+	return req, req
+}
+
+// Digest returns the SHA-256 digest of the part of the request body read by the server.
+func (r *Request) Digest() string {
+	if !r.read {
+		return ""
+	}
+
+	return hex.EncodeToString(r.hash.Sum(nil))
+}
+
+// StatusCode returns the status code sent to the client.
+func (r *Request) StatusCode() int {
+	if r.statusCode == 0 {
+		return http.StatusOK
+	}
+
+	return r.statusCode
+}
+
+// Operation returns the ID of the operation returned to the client, if any.
+func (r *Request) Operation() string {
+	if r.statusCode != http.StatusAccepted {
+		return ""
+	}
+
+	u, err := url.Parse(r.Header().Get("Location"))
+	if err != nil {
+		return ""
+	}
+
+	dir, id := path.Split(u.Path)
+	if dir != "/"+version.APIVersion+"/operations/" {
+		return ""
+	}
+
+	return id
+}
+
+// WriteHeader records the status code and sends it to the client.
+func (r *Request) WriteHeader(statusCode int) {
+	if r.statusCode == 0 {
+		r.statusCode = statusCode
+	}
+
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Flush sends any buffered data to the client.
+func (r *Request) Flush() {
+	flusher, ok := r.ResponseWriter.(http.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection.
+func (r *Request) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("Response writer doesn't support hijacking")
+	}
+
+	if r.statusCode == 0 {
+		r.statusCode = http.StatusSwitchingProtocols
+	}
+
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the original response writer.
+func (r *Request) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// bodyReader feeds the request body to the digest as it's read.
+type bodyReader struct {
+	req *Request
+}
+
+// Read reads from the request body.
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.req.body.Read(p)
+	if n > 0 {
+		b.req.read = true
+		_, _ = b.req.hash.Write(p[:n])
+	}
+
+	return n, err
+}
+
+// Close closes the request body.
+func (b *bodyReader) Close() error {
+	return b.req.body.Close()
+}
@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+)
+
+// NewSyslogHandler returns a handler writing the audit records to the local syslog daemon.
+func NewSyslogHandler() (Handler, func() error, error) {
+	writer, err := syslog.New(syslog.LOG_AUTHPRIV|syslog.LOG_NOTICE, "incus-audit")
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed connecting to syslog: %w", err)
+	}
+
+	handler := func(record Record) {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return
+		}
+
+		_ = writer.Notice(string(line))
+	}
+
+	return handler, writer.Close, nil
+}
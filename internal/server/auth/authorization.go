@@ -19,6 +19,9 @@ const (
 
 	// DriverScriptlet provides scriptlet-based authorization. It is compatible with any authentication method.
 	DriverScriptlet string = "scriptlet"
+
+	// DriverRBAC provides role-based access control backed by the cluster database. It is compatible with any authentication method.
+	DriverRBAC string = "rbac"
 )
 
 // ErrUnknownDriver is the "Unknown driver" error.
@@ -28,6 +31,7 @@ var authorizers = map[string]func() authorizer{
 	DriverTLS:       func() authorizer { return &TLS{} },
 	DriverOpenFGA:   func() authorizer { return &FGA{} },
 	DriverScriptlet: func() authorizer { return &Scriptlet{} },
+	DriverRBAC:      func() authorizer { return &RBAC{} },
 }
 
 type authorizer interface {
@@ -86,6 +90,10 @@ type Authorizer interface {
 	DeleteNetworkACL(ctx context.Context, projectName string, networkACLName string) error
 	RenameNetworkACL(ctx context.Context, projectName string, oldNetworkACLName string, newNetworkACLName string) error
 
+	AddNetworkAddressSet(ctx context.Context, projectName string, networkAddressSetName string) error
+	DeleteNetworkAddressSet(ctx context.Context, projectName string, networkAddressSetName string) error
+	RenameNetworkAddressSet(ctx context.Context, projectName string, oldNetworkAddressSetName string, newNetworkAddressSetName string) error
+
 	AddProfile(ctx context.Context, projectName string, profileName string) error
 	DeleteProfile(ctx context.Context, projectName string, profileName string) error
 	RenameProfile(ctx context.Context, projectName string, oldProfileName string, newProfileName string) error
@@ -107,6 +115,13 @@ type Opts struct {
 	config          map[string]any
 	projectsGetFunc func(ctx context.Context) (map[int64]string, error)
 	resourcesFunc   func() (*Resources, error)
+
+	identitiesFunc         func(ctx context.Context, authMethod string, identifier string) ([]Identity, error)
+	permissionsRewriteFunc func(ctx context.Context, rewrite func(object string) (string, bool)) error
+
+	identityProviderGroupsFunc func(ctx context.Context, identityProviderGroups []string) ([]api.AuthPermission, error)
+
+	authTokenFunc func(ctx context.Context, id string) (*api.AuthToken, error)
 }
 
 // Resources represents a set of current API resources as Object slices for use when loading an Authorizer.
@@ -119,6 +134,7 @@ type Resources struct {
 	InstanceObjects          []Object
 	NetworkObjects           []Object
 	NetworkACLObjects        []Object
+	NetworkAddressSetObjects []Object
 	NetworkZoneObjects       []Object
 	ProfileObjects           []Object
 	StoragePoolVolumeObjects []Object
@@ -146,6 +162,38 @@ func WithResourcesFunc(f func() (*Resources, error)) func(*Opts) {
 	}
 }
 
+// WithIdentitiesFunc must be passed into LoadAuthorizer when DriverRBAC is used.
+// The function returns the matching identities along with their permissions, or all of them if no filter is given.
+func WithIdentitiesFunc(f func(ctx context.Context, authMethod string, identifier string) ([]Identity, error)) func(*Opts) {
+	return func(o *Opts) {
+		o.identitiesFunc = f
+	}
+}
+
+// WithPermissionsRewriteFunc should be passed into LoadAuthorizer when DriverRBAC is used.
+// The function applies the rewrite to the object of every stored permission, removing those that aren't kept.
+func WithPermissionsRewriteFunc(f func(ctx context.Context, rewrite func(object string) (string, bool)) error) func(*Opts) {
+	return func(o *Opts) {
+		o.permissionsRewriteFunc = f
+	}
+}
+
+// WithIdentityProviderGroupsFunc should be passed into LoadAuthorizer when DriverRBAC is used.
+// The function returns the permissions granted through the groups mapped to the identity provider groups.
+func WithIdentityProviderGroupsFunc(f func(ctx context.Context, identityProviderGroups []string) ([]api.AuthPermission, error)) func(*Opts) {
+	return func(o *Opts) {
+		o.identityProviderGroupsFunc = f
+	}
+}
+
+// WithAuthTokenFunc should be passed into LoadAuthorizer so that requests made with scoped API tokens can be authorized.
+// The function returns the scoped API token with the given identifier.
+func WithAuthTokenFunc(f func(ctx context.Context, id string) (*api.AuthToken, error)) func(*Opts) {
+	return func(o *Opts) {
+		o.authTokenFunc = f
+	}
+}
+
 // LoadAuthorizer instantiates, configures, and initializes an Authorizer.
 func LoadAuthorizer(ctx context.Context, driver string, logger logger.Logger, certificateCache *certificate.Cache, options ...func(opts *Opts)) (Authorizer, error) {
 	opts := &Opts{}
@@ -107,6 +107,7 @@ type objectValidator struct {
 
 var objectValidators = map[ObjectType]objectValidator{
 	ObjectTypeUser:               {minIdentifierElements: 1, maxIdentifierElements: 1, requireProject: false},
+	ObjectTypeGroup:              {minIdentifierElements: 1, maxIdentifierElements: 1, requireProject: false},
 	ObjectTypeServer:             {minIdentifierElements: 1, maxIdentifierElements: 1, requireProject: false},
 	ObjectTypeCertificate:        {minIdentifierElements: 1, maxIdentifierElements: 1, requireProject: false},
 	ObjectTypeStoragePool:        {minIdentifierElements: 1, maxIdentifierElements: 1, requireProject: false},
@@ -116,6 +117,7 @@ var objectValidators = map[ObjectType]objectValidator{
 	ObjectTypeInstance:           {minIdentifierElements: 1, maxIdentifierElements: 1, requireProject: true},
 	ObjectTypeNetwork:            {minIdentifierElements: 1, maxIdentifierElements: 1, requireProject: true},
 	ObjectTypeNetworkACL:         {minIdentifierElements: 1, maxIdentifierElements: 1, requireProject: true},
+	ObjectTypeNetworkAddressSet:  {minIdentifierElements: 1, maxIdentifierElements: 1, requireProject: true},
 	ObjectTypeNetworkIntegration: {minIdentifierElements: 1, maxIdentifierElements: 1, requireProject: false},
 	ObjectTypeNetworkZone:        {minIdentifierElements: 1, maxIdentifierElements: 1, requireProject: true},
 	ObjectTypeProfile:            {minIdentifierElements: 1, maxIdentifierElements: 1, requireProject: true},
@@ -253,6 +255,12 @@ func ObjectUser(userName string) Object {
 	return object
 }
 
+// ObjectGroup represents a group of users.
+func ObjectGroup(groupName string) Object {
+	object, _ := NewObject(ObjectTypeGroup, "", groupName)
+	return object
+}
+
 // ObjectServer represents a server.
 func ObjectServer() Object {
 	object, _ := NewObject(ObjectTypeServer, "", "incus")
@@ -307,6 +315,12 @@ func ObjectNetworkACL(projectName string, networkACLName string) Object {
 	return object
 }
 
+// ObjectNetworkAddressSet represents a network address set.
+func ObjectNetworkAddressSet(projectName string, networkAddressSetName string) Object {
+	object, _ := NewObject(ObjectTypeNetworkAddressSet, projectName, networkAddressSetName)
+	return object
+}
+
 // ObjectNetworkIntegration represents a network integration.
 func ObjectNetworkIntegration(networkIntegrationName string) Object {
 	object, _ := NewObject(ObjectTypeNetworkIntegration, "", networkIntegrationName)
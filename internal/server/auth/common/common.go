@@ -2,8 +2,9 @@ package common
 
 // RequestDetails is a type representing an authorization request.
 type RequestDetails struct {
-	Username             string
-	Protocol             string
-	IsAllProjectsRequest bool
-	ProjectName          string
+	Username               string
+	Protocol               string
+	IdentityProviderGroups []string
+	IsAllProjectsRequest   bool
+	ProjectName            string
 }
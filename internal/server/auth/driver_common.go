@@ -32,8 +32,9 @@ func (c *commonAuthorizer) init(driverName string, l logger.Logger) error {
 type requestDetails struct {
 	common.RequestDetails
 
-	forwardedUsername string
-	forwardedProtocol string
+	forwardedUsername               string
+	forwardedProtocol               string
+	forwardedIdentityProviderGroups []string
 }
 
 func (r *requestDetails) isInternalOrUnix() bool {
@@ -64,12 +65,21 @@ func (r *requestDetails) authenticationProtocol() string {
 	return r.Protocol
 }
 
+func (r *requestDetails) identityProviderGroups() []string {
+	if r.Protocol == "cluster" {
+		return r.forwardedIdentityProviderGroups
+	}
+
+	return r.IdentityProviderGroups
+}
+
 func (r *requestDetails) actualDetails() *common.RequestDetails {
 	return &common.RequestDetails{
-		Username:             r.username(),
-		Protocol:             r.authenticationProtocol(),
-		IsAllProjectsRequest: r.IsAllProjectsRequest,
-		ProjectName:          r.ProjectName,
+		Username:               r.username(),
+		Protocol:               r.authenticationProtocol(),
+		IdentityProviderGroups: r.identityProviderGroups(),
+		IsAllProjectsRequest:   r.IsAllProjectsRequest,
+		ProjectName:            r.ProjectName,
 	}
 }
 
@@ -118,6 +128,24 @@ func (c *commonAuthorizer) requestDetails(r *http.Request) (*requestDetails, err
 		}
 	}
 
+	var identityProviderGroups []string
+	val = r.Context().Value(request.CtxIdentityProviderGroups)
+	if val != nil {
+		identityProviderGroups, ok = val.([]string)
+		if !ok {
+			return nil, fmt.Errorf("Request context identity provider groups has incorrect type")
+		}
+	}
+
+	var forwardedIdentityProviderGroups []string
+	val = r.Context().Value(request.CtxForwardedIdentityProviderGroups)
+	if val != nil {
+		forwardedIdentityProviderGroups, ok = val.([]string)
+		if !ok {
+			return nil, fmt.Errorf("Request context forwarded identity provider groups has incorrect type")
+		}
+	}
+
 	values, err := url.ParseQuery(r.URL.RawQuery)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to parse request query parameters: %w", err)
@@ -125,14 +153,16 @@ func (c *commonAuthorizer) requestDetails(r *http.Request) (*requestDetails, err
 
 	return &requestDetails{
 		RequestDetails: common.RequestDetails{
-			Username:             username,
-			Protocol:             protocol,
-			IsAllProjectsRequest: util.IsTrue(values.Get("all-projects")),
-			ProjectName:          request.ProjectParam(r),
+			Username:               username,
+			Protocol:               protocol,
+			IdentityProviderGroups: identityProviderGroups,
+			IsAllProjectsRequest:   util.IsTrue(values.Get("all-projects")),
+			ProjectName:            request.ProjectParam(r),
 		},
 
-		forwardedUsername: forwardedUsername,
-		forwardedProtocol: forwardedProtocol,
+		forwardedUsername:               forwardedUsername,
+		forwardedProtocol:               forwardedProtocol,
+		forwardedIdentityProviderGroups: forwardedIdentityProviderGroups,
 	}, nil
 }
 
@@ -280,6 +310,21 @@ func (c *commonAuthorizer) RenameNetworkACL(ctx context.Context, projectName str
 	return nil
 }
 
+// AddNetworkAddressSet is a no-op.
+func (c *commonAuthorizer) AddNetworkAddressSet(ctx context.Context, projectName string, networkAddressSetName string) error {
+	return nil
+}
+
+// DeleteNetworkAddressSet is a no-op.
+func (c *commonAuthorizer) DeleteNetworkAddressSet(ctx context.Context, projectName string, networkAddressSetName string) error {
+	return nil
+}
+
+// RenameNetworkAddressSet is a no-op.
+func (c *commonAuthorizer) RenameNetworkAddressSet(ctx context.Context, projectName string, oldNetworkAddressSetName string, newNetworkAddressSetName string) error {
+	return nil
+}
+
 // AddProfile is a no-op.
 func (c *commonAuthorizer) AddProfile(ctx context.Context, projectName string, profileName string) error {
 	return nil
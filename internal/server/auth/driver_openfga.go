@@ -276,8 +276,8 @@ func (f *FGA) CheckPermission(ctx context.Context, r *http.Request, object Objec
 		return nil
 	}
 
-	// Use the TLS driver if the user authenticated with TLS.
-	if details.authenticationProtocol() == api.AuthenticationMethodTLS {
+	// Use the TLS driver if the user authenticated with TLS or with an API token.
+	if details.authenticationProtocol() == api.AuthenticationMethodTLS || details.authenticationProtocol() == api.AuthenticationMethodToken {
 		return f.tls.CheckPermission(ctx, r, object, entitlement)
 	}
 
@@ -294,9 +294,10 @@ func (f *FGA) CheckPermission(ctx context.Context, r *http.Request, object Objec
 
 	objectUser := ObjectUser(username)
 	body := client.ClientCheckRequest{
-		User:     objectUser.String(),
-		Relation: string(entitlement),
-		Object:   object.String(),
+		User:             objectUser.String(),
+		Relation:         string(entitlement),
+		Object:           object.String(),
+		ContextualTuples: f.groupTuples(username, details.identityProviderGroups()),
 	}
 
 	f.logger.Debug("Checking OpenFGA relation", logCtx)
@@ -312,6 +313,24 @@ func (f *FGA) CheckPermission(ctx context.Context, r *http.Request, object Objec
 	return nil
 }
 
+// groupTuples returns the contextual tuples making the user a member of its identity provider groups.
+func (f *FGA) groupTuples(username string, groups []string) []client.ClientContextualTupleKey {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	tuples := make([]client.ClientContextualTupleKey, 0, len(groups))
+	for _, group := range groups {
+		tuples = append(tuples, client.ClientContextualTupleKey{
+			User:     ObjectUser(username).String(),
+			Relation: "member",
+			Object:   ObjectGroup(group).String(),
+		})
+	}
+
+	return tuples
+}
+
 // GetPermissionChecker returns a function that can be used to check whether a user has the required entitlement on an authorization object.
 func (f *FGA) GetPermissionChecker(ctx context.Context, r *http.Request, entitlement Entitlement, objectType ObjectType) (PermissionChecker, error) {
 	allowFunc := func(b bool) func(Object) bool {
@@ -330,8 +349,8 @@ func (f *FGA) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 		return allowFunc(true), nil
 	}
 
-	// Use the TLS driver if the user authenticated with TLS.
-	if details.authenticationProtocol() == api.AuthenticationMethodTLS {
+	// Use the TLS driver if the user authenticated with TLS or with an API token.
+	if details.authenticationProtocol() == api.AuthenticationMethodTLS || details.authenticationProtocol() == api.AuthenticationMethodToken {
 		return f.tls.GetPermissionChecker(ctx, r, entitlement, objectType)
 	}
 
@@ -341,9 +360,10 @@ func (f *FGA) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 
 	f.logger.Debug("Listing related objects for user", logCtx)
 	resp, err := f.client.ListObjects(ctx).Body(client.ClientListObjectsRequest{
-		User:     ObjectUser(username).String(),
-		Relation: string(entitlement),
-		Type:     string(objectType),
+		User:             ObjectUser(username).String(),
+		Relation:         string(entitlement),
+		Type:             string(objectType),
+		ContextualTuples: f.groupTuples(username, details.identityProviderGroups()),
 	}).Execute()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to OpenFGA objects of type %q with relation %q for user %q: %w", objectType, entitlement, username, err)
@@ -766,6 +786,53 @@ func (f *FGA) RenameNetworkACL(ctx context.Context, projectName string, oldNetwo
 	return f.updateTuples(ctx, writes, deletions)
 }
 
+// AddNetworkAddressSet adds a network address set in the authorizer.
+func (f *FGA) AddNetworkAddressSet(ctx context.Context, projectName string, networkAddressSetName string) error {
+	writes := []client.ClientTupleKey{
+		{
+			User:     ObjectProject(projectName).String(),
+			Relation: relationProject,
+			Object:   ObjectNetworkAddressSet(projectName, networkAddressSetName).String(),
+		},
+	}
+
+	return f.updateTuples(ctx, writes, nil)
+}
+
+// DeleteNetworkAddressSet deletes a network address set from the authorizer.
+func (f *FGA) DeleteNetworkAddressSet(ctx context.Context, projectName string, networkAddressSetName string) error {
+	deletions := []client.ClientTupleKeyWithoutCondition{
+		{
+			User:     ObjectProject(projectName).String(),
+			Relation: relationProject,
+			Object:   ObjectNetworkAddressSet(projectName, networkAddressSetName).String(),
+		},
+	}
+
+	return f.updateTuples(ctx, nil, deletions)
+}
+
+// RenameNetworkAddressSet renames a network address set in the authorizer.
+func (f *FGA) RenameNetworkAddressSet(ctx context.Context, projectName string, oldNetworkAddressSetName string, newNetworkAddressSetName string) error {
+	writes := []client.ClientTupleKey{
+		{
+			User:     ObjectProject(projectName).String(),
+			Relation: relationProject,
+			Object:   ObjectNetworkAddressSet(projectName, newNetworkAddressSetName).String(),
+		},
+	}
+
+	deletions := []client.ClientTupleKeyWithoutCondition{
+		{
+			User:     ObjectProject(projectName).String(),
+			Relation: relationProject,
+			Object:   ObjectNetworkAddressSet(projectName, oldNetworkAddressSetName).String(),
+		},
+	}
+
+	return f.updateTuples(ctx, writes, deletions)
+}
+
 // AddProfile adds a profile in the authorizer.
 func (f *FGA) AddProfile(ctx context.Context, projectName string, profileName string) error {
 	writes := []client.ClientTupleKey{
@@ -956,6 +1023,7 @@ func (f *FGA) projectObjects(ctx context.Context, projectName string) ([]string,
 		ObjectTypeImageAlias,
 		ObjectTypeNetwork,
 		ObjectTypeNetworkACL,
+		ObjectTypeNetworkAddressSet,
 		ObjectTypeNetworkZone,
 		ObjectTypeProfile,
 		ObjectTypeStorageVolume,
@@ -1098,6 +1166,7 @@ func (f *FGA) syncResources(ctx context.Context, resources Resources) error {
 	localProjectObjects = append(localProjectObjects, resources.NetworkObjects...)
 	localProjectObjects = append(localProjectObjects, resources.NetworkZoneObjects...)
 	localProjectObjects = append(localProjectObjects, resources.NetworkACLObjects...)
+	localProjectObjects = append(localProjectObjects, resources.NetworkAddressSetObjects...)
 	localProjectObjects = append(localProjectObjects, resources.ProfileObjects...)
 	localProjectObjects = append(localProjectObjects, resources.StoragePoolVolumeObjects...)
 	localProjectObjects = append(localProjectObjects, resources.StorageBucketObjects...)
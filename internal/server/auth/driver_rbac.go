@@ -0,0 +1,542 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	openfga "github.com/openfga/go-sdk"
+
+	"github.com/lxc/incus/v6/internal/server/certificate"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// rbacMaxDepth is the maximum depth of relation rewrites followed when evaluating an entitlement.
+const rbacMaxDepth = 32
+
+// Identity represents an identity known to the RBAC authorizer along with the permissions granted to it.
+type Identity struct {
+	AuthMethod  string
+	Identifier  string
+	Permissions []api.AuthPermission
+}
+
+// RBAC represents a role-based access control authorizer backed by the cluster database.
+// It evaluates the same authorization model as the OpenFGA driver but without an external server.
+type RBAC struct {
+	commonAuthorizer
+	tls *TLS
+
+	identitiesFunc         func(ctx context.Context, authMethod string, identifier string) ([]Identity, error)
+	permissionsRewriteFunc func(ctx context.Context, rewrite func(object string) (string, bool)) error
+
+	identityProviderGroupsFunc func(ctx context.Context, identityProviderGroups []string) ([]api.AuthPermission, error)
+
+	typeDefinitions map[ObjectType]openfga.TypeDefinition
+}
+
+func (r *RBAC) load(ctx context.Context, certificateCache *certificate.Cache, opts Opts) error {
+	if opts.identitiesFunc == nil {
+		return errors.New("RBAC authorization driver requires an identities function")
+	}
+
+	r.identitiesFunc = opts.identitiesFunc
+	r.permissionsRewriteFunc = opts.permissionsRewriteFunc
+	r.identityProviderGroupsFunc = opts.identityProviderGroupsFunc
+
+	var err error
+	r.typeDefinitions, err = rbacTypeDefinitions()
+	if err != nil {
+		return err
+	}
+
+	// Use the TLS driver for TLS clients without an identity.
+	r.tls = &TLS{}
+	err = r.tls.init(DriverTLS, r.logger)
+	if err != nil {
+		return err
+	}
+
+	return r.tls.load(ctx, certificateCache, opts)
+}
+
+// rbacTypeDefinitions returns the type definitions of the built-in authorization model.
+func rbacTypeDefinitions() (map[ObjectType]openfga.TypeDefinition, error) {
+	var model openfga.WriteAuthorizationModelRequest
+	err := json.Unmarshal([]byte(authModel), &model)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal built in authorization model: %w", err)
+	}
+
+	typeDefinitions := make(map[ObjectType]openfga.TypeDefinition, len(model.TypeDefinitions))
+	for _, typeDefinition := range model.TypeDefinitions {
+		typeDefinitions[ObjectType(typeDefinition.Type)] = typeDefinition
+	}
+
+	return typeDefinitions, nil
+}
+
+// ValidatePermission checks that the entitlement can be directly granted on the object.
+func ValidatePermission(permission api.AuthPermission) error {
+	typeDefinitions, err := rbacTypeDefinitions()
+	if err != nil {
+		return err
+	}
+
+	object, err := ObjectFromString(permission.Object)
+	if err != nil {
+		return fmt.Errorf("Invalid object %q: %w", permission.Object, err)
+	}
+
+	if object.Type() == ObjectTypeUser || object.Type() == ObjectTypeGroup {
+		return fmt.Errorf("Invalid object %q: Permissions cannot be granted on users or groups", permission.Object)
+	}
+
+	if !slices.Contains(rbacDirectRelations(typeDefinitions[object.Type()]), permission.Entitlement) {
+		return fmt.Errorf("Entitlement %q cannot be granted on objects of type %q", permission.Entitlement, object.Type())
+	}
+
+	return nil
+}
+
+// rbacDirectRelations returns the relations of a type that can be directly granted to users.
+func rbacDirectRelations(typeDefinition openfga.TypeDefinition) []string {
+	relations := []string{}
+
+	if typeDefinition.Metadata == nil || typeDefinition.Metadata.Relations == nil {
+		return relations
+	}
+
+	for relation, metadata := range *typeDefinition.Metadata.Relations {
+		if metadata.DirectlyRelatedUserTypes == nil {
+			continue
+		}
+
+		for _, reference := range *metadata.DirectlyRelatedUserTypes {
+			if reference.Type == string(ObjectTypeUser) && reference.Wildcard == nil && reference.Relation == nil {
+				relations = append(relations, relation)
+				break
+			}
+		}
+	}
+
+	return relations
+}
+
+// identity returns the identity behind the request or nil if none is recorded.
+func (r *RBAC) identity(ctx context.Context, details *requestDetails) (*Identity, error) {
+	identities, err := r.identitiesFunc(ctx, details.authenticationProtocol(), details.username())
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading identity: %w", err)
+	}
+
+	if len(identities) == 0 {
+		return nil, nil
+	}
+
+	return &identities[0], nil
+}
+
+// permissions returns the permissions granted to the user behind the request, either through its identity or
+// through the groups mapped to its identity provider groups. It returns false if neither apply to the user.
+func (r *RBAC) permissions(ctx context.Context, details *requestDetails) ([]api.AuthPermission, bool, error) {
+	identity, err := r.identity(ctx, details)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var permissions []api.AuthPermission
+	found := identity != nil
+	if identity != nil {
+		permissions = append(permissions, identity.Permissions...)
+	}
+
+	identityProviderGroups := details.identityProviderGroups()
+	if len(identityProviderGroups) > 0 && r.identityProviderGroupsFunc != nil {
+		groupPermissions, err := r.identityProviderGroupsFunc(ctx, identityProviderGroups)
+		if err != nil {
+			return nil, false, fmt.Errorf("Failed loading identity provider group permissions: %w", err)
+		}
+
+		if len(groupPermissions) > 0 {
+			found = true
+			permissions = append(permissions, groupPermissions...)
+		}
+	}
+
+	return permissions, found, nil
+}
+
+// check returns whether the permissions grant the relation on the object.
+func (r *RBAC) check(permissions []api.AuthPermission, object Object, relation string, depth int) bool {
+	if depth > rbacMaxDepth {
+		return false
+	}
+
+	typeDefinition, ok := r.typeDefinitions[object.Type()]
+	if !ok || typeDefinition.Relations == nil {
+		return false
+	}
+
+	userset, ok := (*typeDefinition.Relations)[relation]
+	if !ok {
+		return false
+	}
+
+	return r.checkUserset(permissions, typeDefinition, object, relation, userset, depth)
+}
+
+// checkUserset evaluates a relation rewrite of the authorization model.
+func (r *RBAC) checkUserset(permissions []api.AuthPermission, typeDefinition openfga.TypeDefinition, object Object, relation string, userset openfga.Userset, depth int) bool {
+	switch {
+	case userset.This != nil:
+		// Relations open to all users are granted to any authenticated user.
+		if typeDefinition.Metadata != nil && typeDefinition.Metadata.Relations != nil {
+			metadata := (*typeDefinition.Metadata.Relations)[relation]
+			if metadata.DirectlyRelatedUserTypes != nil && slices.ContainsFunc(*metadata.DirectlyRelatedUserTypes, func(reference openfga.RelationReference) bool {
+				return reference.Wildcard != nil
+			}) {
+				return true
+			}
+		}
+
+		return slices.Contains(permissions, api.AuthPermission{Entitlement: relation, Object: object.String()})
+
+	case userset.ComputedUserset != nil && userset.ComputedUserset.Relation != nil:
+		return r.check(permissions, object, *userset.ComputedUserset.Relation, depth+1)
+
+	case userset.TupleToUserset != nil && userset.TupleToUserset.Tupleset.Relation != nil && userset.TupleToUserset.ComputedUserset.Relation != nil:
+		// Objects are only ever related to their project or to the server.
+		var parent Object
+		switch *userset.TupleToUserset.Tupleset.Relation {
+		case "project":
+			parent = ObjectProject(object.Project())
+		case "server":
+			parent = ObjectServer()
+		default:
+			return false
+		}
+
+		return r.check(permissions, parent, *userset.TupleToUserset.ComputedUserset.Relation, depth+1)
+
+	case userset.Union != nil:
+		for _, child := range userset.Union.Child {
+			if r.checkUserset(permissions, typeDefinition, object, relation, child, depth) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// CheckPermission returns an error if the user does not have the given Entitlement on the given Object.
+func (r *RBAC) CheckPermission(ctx context.Context, req *http.Request, object Object, entitlement Entitlement) error {
+	details, err := r.requestDetails(req)
+	if err != nil {
+		return api.StatusErrorf(http.StatusForbidden, "Failed to extract request details: %v", err)
+	}
+
+	if details.isInternalOrUnix() {
+		return nil
+	}
+
+	// API tokens carry their own scope.
+	if details.authenticationProtocol() == api.AuthenticationMethodToken {
+		return r.tls.CheckPermission(ctx, req, object, entitlement)
+	}
+
+	permissions, found, err := r.permissions(ctx, details)
+	if err != nil {
+		return err
+	}
+
+	// Use the TLS driver if the user authenticated with TLS and has no identity.
+	if !found && details.authenticationProtocol() == api.AuthenticationMethodTLS {
+		return r.tls.CheckPermission(ctx, req, object, entitlement)
+	}
+
+	if !r.check(permissions, object, string(entitlement), 0) {
+		return api.StatusErrorf(http.StatusForbidden, "User does not have entitlement %q on object %q", entitlement, object)
+	}
+
+	return nil
+}
+
+// GetPermissionChecker returns a function that can be used to check whether a user has the required entitlement on an authorization object.
+func (r *RBAC) GetPermissionChecker(ctx context.Context, req *http.Request, entitlement Entitlement, objectType ObjectType) (PermissionChecker, error) {
+	allowFunc := func(b bool) func(Object) bool {
+		return func(Object) bool {
+			return b
+		}
+	}
+
+	details, err := r.requestDetails(req)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Failed to extract request details: %v", err)
+	}
+
+	if details.isInternalOrUnix() {
+		return allowFunc(true), nil
+	}
+
+	// API tokens carry their own scope.
+	if details.authenticationProtocol() == api.AuthenticationMethodToken {
+		return r.tls.GetPermissionChecker(ctx, req, entitlement, objectType)
+	}
+
+	permissions, found, err := r.permissions(ctx, details)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the TLS driver if the user authenticated with TLS and has no identity.
+	if !found && details.authenticationProtocol() == api.AuthenticationMethodTLS {
+		return r.tls.GetPermissionChecker(ctx, req, entitlement, objectType)
+	}
+
+	return func(object Object) bool {
+		return r.check(permissions, object, string(entitlement), 0)
+	}, nil
+}
+
+// access returns the highest role of each identity on the object.
+func (r *RBAC) access(ctx context.Context, object Object) (*api.Access, error) {
+	identities, err := r.identitiesFunc(ctx, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading identities: %w", err)
+	}
+
+	access := api.Access{}
+	for _, identity := range identities {
+		for _, role := range []string{"admin", "operator", "user", "viewer"} {
+			if r.check(identity.Permissions, object, role, 0) {
+				access = append(access, api.AccessEntry{
+					Identifier: identity.Identifier,
+					Role:       role,
+					Provider:   DriverRBAC,
+				})
+
+				break
+			}
+		}
+	}
+
+	return &access, nil
+}
+
+// GetInstanceAccess returns the list of entities who have access to the instance.
+func (r *RBAC) GetInstanceAccess(ctx context.Context, projectName string, instanceName string) (*api.Access, error) {
+	access, err := r.access(ctx, ObjectInstance(projectName, instanceName))
+	if err != nil {
+		return nil, err
+	}
+
+	// Include the TLS clients without an identity.
+	tlsAccess, err := r.tls.GetInstanceAccess(ctx, projectName, instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	*access = append(*access, r.filterTLSAccess(ctx, *tlsAccess)...)
+
+	return access, nil
+}
+
+// GetProjectAccess returns the list of entities who have access to the project.
+func (r *RBAC) GetProjectAccess(ctx context.Context, projectName string) (*api.Access, error) {
+	access, err := r.access(ctx, ObjectProject(projectName))
+	if err != nil {
+		return nil, err
+	}
+
+	// Include the TLS clients without an identity.
+	tlsAccess, err := r.tls.GetProjectAccess(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	*access = append(*access, r.filterTLSAccess(ctx, *tlsAccess)...)
+
+	return access, nil
+}
+
+// filterTLSAccess removes the entries of TLS clients that have an identity.
+func (r *RBAC) filterTLSAccess(ctx context.Context, access api.Access) api.Access {
+	filtered := api.Access{}
+	for _, entry := range access {
+		identities, err := r.identitiesFunc(ctx, api.AuthenticationMethodTLS, entry.Identifier)
+		if err != nil {
+			r.logger.Warn("Failed loading identity", logger.Ctx{"identifier": entry.Identifier, "err": err})
+			continue
+		}
+
+		if len(identities) == 0 {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered
+}
+
+// rewritePermissions updates or removes the permissions referencing objects that were renamed or deleted.
+func (r *RBAC) rewritePermissions(ctx context.Context, rewrite func(object Object) (Object, bool)) error {
+	if r.permissionsRewriteFunc == nil {
+		return nil
+	}
+
+	return r.permissionsRewriteFunc(ctx, func(object string) (string, bool) {
+		newObject, keep := rewrite(Object(object))
+		return newObject.String(), keep
+	})
+}
+
+// renameObject updates the permissions referencing the old object.
+func (r *RBAC) renameObject(ctx context.Context, oldObject Object, newObject Object) error {
+	return r.rewritePermissions(ctx, func(object Object) (Object, bool) {
+		if object == oldObject {
+			return newObject, true
+		}
+
+		return object, true
+	})
+}
+
+// deleteObject removes the permissions referencing the object.
+func (r *RBAC) deleteObject(ctx context.Context, deletedObject Object) error {
+	return r.rewritePermissions(ctx, func(object Object) (Object, bool) {
+		return object, object != deletedObject
+	})
+}
+
+// DeleteProject removes the permissions on the project and its resources.
+func (r *RBAC) DeleteProject(ctx context.Context, _ int64, projectName string) error {
+	return r.rewritePermissions(ctx, func(object Object) (Object, bool) {
+		return object, !objectValidators[object.Type()].requireProject || object.Project() != projectName
+	})
+}
+
+// RenameProject updates the permissions on the project and its resources.
+func (r *RBAC) RenameProject(ctx context.Context, _ int64, oldName string, newName string) error {
+	return r.rewritePermissions(ctx, func(object Object) (Object, bool) {
+		if !objectValidators[object.Type()].requireProject || object.Project() != oldName {
+			return object, true
+		}
+
+		newObject, err := NewObject(object.Type(), newName, object.Elements()...)
+		if err != nil {
+			return object, true
+		}
+
+		return newObject, true
+	})
+}
+
+// DeleteCertificate removes the permissions on the certificate.
+func (r *RBAC) DeleteCertificate(ctx context.Context, fingerprint string) error {
+	return r.deleteObject(ctx, ObjectCertificate(fingerprint))
+}
+
+// DeleteStoragePool removes the permissions on the storage pool.
+func (r *RBAC) DeleteStoragePool(ctx context.Context, storagePoolName string) error {
+	return r.deleteObject(ctx, ObjectStoragePool(storagePoolName))
+}
+
+// DeleteImage removes the permissions on the image.
+func (r *RBAC) DeleteImage(ctx context.Context, projectName string, fingerprint string) error {
+	return r.deleteObject(ctx, ObjectImage(projectName, fingerprint))
+}
+
+// DeleteImageAlias removes the permissions on the image alias.
+func (r *RBAC) DeleteImageAlias(ctx context.Context, projectName string, imageAliasName string) error {
+	return r.deleteObject(ctx, ObjectImageAlias(projectName, imageAliasName))
+}
+
+// RenameImageAlias updates the permissions on the image alias.
+func (r *RBAC) RenameImageAlias(ctx context.Context, projectName string, oldAliasName string, newAliasName string) error {
+	return r.renameObject(ctx, ObjectImageAlias(projectName, oldAliasName), ObjectImageAlias(projectName, newAliasName))
+}
+
+// DeleteInstance removes the permissions on the instance.
+func (r *RBAC) DeleteInstance(ctx context.Context, projectName string, instanceName string) error {
+	return r.deleteObject(ctx, ObjectInstance(projectName, instanceName))
+}
+
+// RenameInstance updates the permissions on the instance.
+func (r *RBAC) RenameInstance(ctx context.Context, projectName string, oldInstanceName string, newInstanceName string) error {
+	return r.renameObject(ctx, ObjectInstance(projectName, oldInstanceName), ObjectInstance(projectName, newInstanceName))
+}
+
+// DeleteNetwork removes the permissions on the network.
+func (r *RBAC) DeleteNetwork(ctx context.Context, projectName string, networkName string) error {
+	return r.deleteObject(ctx, ObjectNetwork(projectName, networkName))
+}
+
+// RenameNetwork updates the permissions on the network.
+func (r *RBAC) RenameNetwork(ctx context.Context, projectName string, oldNetworkName string, newNetworkName string) error {
+	return r.renameObject(ctx, ObjectNetwork(projectName, oldNetworkName), ObjectNetwork(projectName, newNetworkName))
+}
+
+// DeleteNetworkZone removes the permissions on the network zone.
+func (r *RBAC) DeleteNetworkZone(ctx context.Context, projectName string, networkZoneName string) error {
+	return r.deleteObject(ctx, ObjectNetworkZone(projectName, networkZoneName))
+}
+
+// DeleteNetworkIntegration removes the permissions on the network integration.
+func (r *RBAC) DeleteNetworkIntegration(ctx context.Context, networkIntegrationName string) error {
+	return r.deleteObject(ctx, ObjectNetworkIntegration(networkIntegrationName))
+}
+
+// RenameNetworkIntegration updates the permissions on the network integration.
+func (r *RBAC) RenameNetworkIntegration(ctx context.Context, oldNetworkIntegrationName string, newNetworkIntegrationName string) error {
+	return r.renameObject(ctx, ObjectNetworkIntegration(oldNetworkIntegrationName), ObjectNetworkIntegration(newNetworkIntegrationName))
+}
+
+// DeleteNetworkACL removes the permissions on the network ACL.
+func (r *RBAC) DeleteNetworkACL(ctx context.Context, projectName string, networkACLName string) error {
+	return r.deleteObject(ctx, ObjectNetworkACL(projectName, networkACLName))
+}
+
+// RenameNetworkACL updates the permissions on the network ACL.
+func (r *RBAC) RenameNetworkACL(ctx context.Context, projectName string, oldNetworkACLName string, newNetworkACLName string) error {
+	return r.renameObject(ctx, ObjectNetworkACL(projectName, oldNetworkACLName), ObjectNetworkACL(projectName, newNetworkACLName))
+}
+
+// DeleteNetworkAddressSet removes the permissions on the network address set.
+func (r *RBAC) DeleteNetworkAddressSet(ctx context.Context, projectName string, networkAddressSetName string) error {
+	return r.deleteObject(ctx, ObjectNetworkAddressSet(projectName, networkAddressSetName))
+}
+
+// RenameNetworkAddressSet updates the permissions on the network address set.
+func (r *RBAC) RenameNetworkAddressSet(ctx context.Context, projectName string, oldNetworkAddressSetName string, newNetworkAddressSetName string) error {
+	return r.renameObject(ctx, ObjectNetworkAddressSet(projectName, oldNetworkAddressSetName), ObjectNetworkAddressSet(projectName, newNetworkAddressSetName))
+}
+
+// DeleteProfile removes the permissions on the profile.
+func (r *RBAC) DeleteProfile(ctx context.Context, projectName string, profileName string) error {
+	return r.deleteObject(ctx, ObjectProfile(projectName, profileName))
+}
+
+// RenameProfile updates the permissions on the profile.
+func (r *RBAC) RenameProfile(ctx context.Context, projectName string, oldProfileName string, newProfileName string) error {
+	return r.renameObject(ctx, ObjectProfile(projectName, oldProfileName), ObjectProfile(projectName, newProfileName))
+}
+
+// DeleteStoragePoolVolume removes the permissions on the storage volume.
+func (r *RBAC) DeleteStoragePoolVolume(ctx context.Context, projectName string, storagePoolName string, storageVolumeType string, storageVolumeName string, storageVolumeLocation string) error {
+	return r.deleteObject(ctx, ObjectStorageVolume(projectName, storagePoolName, storageVolumeType, storageVolumeName, storageVolumeLocation))
+}
+
+// RenameStoragePoolVolume updates the permissions on the storage volume.
+func (r *RBAC) RenameStoragePoolVolume(ctx context.Context, projectName string, storagePoolName string, storageVolumeType string, oldStorageVolumeName string, newStorageVolumeName string, storageVolumeLocation string) error {
+	return r.renameObject(ctx, ObjectStorageVolume(projectName, storagePoolName, storageVolumeType, oldStorageVolumeName, storageVolumeLocation), ObjectStorageVolume(projectName, storagePoolName, storageVolumeType, newStorageVolumeName, storageVolumeLocation))
+}
+
+// DeleteStorageBucket removes the permissions on the storage bucket.
+func (r *RBAC) DeleteStorageBucket(ctx context.Context, projectName string, storagePoolName string, storageBucketName string, storageBucketLocation string) error {
+	return r.deleteObject(ctx, ObjectStorageBucket(projectName, storagePoolName, storageBucketName, storageBucketLocation))
+}
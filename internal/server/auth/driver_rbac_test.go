@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/lxc/incus/v6/internal/server/auth/common"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type rbacSuite struct {
+	suite.Suite
+
+	rbac *RBAC
+}
+
+func TestRBACSuite(t *testing.T) {
+	suite.Run(t, new(rbacSuite))
+}
+
+func (s *rbacSuite) SetupTest() {
+	typeDefinitions, err := rbacTypeDefinitions()
+	s.Require().NoError(err)
+
+	s.rbac = &RBAC{typeDefinitions: typeDefinitions}
+}
+
+func (s *rbacSuite) TestCheckAuthenticated() {
+	s.True(s.rbac.check(nil, ObjectServer(), string(EntitlementCanView), 0))
+	s.True(s.rbac.check(nil, ObjectStoragePool("default"), string(EntitlementCanView), 0))
+	s.False(s.rbac.check(nil, ObjectServer(), string(EntitlementCanEdit), 0))
+	s.False(s.rbac.check(nil, ObjectProject("default"), string(EntitlementCanView), 0))
+}
+
+func (s *rbacSuite) TestCheckProjectViewer() {
+	permissions := []api.AuthPermission{{Entitlement: "viewer", Object: ObjectProject("foo").String()}}
+
+	s.True(s.rbac.check(permissions, ObjectProject("foo"), string(EntitlementCanView), 0))
+	s.True(s.rbac.check(permissions, ObjectInstance("foo", "c1"), string(EntitlementCanView), 0))
+	s.True(s.rbac.check(permissions, ObjectStorageVolume("foo", "default", "custom", "vol1", ""), string(EntitlementCanView), 0))
+	s.False(s.rbac.check(permissions, ObjectInstance("foo", "c1"), string(EntitlementCanEdit), 0))
+	s.False(s.rbac.check(permissions, ObjectInstance("foo", "c1"), string(EntitlementCanExec), 0))
+	s.False(s.rbac.check(permissions, ObjectProject("foo"), string(EntitlementCanCreateInstances), 0))
+	s.False(s.rbac.check(permissions, ObjectInstance("bar", "c1"), string(EntitlementCanView), 0))
+}
+
+func (s *rbacSuite) TestCheckInstance() {
+	permissions := []api.AuthPermission{{Entitlement: "user", Object: ObjectInstance("foo", "c1").String()}}
+
+	s.True(s.rbac.check(permissions, ObjectInstance("foo", "c1"), string(EntitlementCanExec), 0))
+	s.True(s.rbac.check(permissions, ObjectInstance("foo", "c1"), string(EntitlementCanView), 0))
+	s.False(s.rbac.check(permissions, ObjectInstance("foo", "c1"), string(EntitlementCanEdit), 0))
+	s.False(s.rbac.check(permissions, ObjectInstance("foo", "c2"), string(EntitlementCanView), 0))
+	s.False(s.rbac.check(permissions, ObjectProject("foo"), string(EntitlementCanView), 0))
+}
+
+func (s *rbacSuite) TestCheckServerAdmin() {
+	permissions := []api.AuthPermission{{Entitlement: "admin", Object: ObjectServer().String()}}
+
+	s.True(s.rbac.check(permissions, ObjectServer(), string(EntitlementCanEdit), 0))
+	s.True(s.rbac.check(permissions, ObjectServer(), string(EntitlementCanViewSensitive), 0))
+	s.True(s.rbac.check(permissions, ObjectProject("foo"), string(EntitlementCanEdit), 0))
+	s.True(s.rbac.check(permissions, ObjectInstance("foo", "c1"), string(EntitlementCanExec), 0))
+	s.True(s.rbac.check(permissions, ObjectCertificate("abcd"), string(EntitlementCanEdit), 0))
+}
+
+func (s *rbacSuite) TestValidatePermission() {
+	s.NoError(ValidatePermission(api.AuthPermission{Entitlement: "viewer", Object: "project:foo"}))
+	s.NoError(ValidatePermission(api.AuthPermission{Entitlement: "can_exec", Object: "instance:foo/c1"}))
+	s.NoError(ValidatePermission(api.AuthPermission{Entitlement: "admin", Object: "server:incus"}))
+
+	// Relations derived from others can't be granted.
+	s.Error(ValidatePermission(api.AuthPermission{Entitlement: "can_edit", Object: "project:foo"}))
+	s.Error(ValidatePermission(api.AuthPermission{Entitlement: "authenticated", Object: "server:incus"}))
+
+	// Invalid objects.
+	s.Error(ValidatePermission(api.AuthPermission{Entitlement: "viewer", Object: "project:"}))
+	s.Error(ValidatePermission(api.AuthPermission{Entitlement: "viewer", Object: "foo:bar"}))
+	s.Error(ValidatePermission(api.AuthPermission{Entitlement: "viewer", Object: "user:foo"}))
+}
+
+func (s *rbacSuite) TestPermissionsIdentityProviderGroups() {
+	s.rbac.identitiesFunc = func(ctx context.Context, authMethod string, identifier string) ([]Identity, error) {
+		return nil, nil
+	}
+
+	s.rbac.identityProviderGroupsFunc = func(ctx context.Context, identityProviderGroups []string) ([]api.AuthPermission, error) {
+		if slices.Contains(identityProviderGroups, "devs") {
+			return []api.AuthPermission{{Entitlement: "operator", Object: ObjectProject("foo").String()}}, nil
+		}
+
+		return nil, nil
+	}
+
+	details := &requestDetails{RequestDetails: common.RequestDetails{Username: "user1", Protocol: api.AuthenticationMethodOIDC, IdentityProviderGroups: []string{"ops", "devs"}}}
+	permissions, found, err := s.rbac.permissions(context.Background(), details)
+	s.Require().NoError(err)
+	s.True(found)
+	s.True(s.rbac.check(permissions, ObjectInstance("foo", "c1"), string(EntitlementCanExec), 0))
+	s.False(s.rbac.check(permissions, ObjectProject("foo"), string(EntitlementCanEdit), 0))
+
+	// Forwarded requests use the forwarded groups.
+	details = &requestDetails{RequestDetails: common.RequestDetails{Username: "member1", Protocol: "cluster", IdentityProviderGroups: []string{"devs"}}, forwardedUsername: "user1", forwardedProtocol: api.AuthenticationMethodOIDC}
+	_, found, err = s.rbac.permissions(context.Background(), details)
+	s.Require().NoError(err)
+	s.False(found)
+}
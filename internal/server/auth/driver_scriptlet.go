@@ -13,6 +13,8 @@ import (
 // Scriptlet represents a scriptlet authorizer.
 type Scriptlet struct {
 	commonAuthorizer
+
+	tls *TLS
 }
 
 // CheckPermission returns an error if the user does not have the given Entitlement on the given Object.
@@ -26,6 +28,11 @@ func (s *Scriptlet) CheckPermission(ctx context.Context, r *http.Request, object
 		return nil
 	}
 
+	// API tokens carry their own scope.
+	if details.authenticationProtocol() == api.AuthenticationMethodToken {
+		return s.tls.CheckPermission(ctx, r, object, entitlement)
+	}
+
 	authorized, err := authScriptlet.AuthorizationRun(logger.Log, details.actualDetails(), object.String(), string(entitlement))
 	if err != nil {
 		return api.StatusErrorf(http.StatusForbidden, "Authorization scriptlet execution failed with error: %v", err)
@@ -60,6 +67,11 @@ func (s *Scriptlet) GetPermissionChecker(ctx context.Context, r *http.Request, e
 		return allowFunc(true), nil
 	}
 
+	// API tokens carry their own scope.
+	if details.authenticationProtocol() == api.AuthenticationMethodToken {
+		return s.tls.GetPermissionChecker(ctx, r, entitlement, objectType)
+	}
+
 	permissionChecker := func(o Object) bool {
 		authorized, err := authScriptlet.AuthorizationRun(logger.Log, details.actualDetails(), o.String(), string(entitlement))
 		if err != nil {
@@ -79,5 +91,12 @@ func (s *Scriptlet) GetProjectAccess(ctx context.Context, projectName string) (*
 }
 
 func (s *Scriptlet) load(ctx context.Context, certificateCache *certificate.Cache, opts Opts) error {
-	return nil
+	// Use the TLS driver for requests made with API tokens.
+	s.tls = &TLS{}
+	err := s.tls.init(DriverTLS, s.logger)
+	if err != nil {
+		return err
+	}
+
+	return s.tls.load(ctx, certificateCache, opts)
 }
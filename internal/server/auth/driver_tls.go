@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"slices"
+	"time"
 
 	"github.com/lxc/incus/v6/internal/server/certificate"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
@@ -16,7 +17,8 @@ import (
 // TLS represents a TLS authorizer.
 type TLS struct {
 	commonAuthorizer
-	certificates *certificate.Cache
+	certificates  *certificate.Cache
+	authTokenFunc func(ctx context.Context, id string) (*api.AuthToken, error)
 }
 
 func (t *TLS) load(ctx context.Context, certificateCache *certificate.Cache, opts Opts) error {
@@ -25,6 +27,7 @@ func (t *TLS) load(ctx context.Context, certificateCache *certificate.Cache, opt
 	}
 
 	t.certificates = certificateCache
+	t.authTokenFunc = opts.authTokenFunc
 	return nil
 }
 
@@ -40,6 +43,19 @@ func (t *TLS) CheckPermission(ctx context.Context, r *http.Request, object Objec
 	}
 
 	authenticationProtocol := details.authenticationProtocol()
+	if authenticationProtocol == api.AuthenticationMethodToken {
+		allowed, err := t.authTokenChecker(ctx, details)
+		if err != nil {
+			return err
+		}
+
+		if !allowed(object, entitlement) {
+			return api.StatusErrorf(http.StatusForbidden, "API token does not have entitlement %q on object %q", entitlement, object)
+		}
+
+		return nil
+	}
+
 	if authenticationProtocol != api.AuthenticationMethodTLS {
 		t.logger.Warn("Authentication protocol is not compatible with authorization driver", logger.Ctx{"protocol": authenticationProtocol})
 		// Return nil. If the server has been configured with an authentication method but no associated authorization driver,
@@ -118,6 +134,22 @@ func (t *TLS) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 	}
 
 	authenticationProtocol := details.authenticationProtocol()
+	if authenticationProtocol == api.AuthenticationMethodToken {
+		allowed, err := t.authTokenChecker(ctx, details)
+		if err != nil {
+			return nil, err
+		}
+
+		// Error if the token isn't for the requested project (unless we're getting projects, where we want to filter the results).
+		if objectValidators[objectType].requireProject && objectType != ObjectTypeProject && !allowed(ObjectProject(details.ProjectName), EntitlementCanView) {
+			return nil, api.StatusErrorf(http.StatusForbidden, "API token does not have permissions for project %q", details.ProjectName)
+		}
+
+		return func(object Object) bool {
+			return allowed(object, entitlement)
+		}, nil
+	}
+
 	if authenticationProtocol != api.AuthenticationMethodTLS {
 		t.logger.Warn("Authentication protocol is not compatible with authorization driver", logger.Ctx{"protocol": authenticationProtocol})
 		// Allow all. If the server has been configured with an authentication method but no associated authorization driver,
@@ -190,6 +222,39 @@ func (t *TLS) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 	}, nil
 }
 
+// authTokenChecker returns a function checking entitlements against the scope of the API token used for the request.
+func (t *TLS) authTokenChecker(ctx context.Context, details *requestDetails) (func(object Object, entitlement Entitlement) bool, error) {
+	if t.authTokenFunc == nil {
+		return nil, api.StatusErrorf(http.StatusForbidden, "API tokens aren't supported")
+	}
+
+	token, err := t.authTokenFunc(ctx, details.username())
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Failed loading API token: %v", err)
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return nil, api.StatusErrorf(http.StatusForbidden, "API token has expired")
+	}
+
+	if details.IsAllProjectsRequest {
+		return nil, api.StatusErrorf(http.StatusForbidden, "API token is restricted to project %q", token.Project)
+	}
+
+	return func(object Object, entitlement Entitlement) bool {
+		// Always allow looking at the server and at the token's project.
+		if entitlement == EntitlementCanView && (object.Type() == ObjectTypeServer || object == ObjectProject(token.Project)) {
+			return true
+		}
+
+		if object.Project() != token.Project {
+			return false
+		}
+
+		return slices.Contains(token.Entitlements, tokenEntitlement(object.Type(), entitlement))
+	}, nil
+}
+
 // certificateDetails returns the certificate type, a boolean indicating if the certificate is *not* restricted, a slice of
 // project names for this certificate, or an error if the certificate could not be found.
 func (t *TLS) certificateDetails(fingerprint string) (certificate.Type, bool, []string, error) {
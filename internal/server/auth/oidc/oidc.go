@@ -28,6 +28,8 @@ type Verifier struct {
 	audience  string
 	claim     string
 	cookieKey []byte
+
+	groupsClaim string
 }
 
 // AuthError represents an authentication error.
@@ -43,8 +45,8 @@ func (e AuthError) Unwrap() error {
 	return e.Err
 }
 
-// Auth extracts the token, validates it and returns the user information.
-func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, error) {
+// Auth extracts the token, validates it and returns the user information along with its identity provider groups.
+func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, []string, error) {
 	var token string
 
 	auth := r.Header.Get("Authorization")
@@ -57,7 +59,7 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		// Both returned errors contain information which are needed for the client to authenticate.
 		parts := strings.Split(auth, "Bearer ")
 		if len(parts) != 2 {
-			return "", &AuthError{fmt.Errorf("Bad authorization token, expected a Bearer token")}
+			return "", nil, &AuthError{fmt.Errorf("Bad authorization token, expected a Bearer token")}
 		}
 
 		token = parts[1]
@@ -65,7 +67,7 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		// When not using a Bearer token, fetch the equivalent from a cookie and move on with it.
 		cookie, err := r.Cookie("oidc_access")
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		token = cookie.Value
@@ -76,7 +78,7 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 
 		o.accessTokenVerifier, err = getAccessTokenVerifier(o.issuer)
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 	}
 
@@ -85,25 +87,25 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		// See if we can refresh the access token.
 		cookie, cookieErr := r.Cookie("oidc_refresh")
 		if cookieErr != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		// Get the provider.
 		provider, err := o.getProvider(r)
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		// Attempt the refresh.
 		tokens, err := rp.RefreshTokens[*oidc.IDTokenClaims](context.TODO(), provider, cookie.Value, "", "")
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		// Validate the refreshed token.
 		claims, err = o.VerifyAccessToken(ctx, tokens.AccessToken)
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		// If we have a ResponseWriter, refresh the cookies.
@@ -136,22 +138,57 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		}
 	}
 
+	groups, err := o.groups(claims)
+	if err != nil {
+		return "", nil, err
+	}
+
 	if o.claim != "" {
 		claim := claims.Claims[o.claim]
 		username, ok := claim.(string)
 		if claim == nil || !ok || username == "" {
-			return "", fmt.Errorf("OIDC user is missing required claim %q", o.claim)
+			return "", nil, fmt.Errorf("OIDC user is missing required claim %q", o.claim)
 		}
 
-		return username, nil
+		return username, groups, nil
 	}
 
 	user, ok := claims.Claims["email"]
 	if ok && user != nil && user.(string) != "" {
-		return user.(string), nil
+		return user.(string), groups, nil
+	}
+
+	return claims.Subject, groups, nil
+}
+
+// groups returns the identity provider groups listed in the configured groups claim.
+func (o *Verifier) groups(claims *oidc.AccessTokenClaims) ([]string, error) {
+	if o.groupsClaim == "" {
+		return nil, nil
+	}
+
+	claim, ok := claims.Claims[o.groupsClaim]
+	if !ok || claim == nil {
+		// Users without any group don't always get the claim.
+		return nil, nil
+	}
+
+	values, ok := claim.([]any)
+	if !ok {
+		return nil, fmt.Errorf("OIDC claim %q isn't a list of groups", o.groupsClaim)
+	}
+
+	groups := make([]string, 0, len(values))
+	for _, value := range values {
+		group, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("OIDC claim %q isn't a list of groups", o.groupsClaim)
+		}
+
+		groups = append(groups, group)
 	}
 
-	return claims.Subject, nil
+	return groups, nil
 }
 
 func (o *Verifier) Login(w http.ResponseWriter, r *http.Request) {
@@ -351,14 +388,14 @@ func getAccessTokenVerifier(issuer string) (*op.AccessTokenVerifier, error) {
 }
 
 // NewVerifier returns a Verifier.
-func NewVerifier(issuer string, clientid string, scope string, audience string, claim string) (*Verifier, error) {
+func NewVerifier(issuer string, clientid string, scope string, audience string, claim string, groupsClaim string) (*Verifier, error) {
 	cookieKey, err := uuid.New().MarshalBinary()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create UUID: %w", err)
 	}
 
 	scopes := util.SplitNTrimSpace(scope, ",", -1, false)
-	verifier := &Verifier{issuer: issuer, clientID: clientid, scopes: scopes, audience: audience, cookieKey: cookieKey, claim: claim}
+	verifier := &Verifier{issuer: issuer, clientID: clientid, scopes: scopes, audience: audience, cookieKey: cookieKey, claim: claim, groupsClaim: groupsClaim}
 	verifier.accessTokenVerifier, _ = getAccessTokenVerifier(issuer)
 
 	return verifier, nil
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// apiTokenPrefix is the prefix of the bearer tokens issued for scoped API tokens.
+const apiTokenPrefix = "incus_"
+
+// NewAPIToken generates a new scoped API token, returning its identifier, the hash of its secret and the bearer token.
+func NewAPIToken() (string, string, string, error) {
+	buf := make([]byte, 32)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", "", "", fmt.Errorf("Failed generating token secret: %w", err)
+	}
+
+	id := uuid.New().String()
+	secret := hex.EncodeToString(buf)
+
+	return id, HashAPITokenSecret(secret), apiTokenPrefix + id + "." + secret, nil
+}
+
+// HashAPITokenSecret returns the hash of a scoped API token secret as stored in the database.
+func HashAPITokenSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// CheckAPITokenSecret checks that the secret matches the stored hash.
+func CheckAPITokenSecret(secret string, secretHash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPITokenSecret(secret)), []byte(secretHash)) == 1
+}
+
+// ParseAPIToken returns the identifier and secret of the scoped API token passed in the request, if any.
+func ParseAPIToken(r *http.Request) (string, string, bool) {
+	scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return "", "", false
+	}
+
+	value, ok = strings.CutPrefix(value, apiTokenPrefix)
+	if !ok {
+		return "", "", false
+	}
+
+	id, secret, ok := strings.Cut(value, ".")
+	if !ok || id == "" || secret == "" {
+		return "", "", false
+	}
+
+	return id, secret, true
+}
+
+// ValidateTokenEntitlement checks that the entitlement is of the form <object type>:<entitlement> and that the
+// entitlement exists on that object type, which must be scoped to a project.
+func ValidateTokenEntitlement(entitlement string) error {
+	typeDefinitions, err := rbacTypeDefinitions()
+	if err != nil {
+		return err
+	}
+
+	objectType, relation, ok := strings.Cut(entitlement, objectTypeDelimiter)
+	if ok && strings.HasPrefix(relation, "can_") && objectValidators[ObjectType(objectType)].requireProject {
+		typeDefinition, ok := typeDefinitions[ObjectType(objectType)]
+		if ok && typeDefinition.Relations != nil {
+			_, ok = (*typeDefinition.Relations)[relation]
+			if ok {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("Entitlement %q cannot be granted to API tokens", entitlement)
+}
+
+// tokenEntitlement returns the API token representation of an entitlement on an object type.
+func tokenEntitlement(objectType ObjectType, entitlement Entitlement) string {
+	return string(objectType) + objectTypeDelimiter + string(entitlement)
+}
@@ -54,6 +54,8 @@ type Info struct {
 	OptimizedHeader  *bool          `json:"optimized_header,omitempty" yaml:"optimized_header,omitempty"` // Optional field to handle older optimized backups that don't have this field.
 	Type             Type           `json:"type,omitempty" yaml:"type,omitempty"`                         // Type of backup.
 	Config           *config.Config `json:"config,omitempty" yaml:"config,omitempty"`                     // Equivalent of backup.yaml but embedded in index for quick retrieval.
+	Anchor           string         `json:"anchor,omitempty" yaml:"anchor,omitempty"`                     // Storage anchor kept on the source for later incremental backups.
+	IncrementalFrom  string         `json:"incremental_from,omitempty" yaml:"incremental_from,omitempty"` // Storage anchor this incremental backup must be applied on top of.
 }
 
 // GetInfo extracts backup information from a given ReadSeeker.
@@ -28,12 +28,14 @@ type Instance interface {
 type InstanceBackup struct {
 	CommonBackup
 
-	instance     Instance
-	instanceOnly bool
+	instance        Instance
+	instanceOnly    bool
+	anchor          string
+	incrementalFrom string
 }
 
 // NewInstanceBackup instantiates a new InstanceBackup struct.
-func NewInstanceBackup(state *state.State, inst Instance, ID int, name string, creationDate time.Time, expiryDate time.Time, instanceOnly bool, optimizedStorage bool) *InstanceBackup {
+func NewInstanceBackup(state *state.State, inst Instance, ID int, name string, creationDate time.Time, expiryDate time.Time, instanceOnly bool, optimizedStorage bool, anchor string, incrementalFrom string) *InstanceBackup {
 	return &InstanceBackup{
 		CommonBackup: CommonBackup{
 			state:            state,
@@ -43,8 +45,10 @@ func NewInstanceBackup(state *state.State, inst Instance, ID int, name string, c
 			expiryDate:       expiryDate,
 			optimizedStorage: optimizedStorage,
 		},
-		instance:     inst,
-		instanceOnly: instanceOnly,
+		instance:        inst,
+		instanceOnly:    instanceOnly,
+		anchor:          anchor,
+		incrementalFrom: incrementalFrom,
 	}
 }
 
@@ -53,6 +57,16 @@ func (b *InstanceBackup) InstanceOnly() bool {
 	return b.instanceOnly
 }
 
+// Anchor returns the identifier of the storage anchor kept for incremental backups (if any).
+func (b *InstanceBackup) Anchor() string {
+	return b.anchor
+}
+
+// IncrementalFrom returns the name of the backup this incremental backup is based on (if any).
+func (b *InstanceBackup) IncrementalFrom() string {
+	return b.incrementalFrom
+}
+
 // Instance returns the instance to be backed up.
 func (b *InstanceBackup) Instance() Instance {
 	return b.instance
@@ -151,5 +165,17 @@ func (b *InstanceBackup) Render() *api.InstanceBackup {
 		ExpiresAt:        b.expiryDate,
 		InstanceOnly:     b.instanceOnly,
 		OptimizedStorage: b.optimizedStorage,
+		IncrementalFrom:  b.renderIncrementalFrom(),
 	}
 }
+
+// renderIncrementalFrom returns the short name of the backup this backup is based on.
+func (b *InstanceBackup) renderIncrementalFrom() string {
+	if b.incrementalFrom == "" {
+		return ""
+	}
+
+	_, backupName, _ := api.GetParentAndSnapshotName(b.incrementalFrom)
+
+	return backupName
+}
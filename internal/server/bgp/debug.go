@@ -5,6 +5,9 @@ type DebugInfo struct {
 	Server   DebugInfoServer   `json:"server" yaml:"server"`
 	Prefixes []DebugInfoPrefix `json:"prefixes" yaml:"prefixes"`
 	Peers    []DebugInfoPeer   `json:"peers" yaml:"peers"`
+
+	EVPNRoutes       []DebugInfoEVPNRoute `json:"evpn_routes" yaml:"evpn_routes"`
+	EVPNRemoteRoutes []DebugInfoEVPNRoute `json:"evpn_remote_routes" yaml:"evpn_remote_routes"`
 }
 
 // DebugInfoServer exposes the shared listener configuration.
@@ -31,6 +34,15 @@ type DebugInfoPeer struct {
 	HoldTime uint64 `json:"holdtime" yaml:"holdtime"`
 }
 
+// DebugInfoEVPNRoute exposes details on a single EVPN route.
+type DebugInfoEVPNRoute struct {
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	VNI   uint32 `json:"vni" yaml:"vni"`
+	VTEP  string `json:"vtep" yaml:"vtep"`
+	MAC   string `json:"mac,omitempty" yaml:"mac,omitempty"`
+	IP    string `json:"ip,omitempty" yaml:"ip,omitempty"`
+}
+
 // Debug returns a dump of the current configuration.
 func (s *Server) Debug() DebugInfo {
 	// Locking.
@@ -73,5 +85,38 @@ func (s *Server) debug() DebugInfo {
 		debug.Prefixes = append(debug.Prefixes, entry)
 	}
 
+	// Fill in the EVPN routes.
+	debug.EVPNRoutes = []DebugInfoEVPNRoute{}
+	for _, path := range s.evpnPaths {
+		entry := evpnDebugRoute(path.route)
+		entry.Owner = path.owner
+
+		debug.EVPNRoutes = append(debug.EVPNRoutes, entry)
+	}
+
+	s.evpnMu.Lock()
+	debug.EVPNRemoteRoutes = []DebugInfoEVPNRoute{}
+	for _, route := range s.evpnRemote {
+		debug.EVPNRemoteRoutes = append(debug.EVPNRemoteRoutes, evpnDebugRoute(route))
+	}
+
+	s.evpnMu.Unlock()
+
 	return debug
 }
+
+func evpnDebugRoute(route EVPNRoute) DebugInfoEVPNRoute {
+	entry := DebugInfoEVPNRoute{}
+	entry.VNI = route.VNI
+	entry.VTEP = route.VTEP.String()
+
+	if route.MAC != nil {
+		entry.MAC = route.MAC.String()
+	}
+
+	if route.IP != nil {
+		entry.IP = route.IP.String()
+	}
+
+	return entry
+}
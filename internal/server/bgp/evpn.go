@@ -0,0 +1,410 @@
+package bgp
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/google/uuid"
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// EVPNRoute represents an EVPN route for a VXLAN network.
+type EVPNRoute struct {
+	// VXLAN network identifier.
+	VNI uint32
+
+	// Address of the VXLAN tunnel endpoint.
+	VTEP net.IP
+
+	// MAC address (unset for inclusive multicast routes).
+	MAC net.HardwareAddr
+
+	// Optional IP address associated with the MAC address.
+	IP net.IP
+}
+
+// EVPNHandler is called when a remote EVPN route is added or withdrawn.
+// It is called with the EVPN lock held and so must not call back into the server.
+type EVPNHandler func(route EVPNRoute, withdraw bool)
+
+type evpnPath struct {
+	owner string
+	route EVPNRoute
+}
+
+type evpnHandler struct {
+	vni     uint32
+	vtep    net.IP
+	handler EVPNHandler
+}
+
+// BGP extended community and PMSI tunnel types used for EVPN (RFC 8365).
+const (
+	evpnRouteTargetSubType   = 0x02
+	evpnEncapTypeVXLAN       = 8
+	evpnPMSITunnelTypeIngRep = 6
+	evpnASTrans              = 23456
+)
+
+var evpnFamily = &bgpAPI.Family{Afi: bgpAPI.Family_AFI_L2VPN, Safi: bgpAPI.Family_SAFI_EVPN}
+
+// AddEVPNRoute announces a new EVPN route.
+// Routes without a MAC address are announced as inclusive multicast (type 3) routes,
+// the others as MAC/IP advertisement (type 2) routes.
+func (s *Server) AddEVPNRoute(route EVPNRoute, owner string) error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addEVPNRoute(route, owner)
+}
+
+func (s *Server) addEVPNRoute(route EVPNRoute, owner string) error {
+	if route.VTEP.To4() == nil {
+		return fmt.Errorf("Invalid EVPN tunnel endpoint %q (must be IPv4 address)", route.VTEP)
+	}
+
+	// Check for an existing entry.
+	for _, path := range s.evpnPaths {
+		if path.owner == owner && path.route.VNI == route.VNI && path.route.VTEP.Equal(route.VTEP) && path.route.MAC.String() == route.MAC.String() && path.route.IP.Equal(route.IP) {
+			return nil
+		}
+	}
+
+	// Add the route to the server.
+	var pathUUID string
+	if s.bgp != nil {
+		p, err := s.evpnPath(route)
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.bgp.AddPath(context.Background(), &bgpAPI.AddPathRequest{Path: p})
+		if err != nil {
+			return err
+		}
+
+		pathUUID = string(resp.Uuid)
+	} else {
+		// Generate a dummy UUID.
+		pathUUID = uuid.New().String()
+	}
+
+	// Add path to the map.
+	s.evpnPaths[pathUUID] = evpnPath{
+		owner: owner,
+		route: route,
+	}
+
+	return nil
+}
+
+// evpnPath builds the BGP path for an EVPN route.
+func (s *Server) evpnPath(route EVPNRoute) (*bgpAPI.Path, error) {
+	// The route distinguisher only allows for a 16bit value alongside the address.
+	rd, err := anypb.New(&bgpAPI.RouteDistinguisherIPAddress{
+		Admin:    route.VTEP.String(),
+		Assigned: route.VNI & 0xffff,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var nlri *anypb.Any
+	if route.MAC == nil {
+		nlri, err = anypb.New(&bgpAPI.EVPNInclusiveMulticastEthernetTagRoute{
+			Rd:        rd,
+			IpAddress: route.VTEP.String(),
+		})
+	} else {
+		ipAddress := ""
+		if route.IP != nil {
+			ipAddress = route.IP.String()
+		}
+
+		nlri, err = anypb.New(&bgpAPI.EVPNMACIPAdvertisementRoute{
+			Rd:         rd,
+			Esi:        &bgpAPI.EthernetSegmentIdentifier{Value: make([]byte, 9)},
+			MacAddress: route.MAC.String(),
+			IpAddress:  ipAddress,
+			Labels:     []uint32{route.VNI},
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	aOrigin, err := anypb.New(&bgpAPI.OriginAttribute{
+		Origin: 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	aNextHop, err := anypb.New(&bgpAPI.MpReachNLRIAttribute{
+		Family:   evpnFamily,
+		NextHops: []string{route.VTEP.String()},
+		Nlris:    []*anypb.Any{nlri},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The route target carries the VNI, use AS_TRANS for 4 bytes ASNs.
+	asn := s.asn
+	if asn > 65535 {
+		asn = evpnASTrans
+	}
+
+	routeTarget, err := anypb.New(&bgpAPI.TwoOctetAsSpecificExtended{
+		IsTransitive: true,
+		SubType:      evpnRouteTargetSubType,
+		Asn:          asn,
+		LocalAdmin:   route.VNI,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encap, err := anypb.New(&bgpAPI.EncapExtended{
+		TunnelType: evpnEncapTypeVXLAN,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	aCommunities, err := anypb.New(&bgpAPI.ExtendedCommunitiesAttribute{
+		Communities: []*anypb.Any{routeTarget, encap},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := []*anypb.Any{aOrigin, aNextHop, aCommunities}
+
+	// Inclusive multicast routes use ingress replication to the tunnel endpoint.
+	if route.MAC == nil {
+		aPMSI, err := anypb.New(&bgpAPI.PmsiTunnelAttribute{
+			Type:  evpnPMSITunnelTypeIngRep,
+			Label: route.VNI,
+			Id:    route.VTEP.To4(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		attrs = append(attrs, aPMSI)
+	}
+
+	return &bgpAPI.Path{
+		Family: evpnFamily,
+		Nlri:   nlri,
+		Pattrs: attrs,
+	}, nil
+}
+
+// RemoveEVPNRoutesByOwner removes all EVPN routes for the provided owner.
+func (s *Server) RemoveEVPNRoutesByOwner(owner string) error {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for pathUUID, path := range s.evpnPaths {
+		if path.owner != owner {
+			continue
+		}
+
+		// Remove it from the BGP server.
+		if s.bgp != nil {
+			err := s.bgp.DeletePath(context.Background(), &bgpAPI.DeletePathRequest{Uuid: []byte(pathUUID)})
+			if err != nil && err.Error() != "can't find a specified path" {
+				return err
+			}
+		}
+
+		// Remove the path from the map.
+		delete(s.evpnPaths, pathUUID)
+	}
+
+	return nil
+}
+
+// AddEVPNHandler registers a handler for remote EVPN routes of the provided VNI.
+// Routes originating from the provided local tunnel endpoint are ignored.
+// Remote routes already known to the server are immediately passed to the handler.
+func (s *Server) AddEVPNHandler(vni uint32, vtep net.IP, handler EVPNHandler, owner string) {
+	// Locking.
+	s.evpnMu.Lock()
+	defer s.evpnMu.Unlock()
+
+	h := evpnHandler{
+		vni:     vni,
+		vtep:    vtep,
+		handler: handler,
+	}
+
+	s.evpnHandlers[owner] = h
+
+	for _, route := range s.evpnRemote {
+		h.notify(route, false)
+	}
+}
+
+// RemoveEVPNHandler removes the handler registered by the provided owner.
+func (s *Server) RemoveEVPNHandler(owner string) {
+	// Locking.
+	s.evpnMu.Lock()
+	defer s.evpnMu.Unlock()
+
+	delete(s.evpnHandlers, owner)
+}
+
+func (h evpnHandler) notify(route EVPNRoute, withdraw bool) {
+	if route.VNI != h.vni || route.VTEP.Equal(h.vtep) {
+		return
+	}
+
+	h.handler(route, withdraw)
+}
+
+// evpnWatch processes best path updates, keeping track of remote EVPN routes.
+// It only takes the EVPN lock as the server lock is held while the watch is started and cancelled.
+func (s *Server) evpnWatch(r *bgpAPI.WatchEventResponse) {
+	table := r.GetTable()
+	if table == nil {
+		return
+	}
+
+	// Locking.
+	s.evpnMu.Lock()
+	defer s.evpnMu.Unlock()
+
+	for _, p := range table.Paths {
+		if p.GetFamily().GetAfi() != evpnFamily.Afi || p.GetFamily().GetSafi() != evpnFamily.Safi || p.Nlri == nil {
+			continue
+		}
+
+		// Skip locally originated paths.
+		if net.ParseIP(p.NeighborIp) == nil {
+			continue
+		}
+
+		key := string(p.Nlri.GetValue())
+
+		if p.IsWithdraw {
+			route, ok := s.evpnRemote[key]
+			if !ok {
+				continue
+			}
+
+			delete(s.evpnRemote, key)
+			for _, h := range s.evpnHandlers {
+				h.notify(route, true)
+			}
+
+			continue
+		}
+
+		route, err := evpnParsePath(p)
+		if err != nil {
+			logger.Debug("Ignoring EVPN route", logger.Ctx{"err": err})
+			continue
+		}
+
+		// Replace any previous version of the route.
+		oldRoute, ok := s.evpnRemote[key]
+		if ok {
+			for _, h := range s.evpnHandlers {
+				h.notify(oldRoute, true)
+			}
+		}
+
+		s.evpnRemote[key] = *route
+		for _, h := range s.evpnHandlers {
+			h.notify(*route, false)
+		}
+	}
+}
+
+// evpnClearRemote withdraws all known remote EVPN routes.
+func (s *Server) evpnClearRemote() {
+	// Locking.
+	s.evpnMu.Lock()
+	defer s.evpnMu.Unlock()
+
+	for key, route := range s.evpnRemote {
+		for _, h := range s.evpnHandlers {
+			h.notify(route, true)
+		}
+
+		delete(s.evpnRemote, key)
+	}
+}
+
+// evpnParsePath converts a received BGP path into an EVPN route.
+func evpnParsePath(p *bgpAPI.Path) (*EVPNRoute, error) {
+	route := &EVPNRoute{}
+
+	nlri, err := p.Nlri.UnmarshalNew()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := nlri.(type) {
+	case *bgpAPI.EVPNInclusiveMulticastEthernetTagRoute:
+		route.VTEP = net.ParseIP(v.IpAddress)
+	case *bgpAPI.EVPNMACIPAdvertisementRoute:
+		route.MAC, err = net.ParseMAC(v.MacAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		route.IP = net.ParseIP(v.IpAddress)
+	default:
+		return nil, fmt.Errorf("Unsupported EVPN route type %q", p.Nlri.GetTypeUrl())
+	}
+
+	for _, attr := range p.Pattrs {
+		a, err := attr.UnmarshalNew()
+		if err != nil {
+			return nil, err
+		}
+
+		switch v := a.(type) {
+		case *bgpAPI.MpReachNLRIAttribute:
+			// MAC/IP routes are reached through the next hop.
+			if route.VTEP == nil && len(v.NextHops) > 0 {
+				route.VTEP = net.ParseIP(v.NextHops[0])
+			}
+
+		case *bgpAPI.ExtendedCommunitiesAttribute:
+			// Route targets are matched on the VNI alone as the ASN differs between eBGP peers.
+			for _, community := range v.Communities {
+				c, err := community.UnmarshalNew()
+				if err != nil {
+					return nil, err
+				}
+
+				rt, ok := c.(*bgpAPI.TwoOctetAsSpecificExtended)
+				if ok && rt.SubType == evpnRouteTargetSubType {
+					route.VNI = rt.LocalAdmin
+				}
+			}
+		}
+	}
+
+	if route.VTEP == nil {
+		return nil, fmt.Errorf("Missing tunnel endpoint")
+	}
+
+	if route.VNI == 0 {
+		return nil, fmt.Errorf("Missing route target")
+	}
+
+	return route, nil
+}
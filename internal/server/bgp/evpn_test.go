@@ -0,0 +1,110 @@
+package bgp
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseMAC(t *testing.T, value string) net.HardwareAddr {
+	mac, err := net.ParseMAC(value)
+	require.NoError(t, err)
+
+	return mac
+}
+
+func TestEVPNPathRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		asn   uint32
+		route EVPNRoute
+	}{
+		{
+			name:  "Inclusive multicast route",
+			asn:   65000,
+			route: EVPNRoute{VNI: 100, VTEP: net.ParseIP("192.0.2.1")},
+		},
+		{
+			name:  "MAC route",
+			asn:   65000,
+			route: EVPNRoute{VNI: 100, VTEP: net.ParseIP("192.0.2.1"), MAC: mustParseMAC(t, "00:16:3e:00:00:01")},
+		},
+		{
+			name:  "MAC/IP route with 4 bytes ASN",
+			asn:   4200000000,
+			route: EVPNRoute{VNI: 70000, VTEP: net.ParseIP("192.0.2.1"), MAC: mustParseMAC(t, "00:16:3e:00:00:01"), IP: net.ParseIP("10.0.0.1")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer()
+			s.asn = tt.asn
+
+			p, err := s.evpnPath(tt.route)
+			require.NoError(t, err)
+
+			route, err := evpnParsePath(p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.route, *route)
+		})
+	}
+}
+
+func TestEVPNWatch(t *testing.T) {
+	s := NewServer()
+	s.asn = 65000
+
+	local := net.ParseIP("192.0.2.1")
+	remote := EVPNRoute{VNI: 100, VTEP: net.ParseIP("192.0.2.2")}
+	otherVNI := EVPNRoute{VNI: 200, VTEP: net.ParseIP("192.0.2.2")}
+
+	type event struct {
+		route    EVPNRoute
+		withdraw bool
+	}
+
+	var events []event
+	s.AddEVPNHandler(100, local, func(route EVPNRoute, withdraw bool) {
+		events = append(events, event{route: route, withdraw: withdraw})
+	}, "test")
+
+	watch := func(route EVPNRoute, withdraw bool) {
+		p, err := s.evpnPath(route)
+		require.NoError(t, err)
+
+		p.NeighborIp = route.VTEP.String()
+		p.IsWithdraw = withdraw
+
+		// The watch callback must not wait on the server lock, which is held while the watch gets cancelled.
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		done := make(chan struct{})
+		go func() {
+			s.evpnWatch(&bgpAPI.WatchEventResponse{Event: &bgpAPI.WatchEventResponse_Table{Table: &bgpAPI.WatchEventResponse_TableEvent{Paths: []*bgpAPI.Path{p}}}})
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("EVPN watch callback blocked on the server lock")
+		}
+	}
+
+	watch(remote, false)
+	watch(otherVNI, false)
+	watch(remote, true)
+
+	assert.Equal(t, []event{{route: remote}, {route: remote, withdraw: true}}, events)
+	assert.Len(t, s.evpnRemote, 1)
+
+	// Clearing the remote routes withdraws them from the handlers.
+	s.evpnClearRemote()
+	assert.Empty(t, s.evpnRemote)
+}
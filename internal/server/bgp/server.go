@@ -29,7 +29,17 @@ type Server struct {
 	paths    map[string]path
 	peers    map[string]peer
 
+	// EVPN state.
+	evpnPaths    map[string]evpnPath
+	evpnHandlers map[string]evpnHandler
+	evpnRemote   map[string]EVPNRoute
+	evpnCancel   context.CancelFunc
+
 	mu sync.Mutex
+
+	// Protects evpnHandlers and evpnRemote. Those are updated from the BGP watch callback
+	// which must never wait on mu as the watch is cancelled with mu held.
+	evpnMu sync.Mutex
 }
 
 type path struct {
@@ -50,8 +60,11 @@ type peer struct {
 func NewServer() *Server {
 	// Setup new struct.
 	s := &Server{
-		paths: map[string]path{},
-		peers: map[string]peer{},
+		paths:        map[string]path{},
+		peers:        map[string]peer{},
+		evpnPaths:    map[string]evpnPath{},
+		evpnHandlers: map[string]evpnHandler{},
+		evpnRemote:   map[string]EVPNRoute{},
 	}
 
 	return s
@@ -97,8 +110,8 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		RouterId: routerID.String(),
 		Asn:      asn,
 
-		// Always setup for IPv4 and IPv6.
-		Families: []uint32{0, 1},
+		// Always setup for IPv4, IPv6 and EVPN.
+		Families: []uint32{0, 1, 9},
 
 		// Listen address.
 		ListenAddresses: []string{addrHost},
@@ -111,6 +124,25 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		return err
 	}
 
+	// Record the address.
+	s.address = address
+	s.asn = asn
+	s.routerID = routerID
+
+	// Track the EVPN routes received from the peers.
+	ctx, cancel := context.WithCancel(context.Background())
+	err = s.bgp.WatchEvent(ctx, &bgpAPI.WatchEventRequest{
+		Table: &bgpAPI.WatchEventRequest_Table{
+			Filters: []*bgpAPI.WatchEventRequest_Table_Filter{{Type: bgpAPI.WatchEventRequest_Table_Filter_BEST, Init: true}},
+		},
+	}, s.evpnWatch)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	s.evpnCancel = cancel
+
 	// Copy the path list
 	oldPaths := map[string]path{}
 	for pathUUID, path := range s.paths {
@@ -126,6 +158,21 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		}
 	}
 
+	// Copy the EVPN path list.
+	oldEVPNPaths := map[string]evpnPath{}
+	for pathUUID, path := range s.evpnPaths {
+		oldEVPNPaths[pathUUID] = path
+	}
+
+	// Add existing EVPN paths.
+	s.evpnPaths = map[string]evpnPath{}
+	for _, path := range oldEVPNPaths {
+		err := s.addEVPNRoute(path.route, path.owner)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Copy the peer list.
 	oldPeers := map[string]peer{}
 	for peerUUID, peer := range s.peers {
@@ -141,11 +188,6 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		}
 	}
 
-	// Record the address.
-	s.address = address
-	s.asn = asn
-	s.routerID = routerID
-
 	return nil
 }
 
@@ -173,6 +215,14 @@ func (s *Server) stop() error {
 	// Restore peer list.
 	s.peers = oldPeers
 
+	// Stop tracking EVPN routes and withdraw the received ones.
+	if s.evpnCancel != nil {
+		s.evpnCancel()
+		s.evpnCancel = nil
+	}
+
+	s.evpnClearRemote()
+
 	// Stop the listener.
 	err := s.bgp.StopBgp(context.Background(), &bgpAPI.StopBgpRequest{})
 	if err != nil {
@@ -454,9 +504,22 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 		}
 	}
 
-	// Setup peer for dual-stack.
+	// Use the listen address as the source address when bound to a specific address.
+	listenHost, _, err := net.SplitHostPort(s.address)
+	if err != nil {
+		listenHost = s.address
+	}
+
+	listenAddress := net.ParseIP(listenHost)
+	if listenAddress != nil && !listenAddress.IsUnspecified() && (listenAddress.To4() == nil) == (address.To4() == nil) {
+		n.Transport = &bgpAPI.Transport{
+			LocalAddress: listenAddress.String(),
+		}
+	}
+
+	// Setup peer for dual-stack and EVPN.
 	n.AfiSafis = make([]*bgpAPI.AfiSafi, 0)
-	for _, f := range []string{"ipv4-unicast", "ipv6-unicast"} {
+	for _, f := range []string{"ipv4-unicast", "ipv6-unicast", "l2vpn-evpn"} {
 		rf, err := bgpPacket.GetRouteFamily(f)
 		if err != nil {
 			return err
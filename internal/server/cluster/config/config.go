@@ -13,6 +13,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/config"
 	"github.com/lxc/incus/v6/internal/server/db"
 	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/validate"
 )
 
@@ -203,11 +204,23 @@ func (c *Config) InstancesPlacementScriptlet() string {
 	return c.m.GetString("instances.placement.scriptlet")
 }
 
+// Audit returns all the audit log settings.
+func (c *Config) Audit() (bool, int64, int64, bool) {
+	maxSize, _ := units.ParseByteSizeString(c.m.GetString("audit.file.max_size"))
+
+	return c.m.GetBool("audit.enabled"), maxSize, c.m.GetInt64("audit.file.max_files"), c.m.GetBool("audit.syslog")
+}
+
 // AuthorizationScriptlet returns the authorization scriptlet source code.
 func (c *Config) AuthorizationScriptlet() string {
 	return c.m.GetString("authorization.scriptlet")
 }
 
+// AuthorizationRBAC returns whether the built-in role-based access control is enabled.
+func (c *Config) AuthorizationRBAC() bool {
+	return c.m.GetBool("authorization.rbac")
+}
+
 // InstancesLXCFSPerInstance returns whether LXCFS should be run on a per-instance basis.
 func (c *Config) InstancesLXCFSPerInstance() bool {
 	return c.m.GetBool("instances.lxcfs.per_instance")
@@ -229,6 +242,25 @@ func (c *Config) LokiServer() (string, string, string, string, string, string, [
 	return c.m.GetString("loki.api.url"), c.m.GetString("loki.auth.username"), c.m.GetString("loki.auth.password"), c.m.GetString("loki.api.ca_cert"), c.m.GetString("loki.instance"), c.m.GetString("loki.loglevel"), labels, types
 }
 
+// OTLPServer returns all the OpenTelemetry settings needed to export to a collector.
+func (c *Config) OTLPServer() (apiURL string, caCert string, headers map[string]string, instance string, logLevel string, types []string, metricsInterval time.Duration, traces bool) {
+	if c.m.GetString("otlp.types") != "" {
+		types = strings.Split(c.m.GetString("otlp.types"), ",")
+	}
+
+	headers = map[string]string{}
+	if c.m.GetString("otlp.api.headers") != "" {
+		for _, header := range strings.Split(c.m.GetString("otlp.api.headers"), ",") {
+			key, value, _ := strings.Cut(header, "=")
+			headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	metricsInterval = time.Duration(c.m.GetInt64("otlp.metrics.interval")) * time.Second
+
+	return c.m.GetString("otlp.api.url"), c.m.GetString("otlp.api.ca_cert"), headers, c.m.GetString("otlp.instance"), c.m.GetString("otlp.loglevel"), types, metricsInterval, c.m.GetBool("otlp.traces.enabled")
+}
+
 // ACME returns all ACME settings needed for certificate renewal.
 func (c *Config) ACME() (string, string, string, bool, string) {
 	return c.m.GetString("acme.domain"), c.m.GetString("acme.email"), c.m.GetString("acme.ca_url"), c.m.GetBool("acme.agree_tos"), c.m.GetString("acme.challenge")
@@ -278,8 +310,8 @@ func (c *Config) RemoteTokenExpiry() string {
 }
 
 // OIDCServer returns all the OpenID Connect settings needed to connect to a server.
-func (c *Config) OIDCServer() (string, string, string, string, string) {
-	return c.m.GetString("oidc.issuer"), c.m.GetString("oidc.client.id"), c.m.GetString("oidc.scopes"), c.m.GetString("oidc.audience"), c.m.GetString("oidc.claim")
+func (c *Config) OIDCServer() (string, string, string, string, string, string) {
+	return c.m.GetString("oidc.issuer"), c.m.GetString("oidc.client.id"), c.m.GetString("oidc.scopes"), c.m.GetString("oidc.audience"), c.m.GetString("oidc.claim"), c.m.GetString("oidc.groups.claim")
 }
 
 // ClusterHealingThreshold returns the configured healing threshold, i.e. the
@@ -418,6 +450,52 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Comma-separated list of DNS resolvers (used by DNS-01)
 	"acme.provider.resolvers": {Type: config.String, Default: ""},
 
+	// gendoc:generate(entity=server, group=audit, key=audit.enabled)
+	// When enabled, every API request other than `GET` and `HEAD` is recorded in the `audit.log` file of the server log directory.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to record mutating API requests in the audit log
+	"audit.enabled": {Type: config.Bool, Validator: validate.Optional(validate.IsBool)},
+
+	// gendoc:generate(entity=server, group=audit, key=audit.file.max_files)
+	// Number of rotated audit log files to keep on each server. Set to `0` to discard the records on rotation.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `10`
+	//  shortdesc: Number of rotated audit log files to keep
+	"audit.file.max_files": {Type: config.Int64, Default: "10", Validator: validate.Optional(validate.IsInRange(0, 1000))},
+
+	// gendoc:generate(entity=server, group=audit, key=audit.file.max_size)
+	// The audit log file is rotated once it reaches this size.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `100MiB`
+	//  shortdesc: Maximum size of the audit log file
+	"audit.file.max_size": {Type: config.String, Default: "100MiB", Validator: validate.Optional(validate.IsSize)},
+
+	// gendoc:generate(entity=server, group=audit, key=audit.syslog)
+	// When enabled, the audit records are also sent to the local syslog daemon using the `authpriv` facility.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to send the audit records to syslog
+	"audit.syslog": {Type: config.Bool, Validator: validate.Optional(validate.IsBool)},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=authorization.rbac)
+	// When enabled, access is granted based on the groups and identities managed through `/1.0/auth`.
+	// TLS clients without an identity keep their regular access.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to use the built-in role-based access control
+	"authorization.rbac": {Type: config.Bool, Validator: validate.Optional(validate.IsBool)},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=authorization.scriptlet)
 	// When using scriptlet-based authorization, this option stores the scriptlet.
 	// ---
@@ -782,13 +860,13 @@ var ConfigSchema = config.Schema{
 
 	// gendoc:generate(entity=server, group=loki, key=loki.types)
 	// Specify a comma-separated list of events to send to the Loki server.
-	// The events can be any combination of `lifecycle`, `logging`, and `network-acl`.
+	// The events can be any combination of `audit`, `lifecycle`, `logging`, and `network-acl`.
 	// ---
 	//  type: string
 	//  scope: global
 	//  defaultdesc: `lifecycle,logging`
 	//  shortdesc: Events to send to the Loki server
-	"loki.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "logging", "network-acl"))), Default: "lifecycle,logging"},
+	"loki.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("audit", "lifecycle", "logging", "network-acl"))), Default: "lifecycle,logging"},
 
 	// gendoc:generate(entity=server, group=openfga, key=openfga.api.token)
 	//
@@ -854,6 +932,84 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: OpenID Connect claim to use as the username
 	"oidc.claim": {},
 
+	// gendoc:generate(entity=server, group=oidc, key=oidc.groups.claim)
+	// The claim must be present in the access token and hold a list of group names.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: OpenID Connect claim to use as the list of identity provider groups
+	"oidc.groups.claim": {},
+
+	// gendoc:generate(entity=server, group=otlp, key=otlp.api.ca_cert)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: CA certificate for the OpenTelemetry collector
+	"otlp.api.ca_cert": {},
+
+	// gendoc:generate(entity=server, group=otlp, key=otlp.api.headers)
+	// Specify a comma-separated list of `key=value` pairs, for example `Authorization=Bearer abc`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: HTTP headers to add to the requests sent to the OpenTelemetry collector
+	"otlp.api.headers": {Validator: validate.Optional(otlpHeadersValidator)},
+
+	// gendoc:generate(entity=server, group=otlp, key=otlp.api.url)
+	// Specify the protocol, name or IP and port of the OTLP/HTTP endpoint. For example `https://otel.example.com:4318`. Incus will automatically add the `/v1/logs`, `/v1/metrics` and `/v1/traces` suffixes.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: URL to the OpenTelemetry collector
+	"otlp.api.url": {Validator: validate.Optional(validate.IsRequestURL)},
+
+	// gendoc:generate(entity=server, group=otlp, key=otlp.instance)
+	// This is exported as the `service.instance.id` resource attribute.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: Local server host name or cluster member name
+	//  shortdesc: Name identifying the server in the exported data
+	"otlp.instance": {},
+
+	// gendoc:generate(entity=server, group=otlp, key=otlp.loglevel)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `info`
+	//  shortdesc: Minimum log level to send to the OpenTelemetry collector
+	"otlp.loglevel": {Validator: logLevelValidator, Default: logrus.InfoLevel.String()},
+
+	// gendoc:generate(entity=server, group=otlp, key=otlp.metrics.interval)
+	// Set to `0` to disable the export of metrics.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `60`
+	//  shortdesc: Interval in seconds between two exports of the metrics
+	"otlp.metrics.interval": {Type: config.Int64, Default: "60", Validator: validate.Optional(validate.IsInRange(0, 86400))},
+
+	// gendoc:generate(entity=server, group=otlp, key=otlp.traces.enabled)
+	// Traces cover the API requests and the background operations.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `true`
+	//  shortdesc: Whether to send traces to the OpenTelemetry collector
+	"otlp.traces.enabled": {Type: config.Bool, Default: "true"},
+
+	// gendoc:generate(entity=server, group=otlp, key=otlp.types)
+	// Specify a comma-separated list of events to send to the OpenTelemetry collector as logs.
+	// The events can be any combination of `lifecycle`, `logging`, and `network-acl`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `lifecycle,logging`
+	//  shortdesc: Events to send to the OpenTelemetry collector
+	"otlp.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "logging", "network-acl"))), Default: "lifecycle,logging"},
+
 	// OVN networking global keys.
 
 	// gendoc:generate(entity=server, group=miscellaneous, key=network.ovn.integration_bridge)
@@ -924,6 +1080,17 @@ func logLevelValidator(value string) error {
 	return nil
 }
 
+func otlpHeadersValidator(value string) error {
+	for _, header := range strings.Split(value, ",") {
+		key, _, ok := strings.Cut(header, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("Invalid header %q, expected key=value", header)
+		}
+	}
+
+	return nil
+}
+
 func offlineThresholdDefault() string {
 	return strconv.Itoa(db.DefaultOfflineThreshold)
 }
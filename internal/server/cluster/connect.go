@@ -3,6 +3,7 @@ package cluster
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -16,6 +17,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/proxy"
@@ -34,6 +36,16 @@ func init() {
 // to the UserAgentNotifier value, which can be used in some cases to distinguish
 // between a regular client request and an internal cluster request.
 func Connect(address string, networkCert *localtls.CertInfo, serverCert *localtls.CertInfo, r *http.Request, notify bool) (incus.InstanceServer, error) {
+	ctx := context.Background()
+	if r != nil {
+		ctx = r.Context()
+	}
+
+	return connect(ctx, address, networkCert, serverCert, r, notify)
+}
+
+// connect is Connect, propagating the trace context found in ctx to the peer.
+func connect(traceCtx context.Context, address string, networkCert *localtls.CertInfo, serverCert *localtls.CertInfo, r *http.Request, notify bool) (incus.InstanceServer, error) {
 	// Wait for a connection to the events API first for non-notify connections.
 	if !notify {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(10)*time.Second)
@@ -70,12 +82,27 @@ func Connect(address string, networkCert *localtls.CertInfo, serverCert *localtl
 				req.Header.Add(request.HeaderForwardedProtocol, val)
 			}
 
+			groups, ok := ctx.Value(request.CtxIdentityProviderGroups).([]string)
+			if ok && len(groups) > 0 {
+				data, err := json.Marshal(groups)
+				if err == nil {
+					req.Header.Add(request.HeaderForwardedIdentityProviderGroups, string(data))
+				}
+			}
+
 			req.Header.Add(request.HeaderForwardedAddress, r.RemoteAddr)
+			tracing.Inject(traceCtx, req.Header)
 
 			return proxy.FromEnvironment(req)
 		}
 
 		args.Proxy = proxy
+	} else if _, ok := tracing.FromContext(traceCtx); ok {
+		args.Proxy = func(req *http.Request) (*url.URL, error) {
+			tracing.Inject(traceCtx, req.Header)
+
+			return proxy.FromEnvironment(req)
+		}
 	}
 
 	url := fmt.Sprintf("https://%s", address)
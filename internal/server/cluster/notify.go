@@ -9,6 +9,7 @@ import (
 	incus "github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	"github.com/lxc/incus/v6/shared/logger"
 	localtls "github.com/lxc/incus/v6/shared/tls"
 )
@@ -31,6 +32,12 @@ const (
 // NewNotifier builds a Notifier that can be used to notify other peers using
 // the given policy.
 func NewNotifier(state *state.State, networkCert *localtls.CertInfo, serverCert *localtls.CertInfo, policy NotifierPolicy) (Notifier, error) {
+	return NewNotifierWithContext(context.Background(), state, networkCert, serverCert, policy)
+}
+
+// NewNotifierWithContext is NewNotifier, additionally propagating the trace
+// context found in ctx to the notified peers.
+func NewNotifierWithContext(ctx context.Context, state *state.State, networkCert *localtls.CertInfo, serverCert *localtls.CertInfo, policy NotifierPolicy) (Notifier, error) {
 	localClusterAddress := state.LocalConfig.ClusterAddress()
 
 	// Fast-track the case where we're not clustered at all.
@@ -92,7 +99,11 @@ func NewNotifier(state *state.State, networkCert *localtls.CertInfo, serverCert
 			logger.Debugf("Notify node %s of state changes", address)
 			go func(i int, address string) {
 				defer wg.Done()
-				client, err := Connect(address, networkCert, serverCert, nil, true)
+
+				spanCtx, span := tracing.Start(ctx, "cluster notify", tracing.SpanKindClient, map[string]string{"server.address": address})
+				defer func() { span.Finish(errs[i]) }()
+
+				client, err := connect(spanCtx, address, networkCert, serverCert, nil, true)
 				if err != nil {
 					errs[i] = fmt.Errorf("failed to connect to peer %s: %w", address, err)
 					return
@@ -0,0 +1,441 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetAuthGroups returns the names of existing authorization groups.
+func (c *ClusterTx) GetAuthGroups(ctx context.Context) ([]string, error) {
+	q := `SELECT name FROM auth_groups ORDER BY name`
+
+	groupNames, err := query.SelectStrings(ctx, c.tx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupNames, nil
+}
+
+// GetAuthGroup returns the authorization group with the given name.
+func (c *ClusterTx) GetAuthGroup(ctx context.Context, name string) (int64, *api.AuthGroup, error) {
+	var id int64 = int64(-1)
+
+	group := api.AuthGroup{
+		AuthGroupPost: api.AuthGroupPost{
+			Name: name,
+		},
+	}
+
+	q := `SELECT id, description FROM auth_groups WHERE name=? LIMIT 1`
+
+	err := c.tx.QueryRowContext(ctx, q, name).Scan(&id, &group.Description)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, nil, api.StatusErrorf(http.StatusNotFound, "Authorization group not found")
+		}
+
+		return -1, nil, err
+	}
+
+	group.Permissions = []api.AuthPermission{}
+	q = `SELECT entitlement, object FROM auth_groups_permissions WHERE auth_group_id=? ORDER BY object, entitlement`
+	err = query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		permission := api.AuthPermission{}
+
+		err := scan(&permission.Entitlement, &permission.Object)
+		if err != nil {
+			return err
+		}
+
+		group.Permissions = append(group.Permissions, permission)
+
+		return nil
+	}, id)
+	if err != nil {
+		return -1, nil, fmt.Errorf("Failed loading permissions: %w", err)
+	}
+
+	q = `SELECT name FROM auth_groups_identity_provider_groups WHERE auth_group_id=? ORDER BY name`
+	group.IdentityProviderGroups, err = query.SelectStrings(ctx, c.tx, q, id)
+	if err != nil {
+		return -1, nil, fmt.Errorf("Failed loading identity provider groups: %w", err)
+	}
+
+	return id, &group, nil
+}
+
+// GetAuthGroupIdentities returns the identities that are members of the authorization group.
+// The groups of the returned identities aren't populated.
+func (c *ClusterTx) GetAuthGroupIdentities(ctx context.Context, id int64) ([]api.AuthIdentity, error) {
+	q := `
+		SELECT identities.auth_method, identities.identifier
+		FROM identities
+		JOIN identities_auth_groups ON identities_auth_groups.identity_id=identities.id
+		WHERE identities_auth_groups.auth_group_id=?
+		ORDER BY identities.auth_method, identities.identifier
+	`
+
+	identities := []api.AuthIdentity{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		identity := api.AuthIdentity{}
+
+		err := scan(&identity.AuthMethod, &identity.Identifier)
+		if err != nil {
+			return err
+		}
+
+		identities = append(identities, identity)
+
+		return nil
+	}, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// CreateAuthGroup creates a new authorization group.
+func (c *ClusterTx) CreateAuthGroup(ctx context.Context, info *api.AuthGroupsPost) (int64, error) {
+	result, err := c.tx.ExecContext(ctx, `INSERT INTO auth_groups (name, description) VALUES (?, ?)`, info.Name, info.Description)
+	if err != nil {
+		return -1, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	err = authGroupPermissionsAdd(ctx, c.tx, id, info.Permissions)
+	if err != nil {
+		return -1, err
+	}
+
+	err = authGroupIdentityProviderGroupsAdd(ctx, c.tx, id, info.IdentityProviderGroups)
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// UpdateAuthGroup updates the authorization group with the given ID.
+func (c *ClusterTx) UpdateAuthGroup(ctx context.Context, id int64, info *api.AuthGroupPut) error {
+	_, err := c.tx.ExecContext(ctx, `UPDATE auth_groups SET description=? WHERE id=?`, info.Description, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, `DELETE FROM auth_groups_permissions WHERE auth_group_id=?`, id)
+	if err != nil {
+		return err
+	}
+
+	err = authGroupPermissionsAdd(ctx, c.tx, id, info.Permissions)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, `DELETE FROM auth_groups_identity_provider_groups WHERE auth_group_id=?`, id)
+	if err != nil {
+		return err
+	}
+
+	return authGroupIdentityProviderGroupsAdd(ctx, c.tx, id, info.IdentityProviderGroups)
+}
+
+// authGroupPermissionsAdd adds the permissions to the authorization group with the given ID.
+func authGroupPermissionsAdd(ctx context.Context, tx *sql.Tx, id int64, permissions []api.AuthPermission) error {
+	for _, permission := range permissions {
+		_, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO auth_groups_permissions (auth_group_id, entitlement, object) VALUES (?, ?, ?)`, id, permission.Entitlement, permission.Object)
+		if err != nil {
+			return fmt.Errorf("Failed adding permission: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// authGroupIdentityProviderGroupsAdd maps the identity provider groups to the authorization group with the given ID.
+func authGroupIdentityProviderGroupsAdd(ctx context.Context, tx *sql.Tx, id int64, identityProviderGroups []string) error {
+	for _, name := range identityProviderGroups {
+		_, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO auth_groups_identity_provider_groups (auth_group_id, name) VALUES (?, ?)`, id, name)
+		if err != nil {
+			return fmt.Errorf("Failed adding identity provider group: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetIdentityProviderGroupsPermissions returns the permissions granted through the authorization groups
+// that the identity provider groups are mapped to.
+func (c *ClusterTx) GetIdentityProviderGroupsPermissions(ctx context.Context, identityProviderGroups []string) ([]api.AuthPermission, error) {
+	permissions := []api.AuthPermission{}
+	if len(identityProviderGroups) == 0 {
+		return permissions, nil
+	}
+
+	q := fmt.Sprintf(`
+		SELECT DISTINCT auth_groups_permissions.entitlement, auth_groups_permissions.object
+		FROM auth_groups_permissions
+		JOIN auth_groups_identity_provider_groups ON auth_groups_identity_provider_groups.auth_group_id=auth_groups_permissions.auth_group_id
+		WHERE auth_groups_identity_provider_groups.name IN %s
+	`, query.Params(len(identityProviderGroups)))
+
+	args := make([]any, 0, len(identityProviderGroups))
+	for _, name := range identityProviderGroups {
+		args = append(args, name)
+	}
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		permission := api.AuthPermission{}
+
+		err := scan(&permission.Entitlement, &permission.Object)
+		if err != nil {
+			return err
+		}
+
+		permissions = append(permissions, permission)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// RewriteAuthPermissions applies the rewrite function to the object of every permission.
+// Permissions that aren't kept are deleted.
+func (c *ClusterTx) RewriteAuthPermissions(ctx context.Context, rewrite func(object string) (string, bool)) error {
+	type permission struct {
+		id     int64
+		object string
+	}
+
+	permissions := []permission{}
+	err := query.Scan(ctx, c.tx, `SELECT id, object FROM auth_groups_permissions`, func(scan func(dest ...any) error) error {
+		p := permission{}
+
+		err := scan(&p.id, &p.object)
+		if err != nil {
+			return err
+		}
+
+		permissions = append(permissions, p)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, p := range permissions {
+		newObject, keep := rewrite(p.object)
+		if !keep {
+			_, err = c.tx.ExecContext(ctx, `DELETE FROM auth_groups_permissions WHERE id=?`, p.id)
+		} else if newObject != p.object {
+			_, err = c.tx.ExecContext(ctx, `UPDATE OR IGNORE auth_groups_permissions SET object=? WHERE id=?`, newObject, p.id)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenameAuthGroup renames the authorization group with the given ID.
+func (c *ClusterTx) RenameAuthGroup(ctx context.Context, id int64, newName string) error {
+	_, err := c.tx.ExecContext(ctx, `UPDATE auth_groups SET name=? WHERE id=?`, newName, id)
+
+	return err
+}
+
+// DeleteAuthGroup deletes the authorization group with the given ID.
+func (c *ClusterTx) DeleteAuthGroup(ctx context.Context, id int64) error {
+	_, err := c.tx.ExecContext(ctx, `DELETE FROM auth_groups WHERE id=?`, id)
+
+	return err
+}
+
+// GetAuthIdentities returns the existing identities.
+func (c *ClusterTx) GetAuthIdentities(ctx context.Context) ([]api.AuthIdentity, error) {
+	q := `
+		SELECT identities.auth_method, identities.identifier, auth_groups.name
+		FROM identities
+		LEFT JOIN identities_auth_groups ON identities_auth_groups.identity_id=identities.id
+		LEFT JOIN auth_groups ON auth_groups.id=identities_auth_groups.auth_group_id
+		ORDER BY identities.auth_method, identities.identifier, auth_groups.name
+	`
+
+	identities := []api.AuthIdentity{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var authMethod string
+		var identifier string
+		var groupName sql.NullString
+
+		err := scan(&authMethod, &identifier, &groupName)
+		if err != nil {
+			return err
+		}
+
+		// Rows are sorted by identity so only the last entry needs checking.
+		if len(identities) == 0 || identities[len(identities)-1].AuthMethod != authMethod || identities[len(identities)-1].Identifier != identifier {
+			identities = append(identities, api.AuthIdentity{
+				AuthIdentityPut: api.AuthIdentityPut{Groups: []string{}},
+				AuthMethod:      authMethod,
+				Identifier:      identifier,
+			})
+		}
+
+		if groupName.Valid {
+			identity := &identities[len(identities)-1]
+			identity.Groups = append(identity.Groups, groupName.String)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// GetAuthIdentity returns the identity with the given authentication method and identifier.
+func (c *ClusterTx) GetAuthIdentity(ctx context.Context, authMethod string, identifier string) (int64, *api.AuthIdentity, error) {
+	var id int64 = int64(-1)
+
+	identity := api.AuthIdentity{
+		AuthMethod: authMethod,
+		Identifier: identifier,
+	}
+
+	err := c.tx.QueryRowContext(ctx, `SELECT id FROM identities WHERE auth_method=? AND identifier=? LIMIT 1`, authMethod, identifier).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, nil, api.StatusErrorf(http.StatusNotFound, "Identity not found")
+		}
+
+		return -1, nil, err
+	}
+
+	q := `
+		SELECT auth_groups.name
+		FROM auth_groups
+		JOIN identities_auth_groups ON identities_auth_groups.auth_group_id=auth_groups.id
+		WHERE identities_auth_groups.identity_id=?
+		ORDER BY auth_groups.name
+	`
+
+	identity.Groups, err = query.SelectStrings(ctx, c.tx, q, id)
+	if err != nil {
+		return -1, nil, fmt.Errorf("Failed loading groups: %w", err)
+	}
+
+	return id, &identity, nil
+}
+
+// GetAuthIdentityPermissions returns the permissions granted to the identity through its groups.
+func (c *ClusterTx) GetAuthIdentityPermissions(ctx context.Context, authMethod string, identifier string) ([]api.AuthPermission, error) {
+	q := `
+		SELECT DISTINCT auth_groups_permissions.entitlement, auth_groups_permissions.object
+		FROM auth_groups_permissions
+		JOIN identities_auth_groups ON identities_auth_groups.auth_group_id=auth_groups_permissions.auth_group_id
+		JOIN identities ON identities.id=identities_auth_groups.identity_id
+		WHERE identities.auth_method=? AND identities.identifier=?
+	`
+
+	permissions := []api.AuthPermission{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		permission := api.AuthPermission{}
+
+		err := scan(&permission.Entitlement, &permission.Object)
+		if err != nil {
+			return err
+		}
+
+		permissions = append(permissions, permission)
+
+		return nil
+	}, authMethod, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// CreateAuthIdentity creates a new identity.
+func (c *ClusterTx) CreateAuthIdentity(ctx context.Context, info *api.AuthIdentitiesPost) (int64, error) {
+	result, err := c.tx.ExecContext(ctx, `INSERT INTO identities (auth_method, identifier) VALUES (?, ?)`, info.AuthMethod, info.Identifier)
+	if err != nil {
+		return -1, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	err = authIdentityGroupsAdd(ctx, c.tx, id, info.Groups)
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// UpdateAuthIdentity updates the groups of the identity with the given ID.
+func (c *ClusterTx) UpdateAuthIdentity(ctx context.Context, id int64, info *api.AuthIdentityPut) error {
+	_, err := c.tx.ExecContext(ctx, `DELETE FROM identities_auth_groups WHERE identity_id=?`, id)
+	if err != nil {
+		return err
+	}
+
+	return authIdentityGroupsAdd(ctx, c.tx, id, info.Groups)
+}
+
+// authIdentityGroupsAdd adds the identity with the given ID to the named groups.
+func authIdentityGroupsAdd(ctx context.Context, tx *sql.Tx, id int64, groupNames []string) error {
+	for _, groupName := range groupNames {
+		var groupID int64
+
+		err := tx.QueryRowContext(ctx, `SELECT id FROM auth_groups WHERE name=? LIMIT 1`, groupName).Scan(&groupID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return api.StatusErrorf(http.StatusNotFound, "Authorization group %q not found", groupName)
+			}
+
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT OR IGNORE INTO identities_auth_groups (identity_id, auth_group_id) VALUES (?, ?)`, id, groupID)
+		if err != nil {
+			return fmt.Errorf("Failed adding group %q: %w", groupName, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteAuthIdentity deletes the identity with the given ID.
+func (c *ClusterTx) DeleteAuthIdentity(ctx context.Context, id int64) error {
+	_, err := c.tx.ExecContext(ctx, `DELETE FROM identities WHERE id=?`, id)
+
+	return err
+}
@@ -0,0 +1,159 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetAuthTokens returns all the scoped API tokens.
+func (c *ClusterTx) GetAuthTokens(ctx context.Context) ([]api.AuthToken, error) {
+	q := `SELECT auth_tokens.uuid FROM auth_tokens ORDER BY auth_tokens.creation_date`
+
+	uuids, err := query.SelectStrings(ctx, c.tx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]api.AuthToken, 0, len(uuids))
+	for _, uuid := range uuids {
+		_, token, err := c.GetAuthToken(ctx, uuid)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, *token)
+	}
+
+	return tokens, nil
+}
+
+// GetAuthToken returns the scoped API token with the given identifier.
+func (c *ClusterTx) GetAuthToken(ctx context.Context, uuid string) (int64, *api.AuthToken, error) {
+	var id int64 = int64(-1)
+
+	token := api.AuthToken{
+		ID: uuid,
+	}
+
+	q := `
+SELECT auth_tokens.id, auth_tokens.description, projects.name, auth_tokens.creation_date, auth_tokens.expiry_date
+  FROM auth_tokens
+  JOIN projects ON projects.id = auth_tokens.project_id
+ WHERE auth_tokens.uuid=? LIMIT 1`
+
+	err := c.tx.QueryRowContext(ctx, q, uuid).Scan(&id, &token.Description, &token.Project, &token.CreatedAt, &token.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, nil, api.StatusErrorf(http.StatusNotFound, "API token not found")
+		}
+
+		return -1, nil, err
+	}
+
+	q = `SELECT entitlement FROM auth_tokens_entitlements WHERE auth_token_id=? ORDER BY entitlement`
+	token.Entitlements, err = query.SelectStrings(ctx, c.tx, q, id)
+	if err != nil {
+		return -1, nil, err
+	}
+
+	return id, &token, nil
+}
+
+// GetAuthTokenSecretHash returns the hash of the secret of the scoped API token with the given identifier.
+func (c *ClusterTx) GetAuthTokenSecretHash(ctx context.Context, uuid string) (string, error) {
+	var secretHash string
+
+	err := c.tx.QueryRowContext(ctx, `SELECT secret_hash FROM auth_tokens WHERE uuid=? LIMIT 1`, uuid).Scan(&secretHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", api.StatusErrorf(http.StatusNotFound, "API token not found")
+		}
+
+		return "", err
+	}
+
+	return secretHash, nil
+}
+
+// CreateAuthToken creates a new scoped API token with the given identifier and secret hash.
+func (c *ClusterTx) CreateAuthToken(ctx context.Context, uuid string, secretHash string, info *api.AuthTokensPost) (int64, error) {
+	var projectID int64
+
+	err := c.tx.QueryRowContext(ctx, `SELECT id FROM projects WHERE name=? LIMIT 1`, info.Project).Scan(&projectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, api.StatusErrorf(http.StatusNotFound, "Project %q not found", info.Project)
+		}
+
+		return -1, err
+	}
+
+	q := `INSERT INTO auth_tokens (uuid, secret_hash, description, project_id, creation_date, expiry_date) VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := c.tx.ExecContext(ctx, q, uuid, secretHash, info.Description, projectID, time.Now().UTC(), info.ExpiresAt.UTC())
+	if err != nil {
+		return -1, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	for _, entitlement := range info.Entitlements {
+		_, err = c.tx.ExecContext(ctx, `INSERT OR IGNORE INTO auth_tokens_entitlements (auth_token_id, entitlement) VALUES (?, ?)`, id, entitlement)
+		if err != nil {
+			return -1, fmt.Errorf("Failed adding entitlement %q: %w", entitlement, err)
+		}
+	}
+
+	return id, nil
+}
+
+// DeleteAuthToken deletes the scoped API token with the given ID.
+func (c *ClusterTx) DeleteAuthToken(ctx context.Context, id int64) error {
+	_, err := c.tx.ExecContext(ctx, `DELETE FROM auth_tokens WHERE id=?`, id)
+
+	return err
+}
+
+// DeleteExpiredAuthTokens deletes the scoped API tokens that expired before the given time.
+func (c *ClusterTx) DeleteExpiredAuthTokens(ctx context.Context, before time.Time) (int, error) {
+	expiredIDs := []int64{}
+
+	err := query.Scan(ctx, c.tx, `SELECT id, expiry_date FROM auth_tokens`, func(scan func(dest ...any) error) error {
+		var id int64
+		var expiry time.Time
+
+		err := scan(&id, &expiry)
+		if err != nil {
+			return err
+		}
+
+		if expiry.Before(before) {
+			expiredIDs = append(expiredIDs, id)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range expiredIDs {
+		err = c.DeleteAuthToken(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(expiredIDs), nil
+}
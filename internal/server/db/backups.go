@@ -24,6 +24,8 @@ type InstanceBackup struct {
 	InstanceOnly         bool
 	OptimizedStorage     bool
 	CompressionAlgorithm string
+	Anchor               string
+	IncrementalFrom      string
 }
 
 // StoragePoolVolumeBackup is a value object holding all db-related details about a storage volume backup.
@@ -73,7 +75,8 @@ func (c *ClusterTx) GetInstanceBackup(ctx context.Context, projectName string, n
 	q := `
 SELECT instances_backups.id, instances_backups.instance_id,
        instances_backups.creation_date, instances_backups.expiry_date,
-       instances_backups.container_only, instances_backups.optimized_storage
+       instances_backups.container_only, instances_backups.optimized_storage,
+       instances_backups.anchor, instances_backups.incremental_from
     FROM instances_backups
     JOIN instances ON instances.id=instances_backups.instance_id
     JOIN projects ON projects.id=instances.project_id
@@ -81,7 +84,8 @@ SELECT instances_backups.id, instances_backups.instance_id,
 `
 	arg1 := []any{projectName, name}
 	arg2 := []any{&args.ID, &args.InstanceID, &args.CreationDate,
-		&args.ExpiryDate, &instanceOnlyInt, &optimizedStorageInt}
+		&args.ExpiryDate, &instanceOnlyInt, &optimizedStorageInt, &args.Anchor,
+		&args.IncrementalFrom}
 
 	err := dbQueryRowScan(ctx, c, q, arg1, arg2)
 	if err != nil {
@@ -113,7 +117,8 @@ func (c *ClusterTx) GetInstanceBackupWithID(ctx context.Context, backupID int) (
 	q := `
 SELECT instances_backups.name, instances_backups.instance_id,
        instances_backups.creation_date, instances_backups.expiry_date,
-       instances_backups.container_only, instances_backups.optimized_storage
+       instances_backups.container_only, instances_backups.optimized_storage,
+       instances_backups.anchor, instances_backups.incremental_from
     FROM instances_backups
     JOIN instances ON instances.id=instances_backups.instance_id
     JOIN projects ON projects.id=instances.project_id
@@ -121,7 +126,8 @@ SELECT instances_backups.name, instances_backups.instance_id,
 `
 	arg1 := []any{backupID}
 	arg2 := []any{&args.Name, &args.InstanceID, &args.CreationDate,
-		&args.ExpiryDate, &instanceOnlyInt, &optimizedStorageInt}
+		&args.ExpiryDate, &instanceOnlyInt, &optimizedStorageInt, &args.Anchor,
+		&args.IncrementalFrom}
 
 	err := dbQueryRowScan(ctx, c, q, arg1, arg2)
 	if err != nil {
@@ -184,7 +190,7 @@ func (c *ClusterTx) CreateInstanceBackup(ctx context.Context, args InstanceBacku
 		optimizedStorageInt = 1
 	}
 
-	str := "INSERT INTO instances_backups (instance_id, name, creation_date, expiry_date, container_only, optimized_storage) VALUES (?, ?, ?, ?, ?, ?)"
+	str := "INSERT INTO instances_backups (instance_id, name, creation_date, expiry_date, container_only, optimized_storage, anchor, incremental_from) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
 	stmt, err := c.tx.Prepare(str)
 	if err != nil {
 		return err
@@ -193,7 +199,7 @@ func (c *ClusterTx) CreateInstanceBackup(ctx context.Context, args InstanceBacku
 	defer func() { _ = stmt.Close() }()
 	result, err := stmt.Exec(args.InstanceID, args.Name,
 		args.CreationDate.Unix(), args.ExpiryDate.Unix(), instanceOnlyInt,
-		optimizedStorageInt)
+		optimizedStorageInt, args.Anchor, args.IncrementalFrom)
 	if err != nil {
 		return err
 	}
@@ -243,6 +249,12 @@ func (c *ClusterTx) RenameInstanceBackup(ctx context.Context, oldName, newName s
 		return err
 	}
 
+	// Keep incremental backups pointing to the renamed backup.
+	_, err = c.tx.ExecContext(ctx, "UPDATE instances_backups SET incremental_from = ? WHERE incremental_from = ?", newName, oldName)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -6,6 +6,45 @@ package cluster
 // modify the database schema, please add a new schema update to update.go
 // and the run 'make update-schema'.
 const freshSchema = `
+CREATE TABLE auth_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE auth_groups_identity_provider_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE (auth_group_id, name),
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE
+);
+CREATE TABLE auth_groups_permissions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    entitlement TEXT NOT NULL,
+    object TEXT NOT NULL,
+    UNIQUE (auth_group_id, entitlement, object),
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE
+);
+CREATE TABLE auth_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    uuid TEXT NOT NULL,
+    secret_hash TEXT NOT NULL,
+    description TEXT NOT NULL,
+    project_id INTEGER NOT NULL,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME NOT NULL,
+    UNIQUE (uuid),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+CREATE TABLE auth_tokens_entitlements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_token_id INTEGER NOT NULL,
+    entitlement TEXT NOT NULL,
+    UNIQUE (auth_token_id, entitlement),
+    FOREIGN KEY (auth_token_id) REFERENCES auth_tokens (id) ON DELETE CASCADE
+);
 CREATE TABLE certificates (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     fingerprint TEXT NOT NULL,
@@ -43,6 +82,19 @@ CREATE TABLE config (
     value TEXT,
     UNIQUE (key)
 );
+CREATE TABLE identities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_method TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    UNIQUE (auth_method, identifier)
+);
+CREATE TABLE identities_auth_groups (
+    identity_id INTEGER NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    FOREIGN KEY (identity_id) REFERENCES identities (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE,
+    UNIQUE (identity_id, auth_group_id)
+);
 CREATE TABLE "images" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     fingerprint TEXT NOT NULL,
@@ -130,6 +182,8 @@ CREATE TABLE "instances_backups" (
     expiry_date DATETIME,
     container_only INTEGER NOT NULL default 0,
     optimized_storage INTEGER NOT NULL default 0,
+    anchor TEXT NOT NULL DEFAULT '',
+    incremental_from TEXT NOT NULL DEFAULT '',
     FOREIGN KEY (instance_id) REFERENCES "instances" (id) ON DELETE CASCADE,
     UNIQUE (instance_id, name)
 );
@@ -238,6 +292,23 @@ CREATE TABLE "networks_acls_config" (
     UNIQUE (network_acl_id, key),
     FOREIGN KEY (network_acl_id) REFERENCES "networks_acls" (id) ON DELETE CASCADE
 );
+CREATE TABLE networks_address_sets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    addresses TEXT NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+CREATE TABLE networks_address_sets_config (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_address_set_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (network_address_set_id, key),
+    FOREIGN KEY (network_address_set_id) REFERENCES networks_address_sets (id) ON DELETE CASCADE
+);
 CREATE TABLE "networks_config" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     network_id INTEGER NOT NULL,
@@ -655,6 +726,20 @@ CREATE TABLE "warnings" (
     FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
+CREATE TABLE webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE webhooks_config (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    webhook_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (webhook_id, key),
+    FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
 
-INSERT INTO schema (version, updated_at) VALUES (75, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (81, strftime("%s"))
 `
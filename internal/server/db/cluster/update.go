@@ -112,6 +112,170 @@ var updates = map[int]schema.Update{
 	73: updateFromV72,
 	74: updateFromV73,
 	75: updateFromV74,
+	76: updateFromV75,
+	77: updateFromV76,
+	78: updateFromV77,
+	79: updateFromV78,
+	80: updateFromV79,
+	81: updateFromV80,
+}
+
+// updateFromV80 adds the webhooks tables.
+func updateFromV80(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE webhooks_config (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    webhook_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (webhook_id, key),
+    FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding webhooks tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV79 adds the scoped API tokens tables.
+func updateFromV79(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE auth_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    uuid TEXT NOT NULL,
+    secret_hash TEXT NOT NULL,
+    description TEXT NOT NULL,
+    project_id INTEGER NOT NULL,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME NOT NULL,
+    UNIQUE (uuid),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+CREATE TABLE auth_tokens_entitlements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_token_id INTEGER NOT NULL,
+    entitlement TEXT NOT NULL,
+    UNIQUE (auth_token_id, entitlement),
+    FOREIGN KEY (auth_token_id) REFERENCES auth_tokens (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding API tokens tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV78 adds the mapping of identity provider groups to authorization groups.
+func updateFromV78(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE auth_groups_identity_provider_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE (auth_group_id, name),
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding identity provider groups table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV77 adds the role-based access control tables.
+func updateFromV77(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE auth_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE auth_groups_permissions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    entitlement TEXT NOT NULL,
+    object TEXT NOT NULL,
+    UNIQUE (auth_group_id, entitlement, object),
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE
+);
+CREATE TABLE identities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_method TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    UNIQUE (auth_method, identifier)
+);
+CREATE TABLE identities_auth_groups (
+    identity_id INTEGER NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    FOREIGN KEY (identity_id) REFERENCES identities (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE,
+    UNIQUE (identity_id, auth_group_id)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding role-based access control tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV76 adds the network address sets tables.
+func updateFromV76(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE networks_address_sets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    addresses TEXT NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+
+CREATE TABLE networks_address_sets_config (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_address_set_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (network_address_set_id, key),
+    FOREIGN KEY (network_address_set_id) REFERENCES networks_address_sets (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding network address sets tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV75 adds incremental backup tracking to instance backups.
+func updateFromV75(ctx context.Context, tx *sql.Tx) error {
+	q := `
+ALTER TABLE instances_backups ADD COLUMN anchor TEXT NOT NULL DEFAULT '';
+ALTER TABLE instances_backups ADD COLUMN incremental_from TEXT NOT NULL DEFAULT '';
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding incremental backup columns: %w", err)
+	}
+
+	return nil
 }
 
 // updateFromV74 removes the index preventing the same integration to be used multiple times.
@@ -0,0 +1,294 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetNetworkAddressSets returns the names of existing network address sets.
+func (c *ClusterTx) GetNetworkAddressSets(ctx context.Context, project string) ([]string, error) {
+	q := `SELECT name FROM networks_address_sets
+		WHERE project_id = (SELECT id FROM projects WHERE name = ? LIMIT 1)
+		ORDER BY id
+	`
+
+	var setNames []string
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var setName string
+
+		err := scan(&setName)
+		if err != nil {
+			return err
+		}
+
+		setNames = append(setNames, setName)
+
+		return nil
+	}, project)
+	if err != nil {
+		return nil, err
+	}
+
+	return setNames, nil
+}
+
+// GetNetworkAddressSetsAllProjects returns the names of existing network address sets for all projects.
+func (c *ClusterTx) GetNetworkAddressSetsAllProjects(ctx context.Context) (map[string][]string, error) {
+	q := `SELECT projects.name, networks_address_sets.name FROM networks_address_sets
+		JOIN projects ON projects.id=networks_address_sets.project_id
+		ORDER BY networks_address_sets.id
+	`
+
+	setNames := map[string][]string{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var projectName string
+		var setName string
+
+		err := scan(&projectName, &setName)
+		if err != nil {
+			return err
+		}
+
+		setNames[projectName] = append(setNames[projectName], setName)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return setNames, nil
+}
+
+// GetNetworkAddressSetIDsByNames returns a map of names to IDs of existing network address sets.
+func (c *ClusterTx) GetNetworkAddressSetIDsByNames(ctx context.Context, project string) (map[string]int64, error) {
+	q := `SELECT id, name FROM networks_address_sets
+		WHERE project_id = (SELECT id FROM projects WHERE name = ? LIMIT 1)
+		ORDER BY id
+	`
+
+	sets := make(map[string]int64)
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var setID int64
+		var setName string
+
+		err := scan(&setID, &setName)
+		if err != nil {
+			return err
+		}
+
+		sets[setName] = setID
+
+		return nil
+	}, project)
+	if err != nil {
+		return nil, err
+	}
+
+	return sets, nil
+}
+
+// GetNetworkAddressSet returns the network address set with the given name in the given project.
+func (c *ClusterTx) GetNetworkAddressSet(ctx context.Context, projectName string, name string) (int64, *api.NetworkAddressSet, error) {
+	var id int64 = int64(-1)
+	var addressesJSON string
+
+	set := api.NetworkAddressSet{
+		NetworkAddressSetPost: api.NetworkAddressSetPost{
+			Name: name,
+		},
+	}
+
+	q := `
+		SELECT id, description, addresses
+		FROM networks_address_sets
+		WHERE project_id = (SELECT id FROM projects WHERE name = ? LIMIT 1) AND name=?
+		LIMIT 1
+	`
+
+	err := c.tx.QueryRowContext(ctx, q, projectName, name).Scan(&id, &set.Description, &addressesJSON)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return -1, nil, api.StatusErrorf(http.StatusNotFound, "Network address set not found")
+		}
+
+		return -1, nil, err
+	}
+
+	err = networkAddressSetConfig(ctx, c, id, &set)
+	if err != nil {
+		return -1, nil, fmt.Errorf("Failed loading config: %w", err)
+	}
+
+	set.Addresses = []string{}
+	if addressesJSON != "" {
+		err = json.Unmarshal([]byte(addressesJSON), &set.Addresses)
+		if err != nil {
+			return -1, nil, fmt.Errorf("Failed unmarshalling addresses: %w", err)
+		}
+	}
+
+	return id, &set, nil
+}
+
+// networkAddressSetConfig populates the config map of the network address set with the given ID.
+func networkAddressSetConfig(ctx context.Context, tx *ClusterTx, id int64, set *api.NetworkAddressSet) error {
+	q := `
+		SELECT key, value
+		FROM networks_address_sets_config
+		WHERE network_address_set_id=?
+	`
+
+	set.Config = make(map[string]string)
+	return query.Scan(ctx, tx.Tx(), q, func(scan func(dest ...any) error) error {
+		var key, value string
+
+		err := scan(&key, &value)
+		if err != nil {
+			return err
+		}
+
+		_, found := set.Config[key]
+		if found {
+			return fmt.Errorf("Duplicate config row found for key %q for network address set ID %d", key, id)
+		}
+
+		set.Config[key] = value
+
+		return nil
+	}, id)
+}
+
+// CreateNetworkAddressSet creates a new network address set.
+func (c *ClusterTx) CreateNetworkAddressSet(ctx context.Context, projectName string, info *api.NetworkAddressSetsPost) (int64, error) {
+	addresses := info.Addresses
+	if addresses == nil {
+		addresses = []string{}
+	}
+
+	addressesJSON, err := json.Marshal(addresses)
+	if err != nil {
+		return -1, fmt.Errorf("Failed marshalling addresses: %w", err)
+	}
+
+	// Insert a new network address set record.
+	result, err := c.tx.ExecContext(ctx, `
+			INSERT INTO networks_address_sets (project_id, name, description, addresses)
+			VALUES ((SELECT id FROM projects WHERE name = ? LIMIT 1), ?, ?, ?)
+		`, projectName, info.Name, info.Description, string(addressesJSON))
+	if err != nil {
+		return -1, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	err = networkAddressSetConfigAdd(c.tx, id, info.Config)
+	if err != nil {
+		return -1, err
+	}
+
+	return id, err
+}
+
+// networkAddressSetConfigAdd inserts network address set config keys.
+func networkAddressSetConfigAdd(tx *sql.Tx, id int64, config map[string]string) error {
+	sql := "INSERT INTO networks_address_sets_config (network_address_set_id, key, value) VALUES(?, ?, ?)"
+	stmt, err := tx.Prepare(sql)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = stmt.Close() }()
+
+	for k, v := range config {
+		if v == "" {
+			continue
+		}
+
+		_, err = stmt.Exec(id, k, v)
+		if err != nil {
+			return fmt.Errorf("Failed inserting config: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateNetworkAddressSet updates the network address set with the given ID.
+func (c *ClusterTx) UpdateNetworkAddressSet(ctx context.Context, id int64, config *api.NetworkAddressSetPut) error {
+	addresses := config.Addresses
+	if addresses == nil {
+		addresses = []string{}
+	}
+
+	addressesJSON, err := json.Marshal(addresses)
+	if err != nil {
+		return fmt.Errorf("Failed marshalling addresses: %w", err)
+	}
+
+	_, err = c.tx.ExecContext(ctx, `
+			UPDATE networks_address_sets
+			SET description=?, addresses=?
+			WHERE id=?
+		`, config.Description, string(addressesJSON), id)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "DELETE FROM networks_address_sets_config WHERE network_address_set_id=?", id)
+	if err != nil {
+		return err
+	}
+
+	err = networkAddressSetConfigAdd(c.tx, id, config.Config)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameNetworkAddressSet renames a network address set.
+func (c *ClusterTx) RenameNetworkAddressSet(ctx context.Context, id int64, newName string) error {
+	_, err := c.tx.ExecContext(ctx, "UPDATE networks_address_sets SET name=? WHERE id=?", newName, id)
+
+	return err
+}
+
+// DeleteNetworkAddressSet deletes the network address set.
+func (c *ClusterTx) DeleteNetworkAddressSet(ctx context.Context, id int64) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM networks_address_sets WHERE id=?", id)
+
+	return err
+}
+
+// GetNetworkAddressSetURIs returns the URIs for the network address sets with the given project.
+func (c *ClusterTx) GetNetworkAddressSetURIs(ctx context.Context, projectID int, project string) ([]string, error) {
+	sql := `SELECT networks_address_sets.name FROM networks_address_sets WHERE networks_address_sets.project_id = ?`
+
+	names, err := query.SelectStrings(ctx, c.tx, sql, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get URIs for network address sets: %w", err)
+	}
+
+	uris := make([]string, len(names))
+	for i := range names {
+		uris[i] = api.NewURL().Path(version.APIVersion, "network-address-sets", names[i]).Project(project).String()
+	}
+
+	return uris, nil
+}
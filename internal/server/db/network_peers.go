@@ -18,11 +18,13 @@ import (
 const (
 	networkPeerTypeLocal = iota
 	networkPeerTypeRemote
+	networkPeerTypeWireguard
 )
 
 var networkPeerTypeNames = map[int]string{
-	networkPeerTypeLocal:  "local",
-	networkPeerTypeRemote: "remote",
+	networkPeerTypeLocal:     "local",
+	networkPeerTypeRemote:    "remote",
+	networkPeerTypeWireguard: "wireguard",
 }
 
 // CreateNetworkPeer creates a new Network Peer and returns its ID.
@@ -70,6 +72,21 @@ func (c *ClusterTx) CreateNetworkPeer(ctx context.Context, networkID int64, info
 			return -1, false, err
 		}
 
+		localPeerID, err = result.LastInsertId()
+		if err != nil {
+			return -1, false, err
+		}
+	} else if info.Type == networkPeerTypeNames[networkPeerTypeWireguard] {
+		// Insert a new WireGuard peer record (the remote endpoint is entirely described by its config).
+		result, err := c.tx.ExecContext(ctx, `
+		INSERT INTO networks_peers
+		(network_id, name, description, type)
+		VALUES (?, ?, ?, ?)
+		`, networkID, info.Name, info.Description, networkPeerTypeWireguard)
+		if err != nil {
+			return -1, false, err
+		}
+
 		localPeerID, err = result.LastInsertId()
 		if err != nil {
 			return -1, false, err
@@ -252,8 +269,8 @@ func (c *ClusterTx) GetNetworkPeer(ctx context.Context, networkID int64, peerNam
 // It uses the state of the targetPeerNetworkProject and targetPeerNetworkName arguments to decide whether the
 // peering is mutually created and whether to use those values rather than the values contained in the peer.
 func networkPeerPopulatePeerInfo(peer *api.NetworkPeer, targetPeerNetworkProject string, targetPeerNetworkName string) {
-	// The rest of this function doesn't apply to remote peerings.
-	if peer.Type == networkPeerTypeNames[networkPeerTypeRemote] {
+	// The rest of this function doesn't apply to remote and WireGuard peerings.
+	if peer.Type == networkPeerTypeNames[networkPeerTypeRemote] || peer.Type == networkPeerTypeNames[networkPeerTypeWireguard] {
 		peer.Status = api.NetworkStatusCreated
 		return
 	}
@@ -560,11 +560,12 @@ type NetworkType int
 
 // Network types.
 const (
-	NetworkTypeBridge   NetworkType = iota // Network type bridge.
-	NetworkTypeMacvlan                     // Network type macvlan.
-	NetworkTypeSriov                       // Network type sriov.
-	NetworkTypeOVN                         // Network type ovn.
-	NetworkTypePhysical                    // Network type physical.
+	NetworkTypeBridge    NetworkType = iota // Network type bridge.
+	NetworkTypeMacvlan                      // Network type macvlan.
+	NetworkTypeSriov                        // Network type sriov.
+	NetworkTypeOVN                          // Network type ovn.
+	NetworkTypePhysical                     // Network type physical.
+	NetworkTypeWireguard                    // Network type wireguard.
 )
 
 // NetworkNode represents a network node.
@@ -692,6 +693,8 @@ func networkFillType(network *api.Network, netType NetworkType) {
 		network.Type = "ovn"
 	case NetworkTypePhysical:
 		network.Type = "physical"
+	case NetworkTypeWireguard:
+		network.Type = "wireguard"
 	default:
 		network.Type = "" // Unknown
 	}
@@ -857,8 +860,11 @@ func (c *ClusterTx) RenameNetwork(ctx context.Context, project string, oldName s
 
 // NodeSpecificNetworkConfig lists all network config keys which are node-specific.
 var NodeSpecificNetworkConfig = []string{
+	"bgp.evpn.local",
 	"bgp.ipv4.nexthop",
 	"bgp.ipv6.nexthop",
 	"bridge.external_interfaces",
 	"parent",
+	"wireguard.ipv4.address",
+	"wireguard.ipv6.address",
 }
@@ -0,0 +1,129 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetWebhookNames returns the names of all the webhooks.
+func (c *ClusterTx) GetWebhookNames(ctx context.Context) ([]string, error) {
+	return query.SelectStrings(ctx, c.tx, `SELECT name FROM webhooks ORDER BY name`)
+}
+
+// GetWebhooks returns all the webhooks.
+func (c *ClusterTx) GetWebhooks(ctx context.Context) ([]api.Webhook, error) {
+	names, err := c.GetWebhookNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]api.Webhook, 0, len(names))
+	for _, name := range names {
+		_, webhook, err := c.GetWebhook(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, *webhook)
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhook returns the webhook with the given name.
+func (c *ClusterTx) GetWebhook(ctx context.Context, name string) (int64, *api.Webhook, error) {
+	var id int64 = int64(-1)
+
+	webhook := api.Webhook{
+		Name: name,
+	}
+
+	err := c.tx.QueryRowContext(ctx, `SELECT id, description FROM webhooks WHERE name=? LIMIT 1`, name).Scan(&id, &webhook.Description)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, nil, api.StatusErrorf(http.StatusNotFound, "Webhook not found")
+		}
+
+		return -1, nil, err
+	}
+
+	webhook.Config, err = query.SelectConfig(ctx, c.tx, "webhooks_config", "webhook_id=?", id)
+	if err != nil {
+		return -1, nil, fmt.Errorf("Failed loading config: %w", err)
+	}
+
+	return id, &webhook, nil
+}
+
+// CreateWebhook creates a new webhook.
+func (c *ClusterTx) CreateWebhook(ctx context.Context, info api.WebhooksPost) (int64, error) {
+	result, err := c.tx.ExecContext(ctx, `INSERT INTO webhooks (name, description) VALUES (?, ?)`, info.Name, info.Description)
+	if err != nil {
+		return -1, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	err = webhookConfigAdd(ctx, c.tx, id, info.Config)
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// webhookConfigAdd inserts webhook config keys.
+func webhookConfigAdd(ctx context.Context, tx *sql.Tx, id int64, config map[string]string) error {
+	for k, v := range config {
+		if v == "" {
+			continue
+		}
+
+		_, err := tx.ExecContext(ctx, `INSERT INTO webhooks_config (webhook_id, key, value) VALUES (?, ?, ?)`, id, k, v)
+		if err != nil {
+			return fmt.Errorf("Failed inserting config: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateWebhook updates the webhook with the given ID.
+func (c *ClusterTx) UpdateWebhook(ctx context.Context, id int64, info api.WebhookPut) error {
+	_, err := c.tx.ExecContext(ctx, `UPDATE webhooks SET description=? WHERE id=?`, info.Description, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, `DELETE FROM webhooks_config WHERE webhook_id=?`, id)
+	if err != nil {
+		return err
+	}
+
+	return webhookConfigAdd(ctx, c.tx, id, info.Config)
+}
+
+// RenameWebhook renames the webhook with the given ID.
+func (c *ClusterTx) RenameWebhook(ctx context.Context, id int64, newName string) error {
+	_, err := c.tx.ExecContext(ctx, `UPDATE webhooks SET name=? WHERE id=?`, newName, id)
+
+	return err
+}
+
+// DeleteWebhook deletes the webhook with the given ID.
+func (c *ClusterTx) DeleteWebhook(ctx context.Context, id int64) error {
+	_, err := c.tx.ExecContext(ctx, `DELETE FROM webhooks WHERE id=?`, id)
+
+	return err
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/j-keck/arping"
 	"github.com/mdlayher/ndp"
 
+	"github.com/lxc/incus/v6/internal/server/bgp"
 	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
 	pcidev "github.com/lxc/incus/v6/internal/server/device/pci"
 	"github.com/lxc/incus/v6/internal/server/instance"
@@ -674,6 +675,43 @@ func bgpAddPrefix(d *deviceCommon, n network.Network, config map[string]string)
 		}
 	}
 
+	// Advertise the MAC address on EVPN enabled bridges.
+	if n.Type() == "bridge" {
+		evpnVNI, evpnLocal := network.BridgeEVPN(n.Config())
+		if evpnVNI > 0 && evpnLocal != nil {
+			hwaddr := config["hwaddr"]
+			if hwaddr == "" {
+				hwaddr = d.volatileGet()["hwaddr"]
+			}
+
+			mac, err := net.ParseMAC(hwaddr)
+			if err == nil {
+				route := bgp.EVPNRoute{
+					VNI:  evpnVNI,
+					VTEP: evpnLocal,
+					MAC:  mac,
+				}
+
+				err = d.state.BGP.AddEVPNRoute(route, bgpOwner)
+				if err != nil {
+					return err
+				}
+
+				for _, key := range []string{"ipv4.address", "ipv6.address"} {
+					route.IP = net.ParseIP(config[key])
+					if route.IP == nil {
+						continue
+					}
+
+					err = d.state.BGP.AddEVPNRoute(route, bgpOwner)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -689,6 +727,11 @@ func bgpRemovePrefix(d *deviceCommon, config map[string]string) error {
 		return err
 	}
 
+	err = d.state.BGP.RemoveEVPNRoutesByOwner(fmt.Sprintf("instance_%d_%s", d.inst.ID(), d.name))
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -169,6 +169,10 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 		return ErrUnsupportedDevType
 	}
 
+	if d.config["limits.group"] != "" && (d.config["limits.read"] != "" || d.config["limits.write"] != "" || d.config["limits.max"] != "") {
+		return fmt.Errorf(`The "limits.group" property can't be combined with "limits.read", "limits.write" or "limits.max"`)
+	}
+
 	// Supported propagation types.
 	// If an empty value is supplied the default behavior is to assume "private" mode.
 	// These come from https://www.kernel.org/doc/Documentation/filesystems/sharedsubtree.txt
@@ -251,6 +255,16 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 		//  shortdesc: I/O limit in byte/s or IOPS for both read and write (same as setting both `limits.read` and `limits.write`)
 		"limits.max": validate.IsAny,
 
+		// gendoc:generate(entity=devices, group=disk, key=limits.group)
+		// The disks of an instance that use the same group share the I/O limits set in the
+		// {config:option}`instance-resource-limits:limits.disk.group.<name>.read` and
+		// {config:option}`instance-resource-limits:limits.disk.group.<name>.write` instance options.
+		// ---
+		//  type: string
+		//  required: no
+		//  shortdesc: Name of the I/O limits group the disk belongs to - see also {ref}`storage-configure-IO-groups`
+		"limits.group": validate.Optional(validate.IsHostname),
+
 		// gendoc:generate(entity=devices, group=disk, key=size)
 		//
 		// ---
@@ -1037,9 +1051,9 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 
 	// Add I/O limits if set.
 	var diskLimits *deviceConfig.DiskLimits
-	if d.config["limits.read"] != "" || d.config["limits.write"] != "" || d.config["limits.max"] != "" {
+	if d.config["limits.read"] != "" || d.config["limits.write"] != "" || d.config["limits.max"] != "" || d.config["limits.group"] != "" {
 		// Parse the limits into usable values.
-		readBps, readIops, writeBps, writeIops, err := d.parseLimit(d.config)
+		readBps, readIops, writeBps, writeIops, err := d.getLimits(d.config)
 		if err != nil {
 			return nil, err
 		}
@@ -1063,10 +1077,11 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 
 		runConf.Mounts = []deviceConfig.MountEntryItem{
 			{
-				TargetPath: d.config["path"], // Indicator used that this is the root device.
-				DevName:    d.name,
-				Opts:       opts,
-				Limits:     diskLimits,
+				TargetPath:  d.config["path"], // Indicator used that this is the root device.
+				DevName:     d.name,
+				Opts:        opts,
+				Limits:      diskLimits,
+				LimitsGroup: d.config["limits.group"],
 			},
 		}
 
@@ -1137,19 +1152,21 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 			clusterName, userName := d.cephCreds()
 			runConf.Mounts = []deviceConfig.MountEntryItem{
 				{
-					DevPath: DiskGetRBDFormat(clusterName, userName, fields[0], fields[1]),
-					DevName: d.name,
-					Opts:    opts,
-					Limits:  diskLimits,
+					DevPath:     DiskGetRBDFormat(clusterName, userName, fields[0], fields[1]),
+					DevName:     d.name,
+					Opts:        opts,
+					Limits:      diskLimits,
+					LimitsGroup: d.config["limits.group"],
 				},
 			}
 		} else {
 			// Default to block device or image file passthrough first.
 			mount := deviceConfig.MountEntryItem{
-				DevPath: d.config["source"],
-				DevName: d.name,
-				Opts:    opts,
-				Limits:  diskLimits,
+				DevPath:     d.config["source"],
+				DevName:     d.name,
+				Opts:        opts,
+				Limits:      diskLimits,
+				LimitsGroup: d.config["limits.group"],
 			}
 
 			// Mount the pool volume and update srcPath to mount path so it can be recognised as dir
@@ -1204,10 +1221,11 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 					}
 
 					mount := deviceConfig.MountEntryItem{
-						DevPath: DiskGetRBDFormat(clusterName, userName, poolName, d.config["source"]),
-						DevName: d.name,
-						Opts:    opts,
-						Limits:  diskLimits,
+						DevPath:     DiskGetRBDFormat(clusterName, userName, poolName, d.config["source"]),
+						DevName:     d.name,
+						Opts:        opts,
+						Limits:      diskLimits,
+						LimitsGroup: d.config["limits.group"],
 					}
 
 					if contentType == db.StoragePoolVolumeContentTypeISO {
@@ -1494,7 +1512,7 @@ func (d *disk) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 
 		if d.inst.Type() == instancetype.VM {
 			// Parse the limits into usable values.
-			readBps, readIops, writeBps, writeIops, err := d.parseLimit(d.config)
+			readBps, readIops, writeBps, writeIops, err := d.getLimits(d.config)
 			if err != nil {
 				return err
 			}
@@ -1509,8 +1527,9 @@ func (d *disk) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 
 			runConf.Mounts = []deviceConfig.MountEntryItem{
 				{
-					DevName: d.name,
-					Limits:  diskLimits,
+					DevName:     d.name,
+					Limits:      diskLimits,
+					LimitsGroup: d.config["limits.group"],
 				},
 			}
 		}
@@ -1599,7 +1618,7 @@ func (d *disk) generateLimits(runConf *deviceConfig.RunConfig) error {
 			continue
 		}
 
-		if dev["limits.read"] != "" || dev["limits.write"] != "" || dev["limits.max"] != "" {
+		if dev["limits.read"] != "" || dev["limits.write"] != "" || dev["limits.max"] != "" || dev["limits.group"] != "" {
 			hasDiskLimits = true
 		}
 	}
@@ -2266,13 +2285,15 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 
 	// Process all the limits
 	blockLimits := map[string][]diskBlockLimit{}
+	groupLimits := map[string]diskBlockLimit{}
+	groupBlocks := map[string][]string{}
 	for devName, dev := range d.inst.ExpandedDevices() {
 		if dev["type"] != "disk" {
 			continue
 		}
 
 		// Parse the user input
-		readBps, readIops, writeBps, writeIops, err := d.parseLimit(dev)
+		readBps, readIops, writeBps, writeIops, err := d.getLimits(dev)
 		if err != nil {
 			return nil, err
 		}
@@ -2326,6 +2347,17 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 				return nil, fmt.Errorf("Block device doesn't support quotas %q", block)
 			}
 
+			// Members of a limits group share its limits, collect their block devices first.
+			group := dev["limits.group"]
+			if group != "" {
+				groupLimits[group] = device
+				if !slices.Contains(groupBlocks[group], blockStr) {
+					groupBlocks[group] = append(groupBlocks[group], blockStr)
+				}
+
+				continue
+			}
+
 			if blockLimits[blockStr] == nil {
 				blockLimits[blockStr] = []diskBlockLimit{}
 			}
@@ -2334,6 +2366,14 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 		}
 	}
 
+	// Split the limits of each group between the block devices its members use.
+	for group, blocks := range groupBlocks {
+		device := splitDiskGroupLimit(groupLimits[group], len(blocks))
+		for _, blockStr := range blocks {
+			blockLimits[blockStr] = append(blockLimits[blockStr], device)
+		}
+	}
+
 	// Average duplicate limits
 	for block, limits := range blockLimits {
 		var readBpsCount, readBpsTotal, readIopsCount, readIopsTotal, writeBpsCount, writeBpsTotal, writeIopsCount, writeIopsTotal int64
@@ -2384,6 +2424,47 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 	return result, nil
 }
 
+// splitDiskGroupLimit returns the share of the limits of a limits group applied to each of the block devices
+// used by its members. The cgroup limits apply per block device, so the group limits are split evenly between
+// them for the members to never exceed the group limits combined.
+func splitDiskGroupLimit(limit diskBlockLimit, blocks int) diskBlockLimit {
+	if blocks <= 1 {
+		return limit
+	}
+
+	split := func(value int64) int64 {
+		if value <= 0 {
+			return value
+		}
+
+		return max(value/int64(blocks), 1)
+	}
+
+	return diskBlockLimit{
+		readBps:   split(limit.readBps),
+		readIops:  split(limit.readIops),
+		writeBps:  split(limit.writeBps),
+		writeIops: split(limit.writeIops),
+	}
+}
+
+// getLimits returns the I/O bytes/iops limits of a disk, using those of its limits group if it belongs to one.
+func (d *disk) getLimits(dev deviceConfig.Device) (int64, int64, int64, int64, error) {
+	group := dev["limits.group"]
+	if group == "" {
+		return d.parseLimit(dev)
+	}
+
+	prefix := fmt.Sprintf("limits.disk.group.%s.", group)
+	config := d.inst.ExpandedConfig()
+
+	return d.parseLimit(deviceConfig.Device{
+		"limits.read":  config[prefix+"read"],
+		"limits.write": config[prefix+"write"],
+		"limits.max":   config[prefix+"max"],
+	})
+}
+
 // parseLimit parses the disk configuration for its I/O limits and returns the I/O bytes/iops limits.
 func (d *disk) parseLimit(dev deviceConfig.Device) (int64, int64, int64, int64, error) {
 	readSpeed := dev["limits.read"]
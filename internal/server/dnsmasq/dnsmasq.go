@@ -2,11 +2,13 @@ package dnsmasq
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"net"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -107,6 +109,106 @@ func Kill(name string, reload bool) error {
 	return nil
 }
 
+// Restart restarts dnsmasq for a particular network with its existing arguments.
+func Restart(name string) error {
+	pidPath := internalUtil.VarPath("networks", name, "dnsmasq.pid")
+
+	// If the pid file doesn't exist, there is no process to restart.
+	if !util.PathExists(pidPath) {
+		return nil
+	}
+
+	// Import saved subprocess details
+	p, err := subprocess.ImportProcess(pidPath)
+	if err != nil {
+		return fmt.Errorf("Could not read pid file: %s", err)
+	}
+
+	err = p.Stop()
+	if err != nil && err != subprocess.ErrNotRunning {
+		return fmt.Errorf("Unable to kill dnsmasq: %s", err)
+	}
+
+	time.Sleep(100 * time.Millisecond) // Give OS time to release sockets.
+
+	logFile, err := os.OpenFile(internalUtil.LogPath(fmt.Sprintf("dnsmasq.%s.log", name)), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	p.Stderr = logFile
+
+	err = p.Start(context.Background())
+	if err != nil {
+		return fmt.Errorf("Unable to start dnsmasq: %w", err)
+	}
+
+	return p.Save(pidPath)
+}
+
+// NftSetsPath returns the path of the dnsmasq config file listing the nftables sets to populate for a network.
+func NftSetsPath(network string) string {
+	return internalUtil.VarPath("networks", network, "dnsmasq.nftset")
+}
+
+// NftSetsSupported returns whether the dnsmasq version is able to populate nftables sets.
+func NftSetsSupported(dnsmasqVersion *version.DottedVersion) bool {
+	minVer, _ := version.NewDottedVersion("2.87")
+
+	return dnsmasqVersion.Compare(minVer) >= 0
+}
+
+// UpdateNftSets writes the dnsmasq config populating the nftables sets with the addresses resolved for a domain.
+// The sets map domain names to set name prefixes, the sets being found in both the inet and bridge "incus" tables.
+// The TTL of the DNS records is capped to maxTTL seconds so that the domains get resolved again, refreshing
+// the sets, before the addresses expire from them.
+// Returns whether the config changed.
+func UpdateNftSets(network string, sets map[string]string, maxTTL uint64) (bool, error) {
+	config := nftSetsConfig(sets, maxTTL)
+	path := NftSetsPath(network)
+
+	current, err := os.ReadFile(path)
+	if err == nil && string(current) == config {
+		return false, nil
+	}
+
+	err = os.WriteFile(path, []byte(config), 0644)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// nftSetsConfig generates the dnsmasq config populating the nftables sets with the addresses resolved for a domain.
+func nftSetsConfig(sets map[string]string, maxTTL uint64) string {
+	domains := make([]string, 0, len(sets))
+	for domain := range sets {
+		domains = append(domains, domain)
+	}
+
+	slices.Sort(domains)
+
+	var sb strings.Builder
+	if len(domains) > 0 && maxTTL > 0 {
+		// Limit both the TTL handed out to the instances and the cache TTL, dnsmasq only populating the sets
+		// with the answers received from the upstream servers.
+		sb.WriteString(fmt.Sprintf("max-ttl=%d\n", maxTTL))
+		sb.WriteString(fmt.Sprintf("max-cache-ttl=%d\n", maxTTL))
+	}
+
+	for _, domain := range domains {
+		entries := []string{}
+		for _, family := range []string{"inet", "bridge"} {
+			entries = append(entries, fmt.Sprintf("4#%s#incus#%s_ip4", family, sets[domain]), fmt.Sprintf("6#%s#incus#%s_ip6", family, sets[domain]))
+		}
+
+		sb.WriteString(fmt.Sprintf("nftset=/%s/%s\n", domain, strings.Join(entries, ",")))
+	}
+
+	return sb.String()
+}
+
 // GetVersion returns the version of dnsmasq.
 func GetVersion() (*version.DottedVersion, error) {
 	output, err := subprocess.RunCommandCLocale("dnsmasq", "--version")
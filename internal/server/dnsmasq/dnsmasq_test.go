@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/version"
 )
 
 func Test_staticAllocationFileName(t *testing.T) {
@@ -13,3 +16,41 @@ func Test_staticAllocationFileName(t *testing.T) {
 	fileName := StaticAllocationFileName(projectName, instanceName, deviceName)
 	assert.Equal(t, "test.project_test-instance.test-.--_----.device", fileName)
 }
+
+func Test_nftSetsConfig(t *testing.T) {
+	sets := map[string]string{
+		"example.com": "dns_a",
+		"debian.org":  "dns_b",
+	}
+
+	expected := `max-ttl=600
+max-cache-ttl=600
+nftset=/debian.org/4#inet#incus#dns_b_ip4,6#inet#incus#dns_b_ip6,4#bridge#incus#dns_b_ip4,6#bridge#incus#dns_b_ip6
+nftset=/example.com/4#inet#incus#dns_a_ip4,6#inet#incus#dns_a_ip6,4#bridge#incus#dns_a_ip4,6#bridge#incus#dns_a_ip6
+`
+
+	assert.Equal(t, expected, nftSetsConfig(sets, 600))
+
+	// Without any domain, the TTL of the DNS records is left alone.
+	assert.Equal(t, "", nftSetsConfig(map[string]string{}, 600))
+}
+
+func Test_NftSetsSupported(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected bool
+	}{
+		{version: "2.80", expected: false},
+		{version: "2.86", expected: false},
+		{version: "2.87", expected: true},
+		{version: "2.90", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			dnsmasqVersion, err := version.Parse(tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, NftSetsSupported(dnsmasqVersion))
+		})
+	}
+}
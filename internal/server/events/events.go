@@ -40,6 +40,7 @@ type Server struct {
 	listeners map[string]*Listener
 	notify    NotifyFunc
 	location  string
+	history   *History
 }
 
 // NewServer returns a new event server.
@@ -65,8 +66,40 @@ func (s *Server) SetLocalLocation(location string) {
 	s.location = location
 }
 
+// SetHistory sets the history used to record the events and replay them to listeners.
+func (s *Server) SetHistory(history *History) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.history = history
+}
+
+// CheckHistory checks that the recorded events following the given event identifier are available.
+func (s *Server) CheckHistory(since uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.history == nil {
+		return fmt.Errorf("Event history isn't available")
+	}
+
+	_, err := s.history.Since(since)
+
+	return err
+}
+
 // AddListener creates and returns a new event listener.
 func (s *Server) AddListener(projectName string, allProjects bool, projectPermissionFunc auth.PermissionChecker, connection EventListenerConnection, messageTypes []string, excludeSources []EventSource, recvFunc EventHandler, excludeLocations []string) (*Listener, error) {
+	return s.addListener(nil, projectName, allProjects, projectPermissionFunc, connection, messageTypes, excludeSources, recvFunc, excludeLocations)
+}
+
+// AddListenerSince creates and returns a new event listener, first sending it the recorded events following the
+// given event identifier.
+func (s *Server) AddListenerSince(since uint64, projectName string, allProjects bool, projectPermissionFunc auth.PermissionChecker, connection EventListenerConnection, messageTypes []string, excludeSources []EventSource, recvFunc EventHandler, excludeLocations []string) (*Listener, error) {
+	return s.addListener(&since, projectName, allProjects, projectPermissionFunc, connection, messageTypes, excludeSources, recvFunc, excludeLocations)
+}
+
+func (s *Server) addListener(since *uint64, projectName string, allProjects bool, projectPermissionFunc auth.PermissionChecker, connection EventListenerConnection, messageTypes []string, excludeSources []EventSource, recvFunc EventHandler, excludeLocations []string) (*Listener, error) {
 	if allProjects && projectName != "" {
 		return nil, fmt.Errorf("Cannot specify project name when listening for events on all projects")
 	}
@@ -94,16 +127,52 @@ func (s *Server) AddListener(projectName string, allProjects bool, projectPermis
 	}
 
 	s.lock.Lock()
-	defer s.lock.Unlock()
 
 	if s.listeners[listener.id] != nil {
+		s.lock.Unlock()
 		return nil, fmt.Errorf("A listener with ID %q already exists", listener.id)
 	}
 
+	// Get the events to replay while holding the lock so that no event is missed or sent twice.
+	var replay []historyEntry
+	if since != nil {
+		if s.history == nil {
+			s.lock.Unlock()
+			return nil, fmt.Errorf("Event history isn't available")
+		}
+
+		var err error
+		replay, err = s.history.Since(*since)
+		if err != nil {
+			s.lock.Unlock()
+			return nil, err
+		}
+
+		// Hold new events until the recorded ones have been sent.
+		listener.replayDone = make(chan struct{})
+	}
+
 	s.listeners[listener.id] = listener
+	s.lock.Unlock()
 
 	go listener.start()
 
+	if listener.replayDone != nil {
+		defer close(listener.replayDone)
+
+		for _, entry := range replay {
+			if !listener.wants(entry.Event, entry.Source) {
+				continue
+			}
+
+			err := listener.WriteJSON(entry.Event)
+			if err != nil {
+				listener.Close()
+				break
+			}
+		}
+	}
+
 	return listener, nil
 }
 
@@ -156,16 +225,6 @@ func (s *Server) Inject(event api.Event, eventSource EventSource) {
 }
 
 func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
-	sourceInSlice := func(source EventSource, sources []EventSource) bool {
-		for _, i := range sources {
-			if source == i {
-				return true
-			}
-		}
-
-		return false
-	}
-
 	s.lock.Lock()
 
 	// Set the Location for local events to the local serverName if not already populated (do it here rather
@@ -174,6 +233,13 @@ func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
 		event.Location = s.location
 	}
 
+	// Record the event in the local history, replacing any identifier set by another member.
+	// Logging events are too numerous to be worth replaying and so aren't recorded.
+	event.ID = 0
+	if s.history != nil && event.Type != api.EventTypeLogging {
+		s.history.Add(&event, eventSource)
+	}
+
 	// If a notifcation hook is present, then call it for locally produced events.
 	// This can be used to send local events to another target (such as an event-hub member).
 	if s.notify != nil && eventSource == EventSourceLocal {
@@ -182,26 +248,7 @@ func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
 
 	listeners := s.listeners
 	for _, listener := range listeners {
-		// If the event is project specific, check if the listener is requesting events from that project.
-		if event.Project != "" && !listener.allProjects && event.Project != listener.projectName {
-			continue
-		}
-
-		// If the event is project specific, ensure we have permission to view it.
-		if event.Project != "" && !listener.projectPermissionFunc(auth.ObjectProject(event.Project)) {
-			continue
-		}
-
-		if sourceInSlice(eventSource, listener.excludeSources) {
-			continue
-		}
-
-		if !slices.Contains(listener.messageTypes, event.Type) {
-			continue
-		}
-
-		// If the event doesn't come from this member and has been excluded by listener, don't deliver it.
-		if eventSource != EventSourceLocal && slices.Contains(listener.excludeLocations, event.Location) {
+		if !listener.wants(event, eventSource) {
 			continue
 		}
 
@@ -211,6 +258,11 @@ func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
 				return
 			}
 
+			// Wait for the recorded events to be replayed first.
+			if listener.replayDone != nil {
+				<-listener.replayDone
+			}
+
 			// Make sure we're not done already
 			if listener.IsClosed() {
 				// Remove the listener from the list
@@ -246,4 +298,33 @@ type Listener struct {
 	projectPermissionFunc auth.PermissionChecker
 	excludeSources        []EventSource
 	excludeLocations      []string
+	replayDone            chan struct{}
+}
+
+// wants returns whether the event should be delivered to the listener.
+func (l *Listener) wants(event api.Event, eventSource EventSource) bool {
+	// If the event is project specific, check if the listener is requesting events from that project.
+	if event.Project != "" && !l.allProjects && event.Project != l.projectName {
+		return false
+	}
+
+	// If the event is project specific, ensure we have permission to view it.
+	if event.Project != "" && !l.projectPermissionFunc(auth.ObjectProject(event.Project)) {
+		return false
+	}
+
+	if slices.Contains(l.excludeSources, eventSource) {
+		return false
+	}
+
+	if !slices.Contains(l.messageTypes, event.Type) {
+		return false
+	}
+
+	// If the event doesn't come from this member and has been excluded by listener, don't deliver it.
+	if eventSource != EventSourceLocal && slices.Contains(l.excludeLocations, event.Location) {
+		return false
+	}
+
+	return true
 }
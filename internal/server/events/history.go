@@ -0,0 +1,277 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// historyQueueSize is how many recorded events may wait to be written to disk.
+const historyQueueSize = 1024
+
+// historyEntry is a recorded event along with the source it was received from.
+type historyEntry struct {
+	Event  api.Event   `json:"event"`
+	Source EventSource `json:"source"`
+}
+
+// History is a bounded ring of recent events persisted to disk.
+// Each recorded event is given a monotonically increasing identifier which clients can use to replay the
+// events they missed.
+type History struct {
+	path    string
+	size    int
+	entries []historyEntry
+	nextID  uint64
+	lock    sync.Mutex
+
+	// Events are written to disk in the background so that recording them never waits on I/O.
+	pending  chan historyEntry
+	overflow bool // The queue was full, so the file must be rewritten from the ring.
+	closed   bool
+	done     chan struct{}
+
+	// Only used by the writer once the history is loaded.
+	file      *os.File
+	lines     int
+	writtenID uint64
+}
+
+// NewHistory loads the event history stored at the given path, keeping at most size events.
+func NewHistory(path string, size int) (*History, error) {
+	h := &History{
+		path:    path,
+		size:    size,
+		entries: []historyEntry{},
+		nextID:  1,
+		pending: make(chan historyEntry, historyQueueSize),
+		done:    make(chan struct{}),
+	}
+
+	f, err := os.Open(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Failed opening event history: %w", err)
+	}
+
+	if err == nil {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+		for scanner.Scan() {
+			var entry historyEntry
+
+			// Skip entries which may have been partially written during a crash.
+			err := json.Unmarshal(scanner.Bytes(), &entry)
+			if err != nil || entry.Event.ID < h.nextID {
+				continue
+			}
+
+			h.append(entry)
+		}
+
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Failed reading event history: %w", err)
+		}
+	}
+
+	// Rewrite the file to drop the expired and invalid entries.
+	err = h.compact()
+	if err != nil {
+		return nil, err
+	}
+
+	go h.writer()
+
+	return h, nil
+}
+
+// Add records the event, setting its identifier. The event is written to disk in the background.
+func (h *History) Add(event *api.Event, source EventSource) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	event.ID = h.nextID
+	entry := historyEntry{Event: *event, Source: source}
+	h.append(entry)
+
+	if h.closed {
+		return
+	}
+
+	select {
+	case h.pending <- entry:
+	default:
+		// The writer is lagging behind, have it rewrite the file from the ring instead.
+		h.overflow = true
+	}
+}
+
+// Since returns the recorded events following the given event identifier.
+func (h *History) Since(id uint64) ([]historyEntry, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if id >= h.nextID {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Event %d hasn't been recorded yet", id)
+	}
+
+	if id+1 == h.nextID {
+		return []historyEntry{}, nil
+	}
+
+	if len(h.entries) == 0 || id+1 < h.entries[0].Event.ID {
+		return nil, api.StatusErrorf(http.StatusGone, "Events following %d are no longer available", id)
+	}
+
+	// Identifiers may have gaps if invalid entries were skipped when loading the history.
+	start := sort.Search(len(h.entries), func(i int) bool { return h.entries[i].Event.ID > id })
+
+	return append([]historyEntry(nil), h.entries[start:]...), nil
+}
+
+// Close writes the pending events and closes the history file.
+func (h *History) Close() error {
+	h.lock.Lock()
+	if h.closed {
+		h.lock.Unlock()
+		return nil
+	}
+
+	h.closed = true
+	close(h.pending)
+	h.lock.Unlock()
+
+	<-h.done
+
+	if h.file == nil {
+		return nil
+	}
+
+	err := h.file.Close()
+	h.file = nil
+
+	return err
+}
+
+// append adds an entry to the ring, dropping the oldest entry if full.
+func (h *History) append(entry historyEntry) {
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > h.size {
+		h.entries = h.entries[len(h.entries)-h.size:]
+	}
+
+	h.nextID = entry.Event.ID + 1
+}
+
+// writer writes the recorded events to the history file until the history is closed.
+func (h *History) writer() {
+	defer close(h.done)
+
+	for entry := range h.pending {
+		err := h.write(entry)
+		if err != nil {
+			logger.Warn("Failed writing event history", logger.Ctx{"path": h.path, "err": err})
+		}
+	}
+}
+
+// write appends an entry to the history file, rewriting the file instead when it grew too large or
+// when events couldn't be queued.
+func (h *History) write(entry historyEntry) error {
+	h.lock.Lock()
+	overflow := h.overflow
+	h.overflow = false
+	h.lock.Unlock()
+
+	// Rewrite the file once it holds twice as many events as the ring.
+	if overflow || h.file == nil || h.lines >= 2*h.size {
+		return h.compact()
+	}
+
+	// Skip entries already written by the last rewrite.
+	if entry.Event.ID <= h.writtenID {
+		return nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.file.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("Failed writing event history: %w", err)
+	}
+
+	h.lines++
+	h.writtenID = entry.Event.ID
+
+	return nil
+}
+
+// compact rewrites the history file with only the events currently in the ring.
+func (h *History) compact() error {
+	if h.file != nil {
+		_ = h.file.Close()
+		h.file = nil
+	}
+
+	h.lock.Lock()
+	entries := append([]historyEntry(nil), h.entries...)
+	h.lock.Unlock()
+
+	tmpPath := h.path + ".tmp"
+
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed creating event history: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+
+		_, _ = w.Write(append(line, '\n'))
+	}
+
+	err = w.Flush()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("Failed writing event history: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpPath, h.path)
+	if err != nil {
+		return fmt.Errorf("Failed replacing event history: %w", err)
+	}
+
+	h.file, err = os.OpenFile(h.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed opening event history: %w", err)
+	}
+
+	h.lines = len(entries)
+	if len(entries) > 0 {
+		h.writtenID = entries[len(entries)-1].Event.ID
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	h, err := NewHistory(path, 3)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		event := api.Event{Type: api.EventTypeLifecycle}
+		h.Add(&event, EventSourceLocal)
+		assert.Equal(t, uint64(i+1), event.ID)
+	}
+
+	entries, err := h.Since(8)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, uint64(9), entries[0].Event.ID)
+	assert.Equal(t, uint64(10), entries[1].Event.ID)
+
+	entries, err = h.Since(10)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = h.Since(6)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusGone))
+
+	_, err = h.Since(11)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusBadRequest))
+
+	require.NoError(t, h.Close())
+
+	// The identifiers keep increasing after reloading the history.
+	h, err = NewHistory(path, 3)
+	require.NoError(t, err)
+
+	entries, err = h.Since(7)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	event := api.Event{Type: api.EventTypeLifecycle}
+	h.Add(&event, EventSourceLocal)
+	assert.Equal(t, uint64(11), event.ID)
+
+	require.NoError(t, h.Close())
+}
+
+func TestHistoryCorruptEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	content := `{"event":{"id":1,"type":"lifecycle"},"source":0}
+{"event":{"id":2,"type":"lifecycle"},"source":0}
+{"event":{"id":3,"type":"lifec
+{"event":{"id":4,"type":"lifecycle"},"source":0}
+{"event":{"id":5,"type":"lifecycle"},"source":0}
+`
+
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	h, err := NewHistory(path, 10)
+	require.NoError(t, err)
+
+	defer func() { _ = h.Close() }()
+
+	// Events following a skipped entry are replayed from the right position.
+	entries, err := h.Since(2)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, uint64(4), entries[0].Event.ID)
+	assert.Equal(t, uint64(5), entries[1].Event.ID)
+
+	entries, err = h.Since(3)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, uint64(4), entries[0].Event.ID)
+
+	entries, err = h.Since(1)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	event := api.Event{Type: api.EventTypeLifecycle}
+	h.Add(&event, EventSourceLocal)
+	assert.Equal(t, uint64(6), event.ID)
+}
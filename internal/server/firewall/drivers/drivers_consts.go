@@ -39,6 +39,17 @@ type ACLRule struct {
 	DestinationPort string
 	ICMPType        string
 	ICMPCode        string
+	RateLimit       string // Packet rate in <count>/<unit> or bandwidth in <size>/<unit> format.
+	ConnectionRate  string // New connection rate in <count>/<unit> format.
+	ConnectionLimit string // Maximum number of concurrent connections.
+}
+
+// AddressSet represents a named set of addresses that ACL rules can reference as "$<name>".
+type AddressSet struct {
+	Name      string
+	Addresses []string // IP addresses or subnets.
+	Dynamic   bool     // Content is populated externally (by dnsmasq) and is left untouched.
+	Timeout   uint64   // Seconds after which the dynamically added addresses expire (0 for never).
 }
 
 // AddressForward represents a NAT address forward.
@@ -18,6 +18,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
 	"github.com/lxc/incus/v6/shared/validate"
 )
@@ -129,11 +130,11 @@ func (d Nftables) Compat() (bool, error) {
 
 // nftGenericItem represents some common fields amongst the different nftables types.
 type nftGenericItem struct {
-	ItemType string `json:"-"`      // Type of item (table, chain or rule). Populated by Incus.
+	ItemType string `json:"-"`      // Type of item (table, chain, set or rule). Populated by Incus.
 	Family   string `json:"family"` // Family of item (ip, ip6, bridge etc).
 	Table    string `json:"table"`  // Table the item belongs to (for chains and rules).
 	Chain    string `json:"chain"`  // Chain the item belongs to (for rules).
-	Name     string `json:"name"`   // Name of item (for tables and chains).
+	Name     string `json:"name"`   // Name of item (for tables, chains and sets).
 }
 
 // nftParseRuleset parses the ruleset and returns the generic parts as a slice of items.
@@ -167,6 +168,7 @@ func (d Nftables) nftParseRuleset() ([]nftGenericItem, error) {
 		rule, foundRule := item["rule"]
 		chain, foundChain := item["chain"]
 		table, foundTable := item["table"]
+		set, foundSet := item["set"]
 		if foundRule {
 			rule.ItemType = "rule"
 			items = append(items, rule)
@@ -176,6 +178,9 @@ func (d Nftables) nftParseRuleset() ([]nftGenericItem, error) {
 		} else if foundTable {
 			table.ItemType = "table"
 			items = append(items, table)
+		} else if foundSet {
+			set.ItemType = "set"
+			items = append(items, set)
 		}
 	}
 
@@ -462,6 +467,7 @@ func (d Nftables) InstanceSetupBridgeFilter(projectName string, instanceName str
 	}
 
 	// Set the template fields for the ACL rules.
+	tplFields["aclInRateRules"] = nftRules.inRateRules
 	tplFields["aclInDropRules"] = nftRules.inDropRules
 	tplFields["aclInRejectRules"] = nftRules.inRejectRules
 	tplFields["aclInRejectRulesConverted"] = nftRules.inRejectRulesConverted
@@ -469,6 +475,7 @@ func (d Nftables) InstanceSetupBridgeFilter(projectName string, instanceName str
 	tplFields["aclInDefaultRule"] = nftRules.defaultInRule
 	tplFields["aclInDefaultRuleConverted"] = nftRules.defaultInRuleConverted
 
+	tplFields["aclOutRateRules"] = nftRules.outRateRules
 	tplFields["aclOutDropRules"] = nftRules.outDropRules
 	tplFields["aclOutAcceptRules"] = nftRules.outAcceptRules
 	tplFields["aclOutDefaultRule"] = nftRules.defaultOutRule
@@ -599,11 +606,13 @@ func (d Nftables) InstanceClearProxyNAT(projectName string, instanceName string,
 
 // nftRulesCollection contains the ACL rules translated to NFT rules and split in groups.
 type nftRulesCollection struct {
+	inRateRules            []string
 	inDropRules            []string
 	inRejectRules          []string
 	inRejectRulesConverted []string
 	inAcceptRules4         []string
 	inAcceptRules6         []string
+	outRateRules           []string
 	outDropRules           []string
 	outAcceptRules         []string
 	defaultInRule          string
@@ -614,11 +623,13 @@ type nftRulesCollection struct {
 // aclRulesToNftRules converts ACL rules applied to the device to NFT rules.
 func (d Nftables) aclRulesToNftRules(hostName string, aclRules []ACLRule) (*nftRulesCollection, error) {
 	nftRules := nftRulesCollection{
+		inRateRules:            make([]string, 0),
 		inDropRules:            make([]string, 0),
 		inRejectRules:          make([]string, 0),
 		inRejectRulesConverted: make([]string, 0), // To be used in the forward chain where reject is not supported
 		inAcceptRules4:         make([]string, 0),
 		inAcceptRules6:         make([]string, 0),
+		outRateRules:           make([]string, 0),
 		outDropRules:           make([]string, 0),
 		outAcceptRules:         make([]string, 0),
 		defaultInRule:          "",
@@ -664,6 +675,26 @@ func (d Nftables) aclRulesToNftRules(hostName string, aclRules []ACLRule) (*nftR
 			continue
 		}
 
+		rateRule, remainingRule := aclRuleSplitRateLimit(rule)
+		if rateRule != nil {
+			_, _, rateNftRules, err := d.aclRuleToNftRules(hostNameQuoted, *rateRule)
+			if err != nil {
+				return nil, err
+			}
+
+			if rule.Direction == "ingress" {
+				nftRules.outRateRules = append(nftRules.outRateRules, rateNftRules...)
+			} else {
+				nftRules.inRateRules = append(nftRules.inRateRules, rateNftRules...)
+			}
+		}
+
+		if remainingRule == nil {
+			continue
+		}
+
+		rule = *remainingRule
+
 		if rule.Direction == "ingress" && rule.Action == "reject" {
 			// Convert ingress reject rules to drop rules to address nftables limitation.
 			rule.Action = "drop"
@@ -729,6 +760,47 @@ func (d Nftables) aclRulesToNftRules(hostName string, aclRules []ACLRule) (*nftR
 	return &nftRules, nil
 }
 
+// aclRuleSplitRateLimit splits the packet rate limit of an ACL rule into a separate rule dropping the excess.
+// Unlike the other criteria, the rate limit applies to all packets so the rate rule must be evaluated before
+// the established and related connections get accepted. Returns the rate rule (nil if the rule has no rate limit)
+// and the remaining rule (nil for drop and reject rules as those only apply to the traffic exceeding the limit).
+func aclRuleSplitRateLimit(rule ACLRule) (*ACLRule, *ACLRule) {
+	if rule.RateLimit == "" {
+		return nil, &rule
+	}
+
+	rateRule := rule
+	rateRule.Action = "drop"
+	rateRule.ConnectionRate = ""
+	rateRule.ConnectionLimit = ""
+
+	if rule.Action != "allow" {
+		return &rateRule, nil
+	}
+
+	rule.RateLimit = ""
+
+	return &rateRule, &rule
+}
+
+// nftablesLimitRate converts an ACL rate limit into the nftables limit rate syntax.
+// Packet rates (<count>/<unit>) are used as is and bandwidths (<size>/<unit>) are converted to bytes.
+func nftablesLimitRate(rate string) string {
+	count, unit, _ := strings.Cut(rate, "/")
+
+	_, err := strconv.ParseUint(count, 10, 32)
+	if err == nil {
+		return rate
+	}
+
+	bits, err := units.ParseBitSizeString(count)
+	if err != nil {
+		return rate
+	}
+
+	return fmt.Sprintf("%d bytes/%s", bits/8, unit)
+}
+
 func (d Nftables) aclRuleToNftRules(hostNameQuoted string, rule ACLRule) (string, string, []string, error) {
 	nft4Rule := ""
 	nft6Rule := ""
@@ -747,7 +819,8 @@ func (d Nftables) aclRuleToNftRules(hostNameQuoted string, rule ACLRule) (string
 			return "", "", nil, err
 		}
 
-		if nft6Rule == "" {
+		// Address sets may not need an IPv6 rule when combined with IPv4 only criteria.
+		if nft6Rule == "" && nft4Rule == "" {
 			return "", "", nil, fmt.Errorf("Invalid empty rule generated")
 		}
 	} else if nft4Rule == "" {
@@ -903,34 +976,44 @@ func (d Nftables) InstanceClearNetPrio(projectName string, instanceName string,
 
 // NetworkApplyACLRules applies ACL rules to the existing firewall chains.
 func (d Nftables) NetworkApplyACLRules(networkName string, rules []ACLRule) error {
-	nftRules := make([]string, 0)
-	for _, rule := range rules {
-		// First try generating rules with IPv4 or IP agnostic criteria.
-		nftRule, partial, err := d.aclRuleCriteriaToRules(networkName, 4, &rule)
-		if err != nil {
-			return err
-		}
+	config, err := d.networkACLRulesConfig(networkName, rules)
+	if err != nil {
+		return err
+	}
 
-		if nftRule != "" {
-			nftRules = append(nftRules, nftRule)
-		}
+	err = subprocess.RunCommandWithFds(context.TODO(), strings.NewReader(config), nil, "nft", "-f", "-")
+	if err != nil {
+		return err
+	}
 
-		if partial {
-			// If we couldn't fully generate the ruleset with only IPv4 or IP agnostic criteria, then
-			// fill in the remaining parts using IPv6 criteria.
-			nftRule, _, err = d.aclRuleCriteriaToRules(networkName, 6, &rule)
+	return nil
+}
+
+// networkACLRulesConfig generates the nftables config for the ACL rules of a network.
+func (d Nftables) networkACLRulesConfig(networkName string, rules []ACLRule) (string, error) {
+	rateRules := make([]string, 0)
+	nftRules := make([]string, 0)
+	for _, rule := range rules {
+		rateRule, remainingRule := aclRuleSplitRateLimit(rule)
+		if rateRule != nil {
+			_, _, newNftRules, err := d.aclRuleToNftRules(networkName, *rateRule)
 			if err != nil {
-				return err
+				return "", err
 			}
 
-			if nftRule == "" {
-				return fmt.Errorf("Invalid empty rule generated")
-			}
+			rateRules = append(rateRules, newNftRules...)
+		}
 
-			nftRules = append(nftRules, nftRule)
-		} else if nftRule == "" {
-			return fmt.Errorf("Invalid empty rule generated")
+		if remainingRule == nil {
+			continue
 		}
+
+		_, _, newNftRules, err := d.aclRuleToNftRules(networkName, *remainingRule)
+		if err != nil {
+			return "", err
+		}
+
+		nftRules = append(nftRules, newNftRules...)
 	}
 
 	tplFields := map[string]any{
@@ -938,20 +1021,108 @@ func (d Nftables) NetworkApplyACLRules(networkName string, rules []ACLRule) erro
 		"chainSeparator": nftablesChainSeparator,
 		"networkName":    networkName,
 		"family":         "inet",
+		"rateRules":      rateRules,
 		"rules":          nftRules,
 	}
 
 	config := &strings.Builder{}
 	err := nftablesNetACLRules.Execute(config, tplFields)
 	if err != nil {
-		return fmt.Errorf("Failed running %q template: %w", nftablesNetACLRules.Name(), err)
+		return "", fmt.Errorf("Failed running %q template: %w", nftablesNetACLRules.Name(), err)
 	}
 
-	err = subprocess.RunCommandWithFds(context.TODO(), strings.NewReader(config.String()), nil, "nft", "-f", "-")
+	return config.String(), nil
+}
+
+// NetworkApplyAddressSets creates or atomically replaces the content of the named sets for the address sets.
+func (d Nftables) NetworkApplyAddressSets(sets []AddressSet) error {
+	config, err := d.addressSetsConfig(sets)
+	if err != nil {
+		return err
+	}
+
+	err = subprocess.RunCommandWithFds(context.TODO(), strings.NewReader(config), nil, "nft", "-f", "-")
+	if err != nil {
+		return fmt.Errorf("Failed applying address sets: %w", err)
+	}
+
+	return nil
+}
+
+// addressSetsConfig generates the nftables config for the named sets of the address sets.
+func (d Nftables) addressSetsConfig(sets []AddressSet) (string, error) {
+	tplSets := make([]map[string]any, 0, len(sets)*2)
+	for _, set := range sets {
+		ipv4Addresses := []string{}
+		ipv6Addresses := []string{}
+
+		for _, address := range set.Addresses {
+			ip := net.ParseIP(address)
+			if ip == nil {
+				ip, _, _ = net.ParseCIDR(address)
+			}
+
+			if ip == nil {
+				return "", fmt.Errorf("Invalid address %q in address set %q", address, set.Name)
+			}
+
+			if ip.To4() == nil {
+				ipv6Addresses = append(ipv6Addresses, address)
+			} else {
+				ipv4Addresses = append(ipv4Addresses, address)
+			}
+		}
+
+		tplSets = append(tplSets, map[string]any{
+			"name":      fmt.Sprintf("%s_ip4", set.Name),
+			"type":      "ipv4_addr",
+			"addresses": strings.Join(ipv4Addresses, ", "),
+			"dynamic":   set.Dynamic,
+			"timeout":   set.Timeout,
+		}, map[string]any{
+			"name":      fmt.Sprintf("%s_ip6", set.Name),
+			"type":      "ipv6_addr",
+			"addresses": strings.Join(ipv6Addresses, ", "),
+			"dynamic":   set.Dynamic,
+			"timeout":   set.Timeout,
+		})
+	}
+
+	tplFields := map[string]any{
+		"namespace": nftablesNamespace,
+		"families":  []string{"inet", "bridge"},
+		"sets":      tplSets,
+	}
+
+	config := &strings.Builder{}
+	err := nftablesAddressSets.Execute(config, tplFields)
+	if err != nil {
+		return "", fmt.Errorf("Failed running %q template: %w", nftablesAddressSets.Name(), err)
+	}
+
+	return config.String(), nil
+}
+
+// NetworkDeleteAddressSet removes the named sets of an address set.
+func (d Nftables) NetworkDeleteAddressSet(name string) error {
+	ruleset, err := d.nftParseRuleset()
 	if err != nil {
 		return err
 	}
 
+	setNames := []string{fmt.Sprintf("%s_ip4", name), fmt.Sprintf("%s_ip6", name)}
+
+	for _, item := range ruleset {
+		if item.ItemType != "set" || item.Table != nftablesNamespace || !slices.Contains(setNames, item.Name) {
+			continue
+		}
+
+		_, err = subprocess.RunCommand("nft", "delete", "set", item.Family, nftablesNamespace, item.Name)
+		if err != nil {
+			return fmt.Errorf("Failed deleting nftables set %q (%s): %w", item.Name, item.Family, err)
+		}
+	}
+
 	return nil
 }
 
@@ -1029,11 +1200,11 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 		}
 
 		if ipVersion != icmpIPVersion {
-			// If we got this far it means that source/destination are either empty or are filled
-			// with at least some subjects in the same family as ipVersion. So if the icmpIPVersion
+			// If we got this far it means that source/destination are either empty, address sets or are
+			// filled with at least some subjects in the same family as ipVersion. So if the icmpIPVersion
 			// doesn't match the ipVersion then it means the rule contains mixed-version subjects
 			// which is invalid when using an IP version specific ICMP protocol.
-			if rule.Source != "" || rule.Destination != "" {
+			if (rule.Source != "" && !d.aclRuleSubjectIsAddressSet(rule.Source)) || (rule.Destination != "" && !d.aclRuleSubjectIsAddressSet(rule.Destination)) {
 				return "", false, fmt.Errorf("Invalid use of %q protocol with non-IPv%d source/destination criteria", rule.Protocol, ipVersion)
 			}
 
@@ -1051,6 +1222,30 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 		}
 	}
 
+	// Handle limits. Allow rules match the traffic within the limits, drop and reject rules the excess.
+	var limitArgs []string
+	if rule.Action != "allow" {
+		limitArgs = append(limitArgs, "over")
+	}
+
+	if rule.ConnectionRate != "" {
+		args = append(args, "ct", "state", "new", "limit", "rate")
+		args = append(args, limitArgs...)
+		args = append(args, rule.ConnectionRate)
+	}
+
+	if rule.RateLimit != "" {
+		args = append(args, "limit", "rate")
+		args = append(args, limitArgs...)
+		args = append(args, nftablesLimitRate(rule.RateLimit))
+	}
+
+	if rule.ConnectionLimit != "" {
+		args = append(args, "ct", "count")
+		args = append(args, limitArgs...)
+		args = append(args, rule.ConnectionLimit)
+	}
+
 	// Handle logging.
 	if rule.Log {
 		args = append(args, "log")
@@ -1079,8 +1274,22 @@ func (d Nftables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, sub
 
 	partial := false
 
+	ipFamily := "ip"
+	if ipVersion == 6 {
+		ipFamily = "ip6"
+	}
+
+	// Address sets are matched through their per-family named sets, the other family always needs its own rule.
+	if len(subjectCriteria) == 1 && d.aclRuleSubjectIsAddressSet(subjectCriteria[0]) {
+		return []string{ipFamily, direction, fmt.Sprintf("@%s_ip%d", strings.TrimPrefix(subjectCriteria[0], "$"), ipVersion)}, true, nil
+	}
+
 	// For each criterion check if value looks like IP CIDR.
 	for _, subjectCriterion := range subjectCriteria {
+		if d.aclRuleSubjectIsAddressSet(subjectCriterion) {
+			return nil, false, fmt.Errorf("Address set %q cannot be combined with other subjects", subjectCriterion)
+		}
+
 		if validate.IsNetworkRange(subjectCriterion) == nil {
 			criterionParts := strings.SplitN(subjectCriterion, "-", 2)
 			if len(criterionParts) > 1 {
@@ -1126,17 +1335,17 @@ func (d Nftables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, sub
 	}
 
 	if len(fieldParts) > 0 {
-		ipFamily := "ip"
-		if ipVersion == 6 {
-			ipFamily = "ip6"
-		}
-
 		return []string{ipFamily, direction, fmt.Sprintf("{%s}", strings.Join(fieldParts, ","))}, partial, nil
 	}
 
 	return nil, partial, nil // No subjects suitable for ipVersion.
 }
 
+// aclRuleSubjectIsAddressSet returns whether the subject criteria is an address set reference.
+func (d Nftables) aclRuleSubjectIsAddressSet(subjectCriteria string) bool {
+	return strings.HasPrefix(subjectCriteria, "$")
+}
+
 // aclRulePortToACLMatch converts protocol (tcp/udp), direction (sports/dports) and port criteria list into
 // xtables args.
 func (d Nftables) aclRulePortToACLMatch(direction string, portCriteria ...string) []string {
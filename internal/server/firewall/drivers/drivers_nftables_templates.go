@@ -165,7 +165,10 @@ flush chain {{.family}} {{.namespace}} acl{{.chainSeparator}}{{.networkName}}
 
 table {{.family}} {{.namespace}} {
 	chain acl{{.chainSeparator}}{{.networkName}} {
-                ct state established,related accept
+		{{- range .rateRules}}
+		{{.}}
+		{{- end}}
+		ct state established,related accept
 
 		{{- range .rules}}
 		{{.}}
@@ -174,6 +177,29 @@ table {{.family}} {{.namespace}} {
 }
 `))
 
+// nftablesAddressSets defines the named sets used by ACL rules referencing address sets.
+// The sets are created in both the inet and bridge tables so they can be used by network and bridge NIC rules.
+// Dynamic sets are only created, their content being managed outside of the ruleset. Their addresses can expire
+// after a timeout, in which case the set holds single addresses as timeouts can't be combined with auto-merge.
+var nftablesAddressSets = template.Must(template.New("nftablesAddressSets").Parse(`
+{{- range $family := .families}}
+add table {{$family}} {{$.namespace}}
+{{- range $.sets}}
+{{- if .timeout}}
+add set {{$family}} {{$.namespace}} {{.name}} {type {{.type}}; flags timeout; timeout {{.timeout}}s;}
+{{- else}}
+add set {{$family}} {{$.namespace}} {{.name}} {type {{.type}}; flags interval; auto-merge;}
+{{- end}}
+{{- if not .dynamic}}
+flush set {{$family}} {{$.namespace}} {{.name}}
+{{- if .addresses}}
+add element {{$family}} {{$.namespace}} {{.name}} { {{.addresses}} }
+{{- end}}
+{{- end}}
+{{- end}}
+{{- end}}
+`))
+
 // nftablesInstanceBridgeFilter defines the rules needed for MAC, IPv4 and IPv6 bridge security filtering.
 // To prevent instances from using IPs that are different from their assigned IPs we use ARP and NDP filtering
 // to prevent neighbour advertisements that are not allowed. However in order for DHCPv4 & DHCPv6 to work back to
@@ -209,6 +235,9 @@ chain in{{.chainSeparator}}{{.deviceLabel}} {
 	{{if .ipv6FilterAll -}}
 	iifname "{{.hostName}}" ether type ip6 drop
 	{{- end}}
+	{{- range .aclInRateRules}}
+	{{.}}
+	{{- end}}
 	{{- if or .aclInDropRules .aclInRejectRules .aclInAcceptRules .aclOutDropRules .aclOutAcceptRules .aclInDefaultRule -}}
 	ct state established,related accept
 	{{- end}}
@@ -254,6 +283,12 @@ chain fwd{{.chainSeparator}}{{.deviceLabel}} {
 	{{if .ipv6FilterAll -}}
 	iifname "{{.hostName}}" ether type ip6 drop
 	{{- end}}
+	{{- range .aclInRateRules}}
+	{{.}}
+	{{- end}}
+	{{- range .aclOutRateRules}}
+	{{.}}
+	{{- end}}
 	{{- if or .aclInDropRules .aclInRejectRulesConverted .aclInAcceptRules .aclOutDropRules .aclOutAcceptRules .aclInDefaultRuleConverted .aclOutDefaultRule -}}
 	ct state established,related accept
 	{{- end}}
@@ -288,6 +323,9 @@ chain fwd{{.chainSeparator}}{{.deviceLabel}} {
 {{if or .aclInDropRules .aclInRejectRulesConverted .aclInAcceptRules .aclOutDropRules .aclOutAcceptRules .aclInDefaultRule .aclOutDefaultRule -}}
 chain out{{.chainSeparator}}{{.deviceLabel}} {
 	type filter hook output priority filter; policy accept;
+	{{- range .aclOutRateRules}}
+	{{.}}
+	{{- end}}
 	ct state established,related accept
 	{{- range .aclOutDropRules}}
 	{{.}}
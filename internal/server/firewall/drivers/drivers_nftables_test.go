@@ -0,0 +1,306 @@
+package drivers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_aclRuleSplitRateLimit(t *testing.T) {
+	tests := []struct {
+		name              string
+		rule              ACLRule
+		expectedRate      *ACLRule
+		expectedRemaining *ACLRule
+	}{
+		{
+			name:              "No rate limit",
+			rule:              ACLRule{Direction: "egress", Action: "allow", ConnectionLimit: "10"},
+			expectedRate:      nil,
+			expectedRemaining: &ACLRule{Direction: "egress", Action: "allow", ConnectionLimit: "10"},
+		},
+		{
+			name:              "Allow rule",
+			rule:              ACLRule{Direction: "egress", Action: "allow", Protocol: "tcp", RateLimit: "100/second", ConnectionRate: "10/minute"},
+			expectedRate:      &ACLRule{Direction: "egress", Action: "drop", Protocol: "tcp", RateLimit: "100/second"},
+			expectedRemaining: &ACLRule{Direction: "egress", Action: "allow", Protocol: "tcp", ConnectionRate: "10/minute"},
+		},
+		{
+			name:              "Drop rule",
+			rule:              ACLRule{Direction: "ingress", Action: "drop", RateLimit: "100/second"},
+			expectedRate:      &ACLRule{Direction: "ingress", Action: "drop", RateLimit: "100/second"},
+			expectedRemaining: nil,
+		},
+		{
+			name:              "Reject rule",
+			rule:              ACLRule{Direction: "ingress", Action: "reject", RateLimit: "10Mbit/second"},
+			expectedRate:      &ACLRule{Direction: "ingress", Action: "drop", RateLimit: "10Mbit/second"},
+			expectedRemaining: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rateRule, remainingRule := aclRuleSplitRateLimit(tt.rule)
+			assert.Equal(t, tt.expectedRate, rateRule)
+			assert.Equal(t, tt.expectedRemaining, remainingRule)
+		})
+	}
+}
+
+func Test_nftablesLimitRate(t *testing.T) {
+	tests := []struct {
+		rate     string
+		expected string
+	}{
+		{rate: "100/second", expected: "100/second"},
+		{rate: "5/minute", expected: "5/minute"},
+		{rate: "10Mbit/second", expected: "1250000 bytes/second"},
+		{rate: "8kbit/hour", expected: "1000 bytes/hour"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rate, func(t *testing.T) {
+			assert.Equal(t, tt.expected, nftablesLimitRate(tt.rate))
+		})
+	}
+}
+
+func Test_aclRuleCriteriaToRules_limits(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     ACLRule
+		expected string
+	}{
+		{
+			name:     "Allow within connection rate",
+			rule:     ACLRule{Direction: "egress", Action: "allow", Protocol: "tcp", DestinationPort: "80", ConnectionRate: "10/minute"},
+			expected: "iifname incusbr0 meta l4proto tcp th dport {80} ct state new limit rate 10/minute accept",
+		},
+		{
+			name:     "Drop over connection limit",
+			rule:     ACLRule{Direction: "ingress", Action: "drop", ConnectionLimit: "100"},
+			expected: "oifname incusbr0 ct count over 100 drop",
+		},
+		{
+			name:     "Drop over packet rate",
+			rule:     ACLRule{Direction: "ingress", Action: "drop", RateLimit: "100/second"},
+			expected: "oifname incusbr0 limit rate over 100/second drop",
+		},
+		{
+			name:     "Drop over bandwidth",
+			rule:     ACLRule{Direction: "egress", Action: "drop", RateLimit: "10Mbit/second"},
+			expected: "iifname incusbr0 limit rate over 1250000 bytes/second drop",
+		},
+	}
+
+	d := Nftables{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nftRule, partial, err := d.aclRuleCriteriaToRules("incusbr0", 4, &tt.rule)
+			require.NoError(t, err)
+			assert.False(t, partial)
+			assert.Equal(t, tt.expected, nftRule)
+		})
+	}
+}
+
+func Test_aclRulesToNftRules_rateLimits(t *testing.T) {
+	rules := []ACLRule{
+		{Direction: "egress", Action: "allow", Protocol: "tcp", DestinationPort: "80", RateLimit: "100/second"},
+		{Direction: "ingress", Action: "reject", RateLimit: "10/second"},
+		{Direction: "ingress", Action: "allow", Protocol: "udp", DestinationPort: "53"},
+		{Direction: "egress", Action: "reject"},
+		{Direction: "ingress", Action: "reject"},
+	}
+
+	d := Nftables{}
+	nftRules, err := d.aclRulesToNftRules("tap0", rules)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`iifname "tap0" meta l4proto tcp th dport {80} limit rate over 100/second drop`}, nftRules.inRateRules)
+	assert.Equal(t, []string{`iifname "tap0" meta l4proto tcp th dport {80} accept`}, nftRules.inAcceptRules4)
+	assert.Equal(t, []string{`oifname "tap0" limit rate over 10/second drop`}, nftRules.outRateRules)
+	assert.Empty(t, nftRules.outDropRules)
+	assert.Equal(t, []string{`oifname "tap0" meta l4proto udp th dport {53} accept`}, nftRules.outAcceptRules)
+	assert.Equal(t, `iifname "tap0" reject`, nftRules.defaultInRule)
+	assert.Equal(t, `iifname "tap0" drop`, nftRules.defaultInRuleConverted)
+	assert.Equal(t, `oifname "tap0" drop`, nftRules.defaultOutRule)
+}
+
+func Test_networkACLRulesConfig(t *testing.T) {
+	rules := []ACLRule{
+		{Direction: "egress", Action: "allow", Protocol: "tcp", DestinationPort: "80", RateLimit: "100/second"},
+		{Direction: "ingress", Action: "drop", RateLimit: "1Mbit/second"},
+	}
+
+	d := Nftables{}
+	config, err := d.networkACLRulesConfig("incusbr0", rules)
+	require.NoError(t, err)
+
+	established := strings.Index(config, "ct state established,related accept")
+	egressRate := strings.Index(config, "iifname incusbr0 meta l4proto tcp th dport {80} limit rate over 100/second drop")
+	ingressRate := strings.Index(config, "oifname incusbr0 limit rate over 125000 bytes/second drop")
+	egressAccept := strings.Index(config, "iifname incusbr0 meta l4proto tcp th dport {80} accept")
+
+	require.NotEqual(t, -1, established)
+	require.NotEqual(t, -1, egressRate)
+	require.NotEqual(t, -1, ingressRate)
+	require.NotEqual(t, -1, egressAccept)
+
+	// Rate limits must apply to established connections too.
+	assert.Less(t, egressRate, established)
+	assert.Less(t, ingressRate, established)
+	assert.Less(t, established, egressAccept)
+}
+
+func Test_aclRuleSubjectToACLMatch_addressSets(t *testing.T) {
+	tests := []struct {
+		name            string
+		direction       string
+		ipVersion       uint
+		subjects        []string
+		expected        []string
+		expectedPartial bool
+		expectErr       bool
+	}{
+		{
+			name:            "IPv4 source address set",
+			direction:       "saddr",
+			ipVersion:       4,
+			subjects:        []string{"$web"},
+			expected:        []string{"ip", "saddr", "@web_ip4"},
+			expectedPartial: true,
+		},
+		{
+			name:            "IPv6 destination address set",
+			direction:       "daddr",
+			ipVersion:       6,
+			subjects:        []string{"$web"},
+			expected:        []string{"ip6", "daddr", "@web_ip6"},
+			expectedPartial: true,
+		},
+		{
+			name:      "Address set combined with address",
+			direction: "saddr",
+			ipVersion: 4,
+			subjects:  []string{"$web", "192.0.2.1"},
+			expectErr: true,
+		},
+		{
+			name:      "Address set combined with address set",
+			direction: "saddr",
+			ipVersion: 4,
+			subjects:  []string{"$web", "$db"},
+			expectErr: true,
+		},
+	}
+
+	d := Nftables{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchArgs, partial, err := d.aclRuleSubjectToACLMatch(tt.direction, tt.ipVersion, tt.subjects...)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, matchArgs)
+			assert.Equal(t, tt.expectedPartial, partial)
+		})
+	}
+}
+
+func Test_aclRuleToNftRules_addressSets(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      ACLRule
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name: "Source address set",
+			rule: ACLRule{Direction: "ingress", Action: "allow", Source: "$web", Protocol: "tcp", DestinationPort: "443"},
+			expected: []string{
+				`oifname "tap0" ip saddr @web_ip4 meta l4proto tcp th dport {443} accept`,
+				`oifname "tap0" ip6 saddr @web_ip6 meta l4proto tcp th dport {443} accept`,
+			},
+		},
+		{
+			name: "Address set with IPv4 destination",
+			rule: ACLRule{Direction: "egress", Action: "drop", Source: "$web", Destination: "192.0.2.0/24"},
+			expected: []string{
+				`iifname "tap0" ip saddr @web_ip4 ip daddr {192.0.2.0/24} drop`,
+			},
+		},
+		{
+			name: "Address set with ICMPv6",
+			rule: ACLRule{Direction: "egress", Action: "reject", Destination: "$web", Protocol: "icmp6"},
+			expected: []string{
+				`iifname "tap0" ip6 daddr @web_ip6 ip6 nexthdr icmpv6 reject`,
+			},
+		},
+		{
+			name:      "Address set combined with address",
+			rule:      ACLRule{Direction: "egress", Action: "allow", Destination: "$web,192.0.2.1"},
+			expectErr: true,
+		},
+	}
+
+	d := Nftables{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, nftRules, err := d.aclRuleToNftRules(`"tap0"`, tt.rule)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, nftRules)
+		})
+	}
+}
+
+func Test_addressSetsConfig(t *testing.T) {
+	sets := []AddressSet{
+		{Name: "incus_web", Addresses: []string{"192.0.2.1", "198.51.100.0/24", "2001:db8::/64"}},
+		{Name: "incus_empty"},
+		{Name: "incus_dns", Dynamic: true},
+		{Name: "incus_ttl", Dynamic: true, Timeout: 600},
+	}
+
+	d := Nftables{}
+	config, err := d.addressSetsConfig(sets)
+	require.NoError(t, err)
+
+	for _, family := range []string{"inet", "bridge"} {
+		// Static sets are flushed and filled with the addresses of their family.
+		assert.Contains(t, config, "add set "+family+" incus incus_web_ip4 {type ipv4_addr; flags interval; auto-merge;}\n")
+		assert.Contains(t, config, "add set "+family+" incus incus_web_ip6 {type ipv6_addr; flags interval; auto-merge;}\n")
+		assert.Contains(t, config, "flush set "+family+" incus incus_web_ip4\n")
+		assert.Contains(t, config, "add element "+family+" incus incus_web_ip4 { 192.0.2.1, 198.51.100.0/24 }\n")
+		assert.Contains(t, config, "add element "+family+" incus incus_web_ip6 { 2001:db8::/64 }\n")
+
+		// Empty sets are still flushed.
+		assert.Contains(t, config, "flush set "+family+" incus incus_empty_ip4\n")
+		assert.NotContains(t, config, "add element "+family+" incus incus_empty_ip4")
+
+		// Dynamic sets are created but their content is left untouched.
+		assert.Contains(t, config, "add set "+family+" incus incus_dns_ip4 {type ipv4_addr; flags interval; auto-merge;}\n")
+		assert.NotContains(t, config, "flush set "+family+" incus incus_dns_ip4")
+
+		// Addresses of sets with a timeout expire.
+		assert.Contains(t, config, "add set "+family+" incus incus_ttl_ip6 {type ipv6_addr; flags timeout; timeout 600s;}\n")
+		assert.NotContains(t, config, "flush set "+family+" incus incus_ttl_ip6")
+	}
+
+	_, err = d.addressSetsConfig([]AddressSet{{Name: "incus_bad", Addresses: []string{"foo"}}})
+	assert.Error(t, err)
+}
@@ -512,6 +512,16 @@ func (d Xtables) NetworkSetup(networkName string, opts Opts) error {
 	return nil
 }
 
+// NetworkApplyAddressSets isn't supported by the xtables driver.
+func (d Xtables) NetworkApplyAddressSets(sets []AddressSet) error {
+	return fmt.Errorf("Address sets aren't supported by the xtables firewall driver")
+}
+
+// NetworkDeleteAddressSet is a no-op as the xtables driver doesn't support address sets.
+func (d Xtables) NetworkDeleteAddressSet(name string) error {
+	return nil
+}
+
 // NetworkApplyACLRules applies ACL rules to the existing firewall chains.
 func (d Xtables) NetworkApplyACLRules(networkName string, rules []ACLRule) error {
 	chain := fmt.Sprintf("%s_%s", iptablesChainACLFilterPrefix, networkName)
@@ -586,6 +596,10 @@ func (d Xtables) NetworkApplyACLRules(networkName string, rules []ACLRule) error
 func (d Xtables) aclRuleCriteriaToArgs(networkName string, ipVersion uint, rule *ACLRule) ([]string, []string, error) {
 	var args []string
 
+	if rule.RateLimit != "" || rule.ConnectionRate != "" || rule.ConnectionLimit != "" {
+		return nil, nil, fmt.Errorf("ACL rule limits aren't supported by the xtables firewall driver")
+	}
+
 	if rule.Direction == "ingress" {
 		args = append(args, "-o", networkName) // Coming from host into network's interface.
 	} else {
@@ -1158,6 +1158,34 @@ func (d *common) needsNewInstanceID(changedConfig []string, oldExpandedDevices d
 	return false
 }
 
+// diskLimitsGroupDevices returns a disk device for each limits group affected by the changed config keys.
+// Reloading one disk is enough as the group limits are shared by all of its members.
+func (d *common) diskLimitsGroupDevices(changedConfig []string) []string {
+	groups := []string{}
+	for _, key := range changedConfig {
+		if !strings.HasPrefix(key, "limits.disk.group.") {
+			continue
+		}
+
+		group, _, _ := strings.Cut(strings.TrimPrefix(key, "limits.disk.group."), ".")
+		if !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+
+	devNames := []string{}
+	for _, entry := range d.expandedDevices.Sorted() {
+		if entry.Config["type"] != "disk" || !slices.Contains(groups, entry.Config["limits.group"]) {
+			continue
+		}
+
+		devNames = append(devNames, entry.Name)
+		groups = slices.DeleteFunc(groups, func(group string) bool { return group == entry.Config["limits.group"] })
+	}
+
+	return devNames
+}
+
 // getStoragePool returns the current storage pool handle. To avoid a DB lookup each time this
 // function is called, the handle is cached internally in the struct.
 func (d *common) getStoragePool() (storagePools.Pool, error) {
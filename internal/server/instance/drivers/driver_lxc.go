@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"net"
 	"net/http"
 	"os"
@@ -77,6 +78,7 @@ import (
 	"github.com/lxc/incus/v6/shared/idmap"
 	"github.com/lxc/incus/v6/shared/ioprogress"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/oci"
 	"github.com/lxc/incus/v6/shared/osarch"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/subprocess"
@@ -5036,6 +5038,14 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 				}
 			}
 		}
+
+		// Re-apply the I/O limits of disks whose limits group changed.
+		for _, devName := range d.diskLimitsGroupDevices(changedConfig) {
+			err = d.ReloadDevice(devName)
+			if err != nil {
+				return fmt.Errorf("Failed updating limits of disk device %q: %w", devName, err)
+			}
+		}
 	}
 
 	// Re-generate the instance-id if needed.
@@ -5329,6 +5339,51 @@ func (d *lxc) Export(w io.Writer, properties map[string]string, expiration time.
 		return nil, err
 	}
 
+	// Include the OCI configuration of application containers.
+	fnam = filepath.Join(cDir, "config.json")
+	if util.PathExists(fnam) {
+		fnam, err = d.exportOCIConfig(fnam, tempDir)
+		if err != nil {
+			_ = tarWriter.Close()
+			d.logger.Error("Failed exporting instance", ctxMap)
+			return nil, err
+		}
+
+		fi, err := os.Lstat(fnam)
+		if err != nil {
+			_ = tarWriter.Close()
+			d.logger.Error("Failed exporting instance", ctxMap)
+			return nil, err
+		}
+
+		err = tarWriter.WriteFile(fnam[tmpOffset:], fnam, fi, false)
+		if err != nil {
+			_ = tarWriter.Close()
+			d.logger.Debug("Error writing to tarfile", logger.Ctx{"err": err})
+			d.logger.Error("Failed exporting instance", ctxMap)
+			return nil, err
+		}
+	}
+
+	// Include the source of containers created from OCI images, to re-use its layers when publishing.
+	fnam = filepath.Join(cDir, oci.SourceFile)
+	if util.PathExists(fnam) {
+		fi, err := os.Lstat(fnam)
+		if err != nil {
+			_ = tarWriter.Close()
+			d.logger.Error("Failed exporting instance", ctxMap)
+			return nil, err
+		}
+
+		err = tarWriter.WriteFile(oci.SourceFile, fnam, fi, false)
+		if err != nil {
+			_ = tarWriter.Close()
+			d.logger.Debug("Error writing to tarfile", logger.Ctx{"err": err})
+			d.logger.Error("Failed exporting instance", ctxMap)
+			return nil, err
+		}
+	}
+
 	// Include all the rootfs files.
 	fnam = d.RootfsPath()
 	err = filepath.Walk(fnam, writeToTar)
@@ -5357,6 +5412,64 @@ func (d *lxc) Export(w io.Writer, properties map[string]string, expiration time.
 	return &meta, nil
 }
 
+// exportOCIConfig writes a copy of the OCI configuration of the container into
+// the target directory, with the environment reflecting the instance configuration.
+func (d *lxc) exportOCIConfig(path string, targetDir string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	var config ociSpecs.Spec
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return "", err
+	}
+
+	if config.Process != nil {
+		env := map[string]string{}
+		for k, v := range d.expandedConfig {
+			name, ok := strings.CutPrefix(k, "environment.")
+			if ok {
+				env[name] = v
+			}
+		}
+
+		// Update the existing variables, then add the new ones.
+		processEnv := make([]string, 0, len(config.Process.Env)+len(env))
+		for _, entry := range config.Process.Env {
+			name, _, _ := strings.Cut(entry, "=")
+
+			value, ok := env[name]
+			if ok {
+				entry = name + "=" + value
+				delete(env, name)
+			}
+
+			processEnv = append(processEnv, entry)
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(env)) {
+			processEnv = append(processEnv, name+"="+env[name])
+		}
+
+		config.Process.Env = processEnv
+	}
+
+	data, err = json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	target := filepath.Join(targetDir, "config.json")
+	err = os.WriteFile(target, data, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return target, nil
+}
+
 func collectCRIULogFile(d instance.Instance, imagesDir string, function string, method string) error {
 	t := time.Now().Format(time.RFC3339)
 	newPath := filepath.Join(d.LogPath(), fmt.Sprintf("%s_%s_%s.log", function, method, t))
@@ -26,6 +26,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 	"unsafe"
 
@@ -99,6 +100,9 @@ var incusAgentLoader embed.FS
 // qemuSerialChardevName is used to communicate state with QEMU via QMP.
 const qemuSerialChardevName = "qemu_serial-chardev"
 
+// qemuMemoryHotplugDevName is the name of the virtio-mem device used for memory hotplug.
+const qemuMemoryHotplugDevName = "qemu_mem_hotplug"
+
 // qemuPCIDeviceIDStart is the first PCI slot used for user configurable devices.
 const qemuPCIDeviceIDStart = 4
 
@@ -348,6 +352,9 @@ type qemu struct {
 	// Stateful migration streams.
 	migrationReceiveStateful map[string]io.ReadWriteCloser
 
+	// Live migration features negotiated with the source.
+	migrationReceiveFeatures *migration.QemuFeatures
+
 	// Keep a reference to the console socket when switching backends, so we can properly cleanup when switching back to a ring buffer.
 	consoleSocket     *net.UnixListener
 	consoleSocketFile *os.File
@@ -620,6 +627,7 @@ func (d *qemu) onStop(target string) error {
 	err = d.VolatileSet(map[string]string{
 		"volatile.last_state.power": instance.PowerStateStopped,
 		"volatile.last_state.ready": "false",
+		"volatile.migration.broken": "",
 	})
 	if err != nil {
 		// Don't return an error here as we still want to cleanup the instance even if DB not available.
@@ -901,21 +909,28 @@ func (d *qemu) restoreState(monitor *qmp.Monitor) error {
 
 		// Receive checkpoint from QEMU process on source.
 		d.logger.Debug("Stateful migration checkpoint receive starting")
-		pipeRead, pipeWrite, err := os.Pipe()
-		if err != nil {
-			return err
-		}
+		if d.migrationReceiveFeatures != nil {
+			err := d.migrateReceiveLiveStateMux(monitor, stateConn, d.migrationReceiveFeatures)
+			if err != nil {
+				return fmt.Errorf("Failed restoring checkpoint from source: %w", err)
+			}
+		} else {
+			pipeRead, pipeWrite, err := os.Pipe()
+			if err != nil {
+				return err
+			}
 
-		go func() {
-			_, _ = io.Copy(pipeWrite, stateConn)
+			go func() {
+				_, _ = io.Copy(pipeWrite, stateConn)
 
-			_ = pipeRead.Close()
-			_ = pipeWrite.Close()
-		}()
+				_ = pipeRead.Close()
+				_ = pipeWrite.Close()
+			}()
 
-		err = d.restoreStateHandle(context.Background(), monitor, pipeRead)
-		if err != nil {
-			return fmt.Errorf("Failed restoring checkpoint from source: %w", err)
+			err = d.restoreStateHandle(context.Background(), monitor, pipeRead)
+			if err != nil {
+				return fmt.Errorf("Failed restoring checkpoint from source: %w", err)
+			}
 		}
 
 		d.logger.Debug("Stateful migration checkpoint receive finished")
@@ -2541,21 +2556,37 @@ func (d *qemu) deviceDetachBlockDevice(deviceName string, rawConfig deviceConfig
 		return err
 	}
 
+	// Remove the throttle node (if any) ahead of the block device it sits on.
+	nodeNames := []string{blockDevName}
+	if rawConfig["limits.group"] != "" {
+		nodeNames = []string{d.throttleNodeName(escapedDeviceName), blockDevName}
+	}
+
 	waitDuration := time.Duration(time.Second * time.Duration(10))
-	waitUntil := time.Now().Add(waitDuration)
-	for {
-		err = monitor.RemoveBlockDevice(blockDevName)
-		if err == nil {
-			break
-		}
+	for _, nodeName := range nodeNames {
+		waitUntil := time.Now().Add(waitDuration)
+		for {
+			err = monitor.RemoveBlockDevice(nodeName)
+			if err == nil {
+				break
+			}
 
-		if api.StatusErrorCheck(err, http.StatusLocked) {
-			time.Sleep(time.Second * time.Duration(2))
-			continue
+			if api.StatusErrorCheck(err, http.StatusLocked) {
+				time.Sleep(time.Second * time.Duration(2))
+				continue
+			}
+
+			if time.Now().After(waitUntil) {
+				return fmt.Errorf("Failed to detach block device after %v", waitDuration)
+			}
 		}
+	}
 
-		if time.Now().After(waitUntil) {
-			return fmt.Errorf("Failed to detach block device after %v", waitDuration)
+	// Remove the throttle group if this was its last member.
+	if rawConfig["limits.group"] != "" {
+		err = d.removeUnusedThrottleGroups(monitor, deviceName)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -2898,6 +2929,10 @@ func (d *qemu) monitorPath() string {
 	return filepath.Join(d.RunPath(), "qemu.monitor")
 }
 
+func (d *qemu) guestAgentPath() string {
+	return filepath.Join(d.RunPath(), "qemu.guest_agent")
+}
+
 func (d *qemu) nvramPath() string {
 	return filepath.Join(d.Path(), "qemu.nvram")
 }
@@ -3520,6 +3555,10 @@ func (d *qemu) generateQemuConfig(cpuInfo *cpuTopology, mountInfo *storagePools.
 
 	conf = append(conf, qemuSerial(&serialOpts)...)
 
+	if d.guestAgentEnabled() {
+		conf = append(conf, qemuGuestAgent(&qemuGuestAgentOpts{path: d.guestAgentPath()})...)
+	}
+
 	// s390x doesn't really have USB.
 	if d.architecture != osarch.ARCH_64BIT_S390_BIG_ENDIAN {
 		devBus, devAddr, multi = bus.allocate(busFunctionGroupGeneric)
@@ -3767,6 +3806,12 @@ func (d *qemu) generateQemuConfig(cpuInfo *cpuTopology, mountInfo *storagePools.
 		}
 	}
 
+	// Add the memory hotplug device.
+	err = d.addMemoryHotplugConfig(&conf, bus)
+	if err != nil {
+		return nil, err
+	}
+
 	// Allocate 8 PCI slots for hotplug devices.
 	for i := 0; i < 8; i++ {
 		bus.allocate(busFunctionGroupNone)
@@ -3925,14 +3970,60 @@ func (d *qemu) addCPUMemoryConfig(conf *[]cfg.Section, cpuInfo *cpuTopology) err
 	nodeMemory := int64(memSizeMB / int64(len(hostNodes)))
 	cpuOpts.memory = nodeMemory
 
+	// Reserve room for memory hotplug.
+	hotplugSizeBytes, err := d.memoryHotplugSizeBytes(memSizeBytes)
+	if err != nil {
+		return err
+	}
+
+	maxSizeMB := memSizeMB + hotplugSizeBytes/1024/1024
+
 	if conf != nil {
-		*conf = append(*conf, qemuMemory(&qemuMemoryOpts{memSizeMB})...)
+		*conf = append(*conf, qemuMemory(&qemuMemoryOpts{memSizeMB, maxSizeMB})...)
 		*conf = append(*conf, qemuCPU(&cpuOpts, cpuPinning)...)
 	}
 
 	return nil
 }
 
+// addMemoryHotplugConfig adds the virtio-mem device used to hotplug memory when limits.memory.hotplug is set.
+func (d *qemu) addMemoryHotplugConfig(conf *[]cfg.Section, bus *qemuBus) error {
+	memSize := d.expandedConfig["limits.memory"]
+	if memSize == "" {
+		memSize = qemudefault.MemSize // Default if no memory limit specified.
+	}
+
+	memSizeBytes, err := ParseMemoryStr(memSize)
+	if err != nil {
+		return fmt.Errorf("limits.memory invalid: %w", err)
+	}
+
+	hotplugSizeBytes, err := d.memoryHotplugSizeBytes(memSizeBytes)
+	if err != nil {
+		return err
+	}
+
+	if hotplugSizeBytes == 0 {
+		return nil
+	}
+
+	devBus, devAddr, multi := bus.allocate(busFunctionGroupNone)
+	memoryHotplugOpts := qemuMemoryHotplugOpts{
+		dev: qemuDevOpts{
+			busName:       bus.name,
+			devBus:        devBus,
+			devAddr:       devAddr,
+			multifunction: multi,
+		},
+		sizeMB: hotplugSizeBytes / 1024 / 1024,
+		numa:   d.architecture == osarch.ARCH_64BIT_INTEL_X86,
+	}
+
+	*conf = append(*conf, qemuMemoryHotplug(&memoryHotplugOpts)...)
+
+	return nil
+}
+
 // addFileDescriptor adds a file path to the list of files to open and pass file descriptor to qemu.
 // Returns the file descriptor number that qemu will receive.
 func (d *qemu) addFileDescriptor(fdFiles *[]*os.File, file *os.File) int {
@@ -4292,6 +4383,19 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 
 	qemuDev["id"] = fmt.Sprintf("%s%s", qemuDeviceIDPrefix, escapedDeviceName)
 	qemuDev["drive"] = blockDev["node-name"].(string)
+
+	// Disks sharing a limits group go through a throttle filter node tied to the group.
+	var throttleDev map[string]any
+	if driveConf.LimitsGroup != "" {
+		throttleDev = map[string]any{
+			"driver":         "throttle",
+			"node-name":      d.throttleNodeName(escapedDeviceName),
+			"throttle-group": d.throttleGroupName(driveConf.LimitsGroup),
+			"file":           blockDev["node-name"].(string),
+		}
+
+		qemuDev["drive"] = throttleDev["node-name"].(string)
+	}
 	qemuDev["serial"] = fmt.Sprintf("%s%s", qemuBlockDevIDPrefix, escapedDeviceName)
 
 	if bus == "virtio-scsi" {
@@ -4370,12 +4474,39 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 			blockDev["filename"] = fmt.Sprintf("/dev/fdset/%d", info.ID)
 		}
 
-		err := m.AddBlockDevice(blockDev, qemuDev)
-		if err != nil {
-			return fmt.Errorf("Failed adding block device for disk device %q: %w", driveConf.DevName, err)
+		var err error
+		if throttleDev != nil {
+			var limits deviceConfig.DiskLimits
+			if driveConf.Limits != nil {
+				limits = *driveConf.Limits
+			}
+
+			err = m.SetThrottleGroup(throttleDev["throttle-group"].(string), int(limits.ReadBytes), int(limits.WriteBytes), int(limits.ReadIOps), int(limits.WriteIOps))
+			if err != nil {
+				return fmt.Errorf("Failed applying limits group for disk device %q: %w", driveConf.DevName, err)
+			}
+
+			err = m.AddBlockDevice(blockDev, nil)
+			if err != nil {
+				return fmt.Errorf("Failed adding block device for disk device %q: %w", driveConf.DevName, err)
+			}
+
+			revert.Add(func() {
+				_ = m.RemoveBlockDevice(nodeName)
+			})
+
+			err = m.AddBlockDevice(throttleDev, qemuDev)
+			if err != nil {
+				return fmt.Errorf("Failed adding throttle node for disk device %q: %w", driveConf.DevName, err)
+			}
+		} else {
+			err = m.AddBlockDevice(blockDev, qemuDev)
+			if err != nil {
+				return fmt.Errorf("Failed adding block device for disk device %q: %w", driveConf.DevName, err)
+			}
 		}
 
-		if driveConf.Limits != nil {
+		if driveConf.Limits != nil && throttleDev == nil {
 			err = m.SetBlockThrottle(qemuDev["id"].(string), int(driveConf.Limits.ReadBytes), int(driveConf.Limits.WriteBytes), int(driveConf.Limits.ReadIOps), int(driveConf.Limits.WriteIOps))
 			if err != nil {
 				return fmt.Errorf("Failed applying limits for disk device %q: %w", driveConf.DevName, err)
@@ -5222,6 +5353,101 @@ func (d *qemu) Snapshot(name string, expiry time.Time, stateful bool) error {
 	return d.snapshot(name, expiry, stateful)
 }
 
+// FreezeFilesystems freezes the guest file systems ahead of a snapshot or backup and returns a function to thaw them.
+// Nothing is frozen unless agent.fsfreeze is enabled.
+func (d *qemu) FreezeFilesystems() (func() error, error) {
+	if util.IsFalseOrEmpty(d.expandedConfig["agent.fsfreeze"]) || !d.IsRunning() {
+		return func() error { return nil }, nil
+	}
+
+	thaw, err := d.agentFreezeFilesystems()
+	if errors.Is(err, errQemuAgentOffline) && d.guestAgentEnabled() {
+		thaw, err = d.guestAgentFreezeFilesystems()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed freezing guest file systems: %w", err)
+	}
+
+	d.logger.Debug("Froze guest file systems")
+
+	return func() error {
+		err := thaw()
+		if err != nil {
+			return fmt.Errorf("Failed thawing guest file systems: %w", err)
+		}
+
+		d.logger.Debug("Thawed guest file systems")
+
+		return nil
+	}, nil
+}
+
+// agentFreezeFilesystems freezes the guest file systems through the incus-agent.
+func (d *qemu) agentFreezeFilesystems() (func() error, error) {
+	agentQuery := func(method string) error {
+		client, err := d.getAgentClient()
+		if err != nil {
+			return err
+		}
+
+		agentArgs := &incus.ConnectionArgs{SkipGetServer: true}
+		agent, err := incus.ConnectIncusHTTP(agentArgs, client)
+		if err != nil {
+			d.logger.Error("Failed to connect to the agent", logger.Ctx{"project": d.Project().Name, "instance": d.Name(), "err": err})
+			return fmt.Errorf("Failed to connect to the agent")
+		}
+
+		defer agent.Disconnect()
+
+		_, _, err = agent.RawQuery(method, "/1.0/fsfreeze", nil, "")
+		if err != nil {
+			// Older agents don't know how to freeze file systems.
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				return errQemuAgentOffline
+			}
+
+			return err
+		}
+
+		return nil
+	}
+
+	err := agentQuery("POST")
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error { return agentQuery("DELETE") }, nil
+}
+
+// guestAgentFreezeFilesystems freezes the guest file systems through the QEMU guest agent.
+func (d *qemu) guestAgentFreezeFilesystems() (func() error, error) {
+	agent, err := d.getGuestAgent()
+	if err != nil {
+		return nil, err
+	}
+
+	defer agent.Disconnect()
+
+	_, err = agent.FSFreeze()
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		agent, err := d.getGuestAgent()
+		if err != nil {
+			return err
+		}
+
+		defer agent.Disconnect()
+
+		_, err = agent.FSThaw()
+		return err
+	}, nil
+}
+
 // Restore restores an instance snapshot.
 func (d *qemu) Restore(source instance.Instance, stateful bool) error {
 	op, err := operationlock.Create(d.Project().Name, d.Name(), d.op, operationlock.ActionRestore, false, false)
@@ -5794,6 +6020,7 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 	if isRunning {
 		// Only certain keys can be changed on a running VM.
 		liveUpdateKeys := []string{
+			"agent.fsfreeze",
 			"cluster.evacuate",
 			"limits.memory",
 			"security.agent.metrics",
@@ -5808,6 +6035,8 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 			"cloud-init.",
 			"environment.",
 			"image.",
+			"limits.disk.group.",
+			"migration.live.",
 			"snapshots.",
 			"user.",
 			"volatile.",
@@ -5891,6 +6120,27 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 				}
 			}
 		}
+
+		// Re-apply the I/O limits of disks whose limits group changed.
+		for _, devName := range d.diskLimitsGroupDevices(changedConfig) {
+			err = d.ReloadDevice(devName)
+			if err != nil {
+				return fmt.Errorf("Failed updating limits of disk device %q: %w", devName, err)
+			}
+		}
+
+		// Remove the throttle groups left without any member disk.
+		if slices.ContainsFunc(changedConfig, func(key string) bool { return strings.HasPrefix(key, "limits.disk.group.") }) {
+			monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
+			if err != nil {
+				return err
+			}
+
+			err = d.removeUnusedThrottleGroups(monitor, "")
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	if d.architectureSupportsUEFI(d.architecture) && (slices.Contains(changedConfig, "security.secureboot") || slices.Contains(changedConfig, "security.csm")) {
@@ -6085,12 +6335,44 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 		return err
 	}
 
+	// Look for the memory hotplug device.
+	var memDevice *qmp.MemoryDevice
+	if d.architectureSupportsMemoryHotplug() {
+		memDevices, err := monitor.QueryVirtioMemDevices()
+		if err != nil {
+			return err
+		}
+
+		for i := range memDevices {
+			if memDevices[i].ID == qemuMemoryHotplugDevName {
+				memDevice = &memDevices[i]
+				break
+			}
+		}
+	}
+
+	if memDevice != nil {
+		curSizeBytes += memDevice.Size
+	}
+
 	curSizeMB := curSizeBytes / 1024 / 1024
 
 	if curSizeMB == newSizeMB {
 		return nil
 	} else if baseSizeMB < newSizeMB {
-		return fmt.Errorf("Cannot increase memory size beyond boot time size when VM is running (Boot time size %dMiB, new size %dMiB)", baseSizeMB, newSizeMB)
+		if memDevice == nil {
+			return fmt.Errorf("Cannot increase memory size beyond boot time size when VM is running unless limits.memory.hotplug is set (Boot time size %dMiB, new size %dMiB)", baseSizeMB, newSizeMB)
+		}
+
+		return d.updateMemoryHotplug(monitor, memDevice, baseSizeBytes, newSizeBytes)
+	}
+
+	// Unplug any hotplugged memory, the balloon takes over from there.
+	if memDevice != nil && memDevice.RequestedSize > 0 {
+		err = monitor.SetVirtioMemRequestedSize(memDevice.ID, 0)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Set effective memory size.
@@ -6126,6 +6408,54 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 	return fmt.Errorf("Failed setting memory to %dMiB (currently %dMiB) as it was taking too long", newSizeMB, curSizeMB)
 }
 
+// updateMemoryHotplug grows the memory of the VM beyond its boot time size through the virtio-mem device.
+func (d *qemu) updateMemoryHotplug(monitor *qmp.Monitor, memDevice *qmp.MemoryDevice, baseSizeBytes int64, newSizeBytes int64) error {
+	// Round up the hotplugged memory to the block size of the device.
+	requestedSizeBytes := newSizeBytes - baseSizeBytes
+	if memDevice.BlockSize > 0 && requestedSizeBytes%memDevice.BlockSize != 0 {
+		requestedSizeBytes += memDevice.BlockSize - requestedSizeBytes%memDevice.BlockSize
+	}
+
+	if requestedSizeBytes > memDevice.MaxSize {
+		return fmt.Errorf("Cannot increase memory size beyond limits.memory.hotplug when VM is running (Maximum size %dMiB, new size %dMiB)", (baseSizeBytes+memDevice.MaxSize)/1024/1024, newSizeBytes/1024/1024)
+	}
+
+	// Deflate the balloon so the boot time memory is fully available.
+	err := monitor.SetMemoryBalloonSizeBytes(baseSizeBytes)
+	if err != nil {
+		return err
+	}
+
+	err = monitor.SetVirtioMemRequestedSize(memDevice.ID, requestedSizeBytes)
+	if err != nil {
+		return err
+	}
+
+	// The guest plugs the memory asynchronously, so poll the size of the device until it reaches the requested size.
+	var pluggedSizeBytes int64
+	for i := 0; i < 10; i++ {
+		memDevices, err := monitor.QueryVirtioMemDevices()
+		if err != nil {
+			return err
+		}
+
+		for _, device := range memDevices {
+			if device.ID == memDevice.ID {
+				pluggedSizeBytes = device.Size
+				break
+			}
+		}
+
+		if pluggedSizeBytes >= requestedSizeBytes {
+			return nil
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return fmt.Errorf("Failed setting memory to %dMiB (currently %dMiB) as it was taking too long", newSizeBytes/1024/1024, (baseSizeBytes+pluggedSizeBytes)/1024/1024)
+}
+
 func (d *qemu) removeUnixDevices() error {
 	// Check that we indeed have devices to remove.
 	if !util.PathExists(d.DevicesPath()) {
@@ -6705,6 +7035,7 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 	// fulfil the "live" part of the request, albeit with longer pause of the instance during the process.
 	if args.Live {
 		offerHeader.Criu = migration.CRIUType_VM_QEMU.Enum()
+		offerHeader.QemuFeatures = d.migrationLiveFeatures()
 	}
 
 	// Send offer to target.
@@ -6839,7 +7170,7 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 				defer instanceRefClear(d)
 			}
 
-			err = d.migrateSendLive(pool, args.ClusterMoveSourceName, args.StoragePool, blockSize, filesystemConn, stateConn, volSourceArgs)
+			err = d.migrateSendLive(pool, args.ClusterMoveSourceName, args.StoragePool, blockSize, filesystemConn, stateConn, respHeader.GetQemuFeatures(), volSourceArgs)
 			if err != nil {
 				return err
 			}
@@ -6878,7 +7209,7 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 }
 
 // migrateSendLive performs live migration send process.
-func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName string, storagePool string, rootDiskSize int64, filesystemConn io.ReadWriteCloser, stateConn io.ReadWriteCloser, volSourceArgs *localMigration.VolumeSourceArgs) error {
+func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName string, storagePool string, rootDiskSize int64, filesystemConn io.ReadWriteCloser, stateConn io.ReadWriteCloser, features *migration.QemuFeatures, volSourceArgs *localMigration.VolumeSourceArgs) error {
 	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
 	if err != nil {
 		return err
@@ -6892,6 +7223,9 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 	// then we can treat this as shared storage and avoid needing to sync the root disk.
 	sameSharedStorage := clusterMoveSourceName != "" && pool.Driver().Info().Remote && storagePool == ""
 
+	// Whether the migration has switched to post-copy mode, after which the source can't be resumed.
+	var postcopyStarted atomic.Bool
+
 	revert := revert.New()
 
 	// Non-shared storage snapshot setup.
@@ -6985,6 +7319,12 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 		}
 
 		revert.Add(func() {
+			// Once in post-copy mode, the guest is running on the target and must not be resumed here.
+			if postcopyStarted.Load() {
+				d.logger.Warn("Not resuming instance after failed post-copy migration")
+				return
+			}
+
 			// Resume guest (this is needed as it will prevent merging the snapshot if paused).
 			err = monitor.Start()
 			if err != nil {
@@ -7014,6 +7354,14 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 		}
 	}
 
+	// Enable the live migration features negotiated with the target.
+	if features != nil {
+		err = d.migrationSetup(monitor, features)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Perform storage transfer while instance is still running.
 	// For shared storage the storage driver will likely not do much here, but we still call it anyway for the
 	// sense checks it performs.
@@ -7139,23 +7487,39 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 	d.logger.Debug("Stateful migration checkpoint send starting")
 
 	// Send checkpoint to QEMU process on target. This will pause the guest OS (if not already paused).
-	pipeRead, pipeWrite, err := os.Pipe()
-	if err != nil {
-		return err
-	}
+	if features != nil {
+		// Multifd channels and post-copy need a socket rather than a pipe.
+		cleanup, err := d.migrateSendLiveStateMux(monitor, stateConn)
+		if err != nil {
+			return fmt.Errorf("Failed starting state transfer to target: %w", err)
+		}
 
-	defer func() {
-		_ = pipeRead.Close()
-		_ = pipeWrite.Close()
-	}()
+		defer cleanup()
+	} else {
+		pipeRead, pipeWrite, err := os.Pipe()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = pipeRead.Close()
+			_ = pipeWrite.Close()
+		}()
 
-	go func() { _, _ = io.Copy(stateConn, pipeRead) }()
+		go func() { _, _ = io.Copy(stateConn, pipeRead) }()
 
-	err = d.saveStateHandle(monitor, pipeWrite)
-	if err != nil {
-		return fmt.Errorf("Failed starting state transfer to target: %w", err)
+		err = d.saveStateHandle(monitor, pipeWrite)
+		if err != nil {
+			return fmt.Errorf("Failed starting state transfer to target: %w", err)
+		}
 	}
 
+	// Report the progress of the memory transfer.
+	progressCtx, cancelProgress := context.WithCancel(context.Background())
+	defer cancelProgress()
+
+	go d.migrationTrackProgress(progressCtx, monitor, features.GetPostcopy(), &postcopyStarted)
+
 	// Non-shared storage snapshot transfer finalization.
 	if !sameSharedStorage {
 		// Wait until state transfer has reached pre-switchover state (the guest OS will remain paused).
@@ -7185,9 +7549,19 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 	}
 
 	// Wait until the migration state transfer has completed (the guest OS will remain paused).
-	err = monitor.MigrateWait("completed")
+	var recoverMigration func() error
+	if features.GetPostcopy() {
+		recoverMigration = func() error { return monitor.MigrateResumeUnix(d.migrationSocketPath()) }
+	}
+
+	err = d.migrationWait(monitor, recoverMigration)
 	if err != nil {
-		return fmt.Errorf("Failed waiting for state transfer to reach completed stage: %w", err)
+		err = fmt.Errorf("Failed waiting for state transfer to reach completed stage: %w", err)
+		if postcopyStarted.Load() {
+			d.migrationMarkBroken(err)
+		}
+
+		return err
 	}
 
 	d.logger.Debug("Stateful migration checkpoint send finished")
@@ -7371,6 +7745,7 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 	var useStateConn bool
 	if args.Live && offerHeader.Criu != nil && *offerHeader.Criu == migration.CRIUType_VM_QEMU {
 		respHeader.Criu = migration.CRIUType_VM_QEMU.Enum()
+		respHeader.QemuFeatures = qemuMigrationFeaturesSupported(offerHeader.GetQemuFeatures())
 		useStateConn = true
 	}
 
@@ -7628,6 +8003,8 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 					api.SecretNameState: stateConn,
 				}
 
+				d.migrationReceiveFeatures = respHeader.GetQemuFeatures()
+
 				// Populate the filesystem connection handle if doing non-shared storage migration.
 				sameSharedStorage := args.ClusterMoveSourceName != "" && poolInfo.Remote && args.StoragePool == ""
 				if !sameSharedStorage {
@@ -7711,6 +8088,10 @@ func (d *qemu) FileSFTPConn() (net.Conn, error) {
 	// Connect to the agent.
 	client, err := d.getAgentClient()
 	if err != nil {
+		if errors.Is(err, errQemuAgentOffline) && d.guestAgentEnabled() {
+			return d.guestAgentSFTPConn()
+		}
+
 		return nil, err
 	}
 
@@ -7865,6 +8246,10 @@ func (d *qemu) Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, s
 
 	client, err := d.getAgentClient()
 	if err != nil {
+		if errors.Is(err, errQemuAgentOffline) && d.guestAgentEnabled() {
+			return d.guestAgentExec(req, stdout, stderr)
+		}
+
 		return nil, err
 	}
 
@@ -8078,7 +8463,7 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 				status = &api.InstanceState{}
 				status.Processes = -1
 
-				status.Network, err = d.getNetworkState()
+				status.Network, err = d.getFallbackNetworkState()
 				if err != nil {
 					return nil, err
 				}
@@ -8086,7 +8471,7 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 		} else {
 			status.Processes = -1
 
-			status.Network, err = d.getNetworkState()
+			status.Network, err = d.getFallbackNetworkState()
 			if err != nil {
 				return nil, err
 			}
@@ -8278,7 +8663,13 @@ func (d *qemu) DeviceEventHandler(runConf *deviceConfig.RunConfig) error {
 		// Figure out the QEMU device ID.
 		devID := fmt.Sprintf("%s%s", qemuDeviceIDPrefix, linux.PathNameEncode(mount.DevName))
 
-		if mount.Limits != nil {
+		if mount.Limits != nil && mount.LimitsGroup != "" {
+			// Apply the limits to the whole group.
+			err = m.SetThrottleGroup(d.throttleGroupName(mount.LimitsGroup), int(mount.Limits.ReadBytes), int(mount.Limits.WriteBytes), int(mount.Limits.ReadIOps), int(mount.Limits.WriteIOps))
+			if err != nil {
+				return fmt.Errorf("Failed applying limits group for disk device %q: %w", mount.DevName, err)
+			}
+		} else if mount.Limits != nil {
 			// Apply the limits.
 			err = m.SetBlockThrottle(devID, int(mount.Limits.ReadBytes), int(mount.Limits.WriteBytes), int(mount.Limits.ReadIOps), int(mount.Limits.WriteIOps))
 			if err != nil {
@@ -8456,6 +8847,11 @@ func (d *qemu) statusCode() api.StatusCode {
 		return api.Stopped
 	}
 
+	// The guest state was lost during a failed post-copy migration.
+	if util.IsTrue(d.LocalConfig()["volatile.migration.broken"]) {
+		return api.Error
+	}
+
 	status, err := monitor.Status()
 	if err != nil {
 		if err == qmp.ErrMonitorDisconnect {
@@ -8965,6 +9361,35 @@ func (d *qemu) checkFeatures(hostArch int, qemuPath string) (map[string]any, err
 		features["cpu_hotplug"] = struct{}{}
 	}
 
+	// Check memory hotplug feature.
+	_, err = monitor.DeviceListProperties("virtio-mem-pci")
+	if err != nil {
+		logger.Debug("Failed querying virtio-mem device during VM feature check", logger.Ctx{"err": err})
+	} else {
+		features["virtio_mem"] = struct{}{}
+	}
+
+	// Check live migration features.
+	migrationCapabilities, err := monitor.QueryMigrateCapabilities()
+	if err != nil {
+		logger.Debug("Failed querying migration capabilities during VM feature check", logger.Ctx{"err": err})
+	} else {
+		if slices.Contains(migrationCapabilities, "multifd") {
+			features["migration_multifd"] = struct{}{}
+
+			err = monitor.MigrateSetParameters(map[string]any{"multifd-compression": "zstd"})
+			if err != nil {
+				logger.Debug("Failed enabling zstd migration compression during VM feature check", logger.Ctx{"err": err})
+			} else {
+				features["migration_zstd"] = struct{}{}
+			}
+		}
+
+		if slices.Contains(migrationCapabilities, "postcopy-ram") {
+			features["migration_postcopy"] = struct{}{}
+		}
+	}
+
 	// Check AMD SEV features (only for x86 architecture)
 	if hostArch == osarch.ARCH_64BIT_INTEL_X86 {
 		cmdline, err := os.ReadFile("/proc/cmdline")
@@ -9135,6 +9560,23 @@ func (d *qemu) getNetworkState() (map[string]api.InstanceStateNetwork, error) {
 	return networks, nil
 }
 
+// getFallbackNetworkState returns the network state when the agent isn't available, using the QEMU guest
+// agent if enabled and the host side of the NICs otherwise.
+func (d *qemu) getFallbackNetworkState() (map[string]api.InstanceStateNetwork, error) {
+	if d.guestAgentEnabled() {
+		networks, err := d.guestAgentNetworkState()
+		if err == nil {
+			return networks, nil
+		}
+
+		if !errors.Is(err, errQemuAgentOffline) {
+			d.logger.Warn("Could not get VM network state from QEMU guest agent", logger.Ctx{"err": err})
+		}
+	}
+
+	return d.getNetworkState()
+}
+
 func (d *qemu) agentMetricsEnabled() bool {
 	return util.IsTrueOrEmpty(d.expandedConfig["security.agent.metrics"])
 }
@@ -9181,6 +9623,46 @@ func (d *qemu) deviceDetachUSB(usbDev deviceConfig.USBDeviceItem) error {
 	return nil
 }
 
+// throttleNodeName returns the name of the throttle filter node placed on top of a disk's block node.
+func (d *qemu) throttleNodeName(name string) string {
+	return d.blockNodeName(name + "_throttle")
+}
+
+// throttleGroupName returns the object ID of the throttle group backing a disk limits group.
+func (d *qemu) throttleGroupName(group string) string {
+	return fmt.Sprintf("%sthrottle_%s", qemuBlockDevIDPrefix, group)
+}
+
+// removeUnusedThrottleGroups removes the throttle groups which no disk device other than skipDevName belongs to.
+func (d *qemu) removeUnusedThrottleGroups(monitor *qmp.Monitor, skipDevName string) error {
+	groups, err := monitor.GetThrottleGroups()
+	if err != nil {
+		return err
+	}
+
+	used := []string{}
+	for _, entry := range d.expandedDevices.Sorted() {
+		if entry.Name == skipDevName || entry.Config["type"] != "disk" || entry.Config["limits.group"] == "" {
+			continue
+		}
+
+		used = append(used, d.throttleGroupName(entry.Config["limits.group"]))
+	}
+
+	for _, group := range groups {
+		if !strings.HasPrefix(group, d.throttleGroupName("")) || slices.Contains(used, group) {
+			continue
+		}
+
+		err = monitor.RemoveThrottleGroup(group)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Block node names may only be up to 31 characters long, so use a hash if longer.
 func (d *qemu) blockNodeName(name string) string {
 	if len(name) > 25 {
@@ -9335,6 +9817,62 @@ func (d *qemu) architectureSupportsCPUHotplug() bool {
 	return found
 }
 
+func (d *qemu) architectureSupportsMemoryHotplug() bool {
+	if !slices.Contains([]int{osarch.ARCH_64BIT_INTEL_X86, osarch.ARCH_64BIT_ARMV8_LITTLE_ENDIAN}, d.architecture) {
+		return false
+	}
+
+	// Check supported features.
+	info := DriverStatuses()[instancetype.VM].Info
+	_, found := info.Features["virtio_mem"]
+	return found
+}
+
+// memoryHotplugSizeBytes returns the amount of memory which can be hotplugged on top of the boot memory.
+// This is zero unless limits.memory.hotplug is set.
+func (d *qemu) memoryHotplugSizeBytes(memSizeBytes int64) (int64, error) {
+	maxSize := d.expandedConfig["limits.memory.hotplug"]
+	if maxSize == "" {
+		return 0, nil
+	}
+
+	maxSizeBytes, err := ParseMemoryStr(maxSize)
+	if err != nil {
+		return 0, fmt.Errorf("limits.memory.hotplug invalid: %w", err)
+	}
+
+	if maxSizeBytes < memSizeBytes {
+		return 0, fmt.Errorf("limits.memory.hotplug (%s) must be larger than limits.memory", maxSize)
+	}
+
+	if util.IsTrue(d.expandedConfig["limits.memory.hugepages"]) {
+		return 0, fmt.Errorf("limits.memory.hotplug can't be used with huge pages")
+	}
+
+	if !d.architectureSupportsMemoryHotplug() {
+		return 0, fmt.Errorf("Memory hotplug isn't supported on this system")
+	}
+
+	// The hotplugged memory must be a multiple of the virtio-mem block size.
+	blockSize := qemuMemoryHotplugBlockSize()
+
+	return (maxSizeBytes - memSizeBytes) / blockSize * blockSize, nil
+}
+
+// qemuMemoryHotplugBlockSize returns the default block size of virtio-mem devices, which is the size of
+// transparent huge pages on the host.
+func qemuMemoryHotplugBlockSize() int64 {
+	content, err := os.ReadFile("/sys/kernel/mm/transparent_hugepage/hpage_pmd_size")
+	if err == nil {
+		size, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+		if err == nil && size > 0 {
+			return size
+		}
+	}
+
+	return 2 * 1024 * 1024
+}
+
 func (d *qemu) postCPUHotplug(monitor *qmp.Monitor) error {
 	// Get the vCPU PID list.
 	pids, err := monitor.GetCPUs()
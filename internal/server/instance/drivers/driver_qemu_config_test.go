@@ -99,21 +99,66 @@ func TestQemuConfigTemplates(t *testing.T) {
 			opts     qemuMemoryOpts
 			expected string
 		}{{
-			qemuMemoryOpts{4096},
+			qemuMemoryOpts{4096, 0},
 			`# Memory
 			[memory]
 			size = "4096M"`,
 		}, {
-			qemuMemoryOpts{8192},
+			qemuMemoryOpts{8192, 0},
 			`# Memory
 			[memory]
 			size = "8192M"`,
+		}, {
+			qemuMemoryOpts{4096, 16384},
+			`# Memory
+			[memory]
+			size = "4096M"
+			maxmem = "16384M"`,
 		}}
 		for _, tc := range testCases {
 			runTest(tc.expected, qemuMemory(&tc.opts))
 		}
 	})
 
+	t.Run("qemu_memory_hotplug", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuMemoryHotplugOpts
+			expected string
+		}{{
+			qemuMemoryHotplugOpts{qemuDevOpts{"pcie", "qemu_pcie5", "00.0", false}, 12288, true},
+			`# Memory hotplug
+			[object "mem_hotplug"]
+			qom-type = "memory-backend-memfd"
+			size = "12288M"
+			share = "on"
+
+			[device "qemu_mem_hotplug"]
+			driver = "virtio-mem-pci"
+			bus = "qemu_pcie5"
+			addr = "00.0"
+			memdev = "mem_hotplug"
+			requested-size = "0"
+			node = "0"`,
+		}, {
+			qemuMemoryHotplugOpts{qemuDevOpts{"pci", "qemu_pci5", "00.0", false}, 1024, false},
+			`# Memory hotplug
+			[object "mem_hotplug"]
+			qom-type = "memory-backend-memfd"
+			size = "1024M"
+			share = "on"
+
+			[device "qemu_mem_hotplug"]
+			driver = "virtio-mem-pci"
+			bus = "qemu_pci5"
+			addr = "00.0"
+			memdev = "mem_hotplug"
+			requested-size = "0"`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuMemoryHotplug(&tc.opts))
+		}
+	})
+
 	t.Run("qemu_serial", func(t *testing.T) {
 		testCases := []struct {
 			opts     qemuSerialOpts
@@ -170,6 +215,30 @@ func TestQemuConfigTemplates(t *testing.T) {
 		}
 	})
 
+	t.Run("qemu_guest_agent", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuGuestAgentOpts
+			expected string
+		}{{
+			qemuGuestAgentOpts{"/var/run/incus/vm1/qemu.guest_agent"},
+			`# QEMU guest agent
+			[chardev "qemu_guest_agent-chardev"]
+			backend = "socket"
+			path = "/var/run/incus/vm1/qemu.guest_agent"
+			server = "on"
+			wait = "off"
+
+			[device "qemu_guest_agent"]
+			driver = "virtserialport"
+			name = "org.qemu.guest_agent.0"
+			chardev = "qemu_guest_agent-chardev"
+			bus = "dev-qemu_serial.0"`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuGuestAgent(&tc.opts))
+		}
+	})
+
 	t.Run("qemu_pcie", func(t *testing.T) {
 		testCases := []struct {
 			opts     qemuPCIeOpts
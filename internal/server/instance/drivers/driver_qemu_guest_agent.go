@@ -0,0 +1,691 @@
+package drivers
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/drivers/qmp"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// qemuGuestAgentPollInterval is how often the status of a command started through the QEMU guest agent is checked.
+var qemuGuestAgentPollInterval = 200 * time.Millisecond
+
+// qemuGuestAgentChunkSize is the maximum amount of data transferred per guest agent file request.
+const qemuGuestAgentChunkSize = 48 * 1024
+
+// guestAgentEnabled returns whether the QEMU guest agent channel is configured.
+func (d *qemu) guestAgentEnabled() bool {
+	return util.IsTrue(d.expandedConfig["agent.qemu_guest_agent"])
+}
+
+// getGuestAgent returns a connection to the QEMU guest agent.
+func (d *qemu) getGuestAgent() (*qmp.GuestAgent, error) {
+	if !d.guestAgentEnabled() || !d.IsRunning() {
+		return nil, errQemuAgentOffline
+	}
+
+	agent, err := qmp.ConnectGuestAgent(d.guestAgentPath())
+	if err != nil {
+		if errors.Is(err, qmp.ErrGuestAgentNotRunning) {
+			return nil, errQemuAgentOffline
+		}
+
+		return nil, err
+	}
+
+	return agent, nil
+}
+
+// guestAgentStatus returns the status of a command started through the QEMU guest agent.
+func (d *qemu) guestAgentStatus(pid int) (*qmp.GuestExecStatus, error) {
+	agent, err := d.getGuestAgent()
+	if err != nil {
+		return nil, err
+	}
+
+	defer agent.Disconnect()
+
+	return agent.ExecStatus(pid)
+}
+
+// guestAgentRun runs a command through the QEMU guest agent, waits for it and returns its output.
+// The command is executed directly, without going through a shell.
+func (d *qemu) guestAgentRun(args ...string) (string, error) {
+	agent, err := d.getGuestAgent()
+	if err != nil {
+		return "", err
+	}
+
+	pid, err := agent.Exec(args[0], args[1:], nil)
+	agent.Disconnect()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		status, err := d.guestAgentStatus(pid)
+		if err != nil {
+			return "", err
+		}
+
+		if !status.Exited {
+			time.Sleep(qemuGuestAgentPollInterval)
+			continue
+		}
+
+		stdout, _ := base64.StdEncoding.DecodeString(status.OutData)
+		stderr, _ := base64.StdEncoding.DecodeString(status.ErrData)
+
+		if status.ExitCode != 0 || status.Signal != 0 {
+			return string(stdout), guestAgentError(fmt.Errorf("Command %q failed: %s", args[0], strings.TrimSpace(string(stderr))))
+		}
+
+		return string(stdout), nil
+	}
+}
+
+// guestAgentError converts errors reported by the guest into their os package equivalent where possible.
+func guestAgentError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "No such file or directory"), strings.Contains(msg, "cannot find the file"), strings.Contains(msg, "cannot find the path"):
+		return os.ErrNotExist
+	case strings.Contains(msg, "Permission denied"), strings.Contains(msg, "Access is denied"):
+		return os.ErrPermission
+	case strings.Contains(msg, "File exists"):
+		return os.ErrExist
+	case strings.Contains(msg, "Is a directory"):
+		return unix.EISDIR
+	}
+
+	return err
+}
+
+// guestAgentUnsupported returns the error used for operations the QEMU guest agent can't perform.
+func guestAgentUnsupported(operation string) error {
+	return fmt.Errorf("%s isn't supported through the QEMU guest agent", operation)
+}
+
+// guestAgentFilePath converts an absolute path as used by the file API into a path for the guest.
+// Windows paths are expected in the "/C:/path" form and converted to "C:\path".
+func guestAgentFilePath(osInfo *qmp.GuestOSInfo, path string) string {
+	if !osInfo.IsWindows() {
+		return path
+	}
+
+	if len(path) >= 3 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+
+	return strings.ReplaceAll(path, "/", `\`)
+}
+
+// guestAgentExec runs a command in the guest through the QEMU guest agent.
+// Only non-interactive commands are supported and their output is only available once they have exited.
+func (d *qemu) guestAgentExec(req api.InstanceExecPost, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
+	if req.Interactive {
+		return nil, guestAgentUnsupported("Running interactive sessions")
+	}
+
+	if req.User != 0 || req.Group != 0 {
+		return nil, guestAgentUnsupported("Running commands as another user")
+	}
+
+	if req.Cwd != "" {
+		return nil, guestAgentUnsupported("Setting the working directory")
+	}
+
+	if len(req.Command) == 0 {
+		return nil, fmt.Errorf("No command specified")
+	}
+
+	agent, err := d.getGuestAgent()
+	if err != nil {
+		return nil, err
+	}
+
+	defer agent.Disconnect()
+
+	osInfo, err := agent.GetOSInfo()
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting guest operating system: %w", err)
+	}
+
+	env := make([]string, 0, len(req.Environment)+1)
+	for k, v := range req.Environment {
+		env = append(env, k+"="+v)
+	}
+
+	// Windows guests resolve commands through their own search path.
+	_, ok := req.Environment["PATH"]
+	if !ok && !osInfo.IsWindows() {
+		env = append(env, "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin")
+	}
+
+	slices.Sort(env)
+
+	pid, err := agent.Exec(req.Command[0], req.Command[1:], env)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := &qemuGuestAgentCmd{
+		d:   d,
+		pid: pid,
+	}
+
+	if stdout != nil {
+		cmd.stdout = stdout
+		cmd.stderr = stdout
+	}
+
+	if stderr != nil {
+		cmd.stderr = stderr
+	}
+
+	d.logger.Debug("Started command through QEMU guest agent", logger.Ctx{"command": req.Command, "pid": pid, "os": osInfo.ID})
+
+	return cmd, nil
+}
+
+// qemuGuestAgentCmd represents a command running through the QEMU guest agent.
+type qemuGuestAgentCmd struct {
+	d      *qemu
+	pid    int
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// PID returns the process ID of the command in the guest.
+func (c *qemuGuestAgentCmd) PID() int {
+	return c.pid
+}
+
+// Signal sends a signal to the command.
+func (c *qemuGuestAgentCmd) Signal(sig unix.Signal) error {
+	return fmt.Errorf("Sending signals isn't supported through the QEMU guest agent")
+}
+
+// Wait for the command to end and returns its exit code and any error.
+func (c *qemuGuestAgentCmd) Wait() (int, error) {
+	for {
+		status, err := c.d.guestAgentStatus(c.pid)
+		if err != nil {
+			if errors.Is(err, errQemuAgentOffline) {
+				return -1, ErrExecDisconnected
+			}
+
+			return -1, err
+		}
+
+		if !status.Exited {
+			time.Sleep(qemuGuestAgentPollInterval)
+			continue
+		}
+
+		for _, output := range []struct {
+			data   string
+			writer io.Writer
+		}{{status.OutData, c.stdout}, {status.ErrData, c.stderr}} {
+			if output.data == "" || output.writer == nil {
+				continue
+			}
+
+			data, err := base64.StdEncoding.DecodeString(output.data)
+			if err != nil {
+				return -1, fmt.Errorf("Failed decoding command output: %w", err)
+			}
+
+			_, err = output.writer.Write(data)
+			if err != nil {
+				return -1, err
+			}
+		}
+
+		if status.Signal != 0 {
+			return 128 + status.Signal, nil
+		}
+
+		switch status.ExitCode {
+		case 127:
+			return status.ExitCode, ErrExecCommandNotFound
+		case 126:
+			return status.ExitCode, ErrExecCommandNotExecutable
+		}
+
+		return status.ExitCode, nil
+	}
+}
+
+// WindowResize resizes the running command's window.
+func (c *qemuGuestAgentCmd) WindowResize(fd, winchWidth, winchHeight int) error {
+	return fmt.Errorf("Window resizing isn't supported through the QEMU guest agent")
+}
+
+// guestAgentNetworkState returns the network state reported by the QEMU guest agent.
+func (d *qemu) guestAgentNetworkState() (map[string]api.InstanceStateNetwork, error) {
+	agent, err := d.getGuestAgent()
+	if err != nil {
+		return nil, err
+	}
+
+	defer agent.Disconnect()
+
+	ifaces, err := agent.NetworkGetInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]api.InstanceStateNetwork{}
+	for _, iface := range ifaces {
+		network := api.InstanceStateNetwork{
+			Addresses: []api.InstanceStateNetworkAddress{},
+			Counters:  api.InstanceStateNetworkCounters{},
+			Hwaddr:    iface.HardwareAddress,
+			State:     "up",
+			Type:      "broadcast",
+		}
+
+		if iface.HardwareAddress == "" || iface.HardwareAddress == "00:00:00:00:00:00" {
+			network.Type = "loopback"
+		}
+
+		if iface.Statistics != nil {
+			network.Counters.BytesReceived = iface.Statistics.RxBytes
+			network.Counters.BytesSent = iface.Statistics.TxBytes
+			network.Counters.PacketsReceived = iface.Statistics.RxPackets
+			network.Counters.PacketsSent = iface.Statistics.TxPackets
+			network.Counters.ErrorsReceived = iface.Statistics.RxErrors
+			network.Counters.ErrorsSent = iface.Statistics.TxErrors
+			network.Counters.PacketsDroppedInbound = iface.Statistics.RxDropped
+			network.Counters.PacketsDroppedOutbound = iface.Statistics.TxDropped
+		}
+
+		for _, addr := range iface.IPAddresses {
+			networkAddress := api.InstanceStateNetworkAddress{
+				Address: addr.Address,
+				Netmask: strconv.Itoa(addr.Prefix),
+				Family:  "inet",
+				Scope:   "global",
+			}
+
+			if addr.Type == "ipv6" {
+				networkAddress.Family = "inet6"
+			}
+
+			ip := net.ParseIP(addr.Address)
+			if ip != nil && ip.IsLoopback() {
+				networkAddress.Scope = "local"
+			} else if ip != nil && (ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()) {
+				networkAddress.Scope = "link"
+			}
+
+			network.Addresses = append(network.Addresses, networkAddress)
+		}
+
+		result[iface.Name] = network
+	}
+
+	return result, nil
+}
+
+// guestAgentSFTPConn returns a connection to an SFTP server backed by the QEMU guest agent.
+func (d *qemu) guestAgentSFTPConn() (net.Conn, error) {
+	// Fail early if the guest agent isn't reachable.
+	agent, err := d.getGuestAgent()
+	if err != nil {
+		return nil, err
+	}
+
+	osInfo, err := agent.GetOSInfo()
+	agent.Disconnect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting guest operating system: %w", err)
+	}
+
+	client, server := net.Pipe()
+
+	handler := &qemuGuestAgentSFTP{d: d, osInfo: osInfo}
+	sftpServer := sftp.NewRequestServer(server, sftp.Handlers{
+		FileGet:  handler,
+		FilePut:  handler,
+		FileCmd:  handler,
+		FileList: handler,
+	})
+
+	go func() {
+		err := sftpServer.Serve()
+		if err != nil && !errors.Is(err, io.EOF) {
+			d.logger.Debug("QEMU guest agent SFTP server stopped", logger.Ctx{"err": err})
+		}
+
+		_ = sftpServer.Close()
+	}()
+
+	return client, nil
+}
+
+// qemuGuestAgentSFTP implements the SFTP request handlers on top of the QEMU guest agent.
+// File content is transferred with the guest-file-* commands. The guest agent can't list directories
+// or manage links, so those operations are reported as unsupported.
+type qemuGuestAgentSFTP struct {
+	d      *qemu
+	osInfo *qmp.GuestOSInfo
+}
+
+// Fileread returns a reader for the requested file.
+func (h *qemuGuestAgentSFTP) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	path := guestAgentFilePath(h.osInfo, r.Filepath)
+
+	err := h.withFile(path, "r", func(agent *qmp.GuestAgent, handle int64) error { return nil })
+	if err != nil {
+		return nil, err
+	}
+
+	return &qemuGuestAgentFile{d: h.d, path: path}, nil
+}
+
+// Filewrite returns a writer for the requested file, creating or truncating it as requested.
+func (h *qemuGuestAgentSFTP) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	path := guestAgentFilePath(h.osInfo, r.Filepath)
+	pflags := r.Pflags()
+
+	mode := "a"
+	if pflags.Trunc {
+		mode = "w"
+	}
+
+	err := h.withFile(path, mode, func(agent *qmp.GuestAgent, handle int64) error { return nil })
+	if err != nil {
+		return nil, err
+	}
+
+	return &qemuGuestAgentFile{d: h.d, path: path}, nil
+}
+
+// Filecmd handles the metadata operations.
+func (h *qemuGuestAgentSFTP) Filecmd(r *sftp.Request) error {
+	switch r.Method {
+	case "Setstat":
+		return h.setstat(r)
+	case "Rename", "PosixRename":
+		return guestAgentUnsupported("Renaming files")
+	case "Remove":
+		return guestAgentUnsupported("Removing files")
+	case "Rmdir":
+		return guestAgentUnsupported("Removing directories")
+	case "Mkdir":
+		return guestAgentUnsupported("Creating directories")
+	case "Symlink", "Link":
+		return guestAgentUnsupported("Creating links")
+	}
+
+	return sftp.ErrSSHFxOpUnsupported
+}
+
+// setstat applies the requested attributes to a file.
+// Files can only be truncated through the guest agent itself. Permissions, ownership and modification
+// time don't apply to Windows guests and are set with the chmod, chown and touch commands otherwise.
+func (h *qemuGuestAgentSFTP) setstat(r *sftp.Request) error {
+	path := guestAgentFilePath(h.osInfo, r.Filepath)
+	flags := r.AttrFlags()
+	attrs := r.Attributes()
+
+	if flags.Size {
+		if attrs.Size != 0 {
+			return guestAgentUnsupported("Resizing files")
+		}
+
+		err := h.withFile(path, "w", func(agent *qmp.GuestAgent, handle int64) error { return nil })
+		if err != nil {
+			return err
+		}
+	}
+
+	if !flags.Permissions && !flags.UidGid && !flags.Acmodtime {
+		return nil
+	}
+
+	if h.osInfo.IsWindows() {
+		h.d.logger.Debug("Ignoring file attributes on Windows guest", logger.Ctx{"path": path})
+		return nil
+	}
+
+	if flags.Permissions {
+		_, err := h.d.guestAgentRun("chmod", fmt.Sprintf("%04o", attrs.Mode&0o7777), "--", path)
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags.UidGid {
+		_, err := h.d.guestAgentRun("chown", fmt.Sprintf("%d:%d", attrs.UID, attrs.GID), "--", path)
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags.Acmodtime {
+		_, err := h.d.guestAgentRun("touch", "-m", "-d", "@"+strconv.FormatUint(uint64(attrs.Mtime), 10), "--", path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Filelist handles the listing and stat operations.
+func (h *qemuGuestAgentSFTP) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	switch r.Method {
+	case "List":
+		return nil, guestAgentUnsupported("Listing directories")
+	case "Stat":
+		fi, err := h.stat(r.Filepath)
+		if err != nil {
+			return nil, err
+		}
+
+		return qemuGuestAgentFileList{fi}, nil
+	case "Readlink":
+		return nil, guestAgentUnsupported("Reading symlinks")
+	}
+
+	return nil, sftp.ErrSSHFxOpUnsupported
+}
+
+// Lstat returns information about a path.
+// The guest agent always follows symlinks so this is the same as Stat.
+func (h *qemuGuestAgentSFTP) Lstat(r *sftp.Request) (sftp.ListerAt, error) {
+	fi, err := h.stat(r.Filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	return qemuGuestAgentFileList{fi}, nil
+}
+
+// stat returns information about a path based on what the guest agent file commands report.
+// Ownership and permissions aren't available, so regular files are reported as 0644 and directories as 0755.
+func (h *qemuGuestAgentSFTP) stat(path string) (os.FileInfo, error) {
+	fi := &qemuGuestAgentFileInfo{name: filepath.Base(path), mode: 0o644}
+
+	err := h.withFile(guestAgentFilePath(h.osInfo, path), "r", func(agent *qmp.GuestAgent, handle int64) error {
+		// Directories can be opened on Linux guests but not read from.
+		_, _, err := agent.FileRead(handle, 1)
+		if errors.Is(guestAgentError(err), unix.EISDIR) {
+			fi.mode = os.ModeDir | 0o755
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		size, err := agent.FileSeek(handle, 0, io.SeekEnd)
+		if err != nil {
+			return err
+		}
+
+		fi.size = size
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fi, nil
+}
+
+// withFile opens a file in the guest and calls the provided function with its handle.
+func (h *qemuGuestAgentSFTP) withFile(path string, mode string, f func(agent *qmp.GuestAgent, handle int64) error) error {
+	agent, err := h.d.getGuestAgent()
+	if err != nil {
+		return err
+	}
+
+	defer agent.Disconnect()
+
+	handle, err := agent.FileOpen(path, mode)
+	if err != nil {
+		return guestAgentError(err)
+	}
+
+	defer func() { _ = agent.FileClose(handle) }()
+
+	return f(agent, handle)
+}
+
+// qemuGuestAgentFile is a file in the guest accessed through the QEMU guest agent.
+type qemuGuestAgentFile struct {
+	d    *qemu
+	path string
+	lock sync.Mutex
+}
+
+// ReadAt reads data from the file at the given offset.
+func (f *qemuGuestAgentFile) ReadAt(p []byte, off int64) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	h := &qemuGuestAgentSFTP{d: f.d}
+
+	n := 0
+	eof := false
+	err := h.withFile(f.path, "r", func(agent *qmp.GuestAgent, handle int64) error {
+		_, err := agent.FileSeek(handle, off, io.SeekStart)
+		if err != nil {
+			return err
+		}
+
+		for n < len(p) && !eof {
+			var data []byte
+			data, eof, err = agent.FileRead(handle, min(len(p)-n, qemuGuestAgentChunkSize))
+			if err != nil {
+				return err
+			}
+
+			n += copy(p[n:], data)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// WriteAt writes data to the file at the given offset.
+func (f *qemuGuestAgentFile) WriteAt(p []byte, off int64) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	h := &qemuGuestAgentSFTP{d: f.d}
+
+	n := 0
+	err := h.withFile(f.path, "r+", func(agent *qmp.GuestAgent, handle int64) error {
+		_, err := agent.FileSeek(handle, off, io.SeekStart)
+		if err != nil {
+			return err
+		}
+
+		for n < len(p) {
+			end := min(len(p), n+qemuGuestAgentChunkSize)
+
+			err = agent.FileWrite(handle, p[n:end])
+			if err != nil {
+				return err
+			}
+
+			n = end
+		}
+
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+
+	return n, nil
+}
+
+// qemuGuestAgentFileList implements sftp.ListerAt.
+type qemuGuestAgentFileList []os.FileInfo
+
+// ListAt copies the entries starting at the given offset.
+func (l qemuGuestAgentFileList) ListAt(entries []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+
+	n := copy(entries, l[offset:])
+	if n < len(entries) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// qemuGuestAgentFileInfo implements os.FileInfo and sftp.FileInfoUidGid.
+type qemuGuestAgentFileInfo struct {
+	name    string
+	mode    os.FileMode
+	size    int64
+	modTime time.Time
+	uid     uint32
+	gid     uint32
+}
+
+func (fi *qemuGuestAgentFileInfo) Name() string       { return fi.name }
+func (fi *qemuGuestAgentFileInfo) Size() int64        { return fi.size }
+func (fi *qemuGuestAgentFileInfo) Mode() os.FileMode  { return fi.mode }
+func (fi *qemuGuestAgentFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *qemuGuestAgentFileInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi *qemuGuestAgentFileInfo) Sys() any           { return nil }
+func (fi *qemuGuestAgentFileInfo) Uid() uint32        { return fi.uid }
+func (fi *qemuGuestAgentFileInfo) Gid() uint32        { return fi.gid }
@@ -0,0 +1,58 @@
+package drivers
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/instance/drivers/qmp"
+)
+
+func Test_guestAgentFilePath(t *testing.T) {
+	linux := &qmp.GuestOSInfo{ID: "debian"}
+	windows := &qmp.GuestOSInfo{ID: "mswindows"}
+
+	tests := []struct {
+		osInfo   *qmp.GuestOSInfo
+		path     string
+		expected string
+	}{
+		{osInfo: linux, path: "/etc/hosts", expected: "/etc/hosts"},
+		{osInfo: linux, path: "/C:/foo", expected: "/C:/foo"},
+		{osInfo: windows, path: "/C:/Users/foo/bar.txt", expected: `C:\Users\foo\bar.txt`},
+		{osInfo: windows, path: "/D:", expected: "D:"},
+		{osInfo: windows, path: "/Windows/win.ini", expected: `\Windows\win.ini`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.osInfo.ID+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, guestAgentFilePath(tt.osInfo, tt.path))
+		})
+	}
+}
+
+func Test_guestAgentError(t *testing.T) {
+	tests := []struct {
+		msg      string
+		expected error
+	}{
+		{msg: `Guest agent command "guest-file-open" failed: failed to open file '/foo' (mode: 'r'): No such file or directory`, expected: os.ErrNotExist},
+		{msg: `Guest agent command "guest-file-open" failed: failed to open file 'C:\foo': The system cannot find the file specified.`, expected: os.ErrNotExist},
+		{msg: `Guest agent command "guest-file-open" failed: failed to open file '/root/foo' (mode: 'w'): Permission denied`, expected: os.ErrPermission},
+		{msg: `Guest agent command "guest-file-open" failed: failed to open file 'C:\Windows': Access is denied.`, expected: os.ErrPermission},
+		{msg: `Guest agent command "guest-file-read" failed: failed to read file: Is a directory`, expected: unix.EISDIR},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			assert.True(t, errors.Is(guestAgentError(errors.New(tt.msg)), tt.expected))
+		})
+	}
+
+	err := errors.New("Guest agent command \"guest-file-seek\" failed: Invalid argument")
+	assert.Equal(t, err, guestAgentError(err))
+	assert.NoError(t, guestAgentError(nil))
+}
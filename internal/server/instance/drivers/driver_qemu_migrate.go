@@ -0,0 +1,521 @@
+package drivers
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/lxc/incus/v6/internal/migration"
+	"github.com/lxc/incus/v6/internal/server/instance/drivers/qmp"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// qemuMigrationMaxFrameSize is the maximum amount of data carried by a single migration channel frame.
+const qemuMigrationMaxFrameSize = 256 * 1024
+
+// qemuMigrationRecoverAttempts is how many times a paused post-copy migration is recovered before giving up.
+const qemuMigrationRecoverAttempts = 3
+
+// qemuMigrationRecoverTimeout is how long a recovered post-copy migration has to make progress again.
+var qemuMigrationRecoverTimeout = 30 * time.Second
+
+// migrationSocketPath returns the path of the unix socket used for live migration with multiple channels.
+func (d *qemu) migrationSocketPath() string {
+	return filepath.Join(d.RunPath(), "migration.sock")
+}
+
+// migrationLiveFeatures returns the live migration features enabled in the instance configuration and
+// supported by the local QEMU, or nil if none are.
+func (d *qemu) migrationLiveFeatures() *migration.QemuFeatures {
+	features := &migration.QemuFeatures{}
+	supported := DriverStatuses()[instancetype.VM].Info.Features
+
+	channels, _ := strconv.ParseUint(d.expandedConfig["migration.live.multifd"], 10, 32)
+	compression := d.expandedConfig["migration.live.compression"]
+	if compression == "none" {
+		compression = ""
+	}
+
+	if compression != "" && channels == 0 {
+		// Compression is done by the multifd channels.
+		channels = 1
+	}
+
+	if channels > 0 {
+		_, found := supported["migration_multifd"]
+		if found {
+			features.MultifdChannels = proto.Uint32(uint32(channels))
+		} else {
+			d.logger.Warn("Ignoring multifd live migration as it's not supported by QEMU")
+		}
+	}
+
+	if compression != "" && features.MultifdChannels != nil {
+		_, found := supported["migration_"+compression]
+		if found {
+			features.MultifdCompression = proto.String(compression)
+		} else {
+			d.logger.Warn("Ignoring live migration compression as it's not supported by QEMU", logger.Ctx{"compression": compression})
+		}
+	}
+
+	if util.IsTrue(d.expandedConfig["migration.live.postcopy"]) {
+		_, found := supported["migration_postcopy"]
+		if found {
+			features.Postcopy = proto.Bool(true)
+		} else {
+			d.logger.Warn("Ignoring post-copy live migration as it's not supported by QEMU")
+		}
+	}
+
+	if features.MultifdChannels == nil && features.Postcopy == nil {
+		return nil
+	}
+
+	return features
+}
+
+// qemuMigrationFeaturesSupported returns the subset of the offered live migration features which are
+// supported by the local QEMU, or nil if none are.
+func qemuMigrationFeaturesSupported(offer *migration.QemuFeatures) *migration.QemuFeatures {
+	if offer == nil {
+		return nil
+	}
+
+	features := &migration.QemuFeatures{}
+	supported := DriverStatuses()[instancetype.VM].Info.Features
+
+	_, found := supported["migration_multifd"]
+	if found && offer.GetMultifdChannels() > 0 {
+		features.MultifdChannels = proto.Uint32(offer.GetMultifdChannels())
+
+		compression := offer.GetMultifdCompression()
+		_, found = supported["migration_"+compression]
+		if compression != "" && found {
+			features.MultifdCompression = proto.String(compression)
+		}
+	}
+
+	_, found = supported["migration_postcopy"]
+	if found && offer.GetPostcopy() {
+		features.Postcopy = proto.Bool(true)
+	}
+
+	if features.MultifdChannels == nil && features.Postcopy == nil {
+		return nil
+	}
+
+	return features
+}
+
+// migrationSetup configures the negotiated live migration features, this must be done on both sides.
+func (d *qemu) migrationSetup(monitor *qmp.Monitor, features *migration.QemuFeatures) error {
+	capabilities := map[string]bool{}
+
+	if features.GetMultifdChannels() > 0 {
+		capabilities["multifd"] = true
+	}
+
+	if features.GetPostcopy() {
+		capabilities["postcopy-ram"] = true
+	}
+
+	err := monitor.MigrateSetCapabilities(capabilities)
+	if err != nil {
+		return fmt.Errorf("Failed setting migration capabilities: %w", err)
+	}
+
+	if features.GetMultifdChannels() > 0 {
+		params := map[string]any{
+			"multifd-channels": features.GetMultifdChannels(),
+		}
+
+		if features.GetMultifdCompression() != "" {
+			params["multifd-compression"] = features.GetMultifdCompression()
+		}
+
+		err = monitor.MigrateSetParameters(params)
+		if err != nil {
+			return fmt.Errorf("Failed setting migration parameters: %w", err)
+		}
+	}
+
+	d.logger.Debug("Configured live migration features", logger.Ctx{"multifd": features.GetMultifdChannels(), "compression": features.GetMultifdCompression(), "postcopy": features.GetPostcopy()})
+
+	return nil
+}
+
+// migrationTrackProgress reports the progress of the outgoing migration in the operation metadata until the
+// context is cancelled. When post-copy is enabled, it also switches to post-copy mode once a first pass
+// over the guest memory has completed and records it in postcopyStarted.
+func (d *qemu) migrationTrackProgress(ctx context.Context, monitor *qmp.Monitor, postcopy bool, postcopyStarted *atomic.Bool) {
+	metadata := make(map[string]any)
+	postcopyRequested := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+
+		status, err := monitor.QueryMigrate()
+		if err != nil {
+			d.logger.Debug("Failed querying migration status", logger.Ctx{"err": err})
+			return
+		}
+
+		if postcopy && !postcopyRequested && status.Status == "active" && status.RAM.DirtySyncCount >= 2 {
+			// Record the switch first so the source is never resumed once the target may be running.
+			postcopyStarted.Store(true)
+
+			err = monitor.MigrateStartPostcopy()
+			if err != nil {
+				postcopyStarted.Store(false)
+				d.logger.Warn("Failed switching migration to post-copy mode", logger.Ctx{"err": err})
+			} else {
+				d.logger.Debug("Switching migration to post-copy mode")
+			}
+
+			postcopyRequested = true
+		}
+
+		if d.op == nil || status.RAM.Total <= 0 {
+			continue
+		}
+
+		displayPrefix := "Memory transfer"
+		if status.Status == "postcopy-active" {
+			displayPrefix = "Memory transfer (post-copy)"
+		}
+
+		percent := (status.RAM.Total - status.RAM.Remaining) * 100 / status.RAM.Total
+		speed := int64(status.RAM.Mbps * 1000 * 1000 / 8)
+
+		operations.SetProgressMetadata(metadata, "live_migrate", displayPrefix, percent, status.RAM.Transferred, speed)
+		_ = d.op.UpdateMetadata(metadata)
+	}
+}
+
+// migrationWait waits for the migration to complete.
+// A paused post-copy migration is recovered with the provided function, if any, as neither side has a complete
+// copy of the guest memory anymore at that point.
+func (d *qemu) migrationWait(monitor *qmp.Monitor, recoverMigration func() error) error {
+	attempts := 0
+
+	for {
+		err := monitor.MigrateWait("completed")
+		if !errors.Is(err, qmp.ErrMigrationPostcopyPaused) || recoverMigration == nil {
+			return err
+		}
+
+		for {
+			attempts++
+			if attempts > qemuMigrationRecoverAttempts {
+				return fmt.Errorf("Failed recovering post-copy migration after %d attempts: %w", qemuMigrationRecoverAttempts, err)
+			}
+
+			d.logger.Warn("Post-copy migration paused, recovering", logger.Ctx{"attempt": attempts})
+
+			recoverErr := recoverMigration()
+			if recoverErr != nil {
+				d.logger.Warn("Failed recovering post-copy migration", logger.Ctx{"attempt": attempts, "err": recoverErr})
+			}
+
+			recovered, err := monitor.MigrateWaitRecovered(qemuMigrationRecoverTimeout)
+			if err != nil {
+				return err
+			}
+
+			if recovered {
+				d.logger.Info("Post-copy migration recovered", logger.Ctx{"attempt": attempts})
+				break
+			}
+		}
+	}
+}
+
+// migrationMarkBroken records that the instance state was lost during a failed post-copy migration.
+// The instance is then reported in error status until it's stopped.
+func (d *qemu) migrationMarkBroken(err error) {
+	d.logger.Error("Post-copy migration failed, instance state is lost", logger.Ctx{"err": err})
+
+	err = d.VolatileSet(map[string]string{"volatile.migration.broken": "true"})
+	if err != nil {
+		d.logger.Error("Failed marking instance as broken", logger.Ctx{"err": err})
+	}
+}
+
+// qemuMigrationMux carries the channels of a QEMU migration over a single connection.
+// Each chunk of data is sent as a frame made of the channel ID, the data length and the data itself.
+// A frame without data signals the end of the channel.
+type qemuMigrationMux struct {
+	conn io.ReadWriteCloser
+
+	// dial is used by the receiving side to connect new channels to QEMU.
+	dial func() (net.Conn, error)
+
+	writeLock    sync.Mutex
+	channelsLock sync.Mutex
+	channels     map[uint32]net.Conn
+	nextID       uint32
+}
+
+// newQemuMigrationMux returns a new migration channel multiplexer using the provided connection.
+func newQemuMigrationMux(conn io.ReadWriteCloser, dial func() (net.Conn, error)) *qemuMigrationMux {
+	return &qemuMigrationMux{
+		conn:     conn,
+		dial:     dial,
+		channels: map[uint32]net.Conn{},
+	}
+}
+
+// Accept forwards all connections made to the listener until it's closed.
+func (m *qemuMigrationMux) Accept(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		m.channelsLock.Lock()
+		id := m.nextID
+		m.nextID++
+		m.channels[id] = conn
+		m.channelsLock.Unlock()
+
+		go m.forward(id, conn)
+	}
+}
+
+// Run reads the frames from the connection and writes them to the matching channels until the connection is closed.
+func (m *qemuMigrationMux) Run() error {
+	defer m.Close()
+
+	header := make([]byte, 8)
+	buf := make([]byte, qemuMigrationMaxFrameSize)
+
+	for {
+		_, err := io.ReadFull(m.conn, header)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		id := binary.BigEndian.Uint32(header[0:4])
+		length := binary.BigEndian.Uint32(header[4:8])
+		if length > qemuMigrationMaxFrameSize {
+			return fmt.Errorf("Invalid migration frame size %d", length)
+		}
+
+		_, err = io.ReadFull(m.conn, buf[:length])
+		if err != nil {
+			return err
+		}
+
+		conn, err := m.channel(id)
+		if err != nil {
+			return err
+		}
+
+		if conn == nil {
+			continue
+		}
+
+		if length == 0 {
+			unixConn, ok := conn.(*net.UnixConn)
+			if ok {
+				_ = unixConn.CloseWrite()
+			}
+
+			continue
+		}
+
+		_, err = conn.Write(buf[:length])
+		if err != nil {
+			return fmt.Errorf("Failed writing to migration channel %d: %w", id, err)
+		}
+	}
+}
+
+// Close closes all the channels.
+func (m *qemuMigrationMux) Close() {
+	m.channelsLock.Lock()
+	defer m.channelsLock.Unlock()
+
+	for _, conn := range m.channels {
+		_ = conn.Close()
+	}
+}
+
+// channel returns the local connection of a channel, connecting it first if needed on the receiving side.
+func (m *qemuMigrationMux) channel(id uint32) (net.Conn, error) {
+	m.channelsLock.Lock()
+	defer m.channelsLock.Unlock()
+
+	conn, ok := m.channels[id]
+	if ok || m.dial == nil {
+		return conn, nil
+	}
+
+	conn, err := m.dial()
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting migration channel %d: %w", id, err)
+	}
+
+	m.channels[id] = conn
+	go m.forward(id, conn)
+
+	return conn, nil
+}
+
+// forward sends the data read from a local connection as frames until it's closed.
+func (m *qemuMigrationMux) forward(id uint32, conn net.Conn) {
+	buf := make([]byte, 8+qemuMigrationMaxFrameSize)
+
+	for {
+		n, err := conn.Read(buf[8:])
+		if n > 0 {
+			writeErr := m.writeFrame(id, buf[:8+n])
+			if writeErr != nil {
+				return
+			}
+		}
+
+		if err != nil {
+			_ = m.writeFrame(id, buf[:8])
+			return
+		}
+	}
+}
+
+// writeFrame fills in the header of the frame and sends it.
+func (m *qemuMigrationMux) writeFrame(id uint32, frame []byte) error {
+	binary.BigEndian.PutUint32(frame[0:4], id)
+	binary.BigEndian.PutUint32(frame[4:8], uint32(len(frame)-8))
+
+	m.writeLock.Lock()
+	defer m.writeLock.Unlock()
+
+	_, err := m.conn.Write(frame)
+	return err
+}
+
+// dialMigrationSocket connects to the migration socket of the local QEMU, waiting for it to be listening.
+func (d *qemu) dialMigrationSocket() (net.Conn, error) {
+	var err error
+	var conn net.Conn
+
+	for range 50 {
+		conn, err = net.Dial("unix", d.migrationSocketPath())
+		if err == nil {
+			return conn, nil
+		}
+
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	return nil, err
+}
+
+// migrateSendLiveStateMux starts sending the VM state over the state connection using a unix socket, which
+// allows for the multifd channels and return path needed by the negotiated features.
+// The returned function must be called once the migration has completed.
+func (d *qemu) migrateSendLiveStateMux(monitor *qmp.Monitor, stateConn io.ReadWriteCloser) (func(), error) {
+	socketPath := d.migrationSocketPath()
+	_ = os.Remove(socketPath)
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating migration unix listener: %w", err)
+	}
+
+	mux := newQemuMigrationMux(stateConn, nil)
+	go mux.Accept(listener)
+	go func() {
+		err := mux.Run()
+		if err != nil {
+			d.logger.Debug("Migration channels stopped", logger.Ctx{"err": err})
+		}
+	}()
+
+	cleanup := func() {
+		_ = listener.Close()
+		_ = os.Remove(socketPath)
+		mux.Close()
+	}
+
+	err = monitor.MigrateUnix(socketPath)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	return cleanup, nil
+}
+
+// migrateReceiveLiveStateMux receives the VM state over the state connection using a unix socket.
+// When post-copy is enabled, it returns as soon as the guest can be resumed and the remaining memory is
+// transferred in the background.
+func (d *qemu) migrateReceiveLiveStateMux(monitor *qmp.Monitor, stateConn io.ReadWriteCloser, features *migration.QemuFeatures) error {
+	err := d.migrationSetup(monitor, features)
+	if err != nil {
+		return err
+	}
+
+	socketPath := d.migrationSocketPath()
+	_ = os.Remove(socketPath)
+
+	mux := newQemuMigrationMux(stateConn, d.dialMigrationSocket)
+	go func() {
+		err := mux.Run()
+		if err != nil {
+			d.logger.Debug("Migration channels stopped", logger.Ctx{"err": err})
+		}
+	}()
+
+	states := []string{"completed"}
+	if features.GetPostcopy() {
+		states = append(states, "postcopy-active")
+	}
+
+	err = monitor.MigrateIncomingUnix(context.Background(), socketPath, states...)
+	if err != nil {
+		mux.Close()
+		return err
+	}
+
+	if !features.GetPostcopy() {
+		return nil
+	}
+
+	// Wait for the rest of the memory in the background as the guest needs to run to request it.
+	go func() {
+		err := d.migrationWait(monitor, func() error { return monitor.MigrateRecoverUnix(socketPath) })
+		if err != nil {
+			d.migrationMarkBroken(fmt.Errorf("Failed receiving post-copy migration memory: %w", err))
+			return
+		}
+
+		d.logger.Debug("Post-copy migration receive finished")
+	}()
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package drivers
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestQemuMigrationMux(t *testing.T) {
+	sourceConn, targetConn := net.Pipe()
+
+	// Target side, standing in for the QEMU process listening for incoming migration channels.
+	targetPath := filepath.Join(t.TempDir(), "target.sock")
+	targetListener, err := net.Listen("unix", targetPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = targetListener.Close() }()
+
+	targetMux := newQemuMigrationMux(targetConn, func() (net.Conn, error) { return net.Dial("unix", targetPath) })
+	go func() { _ = targetMux.Run() }()
+
+	// Source side, standing in for the QEMU process connecting its migration channels.
+	sourcePath := filepath.Join(t.TempDir(), "source.sock")
+	sourceListener, err := net.Listen("unix", sourcePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = sourceListener.Close() }()
+
+	sourceMux := newQemuMigrationMux(sourceConn, nil)
+	go sourceMux.Accept(sourceListener)
+	go func() { _ = sourceMux.Run() }()
+
+	defer sourceMux.Close()
+
+	channels := [][]byte{
+		bytes.Repeat([]byte("main"), 200*1024),
+		bytes.Repeat([]byte("multifd"), 100*1024),
+	}
+
+	received := make(chan []byte, len(channels))
+	var wg sync.WaitGroup
+
+	for range channels {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			conn, err := targetListener.Accept()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			defer func() { _ = conn.Close() }()
+
+			data, err := io.ReadAll(conn)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			// Answer on the return path.
+			_, err = conn.Write([]byte("ack"))
+			if err != nil {
+				t.Error(err)
+			}
+
+			received <- data
+		}()
+	}
+
+	for _, data := range channels {
+		conn, err := net.Dial("unix", sourcePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = conn.Write(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = conn.(*net.UnixConn).CloseWrite()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reply, err := io.ReadAll(conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(reply) != "ack" {
+			t.Fatalf("Unexpected reply %q", reply)
+		}
+
+		_ = conn.Close()
+	}
+
+	wg.Wait()
+	close(received)
+
+	count := 0
+	for data := range received {
+		if !bytes.Equal(data, channels[0]) && !bytes.Equal(data, channels[1]) {
+			t.Fatalf("Unexpected data received on channel (%d bytes)", len(data))
+		}
+
+		count++
+	}
+
+	if count != len(channels) {
+		t.Fatalf("Expected %d channels, got %d", len(channels), count)
+	}
+}
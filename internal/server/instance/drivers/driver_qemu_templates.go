@@ -111,13 +111,56 @@ func qemuBase(opts *qemuBaseOpts) []cfg.Section {
 
 type qemuMemoryOpts struct {
 	memSizeMB int64
+	maxSizeMB int64
 }
 
 func qemuMemory(opts *qemuMemoryOpts) []cfg.Section {
+	entries := []cfg.Entry{{Key: "size", Value: fmt.Sprintf("%dM", opts.memSizeMB)}}
+
+	if opts.maxSizeMB > opts.memSizeMB {
+		entries = append(entries, cfg.Entry{Key: "maxmem", Value: fmt.Sprintf("%dM", opts.maxSizeMB)})
+	}
+
 	return []cfg.Section{{
 		Name:    "memory",
 		Comment: "Memory",
-		Entries: []cfg.Entry{{Key: "size", Value: fmt.Sprintf("%dM", opts.memSizeMB)}},
+		Entries: entries,
+	}}
+}
+
+type qemuMemoryHotplugOpts struct {
+	dev    qemuDevOpts
+	sizeMB int64
+	numa   bool
+}
+
+func qemuMemoryHotplug(opts *qemuMemoryHotplugOpts) []cfg.Section {
+	entriesOpts := qemuDevEntriesOpts{
+		dev:     opts.dev,
+		pciName: "virtio-mem-pci",
+	}
+
+	deviceEntries := qemuDeviceEntries(&entriesOpts)
+	deviceEntries = append(deviceEntries, []cfg.Entry{
+		{Key: "memdev", Value: "mem_hotplug"},
+		{Key: "requested-size", Value: "0"},
+	}...)
+
+	if opts.numa {
+		deviceEntries = append(deviceEntries, cfg.Entry{Key: "node", Value: "0"})
+	}
+
+	return []cfg.Section{{
+		Name:    `object "mem_hotplug"`,
+		Comment: "Memory hotplug",
+		Entries: []cfg.Entry{
+			{Key: "qom-type", Value: "memory-backend-memfd"},
+			{Key: "size", Value: fmt.Sprintf("%dM", opts.sizeMB)},
+			{Key: "share", Value: "on"},
+		},
+	}, {
+		Name:    fmt.Sprintf(`device "%s"`, qemuMemoryHotplugDevName),
+		Entries: deviceEntries,
 	}}
 }
 
@@ -232,6 +275,31 @@ func qemuSerial(opts *qemuSerialOpts) []cfg.Section {
 	}}
 }
 
+type qemuGuestAgentOpts struct {
+	path string
+}
+
+func qemuGuestAgent(opts *qemuGuestAgentOpts) []cfg.Section {
+	return []cfg.Section{{
+		Name:    `chardev "qemu_guest_agent-chardev"`,
+		Comment: "QEMU guest agent",
+		Entries: []cfg.Entry{
+			{Key: "backend", Value: "socket"},
+			{Key: "path", Value: opts.path},
+			{Key: "server", Value: "on"},
+			{Key: "wait", Value: "off"},
+		},
+	}, {
+		Name: `device "qemu_guest_agent"`,
+		Entries: []cfg.Entry{
+			{Key: "driver", Value: "virtserialport"},
+			{Key: "name", Value: "org.qemu.guest_agent.0"},
+			{Key: "chardev", Value: "qemu_guest_agent-chardev"},
+			{Key: "bus", Value: "dev-qemu_serial.0"},
+		},
+	}}
+}
+
 type qemuPCIeOpts struct {
 	portName      string
 	index         int
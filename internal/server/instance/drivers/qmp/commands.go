@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -68,6 +69,16 @@ type HotpluggableCPU struct {
 	Props CPUInstanceProperties `json:"props"`
 }
 
+// MemoryDevice contains information about a virtio-mem device.
+type MemoryDevice struct {
+	ID            string `json:"id"`
+	Node          int    `json:"node"`
+	Size          int64  `json:"size"`
+	MaxSize       int64  `json:"max-size"`
+	BlockSize     int64  `json:"block-size"`
+	RequestedSize int64  `json:"requested-size"`
+}
+
 // CPUModel contains information about a CPU model.
 type CPUModel struct {
 	Name  string         `json:"name"`
@@ -314,27 +325,86 @@ func (m *Monitor) MigrateSetCapabilities(caps map[string]bool) error {
 	return nil
 }
 
-// Migrate starts a migration stream.
-func (m *Monitor) Migrate(name string) error {
-	// Query the status.
+// MigrateSetParameters sets the parameters used during migration.
+func (m *Monitor) MigrateSetParameters(params map[string]any) error {
+	err := m.Run("migrate-set-parameters", params, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
 
-	type migrateArgsChannel struct {
-		ChannelType string            `json:"channel-type"`
-		Address     map[string]string `json:"addr"`
+// MigrationStatus contains the status of a migration job.
+type MigrationStatus struct {
+	Status string `json:"status"`
+	RAM    struct {
+		Transferred    int64   `json:"transferred"`
+		Remaining      int64   `json:"remaining"`
+		Total          int64   `json:"total"`
+		Mbps           float64 `json:"mbps"`
+		DirtySyncCount int64   `json:"dirty-sync-count"`
+	} `json:"ram"`
+}
+
+// QueryMigrate returns the status of the current migration job.
+func (m *Monitor) QueryMigrate() (*MigrationStatus, error) {
+	var resp struct {
+		Return MigrationStatus `json:"return"`
 	}
 
-	type migrateArgs struct {
-		Channels []migrateArgsChannel `json:"channels"`
+	err := m.Run("query-migrate", nil, &resp)
+	if err != nil {
+		return nil, err
 	}
 
+	return &resp.Return, nil
+}
+
+type migrateArgsChannel struct {
+	ChannelType string            `json:"channel-type"`
+	Address     map[string]string `json:"addr"`
+}
+
+type migrateArgs struct {
+	Channels []migrateArgsChannel `json:"channels"`
+	Resume   bool                 `json:"resume,omitempty"`
+}
+
+// Migrate starts a migration stream.
+func (m *Monitor) Migrate(name string) error {
+	return m.migrate(map[string]string{
+		"transport": "socket",
+		"type":      "fd",
+		"str":       name,
+	}, false)
+}
+
+// MigrateUnix starts a migration stream to a unix socket.
+// Unlike a file descriptor, a socket allows for additional multifd channels and a return path.
+func (m *Monitor) MigrateUnix(path string) error {
+	return m.migrate(map[string]string{
+		"transport": "socket",
+		"type":      "unix",
+		"path":      path,
+	}, false)
+}
+
+// MigrateResumeUnix resumes a paused post-copy migration over a new connection to a unix socket.
+func (m *Monitor) MigrateResumeUnix(path string) error {
+	return m.migrate(map[string]string{
+		"transport": "socket",
+		"type":      "unix",
+		"path":      path,
+	}, true)
+}
+
+func (m *Monitor) migrate(address map[string]string, resume bool) error {
 	args := migrateArgs{}
+	args.Resume = resume
 	args.Channels = []migrateArgsChannel{{
 		ChannelType: "main",
-		Address: map[string]string{
-			"transport": "socket",
-			"type":      "fd",
-			"str":       name,
-		},
+		Address:     address,
 	}}
 
 	err := m.Run("migrate", args, nil)
@@ -345,33 +415,58 @@ func (m *Monitor) Migrate(name string) error {
 	return nil
 }
 
+// migrationPollInterval is how often the status of a migration is checked while waiting for it.
+var migrationPollInterval = time.Second
+
 // MigrateWait waits until migration job reaches the specified status.
 // Returns nil if the migraton job reaches the specified status or an error if the migration job is in the failed
-// status.
+// or cancelled status. ErrMigrationPostcopyPaused is returned if a post-copy migration got paused, as it then
+// needs to be recovered to make progress.
 func (m *Monitor) MigrateWait(state string) error {
 	// Wait until it completes or fails.
 	for {
-		// Prepare the response.
-		var resp struct {
-			Return struct {
-				Status string `json:"status"`
-			} `json:"return"`
-		}
-
-		err := m.Run("query-migrate", nil, &resp)
+		status, err := m.QueryMigrate()
 		if err != nil {
 			return err
 		}
 
-		if resp.Return.Status == "failed" {
+		if status.Status == state {
+			return nil
+		}
+
+		switch status.Status {
+		case "failed":
 			return fmt.Errorf("Migrate call failed")
+		case "cancelled":
+			return fmt.Errorf("Migrate call cancelled")
+		case "postcopy-paused":
+			return ErrMigrationPostcopyPaused
 		}
 
-		if resp.Return.Status == state {
-			return nil
+		time.Sleep(migrationPollInterval)
+	}
+}
+
+// MigrateWaitRecovered waits for a paused post-copy migration to make progress again.
+// Returns false if the migration is still paused or recovering once the timeout is reached.
+func (m *Monitor) MigrateWaitRecovered(timeout time.Duration) (bool, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		status, err := m.QueryMigrate()
+		if err != nil {
+			return false, err
 		}
 
-		time.Sleep(1 * time.Second)
+		if !slices.Contains([]string{"postcopy-paused", "postcopy-recover", "postcopy-recover-setup"}, status.Status) {
+			return true, nil
+		}
+
+		if time.Now().After(deadline) {
+			return false, nil
+		}
+
+		time.Sleep(migrationPollInterval)
 	}
 }
 
@@ -391,25 +486,41 @@ func (m *Monitor) MigrateContinue(fromState string) error {
 	return nil
 }
 
+// MigrateStartPostcopy switches a running migration from pre-copy to post-copy mode.
+func (m *Monitor) MigrateStartPostcopy() error {
+	return m.Run("migrate-start-postcopy", nil, nil)
+}
+
+// MigrateRecoverUnix makes the target of a paused post-copy migration listen on a unix socket for the source
+// to resume the migration.
+func (m *Monitor) MigrateRecoverUnix(path string) error {
+	return m.Run("migrate-recover", map[string]string{"uri": "unix:" + path}, nil)
+}
+
 // MigrateIncoming starts the receiver of a migration stream.
 func (m *Monitor) MigrateIncoming(ctx context.Context, name string) error {
-	type migrateArgsChannel struct {
-		ChannelType string            `json:"channel-type"`
-		Address     map[string]string `json:"addr"`
-	}
+	return m.migrateIncoming(ctx, map[string]string{
+		"transport": "socket",
+		"type":      "fd",
+		"str":       name,
+	}, "completed")
+}
 
-	type migrateArgs struct {
-		Channels []migrateArgsChannel `json:"channels"`
-	}
+// MigrateIncomingUnix starts the receiver of a migration stream listening on a unix socket.
+// It returns once the migration has reached one of the specified statuses.
+func (m *Monitor) MigrateIncomingUnix(ctx context.Context, path string, states ...string) error {
+	return m.migrateIncoming(ctx, map[string]string{
+		"transport": "socket",
+		"type":      "unix",
+		"path":      path,
+	}, states...)
+}
 
+func (m *Monitor) migrateIncoming(ctx context.Context, address map[string]string, states ...string) error {
 	args := migrateArgs{}
 	args.Channels = []migrateArgsChannel{{
 		ChannelType: "main",
-		Address: map[string]string{
-			"transport": "socket",
-			"type":      "fd",
-			"str":       name,
-		},
+		Address:     address,
 	}}
 
 	// Query the status.
@@ -420,23 +531,16 @@ func (m *Monitor) MigrateIncoming(ctx context.Context, name string) error {
 
 	// Wait until it completes or fails.
 	for {
-		// Prepare the response.
-		var resp struct {
-			Return struct {
-				Status string `json:"status"`
-			} `json:"return"`
-		}
-
-		err := m.Run("query-migrate", nil, &resp)
+		status, err := m.QueryMigrate()
 		if err != nil {
 			return err
 		}
 
-		if resp.Return.Status == "failed" {
+		if status.Status == "failed" {
 			return fmt.Errorf("Migrate incoming call failed")
 		}
 
-		if resp.Return.Status == "completed" {
+		if slices.Contains(states, status.Status) {
 			return nil
 		}
 
@@ -448,8 +552,29 @@ func (m *Monitor) MigrateIncoming(ctx context.Context, name string) error {
 			return err
 		}
 
-		time.Sleep(1 * time.Second)
+		time.Sleep(migrationPollInterval)
+	}
+}
+
+// QueryMigrateCapabilities returns the migration capabilities supported by QEMU.
+func (m *Monitor) QueryMigrateCapabilities() ([]string, error) {
+	var resp struct {
+		Return []struct {
+			Capability string `json:"capability"`
+		} `json:"return"`
+	}
+
+	err := m.Run("query-migrate-capabilities", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities := make([]string, 0, len(resp.Return))
+	for _, capability := range resp.Return {
+		capabilities = append(capabilities, capability.Capability)
 	}
+
+	return capabilities, nil
 }
 
 // Powerdown tells the VM to gracefully shutdown.
@@ -537,6 +662,64 @@ func (m *Monitor) SetMemoryBalloonSizeBytes(sizeBytes int64) error {
 	return m.Run("balloon", args, nil)
 }
 
+// QueryVirtioMemDevices returns the virtio-mem devices.
+func (m *Monitor) QueryVirtioMemDevices() ([]MemoryDevice, error) {
+	// Prepare the response.
+	var resp struct {
+		Return []struct {
+			Type string       `json:"type"`
+			Data MemoryDevice `json:"data"`
+		} `json:"return"`
+	}
+
+	err := m.Run("query-memory-devices", nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query memory devices: %w", err)
+	}
+
+	devices := []MemoryDevice{}
+	for _, device := range resp.Return {
+		if device.Type == "virtio-mem" {
+			devices = append(devices, device.Data)
+		}
+	}
+
+	return devices, nil
+}
+
+// SetVirtioMemRequestedSize sets the amount of memory the guest should plug from a virtio-mem device.
+func (m *Monitor) SetVirtioMemRequestedSize(deviceID string, sizeBytes int64) error {
+	args := map[string]any{
+		"path":     "/machine/peripheral/" + deviceID,
+		"property": "requested-size",
+		"value":    sizeBytes,
+	}
+
+	return m.Run("qom-set", args, nil)
+}
+
+// DeviceListProperties returns the names of the properties of a device type.
+func (m *Monitor) DeviceListProperties(typeName string) ([]string, error) {
+	// Prepare the response.
+	var resp struct {
+		Return []struct {
+			Name string `json:"name"`
+		} `json:"return"`
+	}
+
+	err := m.Run("device-list-properties", map[string]string{"typename": typeName}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(resp.Return))
+	for _, property := range resp.Return {
+		names = append(names, property.Name)
+	}
+
+	return names, nil
+}
+
 // AddBlockDevice adds a block device.
 func (m *Monitor) AddBlockDevice(blockDev map[string]any, device map[string]any) error {
 	revert := revert.New()
@@ -1132,6 +1315,121 @@ func (m *Monitor) SetBlockThrottle(id string, bytesRead int, bytesWrite int, iop
 	return nil
 }
 
+// SetThrottleGroup creates or updates a throttle group object with the given I/O limits.
+func (m *Monitor) SetThrottleGroup(id string, bytesRead int, bytesWrite int, iopsRead int, iopsWrite int) error {
+	limits := map[string]any{
+		"bps-read":   bytesRead,
+		"bps-write":  bytesWrite,
+		"iops-read":  iopsRead,
+		"iops-write": iopsWrite,
+	}
+
+	exists, err := m.objectExists(id)
+	if err != nil {
+		return err
+	}
+
+	// Update the limits of an existing group.
+	if exists {
+		args := map[string]any{
+			"path":     "/objects/" + id,
+			"property": "limits",
+			"value":    limits,
+		}
+
+		err = m.Run("qom-set", args, nil)
+		if err != nil {
+			return fmt.Errorf("Failed updating throttle group: %w", err)
+		}
+
+		return nil
+	}
+
+	args := map[string]any{
+		"qom-type": "throttle-group",
+		"id":       id,
+		"limits":   limits,
+	}
+
+	err = m.Run("object-add", &args, nil)
+	if err != nil {
+		return fmt.Errorf("Failed adding throttle group: %w", err)
+	}
+
+	return nil
+}
+
+// GetThrottleGroups returns the IDs of the existing throttle group objects.
+func (m *Monitor) GetThrottleGroups() ([]string, error) {
+	objects, err := m.objects()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []string{}
+	for id, objectType := range objects {
+		if objectType == "throttle-group" {
+			groups = append(groups, id)
+		}
+	}
+
+	slices.Sort(groups)
+
+	return groups, nil
+}
+
+// RemoveThrottleGroup removes a throttle group object.
+// QEMU refuses to remove a group which is still used by a throttle node.
+func (m *Monitor) RemoveThrottleGroup(id string) error {
+	err := m.Run("object-del", map[string]string{"id": id}, nil)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+
+		return fmt.Errorf("Failed removing throttle group: %w", err)
+	}
+
+	return nil
+}
+
+// objectExists returns whether a user-created object with the given ID exists.
+func (m *Monitor) objectExists(id string) (bool, error) {
+	objects, err := m.objects()
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := objects[id]
+
+	return ok, nil
+}
+
+// objects returns the type of each user-created object, keyed by object ID.
+func (m *Monitor) objects() (map[string]string, error) {
+	var resp struct {
+		Return []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"return"`
+	}
+
+	err := m.Run("qom-list", map[string]any{"path": "/objects"}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing objects: %w", err)
+	}
+
+	objects := map[string]string{}
+	for _, property := range resp.Return {
+		objectType, ok := strings.CutPrefix(property.Type, "child<")
+		if ok {
+			objects[property.Name] = strings.TrimSuffix(objectType, ">")
+		}
+	}
+
+	return objects, nil
+}
+
 // CheckPCIDevice checks if the deviceID exists as a bridged PCI device.
 func (m *Monitor) CheckPCIDevice(deviceID string) (bool, error) {
 	pciDevs, err := m.QueryPCI()
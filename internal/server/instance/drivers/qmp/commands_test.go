@@ -0,0 +1,216 @@
+package qmp
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// migrationStatusHandler answers query-migrate with the provided statuses, repeating the last one.
+func migrationStatusHandler(statuses ...string) func(cmd string, args json.RawMessage) (any, error) {
+	var mu sync.Mutex
+
+	return func(cmd string, args json.RawMessage) (any, error) {
+		switch cmd {
+		case "query-migrate":
+			mu.Lock()
+			defer mu.Unlock()
+
+			status := statuses[0]
+			if len(statuses) > 1 {
+				statuses = statuses[1:]
+			}
+
+			return map[string]any{"status": status}, nil
+		case "migrate", "migrate-recover":
+			return nil, nil
+		}
+
+		return nil, errTestCommandNotFound
+	}
+}
+
+func TestMonitorMigrateWait(t *testing.T) {
+	migrationPollInterval = time.Millisecond
+
+	tests := []struct {
+		name        string
+		statuses    []string
+		state       string
+		expectedErr error
+		expectErr   bool
+	}{
+		{name: "Completed", statuses: []string{"active", "postcopy-active", "completed"}, state: "completed"},
+		{name: "Failed", statuses: []string{"active", "failed"}, state: "completed", expectErr: true},
+		{name: "Cancelled", statuses: []string{"cancelling", "cancelled"}, state: "completed", expectErr: true},
+		{name: "Post-copy paused", statuses: []string{"postcopy-active", "postcopy-paused"}, state: "completed", expectedErr: ErrMigrationPostcopyPaused},
+		{name: "Waiting for post-copy pause", statuses: []string{"postcopy-active", "postcopy-paused"}, state: "postcopy-paused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor, _ := newTestMonitor(t, migrationStatusHandler(tt.statuses...))
+
+			err := monitor.MigrateWait(tt.state)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMonitorMigrateWaitRecovered(t *testing.T) {
+	migrationPollInterval = time.Millisecond
+
+	monitor, _ := newTestMonitor(t, migrationStatusHandler("postcopy-paused", "postcopy-recover", "postcopy-active"))
+
+	recovered, err := monitor.MigrateWaitRecovered(time.Minute)
+	require.NoError(t, err)
+	assert.True(t, recovered)
+
+	monitor, _ = newTestMonitor(t, migrationStatusHandler("postcopy-paused", "postcopy-recover"))
+
+	recovered, err = monitor.MigrateWaitRecovered(10 * time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, recovered)
+}
+
+func TestMonitorMigrateRecovery(t *testing.T) {
+	monitor, server := newTestMonitor(t, migrationStatusHandler("postcopy-paused"))
+
+	require.NoError(t, monitor.MigrateUnix("/run/migration.sock"))
+	require.NoError(t, monitor.MigrateResumeUnix("/run/migration.sock"))
+	require.NoError(t, monitor.MigrateRecoverUnix("/run/migration.sock"))
+
+	commands := server.Commands()
+	require.Len(t, commands, 3)
+
+	channels := `"channels":[{"channel-type":"main","addr":{"path":"/run/migration.sock","transport":"socket","type":"unix"}}]`
+
+	assert.Equal(t, "migrate", commands[0].Execute)
+	assert.JSONEq(t, `{`+channels+`}`, string(commands[0].Arguments))
+
+	assert.Equal(t, "migrate", commands[1].Execute)
+	assert.JSONEq(t, `{`+channels+`,"resume":true}`, string(commands[1].Arguments))
+
+	assert.Equal(t, "migrate-recover", commands[2].Execute)
+	assert.JSONEq(t, `{"uri":"unix:/run/migration.sock"}`, string(commands[2].Arguments))
+}
+
+func TestMonitorSetThrottleGroup(t *testing.T) {
+	handler := func(cmd string, args json.RawMessage) (any, error) {
+		switch cmd {
+		case "qom-list":
+			return []map[string]string{
+				{"name": "type", "type": "string"},
+				{"name": "incus_group_db", "type": "child<throttle-group>"},
+			}, nil
+		case "object-add", "qom-set":
+			return nil, nil
+		}
+
+		return nil, errTestCommandNotFound
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		expected []testCommand
+	}{
+		{
+			name: "New group",
+			id:   "incus_group_web",
+			expected: []testCommand{
+				{Execute: "qom-list", Arguments: json.RawMessage(`{"path":"/objects"}`)},
+				{Execute: "object-add", Arguments: json.RawMessage(`{"qom-type":"throttle-group","id":"incus_group_web","limits":{"bps-read":1000,"bps-write":2000,"iops-read":0,"iops-write":50}}`)},
+			},
+		},
+		{
+			name: "Existing group",
+			id:   "incus_group_db",
+			expected: []testCommand{
+				{Execute: "qom-list", Arguments: json.RawMessage(`{"path":"/objects"}`)},
+				{Execute: "qom-set", Arguments: json.RawMessage(`{"path":"/objects/incus_group_db","property":"limits","value":{"bps-read":1000,"bps-write":2000,"iops-read":0,"iops-write":50}}`)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor, server := newTestMonitor(t, handler)
+
+			err := monitor.SetThrottleGroup(tt.id, 1000, 2000, 0, 50)
+			require.NoError(t, err)
+
+			commands := server.Commands()
+			require.Len(t, commands, len(tt.expected))
+
+			for i, command := range commands {
+				assert.Equal(t, tt.expected[i].Execute, command.Execute)
+				assert.JSONEq(t, string(tt.expected[i].Arguments), string(command.Arguments))
+			}
+		})
+	}
+
+	// Failures to add the group are reported.
+	monitor, _ := newTestMonitor(t, func(cmd string, args json.RawMessage) (any, error) {
+		if cmd == "qom-list" {
+			return []map[string]string{}, nil
+		}
+
+		return nil, errors.New("Invalid parameter 'bps-read'")
+	})
+
+	err := monitor.SetThrottleGroup("incus_group_web", -1, 0, 0, 0)
+	assert.ErrorContains(t, err, "Failed adding throttle group")
+}
+
+func TestMonitorThrottleGroupCleanup(t *testing.T) {
+	handler := func(cmd string, args json.RawMessage) (any, error) {
+		switch cmd {
+		case "qom-list":
+			return []map[string]string{
+				{"name": "type", "type": "string"},
+				{"name": "incus_group_web", "type": "child<throttle-group>"},
+				{"name": "incus_group_db", "type": "child<throttle-group>"},
+				{"name": "incus_vsock", "type": "child<vhost-vsock-pci>"},
+			}, nil
+		case "object-del":
+			if string(args) == `{"id":"incus_group_web"}` {
+				return nil, errors.New("object 'incus_group_web' is in use, can not be deleted")
+			}
+
+			return nil, nil
+		}
+
+		return nil, errTestCommandNotFound
+	}
+
+	monitor, server := newTestMonitor(t, handler)
+
+	groups, err := monitor.GetThrottleGroups()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"incus_group_db", "incus_group_web"}, groups)
+
+	err = monitor.RemoveThrottleGroup("incus_group_db")
+	require.NoError(t, err)
+
+	commands := server.Commands()
+	require.Len(t, commands, 2)
+	assert.Equal(t, "object-del", commands[1].Execute)
+	assert.JSONEq(t, `{"id":"incus_group_db"}`, string(commands[1].Arguments))
+
+	// Groups still used by a disk can't be removed.
+	monitor, _ = newTestMonitor(t, handler)
+	err = monitor.RemoveThrottleGroup("incus_group_web")
+	assert.ErrorContains(t, err, "Failed removing throttle group")
+}
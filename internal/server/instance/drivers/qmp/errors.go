@@ -12,3 +12,6 @@ var ErrMonitorBadConsole = fmt.Errorf("Requested console couldn't be found")
 
 // ErrNotARingbuf is returned when the requested device isn't a ring buffer.
 var ErrNotARingbuf = fmt.Errorf("Requested device isn't a ring buffer")
+
+// ErrMigrationPostcopyPaused is returned when a post-copy migration got paused and needs to be recovered.
+var ErrMigrationPostcopyPaused = fmt.Errorf("Post-copy migration is paused")
@@ -0,0 +1,429 @@
+package qmp
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// GuestAgentSyncTimeout is how long to wait for the guest agent to answer the initial synchronization.
+var GuestAgentSyncTimeout = 2 * time.Second
+
+// GuestAgentCommandTimeout is how long to wait for the guest agent to answer a command.
+var GuestAgentCommandTimeout = 30 * time.Second
+
+// GuestAgentMaxResponseSize is the maximum amount of data read from the guest agent for a single command.
+// It accommodates the largest command output the agent returns, while protecting against a guest sending
+// endless responses.
+var GuestAgentMaxResponseSize int64 = 32 * 1024 * 1024
+
+// ErrGuestAgentNotRunning is returned when the guest agent doesn't answer.
+var ErrGuestAgentNotRunning = errors.New("QEMU guest agent isn't currently running")
+
+// The guest agent socket only serves one client at a time, so serialize access to it.
+// Entries are removed once the last client of a socket is done with it.
+var guestAgentLocks = map[string]*guestAgentLock{}
+var guestAgentLocksMu sync.Mutex
+
+// guestAgentLock serializes access to a guest agent socket and counts its current users.
+type guestAgentLock struct {
+	sync.Mutex
+
+	users int
+}
+
+// GuestExecStatus contains the status of a command started through the guest agent.
+type GuestExecStatus struct {
+	Exited       bool   `json:"exited"`
+	ExitCode     int    `json:"exitcode"`
+	Signal       int    `json:"signal"`
+	OutData      string `json:"out-data"`
+	ErrData      string `json:"err-data"`
+	OutTruncated bool   `json:"out-truncated"`
+	ErrTruncated bool   `json:"err-truncated"`
+}
+
+// GuestIPAddress contains an address of a guest network interface.
+type GuestIPAddress struct {
+	Type    string `json:"ip-address-type"`
+	Address string `json:"ip-address"`
+	Prefix  int    `json:"prefix"`
+}
+
+// GuestNetworkStats contains the counters of a guest network interface.
+type GuestNetworkStats struct {
+	RxBytes   int64 `json:"rx-bytes"`
+	RxPackets int64 `json:"rx-packets"`
+	RxErrors  int64 `json:"rx-errs"`
+	RxDropped int64 `json:"rx-dropped"`
+	TxBytes   int64 `json:"tx-bytes"`
+	TxPackets int64 `json:"tx-packets"`
+	TxErrors  int64 `json:"tx-errs"`
+	TxDropped int64 `json:"tx-dropped"`
+}
+
+// GuestOSInfo contains information about the guest operating system.
+type GuestOSInfo struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	PrettyName    string `json:"pretty-name"`
+	Version       string `json:"version"`
+	VersionID     string `json:"version-id"`
+	KernelRelease string `json:"kernel-release"`
+	KernelVersion string `json:"kernel-version"`
+	Machine       string `json:"machine"`
+}
+
+// IsWindows returns whether the guest runs Microsoft Windows.
+func (i *GuestOSInfo) IsWindows() bool {
+	return i.ID == "mswindows"
+}
+
+// GuestNetworkInterface contains information about a guest network interface.
+type GuestNetworkInterface struct {
+	Name            string             `json:"name"`
+	HardwareAddress string             `json:"hardware-address"`
+	IPAddresses     []GuestIPAddress   `json:"ip-addresses"`
+	Statistics      *GuestNetworkStats `json:"statistics"`
+}
+
+// GuestAgent represents a connection to the QEMU guest agent.
+type GuestAgent struct {
+	path    string
+	conn    net.Conn
+	limit   *io.LimitedReader
+	reader  *bufio.Reader
+	decoder *json.Decoder
+	lock    *guestAgentLock
+}
+
+// ConnectGuestAgent connects to the QEMU guest agent socket and synchronizes with the agent.
+func ConnectGuestAgent(path string) (*GuestAgent, error) {
+	guestAgentLocksMu.Lock()
+	lock, ok := guestAgentLocks[path]
+	if !ok {
+		lock = &guestAgentLock{}
+		guestAgentLocks[path] = lock
+	}
+
+	lock.users++
+	guestAgentLocksMu.Unlock()
+
+	lock.Lock()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		releaseGuestAgentLock(path, lock)
+		return nil, err
+	}
+
+	limit := &io.LimitedReader{R: conn}
+
+	agent := &GuestAgent{
+		path:   path,
+		conn:   conn,
+		limit:  limit,
+		reader: bufio.NewReader(limit),
+		lock:   lock,
+	}
+
+	err = agent.sync()
+	if err != nil {
+		agent.Disconnect()
+		return nil, err
+	}
+
+	return agent, nil
+}
+
+// Disconnect closes the connection to the guest agent.
+func (a *GuestAgent) Disconnect() {
+	_ = a.conn.Close()
+	releaseGuestAgentLock(a.path, a.lock)
+}
+
+// releaseGuestAgentLock unlocks the lock of a guest agent socket and forgets about it once unused.
+func releaseGuestAgentLock(path string, lock *guestAgentLock) {
+	lock.Unlock()
+
+	guestAgentLocksMu.Lock()
+	defer guestAgentLocksMu.Unlock()
+
+	lock.users--
+	if lock.users == 0 {
+		delete(guestAgentLocks, path)
+	}
+}
+
+// sync discards any stale data from a previous client and checks that the agent is responding.
+func (a *GuestAgent) sync() error {
+	id := rand.Int63n(1 << 31)
+
+	err := a.conn.SetDeadline(time.Now().Add(GuestAgentSyncTimeout))
+	if err != nil {
+		return err
+	}
+
+	// The leading 0xFF byte resets the parser of the agent.
+	request, err := json.Marshal(map[string]any{"execute": "guest-sync-delimited", "arguments": map[string]any{"id": id}})
+	if err != nil {
+		return err
+	}
+
+	_, err = a.conn.Write(append([]byte{0xFF}, request...))
+	if err != nil {
+		return err
+	}
+
+	a.limit.N = GuestAgentMaxResponseSize
+
+	for {
+		// The agent answers with a 0xFF byte followed by the response.
+		_, err = a.reader.ReadBytes(0xFF)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return ErrGuestAgentNotRunning
+			}
+
+			return a.responseError(err)
+		}
+
+		var resp struct {
+			Return int64 `json:"return"`
+		}
+
+		err = json.NewDecoder(a.reader).Decode(&resp)
+		if err != nil {
+			continue
+		}
+
+		if resp.Return == id {
+			a.reader.Reset(a.limit)
+			a.decoder = json.NewDecoder(a.reader)
+			return nil
+		}
+	}
+}
+
+// Run executes a command on the guest agent.
+func (a *GuestAgent) Run(cmd string, args any, resp any) error {
+	err := a.conn.SetDeadline(time.Now().Add(GuestAgentCommandTimeout))
+	if err != nil {
+		return err
+	}
+
+	request := map[string]any{"execute": cmd}
+	if args != nil {
+		request["arguments"] = args
+	}
+
+	err = json.NewEncoder(a.conn).Encode(request)
+	if err != nil {
+		return err
+	}
+
+	a.limit.N = GuestAgentMaxResponseSize
+
+	var response struct {
+		Return json.RawMessage `json:"return"`
+		Error  *struct {
+			Class string `json:"class"`
+			Desc  string `json:"desc"`
+		} `json:"error"`
+	}
+
+	err = a.decoder.Decode(&response)
+	if err != nil {
+		return fmt.Errorf("Failed reading guest agent response: %w", a.responseError(err))
+	}
+
+	if response.Error != nil {
+		return fmt.Errorf("Guest agent command %q failed: %s", cmd, response.Error.Desc)
+	}
+
+	if resp == nil || len(response.Return) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(response.Return, resp)
+}
+
+// responseError returns the error to report for a failed read, accounting for responses exceeding the size limit.
+func (a *GuestAgent) responseError(err error) error {
+	if a.limit.N <= 0 {
+		return fmt.Errorf("Guest agent response exceeds %d bytes", GuestAgentMaxResponseSize)
+	}
+
+	return err
+}
+
+// Ping checks that the guest agent is responsive.
+func (a *GuestAgent) Ping() error {
+	return a.Run("guest-ping", nil, nil)
+}
+
+// GetOSInfo returns information about the guest operating system.
+func (a *GuestAgent) GetOSInfo() (*GuestOSInfo, error) {
+	var resp GuestOSInfo
+
+	err := a.Run("guest-get-osinfo", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Exec starts a command in the guest and returns its PID.
+func (a *GuestAgent) Exec(path string, args []string, env []string) (int, error) {
+	request := map[string]any{
+		"path":           path,
+		"arg":            args,
+		"capture-output": true,
+	}
+
+	if len(env) > 0 {
+		request["env"] = env
+	}
+
+	var resp struct {
+		PID int `json:"pid"`
+	}
+
+	err := a.Run("guest-exec", request, &resp)
+	if err != nil {
+		return -1, err
+	}
+
+	return resp.PID, nil
+}
+
+// ExecStatus returns the status of a command started with Exec.
+func (a *GuestAgent) ExecStatus(pid int) (*GuestExecStatus, error) {
+	var resp GuestExecStatus
+
+	err := a.Run("guest-exec-status", map[string]any{"pid": pid}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// FileOpen opens a file in the guest and returns its handle.
+func (a *GuestAgent) FileOpen(path string, mode string) (int64, error) {
+	var handle int64
+
+	err := a.Run("guest-file-open", map[string]any{"path": path, "mode": mode}, &handle)
+	if err != nil {
+		return -1, err
+	}
+
+	return handle, nil
+}
+
+// FileClose closes a file opened with FileOpen.
+func (a *GuestAgent) FileClose(handle int64) error {
+	return a.Run("guest-file-close", map[string]any{"handle": handle}, nil)
+}
+
+// FileRead reads up to count bytes from a file, returning whether the end of the file was reached.
+func (a *GuestAgent) FileRead(handle int64, count int) ([]byte, bool, error) {
+	var resp struct {
+		Count  int    `json:"count"`
+		Buffer string `json:"buf-b64"`
+		EOF    bool   `json:"eof"`
+	}
+
+	err := a.Run("guest-file-read", map[string]any{"handle": handle, "count": count}, &resp)
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(resp.Buffer)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, resp.EOF, nil
+}
+
+// FileWrite writes data to a file.
+func (a *GuestAgent) FileWrite(handle int64, data []byte) error {
+	for len(data) > 0 {
+		var resp struct {
+			Count int `json:"count"`
+		}
+
+		err := a.Run("guest-file-write", map[string]any{"handle": handle, "buf-b64": base64.StdEncoding.EncodeToString(data)}, &resp)
+		if err != nil {
+			return err
+		}
+
+		if resp.Count <= 0 {
+			return io.ErrShortWrite
+		}
+
+		data = data[resp.Count:]
+	}
+
+	return nil
+}
+
+// FileSeek moves the position in a file and returns the new position.
+func (a *GuestAgent) FileSeek(handle int64, offset int64, whence int) (int64, error) {
+	var resp struct {
+		Position int64 `json:"position"`
+	}
+
+	err := a.Run("guest-file-seek", map[string]any{"handle": handle, "offset": offset, "whence": whence}, &resp)
+	if err != nil {
+		return -1, err
+	}
+
+	return resp.Position, nil
+}
+
+// NetworkGetInterfaces returns the network interfaces of the guest.
+func (a *GuestAgent) NetworkGetInterfaces() ([]GuestNetworkInterface, error) {
+	var resp []GuestNetworkInterface
+
+	err := a.Run("guest-network-get-interfaces", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// FSFreeze freezes the guest file systems and returns how many were frozen.
+func (a *GuestAgent) FSFreeze() (int, error) {
+	var count int
+
+	err := a.Run("guest-fsfreeze-freeze", nil, &count)
+	if err != nil {
+		return -1, err
+	}
+
+	return count, nil
+}
+
+// FSThaw thaws the guest file systems and returns how many were thawed.
+func (a *GuestAgent) FSThaw() (int, error) {
+	var count int
+
+	err := a.Run("guest-fsfreeze-thaw", nil, &count)
+	if err != nil {
+		return -1, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,206 @@
+package qmp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestGuestAgent starts a fake guest agent answering commands with the provided return values.
+func newTestGuestAgent(t *testing.T, responses map[string]any) string {
+	path := filepath.Join(t.TempDir(), "qemu.qga")
+
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer func() { _ = conn.Close() }()
+
+				reader := bufio.NewReader(conn)
+
+				// Skip the parser reset sent before synchronizing.
+				_, err := reader.ReadByte()
+				if err != nil {
+					return
+				}
+
+				decoder := json.NewDecoder(reader)
+				for {
+					var request struct {
+						Execute   string         `json:"execute"`
+						Arguments map[string]any `json:"arguments"`
+					}
+
+					err := decoder.Decode(&request)
+					if err != nil {
+						return
+					}
+
+					var response any
+					value, ok := responses[request.Execute]
+					if request.Execute == "guest-sync-delimited" {
+						_, _ = conn.Write([]byte{0xFF})
+						response = map[string]any{"return": int64(request.Arguments["id"].(float64))}
+					} else if ok {
+						response = map[string]any{"return": value}
+					} else {
+						response = map[string]any{"error": map[string]any{"class": "CommandNotFound", "desc": "The command " + request.Execute + " has not been found"}}
+					}
+
+					err = json.NewEncoder(conn).Encode(response)
+					if err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	return path
+}
+
+// guestAgentLockUsers returns how many clients currently use the lock of a guest agent socket.
+func guestAgentLockUsers(path string) int {
+	guestAgentLocksMu.Lock()
+	defer guestAgentLocksMu.Unlock()
+
+	lock, ok := guestAgentLocks[path]
+	if !ok {
+		return 0
+	}
+
+	return lock.users
+}
+
+func TestGuestAgentGetOSInfo(t *testing.T) {
+	path := newTestGuestAgent(t, map[string]any{
+		"guest-get-osinfo": map[string]any{"id": "mswindows", "name": "Microsoft Windows", "version": "Microsoft Windows Server 2022"},
+	})
+
+	agent, err := ConnectGuestAgent(path)
+	require.NoError(t, err)
+
+	defer agent.Disconnect()
+
+	info, err := agent.GetOSInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "Microsoft Windows", info.Name)
+	assert.True(t, info.IsWindows())
+
+	err = agent.Run("guest-unknown", nil, nil)
+	assert.ErrorContains(t, err, "has not been found")
+}
+
+func TestGuestAgentLocks(t *testing.T) {
+	path := newTestGuestAgent(t, map[string]any{"guest-ping": map[string]any{}})
+
+	first, err := ConnectGuestAgent(path)
+	require.NoError(t, err)
+
+	connected := make(chan *GuestAgent)
+	go func() {
+		second, err := ConnectGuestAgent(path)
+		if err != nil {
+			close(connected)
+			return
+		}
+
+		connected <- second
+	}()
+
+	// The second client waits for the first one to disconnect.
+	select {
+	case <-connected:
+		t.Fatal("Second client connected while the socket was in use")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.Equal(t, 2, guestAgentLockUsers(path))
+
+	first.Disconnect()
+
+	second, ok := <-connected
+	require.True(t, ok)
+	require.NoError(t, second.Ping())
+
+	second.Disconnect()
+
+	// Locks of unused sockets are forgotten, including after failed connections.
+	assert.Equal(t, 0, guestAgentLockUsers(path))
+
+	_, err = ConnectGuestAgent(filepath.Join(t.TempDir(), "missing.qga"))
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrGuestAgentNotRunning))
+
+	guestAgentLocksMu.Lock()
+	assert.Empty(t, guestAgentLocks)
+	guestAgentLocksMu.Unlock()
+}
+
+func TestGuestAgentMaxResponseSize(t *testing.T) {
+	defer func(size int64) { GuestAgentMaxResponseSize = size }(GuestAgentMaxResponseSize)
+	GuestAgentMaxResponseSize = 1024
+
+	path := newTestGuestAgent(t, map[string]any{
+		"guest-ping":       map[string]any{},
+		"guest-get-osinfo": map[string]any{"id": "linux", "name": strings.Repeat("a", 4096)},
+	})
+
+	agent, err := ConnectGuestAgent(path)
+	require.NoError(t, err)
+
+	defer agent.Disconnect()
+
+	// The limit applies to each response rather than to the whole connection.
+	for i := 0; i < 100; i++ {
+		require.NoError(t, agent.Ping())
+	}
+
+	_, err = agent.GetOSInfo()
+	assert.ErrorContains(t, err, "exceeds 1024 bytes")
+
+	// Data sent without any delimiter is bounded too.
+	path = filepath.Join(t.TempDir(), "flood.qga")
+
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	defer func() { _ = listener.Close() }()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		garbage := []byte(strings.Repeat("x", 512))
+		for {
+			_, err := conn.Write(garbage)
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	_, err = ConnectGuestAgent(path)
+	assert.ErrorContains(t, err, "exceeds 1024 bytes")
+}
@@ -0,0 +1,112 @@
+package qmp
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testCommand is a command received by the fake QMP server.
+type testCommand struct {
+	Execute   string          `json:"execute"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// testServer is a fake QMP server recording the commands it receives.
+type testServer struct {
+	mu       sync.Mutex
+	commands []testCommand
+}
+
+// Commands returns the commands received so far, excluding the ones sent in the background by the monitor.
+func (s *testServer) Commands() []testCommand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]testCommand{}, s.commands...)
+}
+
+// newTestMonitor returns a monitor connected to a fake QMP server.
+// The handler returns the value of each command or an error to report to the client.
+func newTestMonitor(t *testing.T, handler func(cmd string, args json.RawMessage) (any, error)) (*Monitor, *testServer) {
+	path := filepath.Join(t.TempDir(), "qemu.monitor")
+
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	server := &testServer{}
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		encoder := json.NewEncoder(conn)
+		decoder := json.NewDecoder(conn)
+
+		err = encoder.Encode(map[string]any{"QMP": map[string]any{"version": map[string]any{}, "capabilities": []string{}}})
+		if err != nil {
+			return
+		}
+
+		for {
+			var command testCommand
+
+			err := decoder.Decode(&command)
+			if err != nil {
+				return
+			}
+
+			var value any
+			switch command.Execute {
+			case "qmp_capabilities", "query-version":
+				value = map[string]any{}
+			case "ringbuf-read":
+				value = ""
+			default:
+				server.mu.Lock()
+				server.commands = append(server.commands, command)
+				server.mu.Unlock()
+
+				value, err = handler(command.Execute, command.Arguments)
+			}
+
+			var response any
+			if err != nil {
+				response = map[string]any{"error": map[string]any{"class": "GenericError", "desc": err.Error()}}
+			} else {
+				if value == nil {
+					value = map[string]any{}
+				}
+
+				response = map[string]any{"return": value}
+			}
+
+			err = encoder.Encode(response)
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	monitor, err := Connect(path, "", nil, "")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		monitor.Disconnect()
+		_ = listener.Close()
+	})
+
+	return monitor, server
+}
+
+// errTestCommandNotFound is returned by the test handlers for unexpected commands.
+var errTestCommandNotFound = errors.New("The command has not been found")
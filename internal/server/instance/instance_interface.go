@@ -197,6 +197,7 @@ type VM interface {
 	ConsoleLog() (string, error)
 	ConsoleScreenshot(screenshotFile *os.File) error
 	DumpGuestMemory(w *os.File, format string) error
+	FreezeFilesystems() (func() error, error)
 }
 
 // CriuMigrationArgs arguments for CRIU migration.
@@ -0,0 +1,140 @@
+package instance
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// PlacementCandidates filters and orders the candidate cluster members according to the placement rules
+// (placement.* keys) of the instance's expanded config.
+//
+// Members hosting instances of an anti-affinity group are removed, as are members not hosting the existing
+// instances of each affinity group and members outside of the spread cluster groups.
+// The remaining members are then ordered so that those in the cluster group with the fewest instances of
+// the same placement group come first, otherwise preserving the existing order.
+//
+// A conflict error is returned if the rules exclude all the candidates.
+func PlacementCandidates(ctx context.Context, tx *db.ClusterTx, projectName string, instanceName string, expandedConfig map[string]string, candidates []db.NodeInfo) ([]db.NodeInfo, error) {
+	affinity := util.SplitNTrimSpace(expandedConfig["placement.affinity"], ",", -1, true)
+	antiAffinity := util.SplitNTrimSpace(expandedConfig["placement.anti_affinity"], ",", -1, true)
+	spread := util.SplitNTrimSpace(expandedConfig["placement.spread"], ",", -1, true)
+
+	if len(candidates) == 0 || (len(affinity) == 0 && len(antiAffinity) == 0 && len(spread) == 0) {
+		return candidates, nil
+	}
+
+	// Find where the other instances of the project are located, by placement group.
+	groupLocations := map[string][]string{}
+	err := tx.InstanceList(ctx, func(inst db.InstanceArgs, _ api.Project) error {
+		if inst.Name == instanceName {
+			return nil
+		}
+
+		group := db.ExpandInstanceConfig(inst.Config, inst.Profiles)["placement.group"]
+		groupLocations[group] = append(groupLocations[group], inst.Node)
+
+		return nil
+	}, cluster.InstanceFilter{Project: &projectName})
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the cluster groups of each member, only needed to spread the instances.
+	var memberGroups map[string][]string
+	if len(spread) > 0 {
+		members, err := tx.GetNodes(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		memberGroups = make(map[string][]string, len(members))
+		for _, member := range members {
+			memberGroups[member.Name] = member.Groups
+		}
+	}
+
+	return placementCandidates(expandedConfig, groupLocations, memberGroups, candidates)
+}
+
+// placementCandidates applies the placement rules to the candidates, given the members hosting the instances
+// of each placement group and the cluster groups of each member.
+func placementCandidates(expandedConfig map[string]string, groupLocations map[string][]string, memberGroups map[string][]string, candidates []db.NodeInfo) ([]db.NodeInfo, error) {
+	affinity := util.SplitNTrimSpace(expandedConfig["placement.affinity"], ",", -1, true)
+	antiAffinity := util.SplitNTrimSpace(expandedConfig["placement.anti_affinity"], ",", -1, true)
+	spread := util.SplitNTrimSpace(expandedConfig["placement.spread"], ",", -1, true)
+
+	// Filter the candidates.
+	filtered := make([]db.NodeInfo, 0, len(candidates))
+	for _, member := range candidates {
+		// Skip members hosting instances of an anti-affinity group.
+		excluded := slices.ContainsFunc(antiAffinity, func(group string) bool {
+			return slices.Contains(groupLocations[group], member.Name)
+		})
+
+		// Skip members not hosting an instance of each affinity group (when such instances exist).
+		if !excluded {
+			excluded = slices.ContainsFunc(affinity, func(group string) bool {
+				return len(groupLocations[group]) > 0 && !slices.Contains(groupLocations[group], member.Name)
+			})
+		}
+
+		// Skip members outside of the spread cluster groups.
+		if !excluded && len(spread) > 0 {
+			excluded = !slices.ContainsFunc(spread, func(group string) bool {
+				return slices.Contains(member.Groups, group)
+			})
+		}
+
+		if excluded {
+			continue
+		}
+
+		filtered = append(filtered, member)
+	}
+
+	if len(filtered) == 0 {
+		return nil, api.StatusErrorf(http.StatusConflict, "No cluster member satisfies the instance placement rules")
+	}
+
+	if len(spread) == 0 {
+		return filtered, nil
+	}
+
+	// Count the instances of the same placement group in each of the spread cluster groups.
+	spreadCounts := make(map[string]int, len(spread))
+	for _, location := range groupLocations[expandedConfig["placement.group"]] {
+		for _, group := range memberGroups[location] {
+			if slices.Contains(spread, group) {
+				spreadCounts[group]++
+			}
+		}
+	}
+
+	// Score each member using the least populated of its spread cluster groups.
+	memberScore := func(member db.NodeInfo) int {
+		score := -1
+		for _, group := range member.Groups {
+			if !slices.Contains(spread, group) {
+				continue
+			}
+
+			if score < 0 || spreadCounts[group] < score {
+				score = spreadCounts[group]
+			}
+		}
+
+		return score
+	}
+
+	slices.SortStableFunc(filtered, func(a db.NodeInfo, b db.NodeInfo) int {
+		return memberScore(a) - memberScore(b)
+	})
+
+	return filtered, nil
+}
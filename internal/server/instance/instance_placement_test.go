@@ -0,0 +1,126 @@
+package instance
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func Test_placementCandidates(t *testing.T) {
+	memberGroups := map[string][]string{
+		"m1": {"default", "rack1"},
+		"m2": {"default", "rack1"},
+		"m3": {"default", "rack2"},
+		"m4": {"default", "rack3"},
+	}
+
+	candidates := make([]db.NodeInfo, 0, len(memberGroups))
+	for _, name := range []string{"m1", "m2", "m3", "m4"} {
+		candidates = append(candidates, db.NodeInfo{Name: name, Groups: memberGroups[name]})
+	}
+
+	tests := []struct {
+		name           string
+		config         map[string]string
+		groupLocations map[string][]string
+		expected       []string
+		expectConflict bool
+	}{
+		{
+			name:           "No placement rules",
+			config:         map[string]string{"placement.group": "db"},
+			groupLocations: map[string][]string{"db": {"m1"}},
+			expected:       []string{"m1", "m2", "m3", "m4"},
+		},
+		{
+			name:           "Anti-affinity with own group",
+			config:         map[string]string{"placement.group": "db", "placement.anti_affinity": "db"},
+			groupLocations: map[string][]string{"db": {"m1", "m3"}},
+			expected:       []string{"m2", "m4"},
+		},
+		{
+			name:           "Anti-affinity with several groups",
+			config:         map[string]string{"placement.anti_affinity": "db, cache"},
+			groupLocations: map[string][]string{"db": {"m1"}, "cache": {"m4"}, "web": {"m2"}},
+			expected:       []string{"m2", "m3"},
+		},
+		{
+			name:           "Anti-affinity with empty group",
+			config:         map[string]string{"placement.anti_affinity": "db"},
+			groupLocations: map[string][]string{"web": {"m1"}},
+			expected:       []string{"m1", "m2", "m3", "m4"},
+		},
+		{
+			name:           "Anti-affinity with ungrouped instances",
+			config:         map[string]string{"placement.anti_affinity": "db"},
+			groupLocations: map[string][]string{"": {"m1", "m2"}},
+			expected:       []string{"m1", "m2", "m3", "m4"},
+		},
+		{
+			name:           "Anti-affinity excluding all members",
+			config:         map[string]string{"placement.anti_affinity": "db"},
+			groupLocations: map[string][]string{"db": {"m1", "m2", "m3", "m4"}},
+			expectConflict: true,
+		},
+		{
+			name:           "Affinity",
+			config:         map[string]string{"placement.affinity": "web"},
+			groupLocations: map[string][]string{"web": {"m2", "m3"}},
+			expected:       []string{"m2", "m3"},
+		},
+		{
+			name:           "Affinity with empty group",
+			config:         map[string]string{"placement.affinity": "web"},
+			groupLocations: map[string][]string{},
+			expected:       []string{"m1", "m2", "m3", "m4"},
+		},
+		{
+			name:           "Affinity conflicting with anti-affinity",
+			config:         map[string]string{"placement.affinity": "web", "placement.anti_affinity": "db"},
+			groupLocations: map[string][]string{"web": {"m1"}, "db": {"m1"}},
+			expectConflict: true,
+		},
+		{
+			name:           "Spread ordering",
+			config:         map[string]string{"placement.group": "db", "placement.spread": "rack1,rack2,rack3"},
+			groupLocations: map[string][]string{"db": {"m1", "m3", "m3"}, "web": {"m4", "m4"}},
+			expected:       []string{"m4", "m1", "m2", "m3"},
+		},
+		{
+			name:           "Spread outside of cluster groups",
+			config:         map[string]string{"placement.group": "db", "placement.spread": "rack2,rack3"},
+			groupLocations: map[string][]string{"db": {"m4"}},
+			expected:       []string{"m3", "m4"},
+		},
+		{
+			name:           "Spread with anti-affinity",
+			config:         map[string]string{"placement.group": "db", "placement.anti_affinity": "db", "placement.spread": "rack1,rack2,rack3"},
+			groupLocations: map[string][]string{"db": {"m1", "m4"}},
+			expected:       []string{"m3", "m2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := placementCandidates(tt.config, tt.groupLocations, memberGroups, candidates)
+			if tt.expectConflict {
+				assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+				return
+			}
+
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(result))
+			for _, member := range result {
+				names = append(names, member.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package ip
+
+import (
+	"net"
+
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// Fdb represents arguments for bridge forwarding database manipulation.
+type Fdb struct {
+	DevName string
+	MAC     net.HardwareAddr
+	Dst     net.IP
+}
+
+// args returns the common arguments identifying the entry.
+func (f *Fdb) args() []string {
+	args := []string{f.MAC.String(), "dev", f.DevName}
+	if f.Dst != nil {
+		args = append(args, "dst", f.Dst.String())
+	}
+
+	return args
+}
+
+// Append appends a forwarding database entry, allowing multiple destinations for the same MAC address.
+func (f *Fdb) Append() error {
+	_, err := subprocess.RunCommand("bridge", append(append([]string{"fdb", "append"}, f.args()...), "self", "permanent")...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Replace adds or replaces a forwarding database entry.
+func (f *Fdb) Replace() error {
+	_, err := subprocess.RunCommand("bridge", append(append([]string{"fdb", "replace"}, f.args()...), "self", "permanent")...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete deletes a forwarding database entry.
+func (f *Fdb) Delete() error {
+	_, err := subprocess.RunCommand("bridge", append(append([]string{"fdb", "del"}, f.args()...), "self")...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
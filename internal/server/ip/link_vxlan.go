@@ -3,13 +3,14 @@ package ip
 // Vxlan represents arguments for link of type vxlan.
 type Vxlan struct {
 	Link
-	VxlanID string
-	DevName string
-	Local   string
-	Remote  string
-	Group   string
-	DstPort string
-	TTL     string
+	VxlanID  string
+	DevName  string
+	Local    string
+	Remote   string
+	Group    string
+	DstPort  string
+	TTL      string
+	Learning *bool
 }
 
 // additionalArgs generates vxlan specific arguments.
@@ -40,6 +41,14 @@ func (vxlan *Vxlan) additionalArgs() []string {
 		args = append(args, "dstport", vxlan.DstPort)
 	}
 
+	if vxlan.Learning != nil {
+		if *vxlan.Learning {
+			args = append(args, "learning")
+		} else {
+			args = append(args, "nolearning")
+		}
+	}
+
 	return args
 }
 
@@ -0,0 +1,72 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// AuthGroupAction represents a lifecycle event action for authorization groups.
+type AuthGroupAction string
+
+// All supported lifecycle events for authorization groups.
+const (
+	AuthGroupCreated = AuthGroupAction(api.EventLifecycleAuthGroupCreated)
+	AuthGroupDeleted = AuthGroupAction(api.EventLifecycleAuthGroupDeleted)
+	AuthGroupRenamed = AuthGroupAction(api.EventLifecycleAuthGroupRenamed)
+	AuthGroupUpdated = AuthGroupAction(api.EventLifecycleAuthGroupUpdated)
+)
+
+// Event creates the lifecycle event for an action on an authorization group.
+func (a AuthGroupAction) Event(name string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "auth", "groups", name)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
+
+// AuthTokenAction represents a lifecycle event action for scoped API tokens.
+type AuthTokenAction string
+
+// All supported lifecycle events for scoped API tokens.
+const (
+	AuthTokenCreated = AuthTokenAction(api.EventLifecycleAuthTokenCreated)
+	AuthTokenDeleted = AuthTokenAction(api.EventLifecycleAuthTokenDeleted)
+)
+
+// Event creates the lifecycle event for an action on a scoped API token.
+func (a AuthTokenAction) Event(id string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "auth", "tokens", id)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
+
+// IdentityAction represents a lifecycle event action for identities.
+type IdentityAction string
+
+// All supported lifecycle events for identities.
+const (
+	IdentityCreated = IdentityAction(api.EventLifecycleIdentityCreated)
+	IdentityDeleted = IdentityAction(api.EventLifecycleIdentityDeleted)
+	IdentityUpdated = IdentityAction(api.EventLifecycleIdentityUpdated)
+)
+
+// Event creates the lifecycle event for an action on an identity.
+func (a IdentityAction) Event(authMethod string, identifier string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "auth", "identities", authMethod, identifier)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
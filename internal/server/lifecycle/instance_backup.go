@@ -12,6 +12,7 @@ type InstanceBackupAction string
 const (
 	InstanceBackupCreated   = InstanceBackupAction(api.EventLifecycleInstanceBackupCreated)
 	InstanceBackupDeleted   = InstanceBackupAction(api.EventLifecycleInstanceBackupDeleted)
+	InstanceBackupExported  = InstanceBackupAction(api.EventLifecycleInstanceBackupExported)
 	InstanceBackupRenamed   = InstanceBackupAction(api.EventLifecycleInstanceBackupRenamed)
 	InstanceBackupRetrieved = InstanceBackupAction(api.EventLifecycleInstanceBackupRetrieved)
 )
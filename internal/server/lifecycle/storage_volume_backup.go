@@ -12,6 +12,7 @@ type StorageVolumeBackupAction string
 const (
 	StorageVolumeBackupCreated   = StorageVolumeBackupAction(api.EventLifecycleStorageVolumeBackupCreated)
 	StorageVolumeBackupDeleted   = StorageVolumeBackupAction(api.EventLifecycleStorageVolumeBackupDeleted)
+	StorageVolumeBackupExported  = StorageVolumeBackupAction(api.EventLifecycleStorageVolumeBackupExported)
 	StorageVolumeBackupRetrieved = StorageVolumeBackupAction(api.EventLifecycleStorageVolumeBackupRetrieved)
 	StorageVolumeBackupRenamed   = StorageVolumeBackupAction(api.EventLifecycleStorageVolumeBackupRenamed)
 )
@@ -19,6 +19,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/lxc/incus/v6/internal/server/audit"
 	"github.com/lxc/incus/v6/shared/api"
 	localtls "github.com/lxc/incus/v6/shared/tls"
 )
@@ -380,6 +381,84 @@ func (c *Client) HandleEvent(event api.Event) {
 	c.entries <- entry
 }
 
+// HandleAuditRecord handles the records received from the audit logger.
+func (c *Client) HandleAuditRecord(record audit.Record) {
+	if !slices.Contains(c.cfg.types, "audit") {
+		return
+	}
+
+	// Support overriding the location field (used on standalone systems).
+	location := record.Location
+	if c.cfg.location != "" {
+		location = c.cfg.location
+	}
+
+	entry := entry{
+		labels: LabelSet{
+			"app":      "incus",
+			"type":     "audit",
+			"location": location,
+			"instance": c.cfg.instance,
+		},
+		Entry: Entry{
+			Timestamp: record.Timestamp,
+		},
+	}
+
+	if record.Project != "" {
+		entry.labels["project"] = record.Project
+	}
+
+	context := map[string]string{
+		"address":     record.Address,
+		"body-digest": record.BodyDigest,
+		"outcome":     record.Outcome,
+		"protocol":    record.Protocol,
+		"status-code": strconv.Itoa(record.StatusCode),
+		"username":    record.Username,
+	}
+
+	if record.Operation != "" {
+		context["operation"] = record.Operation
+	}
+
+	keys := make([]string, 0, len(context))
+
+	for k := range context {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var message strings.Builder
+
+	// Add key-value pairs as labels but don't override any labels, the rest is the message prefix.
+	for _, k := range keys {
+		v := context[k]
+
+		if slices.Contains(c.cfg.labels, k) {
+			_, ok := entry.labels[k]
+			if !ok {
+				// Label names may not contain any hyphens.
+				entry.labels[strings.ReplaceAll(k, "-", "_")] = v
+				continue
+			}
+		}
+
+		if v == "" {
+			continue
+		}
+
+		message.WriteString(fmt.Sprintf("%s=%q ", k, v))
+	}
+
+	message.WriteString(fmt.Sprintf("%s %s", record.Method, record.URL))
+
+	entry.Line = message.String()
+
+	c.entries <- entry
+}
+
 func buildNestedContext(prefix string, m map[string]any) map[string]string {
 	labels := map[string]string{}
 
@@ -86,11 +86,10 @@ func (m *MetricSet) Merge(metricSet *MetricSet) {
 	}
 }
 
-func (m *MetricSet) String() string {
-	var out strings.Builder
-	metricTypes := []MetricType{}
+// Types returns the types of the metrics in the MetricSet, sorted by their numeric code.
+func (m *MetricSet) Types() []MetricType {
+	metricTypes := make([]MetricType, 0, len(m.set))
 
-	// Sort output by metric type name
 	for metricType := range m.set {
 		metricTypes = append(metricTypes, metricType)
 	}
@@ -99,26 +98,42 @@ func (m *MetricSet) String() string {
 		return int(metricTypes[i]) < int(metricTypes[j])
 	})
 
-	for _, metricType := range metricTypes {
+	return metricTypes
+}
+
+// Samples returns the samples of the type metricType.
+func (m *MetricSet) Samples(metricType MetricType) []Sample {
+	return m.set[metricType]
+}
+
+// Kind returns the OpenMetrics type of the metric, either "gauge" or "counter".
+func (t MetricType) Kind() string {
+	// ProcsTotal is a gauge according to the OpenMetrics spec as its value can decrease.
+	if t == ProcsTotal || t == CPUs || t == GoGoroutines || t == GoHeapObjects {
+		return "gauge"
+	} else if t == ProjectLimit || t == ProjectUsage || t == StoragePoolInodes || t == StoragePoolInodesUsed || t == NetworkLeases {
+		return "gauge"
+	} else if strings.HasSuffix(MetricNames[t], "_total") || strings.HasSuffix(MetricNames[t], "_seconds") {
+		return "counter"
+	} else if strings.HasSuffix(MetricNames[t], "_bytes") {
+		return "gauge"
+	}
+
+	return ""
+}
+
+func (m *MetricSet) String() string {
+	var out strings.Builder
+
+	for _, metricType := range m.Types() {
 		// Add HELP message as specified by OpenMetrics
 		_, err := out.WriteString(MetricHeaders[metricType] + "\n")
 		if err != nil {
 			return ""
 		}
 
-		metricTypeName := ""
-
-		// ProcsTotal is a gauge according to the OpenMetrics spec as its value can decrease.
-		if metricType == ProcsTotal || metricType == CPUs || metricType == GoGoroutines || metricType == GoHeapObjects {
-			metricTypeName = "gauge"
-		} else if strings.HasSuffix(MetricNames[metricType], "_total") || strings.HasSuffix(MetricNames[metricType], "_seconds") {
-			metricTypeName = "counter"
-		} else if strings.HasSuffix(MetricNames[metricType], "_bytes") {
-			metricTypeName = "gauge"
-		}
-
 		// Add TYPE message as specified by OpenMetrics
-		_, err = out.WriteString(fmt.Sprintf("# TYPE %s %s\n", MetricNames[metricType], metricTypeName))
+		_, err = out.WriteString(fmt.Sprintf("# TYPE %s %s\n", MetricNames[metricType], metricType.Kind()))
 		if err != nil {
 			return ""
 		}
@@ -56,3 +56,38 @@ func TestMetricSet_FilterSamples(t *testing.T) {
 		require.Contains(t, hasKeys, "project")
 	}
 }
+
+func TestMetricType_Kind(t *testing.T) {
+	require.Equal(t, "counter", CPUSecondsTotal.Kind())
+	require.Equal(t, "gauge", MemoryMemFreeBytes.Kind())
+	require.Equal(t, "gauge", ProcsTotal.Kind())
+	require.Equal(t, "gauge", ProjectUsage.Kind())
+	require.Equal(t, "gauge", StoragePoolInodesUsed.Kind())
+	require.Equal(t, "gauge", StorageVolumeUsedBytes.Kind())
+	require.Equal(t, "gauge", NetworkLeases.Kind())
+}
+
+func TestMetricSet_FilterSamples_resources(t *testing.T) {
+	m := NewMetricSet(nil)
+	m.AddSamples(ProjectUsage, Sample{Labels: map[string]string{"project": "default", "resource": "instances"}, Value: 2})
+	m.AddSamples(StoragePoolSpaceBytes, Sample{Labels: map[string]string{"pool": "default", "driver": "zfs"}, Value: 1000})
+	m.AddSamples(StorageVolumeUsedBytes, Sample{Labels: map[string]string{"project": "default", "pool": "default", "type": "custom", "volume": "vol1"}, Value: 10})
+
+	// Resource metrics aren't tied to an instance so require access to the server.
+	m.FilterSamples(func(object auth.Object) bool {
+		return object == auth.ObjectInstance("default", "jammy")
+	})
+
+	for _, metricType := range m.Types() {
+		require.Empty(t, m.Samples(metricType))
+	}
+
+	m = NewMetricSet(nil)
+	m.AddSamples(ProjectUsage, Sample{Labels: map[string]string{"project": "default", "resource": "instances"}, Value: 2})
+
+	m.FilterSamples(func(object auth.Object) bool {
+		return object == auth.ObjectServer()
+	})
+
+	require.Equal(t, []Sample{{Labels: map[string]string{"project": "default", "resource": "instances"}, Value: 2}}, m.Samples(ProjectUsage))
+}
@@ -144,6 +144,24 @@ const (
 	GoOtherSysBytes
 	// GoNextGCBytes represents the number of heap bytes when next garbage collection will take place.
 	GoNextGCBytes
+	// ProjectLimit represents the limit set on a project resource.
+	ProjectLimit
+	// ProjectUsage represents the current usage of a project resource.
+	ProjectUsage
+	// StoragePoolSpaceBytes represents the total space of a storage pool.
+	StoragePoolSpaceBytes
+	// StoragePoolSpaceUsedBytes represents the used space of a storage pool.
+	StoragePoolSpaceUsedBytes
+	// StoragePoolInodes represents the total number of inodes of a storage pool.
+	StoragePoolInodes
+	// StoragePoolInodesUsed represents the number of used inodes of a storage pool.
+	StoragePoolInodesUsed
+	// StorageVolumeSizeBytes represents the size of a storage volume.
+	StorageVolumeSizeBytes
+	// StorageVolumeUsedBytes represents the used space of a storage volume.
+	StorageVolumeUsedBytes
+	// NetworkLeases represents the number of leases of a network.
+	NetworkLeases
 )
 
 // MetricNames associates a metric type to its name.
@@ -208,8 +226,17 @@ var MetricNames = map[MetricType]string{
 	NetworkTransmitDropTotal:    "incus_network_transmit_drop_total",
 	NetworkTransmitErrsTotal:    "incus_network_transmit_errs_total",
 	NetworkTransmitPacketsTotal: "incus_network_transmit_packets_total",
+	NetworkLeases:               "incus_network_leases",
 	OperationsTotal:             "incus_operations_total",
 	ProcsTotal:                  "incus_procs_total",
+	ProjectLimit:                "incus_project_limit",
+	ProjectUsage:                "incus_project_usage",
+	StoragePoolInodes:           "incus_storage_pool_inodes",
+	StoragePoolInodesUsed:       "incus_storage_pool_inodes_used",
+	StoragePoolSpaceBytes:       "incus_storage_pool_space_bytes",
+	StoragePoolSpaceUsedBytes:   "incus_storage_pool_space_used_bytes",
+	StorageVolumeSizeBytes:      "incus_storage_volume_size_bytes",
+	StorageVolumeUsedBytes:      "incus_storage_volume_used_bytes",
 	UptimeSeconds:               "incus_uptime_seconds",
 	WarningsTotal:               "incus_warnings_total",
 }
@@ -276,8 +303,17 @@ var MetricHeaders = map[MetricType]string{
 	NetworkTransmitDropTotal:    "# HELP incus_network_transmit_drop_total The amount of transmitted dropped bytes on a given interface.",
 	NetworkTransmitErrsTotal:    "# HELP incus_network_transmit_errs_total The amount of transmitted errors on a given interface.",
 	NetworkTransmitPacketsTotal: "# HELP incus_network_transmit_packets_total The amount of transmitted packets on a given interface.",
+	NetworkLeases:               "# HELP incus_network_leases The number of leases of a network.",
 	OperationsTotal:             "# HELP incus_operations_total The number of running operations",
 	ProcsTotal:                  "# HELP incus_procs_total The number of running processes.",
+	ProjectLimit:                "# HELP incus_project_limit The limit set on a project resource.",
+	ProjectUsage:                "# HELP incus_project_usage The current usage of a project resource.",
+	StoragePoolInodes:           "# HELP incus_storage_pool_inodes The total number of inodes of a storage pool.",
+	StoragePoolInodesUsed:       "# HELP incus_storage_pool_inodes_used The number of used inodes of a storage pool.",
+	StoragePoolSpaceBytes:       "# HELP incus_storage_pool_space_bytes The total space of a storage pool in bytes.",
+	StoragePoolSpaceUsedBytes:   "# HELP incus_storage_pool_space_used_bytes The used space of a storage pool in bytes.",
+	StorageVolumeSizeBytes:      "# HELP incus_storage_volume_size_bytes The size of a storage volume in bytes.",
+	StorageVolumeUsedBytes:      "# HELP incus_storage_volume_used_bytes The used space of a storage volume in bytes.",
 	UptimeSeconds:               "# HELP incus_uptime_seconds The daemon uptime in seconds.",
 	WarningsTotal:               "# HELP incus_warnings_total The number of active warnings.",
 }
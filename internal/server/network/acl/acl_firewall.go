@@ -2,10 +2,14 @@ package acl
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"strings"
 
 	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/dnsmasq"
 	firewallDrivers "github.com/lxc/incus/v6/internal/server/firewall/drivers"
+	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
@@ -22,13 +26,236 @@ func FirewallApplyACLRules(s *state.State, logger logger.Logger, aclProjectName
 	return s.Firewall.NetworkApplyACLRules(aclNet.Name, rules)
 }
 
+// FirewallAddressSetName returns the firewall set name for a network address set ID.
+func FirewallAddressSetName(networkAddressSetID int64) string {
+	return fmt.Sprintf("addrset_%d", networkAddressSetID)
+}
+
+// FirewallAddressSetsSupported checks that address set subjects can be matched on this server.
+// This requires the nftables firewall driver.
+func FirewallAddressSetsSupported(s *state.State) error {
+	if s.Firewall.String() != "nftables" {
+		return fmt.Errorf("Network address sets require the nftables firewall driver")
+	}
+
+	return nil
+}
+
+// FirewallDomainSetTimeout is the number of seconds after which the addresses resolved for a domain name subject
+// expire from its firewall set. The TTL of the DNS records handed out by dnsmasq is capped to it accordingly.
+const FirewallDomainSetTimeout = 600
+
+// FirewallDomainSetsSupported checks that domain name subjects can be matched on this server.
+// This requires the nftables firewall driver and dnsmasq 2.87 or later to populate the sets.
+func FirewallDomainSetsSupported(s *state.State) error {
+	if s.Firewall.String() != "nftables" {
+		return fmt.Errorf("Domain name subjects require the nftables firewall driver")
+	}
+
+	dnsmasqVersion, err := dnsmasq.GetVersion()
+	if err != nil {
+		return fmt.Errorf("Domain name subjects require dnsmasq: %w", err)
+	}
+
+	if !dnsmasq.NftSetsSupported(dnsmasqVersion) {
+		return fmt.Errorf("Domain name subjects require dnsmasq 2.87 or later (found %s)", dnsmasqVersion)
+	}
+
+	return nil
+}
+
+// FirewallDomainSetName returns the firewall set name for a domain name subject.
+// The name is derived from the domain so that the set can be shared by all ACLs and networks.
+func FirewallDomainSetName(domain string) string {
+	return fmt.Sprintf("dns_%x", sha256.Sum256([]byte(domainSubjectName(domain))))[:20]
+}
+
+// FirewallDomainSets returns the domain names referenced in the egress rules of the project's ACLs,
+// mapped to the name of the firewall set that dnsmasq populates with their resolved addresses.
+func FirewallDomainSets(s *state.State, aclProjectName string) (map[string]string, error) {
+	domainSets := map[string]string{}
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		aclNames, err := tx.GetNetworkACLs(ctx, aclProjectName)
+		if err != nil {
+			return err
+		}
+
+		for _, aclName := range aclNames {
+			_, aclInfo, err := tx.GetNetworkACL(ctx, aclProjectName, aclName)
+			if err != nil {
+				return err
+			}
+
+			for _, rule := range aclInfo.Egress {
+				for _, subject := range util.SplitNTrimSpace(rule.Destination, ",", -1, true) {
+					if isDomainSubject(subject) {
+						domainSets[domainSubjectName(subject)] = FirewallDomainSetName(subject)
+					}
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainSets, nil
+}
+
+// FirewallApplyDomainSets creates the firewall sets for the provided domain name sets, leaving their content untouched.
+func FirewallApplyDomainSets(s *state.State, domainSets map[string]string) error {
+	if len(domainSets) == 0 {
+		return nil
+	}
+
+	sets := make([]firewallDrivers.AddressSet, 0, len(domainSets))
+	for _, setName := range domainSets {
+		sets = append(sets, firewallDrivers.AddressSet{Name: setName, Dynamic: true, Timeout: FirewallDomainSetTimeout})
+	}
+
+	err := s.Firewall.NetworkApplyAddressSets(sets)
+	if err != nil {
+		return fmt.Errorf("Failed applying domain name sets: %w", err)
+	}
+
+	return nil
+}
+
+// FirewallRefreshDomainSets creates the firewall sets for the domain names referenced by the project's ACLs
+// and reconfigures the dnsmasq instances of the project's bridge networks running on this member to populate them.
+func FirewallRefreshDomainSets(s *state.State, aclProjectName string) error {
+	domainSets, err := FirewallDomainSets(s, aclProjectName)
+	if err != nil {
+		return err
+	}
+
+	err = FirewallApplyDomainSets(s, domainSets)
+	if err != nil {
+		return err
+	}
+
+	var bridgeNames []string
+
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkNames, err := tx.GetCreatedNetworkNamesByProject(ctx, aclProjectName)
+		if err != nil && !response.IsNotFoundError(err) {
+			return fmt.Errorf("Failed loading networks for project %q: %w", aclProjectName, err)
+		}
+
+		for _, networkName := range networkNames {
+			_, network, _, err := tx.GetNetworkInAnyState(ctx, aclProjectName, networkName)
+			if err != nil {
+				return fmt.Errorf("Failed to get network config for %q: %w", networkName, err)
+			}
+
+			if network.Type == "bridge" {
+				bridgeNames = append(bridgeNames, networkName)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	dnsmasq.ConfigMutex.Lock()
+	defer dnsmasq.ConfigMutex.Unlock()
+
+	for _, bridgeName := range bridgeNames {
+		// Skip networks whose dnsmasq isn't configured for domain name sets on this member.
+		if !util.PathExists(dnsmasq.NftSetsPath(bridgeName)) {
+			continue
+		}
+
+		changed, err := dnsmasq.UpdateNftSets(bridgeName, domainSets, FirewallDomainSetTimeout)
+		if err != nil {
+			return fmt.Errorf("Failed updating dnsmasq domain name sets for network %q: %w", bridgeName, err)
+		}
+
+		if changed {
+			err = dnsmasq.Restart(bridgeName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // FirewallACLRules returns ACL rules for network firewall.
+// Any address sets referenced by the rules are also created or refreshed in the firewall.
+// Domain name subjects are matched through sets populated by dnsmasq with the resolved addresses.
 func FirewallACLRules(s *state.State, aclDeviceName string, aclProjectName string, config map[string]string) ([]firewallDrivers.ACLRule, error) {
 	var dropRules []firewallDrivers.ACLRule
 	var rejectRules []firewallDrivers.ACLRule
 	var allowRules []firewallDrivers.ACLRule
 	var allowStatelessRules []firewallDrivers.ACLRule
 
+	addressSets := map[string]firewallDrivers.AddressSet{}
+	hasDomains := false
+
+	// splitSubjects splits the subjects into groups that can each be matched by a single firewall rule.
+	// Literal addresses are kept together while each referenced address set gets its own group.
+	splitSubjects := func(subjects string) ([]string, error) {
+		if subjects == "" {
+			return []string{""}, nil
+		}
+
+		literals := []string{}
+		groups := []string{}
+
+		for _, subject := range util.SplitNTrimSpace(subjects, ",", -1, false) {
+			if isDomainSubject(subject) {
+				hasDomains = true
+				groups = append(groups, ruleSubjectAddressSetPrefix+FirewallDomainSetName(subject))
+				continue
+			}
+
+			addressSetName, isAddressSet := strings.CutPrefix(subject, ruleSubjectAddressSetPrefix)
+			if !isAddressSet {
+				literals = append(literals, subject)
+				continue
+			}
+
+			addressSet, found := addressSets[addressSetName]
+			if !found {
+				var addressSetID int64
+				var addressSetInfo *api.NetworkAddressSet
+
+				err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+					var err error
+
+					addressSetID, addressSetInfo, err = tx.GetNetworkAddressSet(ctx, aclProjectName, addressSetName)
+
+					return err
+				})
+				if err != nil {
+					return nil, fmt.Errorf("Failed loading address set %q: %w", addressSetName, err)
+				}
+
+				addressSet = firewallDrivers.AddressSet{
+					Name:      FirewallAddressSetName(addressSetID),
+					Addresses: addressSetInfo.Addresses,
+				}
+
+				addressSets[addressSetName] = addressSet
+			}
+
+			groups = append(groups, ruleSubjectAddressSetPrefix+addressSet.Name)
+		}
+
+		if len(literals) > 0 {
+			groups = append([]string{strings.Join(literals, ",")}, groups...)
+		}
+
+		return groups, nil
+	}
+
 	// convertACLRules converts the ACL rules to Firewall ACL rules.
 	convertACLRules := func(direction string, logPrefix string, rules ...api.NetworkACLRule) error {
 		for ruleIndex, rule := range rules {
@@ -39,13 +266,14 @@ func FirewallACLRules(s *state.State, aclDeviceName string, aclProjectName strin
 			firewallACLRule := firewallDrivers.ACLRule{
 				Direction:       direction,
 				Action:          rule.Action,
-				Source:          rule.Source,
-				Destination:     rule.Destination,
 				Protocol:        rule.Protocol,
 				SourcePort:      rule.SourcePort,
 				DestinationPort: rule.DestinationPort,
 				ICMPType:        rule.ICMPType,
 				ICMPCode:        rule.ICMPCode,
+				RateLimit:       rule.RateLimit,
+				ConnectionRate:  rule.ConnectionRate,
+				ConnectionLimit: rule.ConnectionLimit,
 			}
 
 			if rule.State == "logged" {
@@ -54,17 +282,36 @@ func FirewallACLRules(s *state.State, aclDeviceName string, aclProjectName strin
 				firewallACLRule.LogName = fmt.Sprintf("%s-%s-%d", logPrefix, direction, ruleIndex)
 			}
 
-			switch {
-			case rule.Action == "drop":
-				dropRules = append(dropRules, firewallACLRule)
-			case rule.Action == "reject":
-				rejectRules = append(rejectRules, firewallACLRule)
-			case rule.Action == "allow":
-				allowRules = append(allowRules, firewallACLRule)
-			case rule.Action == "allow-stateless": // TODO: add NOTRACK support
-				allowStatelessRules = append(allowStatelessRules, firewallACLRule)
-			default:
-				return fmt.Errorf("Unrecognised action %q", rule.Action)
+			// Address sets can't be combined with other subjects in a single firewall rule,
+			// so generate a rule for each combination of source and destination groups.
+			sources, err := splitSubjects(rule.Source)
+			if err != nil {
+				return err
+			}
+
+			destinations, err := splitSubjects(rule.Destination)
+			if err != nil {
+				return err
+			}
+
+			for _, source := range sources {
+				for _, destination := range destinations {
+					firewallACLRule.Source = source
+					firewallACLRule.Destination = destination
+
+					switch {
+					case rule.Action == "drop":
+						dropRules = append(dropRules, firewallACLRule)
+					case rule.Action == "reject":
+						rejectRules = append(rejectRules, firewallACLRule)
+					case rule.Action == "allow":
+						allowRules = append(allowRules, firewallACLRule)
+					case rule.Action == "allow-stateless": // TODO: add NOTRACK support
+						allowStatelessRules = append(allowStatelessRules, firewallACLRule)
+					default:
+						return fmt.Errorf("Unrecognised action %q", rule.Action)
+					}
+				}
 			}
 		}
 
@@ -99,6 +346,27 @@ func FirewallACLRules(s *state.State, aclDeviceName string, aclProjectName strin
 		}
 	}
 
+	// Make sure the referenced address sets exist before the rules using them get applied.
+	if len(addressSets) > 0 {
+		sets := make([]firewallDrivers.AddressSet, 0, len(addressSets))
+		for _, addressSet := range addressSets {
+			sets = append(sets, addressSet)
+		}
+
+		err := s.Firewall.NetworkApplyAddressSets(sets)
+		if err != nil {
+			return nil, fmt.Errorf("Failed applying address sets for network %q: %w", aclDeviceName, err)
+		}
+	}
+
+	// Make sure the domain name sets exist and get populated by dnsmasq.
+	if hasDomains {
+		err := FirewallRefreshDomainSets(s, aclProjectName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var rules []firewallDrivers.ACLRule
 	rules = append(rules, dropRules...)
 	rules = append(rules, rejectRules...)
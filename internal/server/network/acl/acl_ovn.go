@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net"
 	"slices"
 	"strings"
@@ -33,6 +34,10 @@ const ovnACLPriorityPortGroupAllow = 300
 const ovnACLPriorityPortGroupReject = 400
 const ovnACLPriorityPortGroupDrop = 500
 
+// ovnQoSPriorityPortGroupLimit is the priority of the QoS rules enforcing the ACL rule rate limits.
+// OVN only applies the highest priority matching QoS rule, so traffic matching several limits is limited once.
+const ovnQoSPriorityPortGroupLimit = 100
+
 // ovnACLPortGroupPrefix prefix used when naming ACL related port groups in OVN.
 const ovnACLPortGroupPrefix = "incus_acl"
 
@@ -50,6 +55,11 @@ func OVNACLNetworkPortGroupName(networkACLID int64, networkID int64) ovn.OVNPort
 	return ovn.OVNPortGroup(fmt.Sprintf("%s%d_net%d", ovnACLPortGroupPrefix, networkACLID, networkID))
 }
 
+// OVNAddressSetPrefix returns the address set prefix for a network address set ID.
+func OVNAddressSetPrefix(networkAddressSetID int64) ovn.OVNAddressSet {
+	return ovn.OVNAddressSet(fmt.Sprintf("incus_set%d", networkAddressSetID))
+}
+
 // OVNIntSwitchPortGroupName returns the port group name for a Network ID.
 func OVNIntSwitchPortGroupName(networkID int64) ovn.OVNPortGroup {
 	return ovn.OVNPortGroup(fmt.Sprintf("incus_net%d", networkID))
@@ -88,12 +98,19 @@ func OVNEnsureACLs(s *state.State, l logger.Logger, client *ovn.NB, aclProjectNa
 
 	var err error
 	var projectID int64
+	var addressSetIDs map[string]int64
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		projectID, err = cluster.GetProjectID(ctx, tx.Tx(), aclProjectName)
 		if err != nil {
 			return fmt.Errorf("Failed getting project ID for project %q: %w", aclProjectName, err)
 		}
 
+		// Get map of address set names to DB IDs (used for generating OVN address set names).
+		addressSetIDs, err = tx.GetNetworkAddressSetIDsByNames(ctx, aclProjectName)
+		if err != nil {
+			return fmt.Errorf("Failed getting address sets for project %q: %w", aclProjectName, err)
+		}
+
 		return err
 	})
 	if err != nil {
@@ -207,6 +224,26 @@ func OVNEnsureACLs(s *state.State, l logger.Logger, client *ovn.NB, aclProjectNa
 		delete(referencedACLs, aclStatus.name)
 	}
 
+	// Build a list of address sets referenced by the rules we are going to apply and make sure they exist
+	// with their current content, so the rules referencing them can be matched.
+	referencedAddressSets := make(map[string]struct{}, 0)
+	for _, aclStatus := range createACLPortGroups {
+		ovnAddReferencedAddressSets(aclStatus.aclInfo, referencedAddressSets)
+	}
+
+	for _, aclStatus := range existingACLPortGroups {
+		if aclStatus.aclInfo != nil {
+			ovnAddReferencedAddressSets(aclStatus.aclInfo, referencedAddressSets)
+		}
+	}
+
+	for addressSetName := range referencedAddressSets {
+		err := OVNEnsureAddressSet(s, client, aclProjectName, addressSetName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Create any missing port groups for the referenced ACLs before creating the requested ACL port groups.
 	// This way the referenced port groups will exist for any rules that referenced them in the creation ACLs.
 	// Note: We only create the empty port group, we do not add the ACL rules, so it is expected that any
@@ -259,7 +296,7 @@ func OVNEnsureACLs(s *state.State, l logger.Logger, client *ovn.NB, aclProjectNa
 		}
 
 		// Now apply our ACL rules to port group (and any per-ACL-per-network port groups needed).
-		err = ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, aclNets, peerTargetNetIDs)
+		err = ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, addressSetIDs, aclNets, peerTargetNetIDs)
 		if err != nil {
 			return nil, fmt.Errorf("Failed applying ACL rules to port group %q for security ACL %q setup: %w", portGroupName, aclStatus.name, err)
 		}
@@ -289,7 +326,7 @@ func OVNEnsureACLs(s *state.State, l logger.Logger, client *ovn.NB, aclProjectNa
 		if aclStatus.aclInfo != nil {
 			l.Debug("Applying ACL rules to OVN port group", logger.Ctx{"networkACL": aclStatus.name, "portGroup": portGroupName})
 
-			err := ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, aclNets, peerTargetNetIDs)
+			err := ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, addressSetIDs, aclNets, peerTargetNetIDs)
 			if err != nil {
 				return nil, fmt.Errorf("Failed applying ACL rules to port group %q for security ACL %q setup: %w", portGroupName, aclStatus.name, err)
 			}
@@ -314,6 +351,10 @@ func ovnAddReferencedACLs(info *api.NetworkACL, referencedACLNames map[string]st
 				continue // Skip special reserved subjects that are not ACL names.
 			}
 
+			if strings.HasPrefix(subject, ruleSubjectAddressSetPrefix) {
+				continue // Skip address set references.
+			}
+
 			if validate.IsNetworkAddressCIDR(subject) == nil || validate.IsNetworkRange(subject) == nil {
 				continue // Skip if the subject is an IP CIDR or IP range.
 			}
@@ -333,11 +374,73 @@ func ovnAddReferencedACLs(info *api.NetworkACL, referencedACLNames map[string]st
 	}
 }
 
+// ovnAddReferencedAddressSets adds to the referencedAddressSetNames any address sets referenced by the rules in the supplied ACL.
+func ovnAddReferencedAddressSets(info *api.NetworkACL, referencedAddressSetNames map[string]struct{}) {
+	addAddressSetNamesFrom := func(ruleSubjects []string) {
+		for _, subject := range ruleSubjects {
+			addressSetName, isAddressSet := strings.CutPrefix(subject, ruleSubjectAddressSetPrefix)
+			if isAddressSet {
+				referencedAddressSetNames[addressSetName] = struct{}{}
+			}
+		}
+	}
+
+	for _, rules := range [][]api.NetworkACLRule{info.Ingress, info.Egress} {
+		for _, rule := range rules {
+			addAddressSetNamesFrom(util.SplitNTrimSpace(rule.Source, ",", -1, true))
+			addAddressSetNamesFrom(util.SplitNTrimSpace(rule.Destination, ",", -1, true))
+		}
+	}
+}
+
+// OVNEnsureAddressSet creates or refreshes the OVN address sets for the specified network address set.
+func OVNEnsureAddressSet(s *state.State, client *ovn.NB, projectName string, addressSetName string) error {
+	var addressSetID int64
+	var addressSetInfo *api.NetworkAddressSet
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		addressSetID, addressSetInfo, err = tx.GetNetworkAddressSet(ctx, projectName, addressSetName)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading address set %q: %w", addressSetName, err)
+	}
+
+	addresses := make([]net.IPNet, 0, len(addressSetInfo.Addresses))
+	for _, address := range addressSetInfo.Addresses {
+		_, subnet, err := net.ParseCIDR(address)
+		if err != nil {
+			ip := net.ParseIP(address)
+			if ip == nil {
+				return fmt.Errorf("Invalid address %q in address set %q", address, addressSetName)
+			}
+
+			subnet = &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+			if ip.To4() != nil {
+				subnet = &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}
+			}
+		}
+
+		addresses = append(addresses, *subnet)
+	}
+
+	err = client.UpdateAddressSet(context.TODO(), OVNAddressSetPrefix(addressSetID), addresses...)
+	if err != nil {
+		return fmt.Errorf("Failed applying OVN address set for %q: %w", addressSetName, err)
+	}
+
+	return nil
+}
+
 // ovnApplyToPortGroup applies the rules in the specified ACL to the specified port group.
-func ovnApplyToPortGroup(l logger.Logger, client *ovn.NB, aclInfo *api.NetworkACL, portGroupName ovn.OVNPortGroup, aclNameIDs map[string]int64, aclNets map[string]NetworkACLUsage, peerTargetNetIDs map[db.NetworkPeer]int64) error {
+func ovnApplyToPortGroup(l logger.Logger, client *ovn.NB, aclInfo *api.NetworkACL, portGroupName ovn.OVNPortGroup, aclNameIDs map[string]int64, addressSetIDs map[string]int64, aclNets map[string]NetworkACLUsage, peerTargetNetIDs map[db.NetworkPeer]int64) error {
 	// Create slice for port group rules that has the capacity for ingress and egress rules, plus default rule.
 	portGroupRules := make([]ovn.OVNACLRule, 0, len(aclInfo.Ingress)+len(aclInfo.Egress)+1)
 	networkRules := make([]ovn.OVNACLRule, 0)
+	qosRules := make([]ovn.OVNQoSRule, 0)
 	networkPeersNeeded := make([]db.NetworkPeer, 0)
 
 	// convertACLRules converts the ACL rules to OVN ACL rules.
@@ -347,11 +450,28 @@ func ovnApplyToPortGroup(l logger.Logger, client *ovn.NB, aclInfo *api.NetworkAC
 				continue
 			}
 
-			ovnACLRule, networkSpecific, networkPeers, err := ovnRuleCriteriaToOVNACLRule(direction, &rule, portGroupName, aclNameIDs, peerTargetNetIDs)
+			ovnACLRule, networkSpecific, networkPeers, err := ovnRuleCriteriaToOVNACLRule(direction, &rule, portGroupName, aclNameIDs, addressSetIDs, peerTargetNetIDs)
 			if err != nil {
 				return err
 			}
 
+			networkPeersNeeded = append(networkPeersNeeded, networkPeers...)
+
+			// Rate limits are enforced by QoS rules on each network's logical switch.
+			if rule.RateLimit != "" {
+				qosRule, err := ovnRateLimitToOVNQoSRule(ovnACLRule, rule.RateLimit)
+				if err != nil {
+					return err
+				}
+
+				qosRules = append(qosRules, qosRule)
+
+				// Drop and reject rules only apply to the traffic exceeding the limit, which the QoS rule drops.
+				if rule.Action != "allow" {
+					continue
+				}
+			}
+
 			if rule.State == "logged" {
 				ovnACLRule.Log = true
 				ovnACLRule.LogName = fmt.Sprintf("%s-%s-%d", portGroupName, direction, ruleIndex)
@@ -362,8 +482,6 @@ func ovnApplyToPortGroup(l logger.Logger, client *ovn.NB, aclInfo *api.NetworkAC
 			} else {
 				portGroupRules = append(portGroupRules, ovnACLRule)
 			}
-
-			networkPeersNeeded = append(networkPeersNeeded, networkPeers...)
 		}
 
 		return nil
@@ -423,6 +541,12 @@ func ovnApplyToPortGroup(l logger.Logger, client *ovn.NB, aclInfo *api.NetworkAC
 		if err != nil {
 			return fmt.Errorf("Failed applying ACL %q rules to port group %q for network %q: %w", aclInfo.Name, netPortGroupName, aclNet.Name, err)
 		}
+
+		// Tie the QoS rules to the network specific port group so they get removed alongside it.
+		err = client.UpdateLogicalSwitchQoSRules(context.TODO(), OVNIntSwitchName(aclNet.ID), netPortGroupName, matchReplace, qosRules...)
+		if err != nil {
+			return fmt.Errorf("Failed applying ACL %q rate limits to network %q: %w", aclInfo.Name, aclNet.Name, err)
+		}
 	}
 
 	return nil
@@ -430,7 +554,19 @@ func ovnApplyToPortGroup(l logger.Logger, client *ovn.NB, aclInfo *api.NetworkAC
 
 // ovnRuleCriteriaToOVNACLRule converts an ACL rule into an OVNACLRule for an OVN port group or network.
 // Returns a bool indicating if any of the rule subjects are network specific.
-func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, portGroupName ovn.OVNPortGroup, aclNameIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64) (ovn.OVNACLRule, bool, []db.NetworkPeer, error) {
+func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, portGroupName ovn.OVNPortGroup, aclNameIDs map[string]int64, addressSetIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64) (ovn.OVNACLRule, bool, []db.NetworkPeer, error) {
+	// OVN enforces bandwidth limits using QoS meters but doesn't have packet rate or connection tracking limits.
+	if rule.ConnectionRate != "" || rule.ConnectionLimit != "" {
+		return ovn.OVNACLRule{}, false, nil, fmt.Errorf("ACL rule connection limits aren't supported on OVN networks")
+	}
+
+	if rule.RateLimit != "" {
+		_, _, err := parseRateBandwidth(rule.RateLimit)
+		if err != nil {
+			return ovn.OVNACLRule{}, false, nil, fmt.Errorf("Only bandwidth rate limits (such as 10Mbit/second) are supported on OVN networks: %w", err)
+		}
+	}
+
 	networkSpecific := false
 	networkPeersNeeded := make([]db.NetworkPeer, 0)
 	portGroupRule := ovn.OVNACLRule{
@@ -467,7 +603,7 @@ func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, por
 
 	// Add subject filters.
 	if rule.Source != "" {
-		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("src", aclNameIDs, peerTargetNetIDs, util.SplitNTrimSpace(rule.Source, ",", -1, false)...)
+		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("src", aclNameIDs, addressSetIDs, peerTargetNetIDs, util.SplitNTrimSpace(rule.Source, ",", -1, false)...)
 		if err != nil {
 			return ovn.OVNACLRule{}, false, nil, err
 		}
@@ -481,7 +617,7 @@ func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, por
 	}
 
 	if rule.Destination != "" {
-		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("dst", aclNameIDs, peerTargetNetIDs, util.SplitNTrimSpace(rule.Destination, ",", -1, false)...)
+		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("dst", aclNameIDs, addressSetIDs, peerTargetNetIDs, util.SplitNTrimSpace(rule.Destination, ",", -1, false)...)
 		if err != nil {
 			return ovn.OVNACLRule{}, false, nil, err
 		}
@@ -523,6 +659,28 @@ func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, por
 	return portGroupRule, networkSpecific, networkPeersNeeded, nil
 }
 
+// ovnRateLimitToOVNQoSRule converts the bandwidth rate limit of an ACL rule into an OVNQoSRule matching the same
+// traffic as the OVNACLRule generated for it.
+func ovnRateLimitToOVNQoSRule(aclRule ovn.OVNACLRule, rateLimit string) (ovn.OVNQoSRule, error) {
+	bits, unit, err := parseRateBandwidth(rateLimit)
+	if err != nil {
+		return ovn.OVNQoSRule{}, err
+	}
+
+	// OVN expects the bandwidth in kbps.
+	rate := max(bits/rateUnitSeconds[unit]/1000, 1)
+	if rate > math.MaxUint32 {
+		return ovn.OVNQoSRule{}, fmt.Errorf("Rate limit %q exceeds the maximum OVN bandwidth", rateLimit)
+	}
+
+	return ovn.OVNQoSRule{
+		Direction: aclRule.Direction,
+		Match:     aclRule.Match,
+		Priority:  ovnQoSPriorityPortGroupLimit,
+		Rate:      int(rate),
+	}, nil
+}
+
 // ovnRulePortToOVNACLMatch converts protocol (tcp/udp), direction (src/dst) and port criteria list into an OVN
 // match statement.
 func ovnRulePortToOVNACLMatch(protocol string, direction string, portCriteria ...string) string {
@@ -542,7 +700,7 @@ func ovnRulePortToOVNACLMatch(protocol string, direction string, portCriteria ..
 
 // ovnRuleSubjectToOVNACLMatch converts direction (src/dst) and subject criteria list into an OVN match statement.
 // Returns a bool indicating if any of the subjects are network specific.
-func ovnRuleSubjectToOVNACLMatch(direction string, aclNameIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64, subjectCriteria ...string) (string, bool, []db.NetworkPeer, error) {
+func ovnRuleSubjectToOVNACLMatch(direction string, aclNameIDs map[string]int64, addressSetIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64, subjectCriteria ...string) (string, bool, []db.NetworkPeer, error) {
 	fieldParts := make([]string, 0, len(subjectCriteria))
 	networkSpecific := false
 	networkPeersNeeded := make([]db.NetworkPeer, 0)
@@ -616,6 +774,20 @@ func ovnRuleSubjectToOVNACLMatch(direction string, aclNameIDs map[string]int64,
 					networkPeersNeeded = append(networkPeersNeeded, peer)
 
 					continue // Not a port based selector.
+				} else if strings.HasPrefix(subjectCriterion, ruleSubjectAddressSetPrefix) {
+					// Subject is a network address set. Convert to address set criteria.
+					addressSetID, found := addressSetIDs[strings.TrimPrefix(subjectCriterion, ruleSubjectAddressSetPrefix)]
+					if !found {
+						return "", false, nil, fmt.Errorf("Cannot find address set ID for %q", subjectCriterion)
+					}
+
+					addrSetPrefix := OVNAddressSetPrefix(addressSetID)
+
+					fieldParts = append(fieldParts, fmt.Sprintf("ip6.%s == $%s_ip6 || ip4.%s == $%s_ip4", direction, addrSetPrefix, direction, addrSetPrefix))
+
+					continue // Not a port based selector.
+				} else if isDomainSubject(subjectCriterion) {
+					return "", false, nil, fmt.Errorf("Domain name subjects aren't supported on OVN networks")
 				} else {
 					// Assume the bare name is an ACL name and convert to port group.
 					aclID, found := aclNameIDs[subjectCriterion]
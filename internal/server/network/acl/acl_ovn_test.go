@@ -0,0 +1,90 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/network/ovn"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func Test_ovnRuleCriteriaToOVNACLRule_limits(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      api.NetworkACLRule
+		expectErr bool
+	}{
+		{
+			name: "Bandwidth rate limit",
+			rule: api.NetworkACLRule{Action: "allow", RateLimit: "10Mbit/second"},
+		},
+		{
+			name:      "Packet rate limit",
+			rule:      api.NetworkACLRule{Action: "allow", RateLimit: "100/second"},
+			expectErr: true,
+		},
+		{
+			name:      "Connection rate",
+			rule:      api.NetworkACLRule{Action: "allow", ConnectionRate: "10/minute"},
+			expectErr: true,
+		},
+		{
+			name:      "Connection limit",
+			rule:      api.NetworkACLRule{Action: "drop", ConnectionLimit: "100"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := ovnRuleCriteriaToOVNACLRule("ingress", &tt.rule, "pg", nil, nil, nil)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_ovnRateLimitToOVNQoSRule(t *testing.T) {
+	aclRule := ovn.OVNACLRule{
+		Direction: "to-lport",
+		Action:    "allow-related",
+		Match:     "(inport == @pg) && (tcp)",
+		Priority:  ovnACLPriorityPortGroupAllow,
+	}
+
+	tests := []struct {
+		rateLimit    string
+		expectedRate int
+		expectErr    bool
+	}{
+		{rateLimit: "10Mbit/second", expectedRate: 10000},
+		{rateLimit: "1Gbit/second", expectedRate: 1000000},
+		{rateLimit: "60Mbit/minute", expectedRate: 1000},
+		{rateLimit: "8bit/day", expectedRate: 1},
+		{rateLimit: "100/second", expectErr: true},
+		{rateLimit: "10Mbit/week", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rateLimit, func(t *testing.T) {
+			qosRule, err := ovnRateLimitToOVNQoSRule(aclRule, tt.rateLimit)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, ovn.OVNQoSRule{
+				Direction: aclRule.Direction,
+				Match:     aclRule.Match,
+				Priority:  ovnQoSPriorityPortGroupLimit,
+				Rate:      tt.expectedRate,
+			}, qosRule)
+		})
+	}
+}
@@ -2,6 +2,9 @@ package acl
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
 
 	"github.com/lxc/incus/v6/shared/util"
 	"github.com/lxc/incus/v6/shared/validate"
@@ -27,3 +30,43 @@ func ValidName(name string) error {
 
 	return nil
 }
+
+// domainLabelRegex matches a single label of a domain name.
+var domainLabelRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// isDomainSubject returns whether the rule subject is a domain name.
+// ACL names can't contain dots and IP addresses, ranges and subnets never contain letters alongside dots
+// without also containing a colon, so this is enough to tell domain names apart from other subjects.
+func isDomainSubject(subject string) bool {
+	return strings.Contains(subject, ".") && !strings.ContainsAny(subject, ":/") && strings.ContainsFunc(subject, unicode.IsLetter)
+}
+
+// domainSubjectName returns the domain name of a domain subject, without its optional wildcard prefix.
+func domainSubjectName(subject string) string {
+	return strings.ToLower(strings.TrimPrefix(subject, ruleSubjectDomainWildcardPrefix))
+}
+
+// validDomainSubject checks the domain name subject is valid.
+func validDomainSubject(subject string) error {
+	domain := domainSubjectName(subject)
+	if len(domain) > 253 {
+		return fmt.Errorf("Domain name %q is too long", subject)
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("Domain name %q must contain at least two labels", subject)
+	}
+
+	for _, label := range labels {
+		if !domainLabelRegex.MatchString(label) {
+			return fmt.Errorf("Invalid domain name %q", subject)
+		}
+	}
+
+	if !strings.ContainsFunc(labels[len(labels)-1], unicode.IsLetter) {
+		return fmt.Errorf("Invalid top-level domain in %q", subject)
+	}
+
+	return nil
+}
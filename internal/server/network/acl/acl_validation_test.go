@@ -0,0 +1,77 @@
+package acl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_isDomainSubject(t *testing.T) {
+	tests := []struct {
+		subject  string
+		expected bool
+	}{
+		{subject: "example.com", expected: true},
+		{subject: "*.debian.org", expected: true},
+		{subject: "www.example.123", expected: true},
+		{subject: "-example.com", expected: true},
+		{subject: "192.0.2.1", expected: false},
+		{subject: "192.0.2.0/24", expected: false},
+		{subject: "192.0.2.1-192.0.2.10", expected: false},
+		{subject: "2001:db8::1", expected: false},
+		{subject: "2001:db8:a::/64", expected: false},
+		{subject: "::ffff:192.0.2.1", expected: false},
+		{subject: "myacl", expected: false},
+		{subject: "@internal", expected: false},
+		{subject: "$addrset", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isDomainSubject(tt.subject))
+		})
+	}
+}
+
+func Test_validDomainSubject(t *testing.T) {
+	tests := []struct {
+		subject   string
+		expectErr bool
+	}{
+		{subject: "example.com"},
+		{subject: "*.debian.org"},
+		{subject: "Sub-Domain.Example.COM"},
+		{subject: "a1.b2.c3.io"},
+		{subject: "xn--bcher-kva.example"},
+		{subject: "com", expectErr: true},
+		{subject: "*.com", expectErr: true},
+		{subject: "-example.com", expectErr: true},
+		{subject: "example-.com", expectErr: true},
+		{subject: "example..com", expectErr: true},
+		{subject: "example.com.", expectErr: true},
+		{subject: "exa_mple.com", expectErr: true},
+		{subject: "*.*.example.com", expectErr: true},
+		{subject: "example.123", expectErr: true},
+		{subject: strings.Repeat("a", 63) + ".com"},
+		{subject: strings.Repeat("a", 64) + ".com", expectErr: true},
+		{subject: strings.Repeat("abcdefghi.", 26) + "com", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			err := validDomainSubject(tt.subject)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_domainSubjectName(t *testing.T) {
+	assert.Equal(t, "debian.org", domainSubjectName("*.debian.org"))
+	assert.Equal(t, "example.com", domainSubjectName("Example.COM"))
+	assert.Equal(t, FirewallDomainSetName("*.debian.org"), FirewallDomainSetName("Debian.org"))
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"slices"
@@ -23,6 +24,7 @@ import (
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
 	"github.com/lxc/incus/v6/shared/validate"
 )
@@ -42,6 +44,12 @@ var ReservedNetworkSubects = []string{"internal", "external"}
 const ruleSubjectInternal = "@internal"
 const ruleSubjectExternal = "@external"
 
+// Define prefix for address set subjects.
+const ruleSubjectAddressSetPrefix = "$"
+
+// Define optional prefix for domain name subjects (the domain and all its subdomains are always matched).
+const ruleSubjectDomainWildcardPrefix = "*."
+
 // Define aliases for reserved ACL subjects. This is to allow earlier deprecated names that used the "#" prefix.
 // They were deprecated to avoid confusion with YAML comments. So "#internal" and "#external" should not be used.
 var ruleSubjectInternalAliases = []string{ruleSubjectInternal, "#internal"}
@@ -50,6 +58,12 @@ var ruleSubjectExternalAliases = []string{ruleSubjectExternal, "#external"}
 // ValidActions defines valid actions for rules.
 var ValidActions = []string{"allow", "allow-stateless", "drop", "reject"}
 
+// ValidRateUnits defines valid units for rule rate limits.
+var ValidRateUnits = []string{"second", "minute", "hour", "day"}
+
+// rateUnitSeconds defines the duration of the rule rate limit units in seconds.
+var rateUnitSeconds = map[string]int64{"second": 1, "minute": 60, "hour": 3600, "day": 86400}
+
 // common represents a Network ACL.
 type common struct {
 	logger      logger.Logger
@@ -302,12 +316,18 @@ func (d *common) validateRule(direction ruleDirection, rule api.NetworkACLRule)
 	}
 
 	var acls map[string]int64
+	var addressSets []string
 
 	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
 		// Get map of ACL names to DB IDs (used for generating OVN port group names).
 		acls, err = tx.GetNetworkACLIDsByNames(ctx, d.Project())
+		if err != nil {
+			return err
+		}
+
+		addressSets, err = tx.GetNetworkAddressSets(ctx, d.Project())
 
 		return err
 	})
@@ -328,7 +348,7 @@ func (d *common) validateRule(direction ruleDirection, rule api.NetworkACLRule)
 
 	// Validate Source field.
 	if rule.Source != "" {
-		srcHasName, srcHasIPv4, srcHasIPv6, err = d.validateRuleSubjects("Source", direction, util.SplitNTrimSpace(rule.Source, ",", -1, false), validSubjectNames)
+		srcHasName, srcHasIPv4, srcHasIPv6, err = d.validateRuleSubjects("Source", direction, util.SplitNTrimSpace(rule.Source, ",", -1, false), validSubjectNames, addressSets)
 		if err != nil {
 			return fmt.Errorf("Invalid Source: %w", err)
 		}
@@ -336,7 +356,7 @@ func (d *common) validateRule(direction ruleDirection, rule api.NetworkACLRule)
 
 	// Validate Destination field.
 	if rule.Destination != "" {
-		dstHasName, dstHasIPv4, dstHasIPv6, err = d.validateRuleSubjects("Destination", direction, util.SplitNTrimSpace(rule.Destination, ",", -1, false), validSubjectNames)
+		dstHasName, dstHasIPv4, dstHasIPv6, err = d.validateRuleSubjects("Destination", direction, util.SplitNTrimSpace(rule.Destination, ",", -1, false), validSubjectNames, addressSets)
 		if err != nil {
 			return fmt.Errorf("Invalid Destination: %w", err)
 		}
@@ -445,13 +465,101 @@ func (d *common) validateRule(direction ruleDirection, rule api.NetworkACLRule)
 		}
 	}
 
+	// Validate limit fields.
+	if rule.RateLimit != "" {
+		err := d.validateRate(rule.RateLimit, true)
+		if err != nil {
+			return fmt.Errorf("Invalid rate limit: %w", err)
+		}
+	}
+
+	if rule.ConnectionRate != "" {
+		err := d.validateRate(rule.ConnectionRate, false)
+		if err != nil {
+			return fmt.Errorf("Invalid connection rate: %w", err)
+		}
+	}
+
+	if rule.ConnectionLimit != "" {
+		err := validate.IsInRange(1, math.MaxUint32)(rule.ConnectionLimit)
+		if err != nil {
+			return fmt.Errorf("Invalid connection limit: %w", err)
+		}
+	}
+
+	limits := 0
+	for _, limit := range []string{rule.RateLimit, rule.ConnectionRate, rule.ConnectionLimit} {
+		if limit != "" {
+			limits++
+		}
+	}
+
+	if limits > 0 && rule.Action == "allow-stateless" {
+		return fmt.Errorf("Limits cannot be used with %q action", rule.Action)
+	}
+
+	// Drop and reject rules apply to the traffic exceeding the limit, combining limits would be ambiguous.
+	if limits > 1 && slices.Contains([]string{"drop", "reject"}, rule.Action) {
+		return fmt.Errorf("Only one limit can be used with %q action", rule.Action)
+	}
+
 	return nil
 }
 
+// validateRate checks that the rate is in the format <count>/<unit>.
+// If allowBandwidth is true, the rate can also be a bandwidth in the format <size>/<unit> (e.g. 10Mbit/second).
+func (d *common) validateRate(rate string, allowBandwidth bool) error {
+	count, unit, found := strings.Cut(rate, "/")
+	if !found {
+		return fmt.Errorf("Rate must be in the format <count>/<unit>")
+	}
+
+	if !slices.Contains(ValidRateUnits, unit) {
+		return fmt.Errorf("Rate unit must be one of: %s", strings.Join(ValidRateUnits, ", "))
+	}
+
+	err := validate.IsInRange(1, math.MaxUint32)(count)
+	if err == nil || !allowBandwidth {
+		return err
+	}
+
+	_, _, bandwidthErr := parseRateBandwidth(rate)
+	if bandwidthErr != nil {
+		return fmt.Errorf("Rate must be in the format <count>/<unit> or <size>/<unit>: %w", bandwidthErr)
+	}
+
+	return nil
+}
+
+// parseRateBandwidth parses a rate limit in the <size>/<unit> format.
+// Returns the number of bits per unit and the unit.
+func parseRateBandwidth(rate string) (int64, string, error) {
+	size, unit, found := strings.Cut(rate, "/")
+	if !found || !slices.Contains(ValidRateUnits, unit) {
+		return 0, "", fmt.Errorf("Invalid bandwidth %q", rate)
+	}
+
+	// Plain numbers are packet counts.
+	if validate.IsUint32(size) == nil {
+		return 0, "", fmt.Errorf("Rate %q is a packet rate rather than a bandwidth", rate)
+	}
+
+	bits, err := units.ParseBitSizeString(size)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if bits < 8 {
+		return 0, "", fmt.Errorf("Bandwidth must be at least 8bit/%s", unit)
+	}
+
+	return bits, unit, nil
+}
+
 // validateRuleSubjects checks that the source or destination subjects for a rule are valid.
-// Accepts a validSubjectNames list of valid ACL or special classifier names.
-// Returns whether the subjects include names, IPv4 and IPv6 addresses respectively.
-func (d *common) validateRuleSubjects(fieldName string, direction ruleDirection, subjects []string, validSubjectNames []string) (bool, bool, bool, error) {
+// Accepts a validSubjectNames list of valid ACL or special classifier names and a list of valid address set names.
+// Returns whether the subjects include names (or address sets), IPv4 and IPv6 addresses respectively.
+func (d *common) validateRuleSubjects(fieldName string, direction ruleDirection, subjects []string, validSubjectNames []string, validAddressSets []string) (bool, bool, bool, error) {
 	// Check if named subjects are allowed in field/direction combination.
 	allowSubjectNames := false
 	if (fieldName == "Source" && direction == ruleDirectionIngress) || (fieldName == "Destination" && direction == ruleDirectionEgress) {
@@ -514,6 +622,40 @@ func (d *common) validateRuleSubjects(fieldName string, direction ruleDirection,
 	}
 
 	validSubject := func(subject string) (uint, error) {
+		// Check if it is an address set reference (allowed in any field as it only contains addresses).
+		addressSetName, isAddressSet := strings.CutPrefix(subject, ruleSubjectAddressSetPrefix)
+		if isAddressSet {
+			if !slices.Contains(validAddressSets, addressSetName) {
+				return 0, fmt.Errorf("Network address set %q does not exist", addressSetName)
+			}
+
+			err := FirewallAddressSetsSupported(d.state)
+			if err != nil {
+				return 0, err
+			}
+
+			return 0, nil // Found valid subject.
+		}
+
+		// Check if it is a domain name (only allowed as the destination of egress rules).
+		if isDomainSubject(subject) {
+			if fieldName != "Destination" || direction != ruleDirectionEgress {
+				return 0, fmt.Errorf("Domain name subjects only allowed in %q for %q rules", "Destination", ruleDirectionEgress)
+			}
+
+			err := validDomainSubject(subject)
+			if err != nil {
+				return 0, err
+			}
+
+			err = FirewallDomainSetsSupported(d.state)
+			if err != nil {
+				return 0, err
+			}
+
+			return 0, nil // Found valid subject.
+		}
+
 		// Check if it is one of the network IP types.
 		for _, c := range checks {
 			ipVersion, err := c(subject)
@@ -658,6 +800,14 @@ func (d *common) Update(config *api.NetworkACLPut, clientType request.ClientType
 		}
 	}
 
+	// Refresh the DNS name sets so that domains no longer referenced stop being resolved into them.
+	if (len(aclNets) > 0 || len(aclBridgeNICs) > 0) && d.state.Firewall.String() == "nftables" {
+		err = FirewallRefreshDomainSets(d.state, d.projectName)
+		if err != nil {
+			return fmt.Errorf("Failed refreshing ACL domain sets: %w", err)
+		}
+	}
+
 	// If there are affected OVN networks, then apply the changes, but only if the request type is normal.
 	// This way we won't apply the same changes multiple times for each cluster member.
 	if len(aclOVNNets) > 0 && clientType == request.ClientTypeNormal {
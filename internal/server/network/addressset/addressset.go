@@ -0,0 +1,399 @@
+package addressset
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	incus "github.com/lxc/incus/v6/client"
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/cluster/request"
+	"github.com/lxc/incus/v6/internal/server/db"
+	firewallDrivers "github.com/lxc/incus/v6/internal/server/firewall/drivers"
+	"github.com/lxc/incus/v6/internal/server/network/acl"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// common represents a network address set.
+type common struct {
+	logger      logger.Logger
+	state       *state.State
+	id          int64
+	projectName string
+	info        *api.NetworkAddressSet
+}
+
+// init initialize internal variables.
+func (d *common) init(state *state.State, id int64, projectName string, info *api.NetworkAddressSet) {
+	if info == nil {
+		d.info = &api.NetworkAddressSet{}
+	} else {
+		d.info = info
+	}
+
+	d.logger = logger.AddContext(logger.Ctx{"project": projectName, "networkAddressSet": d.info.Name})
+	d.id = id
+	d.projectName = projectName
+	d.state = state
+
+	if d.info.Addresses == nil {
+		d.info.Addresses = []string{}
+	}
+
+	if d.info.Config == nil {
+		d.info.Config = make(map[string]string)
+	}
+}
+
+// ID returns the network address set ID.
+func (d *common) ID() int64 {
+	return d.id
+}
+
+// Project returns the project name.
+func (d *common) Project() string {
+	return d.projectName
+}
+
+// Info returns copy of internal info for the network address set.
+func (d *common) Info() *api.NetworkAddressSet {
+	// Copy internal info to prevent modification externally.
+	info := api.NetworkAddressSet{}
+	info.Name = d.info.Name
+	info.Description = d.info.Description
+	info.Addresses = append(make([]string, 0, len(d.info.Addresses)), d.info.Addresses...)
+	info.Config = localUtil.CopyConfig(d.info.Config)
+	info.UsedBy = nil // To indicate its not populated (use Usedby() function to populate).
+	info.Project = d.projectName
+
+	return &info
+}
+
+// usedByACLs returns the names of the network ACLs that reference this address set in their rules.
+// If firstOnly is true then search stops at first result.
+func (d *common) usedByACLs(firstOnly bool) ([]string, error) {
+	aclNames := []string{}
+
+	// Address sets are referenced in ACL rules with a "$" prefix.
+	subject := "$" + d.info.Name
+
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		projectACLNames, err := tx.GetNetworkACLs(ctx, d.projectName)
+		if err != nil {
+			return err
+		}
+
+		for _, aclName := range projectACLNames {
+			_, aclInfo, err := tx.GetNetworkACL(ctx, d.projectName, aclName)
+			if err != nil {
+				return err
+			}
+
+			for _, rule := range append(aclInfo.Ingress, aclInfo.Egress...) {
+				subjects := append(util.SplitNTrimSpace(rule.Source, ",", -1, true), util.SplitNTrimSpace(rule.Destination, ",", -1, true)...)
+				if slices.Contains(subjects, subject) {
+					aclNames = append(aclNames, aclName)
+					break
+				}
+			}
+
+			if firstOnly && len(aclNames) > 0 {
+				return nil
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting address set usage: %w", err)
+	}
+
+	return aclNames, nil
+}
+
+// UsedBy returns a list of API endpoints referencing this address set.
+func (d *common) UsedBy() ([]string, error) {
+	aclNames, err := d.usedByACLs(false)
+	if err != nil {
+		return nil, err
+	}
+
+	usedBy := make([]string, 0, len(aclNames))
+	for _, aclName := range aclNames {
+		uri := fmt.Sprintf("/%s/network-acls/%s", version.APIVersion, aclName)
+		if d.projectName != api.ProjectDefaultName {
+			uri += fmt.Sprintf("?project=%s", d.projectName)
+		}
+
+		usedBy = append(usedBy, uri)
+	}
+
+	return usedBy, nil
+}
+
+// isUsed returns whether or not the address set is in use.
+func (d *common) isUsed() (bool, error) {
+	aclNames, err := d.usedByACLs(true)
+	if err != nil {
+		return false, err
+	}
+
+	return len(aclNames) > 0, nil
+}
+
+// Etag returns the values used for etag generation.
+func (d *common) Etag() []any {
+	return []any{d.info.Name, d.info.Description, d.info.Addresses, d.info.Config}
+}
+
+// validateName checks name is valid.
+func (d *common) validateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("Name is required")
+	}
+
+	// Ensures the name can be safely referenced from ACL rules.
+	err := validate.IsHostname(name)
+	if err != nil {
+		return fmt.Errorf("Invalid name %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// validateConfig checks the config and addresses are valid.
+func (d *common) validateConfig(info *api.NetworkAddressSetPut) error {
+	// Address sets are only loaded into the nftables firewall.
+	err := acl.FirewallAddressSetsSupported(d.state)
+	if err != nil {
+		return err
+	}
+
+	for k := range info.Config {
+		// User keys are not validated.
+		if internalInstance.IsUserConfig(k) {
+			continue
+		}
+
+		return fmt.Errorf("Invalid config option %q", k)
+	}
+
+	isAddress := validate.Or(validate.IsNetworkAddress, validate.IsNetwork)
+
+	for i, address := range info.Addresses {
+		err = isAddress(address)
+		if err != nil {
+			return fmt.Errorf("Invalid address %q: %w", address, err)
+		}
+
+		if slices.Contains(info.Addresses[:i], address) {
+			return fmt.Errorf("Duplicate address %q", address)
+		}
+	}
+
+	return nil
+}
+
+// Update applies the supplied config to the address set and refreshes the firewall and OVN sets using it.
+func (d *common) Update(config *api.NetworkAddressSetPut, clientType request.ClientType) error {
+	// Validate the configuration.
+	err := d.validateConfig(config)
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		oldConfig := d.info.NetworkAddressSetPut
+
+		err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateNetworkAddressSet(ctx, d.id, config)
+		})
+		if err != nil {
+			return err
+		}
+
+		// Apply changes internally and reinitialize.
+		d.info.NetworkAddressSetPut = *config
+		d.init(d.state, d.id, d.projectName, d.info)
+
+		revert.Add(func() {
+			_ = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.UpdateNetworkAddressSet(ctx, d.id, &oldConfig)
+			})
+
+			d.info.NetworkAddressSetPut = oldConfig
+			d.init(d.state, d.id, d.projectName, d.info)
+		})
+	}
+
+	// Get a list of networks that are using an ACL referencing this address set.
+	aclNames, err := d.usedByACLs(false)
+	if err != nil {
+		return err
+	}
+
+	aclNets := map[string]acl.NetworkACLUsage{}
+	err = acl.NetworkUsage(d.state, d.projectName, aclNames, aclNets)
+	if err != nil {
+		return fmt.Errorf("Failed getting ACL network usage: %w", err)
+	}
+
+	// OVN networks share their address sets through the northbound database while bridge networks
+	// use sets in the local firewall of each member.
+	usedByOVN := false
+	usedByFirewall := false
+	for _, aclNet := range aclNets {
+		if aclNet.Type == "ovn" {
+			usedByOVN = true
+		} else {
+			usedByFirewall = true
+		}
+	}
+
+	// Refresh the firewall set on this member.
+	if usedByFirewall {
+		err = d.state.Firewall.NetworkApplyAddressSets([]firewallDrivers.AddressSet{{
+			Name:      acl.FirewallAddressSetName(d.id),
+			Addresses: config.Addresses,
+		}})
+		if err != nil {
+			return fmt.Errorf("Failed applying address set to firewall: %w", err)
+		}
+	}
+
+	// Refresh the OVN address set, but only if the request type is normal.
+	// This way we won't apply the same changes multiple times for each cluster member.
+	if usedByOVN && clientType == request.ClientTypeNormal {
+		ovnnb, _, err := d.state.OVN()
+		if err != nil {
+			return err
+		}
+
+		err = acl.OVNEnsureAddressSet(d.state, ovnnb, d.projectName, d.info.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Refresh the firewall set on the other cluster members.
+	if usedByFirewall && clientType == request.ClientTypeNormal {
+		notifier, err := cluster.NewNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(d.projectName).UpdateNetworkAddressSet(d.info.Name, d.info.NetworkAddressSetPut, "")
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	revert.Success()
+	return nil
+}
+
+// Rename renames the address set if not in use.
+func (d *common) Rename(newName string) error {
+	_, err := LoadByName(d.state, d.projectName, newName)
+	if err == nil {
+		return fmt.Errorf("An address set by that name exists already")
+	}
+
+	isUsed, err := d.isUsed()
+	if err != nil {
+		return err
+	}
+
+	if isUsed {
+		return fmt.Errorf("Cannot rename an address set that is in use")
+	}
+
+	err = d.validateName(newName)
+	if err != nil {
+		return err
+	}
+
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.RenameNetworkAddressSet(ctx, d.id, newName)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Apply changes internally.
+	d.info.Name = newName
+
+	return nil
+}
+
+// Delete deletes the address set if not in use and removes its firewall and OVN sets.
+func (d *common) Delete(clientType request.ClientType) error {
+	// Only remove the firewall set on this member when notified by the member handling the request.
+	if clientType != request.ClientTypeNormal {
+		err := d.state.Firewall.NetworkDeleteAddressSet(acl.FirewallAddressSetName(d.id))
+		if err != nil {
+			return fmt.Errorf("Failed removing address set from firewall: %w", err)
+		}
+
+		return nil
+	}
+
+	isUsed, err := d.isUsed()
+	if err != nil {
+		return err
+	}
+
+	if isUsed {
+		return fmt.Errorf("Cannot delete an address set that is in use")
+	}
+
+	// Remove the firewall set on the other cluster members while they can still load the address set.
+	notifier, err := cluster.NewNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
+	if err != nil {
+		return err
+	}
+
+	err = notifier(func(client incus.InstanceServer) error {
+		return client.UseProject(d.projectName).DeleteNetworkAddressSet(d.info.Name)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteNetworkAddressSet(ctx, d.id)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Remove any leftover firewall set on this member.
+	err = d.state.Firewall.NetworkDeleteAddressSet(acl.FirewallAddressSetName(d.id))
+	if err != nil {
+		d.logger.Warn("Failed removing address set from firewall", logger.Ctx{"err": err})
+	}
+
+	// Remove any leftover OVN address set.
+	ovnnb, _, err := d.state.OVN()
+	if err == nil {
+		err = ovnnb.DeleteAddressSet(context.TODO(), acl.OVNAddressSetPrefix(d.id))
+		if err != nil {
+			d.logger.Warn("Failed removing OVN address set", logger.Ctx{"err": err})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package addressset
+
+import (
+	"context"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// LoadByName loads and initializes a network address set from the database by project and name.
+func LoadByName(s *state.State, projectName string, name string) (NetworkAddressSet, error) {
+	var id int64
+	var setInfo *api.NetworkAddressSet
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		id, setInfo, err = tx.GetNetworkAddressSet(ctx, projectName, name)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var set NetworkAddressSet = &common{}
+	set.init(s, id, projectName, setInfo)
+
+	return set, nil
+}
+
+// Create validates supplied record and creates new network address set record in the database.
+func Create(s *state.State, projectName string, setInfo *api.NetworkAddressSetsPost) error {
+	var set NetworkAddressSet = &common{}
+	set.init(s, -1, projectName, nil)
+
+	err := set.validateName(setInfo.Name)
+	if err != nil {
+		return err
+	}
+
+	err = set.validateConfig(&setInfo.NetworkAddressSetPut)
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Insert DB record.
+		_, err := tx.CreateNetworkAddressSet(ctx, projectName, setInfo)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 
 	incus "github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/server/apparmor"
+	"github.com/lxc/incus/v6/internal/server/bgp"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/cluster/request"
 	"github.com/lxc/incus/v6/internal/server/daemon"
@@ -162,6 +163,9 @@ func (n *bridge) Validate(config map[string]string) error {
 	rules := map[string]func(value string) error{
 		"bgp.ipv4.nexthop": validate.Optional(validate.IsNetworkAddressV4),
 		"bgp.ipv6.nexthop": validate.Optional(validate.IsNetworkAddressV6),
+		"bgp.evpn.vni":     validate.Optional(validate.IsInRange(1, 16777215)),
+		"bgp.evpn.local":   validate.Optional(validate.IsNetworkAddressV4),
+		"bgp.evpn.port":    networkValidPort,
 
 		"bridge.driver":              validate.Optional(validate.IsOneOf("native", "openvswitch")),
 		"bridge.external_interfaces": validate.Optional(validateExternalInterfaces),
@@ -303,6 +307,17 @@ func (n *bridge) Validate(config map[string]string) error {
 		}
 	}
 
+	// Check EVPN configuration.
+	if config["bgp.evpn.vni"] != "" {
+		if config["bridge.driver"] == "openvswitch" {
+			return fmt.Errorf("EVPN isn't supported with the openvswitch bridge driver")
+		}
+
+		if len(n.name) > 10 {
+			return fmt.Errorf("Network name too long for EVPN interface: %s-evpn", n.name)
+		}
+	}
+
 	// Check using same MAC address on every cluster node is safe.
 	if config["bridge.hwaddr"] != "" {
 		err = n.checkClusterWideMACSafe(config)
@@ -539,7 +554,7 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 
 		bridge.MTU = uint32(mtuInt)
-	} else if len(tunnels) > 0 {
+	} else if len(tunnels) > 0 || n.config["bgp.evpn.vni"] != "" {
 		bridge.MTU = 1400
 	}
 
@@ -1307,6 +1322,49 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 	}
 
+	// Configure the EVPN tunnel.
+	evpnVNI, evpnLocal := BridgeEVPN(n.config)
+	if evpnVNI > 0 {
+		if evpnLocal == nil {
+			return fmt.Errorf(`EVPN requires a local tunnel address ("bgp.evpn.local" or "bgp.ipv4.nexthop")`)
+		}
+
+		evpnPort := n.config["bgp.evpn.port"]
+		if evpnPort == "" {
+			evpnPort = "4789"
+		}
+
+		// Remote MAC addresses are learned through BGP rather than from the data plane.
+		learning := false
+		vxlan := &ip.Vxlan{
+			Link:     ip.Link{Name: n.evpnInterface()},
+			VxlanID:  fmt.Sprintf("%d", evpnVNI),
+			Local:    evpnLocal.String(),
+			DstPort:  evpnPort,
+			Learning: &learning,
+		}
+
+		err = vxlan.Add()
+		if err != nil {
+			return err
+		}
+
+		err = AttachInterface(n.state, n.name, vxlan.Name)
+		if err != nil {
+			return err
+		}
+
+		err = vxlan.SetMTU(bridge.MTU)
+		if err != nil {
+			return err
+		}
+
+		err = vxlan.SetUp()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Generate and load apparmor profiles.
 	err = apparmor.NetworkLoad(n.state.OS, n)
 	if err != nil {
@@ -1341,6 +1399,26 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 
 		dnsmasqCmd = append(dnsmasqCmd, fmt.Sprintf("--conf-file=%s", internalUtil.VarPath("networks", n.name, "dnsmasq.raw")))
 
+		// Populate the firewall sets used by ACL rules matching domain names.
+		if n.state.Firewall.String() == "nftables" && dnsmasq.NftSetsSupported(dnsmasqVersion) {
+			domainSets, err := acl.FirewallDomainSets(n.state, n.project)
+			if err != nil {
+				return err
+			}
+
+			err = acl.FirewallApplyDomainSets(n.state, domainSets)
+			if err != nil {
+				return err
+			}
+
+			_, err = dnsmasq.UpdateNftSets(n.name, domainSets, acl.FirewallDomainSetTimeout)
+			if err != nil {
+				return err
+			}
+
+			dnsmasqCmd = append(dnsmasqCmd, fmt.Sprintf("--conf-file=%s", dnsmasq.NftSetsPath(n.name)))
+		}
+
 		// Attempt to drop privileges.
 		if n.state.OS.UnprivUser != "" {
 			dnsmasqCmd = append(dnsmasqCmd, []string{"-u", n.state.OS.UnprivUser}...)
@@ -1478,6 +1556,12 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		return err
 	}
 
+	// Setup EVPN.
+	err = n.evpnSetup()
+	if err != nil {
+		return fmt.Errorf("Failed setting up EVPN: %w", err)
+	}
+
 	revert.Success()
 	return nil
 }
@@ -1496,6 +1580,12 @@ func (n *bridge) Stop() error {
 		return err
 	}
 
+	// Clear EVPN.
+	err = n.evpnClear()
+	if err != nil {
+		return err
+	}
+
 	err = n.deleteChildren()
 	if err != nil {
 		return fmt.Errorf("Failed to delete bridge children interfaces: %w", err)
@@ -2727,3 +2817,78 @@ func (n *bridge) deleteChildren() error {
 
 	return nil
 }
+
+// evpnInterface returns the name of the VXLAN interface used for EVPN.
+func (n *bridge) evpnInterface() string {
+	return fmt.Sprintf("%s-evpn", n.name)
+}
+
+// evpnSetup announces the local tunnel endpoint and tracks the remote ones.
+func (n *bridge) evpnSetup() error {
+	// Clear any existing state.
+	err := n.evpnClear()
+	if err != nil {
+		return err
+	}
+
+	evpnVNI, evpnLocal := BridgeEVPN(n.config)
+	if evpnVNI == 0 {
+		return nil
+	}
+
+	// Keep the forwarding database of the VXLAN interface in sync with the remote routes.
+	devName := n.evpnInterface()
+	n.state.BGP.AddEVPNHandler(evpnVNI, evpnLocal, func(route bgp.EVPNRoute, withdraw bool) {
+		fdb := &ip.Fdb{
+			DevName: devName,
+			MAC:     route.MAC,
+			Dst:     route.VTEP,
+		}
+
+		var err error
+		if route.MAC == nil {
+			// Broadcast, unknown unicast and multicast traffic is replicated to every remote endpoint.
+			fdb.MAC = net.HardwareAddr{0, 0, 0, 0, 0, 0}
+			if withdraw {
+				err = fdb.Delete()
+			} else {
+				err = fdb.Append()
+			}
+		} else {
+			if withdraw {
+				err = fdb.Delete()
+			} else {
+				err = fdb.Replace()
+			}
+		}
+
+		if err != nil {
+			n.logger.Warn("Failed updating EVPN forwarding entry", logger.Ctx{"mac": fdb.MAC.String(), "vtep": route.VTEP.String(), "withdraw": withdraw, "err": err})
+		}
+	}, n.evpnOwner())
+
+	// Announce the local tunnel endpoint.
+	err = n.state.BGP.AddEVPNRoute(bgp.EVPNRoute{VNI: evpnVNI, VTEP: evpnLocal}, n.evpnOwner())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// evpnClear withdraws the local tunnel endpoint and stops tracking the remote ones.
+func (n *bridge) evpnClear() error {
+	n.state.BGP.RemoveEVPNHandler(n.evpnOwner())
+
+	err := n.state.BGP.RemoveEVPNRoutesByOwner(n.evpnOwner())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// evpnOwner returns the owner used for the EVPN routes of the network.
+func (n *bridge) evpnOwner() string {
+	return fmt.Sprintf("network_%d_evpn", n.id)
+}
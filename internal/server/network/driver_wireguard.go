@@ -0,0 +1,853 @@
+package network
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/curve25519"
+
+	incus "github.com/lxc/incus/v6/client"
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/cluster/request"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/internal/server/network/acl"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// wireguardDefaultListenPort is the UDP port used when wireguard.listen_port isn't set.
+const wireguardDefaultListenPort = 51820
+
+// wireguardDefaultMTU leaves room for the WireGuard encapsulation over an IPv6 underlay with a 1500 bytes MTU.
+const wireguardDefaultMTU = 1420
+
+// wireguard represents a WireGuard network.
+type wireguard struct {
+	common
+}
+
+// DBType returns the network type DB ID.
+func (n *wireguard) DBType() db.NetworkType {
+	return db.NetworkTypeWireguard
+}
+
+// Info returns the network driver info.
+func (n *wireguard) Info() Info {
+	info := n.common.Info()
+	info.Peering = true
+
+	return info
+}
+
+// ValidateName validates network name.
+func (n *wireguard) ValidateName(name string) error {
+	err := validate.IsInterfaceName(name)
+	if err != nil {
+		return err
+	}
+
+	// Apply common name validation that applies to all network types.
+	return n.common.ValidateName(name)
+}
+
+// Validate network config.
+func (n *wireguard) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		"mtu":                    validate.Optional(validate.IsNetworkMTU),
+		"wireguard.listen_port":  validate.Optional(validate.IsNetworkPort),
+		"wireguard.ipv4.address": validate.Optional(validate.IsNetworkAddressCIDRV4),
+		"wireguard.ipv6.address": validate.Optional(validate.IsNetworkAddressCIDRV6),
+	}
+
+	err := n.validate(config, rules)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isRunning returns whether the WireGuard interface exists.
+func (n *wireguard) isRunning() bool {
+	return InterfaceExists(n.name)
+}
+
+// Delete deletes a network.
+func (n *wireguard) Delete(clientType request.ClientType) error {
+	n.logger.Debug("Delete", logger.Ctx{"clientType": clientType})
+
+	if n.isRunning() {
+		err := n.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	return n.common.delete(clientType)
+}
+
+// Rename renames a network.
+func (n *wireguard) Rename(newName string) error {
+	n.logger.Debug("Rename", logger.Ctx{"newName": newName})
+
+	if InterfaceExists(newName) {
+		return fmt.Errorf("Network interface %q already exists", newName)
+	}
+
+	// Bring the network down.
+	if n.isRunning() {
+		err := n.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Rename common steps.
+	err := n.common.rename(newName)
+	if err != nil {
+		return err
+	}
+
+	// Bring the network up.
+	err = n.Start()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Start starts the network.
+func (n *wireguard) Start() error {
+	n.logger.Debug("Start")
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	revert.Add(func() { n.setUnavailable() })
+
+	err := n.setup()
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+
+	// Ensure network is marked as available now its started.
+	n.setAvailable()
+
+	return nil
+}
+
+// setup creates the WireGuard interface if needed and applies the network config and peers to it.
+func (n *wireguard) setup() error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	if !n.isRunning() {
+		link := &ip.Wireguard{Link: ip.Link{Name: n.name}}
+		err := link.Add()
+		if err != nil {
+			return fmt.Errorf("Failed creating WireGuard interface %q: %w", n.name, err)
+		}
+
+		revert.Add(func() { _ = InterfaceRemove(n.name) })
+	}
+
+	// Set the MTU.
+	mtu := uint64(wireguardDefaultMTU)
+	if n.config["mtu"] != "" {
+		var err error
+
+		mtu, err = strconv.ParseUint(n.config["mtu"], 10, 32)
+		if err != nil {
+			return fmt.Errorf("Invalid MTU %q: %w", n.config["mtu"], err)
+		}
+	}
+
+	link := &ip.Link{Name: n.name}
+	err := link.SetMTU(uint32(mtu))
+	if err != nil {
+		return fmt.Errorf("Failed setting MTU %d on %q: %w", mtu, n.name, err)
+	}
+
+	// Apply the member specific addresses.
+	for _, family := range []string{ip.FamilyV4, ip.FamilyV6} {
+		addr := &ip.Addr{
+			DevName: n.name,
+			Scope:   "global",
+			Family:  family,
+		}
+
+		err = addr.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, key := range []string{"wireguard.ipv4.address", "wireguard.ipv6.address"} {
+		if n.config[key] == "" {
+			continue
+		}
+
+		family := ip.FamilyV4
+		if key == "wireguard.ipv6.address" {
+			family = ip.FamilyV6
+		}
+
+		addr := &ip.Addr{
+			DevName: n.name,
+			Address: n.config[key],
+			Family:  family,
+		}
+
+		err = addr.Add()
+		if err != nil {
+			return fmt.Errorf("Failed adding address %q to %q: %w", n.config[key], n.name, err)
+		}
+	}
+
+	err = link.SetUp()
+	if err != nil {
+		return err
+	}
+
+	err = n.setupPeers()
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// keyPath returns the path of the file holding the local private key.
+func (n *wireguard) keyPath() string {
+	return internalUtil.VarPath("networks", n.name, "wireguard.key")
+}
+
+// privateKey returns the local private key, generating it on first use.
+// The key is specific to each cluster member and never leaves it.
+func (n *wireguard) privateKey() (string, error) {
+	content, err := os.ReadFile(n.keyPath())
+	if err == nil {
+		return strings.TrimSpace(string(content)), nil
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("Failed reading WireGuard private key: %w", err)
+	}
+
+	key := make([]byte, curve25519.ScalarSize)
+	_, err = rand.Read(key)
+	if err != nil {
+		return "", fmt.Errorf("Failed generating WireGuard private key: %w", err)
+	}
+
+	// Clamp the key as described in RFC 7748.
+	key[0] &= 248
+	key[31] = (key[31] & 127) | 64
+
+	encodedKey := base64.StdEncoding.EncodeToString(key)
+
+	err = os.MkdirAll(internalUtil.VarPath("networks", n.name), 0o711)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(n.keyPath(), []byte(encodedKey+"\n"), 0o600)
+	if err != nil {
+		return "", fmt.Errorf("Failed writing WireGuard private key: %w", err)
+	}
+
+	return encodedKey, nil
+}
+
+// validateWireguardKey checks that the value is a base64 encoded WireGuard key.
+func validateWireguardKey(value string) error {
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) != curve25519.ScalarSize {
+		return fmt.Errorf("Invalid WireGuard key")
+	}
+
+	return nil
+}
+
+// wireguardAllowedIPs parses the subnets routed to a peer.
+func wireguardAllowedIPs(config map[string]string) ([]*net.IPNet, error) {
+	var subnets []*net.IPNet
+
+	for _, entry := range util.SplitNTrimSpace(config["wireguard.allowed_ips"], ",", -1, true) {
+		_, subnet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		subnets = append(subnets, subnet)
+	}
+
+	return subnets, nil
+}
+
+// peers returns the WireGuard peers of the network.
+func (n *wireguard) peers() (map[int64]*api.NetworkPeer, error) {
+	var peers map[int64]*api.NetworkPeer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		peers, err = tx.GetNetworkPeers(ctx, n.ID())
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return peers, nil
+}
+
+// wireguardConfig renders the WireGuard config of the interface for the given peers.
+// It also returns the subnets that must be routed through the interface.
+func wireguardConfig(privateKey string, listenPort string, peers map[int64]*api.NetworkPeer) (string, []string) {
+	var sb strings.Builder
+	var routes []string
+
+	sb.WriteString("[Interface]\n")
+	sb.WriteString("PrivateKey = " + privateKey + "\n")
+	sb.WriteString("ListenPort = " + listenPort + "\n")
+
+	// Sort the peers by name so that the generated config is stable.
+	sortedPeers := make([]*api.NetworkPeer, 0, len(peers))
+	for _, peer := range peers {
+		sortedPeers = append(sortedPeers, peer)
+	}
+
+	sort.Slice(sortedPeers, func(i, j int) bool { return sortedPeers[i].Name < sortedPeers[j].Name })
+
+	for _, peer := range sortedPeers {
+		sb.WriteString("\n[Peer]\n")
+		sb.WriteString("PublicKey = " + peer.Config["wireguard.public_key"] + "\n")
+
+		if peer.Config["wireguard.preshared_key"] != "" {
+			sb.WriteString("PresharedKey = " + peer.Config["wireguard.preshared_key"] + "\n")
+		}
+
+		allowedIPs := util.SplitNTrimSpace(peer.Config["wireguard.allowed_ips"], ",", -1, true)
+		if len(allowedIPs) > 0 {
+			sb.WriteString("AllowedIPs = " + strings.Join(allowedIPs, ", ") + "\n")
+			routes = append(routes, allowedIPs...)
+		}
+
+		if peer.Config["wireguard.endpoint"] != "" {
+			sb.WriteString("Endpoint = " + peer.Config["wireguard.endpoint"] + "\n")
+		}
+
+		if peer.Config["wireguard.persistent_keepalive"] != "" {
+			sb.WriteString("PersistentKeepalive = " + peer.Config["wireguard.persistent_keepalive"] + "\n")
+		}
+	}
+
+	return sb.String(), routes
+}
+
+// setupPeers applies the current set of peers to the WireGuard interface and routes their subnets through it.
+func (n *wireguard) setupPeers() error {
+	privateKey, err := n.privateKey()
+	if err != nil {
+		return err
+	}
+
+	peers, err := n.peers()
+	if err != nil {
+		return fmt.Errorf("Failed loading network peers: %w", err)
+	}
+
+	listenPort := strconv.Itoa(wireguardDefaultListenPort)
+	if n.config["wireguard.listen_port"] != "" {
+		listenPort = n.config["wireguard.listen_port"]
+	}
+
+	conf, routes := wireguardConfig(privateKey, listenPort, peers)
+
+	// The config contains the private key so it must only be readable by root.
+	confPath := internalUtil.VarPath("networks", n.name, "wireguard.conf")
+	err = os.WriteFile(confPath, []byte(conf), 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed writing WireGuard config: %w", err)
+	}
+
+	_, err = subprocess.RunCommand("wg", "syncconf", n.name, confPath)
+	if err != nil {
+		return fmt.Errorf("Failed applying WireGuard config: %w", err)
+	}
+
+	// Route the peer subnets through the interface.
+	hasIPv4 := false
+	hasIPv6 := false
+	for _, family := range []string{ip.FamilyV4, ip.FamilyV6} {
+		r := &ip.Route{
+			DevName: n.name,
+			Proto:   "static",
+			Family:  family,
+		}
+
+		err = r.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, route := range routes {
+		family := ip.FamilyV4
+		if validate.IsNetworkV6(route) == nil {
+			family = ip.FamilyV6
+			hasIPv6 = true
+		} else {
+			hasIPv4 = true
+		}
+
+		r := &ip.Route{
+			DevName: n.name,
+			Route:   route,
+			Proto:   "static",
+			Family:  family,
+		}
+
+		err = r.Add()
+		if err != nil {
+			return fmt.Errorf("Failed adding route %q: %w", route, err)
+		}
+	}
+
+	// Allow forwarding so that traffic from other networks can be routed to the peers.
+	if hasIPv4 {
+		err = localUtil.SysctlSet("net/ipv4/ip_forward", "1")
+		if err != nil {
+			return err
+		}
+	}
+
+	if hasIPv6 {
+		err = localUtil.SysctlSet("net/ipv6/conf/all/forwarding", "1")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stop stops the network.
+func (n *wireguard) Stop() error {
+	n.logger.Debug("Stop")
+
+	if !n.isRunning() {
+		return nil
+	}
+
+	err := InterfaceRemove(n.name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Update updates the network. Accepts notification boolean indicating if this update request is coming from a
+// cluster notification, in which case do not update the database, just apply local changes needed.
+func (n *wireguard) Update(newNetwork api.NetworkPut, targetNode string, clientType request.ClientType) error {
+	n.logger.Debug("Update", logger.Ctx{"clientType": clientType, "newNetwork": newNetwork})
+
+	dbUpdateNeeded, _, oldNetwork, err := n.common.configChanged(newNetwork)
+	if err != nil {
+		return err
+	}
+
+	if !dbUpdateNeeded {
+		// Peer changes are sent to the other cluster members as a notification without config changes.
+		if clientType == request.ClientTypeNotifier && n.isRunning() {
+			return n.setupPeers()
+		}
+
+		return nil // Nothing changed.
+	}
+
+	// If the network as a whole has not had any previous creation attempts, or the node itself is still
+	// pending, then don't apply the new settings to the node, just to the database record (ready for the
+	// actual global create request to be initiated).
+	if n.Status() == api.NetworkStatusPending || n.LocalStatus() == api.NetworkStatusPending {
+		return n.common.update(newNetwork, targetNode, clientType)
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Define a function which reverts everything.
+	revert.Add(func() {
+		// Reset changes to all nodes and database.
+		_ = n.common.update(oldNetwork, targetNode, clientType)
+		_ = n.setup()
+	})
+
+	// Apply changes to all nodes and database.
+	err = n.common.update(newNetwork, targetNode, clientType)
+	if err != nil {
+		return err
+	}
+
+	err = n.setup()
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// State returns the network state, including the WireGuard interface and peers state.
+func (n *wireguard) State() (*api.NetworkState, error) {
+	state, err := n.common.State()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := subprocess.RunCommand("wg", "show", n.name, "dump")
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting WireGuard state: %w", err)
+	}
+
+	peers, err := n.peers()
+	if err != nil {
+		return nil, err
+	}
+
+	peerNames := make(map[string]string, len(peers))
+	for _, peer := range peers {
+		peerNames[peer.Config["wireguard.public_key"]] = peer.Name
+	}
+
+	state.Wireguard = &api.NetworkStateWireguard{
+		Peers: []api.NetworkStateWireguardPeer{},
+	}
+
+	// The first line describes the interface, followed by one line per peer.
+	for i, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\t")
+
+		if i == 0 {
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("Invalid WireGuard interface state %q", line)
+			}
+
+			state.Wireguard.PublicKey = fields[1]
+			state.Wireguard.ListenPort, _ = strconv.Atoi(fields[2])
+
+			continue
+		}
+
+		if len(fields) < 7 {
+			return nil, fmt.Errorf("Invalid WireGuard peer state %q", line)
+		}
+
+		peer := api.NetworkStateWireguardPeer{
+			Name:      peerNames[fields[0]],
+			PublicKey: fields[0],
+		}
+
+		if fields[2] != "(none)" {
+			peer.Endpoint = fields[2]
+		}
+
+		handshake, _ := strconv.ParseInt(fields[4], 10, 64)
+		if handshake > 0 {
+			peer.LatestHandshake = time.Unix(handshake, 0).UTC()
+		}
+
+		peer.BytesReceived, _ = strconv.ParseUint(fields[5], 10, 64)
+		peer.BytesSent, _ = strconv.ParseUint(fields[6], 10, 64)
+
+		state.Wireguard.Peers = append(state.Wireguard.Peers, peer)
+	}
+
+	return state, nil
+}
+
+// peerValidateWireguard validates the config of a WireGuard peer against the other peers of the network.
+func (n *wireguard) peerValidateWireguard(peerName string, peer *api.NetworkPeerPut) error {
+	rules := map[string]func(value string) error{
+		"wireguard.public_key":           validate.Required(validateWireguardKey),
+		"wireguard.preshared_key":        validate.Optional(validateWireguardKey),
+		"wireguard.endpoint":             validate.Optional(validate.IsListenAddress(true, false, true)),
+		"wireguard.allowed_ips":          validate.Optional(validate.IsListOf(validate.IsNetwork)),
+		"wireguard.persistent_keepalive": validate.Optional(validate.IsUint32),
+	}
+
+	for k, validator := range rules {
+		err := validator(peer.Config[k])
+		if err != nil {
+			return fmt.Errorf("Invalid value for peer option %q: %w", k, err)
+		}
+	}
+
+	for k := range peer.Config {
+		_, found := rules[k]
+		if !found && !internalInstance.IsUserConfig(k) {
+			return fmt.Errorf("Invalid option %q", k)
+		}
+	}
+
+	peers, err := n.peers()
+	if err != nil {
+		return err
+	}
+
+	return wireguardCheckPeerConflicts(peerName, peer.Config, peers)
+}
+
+// wireguardCheckPeerConflicts checks that a WireGuard peer doesn't conflict with the other peers of the network.
+func wireguardCheckPeerConflicts(peerName string, config map[string]string, peers map[int64]*api.NetworkPeer) error {
+	allowedIPs, err := wireguardAllowedIPs(config)
+	if err != nil {
+		return err
+	}
+
+	// WireGuard routes each subnet to a single peer so the peers can't overlap.
+	for _, otherPeer := range peers {
+		if otherPeer.Name == peerName {
+			continue
+		}
+
+		if otherPeer.Config["wireguard.public_key"] == config["wireguard.public_key"] {
+			return api.StatusErrorf(http.StatusConflict, "Peer %q already uses that public key", otherPeer.Name)
+		}
+
+		otherAllowedIPs, err := wireguardAllowedIPs(otherPeer.Config)
+		if err != nil {
+			return err
+		}
+
+		for _, subnet := range allowedIPs {
+			for _, otherSubnet := range otherAllowedIPs {
+				if SubnetContains(subnet, otherSubnet) || SubnetContains(otherSubnet, subnet) {
+					return api.StatusErrorf(http.StatusConflict, "Allowed IPs %q overlap with %q of peer %q", subnet.String(), otherSubnet.String(), otherPeer.Name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// peerNotify applies the peers locally and on the other cluster members.
+func (n *wireguard) peerNotify() error {
+	if n.isRunning() {
+		err := n.setupPeers()
+		if err != nil {
+			return err
+		}
+	}
+
+	notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAlive)
+	if err != nil {
+		return err
+	}
+
+	sendNetwork := api.NetworkPut{
+		Description: n.description,
+		Config:      make(map[string]string),
+	}
+
+	for k, v := range n.config {
+		// Don't forward node specific keys (these will be merged in on recipient node).
+		if slices.Contains(db.NodeSpecificNetworkConfig, k) {
+			continue
+		}
+
+		sendNetwork.Config[k] = v
+	}
+
+	return notifier(func(client incus.InstanceServer) error {
+		return client.UseProject(n.project).UpdateNetwork(n.name, sendNetwork, "")
+	})
+}
+
+// PeerCreate creates a WireGuard peer.
+func (n *wireguard) PeerCreate(peer api.NetworkPeersPost) error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Default type is wireguard.
+	if peer.Type == "" {
+		peer.Type = "wireguard"
+	}
+
+	if peer.Type != "wireguard" {
+		return api.StatusErrorf(http.StatusBadRequest, "Only peers of type %q are supported on WireGuard networks", "wireguard")
+	}
+
+	if peer.TargetProject != "" || peer.TargetNetwork != "" || peer.TargetIntegration != "" {
+		return api.StatusErrorf(http.StatusBadRequest, "WireGuard peers don't have a target network or integration")
+	}
+
+	err := acl.ValidName(peer.Name)
+	if err != nil {
+		return err
+	}
+
+	peers, err := n.peers()
+	if err != nil {
+		return err
+	}
+
+	for _, existingPeer := range peers {
+		if peer.Name == existingPeer.Name {
+			return api.StatusErrorf(http.StatusConflict, "A peer for that name already exists")
+		}
+	}
+
+	err = n.peerValidateWireguard(peer.Name, &peer.NetworkPeerPut)
+	if err != nil {
+		return err
+	}
+
+	var peerID int64
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		peerID, _, err = tx.CreateNetworkPeer(ctx, n.ID(), &peer)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.DeleteNetworkPeer(n.ID(), peerID)
+		_ = n.peerNotify()
+	})
+
+	err = n.peerNotify()
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// PeerUpdate updates a WireGuard peer.
+func (n *wireguard) PeerUpdate(peerName string, req api.NetworkPeerPut) error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	var curPeerID int64
+	var curPeer *api.NetworkPeer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		curPeerID, curPeer, err = tx.GetNetworkPeer(ctx, n.ID(), peerName)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = n.peerValidateWireguard(peerName, &req)
+	if err != nil {
+		return err
+	}
+
+	curPeerEtagHash, err := localUtil.EtagHash(curPeer.Etag())
+	if err != nil {
+		return err
+	}
+
+	newPeer := api.NetworkPeer{
+		Name:           curPeer.Name,
+		NetworkPeerPut: req,
+	}
+
+	newPeerEtagHash, err := localUtil.EtagHash(newPeer.Etag())
+	if err != nil {
+		return err
+	}
+
+	if curPeerEtagHash == newPeerEtagHash {
+		return nil // Nothing has changed.
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateNetworkPeer(ctx, n.ID(), curPeerID, &newPeer.NetworkPeerPut)
+	})
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateNetworkPeer(ctx, n.ID(), curPeerID, &curPeer.NetworkPeerPut)
+		})
+
+		_ = n.peerNotify()
+	})
+
+	err = n.peerNotify()
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// PeerDelete deletes a WireGuard peer.
+func (n *wireguard) PeerDelete(peerName string) error {
+	var peerID int64
+	var peer *api.NetworkPeer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		peerID, peer, err = tx.GetNetworkPeer(ctx, n.ID(), peerName)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	isUsed, err := n.peerIsUsed(peer.Name)
+	if err != nil {
+		return err
+	}
+
+	if isUsed {
+		return fmt.Errorf("Cannot delete a Peer that is in use")
+	}
+
+	err = n.state.DB.Cluster.DeleteNetworkPeer(n.ID(), peerID)
+	if err != nil {
+		return err
+	}
+
+	return n.peerNotify()
+}
@@ -0,0 +1,151 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+const (
+	testWireguardKey1 = "yAnz5TF+lXXJte14tji3zlMNq+hd2rYUIgJBgB3fBmk="
+	testWireguardKey2 = "xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg="
+)
+
+func TestValidateWireguardKey(t *testing.T) {
+	assert.NoError(t, validateWireguardKey(testWireguardKey1))
+	assert.Error(t, validateWireguardKey(""))
+	assert.Error(t, validateWireguardKey("not-base64"))
+	assert.Error(t, validateWireguardKey("c2hvcnQ="))
+}
+
+func TestWireguardConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		peers          map[int64]*api.NetworkPeer
+		expectedConf   string
+		expectedRoutes []string
+	}{
+		{
+			name:  "No peers",
+			peers: map[int64]*api.NetworkPeer{},
+			expectedConf: `[Interface]
+PrivateKey = private
+ListenPort = 51820
+`,
+		},
+		{
+			name: "Peers sorted by name",
+			peers: map[int64]*api.NetworkPeer{
+				1: {
+					Name: "site-b",
+					NetworkPeerPut: api.NetworkPeerPut{Config: map[string]string{
+						"wireguard.public_key":  testWireguardKey2,
+						"wireguard.allowed_ips": "10.1.0.0/24",
+					}},
+				},
+				2: {
+					Name: "site-a",
+					NetworkPeerPut: api.NetworkPeerPut{Config: map[string]string{
+						"wireguard.public_key":           testWireguardKey1,
+						"wireguard.preshared_key":        testWireguardKey2,
+						"wireguard.allowed_ips":          "10.0.0.0/24, fd42::/64",
+						"wireguard.endpoint":             "192.0.2.1:51820",
+						"wireguard.persistent_keepalive": "25",
+					}},
+				},
+			},
+			expectedConf: `[Interface]
+PrivateKey = private
+ListenPort = 51820
+
+[Peer]
+PublicKey = ` + testWireguardKey1 + `
+PresharedKey = ` + testWireguardKey2 + `
+AllowedIPs = 10.0.0.0/24, fd42::/64
+Endpoint = 192.0.2.1:51820
+PersistentKeepalive = 25
+
+[Peer]
+PublicKey = ` + testWireguardKey2 + `
+AllowedIPs = 10.1.0.0/24
+`,
+			expectedRoutes: []string{"10.0.0.0/24", "fd42::/64", "10.1.0.0/24"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, routes := wireguardConfig("private", "51820", tt.peers)
+			assert.Equal(t, tt.expectedConf, conf)
+			assert.Equal(t, tt.expectedRoutes, routes)
+		})
+	}
+}
+
+func TestWireguardCheckPeerConflicts(t *testing.T) {
+	peers := map[int64]*api.NetworkPeer{
+		1: {
+			Name: "site-a",
+			NetworkPeerPut: api.NetworkPeerPut{Config: map[string]string{
+				"wireguard.public_key":  testWireguardKey1,
+				"wireguard.allowed_ips": "10.0.0.0/24,fd42::/64",
+			}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		peerName  string
+		config    map[string]string
+		expectErr bool
+	}{
+		{
+			name:     "Distinct peer",
+			peerName: "site-b",
+			config:   map[string]string{"wireguard.public_key": testWireguardKey2, "wireguard.allowed_ips": "10.1.0.0/24"},
+		},
+		{
+			name:     "Updating the same peer",
+			peerName: "site-a",
+			config:   map[string]string{"wireguard.public_key": testWireguardKey1, "wireguard.allowed_ips": "10.0.0.0/16"},
+		},
+		{
+			name:      "Same public key",
+			peerName:  "site-b",
+			config:    map[string]string{"wireguard.public_key": testWireguardKey1, "wireguard.allowed_ips": "10.1.0.0/24"},
+			expectErr: true,
+		},
+		{
+			name:      "Larger overlapping subnet",
+			peerName:  "site-b",
+			config:    map[string]string{"wireguard.public_key": testWireguardKey2, "wireguard.allowed_ips": "10.0.0.0/16"},
+			expectErr: true,
+		},
+		{
+			name:      "Smaller overlapping IPv6 subnet",
+			peerName:  "site-b",
+			config:    map[string]string{"wireguard.public_key": testWireguardKey2, "wireguard.allowed_ips": "fd42::/80"},
+			expectErr: true,
+		},
+		{
+			name:      "Invalid subnet",
+			peerName:  "site-b",
+			config:    map[string]string{"wireguard.public_key": testWireguardKey2, "wireguard.allowed_ips": "10.1.0.0"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wireguardCheckPeerConflicts(tt.peerName, tt.config, peers)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
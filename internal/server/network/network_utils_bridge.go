@@ -3,7 +3,9 @@ package network
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/lxc/incus/v6/internal/server/ip"
@@ -101,3 +103,19 @@ func DetachInterface(s *state.State, bridgeName string, devName string) error {
 
 	return nil
 }
+
+// BridgeEVPN returns the VXLAN network identifier and local tunnel endpoint of a bridge in EVPN mode.
+// The VNI is 0 when EVPN isn't enabled, the tunnel endpoint is nil when no usable address is configured.
+func BridgeEVPN(config map[string]string) (uint32, net.IP) {
+	vni, err := strconv.ParseUint(config["bgp.evpn.vni"], 10, 32)
+	if err != nil {
+		return 0, nil
+	}
+
+	vtep := net.ParseIP(config["bgp.evpn.local"])
+	if vtep == nil {
+		vtep = net.ParseIP(config["bgp.ipv4.nexthop"])
+	}
+
+	return uint32(vni), vtep.To4()
+}
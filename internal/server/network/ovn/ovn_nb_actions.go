@@ -142,6 +142,14 @@ type OVNACLRule struct {
 	LogName   string // Log label name (requires Log be true).
 }
 
+// OVNQoSRule represents a QoS rule limiting the bandwidth of the matching traffic on a logical switch.
+type OVNQoSRule struct {
+	Direction string // Either "from-lport" or "to-lport".
+	Match     string // Match criteria. See OVN Southbound database's Logical_Flow table match column usage.
+	Priority  int    // Priority (between 0 and 32767, inclusive). Higher values take precedence.
+	Rate      int    // Maximum bandwidth in kbps, traffic exceeding it is dropped.
+}
+
 // OVNLoadBalancerTarget represents an OVN load balancer Virtual IP target.
 type OVNLoadBalancerTarget struct {
 	Address net.IP
@@ -1527,6 +1535,112 @@ func (o *NB) UpdateLogicalSwitchACLRules(ctx context.Context, switchName OVNSwit
 	return nil
 }
 
+// UpdateLogicalSwitchQoSRules replaces the QoS rules belonging to the port group on the logical switch.
+// Accepts a map of string find and replace rules that will be applied to the Match field of each rule.
+// OVN enforces the bandwidth of the QoS rules using meters.
+func (o *NB) UpdateLogicalSwitchQoSRules(ctx context.Context, switchName OVNSwitch, portGroupName OVNPortGroup, matchReplace map[string]string, qosRules ...OVNQoSRule) error {
+	operations := []ovsdb.Operation{}
+
+	// Get the logical switch.
+	ls, err := o.GetLogicalSwitch(ctx, switchName)
+	if err != nil {
+		return err
+	}
+
+	// Remove any existing rules belonging to the port group.
+	existingRules, err := o.portGroupQoSRules(ctx, portGroupName)
+	if err != nil {
+		return err
+	}
+
+	for _, qos := range existingRules {
+		if !slices.Contains(ls.QOSRules, qos.UUID) {
+			continue
+		}
+
+		updateOps, err := o.client.Where(ls).Mutate(ls, ovsModel.Mutation{
+			Field:   &ls.QOSRules,
+			Mutator: ovsdb.MutateOperationDelete,
+			Value:   []string{qos.UUID},
+		})
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, updateOps...)
+	}
+
+	// Add new rules.
+	for i, rule := range qosRules {
+		// Perform any replacements requested on the Match string.
+		for find, replace := range matchReplace {
+			rule.Match = strings.ReplaceAll(rule.Match, find, replace)
+		}
+
+		qos := ovnNB.QoS{
+			UUID:      fmt.Sprintf("qos%d", i),
+			Direction: rule.Direction,
+			Match:     rule.Match,
+			Priority:  rule.Priority,
+			Bandwidth: map[string]int{ovnNB.QoSBandwidthRate: rule.Rate},
+			ExternalIDs: map[string]string{
+				ovnExtIDIncusSwitch:    string(switchName),
+				ovnExtIDIncusPortGroup: string(portGroupName),
+			},
+		}
+
+		createOps, err := o.client.Create(&qos)
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, createOps...)
+
+		updateOps, err := o.client.Where(ls).Mutate(ls, ovsModel.Mutation{
+			Field:   &ls.QOSRules,
+			Mutator: ovsdb.MutateOperationInsert,
+			Value:   []string{qos.UUID},
+		})
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, updateOps...)
+	}
+
+	// Check if we have anything to do.
+	if len(operations) == 0 {
+		return nil
+	}
+
+	// Apply the database changes.
+	resp, err := o.client.Transact(ctx, operations...)
+	if err != nil {
+		return err
+	}
+
+	_, err = ovsdb.CheckOperationResults(resp, operations)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// portGroupQoSRules returns the QoS rules belonging to a port group.
+func (o *NB) portGroupQoSRules(ctx context.Context, portGroupName OVNPortGroup) ([]ovnNB.QoS, error) {
+	qosRules := []ovnNB.QoS{}
+
+	err := o.client.WhereCache(func(qos *ovnNB.QoS) bool {
+		return qos.ExternalIDs != nil && qos.ExternalIDs[ovnExtIDIncusPortGroup] == string(portGroupName)
+	}).List(ctx, &qosRules)
+	if err != nil {
+		return nil, err
+	}
+
+	return qosRules, nil
+}
+
 // logicalSwitchPortACLRules returns the ACL rule UUIDs belonging to a logical switch port.
 func (o *NB) logicalSwitchPortACLRules(ctx context.Context, portName OVNSwitchPort) ([]string, error) {
 	acls := []ovnNB.ACL{}
@@ -2598,6 +2712,29 @@ func (o *NB) DeletePortGroup(ctx context.Context, portGroupNames ...OVNPortGroup
 		}
 
 		operations = append(operations, deleteOps...)
+
+		// Remove the QoS rules belonging to the port group from their logical switches.
+		qosRules, err := o.portGroupQoSRules(ctx, portGroupName)
+		if err != nil {
+			return err
+		}
+
+		for _, qos := range qosRules {
+			ls := ovnNB.LogicalSwitch{
+				Name: qos.ExternalIDs[ovnExtIDIncusSwitch],
+			}
+
+			updateOps, err := o.client.Where(&ls).Mutate(&ls, ovsModel.Mutation{
+				Field:   &ls.QOSRules,
+				Mutator: ovsdb.MutateOperationDelete,
+				Value:   []string{qos.UUID},
+			})
+			if err != nil {
+				return err
+			}
+
+			operations = append(operations, updateOps...)
+		}
 	}
 
 	// Check if we have anything to do.
@@ -3609,6 +3746,80 @@ func (o *NB) UpdateAddressSetRemove(ctx context.Context, addressSetPrefix OVNAdd
 	return nil
 }
 
+// UpdateAddressSet replaces the content of the address sets with the supplied addresses.
+// If the set is missing, it will get automatically created.
+// The address set name used is "<addressSetPrefix>_ip<IP version>", e.g. "foo_ip4".
+func (o *NB) UpdateAddressSet(ctx context.Context, addressSetPrefix OVNAddressSet, addresses ...net.IPNet) error {
+	// Get the address sets.
+	ipv4Set := ovnNB.AddressSet{
+		Name: fmt.Sprintf("%s_ip4", addressSetPrefix),
+	}
+
+	err := o.get(ctx, &ipv4Set)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+
+	ipv6Set := ovnNB.AddressSet{
+		Name: fmt.Sprintf("%s_ip6", addressSetPrefix),
+	}
+
+	err = o.get(ctx, &ipv6Set)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+
+	// Set the addresses.
+	ipv4Set.Addresses = []string{}
+	ipv6Set.Addresses = []string{}
+
+	for _, address := range addresses {
+		if address.IP.To4() == nil {
+			if !slices.Contains(ipv6Set.Addresses, address.String()) {
+				ipv6Set.Addresses = append(ipv6Set.Addresses, address.String())
+			}
+		} else {
+			if !slices.Contains(ipv4Set.Addresses, address.String()) {
+				ipv4Set.Addresses = append(ipv4Set.Addresses, address.String())
+			}
+		}
+	}
+
+	// Prepare the records.
+	operations := []ovsdb.Operation{}
+
+	for _, addressSet := range []*ovnNB.AddressSet{&ipv4Set, &ipv6Set} {
+		if addressSet.UUID == "" {
+			createOps, err := o.client.Create(addressSet)
+			if err != nil {
+				return err
+			}
+
+			operations = append(operations, createOps...)
+		} else {
+			updateOps, err := o.client.Where(addressSet).Update(addressSet)
+			if err != nil {
+				return err
+			}
+
+			operations = append(operations, updateOps...)
+		}
+	}
+
+	// Apply the changes.
+	resp, err := o.client.Transact(ctx, operations...)
+	if err != nil {
+		return err
+	}
+
+	_, err = ovsdb.CheckOperationResults(resp, operations)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DeleteAddressSet deletes address sets for IP versions 4 and 6 in the format "<addressSetPrefix>_ip<IP version>".
 func (o *NB) DeleteAddressSet(ctx context.Context, addressSetPrefix OVNAddressSet) error {
 	// Get the address sets.
@@ -15,6 +15,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/cancel"
@@ -104,6 +105,7 @@ type Operation struct {
 	dbOpType    operationtype.Type
 	requestor   *api.EventLifecycleRequestor
 	logger      logger.Logger
+	span        *tracing.Span
 
 	// Those functions are called at various points in the Operation lifecycle
 	onRun     func(*Operation) error
@@ -183,12 +185,25 @@ func OperationCreate(s *state.State, projectName string, opClass OperationClass,
 		op.SetRequestor(r)
 	}
 
+	// Record the operation as a span, child of the request that created it if any.
+	traceCtx := context.Background()
+	if r != nil {
+		traceCtx = r.Context()
+	}
+
+	_, op.span = tracing.Start(traceCtx, fmt.Sprintf("operation %s", op.description), tracing.SpanKindInternal, map[string]string{
+		"incus.operation.id":    op.id,
+		"incus.operation.class": op.class.String(),
+		"incus.project":         op.projectName,
+	})
+
 	operationsLock.Lock()
 	operations[op.id] = &op
 	operationsLock.Unlock()
 
 	err = registerDBOperation(&op, opType)
 	if err != nil {
+		op.span.Finish(err)
 		return nil, err
 	}
 
@@ -233,8 +248,13 @@ func (op *Operation) done() {
 	op.onCancel = nil
 	op.onConnect = nil
 	op.finished.Cancel()
+	status := op.status
+	err := op.err
 	op.lock.Unlock()
 
+	op.span.SetAttribute("incus.operation.status", status.String())
+	op.span.Finish(err)
+
 	go func() {
 		shutdownCtx := context.Background()
 		if op.state != nil {
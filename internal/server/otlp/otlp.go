@@ -0,0 +1,524 @@
+package otlp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/lxc/incus/v6/internal/server/metrics"
+	"github.com/lxc/incus/v6/internal/server/tracing"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+const (
+	contentType  = "application/json"
+	maxErrMsgLen = 1024
+	scopeName    = "github.com/lxc/incus"
+)
+
+type config struct {
+	batchSize int
+	batchWait time.Duration
+
+	headers  map[string]string
+	instance string
+	location string
+	logLevel string
+	types    []string
+
+	retries    int
+	retryDelay time.Duration
+	timeout    time.Duration
+	url        *url.URL
+}
+
+// MetricsFunc returns the metrics to export.
+type MetricsFunc func(ctx context.Context) (*metrics.MetricSet, error)
+
+// Client represents an OTLP/HTTP client exporting logs, traces and metrics.
+type Client struct {
+	cfg      config
+	client   *http.Client
+	ctx      context.Context
+	quit     chan struct{}
+	once     sync.Once
+	logs     chan logRecord
+	spans    chan span
+	wg       sync.WaitGroup
+	resource resource
+}
+
+// NewClient returns a Client.
+// The logs, traces and metrics are sent to the "/v1/logs", "/v1/traces" and "/v1/metrics" paths of the URL.
+func NewClient(ctx context.Context, u *url.URL, caCert string, headers map[string]string, instance string, location string, logLevel string, types []string) (*Client, error) {
+	client := Client{
+		cfg: config{
+			batchSize:  512,
+			batchWait:  1 * time.Second,
+			headers:    headers,
+			instance:   instance,
+			location:   location,
+			logLevel:   logLevel,
+			retries:    5,
+			retryDelay: 1 * time.Second,
+			timeout:    10 * time.Second,
+			types:      types,
+			url:        u,
+		},
+		client: &http.Client{},
+		ctx:    ctx,
+		logs:   make(chan logRecord, 1024),
+		spans:  make(chan span, 1024),
+		quit:   make(chan struct{}),
+	}
+
+	if caCert != "" {
+		tlsConfig, err := localtls.GetTLSConfigMem("", "", caCert, "", false)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading CA certificate: %w", err)
+		}
+
+		client.client.Transport = &http.Transport{
+			TLSClientConfig: tlsConfig,
+		}
+	} else {
+		client.client = http.DefaultClient
+	}
+
+	client.resource = resource{Attributes: []keyValue{
+		stringAttribute("service.name", "incus"),
+		stringAttribute("service.version", version.Version),
+	}}
+
+	if instance != "" {
+		client.resource.Attributes = append(client.resource.Attributes, stringAttribute("service.instance.id", instance))
+	}
+
+	if location != "" {
+		client.resource.Attributes = append(client.resource.Attributes, stringAttribute("incus.location", location))
+	}
+
+	client.wg.Add(1)
+	go client.run()
+
+	return &client, nil
+}
+
+func (c *Client) run() {
+	logs := []logRecord{}
+	spans := []span{}
+
+	ticker := time.NewTicker(c.cfg.batchWait)
+	defer ticker.Stop()
+
+	defer func() {
+		// Send all pending batches.
+		c.sendLogs(logs)
+		c.sendSpans(spans)
+		c.wg.Done()
+	}()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+
+		case <-c.quit:
+			return
+
+		case record := <-c.logs:
+			logs = append(logs, record)
+			if len(logs) >= c.cfg.batchSize {
+				c.sendLogs(logs)
+				logs = []logRecord{}
+			}
+
+		case s := <-c.spans:
+			spans = append(spans, s)
+			if len(spans) >= c.cfg.batchSize {
+				c.sendSpans(spans)
+				spans = []span{}
+			}
+
+		case <-ticker.C:
+			c.sendLogs(logs)
+			logs = []logRecord{}
+
+			c.sendSpans(spans)
+			spans = []span{}
+		}
+	}
+}
+
+func (c *Client) sendLogs(records []logRecord) {
+	if len(records) == 0 {
+		return
+	}
+
+	data := logsData{ResourceLogs: []resourceLogs{{
+		Resource:  c.resource,
+		ScopeLogs: []scopeLogs{{Scope: c.scope(), LogRecords: records}},
+	}}}
+
+	_ = c.export("/v1/logs", data)
+}
+
+func (c *Client) sendSpans(spans []span) {
+	if len(spans) == 0 {
+		return
+	}
+
+	data := tracesData{ResourceSpans: []resourceSpans{{
+		Resource:   c.resource,
+		ScopeSpans: []scopeSpans{{Scope: c.scope(), Spans: spans}},
+	}}}
+
+	_ = c.export("/v1/traces", data)
+}
+
+func (c *Client) scope() scope {
+	return scope{Name: scopeName, Version: version.Version}
+}
+
+// export sends the data to the given path, retrying on connection errors, 429s and 500s.
+func (c *Client) export(path string, data any) error {
+	buf, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	delay := c.cfg.retryDelay
+
+	for i := 0; ; i++ {
+		var status int
+
+		status, err = c.send(c.ctx, path, buf)
+		if err == nil {
+			return nil
+		}
+
+		// Only retry 429s, 500s and connection-level errors.
+		if status > 0 && status != http.StatusTooManyRequests && status/100 != 5 {
+			return err
+		}
+
+		if i >= c.cfg.retries {
+			return err
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return err
+		case <-c.quit:
+			return err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, path string, buf []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(c.cfg.url.String(), "/")+path, bytes.NewReader(buf))
+	if err != nil {
+		return -1, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	for k, v := range c.cfg.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return -1, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxErrMsgLen))
+		line := ""
+
+		if scanner.Scan() {
+			line = scanner.Text()
+		}
+
+		err = fmt.Errorf("server returned HTTP status %s (%d): %s", resp.Status, resp.StatusCode, line)
+	}
+
+	return resp.StatusCode, err
+}
+
+// Stop the client.
+func (c *Client) Stop() {
+	c.once.Do(func() { close(c.quit) })
+	c.wg.Wait()
+}
+
+// HandleEvent handles the event received from the internal event listener.
+func (c *Client) HandleEvent(event api.Event) {
+	if !slices.Contains(c.cfg.types, event.Type) {
+		return
+	}
+
+	// Support overriding the location field (used on standalone systems).
+	location := event.Location
+	if c.cfg.location != "" {
+		location = c.cfg.location
+	}
+
+	record := logRecord{
+		TimeUnixNano:         unixNano(event.Timestamp),
+		ObservedTimeUnixNano: unixNano(time.Now()),
+	}
+
+	attributes := map[string]string{
+		"incus.event.type": event.Type,
+		"incus.location":   location,
+		"incus.project":    event.Project,
+	}
+
+	if event.Type == api.EventTypeLifecycle {
+		lifecycleEvent := api.EventLifecycle{}
+
+		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+		if err != nil {
+			return
+		}
+
+		if lifecycleEvent.Name != "" {
+			attributes["incus.name"] = lifecycleEvent.Name
+		}
+
+		if lifecycleEvent.Project != "" {
+			attributes["incus.project"] = lifecycleEvent.Project
+		}
+
+		attributes["incus.action"] = lifecycleEvent.Action
+		attributes["incus.source"] = lifecycleEvent.Source
+
+		for k, v := range lifecycleEvent.Context {
+			attributes["incus.context."+k] = fmt.Sprintf("%v", v)
+		}
+
+		if lifecycleEvent.Requestor != nil {
+			attributes["incus.requestor.address"] = lifecycleEvent.Requestor.Address
+			attributes["incus.requestor.protocol"] = lifecycleEvent.Requestor.Protocol
+			attributes["incus.requestor.username"] = lifecycleEvent.Requestor.Username
+		}
+
+		record.SeverityNumber = severityInfo
+		record.SeverityText = "info"
+		record.Body = stringValue(lifecycleEvent.Action)
+	} else if event.Type == api.EventTypeLogging || event.Type == api.EventTypeNetworkACL {
+		logEvent := api.EventLogging{}
+
+		err := json.Unmarshal(event.Metadata, &logEvent)
+		if err != nil {
+			return
+		}
+
+		// The errors can be ignored as the values are validated elsewhere.
+		l1, _ := logrus.ParseLevel(logEvent.Level)
+		l2, _ := logrus.ParseLevel(c.cfg.logLevel)
+
+		// Only consider log messages with a certain log level.
+		if l2 < l1 {
+			return
+		}
+
+		for k, v := range logEvent.Context {
+			attributes["incus.context."+k] = v
+		}
+
+		record.SeverityNumber = severityNumber(l1)
+		record.SeverityText = logEvent.Level
+		record.Body = stringValue(logEvent.Message)
+	} else {
+		return
+	}
+
+	record.Attributes = attributesFromMap(attributes)
+
+	// Drop the record rather than blocking the event listener if the collector can't keep up.
+	select {
+	case c.logs <- record:
+	default:
+	}
+}
+
+// ExportSpan handles the spans received from the tracing package.
+func (c *Client) ExportSpan(s *tracing.Span) {
+	out := span{
+		TraceID:           s.Context.TraceID.String(),
+		SpanID:            s.Context.SpanID.String(),
+		Name:              s.Name,
+		Kind:              int(s.Kind),
+		StartTimeUnixNano: unixNano(s.Start),
+		EndTimeUnixNano:   unixNano(s.End),
+		Attributes:        attributesFromMap(s.Attributes),
+		Status:            spanStatus{Code: statusCodeUnset},
+	}
+
+	if s.Parent.IsValid() {
+		out.ParentSpanID = s.Parent.String()
+	}
+
+	if s.Error != "" {
+		out.Status = spanStatus{Code: statusCodeError, Message: s.Error}
+	}
+
+	select {
+	case c.spans <- out:
+	default:
+	}
+}
+
+// ExportMetrics sends the metric set to the collector.
+func (c *Client) ExportMetrics(metricSet *metrics.MetricSet) error {
+	now := unixNano(time.Now())
+	out := []metric{}
+
+	for _, metricType := range metricSet.Types() {
+		dataPoints := []numberDataPoint{}
+
+		for _, sample := range metricSet.Samples(metricType) {
+			dataPoints = append(dataPoints, numberDataPoint{
+				Attributes:   attributesFromMap(sample.Labels),
+				TimeUnixNano: now,
+				AsDouble:     sample.Value,
+			})
+		}
+
+		name := metrics.MetricNames[metricType]
+
+		m := metric{
+			Name:        name,
+			Description: strings.TrimPrefix(metrics.MetricHeaders[metricType], "# HELP "+name+" "),
+		}
+
+		if metricType.Kind() == "counter" {
+			m.Sum = &sum{DataPoints: dataPoints, AggregationTemporality: aggregationTemporalityCumulative, IsMonotonic: true}
+		} else {
+			m.Gauge = &gauge{DataPoints: dataPoints}
+		}
+
+		out = append(out, m)
+	}
+
+	if len(out) == 0 {
+		return nil
+	}
+
+	data := metricsData{ResourceMetrics: []resourceMetrics{{
+		Resource:     c.resource,
+		ScopeMetrics: []scopeMetrics{{Scope: c.scope(), Metrics: out}},
+	}}}
+
+	return c.export("/v1/metrics", data)
+}
+
+// RunMetrics periodically exports the metrics returned by the gather function until the client is stopped.
+func (c *Client) RunMetrics(interval time.Duration, gather MetricsFunc) {
+	if interval <= 0 {
+		return
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+
+			case <-c.quit:
+				return
+
+			case <-ticker.C:
+				metricSet, err := gather(c.ctx)
+				if err != nil {
+					continue
+				}
+
+				_ = c.ExportMetrics(metricSet)
+			}
+		}
+	}()
+}
+
+// severityNumber converts a log level to an OpenTelemetry severity number.
+func severityNumber(level logrus.Level) int {
+	switch level {
+	case logrus.TraceLevel:
+		return severityTrace
+	case logrus.DebugLevel:
+		return severityDebug
+	case logrus.InfoLevel:
+		return severityInfo
+	case logrus.WarnLevel:
+		return severityWarn
+	case logrus.ErrorLevel:
+		return severityError
+	default:
+		return severityFatal
+	}
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func stringValue(value string) anyValue {
+	return anyValue{StringValue: &value}
+}
+
+func stringAttribute(key string, value string) keyValue {
+	return keyValue{Key: key, Value: stringValue(value)}
+}
+
+// attributesFromMap returns the attributes sorted by key, skipping empty values.
+func attributesFromMap(m map[string]string) []keyValue {
+	keys := make([]string, 0, len(m))
+
+	for k, v := range m {
+		if v == "" {
+			continue
+		}
+
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	attributes := make([]keyValue, 0, len(keys))
+	for _, k := range keys {
+		attributes = append(attributes, stringAttribute(k, m[k]))
+	}
+
+	return attributes
+}
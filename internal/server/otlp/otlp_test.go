@@ -0,0 +1,170 @@
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/metrics"
+	"github.com/lxc/incus/v6/internal/server/tracing"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type collectorRequest struct {
+	path   string
+	header http.Header
+	body   map[string]any
+}
+
+// newCollector starts an OTLP collector stand-in recording the requests it receives.
+func newCollector(t *testing.T) (*httptest.Server, chan collectorRequest) {
+	requests := make(chan collectorRequest, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		body := map[string]any{}
+		require.NoError(t, json.Unmarshal(buf, &body))
+
+		requests <- collectorRequest{path: r.URL.Path, header: r.Header, body: body}
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+func newTestClient(t *testing.T, server *httptest.Server) *Client {
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	c, err := NewClient(context.Background(), u, "", map[string]string{"Authorization": "Bearer token"}, "incus01", "node01", "info", []string{api.EventTypeLifecycle, api.EventTypeLogging})
+	require.NoError(t, err)
+
+	t.Cleanup(c.Stop)
+
+	return c
+}
+
+func receive(t *testing.T, requests chan collectorRequest) collectorRequest {
+	select {
+	case req := <-requests:
+		return req
+	case <-time.After(5 * time.Second):
+		t.Fatal("Nothing was exported")
+	}
+
+	return collectorRequest{}
+}
+
+// lookup follows the path through the decoded JSON document.
+func lookup(t *testing.T, value any, path ...any) any {
+	for _, p := range path {
+		switch key := p.(type) {
+		case string:
+			m, ok := value.(map[string]any)
+			require.True(t, ok, "Expected an object for %q", key)
+			value = m[key]
+		case int:
+			l, ok := value.([]any)
+			require.True(t, ok, "Expected a list for %d", key)
+			require.Greater(t, len(l), key)
+			value = l[key]
+		}
+	}
+
+	return value
+}
+
+func TestClientLogs(t *testing.T) {
+	server, requests := newCollector(t)
+	c := newTestClient(t, server)
+
+	metadata, err := json.Marshal(api.EventLogging{Message: "Hello", Level: "debug"})
+	require.NoError(t, err)
+
+	// Filtered out by the log level.
+	c.HandleEvent(api.Event{Type: api.EventTypeLogging, Timestamp: time.Now(), Metadata: metadata})
+
+	metadata, err = json.Marshal(api.EventLifecycle{Action: "instance-started", Source: "/1.0/instances/c1", Name: "c1", Project: "default"})
+	require.NoError(t, err)
+
+	c.HandleEvent(api.Event{Type: api.EventTypeLifecycle, Timestamp: time.Now(), Metadata: metadata})
+
+	req := receive(t, requests)
+	assert.Equal(t, "/v1/logs", req.path)
+	assert.Equal(t, "Bearer token", req.header.Get("Authorization"))
+
+	resource := lookup(t, req.body, "resourceLogs", 0, "resource", "attributes")
+	assert.Contains(t, resource, map[string]any{"key": "service.instance.id", "value": map[string]any{"stringValue": "incus01"}})
+
+	records := lookup(t, req.body, "resourceLogs", 0, "scopeLogs", 0, "logRecords").([]any)
+	require.Len(t, records, 1)
+	assert.Equal(t, "instance-started", lookup(t, records[0], "body", "stringValue"))
+	assert.Equal(t, float64(severityInfo), lookup(t, records[0], "severityNumber"))
+	assert.Contains(t, lookup(t, records[0], "attributes"), map[string]any{"key": "incus.project", "value": map[string]any{"stringValue": "default"}})
+}
+
+func TestClientSpans(t *testing.T) {
+	server, requests := newCollector(t)
+	c := newTestClient(t, server)
+
+	tracing.SetExporter(c)
+	defer tracing.SetExporter(nil)
+
+	ctx, parent := tracing.Start(context.Background(), "GET /1.0", tracing.SpanKindServer, nil)
+	_, child := tracing.Start(ctx, "operation", tracing.SpanKindInternal, map[string]string{"incus.operation.class": "task"})
+	child.Finish(errors.New("Failed"))
+	parent.Finish(nil)
+
+	req := receive(t, requests)
+	assert.Equal(t, "/v1/traces", req.path)
+
+	spans := lookup(t, req.body, "resourceSpans", 0, "scopeSpans", 0, "spans").([]any)
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "operation", lookup(t, spans[0], "name"))
+	assert.Equal(t, parent.Context.TraceID.String(), lookup(t, spans[0], "traceId"))
+	assert.Equal(t, parent.Context.SpanID.String(), lookup(t, spans[0], "parentSpanId"))
+	assert.Equal(t, float64(statusCodeError), lookup(t, spans[0], "status", "code"))
+	assert.Equal(t, "Failed", lookup(t, spans[0], "status", "message"))
+
+	assert.Equal(t, "GET /1.0", lookup(t, spans[1], "name"))
+	assert.Nil(t, lookup(t, spans[1], "parentSpanId"))
+	assert.Equal(t, float64(tracing.SpanKindServer), lookup(t, spans[1], "kind"))
+}
+
+func TestClientMetrics(t *testing.T) {
+	server, requests := newCollector(t)
+	c := newTestClient(t, server)
+
+	metricSet := metrics.NewMetricSet(map[string]string{"project": "default"})
+	metricSet.AddSamples(metrics.CPUSecondsTotal, metrics.Sample{Value: 12.5, Labels: map[string]string{"cpu": "0"}})
+	metricSet.AddSamples(metrics.MemoryMemFreeBytes, metrics.Sample{Value: 1024})
+
+	require.NoError(t, c.ExportMetrics(metricSet))
+
+	req := receive(t, requests)
+	assert.Equal(t, "/v1/metrics", req.path)
+
+	out := lookup(t, req.body, "resourceMetrics", 0, "scopeMetrics", 0, "metrics").([]any)
+	require.Len(t, out, 2)
+
+	assert.Equal(t, "incus_cpu_seconds_total", lookup(t, out[0], "name"))
+	assert.Equal(t, true, lookup(t, out[0], "sum", "isMonotonic"))
+	assert.Equal(t, 12.5, lookup(t, out[0], "sum", "dataPoints", 0, "asDouble"))
+	assert.Contains(t, lookup(t, out[0], "sum", "dataPoints", 0, "attributes"), map[string]any{"key": "cpu", "value": map[string]any{"stringValue": "0"}})
+
+	assert.Equal(t, "incus_memory_MemFree_bytes", lookup(t, out[1], "name"))
+	assert.Equal(t, float64(1024), lookup(t, out[1], "gauge", "dataPoints", 0, "asDouble"))
+}
@@ -0,0 +1,132 @@
+package otlp
+
+// The types below are the subset of the OTLP/HTTP JSON encoding used by the client.
+// See https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding.
+
+// Severity numbers as defined by the OpenTelemetry logs data model.
+const (
+	severityTrace = 1
+	severityDebug = 5
+	severityInfo  = 9
+	severityWarn  = 13
+	severityError = 17
+	severityFatal = 21
+)
+
+// Span status codes.
+const (
+	statusCodeUnset = 0
+	statusCodeError = 2
+)
+
+// aggregationTemporalityCumulative is used for all the exported counters.
+const aggregationTemporalityCumulative = 2
+
+type anyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type logRecord struct {
+	TimeUnixNano         string     `json:"timeUnixNano"`
+	ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+	SeverityNumber       int        `json:"severityNumber"`
+	SeverityText         string     `json:"severityText,omitempty"`
+	Body                 anyValue   `json:"body"`
+	Attributes           []keyValue `json:"attributes,omitempty"`
+}
+
+type scopeLogs struct {
+	Scope      scope       `json:"scope"`
+	LogRecords []logRecord `json:"logRecords"`
+}
+
+type resourceLogs struct {
+	Resource  resource    `json:"resource"`
+	ScopeLogs []scopeLogs `json:"scopeLogs"`
+}
+
+type logsData struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+type spanStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type span struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            spanStatus `json:"status"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type tracesData struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type numberDataPoint struct {
+	Attributes   []keyValue `json:"attributes,omitempty"`
+	TimeUnixNano string     `json:"timeUnixNano"`
+	AsDouble     float64    `json:"asDouble"`
+}
+
+type gauge struct {
+	DataPoints []numberDataPoint `json:"dataPoints"`
+}
+
+type sum struct {
+	DataPoints             []numberDataPoint `json:"dataPoints"`
+	AggregationTemporality int               `json:"aggregationTemporality"`
+	IsMonotonic            bool              `json:"isMonotonic"`
+}
+
+type metric struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Gauge       *gauge `json:"gauge,omitempty"`
+	Sum         *sum   `json:"sum,omitempty"`
+}
+
+type scopeMetrics struct {
+	Scope   scope    `json:"scope"`
+	Metrics []metric `json:"metrics"`
+}
+
+type resourceMetrics struct {
+	Resource     resource       `json:"resource"`
+	ScopeMetrics []scopeMetrics `json:"scopeMetrics"`
+}
+
+type metricsData struct {
+	ResourceMetrics []resourceMetrics `json:"resourceMetrics"`
+}
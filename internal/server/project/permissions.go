@@ -263,6 +263,11 @@ func AllowVolumeCreation(tx *db.ClusterTx, projectName string, poolName string,
 		return nil
 	}
 
+	err = checkRestrictionsOnVolumeConfig(info.Project, req.Config)
+	if err != nil {
+		return err
+	}
+
 	// If "limits.disk" is not set, there's nothing to do.
 	if info.Project.Config["limits.disk"] == "" {
 		return nil
@@ -723,6 +728,14 @@ func checkRestrictions(project api.Project, instances []api.Instance, profiles [
 				continue
 			}
 
+			if key == "backups.target" {
+				// Scheduled backups written to a storage bucket of the project don't touch the host.
+				target, err := instance.ParseBackupTarget(value)
+				if err == nil && target.Path == "" {
+					continue
+				}
+			}
+
 			if isContainerOrProfile && !allowContainerLowLevel && isContainerLowLevelOptionForbidden(key) {
 				return fmt.Errorf("Use of low-level config %q on %s %q of project %q is forbidden", key, entityTypeLabel, entityName, project.Name)
 			}
@@ -835,6 +848,7 @@ var allAggregateLimits = []string{
 // allRestrictions lists all available 'restrict.*' config keys along with their default setting.
 var allRestrictions = map[string]string{
 	"restricted.backups":                   "block",
+	"restricted.backups.external":          "block",
 	"restricted.cluster.groups":            "",
 	"restricted.cluster.target":            "block",
 	"restricted.containers.nesting":        "block",
@@ -877,6 +891,7 @@ func isContainerLowLevelOptionForbidden(key string) bool {
 	}
 
 	if slices.Contains([]string{
+		"backups.target",
 		"boot.host_shutdown_action",
 		"boot.host_shutdown_timeout",
 		"linux.kernel_modules",
@@ -899,6 +914,7 @@ func isContainerLowLevelOptionForbidden(key string) bool {
 // Return true if a low-level VM option is forbidden.
 func isVMLowLevelOptionForbidden(key string) bool {
 	return slices.Contains([]string{
+		"backups.target",
 		"boot.host_shutdown_action",
 		"boot.host_shutdown_timeout",
 		"limits.memory.hugepages",
@@ -960,6 +976,21 @@ func AllowInstanceUpdate(tx *db.ClusterTx, projectName, instanceName string, req
 	return nil
 }
 
+// Check that a custom volume config doesn't use options that are forbidden in restricted projects.
+func checkRestrictionsOnVolumeConfig(project api.Project, config map[string]string) error {
+	if util.IsFalseOrEmpty(project.Config["restricted"]) {
+		return nil
+	}
+
+	// Scheduled backups written to arbitrary host paths are only allowed in unrestricted projects.
+	target, err := instance.ParseBackupTarget(config["backups.target"])
+	if err == nil && target.Path != "" {
+		return fmt.Errorf("Use of a host path in \"backups.target\" is forbidden in restricted projects")
+	}
+
+	return nil
+}
+
 // AllowVolumeUpdate returns an error if any project-specific limit or
 // restriction is violated when updating an existing custom volume.
 func AllowVolumeUpdate(tx *db.ClusterTx, projectName, volumeName string, req api.StorageVolumePut, currentConfig map[string]string) error {
@@ -972,6 +1003,11 @@ func AllowVolumeUpdate(tx *db.ClusterTx, projectName, volumeName string, req api
 		return nil
 	}
 
+	err = checkRestrictionsOnVolumeConfig(info.Project, req.Config)
+	if err != nil {
+		return err
+	}
+
 	// If "limits.disk" is not set, there's nothing to do.
 	if info.Project.Config["limits.disk"] == "" {
 		return nil
@@ -1630,6 +1666,16 @@ func AllowBackupCreation(tx *db.ClusterTx, projectName string) error {
 	return nil
 }
 
+// AllowExternalBackupBucket returns an error if any project-specific restriction is violated
+// when transferring a backup to or from a bucket on an external S3-compatible endpoint.
+func AllowExternalBackupBucket(p *api.Project) error {
+	if projectHasRestriction(p, "restricted.backups.external", "block") {
+		return fmt.Errorf("Project %q doesn't allow for backups on external S3 endpoints", p.Name)
+	}
+
+	return nil
+}
+
 // AllowSnapshotCreation returns an error if any project-specific restriction is violated
 // when creating a new snapshot in a project.
 func AllowSnapshotCreation(p *api.Project) error {
@@ -249,3 +249,32 @@ func TestCheckClusterTargetRestriction_RestrictedFalse(t *testing.T) {
 	err = project.CheckClusterTargetRestriction(authorizer, req, p, "n1")
 	assert.NoError(t, err)
 }
+
+// External S3 endpoints for backups are only blocked in restricted projects which don't allow them.
+func TestAllowExternalBackupBucket(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    map[string]string
+		expectErr bool
+	}{
+		{name: "Unrestricted", config: map[string]string{}},
+		{name: "Restricted", config: map[string]string{"restricted": "true"}, expectErr: true},
+		{name: "Restricted blocked", config: map[string]string{"restricted": "true", "restricted.backups.external": "block"}, expectErr: true},
+		{name: "Restricted allowed", config: map[string]string{"restricted": "true", "restricted.backups.external": "allow"}},
+		{name: "Unrestricted blocked", config: map[string]string{"restricted": "false", "restricted.backups.external": "block"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &api.Project{Name: "p1", ProjectPut: api.ProjectPut{Config: tt.config}}
+
+			err := project.AllowExternalBackupBucket(p)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
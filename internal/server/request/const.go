@@ -20,6 +20,9 @@ const (
 	// CtxProtocol is the protocol field in request context.
 	CtxProtocol CtxKey = "protocol"
 
+	// CtxIdentityProviderGroups is the identity provider groups field in request context.
+	CtxIdentityProviderGroups CtxKey = "identity_provider_groups"
+
 	// CtxForwardedAddress is the forwarded address field in request context.
 	CtxForwardedAddress CtxKey = "forwarded_address"
 
@@ -28,6 +31,9 @@ const (
 
 	// CtxForwardedProtocol is the forwarded protocol field in request context.
 	CtxForwardedProtocol CtxKey = "forwarded_protocol"
+
+	// CtxForwardedIdentityProviderGroups is the forwarded identity provider groups field in request context.
+	CtxForwardedIdentityProviderGroups CtxKey = "forwarded_identity_provider_groups"
 )
 
 // Headers.
@@ -40,4 +46,7 @@ const (
 
 	// HeaderForwardedProtocol is the forwarded protocol field in request header.
 	HeaderForwardedProtocol = "X-Incus-forwarded-protocol"
+
+	// HeaderForwardedIdentityProviderGroups is the forwarded identity provider groups field in request header.
+	HeaderForwardedIdentityProviderGroups = "X-Incus-forwarded-identity-provider-groups"
 )
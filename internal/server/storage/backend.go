@@ -265,6 +265,15 @@ func (b *backend) GetResources() (*api.ResourcesStoragePool, error) {
 	return b.driver.GetResources()
 }
 
+// Scrub starts verifying the integrity of the data held by the pool, returns false if a scrub was already running.
+func (b *backend) Scrub() (bool, error) {
+	l := b.logger.AddContext(nil)
+	l.Debug("Scrub started")
+	defer l.Debug("Scrub finished")
+
+	return b.driver.Scrub()
+}
+
 // IsUsed returns whether the storage pool is used by any volumes or profiles (excluding image volumes).
 func (b *backend) IsUsed() (bool, error) {
 	usedBy, err := UsedBy(context.TODO(), b.state, b, true, true, db.StoragePoolVolumeTypeNameImage)
@@ -2665,17 +2674,106 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 	l.Debug("BackupInstance started")
 	defer l.Debug("BackupInstance finished")
 
+	vol, snapNames, err := b.instanceBackupVolume(inst, snapshots, op)
+	if err != nil {
+		return err
+	}
+
+	// Have the driver freeze the guest file systems while it takes the snapshot the backup is made from.
+	vol.SetQuiesce(b.instanceFreezeFilesystems(inst))
+
+	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, op)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// BackupInstanceIncremental creates an optimized instance backup and keeps a storage anchor for later
+// incremental backups. If parentAnchor is set, only the changes since that anchor are included.
+func (b *backend) BackupInstanceIncremental(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, snapshots bool, anchor string, parentAnchor string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "snapshots": snapshots, "anchor": anchor, "parentAnchor": parentAnchor})
+	l.Debug("BackupInstanceIncremental started")
+	defer l.Debug("BackupInstanceIncremental finished")
+
+	if !b.driver.Info().IncrementalBackups {
+		return fmt.Errorf("Storage pool %q doesn't support incremental backups", b.name)
+	}
+
+	vol, snapNames, err := b.instanceBackupVolume(inst, snapshots && parentAnchor == "", op)
+	if err != nil {
+		return err
+	}
+
+	// Have the driver freeze the guest file systems while it takes the snapshot the backup is made from.
+	vol.SetQuiesce(b.instanceFreezeFilesystems(inst))
+
+	err = b.driver.BackupVolumeIncremental(vol, tarWriter, anchor, parentAnchor, snapNames, op)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ApplyInstanceBackup applies an incremental backup on top of an existing instance.
+func (b *backend) ApplyInstanceBackup(inst instance.Instance, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "incrementalFrom": srcBackup.IncrementalFrom})
+	l.Debug("ApplyInstanceBackup started")
+	defer l.Debug("ApplyInstanceBackup finished")
+
+	if !b.driver.Info().IncrementalBackups {
+		return fmt.Errorf("Storage pool %q doesn't support incremental backups", b.name)
+	}
+
+	if inst.IsRunning() {
+		return fmt.Errorf("Incremental backups can only be applied to stopped instances")
+	}
+
+	vol, _, err := b.instanceBackupVolume(inst, false, op)
+	if err != nil {
+		return err
+	}
+
+	err = b.driver.ApplyVolumeBackup(vol, srcBackup, srcData, op)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteInstanceBackupAnchor removes the storage anchor kept for incremental backups of an instance.
+func (b *backend) DeleteInstanceBackupAnchor(inst instance.Instance, anchor string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "anchor": anchor})
+	l.Debug("DeleteInstanceBackupAnchor started")
+	defer l.Debug("DeleteInstanceBackupAnchor finished")
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
 	}
 
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	vol := b.GetVolume(volType, InstanceContentType(inst), volStorageName, nil)
+
+	return b.driver.DeleteVolumeBackupAnchor(vol, anchor, op)
+}
+
+// instanceBackupVolume returns the root volume of the instance and the names of the snapshots to back up.
+func (b *backend) instanceBackupVolume(inst instance.Instance, snapshots bool, op *operations.Operation) (drivers.Volume, []string, error) {
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return drivers.Volume{}, nil, err
+	}
+
 	contentType := InstanceContentType(inst)
 
 	// Load storage volume from database.
 	dbVol, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), volType)
 	if err != nil {
-		return err
+		return drivers.Volume{}, nil, err
 	}
 
 	// Generate the effective root device volume for instance.
@@ -2683,13 +2781,13 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 	vol := b.GetVolume(volType, contentType, volStorageName, dbVol.Config)
 	err = b.applyInstanceRootDiskOverrides(inst, &vol)
 	if err != nil {
-		return err
+		return drivers.Volume{}, nil, err
 	}
 
 	// Ensure the backup file reflects current config.
 	err = b.UpdateInstanceBackupFile(inst, snapshots, op)
 	if err != nil {
-		return err
+		return drivers.Volume{}, nil, err
 	}
 
 	var snapNames []string
@@ -2697,7 +2795,7 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 		// Get snapshots in age order, oldest first, and pass names to storage driver.
 		instSnapshots, err := inst.Snapshots()
 		if err != nil {
-			return err
+			return drivers.Volume{}, nil, err
 		}
 
 		snapNames = make([]string, 0, len(instSnapshots))
@@ -2707,12 +2805,34 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 		}
 	}
 
-	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, op)
-	if err != nil {
-		return err
+	return vol, snapNames, nil
+}
+
+// instanceFreezeFilesystems returns a function freezing the guest file systems of a running VM and returning a hook
+// to thaw them, or nil if the instance isn't configured for it. Failing to freeze isn't fatal, the resulting copy
+// is then only crash-consistent.
+func (b *backend) instanceFreezeFilesystems(inst instance.Instance) func() revert.Hook {
+	vm, ok := inst.(instance.VM)
+	if !ok || inst.IsSnapshot() || !inst.IsRunning() || inst.IsFrozen() || util.IsFalseOrEmpty(inst.ExpandedConfig()["agent.fsfreeze"]) {
+		return nil
 	}
 
-	return nil
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+	return func() revert.Hook {
+		thaw, err := vm.FreezeFilesystems()
+		if err != nil {
+			l.Warn("Failed freezing guest file systems", logger.Ctx{"err": err})
+			return func() {}
+		}
+
+		return func() {
+			err := thaw()
+			if err != nil {
+				l.Error("Failed thawing guest file systems", logger.Ctx{"err": err})
+			}
+		}
+	}
 }
 
 // GetInstanceUsage returns the disk usage of the instance's root volume.
@@ -3012,6 +3132,13 @@ func (b *backend) CreateInstanceSnapshot(inst instance.Instance, src instance.In
 
 	revert.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, inst.Name(), volType) })
 
+	// Quiesce the guest file systems ahead of any freezing of the instance itself.
+	freeze := b.instanceFreezeFilesystems(src)
+	if freeze != nil {
+		thaw := freeze()
+		defer thaw()
+	}
+
 	// Some driver backing stores require that running instances be frozen during snapshot.
 	if b.driver.Info().RunningCopyFreeze && src.IsRunning() && !src.IsFrozen() {
 		// Freeze the processes.
@@ -4600,6 +4727,23 @@ func (b *backend) GetBucketURL(bucketName string) *url.URL {
 	return b.driver.GetBucketURL(bucketName)
 }
 
+// GetBucketTransferManager returns a transfer manager using the admin key of a bucket.
+func (b *backend) GetBucketTransferManager(projectName string, bucketName string) (*s3.TransferManager, error) {
+	bucketURL := b.GetBucketURL(bucketName)
+	if bucketURL == nil {
+		return nil, fmt.Errorf("Storage bucket %q isn't reachable over S3", bucketName)
+	}
+
+	bucketKey, err := b.getFirstAdminStorageBucketPoolKey(projectName, bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	transferManager := s3.NewTransferManager(bucketURL, bucketKey.AccessKey, bucketKey.SecretKey)
+
+	return &transferManager, nil
+}
+
 // CreateCustomVolume creates an empty custom volume.
 func (b *backend) CreateCustomVolume(projectName string, volName string, desc string, config map[string]string, contentType drivers.ContentType, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "desc": desc, "config": config, "contentType": contentType})
@@ -14,6 +14,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/internal/server/storage/s3"
 	"github.com/lxc/incus/v6/internal/server/storage/s3/miniod"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
@@ -104,6 +105,10 @@ func (b *mockBackend) ApplyPatch(name string) error {
 	return nil
 }
 
+func (b *mockBackend) Scrub() (bool, error) {
+	return true, nil
+}
+
 func (b *mockBackend) GetVolume(volType drivers.VolumeType, contentType drivers.ContentType, volName string, volConfig map[string]string) drivers.Volume {
 	return drivers.Volume{}
 }
@@ -185,6 +190,18 @@ func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter *instance
 	return nil
 }
 
+func (b *mockBackend) BackupInstanceIncremental(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, snapshots bool, anchor string, parentAnchor string, op *operations.Operation) error {
+	return nil
+}
+
+func (b *mockBackend) ApplyInstanceBackup(inst instance.Instance, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+	return nil
+}
+
+func (b *mockBackend) DeleteInstanceBackupAnchor(inst instance.Instance, anchor string, op *operations.Operation) error {
+	return nil
+}
+
 func (b *mockBackend) GetInstanceUsage(inst instance.Instance) (*VolumeUsage, error) {
 	return nil, nil
 }
@@ -277,6 +294,10 @@ func (b *mockBackend) GetBucketURL(bucketName string) *url.URL {
 	return nil
 }
 
+func (b *mockBackend) GetBucketTransferManager(projectName string, bucketName string) (*s3.TransferManager, error) {
+	return nil, nil
+}
+
 func (b *mockBackend) CreateCustomVolume(projectName string, volName string, desc string, config map[string]string, contentType drivers.ContentType, op *operations.Operation) error {
 	return nil
 }
@@ -325,6 +325,7 @@ func (d *btrfs) Validate(config map[string]string) error {
 	rules := map[string]func(value string) error{
 		"size":                validate.Optional(validate.IsSize),
 		"btrfs.mount_options": validate.IsAny,
+		"scrub.schedule":      validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
 	}
 
 	return d.validatePool(config, rules, nil)
@@ -485,6 +486,28 @@ func (d *btrfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return genericVFSGetResources(d)
 }
 
+// Scrub starts a scrub of the file system, which runs in the background, returns false if one is already running.
+func (d *btrfs) Scrub() (bool, error) {
+	poolMntPoint := GetPoolMountPath(d.name)
+
+	status, err := subprocess.RunCommand("btrfs", "scrub", "status", poolMntPoint)
+	if err != nil {
+		return false, fmt.Errorf("Failed getting scrub status of pool %q: %w", d.name, err)
+	}
+
+	// Recent versions report "Status: running", older ones "scrub started at ... and running for ...".
+	if strings.Contains(status, "running") {
+		return false, nil
+	}
+
+	_, err = subprocess.RunCommand("btrfs", "scrub", "start", poolMntPoint)
+	if err != nil {
+		return false, fmt.Errorf("Failed starting scrub of pool %q: %w", d.name, err)
+	}
+
+	return true, nil
+}
+
 // MigrationType returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *btrfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []localMigration.Type {
 	var rsyncFeatures []string
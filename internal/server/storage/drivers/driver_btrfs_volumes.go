@@ -1743,7 +1743,10 @@ func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWr
 
 	// Create the read-only snapshot.
 	targetVolume := fmt.Sprintf("%s/.backup", tmpInstanceMntPoint)
-	_, err = d.snapshotSubvolume(sourceVolume, targetVolume, true)
+	err = vol.quiesceTask(func() error {
+		_, err := d.snapshotSubvolume(sourceVolume, targetVolume, true)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -263,6 +263,11 @@ func (d *common) ApplyPatch(name string) error {
 	return patch()
 }
 
+// Scrub starts verifying the integrity of the data held by the storage pool.
+func (d *common) Scrub() (bool, error) {
+	return false, ErrNotSupported
+}
+
 // moveGPTAltHeader moves the GPT alternative header to the end of the disk device supplied.
 // If the device supplied is not detected as not being a GPT disk then no action is taken and nil is returned.
 // If the required sgdisk command is not available a warning is logged, but no error is returned, as really it is
@@ -436,6 +441,21 @@ func (d *common) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarW
 	return ErrNotSupported
 }
 
+// BackupVolumeIncremental creates an optimized backup of a volume while keeping a storage anchor.
+func (d *common) BackupVolumeIncremental(vol Volume, tarWriter *instancewriter.InstanceTarWriter, anchor string, parentAnchor string, snapshots []string, op *operations.Operation) error {
+	return ErrNotSupported
+}
+
+// ApplyVolumeBackup applies an incremental backup on top of an existing volume.
+func (d *common) ApplyVolumeBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+	return ErrNotSupported
+}
+
+// DeleteVolumeBackupAnchor removes a storage anchor kept for incremental backups.
+func (d *common) DeleteVolumeBackupAnchor(vol Volume, anchor string, op *operations.Operation) error {
+	return ErrNotSupported
+}
+
 // CreateVolumeSnapshot creates a new snapshot.
 func (d *common) CreateVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
 	return ErrNotSupported
@@ -0,0 +1,212 @@
+package drivers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lxc/incus/v6/internal/linux"
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+type netfs struct {
+	common
+}
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *netfs) load() error {
+	// Register the patches.
+	d.patches = map[string]func() error{
+		"storage_lvm_skipactivation":                         nil,
+		"storage_missing_snapshot_records":                   nil,
+		"storage_delete_old_snapshot_records":                nil,
+		"storage_zfs_drop_block_volume_filesystem_extension": nil,
+		"storage_prefix_bucket_names_with_project":           nil,
+	}
+
+	return nil
+}
+
+// isRemote returns true indicating this driver uses remote storage.
+func (d *netfs) isRemote() bool {
+	return true
+}
+
+// Info returns the pool driver information.
+func (d *netfs) Info() Info {
+	return Info{
+		Name:                         "netfs",
+		Version:                      "1",
+		DefaultVMBlockFilesystemSize: deviceConfig.DefaultVMBlockFilesystemSize,
+		OptimizedImages:              false,
+		PreservesInodes:              false,
+		Remote:                       d.isRemote(),
+		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer},
+		VolumeMultiNode:              d.isRemote(),
+		BlockBacking:                 false,
+		RunningCopyFreeze:            true,
+		DirectIO:                     false,
+		MountedRoot:                  true,
+	}
+}
+
+// FillConfig populates the storage pool's configuration file with the default values.
+func (d *netfs) FillConfig() error {
+	if d.config["netfs.protocol"] == "" {
+		d.config["netfs.protocol"] = netfsProtocolNFS
+	}
+
+	return nil
+}
+
+// Create is called during pool creation and is effectively using an empty driver struct.
+// WARNING: The Create() function cannot rely on any of the struct attributes being set.
+func (d *netfs) Create() error {
+	err := d.FillConfig()
+	if err != nil {
+		return err
+	}
+
+	// Config validation.
+	if d.config["source"] == "" {
+		return fmt.Errorf("Missing required source")
+	}
+
+	if d.config["netfs.source"] != "" && d.config["netfs.source"] != d.config["source"] {
+		return fmt.Errorf("netfs.source must match the source")
+	}
+
+	d.config["netfs.source"] = d.config["source"]
+
+	// Create a temporary mountpoint.
+	mountPath, err := os.MkdirTemp("", "incus_netfs_")
+	if err != nil {
+		return fmt.Errorf("Failed to create temporary directory under: %w", err)
+	}
+
+	defer func() { _ = os.RemoveAll(mountPath) }()
+
+	err = os.Chmod(mountPath, 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to chmod '%s': %w", mountPath, err)
+	}
+
+	mountPoint := filepath.Join(mountPath, "mount")
+
+	err = os.Mkdir(mountPoint, 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create directory '%s': %w", mountPoint, err)
+	}
+
+	// Mount the share.
+	err = d.mountShare(mountPoint)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _, _ = forceUnmount(mountPoint) }()
+
+	// Check that the existing share is empty.
+	ok, _ := internalUtil.PathIsEmpty(mountPoint)
+	if !ok {
+		return fmt.Errorf("Only empty network shares can be used as a storage pool")
+	}
+
+	return nil
+}
+
+// Delete clears any local and remote data related to this driver instance.
+func (d *netfs) Delete(op *operations.Operation) error {
+	// Make sure the share is mounted so its content can be wiped.
+	_, err := d.Mount()
+	if err != nil {
+		return err
+	}
+
+	// On delete, wipe everything in the directory.
+	err = wipeDirectory(GetPoolMountPath(d.name))
+	if err != nil {
+		return err
+	}
+
+	// Make sure the existing pool is unmounted.
+	_, err = d.Unmount()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
+func (d *netfs) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		"netfs.mount_options": validate.IsAny,
+		"netfs.protocol":      validate.Optional(validate.IsOneOf(netfsProtocolNFS, netfsProtocolSMB)),
+		"netfs.source":        validate.IsAny,
+	}
+
+	err := d.validatePool(config, rules, nil)
+	if err != nil {
+		return err
+	}
+
+	// Check that the source is usable with the selected protocol.
+	for _, key := range []string{"source", "netfs.source"} {
+		if config[key] == "" {
+			continue
+		}
+
+		_, _, err = netfsParseSource(config["netfs.protocol"], config[key])
+		if err != nil {
+			return fmt.Errorf("Invalid value for option %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// Update applies any driver changes required from a configuration change.
+func (d *netfs) Update(changedConfig map[string]string) error {
+	_, changed := changedConfig["netfs.protocol"]
+	if changed {
+		return fmt.Errorf("netfs.protocol cannot be changed")
+	}
+
+	_, changed = changedConfig["netfs.source"]
+	if changed {
+		return fmt.Errorf("netfs.source cannot be changed")
+	}
+
+	return nil
+}
+
+// Mount brings up the driver and sets it up to be used.
+func (d *netfs) Mount() (bool, error) {
+	// Check if already mounted.
+	if linux.IsMountPoint(GetPoolMountPath(d.name)) {
+		return false, nil
+	}
+
+	// Mount the pool.
+	err := d.mountShare(GetPoolMountPath(d.name))
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Unmount clears any of the runtime state of the driver.
+func (d *netfs) Unmount() (bool, error) {
+	return forceUnmount(GetPoolMountPath(d.name))
+}
+
+// GetResources returns the pool resource usage information.
+func (d *netfs) GetResources() (*api.ResourcesStoragePool, error) {
+	return genericVFSGetResources(d)
+}
@@ -0,0 +1,114 @@
+package drivers
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/linux"
+)
+
+const (
+	netfsProtocolNFS = "nfs"
+	netfsProtocolSMB = "smb"
+)
+
+// netfsParseSource splits a network share source into its host and path components.
+// NFS sources use the "host:/path" syntax (with IPv6 addresses in square brackets)
+// while SMB sources use the "//host/share[/path]" syntax.
+func netfsParseSource(protocol string, source string) (string, string, error) {
+	if protocol == "" {
+		protocol = netfsProtocolNFS
+	}
+
+	switch protocol {
+	case netfsProtocolNFS:
+		var host, path string
+		var ok bool
+
+		if strings.HasPrefix(source, "[") {
+			host, path, ok = strings.Cut(strings.TrimPrefix(source, "["), "]:")
+		} else {
+			host, path, ok = strings.Cut(source, ":")
+		}
+
+		if !ok || host == "" || !strings.HasPrefix(path, "/") {
+			return "", "", fmt.Errorf("NFS source must be in the form HOST:/PATH")
+		}
+
+		return host, path, nil
+	case netfsProtocolSMB:
+		host, path, ok := strings.Cut(strings.TrimPrefix(source, "//"), "/")
+		if !strings.HasPrefix(source, "//") || !ok || host == "" || path == "" {
+			return "", "", fmt.Errorf("SMB source must be in the form //HOST/SHARE[/PATH]")
+		}
+
+		return host, "/" + path, nil
+	}
+
+	return "", "", fmt.Errorf("Unsupported network file system protocol %q", protocol)
+}
+
+// netfsResolveHost returns the first IP address of the provided host.
+// The kernel clients need the server address to be passed as a mount option.
+func netfsResolveHost(host string) (string, error) {
+	ip := net.ParseIP(host)
+	if ip != nil {
+		return ip.String(), nil
+	}
+
+	addrs, err := net.LookupHost(host)
+	if err != nil {
+		return "", fmt.Errorf("Failed resolving %q: %w", host, err)
+	}
+
+	if len(addrs) == 0 {
+		return "", fmt.Errorf("No address found for %q", host)
+	}
+
+	return addrs[0], nil
+}
+
+// mountShare mounts the pool's network share on the target path.
+func (d *netfs) mountShare(target string) error {
+	protocol := d.config["netfs.protocol"]
+	if protocol == "" {
+		protocol = netfsProtocolNFS
+	}
+
+	source := d.config["netfs.source"]
+	if source == "" {
+		source = d.config["source"]
+	}
+
+	host, _, err := netfsParseSource(protocol, source)
+	if err != nil {
+		return err
+	}
+
+	addr, err := netfsResolveHost(host)
+	if err != nil {
+		return err
+	}
+
+	// Build the options list.
+	var options []string
+	var fsType string
+
+	switch protocol {
+	case netfsProtocolNFS:
+		fsType = "nfs"
+		options = append(options, "addr="+addr)
+	case netfsProtocolSMB:
+		fsType = "cifs"
+		options = append(options, "ip="+addr)
+	}
+
+	if d.config["netfs.mount_options"] != "" {
+		options = append(options, strings.Split(d.config["netfs.mount_options"], ",")...)
+	}
+
+	mountFlags, mountOptions := linux.ResolveMountOptions(options)
+
+	return TryMount(source, target, fsType, mountFlags, mountOptions)
+}
@@ -0,0 +1,41 @@
+package drivers
+
+import (
+	"testing"
+)
+
+func Test_netfsParseSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		source   string
+		wantHost string
+		wantPath string
+		wantErr  bool
+	}{
+		{"NFS with hostname", "nfs", "nfs.example.net:/exports/incus", "nfs.example.net", "/exports/incus", false},
+		{"NFS with default protocol", "", "192.0.2.10:/srv", "192.0.2.10", "/srv", false},
+		{"NFS with IPv6 address", "nfs", "[2001:db8::1]:/exports", "2001:db8::1", "/exports", false},
+		{"NFS without path", "nfs", "nfs.example.net", "", "", true},
+		{"NFS with relative path", "nfs", "nfs.example.net:exports", "", "", true},
+		{"NFS without host", "nfs", ":/exports", "", "", true},
+		{"SMB share", "smb", "//smb.example.net/incus", "smb.example.net", "/incus", false},
+		{"SMB share with path", "smb", "//smb.example.net/share/incus", "smb.example.net", "/share/incus", false},
+		{"SMB without share", "smb", "//smb.example.net", "", "", true},
+		{"SMB with NFS syntax", "smb", "smb.example.net:/share", "", "", true},
+		{"Unknown protocol", "ftp", "ftp.example.net:/share", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, path, err := netfsParseSource(tt.protocol, tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("netfsParseSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if host != tt.wantHost || path != tt.wantPath {
+				t.Errorf("netfsParseSource() = %q, %q, want %q, %q", host, path, tt.wantHost, tt.wantPath)
+			}
+		})
+	}
+}
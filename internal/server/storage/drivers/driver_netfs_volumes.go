@@ -0,0 +1,389 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/lxc/incus/v6/internal/instancewriter"
+	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/internal/rsync"
+	"github.com/lxc/incus/v6/internal/server/backup"
+	"github.com/lxc/incus/v6/internal/server/migration"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// CreateVolume creates an empty volume and can optionally fill it by executing the supplied
+// filler function.
+func (d *netfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Operation) error {
+	if vol.contentType != ContentTypeFS {
+		return ErrNotSupported
+	}
+
+	volPath := vol.MountPath()
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	if util.PathExists(volPath) {
+		return fmt.Errorf("Volume path %q already exists", volPath)
+	}
+
+	// Create the volume itself.
+	err := vol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = os.RemoveAll(volPath) })
+
+	// Run the volume filler function if supplied.
+	err = d.runFiller(vol, "", filler, false)
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// CreateVolumeFromBackup restores a backup tarball onto the storage device.
+func (d *netfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
+	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+}
+
+// CreateVolumeFromCopy provides same-pool volume copying functionality.
+func (d *netfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
+	var err error
+	var srcSnapshots []Volume
+
+	if copySnapshots && !srcVol.IsSnapshot() {
+		// Get the list of snapshots from the source.
+		srcSnapshots, err = srcVol.Snapshots(op)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Run the generic copy.
+	return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
+}
+
+// CreateVolumeFromMigration creates a volume being sent via a migration.
+func (d *netfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
+	if vol.contentType != ContentTypeFS {
+		return ErrNotSupported
+	}
+
+	return genericVFSCreateVolumeFromMigration(d, nil, vol, conn, volTargetArgs, preFiller, op)
+}
+
+// RefreshVolume provides same-pool volume and specific snapshots syncing functionality.
+func (d *netfs) RefreshVolume(vol Volume, srcVol Volume, srcSnapshots []Volume, allowInconsistent bool, op *operations.Operation) error {
+	return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, true, allowInconsistent, op)
+}
+
+// DeleteVolume deletes a volume of the storage device. If any snapshots of the volume remain then
+// this function will return an error.
+func (d *netfs) DeleteVolume(vol Volume, op *operations.Operation) error {
+	snapshots, err := d.VolumeSnapshots(vol, op)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) > 0 {
+		return fmt.Errorf("Cannot remove a volume that has snapshots")
+	}
+
+	volPath := vol.MountPath()
+
+	// If the volume doesn't exist, then nothing more to do.
+	if !util.PathExists(volPath) {
+		return nil
+	}
+
+	// Remove the volume from the storage device.
+	err = forceRemoveAll(volPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed to remove '%s': %w", volPath, err)
+	}
+
+	// Although the volume snapshot directory should already be removed, lets remove it here
+	// to just in case the top-level directory is left.
+	err = deleteParentSnapshotDirIfEmpty(d.name, vol.volType, vol.name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// HasVolume indicates whether a specific volume exists on the storage pool.
+func (d *netfs) HasVolume(vol Volume) (bool, error) {
+	return genericVFSHasVolume(vol)
+}
+
+// ValidateVolume validates the supplied volume config. Optionally removes invalid keys from the volume's config.
+func (d *netfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
+	err := d.validateVolume(vol, nil, removeUnknownKeys)
+	if err != nil {
+		return err
+	}
+
+	sizeBytes, err := units.ParseByteSizeString(vol.config["size"])
+	if err != nil {
+		return err
+	}
+
+	if sizeBytes > 0 {
+		return fmt.Errorf("Size cannot be specified for volumes on netfs pools: %w", ErrNotSupported)
+	}
+
+	return nil
+}
+
+// UpdateVolume applies config changes to the volume.
+func (d *netfs) UpdateVolume(vol Volume, changedConfig map[string]string) error {
+	newSize, sizeChanged := changedConfig["size"]
+	if sizeChanged {
+		err := d.SetVolumeQuota(vol, newSize, false, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetVolumeUsage returns the disk space used by the volume.
+// Network file systems don't provide per-directory accounting so this isn't supported.
+func (d *netfs) GetVolumeUsage(vol Volume) (int64, error) {
+	return -1, ErrNotSupported
+}
+
+// SetVolumeQuota applies a size limit on volume.
+// Network file systems don't support project quotas, so only removing the size limit is accepted.
+func (d *netfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
+	sizeBytes, err := units.ParseByteSizeString(size)
+	if err != nil {
+		return err
+	}
+
+	if sizeBytes > 0 {
+		return ErrNotSupported
+	}
+
+	return nil
+}
+
+// GetVolumeDiskPath returns the location of a disk volume.
+func (d *netfs) GetVolumeDiskPath(vol Volume) (string, error) {
+	return "", ErrNotSupported
+}
+
+// ListVolumes returns a list of volumes in storage pool.
+func (d *netfs) ListVolumes() ([]Volume, error) {
+	return genericVFSListVolumes(d)
+}
+
+// MountVolume simulates mounting a volume.
+func (d *netfs) MountVolume(vol Volume, op *operations.Operation) error {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	// Don't attempt to modify the permission of an existing custom volume root.
+	// A user inside the instance may have modified this and we don't want to reset it on restart.
+	if !util.PathExists(vol.MountPath()) || vol.volType != VolumeTypeCustom {
+		err := vol.EnsureMountPath()
+		if err != nil {
+			return err
+		}
+	}
+
+	vol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolume() when done.
+	return nil
+}
+
+// UnmountVolume simulates unmounting a volume.
+// As driver doesn't have volumes to unmount it returns false indicating the volume was already unmounted.
+func (d *netfs) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operation) (bool, error) {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return false, err
+	}
+
+	defer unlock()
+
+	refCount := vol.MountRefCountDecrement()
+	if refCount > 0 {
+		d.logger.Debug("Skipping unmount as in use", logger.Ctx{"volName": vol.name, "refCount": refCount})
+		return false, ErrInUse
+	}
+
+	return false, nil
+}
+
+// RenameVolume renames a volume and its snapshots.
+func (d *netfs) RenameVolume(vol Volume, newVolName string, op *operations.Operation) error {
+	return genericVFSRenameVolume(d, vol, newVolName, op)
+}
+
+// MigrateVolume sends a volume for migration.
+func (d *netfs) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *migration.VolumeSourceArgs, op *operations.Operation) error {
+	return genericVFSMigrateVolume(d, d.state, vol, conn, volSrcArgs, op)
+}
+
+// BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
+// This driver does not support optimized backups.
+func (d *netfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+}
+
+// CreateVolumeSnapshot creates a snapshot of a volume.
+func (d *netfs) CreateVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	parentName, _, _ := api.GetParentAndSnapshotName(snapVol.name)
+
+	// Create snapshot directory.
+	err := snapVol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	snapPath := snapVol.MountPath()
+	revert.Add(func() { _ = os.RemoveAll(snapPath) })
+
+	bwlimit := d.config["rsync.bwlimit"]
+	srcPath := GetVolumeMountPath(d.name, snapVol.volType, parentName)
+	d.Logger().Debug("Copying fileystem volume", logger.Ctx{"sourcePath": srcPath, "targetPath": snapPath, "bwlimit": bwlimit})
+
+	// Copy filesystem volume into snapshot directory.
+	_, err = rsync.LocalCopy(srcPath, snapPath, bwlimit, true)
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// DeleteVolumeSnapshot removes a snapshot from the storage device. The volName and snapshotName
+// must be bare names and should not be in the format "volume/snapshot".
+func (d *netfs) DeleteVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	snapPath := snapVol.MountPath()
+
+	// Remove the snapshot from the storage device.
+	err := forceRemoveAll(snapPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed to remove '%s': %w", snapPath, err)
+	}
+
+	parentName, _, _ := api.GetParentAndSnapshotName(snapVol.name)
+
+	// Remove the parent snapshot directory if this is the last snapshot being removed.
+	err = deleteParentSnapshotDirIfEmpty(d.name, snapVol.volType, parentName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MountVolumeSnapshot sets up a read-only mount on top of the snapshot to avoid accidental modifications.
+func (d *netfs) MountVolumeSnapshot(snapVol Volume, op *operations.Operation) error {
+	unlock, err := snapVol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	snapPath := snapVol.MountPath()
+
+	// Don't attempt to modify the permission of an existing custom volume root.
+	// A user inside the instance may have modified this and we don't want to reset it on restart.
+	if !util.PathExists(snapPath) || snapVol.volType != VolumeTypeCustom {
+		err := snapVol.EnsureMountPath()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = mountReadOnly(snapPath, snapPath)
+	if err != nil {
+		return err
+	}
+
+	snapVol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolumeSnapshot() when done.
+	return nil
+}
+
+// UnmountVolumeSnapshot removes the read-only mount placed on top of a snapshot.
+func (d *netfs) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (bool, error) {
+	unlock, err := snapVol.MountLock()
+	if err != nil {
+		return false, err
+	}
+
+	defer unlock()
+
+	mountPath := snapVol.MountPath()
+
+	refCount := snapVol.MountRefCountDecrement()
+
+	if linux.IsMountPoint(mountPath) {
+		if refCount > 0 {
+			d.logger.Debug("Skipping unmount as in use", logger.Ctx{"volName": snapVol.name, "refCount": refCount})
+			return false, ErrInUse
+		}
+
+		return forceUnmount(mountPath)
+	}
+
+	return false, nil
+}
+
+// VolumeSnapshots returns a list of snapshots for the volume (in no particular order).
+func (d *netfs) VolumeSnapshots(vol Volume, op *operations.Operation) ([]string, error) {
+	return genericVFSVolumeSnapshots(d, vol, op)
+}
+
+// RestoreVolume restores a volume from a snapshot.
+func (d *netfs) RestoreVolume(vol Volume, snapshotName string, op *operations.Operation) error {
+	snapVol, err := vol.NewSnapshot(snapshotName)
+	if err != nil {
+		return err
+	}
+
+	srcPath := snapVol.MountPath()
+	if !util.PathExists(srcPath) {
+		return fmt.Errorf("Snapshot not found")
+	}
+
+	// Restore using rsync.
+	bwlimit := d.config["rsync.bwlimit"]
+	_, err = rsync.LocalCopy(srcPath, vol.MountPath(), bwlimit, true)
+	if err != nil {
+		return fmt.Errorf("Failed to rsync volume: %w", err)
+	}
+
+	return nil
+}
+
+// RenameVolumeSnapshot renames a volume snapshot.
+func (d *netfs) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op *operations.Operation) error {
+	return genericVFSRenameVolumeSnapshot(d, snapVol, newSnapshotName, op)
+}
@@ -137,6 +137,7 @@ func (d *zfs) Info() Info {
 		DefaultVMBlockFilesystemSize: deviceConfig.DefaultVMBlockFilesystemSize,
 		OptimizedImages:              true,
 		OptimizedBackups:             true,
+		IncrementalBackups:           true,
 		PreservesInodes:              true,
 		Remote:                       d.isRemote(),
 		VolumeTypes:                  []VolumeType{VolumeTypeBucket, VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
@@ -463,8 +464,9 @@ func (d *zfs) Delete(op *operations.Operation) error {
 // Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
 func (d *zfs) Validate(config map[string]string) error {
 	rules := map[string]func(value string) error{
-		"size":          validate.Optional(validate.IsSize),
-		"zfs.pool_name": validate.IsAny,
+		"size":           validate.Optional(validate.IsSize),
+		"scrub.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+		"zfs.pool_name":  validate.IsAny,
 		"zfs.clone_copy": validate.Optional(func(value string) error {
 			if value == "rebase" {
 				return nil
@@ -637,6 +639,27 @@ func (d *zfs) Unmount() (bool, error) {
 	return true, nil
 }
 
+// Scrub starts a scrub of the zpool, which runs in the background, returns false if one is already running.
+func (d *zfs) Scrub() (bool, error) {
+	poolName := strings.Split(d.config["zfs.pool_name"], "/")[0]
+
+	status, err := subprocess.RunCommand("zpool", "status", poolName)
+	if err != nil {
+		return false, fmt.Errorf("Failed getting status of zpool %q: %w", poolName, err)
+	}
+
+	if strings.Contains(status, "scrub in progress") {
+		return false, nil
+	}
+
+	_, err = subprocess.RunCommand("zpool", "scrub", poolName)
+	if err != nil {
+		return false, fmt.Errorf("Failed starting scrub of zpool %q: %w", poolName, err)
+	}
+
+	return true, nil
+}
+
 func (d *zfs) GetResources() (*api.ResourcesStoragePool, error) {
 	// Get the total amount of space.
 	availableStr, err := d.getDatasetProperty(d.config["zfs.pool_name"], "available")
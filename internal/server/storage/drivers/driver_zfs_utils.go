@@ -472,3 +472,38 @@ func (d *zfs) delegateDataset(vol Volume, pid int) error {
 func ZFSSupportsDelegation() bool {
 	return zfsDelegate
 }
+
+// zfsSubsequentSnapshots returns the snapshots following the given one in the list of snapshot entries.
+// It fails if internal snapshots or incremental backup anchors follow it as those must be kept.
+func zfsSubsequentSnapshots(entries []string, snapshotName string) ([]string, error) {
+	idx := -1
+	snapshots := []string{}
+	for i, entry := range entries {
+		if entry == fmt.Sprintf("@snapshot-%s", snapshotName) {
+			// Located the current snapshot.
+			idx = i
+			continue
+		} else if idx < 0 {
+			// Skip any previous snapshot.
+			continue
+		}
+
+		if strings.HasPrefix(entry, "@snapshot-") {
+			// Located a normal snapshot following ours.
+			snapshots = append(snapshots, strings.TrimPrefix(entry, "@snapshot-"))
+			continue
+		}
+
+		if strings.HasPrefix(entry, "@backup-") {
+			// Located an incremental backup anchor, still referenced by a backup.
+			return nil, fmt.Errorf("Snapshot %q cannot be restored due to subsequent backup(s) kept for incremental backups", snapshotName)
+		}
+
+		if strings.HasPrefix(entry, "@") {
+			// Located an internal snapshot.
+			return nil, fmt.Errorf("Snapshot %q cannot be restored due to subsequent internal snapshot(s) (from a copy)", snapshotName)
+		}
+	}
+
+	return snapshots, nil
+}
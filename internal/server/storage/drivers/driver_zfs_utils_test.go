@@ -0,0 +1,61 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZFSSubsequentSnapshots(t *testing.T) {
+	tests := []struct {
+		name      string
+		entries   []string
+		snapshot  string
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "Latest snapshot",
+			entries:  []string{"@snapshot-snap0", "@snapshot-snap1"},
+			snapshot: "snap1",
+			expected: []string{},
+		},
+		{
+			name:     "Older snapshot",
+			entries:  []string{"@snapshot-snap0", "@snapshot-snap1", "@snapshot-snap2"},
+			snapshot: "snap0",
+			expected: []string{"snap1", "snap2"},
+		},
+		{
+			name:     "Older incremental backup anchor",
+			entries:  []string{"@backup-0a1b", "@snapshot-snap0", "@snapshot-snap1"},
+			snapshot: "snap0",
+			expected: []string{"snap1"},
+		},
+		{
+			name:      "Subsequent incremental backup anchor",
+			entries:   []string{"@snapshot-snap0", "@backup-0a1b", "@snapshot-snap1"},
+			snapshot:  "snap0",
+			expectErr: true,
+		},
+		{
+			name:      "Subsequent internal snapshot",
+			entries:   []string{"@snapshot-snap0", "@copy-0a1b"},
+			snapshot:  "snap0",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshots, err := zfsSubsequentSnapshots(tt.entries, tt.snapshot)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, snapshots)
+		})
+	}
+}
@@ -394,48 +394,6 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 	// Only execute the revert function if we have had an error internally.
 	revert.Add(revertHook)
 
-	// Define function to unpack a volume from a backup tarball file.
-	unpackVolume := func(v Volume, r io.ReadSeeker, unpacker []string, srcFile string, target string) error {
-		d.Logger().Debug("Unpacking optimized volume", logger.Ctx{"source": srcFile, "target": target})
-
-		targetPath := fmt.Sprintf("%s/storage-pools/%s", internalUtil.VarPath(""), target)
-		tr, cancelFunc, err := archive.CompressedTarReader(context.Background(), r, unpacker, targetPath)
-		if err != nil {
-			return err
-		}
-
-		defer cancelFunc()
-
-		for {
-			hdr, err := tr.Next()
-			if err == io.EOF {
-				break // End of archive.
-			}
-
-			if err != nil {
-				return err
-			}
-
-			if hdr.Name == srcFile {
-				// Extract the backup.
-				if v.ContentType() == ContentTypeBlock || d.isBlockBacked(v) {
-					err = subprocess.RunCommandWithFds(context.TODO(), tr, nil, "zfs", "receive", "-F", target)
-				} else {
-					err = subprocess.RunCommandWithFds(context.TODO(), tr, nil, "zfs", "receive", "-x", "mountpoint", "-F", target)
-				}
-
-				if err != nil {
-					return err
-				}
-
-				cancelFunc()
-				return nil
-			}
-		}
-
-		return fmt.Errorf("Could not find %q", srcFile)
-	}
-
 	var postHook VolumePostHook
 
 	// Create a list of actual volumes to unpack.
@@ -481,7 +439,7 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 
 			srcFile := fmt.Sprintf("backup/%s/%s", prefix, fileName)
 			dstSnapshot := fmt.Sprintf("%s@snapshot-%s", d.dataset(v, false), snapName)
-			err = unpackVolume(v, srcData, unpacker, srcFile, dstSnapshot)
+			err = d.unpackOptimizedBackupFile(v, srcData, unpacker, srcFile, dstSnapshot)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -499,7 +457,7 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 			fileName = "volume.bin"
 		}
 
-		err = unpackVolume(v, srcData, unpacker, fmt.Sprintf("backup/%s", fileName), d.dataset(v, false))
+		err = d.unpackOptimizedBackupFile(v, srcData, unpacker, fmt.Sprintf("backup/%s", fileName), d.dataset(v, false))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -516,6 +474,11 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 				continue
 			}
 
+			// Keep the anchor needed to apply later incremental backups.
+			if srcBackup.Anchor != "" && entry == fmt.Sprintf("@backup-%s", srcBackup.Anchor) {
+				continue
+			}
+
 			if strings.Contains(entry, "@") {
 				_, err := subprocess.RunCommand("zfs", "destroy", fmt.Sprintf("%s%s", d.dataset(v, false), entry))
 				if err != nil {
@@ -568,6 +531,123 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 	return postHook, cleanup, nil
 }
 
+// unpackOptimizedBackupFile receives the ZFS stream stored as srcFile in the backup tarball into target.
+func (d *zfs) unpackOptimizedBackupFile(v Volume, r io.ReadSeeker, unpacker []string, srcFile string, target string) error {
+	d.Logger().Debug("Unpacking optimized volume", logger.Ctx{"source": srcFile, "target": target})
+
+	targetPath := fmt.Sprintf("%s/storage-pools/%s", internalUtil.VarPath(""), target)
+	tr, cancelFunc, err := archive.CompressedTarReader(context.Background(), r, unpacker, targetPath)
+	if err != nil {
+		return err
+	}
+
+	defer cancelFunc()
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break // End of archive.
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if hdr.Name == srcFile {
+			// Extract the backup.
+			if v.ContentType() == ContentTypeBlock || d.isBlockBacked(v) {
+				err = subprocess.RunCommandWithFds(context.TODO(), tr, nil, "zfs", "receive", "-F", target)
+			} else {
+				err = subprocess.RunCommandWithFds(context.TODO(), tr, nil, "zfs", "receive", "-x", "mountpoint", "-F", target)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			cancelFunc()
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Could not find %q", srcFile)
+}
+
+// ApplyVolumeBackup applies an incremental backup on top of an existing volume.
+// The volume must hold the anchor of the backup the incremental backup was taken from.
+func (d *zfs) ApplyVolumeBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+	if srcBackup.IncrementalFrom == "" || srcBackup.OptimizedStorage == nil || !*srcBackup.OptimizedStorage {
+		return fmt.Errorf("Only optimized incremental backups can be applied to an existing volume")
+	}
+
+	// Create a list of actual volumes to apply the backup to.
+	var vols []Volume
+	if vol.IsVMBlock() {
+		vols = append(vols, vol.NewVMBlockFilesystemVolume())
+	}
+
+	vols = append(vols, vol)
+
+	// Check that all volumes hold the expected anchor before changing anything.
+	for _, v := range vols {
+		parentAnchor := fmt.Sprintf("%s@backup-%s", d.dataset(v, false), srcBackup.IncrementalFrom)
+
+		exists, err := d.datasetExists(parentAnchor)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return fmt.Errorf("Volume %q doesn't hold the anchor of the backup this incremental backup is based on", v.name)
+		}
+	}
+
+	for _, v := range vols {
+		// Find the compression algorithm used for backup source data.
+		_, err := srcData.Seek(0, io.SeekStart)
+		if err != nil {
+			return err
+		}
+
+		_, _, unpacker, err := archive.DetectCompressionFile(srcData)
+		if err != nil {
+			return err
+		}
+
+		// The volume must not be in use while receiving the stream.
+		if v.contentType == ContentTypeFS {
+			_, err = d.UnmountVolume(v, false, op)
+			if err != nil {
+				return err
+			}
+		}
+
+		fileName := "container.bin"
+		if v.volType == VolumeTypeVM {
+			if v.contentType == ContentTypeFS {
+				fileName = "virtual-machine-config.bin"
+			} else {
+				fileName = "virtual-machine.bin"
+			}
+		} else if v.volType == VolumeTypeCustom {
+			fileName = "volume.bin"
+		}
+
+		err = d.unpackOptimizedBackupFile(v, srcData, unpacker, fmt.Sprintf("backup/%s", fileName), d.dataset(v, false))
+		if err != nil {
+			return err
+		}
+
+		// The previous anchor isn't needed anymore as the next incremental backup will be based on the new one.
+		_, err = subprocess.RunCommand("zfs", "destroy", fmt.Sprintf("%s@backup-%s", d.dataset(v, false), srcBackup.IncrementalFrom))
+		if err != nil {
+			d.logger.Warn("Failed deleting previous backup anchor", logger.Ctx{"volume": v.name, "anchor": srcBackup.IncrementalFrom, "err": err})
+		}
+	}
+
+	return nil
+}
+
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
 func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
 	var err error
@@ -2738,7 +2818,67 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 		return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
 	}
 
-	// Optimized backup.
+	return d.backupVolumeOptimized(vol, tarWriter, "", "", snapshots, op)
+}
+
+// BackupVolumeIncremental creates an optimized backup of a volume and keeps its source snapshot as an anchor.
+// When parentAnchor is set, only the changes since that anchor are exported and snapshots are skipped.
+func (d *zfs) BackupVolumeIncremental(vol Volume, tarWriter *instancewriter.InstanceTarWriter, anchor string, parentAnchor string, snapshots []string, op *operations.Operation) error {
+	if anchor == "" {
+		return fmt.Errorf("An anchor name is required for incremental backups")
+	}
+
+	return d.backupVolumeOptimized(vol, tarWriter, anchor, parentAnchor, snapshots, op)
+}
+
+// DeleteVolumeBackupAnchor removes a storage anchor kept for incremental backups.
+func (d *zfs) DeleteVolumeBackupAnchor(vol Volume, anchor string, op *operations.Operation) error {
+	var vols []Volume
+	if vol.IsVMBlock() {
+		vols = append(vols, vol.NewVMBlockFilesystemVolume())
+	}
+
+	vols = append(vols, vol)
+
+	for _, v := range vols {
+		anchorDataset := fmt.Sprintf("%s@backup-%s", d.dataset(v, false), anchor)
+
+		exists, err := d.datasetExists(anchorDataset)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			continue
+		}
+
+		// Delete snapshot (or mark for deferred deletion if cannot be deleted currently).
+		_, err = subprocess.RunCommand("zfs", "destroy", "-r", "-d", anchorDataset)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backupVolumeOptimized writes ZFS streams of the volume and its snapshots to the tarball.
+// If anchor is set, the snapshot used to export the volume is kept for later incremental backups.
+// If parentAnchor is set, only the changes since that anchor are exported.
+func (d *zfs) backupVolumeOptimized(vol Volume, tarWriter *instancewriter.InstanceTarWriter, anchor string, parentAnchor string, snapshots []string, op *operations.Operation) error {
+	if parentAnchor != "" {
+		// Incremental backups only carry the changes to the volume itself.
+		snapshots = nil
+
+		exists, err := d.datasetExists(fmt.Sprintf("%s@backup-%s", d.dataset(vol, false), parentAnchor))
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return fmt.Errorf("The anchor of the backup to base the incremental backup on is missing")
+		}
+	}
 
 	if len(snapshots) > 0 {
 		// Check requested snapshot match those in storage.
@@ -2751,7 +2891,7 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 	// Backup VM config volumes first.
 	if vol.IsVMBlock() {
 		fsVol := vol.NewVMBlockFilesystemVolume()
-		err := d.BackupVolume(fsVol, tarWriter, optimized, snapshots, op)
+		err := d.backupVolumeOptimized(fsVol, tarWriter, anchor, parentAnchor, snapshots, op)
 		if err != nil {
 			return err
 		}
@@ -2812,6 +2952,10 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 
 	// Handle snapshots.
 	finalParent := ""
+	if parentAnchor != "" {
+		finalParent = fmt.Sprintf("%s@backup-%s", d.dataset(vol, false), parentAnchor)
+	}
+
 	if len(snapshots) > 0 {
 		for i, snapName := range snapshots {
 			snapshot, _ := vol.NewSnapshot(snapName)
@@ -2845,14 +2989,27 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 		}
 	}
 
-	// Create a temporary read-only snapshot.
-	srcSnapshot := fmt.Sprintf("%s@backup-%s", d.dataset(vol, false), uuid.New().String())
-	_, err := subprocess.RunCommand("zfs", "snapshot", "-r", srcSnapshot)
+	// Create a read-only snapshot, temporary unless it's kept as an anchor for incremental backups.
+	keepAnchor := anchor != ""
+	if !keepAnchor {
+		anchor = uuid.New().String()
+	}
+
+	srcSnapshot := fmt.Sprintf("%s@backup-%s", d.dataset(vol, false), anchor)
+	err := vol.quiesceTask(func() error {
+		_, err := subprocess.RunCommand("zfs", "snapshot", "-r", srcSnapshot)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
+	success := false
 	defer func() {
+		if success && keepAnchor {
+			return
+		}
+
 		// Delete snapshot (or mark for deferred deletion if cannot be deleted currently).
 		_, err := subprocess.RunCommand("zfs", "destroy", "-r", "-d", srcSnapshot)
 		if err != nil {
@@ -2877,6 +3034,8 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 		return err
 	}
 
+	success = true
+
 	return nil
 }
 
@@ -3315,28 +3474,9 @@ func (d *zfs) restoreVolume(vol Volume, snapshotName string, migration bool, op
 	}
 
 	// Check if more recent snapshots exist.
-	idx := -1
-	snapshots := []string{}
-	for i, entry := range entries {
-		if entry == fmt.Sprintf("@snapshot-%s", snapshotName) {
-			// Located the current snapshot.
-			idx = i
-			continue
-		} else if idx < 0 {
-			// Skip any previous snapshot.
-			continue
-		}
-
-		if strings.HasPrefix(entry, "@snapshot-") {
-			// Located a normal snapshot following ours.
-			snapshots = append(snapshots, strings.TrimPrefix(entry, "@snapshot-"))
-			continue
-		}
-
-		if strings.HasPrefix(entry, "@") {
-			// Located an internal snapshot.
-			return fmt.Errorf("Snapshot %q cannot be restored due to subsequent internal snapshot(s) (from a copy)", snapshotName)
-		}
+	snapshots, err := zfsSubsequentSnapshots(entries, snapshotName)
+	if err != nil {
+		return err
 	}
 
 	// Check if snapshot removal is allowed.
@@ -11,6 +11,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/lxc/incus/v6/internal/instancewriter"
 	"github.com/lxc/incus/v6/internal/linux"
 	"github.com/lxc/incus/v6/internal/migration"
@@ -688,7 +690,30 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 		prefix = "backup/volume"
 	}
 
-	err := backupVolume(vol, prefix)
+	srcVol := vol
+	if vol.quiesce != nil {
+		// Copy the volume from a temporary snapshot so its users are only quiesced while that snapshot is taken.
+		snapVol, err := vol.NewSnapshot(fmt.Sprintf("backup-%s", uuid.New().String()))
+		if err != nil {
+			return err
+		}
+
+		err = vol.quiesceTask(func() error { return d.CreateVolumeSnapshot(snapVol, op) })
+		if err != nil {
+			return fmt.Errorf("Failed creating temporary snapshot for backup: %w", err)
+		}
+
+		defer func() {
+			err := d.DeleteVolumeSnapshot(snapVol, op)
+			if err != nil {
+				d.Logger().Warn("Failed deleting temporary snapshot for backup", logger.Ctx{"snapshot": snapVol.Name(), "err": err})
+			}
+		}()
+
+		srcVol = snapVol
+	}
+
+	err := backupVolume(srcVol, prefix)
 	if err != nil {
 		return err
 	}
@@ -50,6 +50,10 @@ type Driver interface {
 	Update(changedConfig map[string]string) error
 	ApplyPatch(name string) error
 
+	// Scrub starts verifying the integrity of the data held by the storage pool, returns false if a scrub
+	// was already running.
+	Scrub() (bool, error)
+
 	// Buckets.
 	ValidateBucket(bucket Volume) error
 	GetBucketURL(bucketName string) *url.URL
@@ -109,4 +113,9 @@ type Driver interface {
 	// Backup.
 	BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error
 	CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error)
+
+	// Incremental backup.
+	BackupVolumeIncremental(vol Volume, tarWriter *instancewriter.InstanceTarWriter, anchor string, parentAnchor string, snapshots []string, op *operations.Operation) error
+	ApplyVolumeBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error
+	DeleteVolumeBackupAnchor(vol Volume, anchor string, op *operations.Operation) error
 }
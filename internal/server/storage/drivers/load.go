@@ -13,6 +13,7 @@ var drivers = map[string]func() driver{
 	"dir":        func() driver { return &dir{} },
 	"lvm":        func() driver { return &lvm{} },
 	"lvmcluster": func() driver { return &lvm{clustered: true} },
+	"netfs":      func() driver { return &netfs{} },
 	"zfs":        func() driver { return &zfs{} },
 }
 
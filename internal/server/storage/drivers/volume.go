@@ -107,6 +107,9 @@ type Volume struct {
 	mountFilesystemProbe bool   // Probe filesystem type when mounting volume (when needed).
 	hasSource            bool   // Whether the volume is created from a source volume.
 	isDeleted            bool   // Whether we're dealing with a hidden volume (kept until all references are gone).
+
+	// Quiesces the users of the volume while a temporary snapshot of it is taken, returns a hook undoing it.
+	quiesce func() revert.Hook
 }
 
 // NewVolume instantiates a new Volume struct.
@@ -613,6 +616,22 @@ func (v *Volume) SetHasSource(hasSource bool) {
 	v.hasSource = hasSource
 }
 
+// SetQuiesce sets the function used to quiesce the users of the volume while a temporary snapshot of it is taken,
+// such as when backing it up. Drivers then copy the volume from such a snapshot rather than from its live content.
+func (v *Volume) SetQuiesce(quiesce func() revert.Hook) {
+	v.quiesce = quiesce
+}
+
+// quiesceTask runs the task with the users of the volume quiesced, if the volume has a quiesce function.
+func (v Volume) quiesceTask(task func() error) error {
+	if v.quiesce != nil {
+		unquiesce := v.quiesce()
+		defer unquiesce()
+	}
+
+	return task()
+}
+
 // Clone returns a copy of the volume.
 func (v Volume) Clone() Volume {
 	// Copy the config map to avoid internal modifications affecting external state.
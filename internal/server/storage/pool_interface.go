@@ -13,6 +13,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/internal/server/storage/s3"
 	"github.com/lxc/incus/v6/internal/server/storage/s3/miniod"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/revert"
@@ -59,6 +60,7 @@ type Pool interface {
 	Unmount() (bool, error)
 
 	ApplyPatch(name string) error
+	Scrub() (bool, error)
 
 	GetVolume(volumeType drivers.VolumeType, contentType drivers.ContentType, name string, config map[string]string) drivers.Volume
 
@@ -80,6 +82,9 @@ type Pool interface {
 	MigrateInstance(inst instance.Instance, conn io.ReadWriteCloser, args *migration.VolumeSourceArgs, op *operations.Operation) error
 	RefreshInstance(inst instance.Instance, src instance.Instance, srcSnapshots []instance.Instance, allowInconsistent bool, op *operations.Operation) error
 	BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, op *operations.Operation) error
+	BackupInstanceIncremental(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, snapshots bool, anchor string, parentAnchor string, op *operations.Operation) error
+	ApplyInstanceBackup(inst instance.Instance, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error
+	DeleteInstanceBackupAnchor(inst instance.Instance, anchor string, op *operations.Operation) error
 
 	GetInstanceUsage(inst instance.Instance) (*VolumeUsage, error)
 	SetInstanceQuota(inst instance.Instance, size string, vmStateSize string, op *operations.Operation) error
@@ -111,6 +116,7 @@ type Pool interface {
 	DeleteBucketKey(projectName string, bucketName string, keyName string, op *operations.Operation) error
 	ActivateBucket(projectName string, bucketName string, op *operations.Operation) (*miniod.Process, error)
 	GetBucketURL(bucketName string) *url.URL
+	GetBucketTransferManager(projectName string, bucketName string) (*s3.TransferManager, error)
 	GenerateBucketBackupConfig(projectName string, bucketName string, op *operations.Operation) (*backupConfig.Config, error)
 	BackupBucket(projectName string, bucketName string, tarWriter *instancewriter.InstanceTarWriter, op *operations.Operation) error
 	CreateBucketFromBackup(srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error
@@ -24,6 +24,7 @@ type TransferManager struct {
 	s3URL     *url.URL
 	accessKey string
 	secretKey string
+	verifyTLS bool
 }
 
 // NewTransferManager instantiates a new TransferManager struct.
@@ -35,6 +36,17 @@ func NewTransferManager(s3URL *url.URL, accessKey string, secretKey string) Tran
 	}
 }
 
+// NewRemoteTransferManager instantiates a new TransferManager struct for an external S3 endpoint.
+// Unlike the endpoints of local storage buckets, the TLS certificate of the endpoint is verified.
+func NewRemoteTransferManager(s3URL *url.URL, accessKey string, secretKey string) TransferManager {
+	return TransferManager{
+		s3URL:     s3URL,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		verifyTLS: true,
+	}
+}
+
 // DownloadAllFiles downloads all files from a bucket and writes them to a tar writer.
 func (t TransferManager) DownloadAllFiles(bucketName string, tarWriter *instancewriter.InstanceTarWriter) error {
 	logger.Debugf("Downloading all files from bucket %s", bucketName)
@@ -147,10 +159,100 @@ func (t TransferManager) UploadAllFiles(bucketName string, srcData io.ReadSeeker
 	return nil
 }
 
+// UploadFile uploads the content of the reader as a single object in the bucket.
+func (t TransferManager) UploadFile(bucketName string, objectName string, r io.Reader) error {
+	logger.Debugf("Uploading file %s to bucket %s", objectName, bucketName)
+	logger.Debugf("Endpoint: %s", t.getEndpoint())
+
+	minioClient, err := t.getMinioClient()
+	if err != nil {
+		return err
+	}
+
+	// The size isn't known upfront, so use a fixed part size to keep memory usage bounded.
+	_, err = minioClient.PutObject(context.TODO(), bucketName, objectName, r, -1, minio.PutObjectOptions{PartSize: 64 * 1024 * 1024})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DownloadFile returns a reader for a single object in the bucket.
+func (t TransferManager) DownloadFile(bucketName string, objectName string) (io.ReadCloser, error) {
+	logger.Debugf("Downloading file %s from bucket %s", objectName, bucketName)
+	logger.Debugf("Endpoint: %s", t.getEndpoint())
+
+	minioClient, err := t.getMinioClient()
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := minioClient.GetObject(context.TODO(), bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that the object exists, as errors are otherwise only reported on first read.
+	_, err = object.Stat()
+	if err != nil {
+		_ = object.Close()
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// ListFiles returns the names of the objects in the bucket starting with the given prefix.
+func (t TransferManager) ListFiles(bucketName string, prefix string) ([]string, error) {
+	logger.Debugf("Listing files with prefix %s in bucket %s", prefix, bucketName)
+	logger.Debugf("Endpoint: %s", t.getEndpoint())
+
+	minioClient, err := t.getMinioClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	names := []string{}
+	for objectInfo := range minioClient.ListObjects(ctx, bucketName, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if objectInfo.Err != nil {
+			return nil, objectInfo.Err
+		}
+
+		names = append(names, objectInfo.Key)
+	}
+
+	return names, nil
+}
+
+// DeleteFile removes a single object from the bucket.
+func (t TransferManager) DeleteFile(bucketName string, objectName string) error {
+	logger.Debugf("Deleting file %s from bucket %s", objectName, bucketName)
+	logger.Debugf("Endpoint: %s", t.getEndpoint())
+
+	minioClient, err := t.getMinioClient()
+	if err != nil {
+		return err
+	}
+
+	return minioClient.RemoveObject(context.TODO(), bucketName, objectName, minio.RemoveObjectOptions{})
+}
+
 func (t TransferManager) getMinioClient() (*minio.Client, error) {
 	bucketLookup := minio.BucketLookupPath
 	creds := credentials.NewStaticV4(t.accessKey, t.secretKey, "")
 
+	if t.isSecureEndpoint() && t.verifyTLS {
+		return minio.New(t.getEndpoint(), &minio.Options{
+			BucketLookup: bucketLookup,
+			Creds:        creds,
+			Secure:       true,
+		})
+	}
+
 	if t.isSecureEndpoint() {
 		return minio.New(t.getEndpoint(), &minio.Options{
 			BucketLookup: bucketLookup,
@@ -173,6 +275,10 @@ func (t TransferManager) getEndpoint() string {
 		hostname = fmt.Sprintf("[%s]", hostname)
 	}
 
+	if t.s3URL.Port() == "" {
+		return hostname
+	}
+
 	return fmt.Sprintf("%s:%s", hostname, t.s3URL.Port())
 }
 
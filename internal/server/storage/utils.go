@@ -500,6 +500,18 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		rules["initial.mode"] = validate.Optional(validate.IsInt64)
 	}
 
+	// Scheduled backups are only relevant for custom volumes.
+	if (vol == nil) || (vol != nil && vol.Type() == drivers.VolumeTypeCustom) {
+		rules["backups.expiry"] = func(value string) error {
+			// Validate expression
+			_, err := internalInstance.GetExpiry(time.Time{}, value)
+			return err
+		}
+
+		rules["backups.schedule"] = validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"}))
+		rules["backups.target"] = validate.Optional(internalInstance.IsBackupTarget)
+	}
+
 	// security.shared is only relevant for custom block volumes.
 	if (vol == nil) || (vol != nil && vol.Type() == drivers.VolumeTypeCustom && vol.ContentType() == drivers.ContentTypeBlock) {
 		rules["security.shared"] = validate.Optional(validate.IsBool)
@@ -0,0 +1,230 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HeaderTraceParent is the W3C Trace Context header carrying the trace and parent span identifiers.
+const HeaderTraceParent = "traceparent"
+
+// SpanKind describes the relationship between a span and its caller.
+type SpanKind int
+
+// The span kinds, numbered as in OpenTelemetry.
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+// String returns the hex encoding of the trace identifier.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// String returns the hex encoding of the span identifier.
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// IsValid returns whether the span identifier is set.
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// SpanContext identifies a span and the trace it belongs to.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// Span is a timed operation within a trace.
+type Span struct {
+	Name       string
+	Kind       SpanKind
+	Context    SpanContext
+	Parent     SpanID
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]string
+	Error      string
+
+	exporter Exporter
+	lock     sync.Mutex
+	ended    bool
+}
+
+// Exporter receives the spans once they have ended.
+type Exporter interface {
+	ExportSpan(span *Span)
+}
+
+// exporterHolder wraps the exporter so it can be stored atomically.
+type exporterHolder struct {
+	exporter Exporter
+}
+
+var exporter atomic.Pointer[exporterHolder]
+
+type contextKey struct{}
+
+// SetExporter sets the exporter receiving the ended spans.
+// Tracing is disabled when the exporter is nil.
+func SetExporter(e Exporter) {
+	if e == nil {
+		exporter.Store(nil)
+		return
+	}
+
+	exporter.Store(&exporterHolder{exporter: e})
+}
+
+// Enabled returns whether spans are being recorded.
+func Enabled() bool {
+	return exporter.Load() != nil
+}
+
+// Start starts a new span, child of the span found in the context if any.
+// The returned context carries the new span. A nil span is returned when tracing is disabled.
+func Start(ctx context.Context, name string, kind SpanKind, attributes map[string]string) (context.Context, *Span) {
+	holder := exporter.Load()
+	if holder == nil {
+		return ctx, nil
+	}
+
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+
+	span := &Span{
+		Name:       name,
+		Kind:       kind,
+		Start:      time.Now(),
+		Attributes: attributes,
+		exporter:   holder.exporter,
+	}
+
+	parent, ok := FromContext(ctx)
+	if ok {
+		span.Context.TraceID = parent.TraceID
+		span.Parent = parent.SpanID
+	} else {
+		_, _ = rand.Read(span.Context.TraceID[:])
+	}
+
+	_, _ = rand.Read(span.Context.SpanID[:])
+
+	return context.WithValue(ctx, contextKey{}, span.Context), span
+}
+
+// SetAttribute sets an attribute on the span.
+func (s *Span) SetAttribute(key string, value string) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	s.Attributes[key] = value
+	s.lock.Unlock()
+}
+
+// Finish ends the span, marking it as failed if err isn't nil, and exports it.
+func (s *Span) Finish(err error) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+
+	s.ended = true
+	s.End = time.Now()
+	if err != nil {
+		s.Error = err.Error()
+	}
+
+	s.lock.Unlock()
+
+	s.exporter.ExportSpan(s)
+}
+
+// FromContext returns the span context carried by the context.
+func FromContext(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+
+	sc, ok := ctx.Value(contextKey{}).(SpanContext)
+
+	return sc, ok
+}
+
+// Inject adds the trace context of ctx to the request headers.
+func Inject(ctx context.Context, header http.Header) {
+	sc, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+
+	header.Set(HeaderTraceParent, fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID))
+}
+
+// Extract returns a context carrying the remote trace context found in the request headers, if any.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, err := parseTraceParent(header.Get(HeaderTraceParent))
+	if err != nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, contextKey{}, sc)
+}
+
+// parseTraceParent parses the value of a W3C Trace Context traceparent header.
+func parseTraceParent(value string) (SpanContext, error) {
+	sc := SpanContext{}
+
+	fields := strings.Split(value, "-")
+	if len(fields) < 4 || len(fields[0]) != 2 || fields[0] == "ff" {
+		return sc, fmt.Errorf("Invalid traceparent %q", value)
+	}
+
+	if fields[0] == "00" && len(fields) != 4 {
+		return sc, fmt.Errorf("Invalid traceparent %q", value)
+	}
+
+	traceID, err := hex.DecodeString(fields[1])
+	if err != nil || len(traceID) != len(sc.TraceID) {
+		return sc, fmt.Errorf("Invalid trace identifier %q", fields[1])
+	}
+
+	spanID, err := hex.DecodeString(fields[2])
+	if err != nil || len(spanID) != len(sc.SpanID) {
+		return sc, fmt.Errorf("Invalid span identifier %q", fields[2])
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+
+	if sc.TraceID == (TraceID{}) || !sc.SpanID.IsValid() {
+		return sc, fmt.Errorf("Invalid traceparent %q", value)
+	}
+
+	return sc, nil
+}
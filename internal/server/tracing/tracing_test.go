@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	spans []*Span
+}
+
+func (r *recorder) ExportSpan(span *Span) {
+	r.spans = append(r.spans, span)
+}
+
+func TestStartDisabled(t *testing.T) {
+	ctx, span := Start(context.Background(), "test", SpanKindInternal, nil)
+	assert.Nil(t, span)
+
+	// Nil spans are safe to use.
+	span.SetAttribute("foo", "bar")
+	span.Finish(nil)
+
+	_, ok := FromContext(ctx)
+	assert.False(t, ok)
+}
+
+func TestPropagation(t *testing.T) {
+	r := &recorder{}
+	SetExporter(r)
+	defer SetExporter(nil)
+
+	ctx, parent := Start(context.Background(), "parent", SpanKindClient, nil)
+
+	header := http.Header{}
+	Inject(ctx, header)
+	assert.Regexp(t, "^00-[0-9a-f]{32}-[0-9a-f]{16}-01$", header.Get(HeaderTraceParent))
+
+	remoteCtx := Extract(context.Background(), header)
+	_, child := Start(remoteCtx, "child", SpanKindServer, nil)
+	child.Finish(nil)
+	child.Finish(nil)
+	parent.Finish(nil)
+
+	require.Len(t, r.spans, 2)
+	assert.Equal(t, parent.Context.TraceID, child.Context.TraceID)
+	assert.Equal(t, parent.Context.SpanID, child.Parent)
+	assert.NotEqual(t, parent.Context.SpanID, child.Context.SpanID)
+}
+
+func TestExtractInvalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b71692033-01",
+	} {
+		header := http.Header{}
+		header.Set(HeaderTraceParent, value)
+
+		_, ok := FromContext(Extract(context.Background(), header))
+		assert.False(t, ok, "Accepted %q", value)
+	}
+}
@@ -0,0 +1,136 @@
+package webhooks
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// defaultRetries is the default number of delivery retries.
+const defaultRetries = 5
+
+// configKeys is the list of the webhook configuration keys with their validators.
+var configKeys = map[string]func(value string) error{
+	// gendoc:generate(entity=webhook, group=common, key=url)
+	// The event is sent as a JSON `POST` request to this URL.
+	// ---
+	//  type: string
+	//  required: yes
+	//  shortdesc: URL of the webhook target
+	"url": isWebhookURL,
+
+	// gendoc:generate(entity=webhook, group=common, key=secret)
+	// When set, the requests carry an HMAC-SHA256 signature of their timestamp and body in the `X-Incus-Signature` header.
+	// ---
+	//  type: string
+	//  shortdesc: Secret used to sign the requests
+	"secret": validate.IsAny,
+
+	// gendoc:generate(entity=webhook, group=common, key=types)
+	// Specify a comma-separated list of event types to send.
+	// The types can be any combination of `lifecycle` and `network-acl`.
+	// ---
+	//  type: string
+	//  defaultdesc: `lifecycle`
+	//  shortdesc: Event types to send
+	"types": validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "network-acl"))),
+
+	// gendoc:generate(entity=webhook, group=common, key=actions)
+	// Specify a comma-separated list of lifecycle actions (for example `instance-started,instance-stopped`) to send.
+	// All actions are sent if empty.
+	// ---
+	//  type: string
+	//  shortdesc: Lifecycle actions to send
+	"actions": validate.Optional(validate.IsListOf(validate.IsNotEmpty)),
+
+	// gendoc:generate(entity=webhook, group=common, key=projects)
+	// Specify a comma-separated list of projects whose events are sent.
+	// Events from all projects as well as the events not tied to a project are sent if empty.
+	// ---
+	//  type: string
+	//  shortdesc: Projects to send events from
+	"projects": validate.Optional(validate.IsListOf(validate.IsNotEmpty)),
+
+	// gendoc:generate(entity=webhook, group=common, key=retries)
+	// Failed deliveries are retried with an exponential backoff, starting at one second.
+	// Events that still can't be delivered are counted in a warning.
+	// ---
+	//  type: integer
+	//  defaultdesc: `5`
+	//  shortdesc: Number of delivery retries
+	"retries": validate.Optional(validate.IsInRange(0, 20)),
+}
+
+// isWebhookURL checks that the value is a valid HTTP or HTTPS URL.
+func isWebhookURL(value string) error {
+	err := validate.IsRequestURL(value)
+	if err != nil {
+		return err
+	}
+
+	u, _ := url.Parse(value)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("Unsupported URL scheme %q", u.Scheme)
+	}
+
+	return nil
+}
+
+// Validate checks the webhook configuration.
+func Validate(config map[string]string) error {
+	for k, v := range config {
+		// User keys are free for all.
+
+		// gendoc:generate(entity=webhook, group=common, key=user.*)
+		// User keys can be used in search.
+		// ---
+		//  type: string
+		//  shortdesc: Free form user key/value storage
+		if strings.HasPrefix(k, "user.") {
+			continue
+		}
+
+		validator, ok := configKeys[k]
+		if !ok {
+			return fmt.Errorf("Invalid webhook configuration key %q", k)
+		}
+
+		err := validator(v)
+		if err != nil {
+			return fmt.Errorf("Invalid webhook configuration key %q value: %w", k, err)
+		}
+	}
+
+	if config["url"] == "" {
+		return fmt.Errorf("The webhook URL must be set")
+	}
+
+	return nil
+}
+
+// newTarget returns the delivery target matching the webhook configuration.
+func newTarget(name string, config map[string]string) *target {
+	t := &target{
+		name:     name,
+		url:      config["url"],
+		secret:   config["secret"],
+		types:    util.SplitNTrimSpace(config["types"], ",", -1, true),
+		actions:  util.SplitNTrimSpace(config["actions"], ",", -1, true),
+		projects: util.SplitNTrimSpace(config["projects"], ",", -1, true),
+		retries:  defaultRetries,
+	}
+
+	if len(t.types) == 0 {
+		t.types = []string{"lifecycle"}
+	}
+
+	if config["retries"] != "" {
+		t.retries, _ = strconv.Atoi(config["retries"])
+	}
+
+	return t
+}
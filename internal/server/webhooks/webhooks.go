@@ -0,0 +1,284 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// queueSize is the maximum number of events waiting to be delivered to a single webhook.
+const queueSize = 1000
+
+// DeadLetterFunc is called whenever an event couldn't be delivered to a webhook.
+type DeadLetterFunc func(name string, event api.Event, err error)
+
+// target is a configured webhook along with its delivery queue.
+type target struct {
+	name     string
+	url      string
+	secret   string
+	types    []string
+	actions  []string
+	projects []string
+	retries  int
+
+	config map[string]string
+	queue  chan api.Event
+	cancel context.CancelFunc
+
+	// interrupted holds the event whose delivery got cut short by a configuration change,
+	// so the worker using the new configuration delivers it first.
+	interrupted chan api.Event
+}
+
+// Manager delivers events to the configured webhooks.
+type Manager struct {
+	ctx        context.Context
+	client     *http.Client
+	deadLetter DeadLetterFunc
+	retryDelay time.Duration
+
+	targets map[string]*target
+	lock    sync.Mutex
+}
+
+// NewManager returns a new webhook manager.
+// The delivery workers are stopped when the context is cancelled.
+func NewManager(ctx context.Context, proxy func(req *http.Request) (*url.URL, error), deadLetter DeadLetterFunc) *Manager {
+	return &Manager{
+		ctx: ctx,
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{Proxy: proxy},
+		},
+		deadLetter: deadLetter,
+		retryDelay: time.Second,
+		targets:    map[string]*target{},
+	}
+}
+
+// Load replaces the configured webhooks.
+// The events waiting to be delivered to a webhook, including the one being delivered, are kept when its
+// configuration changes.
+func (m *Manager) Load(webhooks []api.Webhook) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	targets := make(map[string]*target, len(webhooks))
+	for _, webhook := range webhooks {
+		old := m.targets[webhook.Name]
+		if old != nil && maps.Equal(old.config, webhook.Config) {
+			targets[webhook.Name] = old
+			delete(m.targets, webhook.Name)
+			continue
+		}
+
+		t := newTarget(webhook.Name, webhook.Config)
+		t.config = webhook.Config
+
+		if old != nil {
+			old.cancel()
+			delete(m.targets, webhook.Name)
+			t.queue = old.queue
+			t.interrupted = old.interrupted
+		} else {
+			t.queue = make(chan api.Event, queueSize)
+			t.interrupted = make(chan api.Event, 1)
+		}
+
+		ctx, cancel := context.WithCancel(m.ctx)
+		t.cancel = cancel
+		go m.worker(ctx, t)
+
+		targets[webhook.Name] = t
+	}
+
+	// Stop the workers of the removed webhooks.
+	for _, t := range m.targets {
+		t.cancel()
+	}
+
+	m.targets = targets
+}
+
+// HandleEvent queues the event for delivery to the matching webhooks.
+func (m *Manager) HandleEvent(event api.Event) {
+	dropped := []string{}
+
+	m.lock.Lock()
+	for _, t := range m.targets {
+		if !t.matches(event) {
+			continue
+		}
+
+		select {
+		case t.queue <- event:
+		default:
+			dropped = append(dropped, t.name)
+		}
+	}
+
+	m.lock.Unlock()
+
+	for _, name := range dropped {
+		m.deadLetter(name, event, fmt.Errorf("Delivery queue is full"))
+	}
+}
+
+// matches returns whether the event should be delivered to the webhook.
+func (t *target) matches(event api.Event) bool {
+	if !slices.Contains(t.types, event.Type) {
+		return false
+	}
+
+	if len(t.projects) > 0 && !slices.Contains(t.projects, event.Project) {
+		return false
+	}
+
+	if len(t.actions) > 0 {
+		if event.Type != api.EventTypeLifecycle {
+			return false
+		}
+
+		lifecycleEvent := api.EventLifecycle{}
+		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+		if err != nil || !slices.Contains(t.actions, lifecycleEvent.Action) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// worker delivers the queued events to the webhook until the context is cancelled.
+// An event whose delivery is interrupted is handed over to the next worker of the webhook.
+func (m *Manager) worker(ctx context.Context, t *target) {
+	for {
+		var event api.Event
+
+		// Resume the interrupted delivery first.
+		select {
+		case event = <-t.interrupted:
+		default:
+			select {
+			case <-ctx.Done():
+				return
+			case event = <-t.interrupted:
+			case event = <-t.queue:
+			}
+		}
+
+		err := m.deliver(ctx, t, event)
+		if err == nil {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			if m.ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case t.interrupted <- event:
+			default:
+				m.deadLetter(t.name, event, fmt.Errorf("Delivery interrupted by a configuration change"))
+			}
+
+			return
+		}
+
+		logger.Warn("Failed delivering event to webhook", logger.Ctx{"webhook": t.name, "err": err})
+		m.deadLetter(t.name, event, err)
+	}
+}
+
+// deliver sends the event to the webhook, retrying with an exponential backoff.
+func (m *Manager) deliver(ctx context.Context, t *target, event api.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	delivery := uuid.New().String()
+	delay := m.retryDelay
+
+	for attempt := 0; ; attempt++ {
+		retry, err := m.send(ctx, t, delivery, event.Type, body)
+		if err == nil {
+			return nil
+		}
+
+		if !retry || attempt >= t.retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+// send performs a single delivery attempt and returns whether it should be retried on failure.
+func (m *Manager) send(ctx context.Context, t *target, delivery string, eventType string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent)
+	req.Header.Set("X-Incus-Delivery", delivery)
+	req.Header.Set("X-Incus-Event", eventType)
+	req.Header.Set("X-Incus-Timestamp", timestamp)
+
+	if t.secret != "" {
+		req.Header.Set("X-Incus-Signature", "sha256="+Sign(t.secret, timestamp, body))
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+
+	return retry, fmt.Errorf("Webhook target returned %q", resp.Status)
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of the timestamp and body, separated by a dot.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp + "."))
+	_, _ = mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,193 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func lifecycleEvent(t *testing.T, project string, action string) api.Event {
+	metadata, err := json.Marshal(api.EventLifecycle{Action: action, Source: "/1.0/instances/c1"})
+	require.NoError(t, err)
+
+	return api.Event{Type: api.EventTypeLifecycle, Project: project, Metadata: metadata}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(map[string]string{"url": "https://example.com/hook", "types": "lifecycle,network-acl", "user.foo": "bar"}))
+	assert.Error(t, Validate(map[string]string{}))
+	assert.Error(t, Validate(map[string]string{"url": "ftp://example.com"}))
+	assert.Error(t, Validate(map[string]string{"url": "https://example.com", "types": "logging"}))
+	assert.Error(t, Validate(map[string]string{"url": "https://example.com", "retries": "100"}))
+	assert.Error(t, Validate(map[string]string{"url": "https://example.com", "foo": "bar"}))
+}
+
+func TestManagerDelivery(t *testing.T) {
+	received := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+
+	var failures atomic.Int32
+	failures.Store(2)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempts to exercise the retries.
+		if failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewManager(ctx, nil, func(name string, event api.Event, err error) {
+		t.Errorf("Unexpected dead letter for %q: %v", name, err)
+	})
+
+	m.retryDelay = time.Millisecond
+	m.Load([]api.Webhook{{Name: "test", WebhookPut: api.WebhookPut{Config: map[string]string{
+		"url":      server.URL,
+		"secret":   "s3cr3t",
+		"actions":  "instance-started",
+		"projects": "default",
+	}}}})
+
+	// Filtered out by project, action and type.
+	m.HandleEvent(lifecycleEvent(t, "other", "instance-started"))
+	m.HandleEvent(lifecycleEvent(t, "default", "instance-stopped"))
+	m.HandleEvent(api.Event{Type: api.EventTypeLogging, Project: "default"})
+
+	m.HandleEvent(lifecycleEvent(t, "default", "instance-started"))
+
+	select {
+	case r := <-received:
+		body := <-bodies
+
+		timestamp := r.Header.Get("X-Incus-Timestamp")
+		assert.Equal(t, "sha256="+Sign("s3cr3t", timestamp, body), r.Header.Get("X-Incus-Signature"))
+		assert.Equal(t, api.EventTypeLifecycle, r.Header.Get("X-Incus-Event"))
+		assert.NotEmpty(t, r.Header.Get("X-Incus-Delivery"))
+
+		event := api.Event{}
+		require.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, "default", event.Project)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Event wasn't delivered")
+	}
+
+	select {
+	case <-received:
+		t.Fatal("Filtered event was delivered")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestManagerDeadLetter(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	deadLetters := make(chan string, 1)
+	m := NewManager(ctx, nil, func(name string, event api.Event, err error) {
+		deadLetters <- name
+	})
+
+	m.retryDelay = time.Millisecond
+	m.Load([]api.Webhook{{Name: "failing", WebhookPut: api.WebhookPut{Config: map[string]string{
+		"url":     server.URL,
+		"retries": "2",
+	}}}})
+
+	m.HandleEvent(lifecycleEvent(t, "default", "instance-started"))
+
+	select {
+	case name := <-deadLetters:
+		assert.Equal(t, "failing", name)
+		assert.Equal(t, int32(3), attempts.Load())
+	case <-time.After(5 * time.Second):
+		t.Fatal("Event wasn't dead-lettered")
+	}
+}
+
+func TestManagerReloadDuringDelivery(t *testing.T) {
+	started := make(chan struct{}, 1)
+	signatures := make(chan string, 10)
+
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		// Hang on the first request until the client gives up on it.
+		if requests.Add(1) == 1 {
+			started <- struct{}{}
+			<-r.Context().Done()
+			return
+		}
+
+		signatures <- r.Header.Get("X-Incus-Signature") + " " + Sign("new", r.Header.Get("X-Incus-Timestamp"), body)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewManager(ctx, nil, func(name string, event api.Event, err error) {
+		t.Errorf("Unexpected dead letter for %q: %v", name, err)
+	})
+
+	webhook := func(secret string) []api.Webhook {
+		return []api.Webhook{{Name: "test", WebhookPut: api.WebhookPut{Config: map[string]string{
+			"url":    server.URL,
+			"secret": secret,
+		}}}}
+	}
+
+	m.Load(webhook("old"))
+	m.HandleEvent(lifecycleEvent(t, "default", "instance-started"))
+	m.HandleEvent(lifecycleEvent(t, "default", "instance-stopped"))
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Delivery didn't start")
+	}
+
+	// The event being delivered and the queued one are both sent using the new configuration.
+	m.Load(webhook("new"))
+
+	for range 2 {
+		select {
+		case signature := <-signatures:
+			var got, expected string
+			_, err := fmt.Sscan(signature, &got, &expected)
+			require.NoError(t, err)
+			assert.Equal(t, "sha256="+expected, got)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Event wasn't delivered after the reload")
+		}
+	}
+}
@@ -467,6 +467,31 @@ var APIExtensions = []string{
 	"acme_dns01",
 	"security_iommu",
 	"network_ipv4_dhcp_routes",
+	"storage_driver_netfs",
+	"instance_backup_incremental",
+	"backups_schedule",
+	"backup_bucket",
+	"network_type_wireguard",
+	"network_bridge_evpn",
+	"network_acl_limits",
+	"network_address_set",
+	"network_acl_dns_names",
+	"instance_placement_rules",
+	"auth_rbac",
+	"oidc_groups_claim",
+	"auth_tokens",
+	"audit_log",
+	"event_history",
+	"webhooks",
+	"otlp",
+	"metrics_resources",
+	"images_oci",
+	"vm_memory_hotplug",
+	"vm_qemu_guest_agent",
+	"vm_live_migration_tuning",
+	"disk_limits_group",
+	"vm_fsfreeze",
+	"storage_scrub_schedule",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
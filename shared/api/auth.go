@@ -1,9 +1,199 @@
 package api
 
+import (
+	"time"
+)
+
 const (
 	// AuthenticationMethodTLS is the default authentication method for interacting with Incus remotely.
 	AuthenticationMethodTLS = "tls"
 
 	// AuthenticationMethodOIDC is a token based authentication method.
 	AuthenticationMethodOIDC = "oidc"
+
+	// AuthenticationMethodToken is the scoped API token authentication method.
+	AuthenticationMethodToken = "token"
 )
+
+// AuthGroupPost used for renaming an authorization group.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroupPost struct {
+	// The new name for the group
+	// Example: contractors
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthGroupPut used for updating an authorization group.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroupPut struct {
+	// Description of the group
+	// Example: External contractors
+	Description string `json:"description" yaml:"description"`
+
+	// List of permissions granted to the members of the group
+	Permissions []AuthPermission `json:"permissions" yaml:"permissions"`
+
+	// List of identity provider groups whose members are members of the group
+	// Example: ["incus-contractors"]
+	//
+	// API extension: oidc_groups_claim
+	IdentityProviderGroups []string `json:"identity_provider_groups" yaml:"identity_provider_groups"`
+}
+
+// AuthGroup used for displaying an authorization group.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroup struct {
+	AuthGroupPost `yaml:",inline"`
+	AuthGroupPut  `yaml:",inline"`
+
+	// List of URLs of the identities that are members of the group
+	// Read only: true
+	// Example: ["/1.0/auth/identities/oidc/jane@example.com"]
+	Identities []string `json:"identities" yaml:"identities"`
+}
+
+// Writable converts a full AuthGroup struct into a AuthGroupPut struct (filters read-only fields).
+func (group *AuthGroup) Writable() AuthGroupPut {
+	return group.AuthGroupPut
+}
+
+// AuthGroupsPost used for creating an authorization group.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroupsPost struct {
+	AuthGroupPost `yaml:",inline"`
+	AuthGroupPut  `yaml:",inline"`
+}
+
+// AuthPermission represents an entitlement granted on an authorization object.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthPermission struct {
+	// Entitlement granted on the object
+	// Example: can_view
+	Entitlement string `json:"entitlement" yaml:"entitlement"`
+
+	// Authorization object the entitlement applies to
+	// Example: project:default
+	Object string `json:"object" yaml:"object"`
+}
+
+// AuthIdentityPut used for updating an identity.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthIdentityPut struct {
+	// List of groups the identity is a member of
+	// Example: ["contractors"]
+	Groups []string `json:"groups" yaml:"groups"`
+}
+
+// AuthIdentity used for displaying an identity.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthIdentity struct {
+	AuthIdentityPut `yaml:",inline"`
+
+	// Authentication method of the identity (tls or oidc)
+	// Example: oidc
+	AuthMethod string `json:"auth_method" yaml:"auth_method"`
+
+	// Identifier of the identity (certificate fingerprint or OIDC user name)
+	// Example: jane@example.com
+	Identifier string `json:"identifier" yaml:"identifier"`
+}
+
+// Writable converts a full AuthIdentity struct into a AuthIdentityPut struct (filters read-only fields).
+func (identity *AuthIdentity) Writable() AuthIdentityPut {
+	return identity.AuthIdentityPut
+}
+
+// AuthIdentitiesPost used for creating an identity.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthIdentitiesPost struct {
+	AuthIdentityPut `yaml:",inline"`
+
+	// Authentication method of the identity (tls or oidc)
+	// Example: oidc
+	AuthMethod string `json:"auth_method" yaml:"auth_method"`
+
+	// Identifier of the identity (certificate fingerprint or OIDC user name)
+	// Example: jane@example.com
+	Identifier string `json:"identifier" yaml:"identifier"`
+}
+
+// AuthTokensPost used for creating a scoped API token.
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthTokensPost struct {
+	// Description of the token
+	// Example: CI runner
+	Description string `json:"description" yaml:"description"`
+
+	// Project the token is restricted to
+	// Example: ci
+	Project string `json:"project" yaml:"project"`
+
+	// List of entitlements (in the form <object type>:<entitlement>) granted on the objects of the project
+	// Example: ["instance:can_view", "instance:can_exec"]
+	Entitlements []string `json:"entitlements" yaml:"entitlements"`
+
+	// When the token expires
+	// Example: 2025-03-23T20:00:00-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// AuthToken used for displaying a scoped API token.
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthToken struct {
+	AuthTokensPost `yaml:",inline"`
+
+	// Identifier of the token
+	// Read only: true
+	// Example: 3b6e4a4f-1c1f-4b0e-9a54-0b3ec9d2a1a7
+	ID string `json:"id" yaml:"id"`
+
+	// When the token was created
+	// Read only: true
+	// Example: 2025-03-23T19:00:00-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
+
+// AuthTokenSecret used for returning a newly created scoped API token.
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthTokenSecret struct {
+	// Identifier of the token
+	// Example: 3b6e4a4f-1c1f-4b0e-9a54-0b3ec9d2a1a7
+	ID string `json:"id" yaml:"id"`
+
+	// Bearer token to pass in the Authorization header (only returned on creation)
+	// Example: incus_3b6e4a4f-1c1f-4b0e-9a54-0b3ec9d2a1a7.8d1e0c6b8f5a4e2d9c7b6a5f4e3d2c1b
+	Token string `json:"token" yaml:"token"`
+}
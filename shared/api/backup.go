@@ -0,0 +1,35 @@
+package api
+
+// BackupBucket represents the location of a backup file in a storage bucket.
+//
+// Either Pool is set to use an Incus storage bucket from the current project,
+// or URL, AccessKey and SecretKey are set to use an arbitrary S3-compatible endpoint.
+//
+// swagger:model
+//
+// API extension: backup_bucket.
+type BackupBucket struct {
+	// Storage pool of the Incus storage bucket
+	// Example: default
+	Pool string `json:"pool,omitempty" yaml:"pool,omitempty"`
+
+	// URL of the S3-compatible endpoint
+	// Example: https://s3.example.com
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Name of the bucket
+	// Example: backups
+	Bucket string `json:"bucket" yaml:"bucket"`
+
+	// Path of the backup file in the bucket
+	// Example: instances/c1.tar.gz
+	Path string `json:"path" yaml:"path"`
+
+	// Access key for the S3-compatible endpoint
+	// Example: 33UgkaIBLBIxb7O1
+	AccessKey string `json:"access_key,omitempty" yaml:"access_key,omitempty"`
+
+	// Secret key for the S3-compatible endpoint
+	// Example: kDQD6AOgwHgaQI1UIJBJpPaiLgZuJbq0
+	SecretKey string `json:"secret_key,omitempty" yaml:"secret_key,omitempty"`
+}
@@ -41,6 +41,12 @@ type Event struct {
 	//
 	// API extension: event_project
 	Project string `yaml:"project,omitempty" json:"project,omitempty"`
+
+	// Identifier of the event in the history of the cluster member serving the event stream
+	// Example: 1234
+	//
+	// API extension: event_history
+	ID uint64 `yaml:"id,omitempty" json:"id,omitempty"`
 }
 
 // ToLogging creates log record for the event.
@@ -2,6 +2,12 @@ package api
 
 // Define consts for all the lifecycle events.
 const (
+	EventLifecycleAuthGroupCreated                  = "auth-group-created"
+	EventLifecycleAuthGroupDeleted                  = "auth-group-deleted"
+	EventLifecycleAuthGroupRenamed                  = "auth-group-renamed"
+	EventLifecycleAuthGroupUpdated                  = "auth-group-updated"
+	EventLifecycleAuthTokenCreated                  = "auth-token-created"
+	EventLifecycleAuthTokenDeleted                  = "auth-token-deleted"
 	EventLifecycleCertificateCreated                = "certificate-created"
 	EventLifecycleCertificateDeleted                = "certificate-deleted"
 	EventLifecycleCertificateUpdated                = "certificate-updated"
@@ -21,6 +27,9 @@ const (
 	EventLifecycleClusterMemberUpdated              = "cluster-member-updated"
 	EventLifecycleClusterTokenCreated               = "cluster-token-created"
 	EventLifecycleConfigUpdated                     = "config-updated"
+	EventLifecycleIdentityCreated                   = "identity-created"
+	EventLifecycleIdentityDeleted                   = "identity-deleted"
+	EventLifecycleIdentityUpdated                   = "identity-updated"
 	EventLifecycleImageAliasCreated                 = "image-alias-created"
 	EventLifecycleImageAliasDeleted                 = "image-alias-deleted"
 	EventLifecycleImageAliasRenamed                 = "image-alias-renamed"
@@ -33,6 +42,7 @@ const (
 	EventLifecycleImageUpdated                      = "image-updated"
 	EventLifecycleInstanceBackupCreated             = "instance-backup-created"
 	EventLifecycleInstanceBackupDeleted             = "instance-backup-deleted"
+	EventLifecycleInstanceBackupExported            = "instance-backup-exported"
 	EventLifecycleInstanceBackupRenamed             = "instance-backup-renamed"
 	EventLifecycleInstanceBackupRetrieved           = "instance-backup-retrieved"
 	EventLifecycleInstanceConsole                   = "instance-console"
@@ -70,6 +80,10 @@ const (
 	EventLifecycleNetworkACLDeleted                 = "network-acl-deleted"
 	EventLifecycleNetworkACLRenamed                 = "network-acl-renamed"
 	EventLifecycleNetworkACLUpdated                 = "network-acl-updated"
+	EventLifecycleNetworkAddressSetCreated          = "network-address-set-created"
+	EventLifecycleNetworkAddressSetDeleted          = "network-address-set-deleted"
+	EventLifecycleNetworkAddressSetRenamed          = "network-address-set-renamed"
+	EventLifecycleNetworkAddressSetUpdated          = "network-address-set-updated"
 	EventLifecycleNetworkCreated                    = "network-created"
 	EventLifecycleNetworkDeleted                    = "network-deleted"
 	EventLifecycleNetworkForwardCreated             = "network-forward-created"
@@ -117,6 +131,7 @@ const (
 	EventLifecycleStoragePoolUpdated                = "storage-pool-updated"
 	EventLifecycleStorageVolumeBackupCreated        = "storage-volume-backup-created"
 	EventLifecycleStorageVolumeBackupDeleted        = "storage-volume-backup-deleted"
+	EventLifecycleStorageVolumeBackupExported       = "storage-volume-backup-exported"
 	EventLifecycleStorageVolumeBackupRenamed        = "storage-volume-backup-renamed"
 	EventLifecycleStorageVolumeBackupRetrieved      = "storage-volume-backup-retrieved"
 	EventLifecycleStorageVolumeCreated              = "storage-volume-created"
@@ -131,4 +146,8 @@ const (
 	EventLifecycleWarningAcknowledged               = "warning-acknowledged"
 	EventLifecycleWarningDeleted                    = "warning-deleted"
 	EventLifecycleWarningReset                      = "warning-reset"
+	EventLifecycleWebhookCreated                    = "webhook-created"
+	EventLifecycleWebhookDeleted                    = "webhook-deleted"
+	EventLifecycleWebhookRenamed                    = "webhook-renamed"
+	EventLifecycleWebhookUpdated                    = "webhook-updated"
 )
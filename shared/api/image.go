@@ -36,6 +36,12 @@ type ImageExportPost struct {
 	//
 	// API extension: image_copy_profile
 	Profiles []string `json:"profiles" yaml:"profiles"`
+
+	// Protocol of the target server (incus or oci)
+	// Example: oci
+	//
+	// API extension: images_oci
+	Protocol string `json:"protocol" yaml:"protocol"`
 }
 
 // ImagesPost represents the fields available for a new image
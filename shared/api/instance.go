@@ -377,4 +377,9 @@ type InstanceSource struct {
 	//
 	// API extension: instance_allow_inconsistent_copy
 	AllowInconsistent bool `json:"allow_inconsistent" yaml:"allow_inconsistent"`
+
+	// Storage bucket holding the backup file (for backup)
+	//
+	// API extension: backup_bucket
+	Bucket *BackupBucket `json:"bucket,omitempty" yaml:"bucket,omitempty"`
 }
@@ -31,6 +31,23 @@ type InstanceBackupsPost struct {
 	//
 	// API extension: backup_compression_algorithm
 	CompressionAlgorithm string `json:"compression_algorithm" yaml:"compression_algorithm"`
+
+	// Name of an existing backup to base an incremental backup on
+	// Example: backup0
+	//
+	// API extension: instance_backup_incremental
+	IncrementalFrom string `json:"incremental_from" yaml:"incremental_from"`
+
+	// Whether to keep a storage anchor so the backup can be the base of later incremental backups
+	// Example: true
+	//
+	// API extension: instance_backup_incremental
+	IncrementalBase bool `json:"incremental_base" yaml:"incremental_base"`
+
+	// Storage bucket to upload the backup to instead of keeping it on the server
+	//
+	// API extension: backup_bucket
+	Target *BackupBucket `json:"target,omitempty" yaml:"target,omitempty"`
 }
 
 // InstanceBackup represents an instance backup.
@@ -58,6 +75,12 @@ type InstanceBackup struct {
 	// Whether to use a pool-optimized binary format (instead of plain tarball)
 	// Example: true
 	OptimizedStorage bool `json:"optimized_storage" yaml:"optimized_storage"`
+
+	// Name of the backup this incremental backup is based on
+	// Example: backup0
+	//
+	// API extension: instance_backup_incremental
+	IncrementalFrom string `json:"incremental_from" yaml:"incremental_from"`
 }
 
 // InstanceBackupPost represents the fields available for the renaming of a instance backup.
@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // NetworksPost represents the fields of a new network
 //
 // swagger:model
@@ -188,6 +192,11 @@ type NetworkState struct {
 	//
 	// API extension: network_state_ovn
 	OVN *NetworkStateOVN `json:"ovn" yaml:"ovn"`
+
+	// Additional WireGuard network information
+	//
+	// API extension: network_type_wireguard
+	Wireguard *NetworkStateWireguard `json:"wireguard,omitempty" yaml:"wireguard,omitempty"`
 }
 
 // NetworkStateAddress represents a network address
@@ -341,3 +350,52 @@ type NetworkStateOVN struct {
 	// API extension: network_ovn_state_addresses
 	UplinkIPv6 string `json:"uplink_ipv6" yaml:"uplink_ipv6"`
 }
+
+// NetworkStateWireguard represents WireGuard specific state
+//
+// swagger:model
+//
+// API extension: network_type_wireguard.
+type NetworkStateWireguard struct {
+	// Public key of the local WireGuard interface
+	// Example: xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=
+	PublicKey string `json:"public_key" yaml:"public_key"`
+
+	// UDP port the interface listens on
+	// Example: 51820
+	ListenPort int `json:"listen_port" yaml:"listen_port"`
+
+	// State of the configured peers
+	Peers []NetworkStateWireguardPeer `json:"peers" yaml:"peers"`
+}
+
+// NetworkStateWireguardPeer represents the state of a WireGuard peer
+//
+// swagger:model
+//
+// API extension: network_type_wireguard.
+type NetworkStateWireguardPeer struct {
+	// Name of the network peer
+	// Example: site2
+	Name string `json:"name" yaml:"name"`
+
+	// Public key of the peer
+	// Example: TrMvSoP4jYQlY6RIzBgbssQqY3vxI2Pi+y71lOWWXX0=
+	PublicKey string `json:"public_key" yaml:"public_key"`
+
+	// Current endpoint of the peer
+	// Example: 192.0.2.10:51820
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+
+	// Time of the latest handshake with the peer
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	LatestHandshake time.Time `json:"latest_handshake" yaml:"latest_handshake"`
+
+	// Number of bytes received from the peer
+	// Example: 4096
+	BytesReceived uint64 `json:"bytes_received" yaml:"bytes_received"`
+
+	// Number of bytes sent to the peer
+	// Example: 8192
+	BytesSent uint64 `json:"bytes_sent" yaml:"bytes_sent"`
+}
@@ -43,6 +43,24 @@ type NetworkACLRule struct {
 	// Example: 0
 	ICMPCode string `json:"icmp_code,omitempty" yaml:"icmp_code,omitempty"`
 
+	// Maximum rate of packets (or bandwidth) matched by the rule
+	// Example: 100/second
+	//
+	// API extension: network_acl_limits
+	RateLimit string `json:"rate_limit,omitempty" yaml:"rate_limit,omitempty"`
+
+	// Maximum rate of new connections matched by the rule
+	// Example: 10/minute
+	//
+	// API extension: network_acl_limits
+	ConnectionRate string `json:"connection_rate,omitempty" yaml:"connection_rate,omitempty"`
+
+	// Maximum number of concurrent connections matched by the rule
+	// Example: 100
+	//
+	// API extension: network_acl_limits
+	ConnectionLimit string `json:"connection_limit,omitempty" yaml:"connection_limit,omitempty"`
+
 	// Description of the rule
 	// Example: Allow DNS queries to Google DNS
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
@@ -58,6 +76,9 @@ func (r *NetworkACLRule) Normalise() {
 	r.Protocol = strings.TrimSpace(r.Protocol)
 	r.ICMPType = strings.TrimSpace(r.ICMPType)
 	r.ICMPCode = strings.TrimSpace(r.ICMPCode)
+	r.RateLimit = strings.TrimSpace(r.RateLimit)
+	r.ConnectionRate = strings.TrimSpace(r.ConnectionRate)
+	r.ConnectionLimit = strings.TrimSpace(r.ConnectionLimit)
 	r.Description = strings.TrimSpace(r.Description)
 	r.State = strings.TrimSpace(r.State)
 
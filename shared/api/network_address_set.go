@@ -0,0 +1,65 @@
+package api
+
+// NetworkAddressSetPost used for renaming an address set.
+//
+// swagger:model
+//
+// API extension: network_address_set.
+type NetworkAddressSetPost struct {
+	// The new name for the address set
+	// Example: bar
+	Name string `json:"name" yaml:"name"`
+}
+
+// NetworkAddressSetPut used for updating an address set.
+//
+// swagger:model
+//
+// API extension: network_address_set.
+type NetworkAddressSetPut struct {
+	// Description of the address set
+	// Example: Web servers
+	Description string `json:"description" yaml:"description"`
+
+	// List of addresses and subnets in the set
+	// Example: ["192.0.2.1", "2001:db8::/64"]
+	Addresses []string `json:"addresses" yaml:"addresses"`
+
+	// Address set configuration map (refer to doc/howto/network_address_sets.md)
+	// Example: {"user.mykey": "foo"}
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// NetworkAddressSet used for displaying an address set.
+//
+// swagger:model
+//
+// API extension: network_address_set.
+type NetworkAddressSet struct {
+	NetworkAddressSetPost `yaml:",inline"`
+	NetworkAddressSetPut  `yaml:",inline"`
+
+	// List of URLs of objects using this address set
+	// Read only: true
+	// Example: ["/1.0/network-acls/foo"]
+	UsedBy []string `json:"used_by" yaml:"used_by"`
+
+	// Project name
+	// Example: project1
+	Project string `json:"project" yaml:"project"`
+}
+
+// Writable converts a full NetworkAddressSet struct into a NetworkAddressSetPut struct (filters read-only fields).
+func (set *NetworkAddressSet) Writable() NetworkAddressSetPut {
+	return set.NetworkAddressSetPut
+}
+
+// NetworkAddressSetsPost used for creating an address set.
+//
+// swagger:model
+//
+// API extension: network_address_set.
+type NetworkAddressSetsPost struct {
+	NetworkAddressSetPost `yaml:",inline"`
+	NetworkAddressSetPut  `yaml:",inline"`
+}
@@ -248,6 +248,11 @@ type StorageVolumeSource struct {
 	//
 	// API extension: cluster_internal_custom_volume_copy
 	Location string `json:"location" yaml:"location"`
+
+	// Storage bucket holding the backup file (for backup)
+	//
+	// API extension: backup_bucket
+	Bucket *BackupBucket `json:"bucket,omitempty" yaml:"bucket,omitempty"`
 }
 
 // Writable converts a full StorageVolume struct into a StorageVolumePut struct (filters read-only fields).
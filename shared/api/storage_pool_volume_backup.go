@@ -56,6 +56,11 @@ type StorageVolumeBackupsPost struct {
 	// What compression algorithm to use
 	// Example: gzip
 	CompressionAlgorithm string `json:"compression_algorithm" yaml:"compression_algorithm"`
+
+	// Storage bucket to upload the backup to instead of keeping it on the server
+	//
+	// API extension: backup_bucket
+	Target *BackupBucket `json:"target,omitempty" yaml:"target,omitempty"`
 }
 
 // StorageVolumeBackupPost represents the fields available for the renaming of a volume backup
@@ -0,0 +1,58 @@
+package api
+
+// WebhooksPost represents the fields of a new webhook
+//
+// swagger:model
+//
+// API extension: webhooks.
+type WebhooksPost struct {
+	WebhookPut `yaml:",inline"`
+
+	// The name of the webhook
+	// Example: ci
+	Name string `json:"name" yaml:"name"`
+}
+
+// WebhookPut represents the modifiable fields of a webhook
+//
+// swagger:model
+//
+// API extension: webhooks.
+type WebhookPut struct {
+	// Description of the webhook
+	// Example: Notify the CI system
+	Description string `json:"description" yaml:"description"`
+
+	// Webhook configuration map (refer to doc/webhooks.md)
+	// Example: {"url": "https://ci.example.net/hooks/incus", "types": "lifecycle"}
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// Webhook represents a webhook.
+//
+// swagger:model
+//
+// API extension: webhooks.
+type Webhook struct {
+	WebhookPut `yaml:",inline"`
+
+	// The name of the webhook
+	// Example: ci
+	Name string `json:"name" yaml:"name"`
+}
+
+// Writable converts a full Webhook struct into a WebhookPut struct (filters read-only fields).
+func (w *Webhook) Writable() WebhookPut {
+	return w.WebhookPut
+}
+
+// WebhookPost represents the fields required to rename a webhook
+//
+// swagger:model
+//
+// API extension: webhooks.
+type WebhookPost struct {
+	// The new name for the webhook
+	// Example: ci2
+	Name string `json:"name" yaml:"name"`
+}
@@ -0,0 +1,241 @@
+package oci
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Prefixes of the whiteout entries found in layers.
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// decompress detects the compression of a layer and returns a reader for the tarball.
+func decompress(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(4)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		decoder, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+
+		return decoder.IOReadCloser(), nil
+	}
+
+	return io.NopCloser(br), nil
+}
+
+// Differ computes the changes of a root filesystem compared to the layers of an image.
+//
+// The layers are indexed from their headers, without being unpacked. The
+// entries of the root filesystem are then compared to the index using their
+// type, ownership, permissions, size, modification time and extended attributes.
+type Differ struct {
+	lower map[string]*tar.Header
+	seen  map[string]byte
+}
+
+// NewDiffer returns a Differ without any lower layer.
+func NewDiffer() *Differ {
+	return &Differ{
+		lower: map[string]*tar.Header{},
+		seen:  map[string]byte{},
+	}
+}
+
+// diffName normalizes the name of a tarball entry, the root directory being empty.
+func diffName(name string) string {
+	name = path.Clean("/" + name)
+	return strings.TrimPrefix(name, "/")
+}
+
+// removeTree removes an entry and all its children from the index, except for the kept ones.
+func (d *Differ) removeTree(name string, keep func(entry string) bool) {
+	for entry := range d.lower {
+		if name != "" && entry != name && !strings.HasPrefix(entry, name+"/") {
+			continue
+		}
+
+		if keep != nil && keep(entry) {
+			continue
+		}
+
+		delete(d.lower, entry)
+	}
+}
+
+// AddLayer adds a layer, possibly compressed, on top of the indexed layers.
+func (d *Differ) AddLayer(r io.Reader) error {
+	reader, err := decompress(r)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = reader.Close() }()
+
+	created := map[string]bool{}
+
+	tr := tar.NewReader(reader)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return fmt.Errorf("Failed to read layer: %w", err)
+		}
+
+		name := diffName(hdr.Name)
+		dir, base := path.Split(name)
+		dir = strings.TrimSuffix(dir, "/")
+
+		if base == whiteoutOpaque {
+			// Only keep the content added by the current layer.
+			d.removeTree(dir, func(entry string) bool { return entry == dir || entry == "" || created[entry] })
+			continue
+		}
+
+		target, ok := strings.CutPrefix(base, whiteoutPrefix)
+		if ok {
+			if target == "" || target == "." || target == ".." {
+				return fmt.Errorf("Invalid whiteout entry %q", hdr.Name)
+			}
+
+			d.removeTree(path.Join(dir, target), nil)
+			continue
+		}
+
+		// Replacing a directory by another type of entry removes its content.
+		lower, ok := d.lower[name]
+		if ok && lower.Typeflag == tar.TypeDir && hdr.Typeflag != tar.TypeDir {
+			d.removeTree(name, nil)
+		}
+
+		d.lower[name] = hdr
+		created[name] = true
+	}
+
+	return nil
+}
+
+// Changed returns whether the entry of the root filesystem differs from the indexed layers.
+func (d *Differ) Changed(hdr *tar.Header) bool {
+	name := diffName(hdr.Name)
+	d.seen[name] = hdr.Typeflag
+
+	lower, ok := d.lower[name]
+	if !ok {
+		return true
+	}
+
+	typeflag := func(t byte) byte {
+		//nolint:staticcheck // TypeRegA is still found in old tarballs.
+		if t == tar.TypeRegA {
+			return tar.TypeReg
+		}
+
+		return t
+	}
+
+	if typeflag(lower.Typeflag) != typeflag(hdr.Typeflag) {
+		return true
+	}
+
+	if lower.Mode&07777 != hdr.Mode&07777 || lower.Uid != hdr.Uid || lower.Gid != hdr.Gid {
+		return true
+	}
+
+	switch typeflag(hdr.Typeflag) {
+	case tar.TypeReg:
+		if lower.Size != hdr.Size {
+			return true
+		}
+
+	case tar.TypeSymlink:
+		if lower.Linkname != hdr.Linkname {
+			return true
+		}
+
+	case tar.TypeLink:
+		if diffName(lower.Linkname) != diffName(hdr.Linkname) {
+			return true
+		}
+
+	case tar.TypeChar, tar.TypeBlock:
+		if lower.Devmajor != hdr.Devmajor || lower.Devminor != hdr.Devminor {
+			return true
+		}
+	}
+
+	if lower.ModTime.Unix() != hdr.ModTime.Unix() {
+		return true
+	}
+
+	xattrs := func(records map[string]string) map[string]string {
+		out := map[string]string{}
+		for key, value := range records {
+			if strings.HasPrefix(key, "SCHILY.xattr.") {
+				out[key] = value
+			}
+		}
+
+		return out
+	}
+
+	return !maps.Equal(xattrs(lower.PAXRecords), xattrs(hdr.PAXRecords))
+}
+
+// Whiteouts returns the whiteout entries of the indexed content missing from the entries passed to Changed.
+//
+// Only the top-most missing entries get a whiteout, their content being removed along with them.
+func (d *Differ) Whiteouts() []string {
+	var whiteouts []string
+
+	for name := range d.lower {
+		if name == "" {
+			continue
+		}
+
+		_, ok := d.seen[name]
+		if ok {
+			continue
+		}
+
+		// Skip the entries whose parent is either removed too or replaced by something else than a directory.
+		dir, base := path.Split(name)
+		dir = strings.TrimSuffix(dir, "/")
+
+		parentType, ok := d.seen[dir]
+		if dir != "" && (!ok || parentType != tar.TypeDir) {
+			continue
+		}
+
+		whiteouts = append(whiteouts, path.Join(dir, whiteoutPrefix+base))
+	}
+
+	slices.Sort(whiteouts)
+
+	return whiteouts
+}
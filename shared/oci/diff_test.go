@@ -0,0 +1,95 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildHeaderLayer builds a gzip compressed layer from headers only, regular files being empty.
+func buildHeaderLayer(t *testing.T, modTime time.Time, headers []tar.Header) []byte {
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, hdr := range headers {
+		hdr.ModTime = modTime
+		require.NoError(t, tw.WriteHeader(&hdr))
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	return buf.Bytes()
+}
+
+func TestDiffer(t *testing.T) {
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	lower := buildHeaderLayer(t, modTime, []tar.Header{
+		{Name: "etc/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "etc/hostname", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "etc/removed", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "etc/whiteout", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "opaque/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "opaque/lower", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "replaced/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "replaced/child", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "tree/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "tree/sub/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "tree/sub/file", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "bin/sh", Typeflag: tar.TypeReg, Mode: 0755},
+		{Name: "lib", Typeflag: tar.TypeSymlink, Linkname: "usr/lib"},
+	})
+
+	upper := buildHeaderLayer(t, modTime, []tar.Header{
+		{Name: "etc/.wh.whiteout", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "opaque/upper", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "opaque/.wh..wh..opq", Typeflag: tar.TypeReg, Mode: 0644},
+	})
+
+	d := NewDiffer()
+	require.NoError(t, d.AddLayer(bytes.NewReader(lower)))
+	require.NoError(t, d.AddLayer(bytes.NewReader(upper)))
+
+	entries := []struct {
+		hdr     tar.Header
+		changed bool
+	}{
+		{hdr: tar.Header{Name: "etc", Typeflag: tar.TypeDir, Mode: 0755}},
+		{hdr: tar.Header{Name: "etc/hostname", Typeflag: tar.TypeReg, Mode: 0644, Size: 5}, changed: true},
+		{hdr: tar.Header{Name: "etc/whiteout", Typeflag: tar.TypeReg, Mode: 0644}, changed: true},
+		{hdr: tar.Header{Name: "etc/new", Typeflag: tar.TypeReg, Mode: 0644}, changed: true},
+		{hdr: tar.Header{Name: "opaque", Typeflag: tar.TypeDir, Mode: 0755}},
+		{hdr: tar.Header{Name: "opaque/upper", Typeflag: tar.TypeReg, Mode: 0644}},
+		{hdr: tar.Header{Name: "opaque/lower", Typeflag: tar.TypeReg, Mode: 0644}, changed: true},
+		{hdr: tar.Header{Name: "replaced", Typeflag: tar.TypeReg, Mode: 0644}, changed: true},
+		{hdr: tar.Header{Name: "bin/sh", Typeflag: tar.TypeReg, Mode: 04755}, changed: true},
+		{hdr: tar.Header{Name: "lib", Typeflag: tar.TypeSymlink, Linkname: "usr/lib64"}, changed: true},
+	}
+
+	for _, entry := range entries {
+		entry.hdr.ModTime = modTime
+		assert.Equal(t, entry.changed, d.Changed(&entry.hdr), entry.hdr.Name)
+	}
+
+	// Modification times, ownership and extended attributes are compared too.
+	assert.True(t, d.Changed(&tar.Header{Name: "etc", Typeflag: tar.TypeDir, Mode: 0755, ModTime: modTime.Add(time.Hour)}))
+	assert.True(t, d.Changed(&tar.Header{Name: "etc", Typeflag: tar.TypeDir, Mode: 0755, ModTime: modTime, Uid: 1000}))
+	assert.True(t, d.Changed(&tar.Header{Name: "etc", Typeflag: tar.TypeDir, Mode: 0755, ModTime: modTime, PAXRecords: map[string]string{"SCHILY.xattr.user.foo": "bar"}}))
+
+	// Only the top-most removed entries get a whiteout, the replaced directory removing its content.
+	assert.Equal(t, []string{".wh.tree", "etc/.wh.removed"}, d.Whiteouts())
+}
+
+func TestDifferInvalidWhiteout(t *testing.T) {
+	layer := buildHeaderLayer(t, time.Now(), []tar.Header{{Name: "etc/.wh..", Typeflag: tar.TypeReg, Mode: 0644}})
+
+	d := NewDiffer()
+	assert.Error(t, d.AddLayer(bytes.NewReader(layer)))
+}
@@ -0,0 +1,301 @@
+package oci
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalImage is an image assembled from blobs stored in a local directory.
+//
+// It can then be written as an OCI image layout tarball or pushed to a registry.
+type LocalImage struct {
+	dir string
+
+	// Manifest is the descriptor of the image manifest, set by Finalize.
+	Manifest Descriptor
+
+	// Config is the descriptor of the image configuration, set by Finalize.
+	Config Descriptor
+
+	// Layers are the descriptors of the image layers.
+	Layers []Descriptor
+
+	diffIDs []string
+}
+
+// NewLocalImage returns an empty image storing its blobs in the given directory.
+func NewLocalImage(dir string) *LocalImage {
+	return &LocalImage{dir: dir}
+}
+
+// AddLayer returns a writer for a new layer, added to the image once closed.
+func (i *LocalImage) AddLayer() (*LayerWriter, error) {
+	f, err := os.CreateTemp(i.dir, "layer_")
+	if err != nil {
+		return nil, err
+	}
+
+	l := &LayerWriter{
+		image:      i,
+		file:       f,
+		compressed: sha256.New(),
+		diffID:     sha256.New(),
+	}
+
+	l.counter = &countingWriter{writer: io.MultiWriter(f, l.compressed)}
+	l.gzip = gzip.NewWriter(l.counter)
+	l.Writer = tar.NewWriter(io.MultiWriter(l.gzip, l.diffID))
+
+	return l, nil
+}
+
+// AddExistingLayer stores a layer of another image and adds it to the image.
+//
+// The layer is kept as it is, so it can be shared with the original image.
+func (i *LocalImage) AddExistingLayer(r io.Reader, descriptor Descriptor, diffID string) error {
+	hexDigest, err := splitDigest(descriptor.Digest)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Join(i.dir, hexDigest))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, r)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("Failed to store layer %q: %w", descriptor.Digest, err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	// Docker layers have the same format as the OCI ones.
+	mediaType := descriptor.MediaType
+	if mediaType == MediaTypeDockerLayer {
+		mediaType = MediaTypeImageLayerGzip
+	}
+
+	i.Layers = append(i.Layers, Descriptor{MediaType: mediaType, Digest: descriptor.Digest, Size: descriptor.Size})
+	i.diffIDs = append(i.diffIDs, diffID)
+
+	return nil
+}
+
+// Finalize writes the configuration and manifest of the image.
+//
+// The list of layers of the configuration is filled from the layers of the image.
+func (i *LocalImage) Finalize(config ImageConfig) error {
+	config.RootFS = RootFS{Type: "layers", DiffIDs: i.diffIDs}
+
+	if config.Created != nil {
+		created := config.Created.UTC().Truncate(time.Second)
+		config.Created = &created
+	}
+
+	var err error
+
+	i.Config, err = i.writeJSON(MediaTypeImageConfig, config)
+	if err != nil {
+		return err
+	}
+
+	manifest := Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		Config:        i.Config,
+		Layers:        i.Layers,
+	}
+
+	i.Manifest, err = i.writeJSON(MediaTypeImageManifest, manifest)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Open returns the content of a blob of the image.
+func (i *LocalImage) Open(descriptor Descriptor) (*os.File, error) {
+	hexDigest, err := splitDigest(descriptor.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(filepath.Join(i.dir, hexDigest))
+}
+
+// Blobs returns the descriptors of all the blobs of the image, the manifest being last.
+func (i *LocalImage) Blobs() []Descriptor {
+	blobs := append([]Descriptor{}, i.Layers...)
+	return append(blobs, i.Config, i.Manifest)
+}
+
+// WriteLayout writes the image as an OCI image layout tarball, under the given reference name.
+func (i *LocalImage) WriteLayout(w io.Writer, refName string) error {
+	if i.Manifest.Digest == "" {
+		return errors.New("The image isn't finalized")
+	}
+
+	tw := tar.NewWriter(w)
+
+	writeFile := func(name string, content []byte) error {
+		err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))})
+		if err != nil {
+			return err
+		}
+
+		_, err = tw.Write(content)
+		return err
+	}
+
+	layout, err := json.Marshal(map[string]string{"imageLayoutVersion": LayoutVersion})
+	if err != nil {
+		return err
+	}
+
+	manifest := i.Manifest
+	manifest.Annotations = map[string]string{AnnotationRefName: refName}
+
+	index, err := json.Marshal(Index{SchemaVersion: 2, MediaType: MediaTypeImageIndex, Manifests: []Descriptor{manifest}})
+	if err != nil {
+		return err
+	}
+
+	err = writeFile("oci-layout", layout)
+	if err != nil {
+		return err
+	}
+
+	err = writeFile("index.json", index)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range []string{"blobs/", "blobs/sha256/"} {
+		err = tw.WriteHeader(&tar.Header{Name: dir, Typeflag: tar.TypeDir, Mode: 0755})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, blob := range i.Blobs() {
+		f, err := i.Open(blob)
+		if err != nil {
+			return err
+		}
+
+		err = tw.WriteHeader(&tar.Header{Name: "blobs/sha256/" + blob.Digest[len("sha256:"):], Typeflag: tar.TypeReg, Mode: 0644, Size: blob.Size})
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+
+		_, err = io.Copy(tw, f)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+// writeJSON stores a JSON document as a blob and returns its descriptor.
+func (i *LocalImage) writeJSON(mediaType string, value any) (Descriptor, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return Descriptor{}, err
+	}
+
+	hash := sha256.Sum256(content)
+	hexDigest := hex.EncodeToString(hash[:])
+
+	err = os.WriteFile(filepath.Join(i.dir, hexDigest), content, 0644)
+	if err != nil {
+		return Descriptor{}, err
+	}
+
+	return Descriptor{MediaType: mediaType, Digest: "sha256:" + hexDigest, Size: int64(len(content))}, nil
+}
+
+// LayerWriter writes a gzip compressed layer of an image.
+type LayerWriter struct {
+	*tar.Writer
+
+	image      *LocalImage
+	file       *os.File
+	gzip       *gzip.Writer
+	counter    *countingWriter
+	compressed hash.Hash
+	diffID     hash.Hash
+	closed     bool
+}
+
+// Close completes the layer and adds it to the image.
+func (l *LayerWriter) Close() error {
+	if l.closed {
+		return nil
+	}
+
+	l.closed = true
+
+	defer func() { _ = os.Remove(l.file.Name()) }()
+
+	err := l.Writer.Close()
+	if err != nil {
+		_ = l.file.Close()
+		return err
+	}
+
+	err = l.gzip.Close()
+	if err != nil {
+		_ = l.file.Close()
+		return err
+	}
+
+	err = l.file.Close()
+	if err != nil {
+		return err
+	}
+
+	hexDigest := hex.EncodeToString(l.compressed.Sum(nil))
+
+	err = os.Rename(l.file.Name(), filepath.Join(l.image.dir, hexDigest))
+	if err != nil {
+		return fmt.Errorf("Failed to store layer: %w", err)
+	}
+
+	l.image.Layers = append(l.image.Layers, Descriptor{MediaType: MediaTypeImageLayerGzip, Digest: "sha256:" + hexDigest, Size: l.counter.count})
+	l.image.diffIDs = append(l.image.diffIDs, "sha256:"+hex.EncodeToString(l.diffID.Sum(nil)))
+
+	return nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+// Write writes to the underlying writer.
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,139 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLocalImage builds an image with a single layer holding a file.
+func newTestLocalImage(t *testing.T) *LocalImage {
+	img := NewLocalImage(t.TempDir())
+
+	layer, err := img.AddLayer()
+	require.NoError(t, err)
+
+	require.NoError(t, layer.WriteHeader(&tar.Header{Name: "hello", Typeflag: tar.TypeReg, Mode: 0644, Size: 5}))
+	_, err = layer.Write([]byte("world"))
+	require.NoError(t, err)
+	require.NoError(t, layer.Close())
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, img.Finalize(ImageConfig{
+		Created:      &created,
+		Architecture: "arm64",
+		OS:           "linux",
+		Config:       ContainerConfig{Entrypoint: []string{"/sbin/init"}},
+	}))
+
+	return img
+}
+
+func TestLocalImageLayout(t *testing.T) {
+	img := newTestLocalImage(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, img.WriteLayout(&buf, "1.0"))
+
+	// Read the layout back.
+	files := map[string][]byte{}
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = content
+	}
+
+	assert.JSONEq(t, `{"imageLayoutVersion":"1.0.0"}`, string(files["oci-layout"]))
+
+	var index Index
+	require.NoError(t, json.Unmarshal(files["index.json"], &index))
+	require.Len(t, index.Manifests, 1)
+	assert.Equal(t, img.Manifest.Digest, index.Manifests[0].Digest)
+	assert.Equal(t, "1.0", index.Manifests[0].Annotations[AnnotationRefName])
+
+	// All the blobs are present and match their digest.
+	for _, blob := range img.Blobs() {
+		content, ok := files["blobs/sha256/"+blob.Digest[len("sha256:"):]]
+		require.True(t, ok, blob.Digest)
+
+		hash := sha256.Sum256(content)
+		assert.Equal(t, blob.Digest, "sha256:"+hex.EncodeToString(hash[:]))
+		assert.Equal(t, blob.Size, int64(len(content)))
+	}
+
+	// The configuration lists the uncompressed layer.
+	var config ImageConfig
+	require.NoError(t, json.Unmarshal(files["blobs/sha256/"+img.Config.Digest[len("sha256:"):]], &config))
+	require.Len(t, config.RootFS.DiffIDs, 1)
+	assert.Equal(t, "layers", config.RootFS.Type)
+	assert.NotEqual(t, img.Layers[0].Digest, config.RootFS.DiffIDs[0])
+
+}
+
+func TestLocalImageExistingLayer(t *testing.T) {
+	source := newTestLocalImage(t)
+
+	f, err := source.Open(source.Layers[0])
+	require.NoError(t, err)
+
+	defer func() { _ = f.Close() }()
+
+	var config ImageConfig
+	configFile, err := source.Open(source.Config)
+	require.NoError(t, err)
+	require.NoError(t, json.NewDecoder(configFile).Decode(&config))
+	_ = configFile.Close()
+
+	descriptor := source.Layers[0]
+	descriptor.MediaType = MediaTypeDockerLayer
+
+	img := NewLocalImage(t.TempDir())
+	require.NoError(t, img.AddExistingLayer(f, descriptor, config.RootFS.DiffIDs[0]))
+
+	layer, err := img.AddLayer()
+	require.NoError(t, err)
+	require.NoError(t, layer.WriteHeader(&tar.Header{Name: ".wh.hello", Typeflag: tar.TypeReg, Mode: 0644}))
+	require.NoError(t, layer.Close())
+
+	require.NoError(t, img.Finalize(ImageConfig{Architecture: "arm64", OS: "linux"}))
+
+	// The existing layer is kept as it is, using the OCI media type.
+	require.Len(t, img.Layers, 2)
+	assert.Equal(t, Descriptor{MediaType: MediaTypeImageLayerGzip, Digest: source.Layers[0].Digest, Size: source.Layers[0].Size}, img.Layers[0])
+
+	content, err := os.ReadFile(filepath.Join(img.dir, source.Layers[0].Digest[len("sha256:"):]))
+	require.NoError(t, err)
+
+	hash := sha256.Sum256(content)
+	assert.Equal(t, source.Layers[0].Digest, "sha256:"+hex.EncodeToString(hash[:]))
+
+	// The configuration lists both layers.
+	configFile, err = img.Open(img.Config)
+	require.NoError(t, err)
+
+	var newConfig ImageConfig
+	require.NoError(t, json.NewDecoder(configFile).Decode(&newConfig))
+	_ = configFile.Close()
+
+	require.Len(t, newConfig.RootFS.DiffIDs, 2)
+	assert.Equal(t, config.RootFS.DiffIDs[0], newConfig.RootFS.DiffIDs[0])
+}
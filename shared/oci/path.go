@@ -0,0 +1,71 @@
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxSymlinks is the number of symlinks followed before giving up on resolving a path.
+const maxSymlinks = 255
+
+// secureJoin resolves a path inside of root, following symlinks as if root was the filesystem root.
+//
+// The resulting path is always within root, no matter what the path or the
+// symlinks it traverses point to. Missing components are kept as they are.
+func secureJoin(root string, path string) (string, error) {
+	resolved := "/"
+	remaining := path
+	links := 0
+
+	for remaining != "" {
+		var part string
+
+		part, remaining, _ = strings.Cut(remaining, "/")
+		if part == "" || part == "." {
+			continue
+		}
+
+		if part == ".." {
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, part)
+
+		fi, err := os.Lstat(filepath.Join(root, next))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				resolved = next
+				continue
+			}
+
+			return "", err
+		}
+
+		if fi.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", fmt.Errorf("Too many levels of symbolic links in %q", path)
+		}
+
+		target, err := os.Readlink(filepath.Join(root, next))
+		if err != nil {
+			return "", err
+		}
+
+		if filepath.IsAbs(target) {
+			resolved = "/"
+		}
+
+		remaining = target + "/" + remaining
+	}
+
+	return filepath.Join(root, resolved), nil
+}
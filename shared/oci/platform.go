@@ -0,0 +1,110 @@
+package oci
+
+import (
+	"fmt"
+
+	"github.com/lxc/incus/v6/shared/osarch"
+)
+
+// platforms maps Incus architecture names to matching OCI platforms, in order of preference.
+var platforms = map[string][]Platform{
+	"i686":        {{Architecture: "386"}},
+	"x86_64":      {{Architecture: "amd64"}},
+	"armv6l":      {{Architecture: "arm", Variant: "v6"}, {Architecture: "arm", Variant: "v5"}},
+	"armv7l":      {{Architecture: "arm", Variant: "v7"}, {Architecture: "arm", Variant: "v6"}, {Architecture: "arm", Variant: "v5"}},
+	"armv8l":      {{Architecture: "arm", Variant: "v8"}, {Architecture: "arm", Variant: "v7"}, {Architecture: "arm", Variant: "v6"}},
+	"aarch64":     {{Architecture: "arm64", Variant: "v8"}},
+	"ppc":         {{Architecture: "ppc"}},
+	"ppc64":       {{Architecture: "ppc64"}},
+	"ppc64le":     {{Architecture: "ppc64le"}},
+	"s390x":       {{Architecture: "s390x"}},
+	"mips":        {{Architecture: "mipsle"}},
+	"mips64":      {{Architecture: "mips64le"}},
+	"riscv64":     {{Architecture: "riscv64"}},
+	"loongarch64": {{Architecture: "loong64"}},
+}
+
+// defaultVariants holds the variant assumed by images which don't specify one.
+var defaultVariants = map[string]string{
+	"arm":   "v7",
+	"arm64": "v8",
+}
+
+// ArchitecturePlatforms returns the OCI platforms suitable for an Incus architecture, in order of preference.
+func ArchitecturePlatforms(architecture string) ([]Platform, error) {
+	archID, err := osarch.ArchitectureId(architecture)
+	if err != nil {
+		return nil, err
+	}
+
+	archName, err := osarch.ArchitectureName(archID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, ok := platforms[archName]
+	if !ok {
+		return nil, fmt.Errorf("Architecture %q isn't supported for OCI images", archName)
+	}
+
+	result := make([]Platform, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidate.OS = "linux"
+		result = append(result, candidate)
+	}
+
+	return result, nil
+}
+
+// PlatformArchitecture returns the Incus architecture name for an OCI platform.
+func PlatformArchitecture(platform Platform) (string, error) {
+	variant := platform.Variant
+	if variant == "" {
+		variant = defaultVariants[platform.Architecture]
+	}
+
+	for archName, candidates := range platforms {
+		if candidates[0].Architecture == platform.Architecture && candidates[0].Variant == variant {
+			return archName, nil
+		}
+	}
+
+	// Fallback to the architecture aliases.
+	archID, err := osarch.ArchitectureId(platform.Architecture)
+	if err != nil {
+		return "", err
+	}
+
+	return osarch.ArchitectureName(archID)
+}
+
+// matches returns whether the platform of a manifest matches the wanted one.
+func (p Platform) matches(wanted Platform) bool {
+	if p.OS != wanted.OS || p.Architecture != wanted.Architecture {
+		return false
+	}
+
+	variant := p.Variant
+	if variant == "" {
+		variant = defaultVariants[p.Architecture]
+	}
+
+	return wanted.Variant == "" || variant == wanted.Variant
+}
+
+// selectManifest picks the manifest of an index best matching the wanted platforms.
+func selectManifest(index Index, wanted []Platform) (*Descriptor, error) {
+	for _, platform := range wanted {
+		for i, manifest := range index.Manifests {
+			if manifest.Platform == nil {
+				continue
+			}
+
+			if manifest.Platform.matches(platform) {
+				return &index.Manifests[i], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("No image found for platform %s/%s", wanted[0].OS, wanted[0].Architecture)
+}
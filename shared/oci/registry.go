@@ -0,0 +1,656 @@
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxManifestSize is the largest manifest or configuration accepted from a registry.
+const maxManifestSize = 4 * 1024 * 1024
+
+// manifestMediaTypes lists the manifest formats accepted from registries.
+var manifestMediaTypes = []string{
+	MediaTypeImageIndex,
+	MediaTypeDockerManifestList,
+	MediaTypeImageManifest,
+	MediaTypeDockerManifest,
+}
+
+// Client is a client for the OCI distribution API of a registry.
+type Client struct {
+	http      *http.Client
+	registry  *url.URL
+	prefix    string
+	userAgent string
+	username  string
+	password  string
+	dockerHub bool
+
+	tokens   map[string]string
+	tokensMu sync.Mutex
+}
+
+// NewClient returns a client for the registry at the given URL.
+//
+// The URL may include credentials, used to authenticate against the registry
+// as well as a path, used as a prefix for all the repositories.
+func NewClient(httpClient *http.Client, registryURL string, userAgent string) (*Client, error) {
+	u, err := url.Parse(registryURL)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse registry URL %q: %w", registryURL, err)
+	}
+
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("Unsupported registry URL scheme %q", u.Scheme)
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	c := &Client{
+		http:      httpClient,
+		prefix:    strings.Trim(u.Path, "/"),
+		userAgent: userAgent,
+		tokens:    map[string]string{},
+	}
+
+	if u.User != nil {
+		c.username = u.User.Username()
+		c.password, _ = u.User.Password()
+	}
+
+	// The Docker Hub API isn't served from its main domain.
+	if u.Host == "docker.io" || u.Host == "index.docker.io" {
+		u.Host = "registry-1.docker.io"
+	}
+
+	c.dockerHub = u.Host == "registry-1.docker.io"
+	c.registry = &url.URL{Scheme: u.Scheme, Host: u.Host}
+
+	return c, nil
+}
+
+// ParseReference splits an image name into its repository and its tag or digest.
+func ParseReference(name string) (string, string, error) {
+	repository, reference := name, "latest"
+
+	i := strings.Index(name, "@")
+	if i >= 0 {
+		repository, reference = name[:i], name[i+1:]
+		_, err := splitDigest(reference)
+		if err != nil {
+			return "", "", err
+		}
+	} else {
+		i := strings.LastIndex(name, ":")
+		if i > strings.LastIndex(name, "/") {
+			repository, reference = name[:i], name[i+1:]
+		}
+	}
+
+	if repository == "" || reference == "" || strings.HasPrefix(repository, "/") || strings.HasSuffix(repository, "/") || strings.Contains(repository, "//") {
+		return "", "", fmt.Errorf("Invalid image name %q", name)
+	}
+
+	if repository != strings.ToLower(repository) {
+		return "", "", fmt.Errorf("Invalid image name %q: Repository names must be lowercase", name)
+	}
+
+	return repository, reference, nil
+}
+
+// parseReference parses an image name into the full name of its repository on the registry and its reference.
+func (c *Client) parseReference(name string) (string, string, error) {
+	repository, reference, err := ParseReference(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	if c.prefix != "" {
+		repository = c.prefix + "/" + repository
+	} else if c.dockerHub && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+
+	return repository, reference, nil
+}
+
+// Resolve fetches the manifest and configuration of an image for the first matching platform.
+func (c *Client) Resolve(ctx context.Context, name string, platforms []Platform) (*Image, error) {
+	repository, reference, err := c.parseReference(name)
+	if err != nil {
+		return nil, err
+	}
+
+	body, mediaType, digest, err := c.getManifest(ctx, repository, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	img := &Image{
+		Repository: repository,
+		Digest:     digest,
+	}
+
+	// Pick the manifest of the platform from the index.
+	if mediaType == MediaTypeImageIndex || mediaType == MediaTypeDockerManifestList {
+		var index Index
+		err = json.Unmarshal(body, &index)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse image index: %w", err)
+		}
+
+		descriptor, err := selectManifest(index, platforms)
+		if err != nil {
+			return nil, err
+		}
+
+		body, mediaType, _, err = c.getManifest(ctx, repository, descriptor.Digest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if mediaType != MediaTypeImageManifest && mediaType != MediaTypeDockerManifest {
+		return nil, fmt.Errorf("Unsupported manifest type %q", mediaType)
+	}
+
+	err = json.Unmarshal(body, &img.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse image manifest: %w", err)
+	}
+
+	// Fetch the image configuration.
+	if img.Manifest.Config.Size > maxManifestSize {
+		return nil, fmt.Errorf("Image configuration is too large (%d bytes)", img.Manifest.Config.Size)
+	}
+
+	rc, err := c.GetBlob(ctx, repository, img.Manifest.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rc.Close() }()
+
+	body, err = io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &img.Config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse image configuration: %w", err)
+	}
+
+	return img, nil
+}
+
+// GetBlob returns a reader for a blob of the repository.
+//
+// The content is checked against the size and digest of the descriptor, a
+// mismatch being reported as an error when reaching the end of the blob.
+func (c *Client) GetBlob(ctx context.Context, repository string, descriptor Descriptor) (io.ReadCloser, error) {
+	hexDigest, err := splitDigest(descriptor.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, request{method: http.MethodGet, repository: repository, url: "/blobs/" + descriptor.Digest})
+	if err != nil {
+		return nil, err
+	}
+
+	return &verifyingReader{
+		body:   resp.Body,
+		reader: io.LimitReader(resp.Body, descriptor.Size+1),
+		hash:   sha256.New(),
+		digest: hexDigest,
+		size:   descriptor.Size,
+	}, nil
+}
+
+// Push uploads a local image to the registry under the given name, skipping the blobs already present.
+func (c *Client) Push(ctx context.Context, name string, img *LocalImage) error {
+	repository, reference, err := c.parseReference(name)
+	if err != nil {
+		return err
+	}
+
+	for _, blob := range img.Blobs() {
+		if blob.Digest == img.Manifest.Digest {
+			break
+		}
+
+		err = c.pushBlob(ctx, repository, img, blob)
+		if err != nil {
+			return fmt.Errorf("Failed to push blob %q: %w", blob.Digest, err)
+		}
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", img.Manifest.MediaType)
+
+	resp, err := c.do(ctx, request{
+		method:     http.MethodPut,
+		repository: repository,
+		push:       true,
+		url:        "/manifests/" + reference,
+		header:     header,
+		body:       blobBody(img, img.Manifest),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to push manifest: %w", err)
+	}
+
+	return resp.Body.Close()
+}
+
+// pushBlob uploads a blob to the repository unless already present.
+func (c *Client) pushBlob(ctx context.Context, repository string, img *LocalImage, blob Descriptor) error {
+	resp, err := c.do(ctx, request{method: http.MethodHead, repository: repository, push: true, url: "/blobs/" + blob.Digest})
+	if err == nil {
+		return resp.Body.Close()
+	}
+
+	// Start a new upload.
+	resp, err = c.do(ctx, request{method: http.MethodPost, repository: repository, push: true, url: "/blobs/uploads/"})
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.Header.Get("Location") == "" {
+		return errors.New("The registry didn't return an upload location")
+	}
+
+	location = resp.Request.URL.ResolveReference(location)
+	query := location.Query()
+	query.Set("digest", blob.Digest)
+	location.RawQuery = query.Encode()
+
+	// Upload the content in a single request.
+	header := http.Header{}
+	header.Set("Content-Type", "application/octet-stream")
+
+	resp, err = c.do(ctx, request{
+		method:     http.MethodPut,
+		repository: repository,
+		push:       true,
+		url:        location.String(),
+		header:     header,
+		body:       blobBody(img, blob),
+	})
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// blobBody returns a request body function for a blob of a local image.
+func blobBody(img *LocalImage, blob Descriptor) func() (io.ReadCloser, int64, error) {
+	return func() (io.ReadCloser, int64, error) {
+		f, err := img.Open(blob)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return f, blob.Size, nil
+	}
+}
+
+// getManifest fetches a manifest by tag or digest, returning its content, media type and digest.
+func (c *Client) getManifest(ctx context.Context, repository string, reference string) ([]byte, string, string, error) {
+	header := http.Header{}
+	header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	resp, err := c.do(ctx, request{method: http.MethodGet, repository: repository, url: "/manifests/" + reference, header: header})
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if len(body) > maxManifestSize {
+		return nil, "", "", fmt.Errorf("Manifest %q is too large", reference)
+	}
+
+	hash := sha256.Sum256(body)
+	digest := "sha256:" + hex.EncodeToString(hash[:])
+
+	// Manifests fetched by digest must match it.
+	if strings.Contains(reference, ":") && reference != digest {
+		return nil, "", "", fmt.Errorf("Manifest digest mismatch (expected %q, got %q)", reference, digest)
+	}
+
+	// Fallback to the media type embedded in the manifest.
+	mediaType, _, _ := strings.Cut(resp.Header.Get("Content-Type"), ";")
+	if !slices.Contains(manifestMediaTypes, mediaType) {
+		var manifest struct {
+			MediaType string            `json:"mediaType"`
+			Manifests []json.RawMessage `json:"manifests"`
+		}
+
+		err = json.Unmarshal(body, &manifest)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("Failed to parse manifest: %w", err)
+		}
+
+		mediaType = manifest.MediaType
+		if mediaType == "" && manifest.Manifests != nil {
+			mediaType = MediaTypeImageIndex
+		} else if mediaType == "" {
+			mediaType = MediaTypeImageManifest
+		}
+	}
+
+	return body, mediaType, digest, nil
+}
+
+// request is a request sent to the registry.
+type request struct {
+	method     string
+	repository string
+	push       bool
+
+	// url is either absolute or relative to the repository.
+	url    string
+	header http.Header
+
+	// body returns a new reader for the request body, needed as the request may be retried.
+	body func() (io.ReadCloser, int64, error)
+}
+
+// do sends a request for the repository, authenticating as requested by the registry.
+func (c *Client) do(ctx context.Context, r request) (*http.Response, error) {
+	scope := "repository:" + r.repository + ":pull"
+	if r.push {
+		scope += ",push"
+	}
+
+	reqURL, err := url.Parse(r.url)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reqURL.IsAbs() {
+		reqURL, err = url.Parse(c.registry.JoinPath("v2", r.repository).String() + r.url)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	authorized := false
+	for {
+		req, err := http.NewRequestWithContext(ctx, r.method, reqURL.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.body != nil {
+			req.Body, req.ContentLength, err = r.body()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for key, values := range r.header {
+			req.Header[key] = values
+		}
+
+		if c.userAgent != "" {
+			req.Header.Set("User-Agent", c.userAgent)
+		}
+
+		c.tokensMu.Lock()
+		authorization := c.tokens[scope]
+		c.tokensMu.Unlock()
+
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+			return resp, nil
+		}
+
+		challenge := resp.Header.Get("WWW-Authenticate")
+		err = responseError(resp)
+		if resp.StatusCode != http.StatusUnauthorized || authorized || challenge == "" {
+			return nil, err
+		}
+
+		// Authenticate and retry once.
+		authorization, err = c.authenticate(ctx, challenge, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		c.tokensMu.Lock()
+		c.tokens[scope] = authorization
+		c.tokensMu.Unlock()
+
+		authorized = true
+	}
+}
+
+// authenticate answers an authentication challenge, returning the authorization header to use.
+func (c *Client) authenticate(ctx context.Context, challenge string, scope string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if c.username == "" {
+			return "", errors.New("The registry requires credentials")
+		}
+
+		req := http.Request{Header: http.Header{}}
+		req.SetBasicAuth(c.username, c.password)
+
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+		realm, err := url.Parse(params["realm"])
+		if err != nil || (realm.Scheme != "https" && realm.Scheme != "http") {
+			return "", fmt.Errorf("Invalid authentication realm %q", params["realm"])
+		}
+
+		query := realm.Query()
+		if params["service"] != "" {
+			query.Set("service", params["service"])
+		}
+
+		if params["scope"] != "" {
+			scope = params["scope"]
+		}
+
+		query.Set("scope", scope)
+		realm.RawQuery = query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+
+		if c.userAgent != "" {
+			req.Header.Set("User-Agent", c.userAgent)
+		}
+
+		if c.username != "" {
+			req.SetBasicAuth(c.username, c.password)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("Failed to get registry token: %w", err)
+		}
+
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("Failed to get registry token: %s", resp.Status)
+		}
+
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+
+		err = json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&token)
+		if err != nil {
+			return "", fmt.Errorf("Failed to parse registry token: %w", err)
+		}
+
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+
+		if token.Token == "" {
+			return "", errors.New("The registry didn't return a token")
+		}
+
+		return "Bearer " + token.Token, nil
+	}
+
+	return "", fmt.Errorf("Unsupported authentication scheme %q", scheme)
+}
+
+// parseChallenge parses a WWW-Authenticate header into its scheme and parameters.
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+
+	for rest != "" {
+		rest = strings.TrimLeft(rest, " ,")
+
+		key, value, found := strings.Cut(rest, "=")
+		if !found {
+			break
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if strings.HasPrefix(value, "\"") {
+			// Quoted value, may contain commas and escaped characters.
+			var sb strings.Builder
+			i := 1
+			for ; i < len(value) && value[i] != '"'; i++ {
+				if value[i] == '\\' && i+1 < len(value) {
+					i++
+				}
+
+				sb.WriteByte(value[i])
+			}
+
+			params[key] = sb.String()
+			rest = value[min(i+1, len(value)):]
+		} else {
+			params[key], rest, _ = strings.Cut(value, ",")
+			params[key] = strings.TrimSpace(params[key])
+		}
+	}
+
+	return scheme, params
+}
+
+// responseError turns a failed registry response into an error, consuming the body.
+func responseError(resp *http.Response) error {
+	defer func() { _ = resp.Body.Close() }()
+
+	var body struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	_ = json.Unmarshal(data, &body)
+
+	if len(body.Errors) > 0 && body.Errors[0].Message != "" {
+		return fmt.Errorf("Registry request failed (%s): %s", resp.Status, body.Errors[0].Message)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.New("Image not found")
+	}
+
+	return fmt.Errorf("Registry request failed: %s", resp.Status)
+}
+
+// splitDigest validates a sha256 digest and returns its hex part.
+func splitDigest(digest string) (string, error) {
+	algorithm, value, _ := strings.Cut(digest, ":")
+	if algorithm != "sha256" {
+		return "", fmt.Errorf("Unsupported digest %q", digest)
+	}
+
+	_, err := hex.DecodeString(value)
+	if err != nil || len(value) != sha256.Size*2 {
+		return "", fmt.Errorf("Invalid digest %q", digest)
+	}
+
+	return value, nil
+}
+
+// verifyingReader checks the content read against the expected digest and size.
+type verifyingReader struct {
+	body   io.Closer
+	reader io.Reader
+	hash   hash.Hash
+	digest string
+	size   int64
+	read   int64
+}
+
+// Read reads from the blob, failing at the end of it if its content doesn't match.
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	r.hash.Write(p[:n])
+
+	if r.read > r.size {
+		return n, fmt.Errorf("Blob is larger than expected (%d bytes)", r.size)
+	}
+
+	if errors.Is(err, io.EOF) {
+		if r.read != r.size {
+			return n, fmt.Errorf("Blob size mismatch (expected %d bytes, got %d)", r.size, r.read)
+		}
+
+		digest := hex.EncodeToString(r.hash.Sum(nil))
+		if digest != r.digest {
+			return n, fmt.Errorf("Blob digest mismatch (expected %q, got %q)", r.digest, digest)
+		}
+	}
+
+	return n, err
+}
+
+// Close closes the underlying response body.
+func (r *verifyingReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,342 @@
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRegistry is a registry stand-in requiring token authentication.
+type testRegistry struct {
+	server    *httptest.Server
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	types     map[string]string
+	uploads   int
+}
+
+func newTestRegistry(t *testing.T) *testRegistry {
+	r := &testRegistry{
+		blobs:     map[string][]byte{},
+		manifests: map[string][]byte{},
+		types:     map[string]string{},
+	}
+
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			user, password, _ := req.BasicAuth()
+			if req.URL.Query().Get("service") != "test" || user != "user" || password != "secret" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": req.URL.Query().Get("scope")})
+			return
+		}
+
+		var repository, kind, reference string
+		for _, k := range []string{"manifests", "blobs"} {
+			path := strings.TrimPrefix(req.URL.Path, "/v2/")
+
+			i := strings.LastIndex(path, "/"+k+"/")
+			if i >= 0 {
+				repository, kind, reference = path[:i], k, path[i+len(k)+2:]
+			}
+		}
+
+		scope := "repository:" + repository + ":pull"
+		authorization := req.Header.Get("Authorization")
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			scope += ",push"
+		} else if authorization == "Bearer "+scope+",push" {
+			authorization = "Bearer " + scope
+		}
+
+		if authorization != "Bearer "+scope {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="%s"`, r.server.URL, scope))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case kind == "blobs" && req.Method == http.MethodPost:
+			w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/%d?state=abc", repository, r.uploads))
+			w.WriteHeader(http.StatusAccepted)
+		case kind == "blobs" && req.Method == http.MethodPut:
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			require.Equal(t, "abc", req.URL.Query().Get("state"))
+
+			descriptor := r.addBlob("", body)
+			if descriptor.Digest != req.URL.Query().Get("digest") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			r.uploads++
+			w.WriteHeader(http.StatusCreated)
+		case kind == "manifests" && req.Method == http.MethodPut:
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+
+			r.addManifest(t, repository, req.Header.Get("Content-Type"), json.RawMessage(body), reference)
+			w.WriteHeader(http.StatusCreated)
+		case kind == "manifests":
+			body, ok := r.manifests[repository+":"+reference]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`))
+				return
+			}
+
+			w.Header().Set("Content-Type", r.types[repository+":"+reference])
+			_, _ = w.Write(body)
+		case kind == "blobs":
+			body, ok := r.blobs[reference]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, _ = w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	t.Cleanup(r.server.Close)
+
+	return r
+}
+
+// addBlob stores a blob and returns its descriptor.
+func (r *testRegistry) addBlob(mediaType string, content []byte) Descriptor {
+	hash := sha256.Sum256(content)
+	digest := "sha256:" + hex.EncodeToString(hash[:])
+	r.blobs[digest] = content
+
+	return Descriptor{MediaType: mediaType, Digest: digest, Size: int64(len(content))}
+}
+
+// addManifest stores a manifest under its digest and the given tags and returns its descriptor.
+func (r *testRegistry) addManifest(t *testing.T, repository string, mediaType string, manifest any, tags ...string) Descriptor {
+	body, err := json.Marshal(manifest)
+	require.NoError(t, err)
+
+	descriptor := r.addBlob(mediaType, body)
+	for _, reference := range append(tags, descriptor.Digest) {
+		r.manifests[repository+":"+reference] = body
+		r.types[repository+":"+reference] = mediaType
+	}
+
+	return descriptor
+}
+
+// addImage stores an image for a platform and returns the descriptor of its manifest.
+func (r *testRegistry) addImage(t *testing.T, repository string, platform Platform, tags ...string) Descriptor {
+	config, err := json.Marshal(ImageConfig{Architecture: platform.Architecture, OS: platform.OS, Variant: platform.Variant})
+	require.NoError(t, err)
+
+	manifest := Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		Config:        r.addBlob(MediaTypeImageConfig, config),
+		Layers:        []Descriptor{r.addBlob(MediaTypeImageLayer, []byte(platform.Architecture))},
+	}
+
+	descriptor := r.addManifest(t, repository, MediaTypeImageManifest, manifest, tags...)
+	descriptor.Platform = &platform
+
+	return descriptor
+}
+
+func newTestClient(t *testing.T, r *testRegistry) *Client {
+	c, err := NewClient(r.server.Client(), strings.Replace(r.server.URL, "http://", "http://user:secret@", 1), "test")
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	for name, expected := range map[string][2]string{
+		"alpine":                   {"alpine", "latest"},
+		"alpine:3.20":              {"alpine", "3.20"},
+		"library/alpine:edge":      {"library/alpine", "edge"},
+		"org/app@" + digest:        {"org/app", digest},
+		"some/deep/repository:1.0": {"some/deep/repository", "1.0"},
+	} {
+		repository, reference, err := ParseReference(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, [2]string{repository, reference}, name)
+	}
+
+	for _, name := range []string{"", ":tag", "Alpine", "org/", "alpine@sha256:abc", "alpine@md5:" + strings.Repeat("a", 32)} {
+		_, _, err := ParseReference(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:foo/bar:pull,push"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:foo/bar:pull,push",
+	}, params)
+
+	scheme, params = parseChallenge(`Basic realm=registry`)
+	assert.Equal(t, "Basic", scheme)
+	assert.Equal(t, map[string]string{"realm": "registry"}, params)
+}
+
+func TestResolveIndex(t *testing.T) {
+	r := newTestRegistry(t)
+
+	index := Index{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageIndex,
+		Manifests: []Descriptor{
+			r.addImage(t, "library/app", Platform{OS: "linux", Architecture: "amd64"}),
+			r.addImage(t, "library/app", Platform{OS: "linux", Architecture: "arm", Variant: "v6"}),
+			r.addImage(t, "library/app", Platform{OS: "linux", Architecture: "arm", Variant: "v7"}),
+			r.addImage(t, "library/app", Platform{OS: "linux", Architecture: "arm64"}),
+		},
+	}
+
+	indexDescriptor := r.addManifest(t, "library/app", MediaTypeImageIndex, index, "latest")
+
+	c := newTestClient(t, r)
+
+	for architecture, expected := range map[string]Platform{
+		"x86_64":  {Architecture: "amd64"},
+		"aarch64": {Architecture: "arm64"},
+		"armv7l":  {Architecture: "arm", Variant: "v7"},
+		"armv8l":  {Architecture: "arm", Variant: "v7"},
+		"armv6l":  {Architecture: "arm", Variant: "v6"},
+	} {
+		platforms, err := ArchitecturePlatforms(architecture)
+		require.NoError(t, err)
+
+		img, err := c.Resolve(context.Background(), "library/app", platforms)
+		require.NoError(t, err, architecture)
+
+		assert.Equal(t, indexDescriptor.Digest, img.Digest)
+		assert.Equal(t, expected.Architecture, img.Config.Architecture, architecture)
+		assert.Equal(t, expected.Variant, img.Config.Variant, architecture)
+		assert.Equal(t, int64(len(expected.Architecture)), img.Size())
+
+		// The image architecture maps back to the most specific architecture.
+		if architecture != "armv8l" {
+			imgArchitecture, err := PlatformArchitecture(Platform{Architecture: img.Config.Architecture, Variant: img.Config.Variant})
+			require.NoError(t, err)
+			assert.Equal(t, architecture, imgArchitecture)
+		}
+	}
+
+	platforms, err := ArchitecturePlatforms("s390x")
+	require.NoError(t, err)
+
+	_, err = c.Resolve(context.Background(), "library/app", platforms)
+	assert.ErrorContains(t, err, "No image found for platform linux/s390x")
+}
+
+func TestResolveManifest(t *testing.T) {
+	r := newTestRegistry(t)
+	descriptor := r.addImage(t, "org/app", Platform{OS: "linux", Architecture: "amd64"}, "1.0")
+
+	c := newTestClient(t, r)
+
+	platforms, err := ArchitecturePlatforms("x86_64")
+	require.NoError(t, err)
+
+	img, err := c.Resolve(context.Background(), "org/app:1.0", platforms)
+	require.NoError(t, err)
+	assert.Equal(t, descriptor.Digest, img.Digest)
+	assert.Equal(t, "org/app", img.Repository)
+
+	img, err = c.Resolve(context.Background(), "org/app@"+descriptor.Digest, platforms)
+	require.NoError(t, err)
+	assert.Equal(t, descriptor.Digest, img.Digest)
+
+	_, err = c.Resolve(context.Background(), "org/app:2.0", platforms)
+	assert.ErrorContains(t, err, "manifest unknown")
+
+	// Authentication fails without the right credentials.
+	anonymous, err := NewClient(r.server.Client(), r.server.URL, "test")
+	require.NoError(t, err)
+
+	_, err = anonymous.Resolve(context.Background(), "org/app:1.0", platforms)
+	assert.ErrorContains(t, err, "Failed to get registry token")
+}
+
+func TestGetBlob(t *testing.T) {
+	r := newTestRegistry(t)
+	descriptor := r.addBlob(MediaTypeImageLayer, []byte("layer"))
+
+	c := newTestClient(t, r)
+
+	rc, err := c.GetBlob(context.Background(), "org/app", descriptor)
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "layer", string(content))
+	require.NoError(t, rc.Close())
+
+	// Corrupted content is detected.
+	r.blobs[descriptor.Digest] = []byte("Layer")
+
+	rc, err = c.GetBlob(context.Background(), "org/app", descriptor)
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(rc)
+	assert.ErrorContains(t, err, "Blob digest mismatch")
+	require.NoError(t, rc.Close())
+
+	// Truncated content is detected.
+	r.blobs[descriptor.Digest] = []byte("lay")
+
+	rc, err = c.GetBlob(context.Background(), "org/app", descriptor)
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(rc)
+	assert.ErrorContains(t, err, "Blob size mismatch")
+	require.NoError(t, rc.Close())
+}
+
+func TestPush(t *testing.T) {
+	r := newTestRegistry(t)
+	c := newTestClient(t, r)
+
+	img := newTestLocalImage(t)
+
+	require.NoError(t, c.Push(context.Background(), "org/app:1.0", img))
+	assert.Equal(t, 2, r.uploads)
+
+	// Existing blobs aren't uploaded again.
+	require.NoError(t, c.Push(context.Background(), "org/app:1.1", img))
+	assert.Equal(t, 2, r.uploads)
+
+	// The pushed image can be pulled back.
+	platforms, err := ArchitecturePlatforms("aarch64")
+	require.NoError(t, err)
+
+	pulled, err := c.Resolve(context.Background(), "org/app:1.1", platforms)
+	require.NoError(t, err)
+	assert.Equal(t, img.Manifest.Digest, pulled.Digest)
+	assert.Equal(t, []string{"/sbin/init"}, pulled.Config.Config.Entrypoint)
+	assert.Equal(t, img.Layers, pulled.Manifest.Layers)
+}
@@ -0,0 +1,155 @@
+package oci
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// defaultPath is the PATH used for images which don't set one.
+const defaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// RuntimeSpec generates the runtime configuration of a container using the image.
+//
+// The root filesystem is used to resolve the user and group names of the image.
+func RuntimeSpec(config ImageConfig, rootfs string) (*ociSpecs.Spec, error) {
+	uid, gid, err := resolveUser(config.Config.User, rootfs)
+	if err != nil {
+		return nil, err
+	}
+
+	env := slices.Clone(config.Config.Env)
+	if !slices.ContainsFunc(env, func(value string) bool { return strings.HasPrefix(value, "PATH=") }) {
+		env = append([]string{"PATH=" + defaultPath}, env...)
+	}
+
+	cwd := config.Config.WorkingDir
+	if cwd == "" {
+		cwd = "/"
+	}
+
+	spec := &ociSpecs.Spec{
+		Version: ociSpecs.Version,
+		Process: &ociSpecs.Process{
+			User: ociSpecs.User{UID: uid, GID: gid},
+			Args: append(slices.Clone(config.Config.Entrypoint), config.Config.Cmd...),
+			Env:  env,
+			Cwd:  cwd,
+		},
+		Root: &ociSpecs.Root{Path: "rootfs"},
+		Mounts: []ociSpecs.Mount{
+			{Destination: "/proc", Type: "proc", Source: "proc"},
+			{Destination: "/dev", Type: "tmpfs", Source: "tmpfs", Options: []string{"nosuid", "strictatime", "mode=755", "size=65536k"}},
+			{Destination: "/dev/pts", Type: "devpts", Source: "devpts", Options: []string{"nosuid", "noexec", "newinstance", "ptmxmode=0666", "mode=0620", "gid=5"}},
+			{Destination: "/dev/shm", Type: "tmpfs", Source: "shm", Options: []string{"nosuid", "noexec", "nodev", "mode=1777", "size=65536k"}},
+			{Destination: "/dev/mqueue", Type: "mqueue", Source: "mqueue", Options: []string{"nosuid", "noexec", "nodev"}},
+			{Destination: "/sys", Type: "sysfs", Source: "sysfs", Options: []string{"nosuid", "noexec", "nodev", "ro"}},
+			{Destination: "/sys/fs/cgroup", Type: "cgroup", Source: "cgroup", Options: []string{"nosuid", "noexec", "nodev", "relatime", "ro"}},
+		},
+		Annotations: map[string]string{},
+	}
+
+	for key, value := range config.Config.Labels {
+		spec.Annotations[key] = value
+	}
+
+	if config.Created != nil {
+		spec.Annotations[AnnotationCreated] = config.Created.UTC().Format(time.RFC3339)
+	}
+
+	if config.Config.StopSignal != "" {
+		spec.Annotations["org.opencontainers.image.stopSignal"] = config.Config.StopSignal
+	}
+
+	return spec, nil
+}
+
+// resolveUser resolves the user of an image into its uid and gid.
+func resolveUser(user string, rootfs string) (uint32, uint32, error) {
+	if user == "" {
+		return 0, 0, nil
+	}
+
+	userName, groupName, hasGroup := strings.Cut(user, ":")
+
+	// Resolve the user, getting its primary group.
+	uid, gid, err := lookupID(rootfs, "/etc/passwd", userName)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Failed to resolve user %q: %w", userName, err)
+	}
+
+	if hasGroup {
+		gid, _, err = lookupID(rootfs, "/etc/group", groupName)
+		if err != nil {
+			return 0, 0, fmt.Errorf("Failed to resolve group %q: %w", groupName, err)
+		}
+	}
+
+	return uid, gid, nil
+}
+
+// lookupID looks a name up in a passwd or group file, returning its id and group id.
+//
+// Numeric names are used as they are, in which case the group id defaults to 0
+// unless the id is found in the file.
+func lookupID(rootfs string, file string, name string) (uint32, uint32, error) {
+	numericID, numericErr := strconv.ParseUint(name, 10, 32)
+
+	path, err := secureJoin(rootfs, file)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if numericErr == nil {
+			return uint32(numericID), 0, nil
+		}
+
+		return 0, 0, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 3 {
+			continue
+		}
+
+		id, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			continue
+		}
+
+		if fields[0] != name && (numericErr != nil || id != numericID) {
+			continue
+		}
+
+		var gid uint64
+		if filepath.Base(file) == "passwd" && len(fields) >= 4 {
+			gid, _ = strconv.ParseUint(fields[3], 10, 32)
+		}
+
+		return uint32(id), uint32(gid), nil
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if numericErr == nil {
+		return uint32(numericID), 0, nil
+	}
+
+	return 0, 0, fmt.Errorf("No entry in %s", file)
+}
@@ -0,0 +1,135 @@
+package oci
+
+import (
+	"time"
+)
+
+// Media types of the manifests and indexes understood by the client.
+const (
+	MediaTypeImageIndex         = "application/vnd.oci.image.index.v1+json"
+	MediaTypeImageManifest      = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeImageConfig        = "application/vnd.oci.image.config.v1+json"
+	MediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	MediaTypeDockerImageConfig  = "application/vnd.docker.container.image.v1+json"
+)
+
+// Layout files and annotations.
+const (
+	LayoutVersion         = "1.0.0"
+	SourceFile            = "oci-source.json"
+	AnnotationRefName     = "org.opencontainers.image.ref.name"
+	AnnotationCreated     = "org.opencontainers.image.created"
+	AnnotationDescription = "org.opencontainers.image.description"
+)
+
+// Media types of the image layers.
+const (
+	MediaTypeImageLayer     = "application/vnd.oci.image.layer.v1.tar"
+	MediaTypeImageLayerGzip = "application/vnd.oci.image.layer.v1.tar+gzip"
+	MediaTypeImageLayerZstd = "application/vnd.oci.image.layer.v1.tar+zstd"
+	MediaTypeDockerLayer    = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+)
+
+// Descriptor describes the content of a blob or manifest.
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Platform    *Platform         `json:"platform,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Platform describes the platform an image manifest applies to.
+type Platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// Index is a list of manifests, one per platform (also known as manifest list).
+type Index struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Manifests     []Descriptor `json:"manifests"`
+}
+
+// Manifest describes the configuration and layers of an image.
+type Manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Config        Descriptor   `json:"config"`
+	Layers        []Descriptor `json:"layers"`
+}
+
+// ImageConfig is the image configuration blob.
+type ImageConfig struct {
+	Created      *time.Time      `json:"created,omitempty"`
+	Architecture string          `json:"architecture"`
+	OS           string          `json:"os"`
+	Variant      string          `json:"variant,omitempty"`
+	Config       ContainerConfig `json:"config"`
+	RootFS       RootFS          `json:"rootfs"`
+}
+
+// RootFS lists the uncompressed digests of the layers of an image.
+type RootFS struct {
+	Type    string   `json:"type"`
+	DiffIDs []string `json:"diff_ids"`
+}
+
+// ContainerConfig holds the execution parameters of an image.
+type ContainerConfig struct {
+	User         string              `json:"User,omitempty"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	Env          []string            `json:"Env,omitempty"`
+	Entrypoint   []string            `json:"Entrypoint,omitempty"`
+	Cmd          []string            `json:"Cmd,omitempty"`
+	Volumes      map[string]struct{} `json:"Volumes,omitempty"`
+	WorkingDir   string              `json:"WorkingDir,omitempty"`
+	Labels       map[string]string   `json:"Labels,omitempty"`
+	StopSignal   string              `json:"StopSignal,omitempty"`
+}
+
+// Image is an image resolved from a registry for a given platform.
+type Image struct {
+	// Repository is the full name of the repository on the registry.
+	Repository string
+
+	// Digest is the digest the reference resolved to, either of the index or of the manifest.
+	Digest string
+
+	// Manifest is the manifest selected for the platform.
+	Manifest Manifest
+
+	// Config is the configuration of the selected manifest.
+	Config ImageConfig
+}
+
+// Size returns the total size of the image layers.
+func (i *Image) Size() int64 {
+	var size int64
+	for _, layer := range i.Manifest.Layers {
+		size += layer.Size
+	}
+
+	return size
+}
+
+// Source describes the image of a registry an image was converted from.
+//
+// It's stored in the metadata of the converted image so that the original
+// layers can be retrieved when the image gets converted back to an OCI image.
+type Source struct {
+	// Registry is the URL of the registry, without credentials.
+	Registry string `json:"registry"`
+
+	// Repository is the full name of the repository on the registry.
+	Repository string `json:"repository"`
+
+	// Layers are the descriptors of the image layers.
+	Layers []Descriptor `json:"layers"`
+
+	// DiffIDs are the uncompressed digests of the image layers.
+	DiffIDs []string `json:"diff_ids"`
+}
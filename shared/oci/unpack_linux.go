@@ -0,0 +1,304 @@
+//go:build linux
+
+package oci
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// UnpackLayer applies a layer, possibly compressed, on top of the root filesystem.
+//
+// Whiteout entries remove the matching content of the lower layers. Ownership
+// and device nodes are only restored when running as root.
+func UnpackLayer(r io.Reader, rootfs string) error {
+	reader, err := decompress(r)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = reader.Close() }()
+
+	u := unpacker{
+		rootfs:  rootfs,
+		root:    os.Geteuid() == 0,
+		created: map[string]bool{},
+	}
+
+	tr := tar.NewReader(reader)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return fmt.Errorf("Failed to read layer: %w", err)
+		}
+
+		err = u.apply(hdr, tr)
+		if err != nil {
+			return fmt.Errorf("Failed to unpack %q: %w", hdr.Name, err)
+		}
+	}
+
+	// Consume any trailing data so the digest of the blob gets verified.
+	_, err = io.Copy(io.Discard, r)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// unpacker holds the state of a layer being unpacked.
+type unpacker struct {
+	rootfs string
+	root   bool
+
+	// created records the paths created by the layer, kept by opaque whiteouts.
+	created map[string]bool
+}
+
+// apply applies a single entry of the layer.
+func (u *unpacker) apply(hdr *tar.Header, r io.Reader) error {
+	name := filepath.Clean("/" + hdr.Name)
+	base := filepath.Base(name)
+
+	// Resolve the parent directory within the root filesystem.
+	parent, err := secureJoin(u.rootfs, filepath.Dir(name))
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(parent, 0755)
+	if err != nil {
+		return err
+	}
+
+	// Handle whiteouts.
+	if base == whiteoutOpaque {
+		return u.opaque(parent)
+	}
+
+	if strings.HasPrefix(base, whiteoutPrefix) {
+		return u.whiteout(filepath.Dir(name), strings.TrimPrefix(base, whiteoutPrefix))
+	}
+
+	path := filepath.Join(parent, base)
+	if name == "/" {
+		if hdr.Typeflag != tar.TypeDir {
+			return nil
+		}
+
+		path = u.rootfs
+	}
+
+	// Replace whatever is in the way, except for directories (or symlinks to them) receiving a directory.
+	fi, err := os.Lstat(path)
+	if err == nil {
+		if hdr.Typeflag == tar.TypeDir && fi.Mode()&os.ModeSymlink != 0 {
+			target, err := secureJoin(u.rootfs, strings.TrimPrefix(path, u.rootfs))
+			if err != nil {
+				return err
+			}
+
+			targetInfo, err := os.Stat(target)
+			if err == nil && targetInfo.IsDir() {
+				u.created[path] = true
+				return nil
+			}
+		}
+
+		if hdr.Typeflag != tar.TypeDir || !fi.IsDir() {
+			err = os.RemoveAll(path)
+			if err != nil {
+				return err
+			}
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		err = os.Mkdir(path, 0700)
+		if err != nil && !errors.Is(err, os.ErrExist) {
+			return err
+		}
+
+	case tar.TypeReg:
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(f, r)
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+
+		err = f.Close()
+		if err != nil {
+			return err
+		}
+
+	case tar.TypeSymlink:
+		err = os.Symlink(hdr.Linkname, path)
+		if err != nil {
+			return err
+		}
+
+	case tar.TypeLink:
+		linkName := filepath.Clean("/" + hdr.Linkname)
+
+		linkParent, err := secureJoin(u.rootfs, filepath.Dir(linkName))
+		if err != nil {
+			return err
+		}
+
+		err = os.Link(filepath.Join(linkParent, filepath.Base(linkName)), path)
+		if err != nil {
+			return err
+		}
+
+		// Hardlinks share the metadata of their target.
+		u.created[path] = true
+		return nil
+
+	case tar.TypeChar, tar.TypeBlock, tar.TypeFifo:
+		mode := uint32(hdr.Mode & 07777)
+		switch hdr.Typeflag {
+		case tar.TypeChar:
+			mode |= unix.S_IFCHR
+		case tar.TypeBlock:
+			mode |= unix.S_IFBLK
+		case tar.TypeFifo:
+			mode |= unix.S_IFIFO
+		}
+
+		// Device nodes can only be created by root.
+		if !u.root && hdr.Typeflag != tar.TypeFifo {
+			return nil
+		}
+
+		err = unix.Mknod(path, mode, int(unix.Mkdev(uint32(hdr.Devmajor), uint32(hdr.Devminor))))
+		if err != nil {
+			return err
+		}
+
+	default:
+		// Skip unsupported entries (global headers, ...).
+		return nil
+	}
+
+	u.created[path] = true
+
+	return u.applyMetadata(hdr, path)
+}
+
+// applyMetadata restores the ownership, permissions, extended attributes and timestamps of an entry.
+func (u *unpacker) applyMetadata(hdr *tar.Header, path string) error {
+	if u.root {
+		err := os.Lchown(path, hdr.Uid, hdr.Gid)
+		if err != nil {
+			return err
+		}
+	}
+
+	for key, value := range hdr.PAXRecords {
+		attr, ok := strings.CutPrefix(key, "SCHILY.xattr.")
+		if !ok {
+			continue
+		}
+
+		err := unix.Lsetxattr(path, attr, []byte(value), 0)
+		if err != nil && u.root && !errors.Is(err, unix.ENOTSUP) {
+			return fmt.Errorf("Failed to set extended attribute %q: %w", attr, err)
+		}
+	}
+
+	// Permissions are applied last as changing ownership resets the setuid and setgid bits.
+	if hdr.Typeflag != tar.TypeSymlink {
+		mode := hdr.FileInfo().Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+
+		err := os.Chmod(path, mode)
+		if err != nil {
+			return err
+		}
+	}
+
+	times := []unix.Timespec{unix.NsecToTimespec(hdr.AccessTime.UnixNano()), unix.NsecToTimespec(hdr.ModTime.UnixNano())}
+	if hdr.AccessTime.IsZero() {
+		times[0] = times[1]
+	}
+
+	err := unix.UtimesNanoAt(unix.AT_FDCWD, path, times, unix.AT_SYMLINK_NOFOLLOW)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// whiteout removes an entry of the lower layers from a directory of the root filesystem.
+//
+// The directory is resolved within the root filesystem but the entry itself
+// isn't followed, so whiting out a symlink removes the symlink and not its target.
+func (u *unpacker) whiteout(dir string, name string) error {
+	if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+		return fmt.Errorf("Invalid whiteout entry %q", whiteoutPrefix+name)
+	}
+
+	parent, err := secureJoin(u.rootfs, dir)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(parent, name)
+
+	rel, err := filepath.Rel(u.rootfs, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+		return fmt.Errorf("Whiteout entry %q is outside of the root filesystem", whiteoutPrefix+name)
+	}
+
+	return os.RemoveAll(path)
+}
+
+// opaque removes the content of a directory which wasn't created by the current layer.
+func (u *unpacker) opaque(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if u.created[path] {
+			// Directories re-created by the layer still hide their lower content.
+			if entry.IsDir() {
+				err = u.opaque(path)
+				if err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		err = os.RemoveAll(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
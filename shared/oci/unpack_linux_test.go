@@ -0,0 +1,278 @@
+//go:build linux
+
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// layerEntry is an entry of a test layer, regular files having content.
+type layerEntry struct {
+	name     string
+	typeflag byte
+	content  string
+	linkname string
+	mode     int64
+}
+
+func buildLayer(t *testing.T, entries []layerEntry) []byte {
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		if entry.mode == 0 {
+			entry.mode = 0644
+			if entry.typeflag == tar.TypeDir {
+				entry.mode = 0755
+			}
+		}
+
+		hdr := &tar.Header{
+			Name:     entry.name,
+			Typeflag: entry.typeflag,
+			Linkname: entry.linkname,
+			Mode:     entry.mode,
+			Size:     int64(len(entry.content)),
+		}
+
+		require.NoError(t, tw.WriteHeader(hdr))
+
+		_, err := tw.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+
+	return buf.Bytes()
+}
+
+func gzipLayer(t *testing.T, layer []byte) []byte {
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	_, err := gw.Write(layer)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	return buf.Bytes()
+}
+
+func zstdLayer(t *testing.T, layer []byte) []byte {
+	var buf bytes.Buffer
+
+	zw, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+
+	_, err = zw.Write(layer)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func readFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return string(content)
+}
+
+func TestUnpackLayerWhiteouts(t *testing.T) {
+	rootfs := t.TempDir()
+
+	lower := buildLayer(t, []layerEntry{
+		{name: "etc/", typeflag: tar.TypeDir},
+		{name: "etc/hostname", typeflag: tar.TypeReg, content: "lower"},
+		{name: "etc/removed", typeflag: tar.TypeReg, content: "removed"},
+		{name: "etc/link", typeflag: tar.TypeLink, linkname: "etc/hostname"},
+		{name: "opaque/", typeflag: tar.TypeDir},
+		{name: "opaque/lower", typeflag: tar.TypeReg, content: "lower"},
+		{name: "opaque/sub/lower", typeflag: tar.TypeReg, content: "lower"},
+		{name: "bin/sh", typeflag: tar.TypeReg, content: "shell", mode: 04755},
+		{name: "usr/lib/", typeflag: tar.TypeDir},
+		{name: "lib", typeflag: tar.TypeSymlink, linkname: "usr/lib"},
+	})
+
+	upper := buildLayer(t, []layerEntry{
+		{name: "etc/.wh.removed", typeflag: tar.TypeReg},
+		{name: "etc/hostname", typeflag: tar.TypeReg, content: "upper"},
+		{name: "opaque/upper", typeflag: tar.TypeReg, content: "upper"},
+		{name: "opaque/sub/", typeflag: tar.TypeDir},
+		{name: "opaque/.wh..wh..opq", typeflag: tar.TypeReg},
+		{name: "lib/", typeflag: tar.TypeDir},
+		{name: "lib/libc.so", typeflag: tar.TypeReg, content: "libc"},
+	})
+
+	require.NoError(t, UnpackLayer(bytes.NewReader(lower), rootfs))
+	require.NoError(t, UnpackLayer(bytes.NewReader(gzipLayer(t, upper)), rootfs))
+
+	// Regular whiteouts remove the lower content.
+	assert.NoFileExists(t, filepath.Join(rootfs, "etc/removed"))
+	assert.NoFileExists(t, filepath.Join(rootfs, "etc/.wh.removed"))
+
+	// Files get replaced, breaking hardlinks.
+	assert.Equal(t, "upper", readFile(t, filepath.Join(rootfs, "etc/hostname")))
+	assert.Equal(t, "lower", readFile(t, filepath.Join(rootfs, "etc/link")))
+
+	// Opaque whiteouts hide the lower content only.
+	assert.Equal(t, "upper", readFile(t, filepath.Join(rootfs, "opaque/upper")))
+	assert.NoFileExists(t, filepath.Join(rootfs, "opaque/lower"))
+	assert.NoFileExists(t, filepath.Join(rootfs, "opaque/sub/lower"))
+	assert.DirExists(t, filepath.Join(rootfs, "opaque/sub"))
+	assert.NoFileExists(t, filepath.Join(rootfs, "opaque/.wh..wh..opq"))
+
+	// Symlinks to directories are kept.
+	fi, err := os.Lstat(filepath.Join(rootfs, "lib"))
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeSymlink, fi.Mode().Type())
+	assert.Equal(t, "libc", readFile(t, filepath.Join(rootfs, "usr/lib/libc.so")))
+
+	// Permissions are restored.
+	fi, err = os.Stat(filepath.Join(rootfs, "bin/sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755)|os.ModeSetuid, fi.Mode()&(os.ModePerm|os.ModeSetuid))
+}
+
+func TestUnpackLayerEscape(t *testing.T) {
+	dir := t.TempDir()
+	rootfs := filepath.Join(dir, "rootfs")
+	require.NoError(t, os.Mkdir(rootfs, 0755))
+
+	layer := buildLayer(t, []layerEntry{
+		{name: "../../escape", typeflag: tar.TypeReg, content: "dotdot"},
+		{name: "abs", typeflag: tar.TypeSymlink, linkname: dir},
+		{name: "abs/escape", typeflag: tar.TypeReg, content: "absolute"},
+		{name: "rel", typeflag: tar.TypeSymlink, linkname: "../../.."},
+		{name: "rel/escape-rel", typeflag: tar.TypeReg, content: "relative"},
+		{name: "hardlink", typeflag: tar.TypeLink, linkname: "../../escape"},
+	})
+
+	require.NoError(t, UnpackLayer(bytes.NewReader(zstdLayer(t, layer)), rootfs))
+
+	// Everything ends up within the root filesystem.
+	assert.NoFileExists(t, filepath.Join(dir, "escape"))
+	assert.NoFileExists(t, filepath.Join(dir, "escape-rel"))
+	assert.Equal(t, "dotdot", readFile(t, filepath.Join(rootfs, "escape")))
+	assert.Equal(t, "absolute", readFile(t, filepath.Join(rootfs, dir, "escape")))
+	assert.Equal(t, "relative", readFile(t, filepath.Join(rootfs, "escape-rel")))
+	assert.Equal(t, "dotdot", readFile(t, filepath.Join(rootfs, "hardlink")))
+}
+
+func TestUnpackLayerMaliciousWhiteouts(t *testing.T) {
+	tests := []struct {
+		name      string
+		whiteout  string
+		expectErr bool
+	}{
+		{name: "Empty name", whiteout: "etc/.wh.", expectErr: true},
+		{name: "Empty name at the root", whiteout: ".wh.", expectErr: true},
+		{name: "Current directory", whiteout: "etc/.wh..", expectErr: true},
+		{name: "Parent directory", whiteout: "etc/.wh...", expectErr: true},
+		{name: "Parent of the root", whiteout: ".wh...", expectErr: true},
+		{name: "Dot-dot path", whiteout: "../../.wh.outside"},
+		{name: "Absolute symlink", whiteout: "abs/.wh.outside"},
+		{name: "Relative symlink", whiteout: "rel/.wh.outside"},
+		{name: "Symlink to the outside", whiteout: ".wh.escape"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			rootfs := filepath.Join(dir, "rootfs")
+			require.NoError(t, os.Mkdir(rootfs, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "outside"), []byte("outside"), 0644))
+
+			lower := buildLayer(t, []layerEntry{
+				{name: "etc/", typeflag: tar.TypeDir},
+				{name: "etc/hostname", typeflag: tar.TypeReg, content: "lower"},
+				{name: "abs", typeflag: tar.TypeSymlink, linkname: dir},
+				{name: "rel", typeflag: tar.TypeSymlink, linkname: "../../.."},
+				{name: "escape", typeflag: tar.TypeSymlink, linkname: filepath.Join(dir, "outside")},
+			})
+
+			require.NoError(t, UnpackLayer(bytes.NewReader(lower), rootfs))
+
+			upper := buildLayer(t, []layerEntry{{name: tt.whiteout, typeflag: tar.TypeReg}})
+
+			err := UnpackLayer(bytes.NewReader(upper), rootfs)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Nothing outside of the root filesystem is removed, nor the root filesystem itself.
+			assert.Equal(t, "outside", readFile(t, filepath.Join(dir, "outside")))
+			assert.Equal(t, "lower", readFile(t, filepath.Join(rootfs, "etc/hostname")))
+		})
+	}
+
+	// Whiting out a symlink removes the symlink and not its target.
+	dir := t.TempDir()
+	rootfs := filepath.Join(dir, "rootfs")
+	require.NoError(t, os.Mkdir(rootfs, 0755))
+
+	lower := buildLayer(t, []layerEntry{
+		{name: "usr/lib/libc.so", typeflag: tar.TypeReg, content: "libc"},
+		{name: "lib", typeflag: tar.TypeSymlink, linkname: "/usr/lib"},
+	})
+
+	upper := buildLayer(t, []layerEntry{{name: ".wh.lib", typeflag: tar.TypeReg}})
+
+	require.NoError(t, UnpackLayer(bytes.NewReader(lower), rootfs))
+	require.NoError(t, UnpackLayer(bytes.NewReader(upper), rootfs))
+
+	_, err := os.Lstat(filepath.Join(rootfs, "lib"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.Equal(t, "libc", readFile(t, filepath.Join(rootfs, "usr/lib/libc.so")))
+}
+
+func TestRuntimeSpec(t *testing.T) {
+	rootfs := t.TempDir()
+
+	layer := buildLayer(t, []layerEntry{
+		{name: "etc/passwd", typeflag: tar.TypeReg, content: "root:x:0:0::/root:/bin/sh\nnginx:x:101:102::/nonexistent:/bin/false\n"},
+		{name: "etc/group", typeflag: tar.TypeReg, content: "root:x:0:\nnginx:x:102:\nwww:x:33:\n"},
+	})
+
+	require.NoError(t, UnpackLayer(bytes.NewReader(layer), rootfs))
+
+	config := ImageConfig{Config: ContainerConfig{
+		User:       "nginx",
+		Env:        []string{"FOO=bar"},
+		Entrypoint: []string{"/docker-entrypoint.sh"},
+		Cmd:        []string{"nginx", "-g", "daemon off;"},
+	}}
+
+	spec, err := RuntimeSpec(config, rootfs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/docker-entrypoint.sh", "nginx", "-g", "daemon off;"}, spec.Process.Args)
+	assert.Equal(t, []string{"PATH=" + defaultPath, "FOO=bar"}, spec.Process.Env)
+	assert.Equal(t, "/", spec.Process.Cwd)
+	assert.Equal(t, uint32(101), spec.Process.User.UID)
+	assert.Equal(t, uint32(102), spec.Process.User.GID)
+
+	for user, expected := range map[string][2]uint32{
+		"":           {0, 0},
+		"nginx:www":  {101, 33},
+		"101":        {101, 102},
+		"1000:1000":  {1000, 1000},
+		"nginx:1234": {101, 1234},
+	} {
+		uid, gid, err := resolveUser(user, rootfs)
+		require.NoError(t, err, user)
+		assert.Equal(t, expected, [2]uint32{uid, gid}, user)
+	}
+
+	_, _, err = resolveUser("missing", rootfs)
+	assert.Error(t, err)
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package oci
+
+import (
+	"errors"
+	"io"
+)
+
+// UnpackLayer applies a layer, possibly compressed, on top of the root filesystem.
+func UnpackLayer(r io.Reader, rootfs string) error {
+	return errors.New("Unpacking OCI layers is only supported on Linux")
+}
@@ -8,7 +8,7 @@ spawn_oidc() {
 
     PORT="$(local_tcp_port)"
     echo "${PORT}" > "${TEST_DIR}/oidc.port"
-    ./mini-oidc "${PORT}" "${TEST_DIR}/oidc.user" &
+    ./mini-oidc "${PORT}" "${TEST_DIR}/oidc.user" "${TEST_DIR}/oidc.groups" &
     echo $! > "${TEST_DIR}/oidc.pid"
 
     sleep 3
@@ -23,4 +23,5 @@ kill_oidc() {
 
 set_oidc() {
   echo "${1}" > "${TEST_DIR}/oidc.user"
+  echo "${2:-}" > "${TEST_DIR}/oidc.groups"
 }
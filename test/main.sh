@@ -209,6 +209,13 @@ if [ "${1:-"all"}" != "cluster" ]; then
     run_test test_tls_restrictions "TLS restrictions"
     run_test test_oidc "OpenID Connect"
     run_test test_openfga "OpenFGA"
+    run_test test_auth_rbac "built-in RBAC"
+    run_test test_auth_rbac_oidc_groups "built-in RBAC with OIDC groups"
+    run_test test_auth_tokens "scoped API tokens"
+    run_test test_audit "audit log"
+    run_test test_event_history "event history"
+    run_test test_webhooks "webhooks"
+    run_test test_otlp "OpenTelemetry export"
     run_test test_certificate_edit "Certificate edit"
     run_test test_basic_usage "basic usage"
     run_test test_remote_url "remote url handling"
@@ -241,6 +248,7 @@ if [ "${1:-"all"}" != "standalone" ]; then
     run_test test_clustering_image_refresh "clustering image refresh"
     run_test test_clustering_evacuation "clustering evacuation"
     run_test test_clustering_instance_placement_scriptlet "clustering instance placement scriptlet"
+    run_test test_clustering_instance_placement_rules "clustering instance placement rules"
     run_test test_clustering_move "clustering move"
     run_test test_clustering_edit_configuration "clustering config edit"
     run_test test_clustering_remove_members "clustering config remove members"
@@ -320,8 +328,11 @@ if [ "${1:-"all"}" != "cluster" ]; then
     run_test test_network "network management"
     run_test test_network_dhcp_routes "network dhcp routes"
     run_test test_network_acl "network ACL management"
+    run_test test_network_address_set "network address sets"
     run_test test_network_forward "network address forwards"
     run_test test_network_zone "network DNS zones"
+    run_test test_network_wireguard "network WireGuard"
+    run_test test_network_bridge_evpn "network bridge EVPN"
     run_test test_idmap "id mapping"
     run_test test_template "file templating"
     run_test test_pki "PKI mode"
@@ -343,6 +354,7 @@ if [ "${1:-"all"}" != "cluster" ]; then
     run_test test_storage_driver_btrfs "btrfs storage driver"
     run_test test_storage_driver_ceph "ceph storage driver"
     run_test test_storage_driver_cephfs "cephfs storage driver"
+    run_test test_storage_driver_netfs "netfs storage driver"
     run_test test_storage_driver_zfs "zfs storage driver"
     run_test test_storage_buckets "storage buckets"
     run_test test_storage_bucket_export "storage buckets export and import"
@@ -358,9 +370,12 @@ if [ "${1:-"all"}" != "cluster" ]; then
     run_test test_backup_rename "backup rename"
     run_test test_backup_volume_export "backup volume export"
     run_test test_backup_export_import_instance_only "backup export and import instance only"
+    run_test test_backup_export_import_incremental "backup export and import incremental"
     run_test test_backup_volume_rename_delete "backup volume rename and delete"
     run_test test_backup_different_instance_uuid "backup instance and check instance UUIDs"
     run_test test_backup_volume_expiry "backup volume expiry"
+    run_test test_backup_schedule "backup schedule"
+    run_test test_backup_bucket "backup upload to storage bucket"
     run_test test_backup_export_import_recover "backup export, import, and recovery"
     run_test test_container_local_cross_pool_handling "container local cross pool handling"
     run_test test_incremental_copy "incremental container copy"
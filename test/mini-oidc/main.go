@@ -97,6 +97,26 @@ func userCodeHandler(storage *storage.Storage, w http.ResponseWriter, r *http.Re
 	return
 }
 
+func groups() []string {
+	if len(os.Args) < 4 {
+		return nil
+	}
+
+	content, err := os.ReadFile(os.Args[3])
+	if err != nil {
+		return nil
+	}
+
+	groups := []string{}
+	for _, group := range strings.Split(strings.TrimSpace(string(content)), ",") {
+		if group != "" {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
 func username() string {
 	userName := "unknown"
 
@@ -120,6 +140,7 @@ func (u userStore) GetUserByID(string) *storage.User {
 	return &storage.User{
 		ID:       name,
 		Username: name,
+		Groups:   groups(),
 	}
 }
 
@@ -129,5 +150,6 @@ func (u userStore) GetUserByUsername(string) *storage.User {
 	return &storage.User{
 		ID:       name,
 		Username: name,
+		Groups:   groups(),
 	}
 }
@@ -530,6 +530,13 @@ func (s *Storage) GetPrivateClaimsFromScopes(ctx context.Context, userID, client
 }
 
 func (s *Storage) getPrivateClaimsFromScopes(ctx context.Context, userID, clientID string, scopes []string) (claims map[string]any, err error) {
+	if userID != "" {
+		user := s.userStore.GetUserByID(userID)
+		if user != nil && len(user.Groups) > 0 {
+			claims = appendClaim(claims, "groups", user.Groups)
+		}
+	}
+
 	for _, scope := range scopes {
 		switch scope {
 		case CustomScope:
@@ -19,6 +19,7 @@ type User struct {
 	PhoneVerified     bool
 	PreferredLanguage language.Tag
 	IsAdmin           bool
+	Groups            []string
 }
 
 type Service struct {
@@ -0,0 +1,48 @@
+test_audit() {
+  audit_log="${INCUS_DIR}/logs/audit.log"
+
+  incus config set core.https_address "${INCUS_ADDR}"
+  incus config set audit.enabled=true audit.file.max_size=1KiB audit.file.max_files=2
+
+  echo "==> Checking successful requests are recorded..."
+  incus project create audit-test
+  incus project list >/dev/null
+  jq -e 'select(.method == "POST" and .url == "/1.0/projects" and .outcome == "success" and .status_code == 201 and .protocol == "unix")' "${audit_log}"
+  jq -e 'select(.body_digest != "")' "${audit_log}" >/dev/null
+  ! jq -e 'select(.method == "GET")' "${audit_log}" || false
+
+  echo "==> Checking failed and denied requests are recorded..."
+  ! incus project create audit-test || false
+  jq -e 'select(.method == "POST" and .url == "/1.0/projects" and .outcome == "failure")' "${audit_log}"
+
+  curl -k -s -X DELETE "https://${INCUS_ADDR}/1.0/projects/audit-test" >/dev/null
+  jq -e 'select(.method == "DELETE" and .url == "/1.0/projects/audit-test" and .outcome == "denied" and .status_code == 403)' "${audit_log}"
+  incus project show audit-test >/dev/null
+
+  echo "==> Checking operations are recorded with their final outcome..."
+  incus init --empty audit-c1 --project audit-test -s "$(incus profile device get default root pool)"
+  ! incus start audit-c1 --project audit-test || false
+  sleep 1
+  cat "${audit_log}"* | jq -e 'select(.method == "POST" and (.url | startswith("/1.0/instances?")) and .outcome == "success" and .status_code == 202 and .operation != "")'
+  cat "${audit_log}"* | jq -e 'select(.method == "PUT" and (.url | startswith("/1.0/instances/audit-c1/state?")) and .outcome == "failure" and .status_code == 202)'
+  incus delete audit-c1 --project audit-test
+
+  echo "==> Checking rotation..."
+  for i in $(seq 10); do
+    incus project set audit-test user.foo="${i}"
+  done
+
+  [ -e "${audit_log}.1" ]
+  [ -e "${audit_log}.2" ]
+  [ ! -e "${audit_log}.3" ]
+
+  echo "==> Checking the audit log can be disabled..."
+  incus config set audit.enabled=false
+  rm -f "${audit_log}"*
+  incus project delete audit-test
+  [ ! -e "${audit_log}" ]
+
+  incus config unset audit.file.max_size
+  incus config unset audit.file.max_files
+  incus config unset core.https_address
+}
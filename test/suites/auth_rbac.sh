@@ -0,0 +1,148 @@
+test_auth_rbac() {
+  incus config set core.https_address "${INCUS_ADDR}"
+  ensure_has_localhost_remote "${INCUS_ADDR}"
+  ensure_import_testimage
+
+  # Run OIDC server.
+  spawn_oidc
+  set_oidc user1
+
+  incus config set "oidc.issuer=http://127.0.0.1:$(cat "${TEST_DIR}/oidc.port")/"
+  incus config set "oidc.client.id=device"
+
+  BROWSER=curl incus remote add --accept-certificate oidc-rbac "${INCUS_ADDR}" --auth-type oidc
+  [ "$(incus info oidc-rbac: | grep ^auth_user_name | sed "s/.*: //g")" = "user1" ]
+
+  # Enable the built-in RBAC driver.
+  incus config set authorization.rbac=true
+
+  echo "==> Checking group management..."
+  incus auth group create contractors --description "External contractors"
+  incus auth group list -f csv | grep -q '^contractors,External contractors,0,0$'
+  ! incus auth group create contractors || false
+  ! incus auth group permission add contractors can_edit project:default || false
+  ! incus auth group permission add contractors viewer project: || false
+  incus auth group permission add contractors viewer project:default
+  ! incus auth group permission add contractors viewer project:default || false
+  incus auth group show contractors | grep -q 'object: project:default'
+  incus auth group rename contractors temps
+  incus auth group rename temps contractors
+
+  echo "==> Checking permissions for unknown user..."
+  incus info oidc-rbac: > /dev/null
+  ! incus info oidc-rbac: | grep -Fq 'core.https_address' || false
+  ! incus project list oidc-rbac: -f csv | grep -Fq 'default' || false
+  [ "$(incus list oidc-rbac: -f csv 2>/dev/null | wc -l)" = 0 ]
+
+  echo "==> Checking identity management..."
+  ! incus auth identity create oidc/user1 missing || false
+  incus auth identity create oidc/user1 contractors
+  incus auth identity list -f csv | grep -q '^oidc,user1,contractors$'
+  incus auth group show contractors | grep -q '/1.0/auth/identities/oidc/user1'
+
+  echo "==> Checking permissions for project viewer..."
+  incus project list oidc-rbac: -f csv | grep -Fq 'default'
+  incus init testimage c1
+  incus list oidc-rbac: -f csv -cn | grep -q '^c1$'
+  ! incus config set oidc-rbac:c1 user.foo=bar || false
+  ! incus exec oidc-rbac:c1 -- true || false
+  ! incus project set oidc-rbac:default user.foo bar || false
+  ! incus init --empty oidc-rbac:c2 || false
+
+  echo "==> Checking permissions for instance user..."
+  incus auth group permission remove contractors viewer project:default
+  incus auth group permission add contractors user instance:default/c1
+  incus start c1
+  incus exec oidc-rbac:c1 -- true
+  ! incus config set oidc-rbac:c1 user.foo=bar || false
+
+  echo "==> Checking permissions follow renames..."
+  incus stop c1 --force
+  incus rename c1 c3
+  incus auth group show contractors | grep -q 'object: instance:default/c3'
+  incus delete c3
+  ! incus auth group show contractors | grep -q 'object: instance:' || false
+
+  echo "==> Checking permissions for server admin..."
+  incus auth group create admins
+  incus auth group permission add admins admin server:incus
+  incus auth identity group add oidc/user1 admins
+  incus info oidc-rbac: | grep -Fq 'core.https_address'
+  incus project create oidc-rbac:foo
+  incus project delete oidc-rbac:foo
+  incus auth identity group remove oidc/user1 admins
+  ! incus project create oidc-rbac:foo || false
+
+  echo "==> Checking the driver can be disabled..."
+  incus config unset authorization.rbac
+  incus info oidc-rbac: | grep -Fq 'core.https_address'
+  incus config set authorization.rbac=true
+
+  # Cleanup.
+  incus auth identity delete oidc/user1
+  incus auth group delete contractors
+  incus auth group delete admins
+  [ "$(incus auth group list -f csv | wc -l)" = 0 ]
+  [ "$(incus auth identity list -f csv | wc -l)" = 0 ]
+
+  kill_oidc
+  incus config unset authorization.rbac
+  incus config unset oidc.issuer
+  incus config unset oidc.client.id
+  incus remote remove oidc-rbac
+}
+
+test_auth_rbac_oidc_groups() {
+  incus config set core.https_address "${INCUS_ADDR}"
+  ensure_has_localhost_remote "${INCUS_ADDR}"
+  ensure_import_testimage
+
+  # Run OIDC server with a user in two identity provider groups.
+  spawn_oidc
+  set_oidc user2 "devs,ops"
+
+  incus config set "oidc.issuer=http://127.0.0.1:$(cat "${TEST_DIR}/oidc.port")/"
+  incus config set "oidc.client.id=device"
+  incus config set "oidc.groups.claim=groups"
+
+  BROWSER=curl incus remote add --accept-certificate oidc-groups "${INCUS_ADDR}" --auth-type oidc
+  [ "$(incus info oidc-groups: | grep ^auth_user_name | sed "s/.*: //g")" = "user2" ]
+
+  # Enable the built-in RBAC driver.
+  incus config set authorization.rbac=true
+  incus project create foo -c features.images=false -c features.profiles=false
+
+  echo "==> Checking permissions without mapped groups..."
+  ! incus project list oidc-groups: -f csv | grep -Fq 'foo' || false
+
+  echo "==> Checking permissions through mapped groups..."
+  incus auth group create foo-operators
+  incus auth group permission add foo-operators operator project:foo
+  ! incus auth group identity-provider-group add foo-operators "" || false
+  incus auth group identity-provider-group add foo-operators devs
+  incus auth group show foo-operators | grep -q '^- devs$'
+  incus project list oidc-groups: -f csv | grep -Fq 'foo'
+  incus init --empty oidc-groups:c1 --project foo
+  incus delete oidc-groups:c1 --project foo
+  ! incus init --empty oidc-groups:c1 --project default || false
+  ! incus project set oidc-groups:foo user.foo bar || false
+
+  echo "==> Checking permissions after unmapping..."
+  incus auth group identity-provider-group remove foo-operators devs
+  ! incus project list oidc-groups: -f csv | grep -Fq 'foo' || false
+
+  echo "==> Checking the groups claim is optional..."
+  incus auth group identity-provider-group add foo-operators devs
+  incus config unset oidc.groups.claim
+  ! incus project list oidc-groups: -f csv | grep -Fq 'foo' || false
+
+  # Cleanup.
+  incus auth group delete foo-operators
+  incus project delete foo
+
+  kill_oidc
+  incus config unset authorization.rbac
+  incus config unset oidc.issuer
+  incus config unset oidc.client.id
+  incus remote remove oidc-groups
+}
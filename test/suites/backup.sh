@@ -959,6 +959,141 @@ test_backup_volume_expiry() {
   incus storage volume delete "${poolName}" vol1
 }
 
+test_backup_schedule() {
+  ensure_import_testimage
+
+  poolName=$(incus profile device get default root pool)
+
+  incus init testimage c1
+  incus storage volume create "${poolName}" vol1
+
+  # Check validation of the configuration keys.
+  ! incus config set c1 backups.schedule "invalid" || false
+  ! incus config set c1 backups.expiry "invalid" || false
+  ! incus config set c1 backups.target "relative/path" || false
+  ! incus storage volume set "${poolName}" vol1 backups.target "relative/path" || false
+
+  # Backups kept on the server.
+  incus config set c1 backups.schedule "* * * * *"
+  incus config set c1 backups.expiry "1d"
+  incus storage volume set "${poolName}" vol1 backups.schedule "* * * * *"
+
+  # Backups written to a target directory.
+  mkdir -p "${INCUS_DIR}/scheduled-backups"
+  incus storage volume create "${poolName}" vol2
+  incus storage volume set "${poolName}" vol2 backups.schedule "* * * * *"
+  incus storage volume set "${poolName}" vol2 backups.target "${INCUS_DIR}/scheduled-backups"
+
+  # Wait for the scheduled backups to be taken.
+  for _ in $(seq 90); do
+    if [ "$(incus query /1.0/instances/c1/backups | jq '.[]' | wc -l)" -ge 1 ] && [ "$(incus query /1.0/storage-pools/"${poolName}"/volumes/custom/vol1/backups | jq '.[]' | wc -l)" -ge 1 ] && ls "${INCUS_DIR}/scheduled-backups/${poolName}_vol2-scheduled-"*.backup >/dev/null 2>&1; then
+      break
+    fi
+
+    sleep 1
+  done
+
+  incus query /1.0/instances/c1/backups?recursion=1 | jq -r '.[0].name' | grep -q '^scheduled-'
+  [ "$(incus query /1.0/instances/c1/backups?recursion=1 | jq -r '.[0].expires_at')" != "0001-01-01T00:00:00Z" ]
+  incus query /1.0/storage-pools/"${poolName}"/volumes/custom/vol1/backups?recursion=1 | jq -r '.[0].name' | grep -q '^scheduled-'
+
+  # Backups written to a target aren't kept on the server.
+  ls "${INCUS_DIR}/scheduled-backups/${poolName}_vol2-scheduled-"*.backup
+  [ "$(incus query /1.0/storage-pools/"${poolName}"/volumes/custom/vol2/backups | jq '.[]' | wc -l)" -eq 0 ]
+
+  # The export files can be imported.
+  incus storage volume import "${poolName}" "$(ls "${INCUS_DIR}/scheduled-backups/${poolName}_vol2-scheduled-"*.backup | head -n1)" vol3
+  incus storage volume delete "${poolName}" vol3
+
+  # Scheduled backups written to a target are not allowed in restricted projects.
+  incus project create foo -c features.images=false -c features.profiles=false -c restricted=true
+  ! incus storage volume create "${poolName}" vol4 backups.target="${INCUS_DIR}/scheduled-backups" --project foo || false
+  ! incus init testimage c2 -c backups.target="${INCUS_DIR}/scheduled-backups" --project foo || false
+  incus project delete foo
+
+  # Cleanup.
+  incus delete -f c1
+  incus storage volume delete "${poolName}" vol1
+  incus storage volume delete "${poolName}" vol2
+  rm -rf "${INCUS_DIR}/scheduled-backups"
+}
+
+test_backup_bucket() {
+  # shellcheck disable=2039,3043
+  local incus_backend
+
+  incus_backend=$(storage_backend "$INCUS_DIR")
+
+  if [ "$incus_backend" = "ceph" ]; then
+    export TEST_UNMET_REQUIREMENT="local storage buckets are required"
+    return
+  elif ! command -v minio ; then
+    export TEST_UNMET_REQUIREMENT="minio command not found"
+    return
+  fi
+
+  ensure_import_testimage
+
+  poolName=$(incus profile device get default root pool)
+  bucketPool="${poolName}"
+
+  # MinIO doesn't support running on tmpfs (which the test suite can do).
+  if [ "$incus_backend" = "dir" ]; then
+    configure_loop_device loop_file_1 loop_device_1
+    # shellcheck disable=SC2154
+    mkfs.ext4 "${loop_device_1}"
+    mkdir "${TEST_DIR}/backup-bucket"
+    mount "${loop_device_1}" "${TEST_DIR}/backup-bucket"
+    losetup -d "${loop_device_1}"
+    mkdir "${TEST_DIR}/backup-bucket/s3"
+    incus storage create s3 dir source="${TEST_DIR}/backup-bucket/s3"
+    bucketPool="s3"
+  fi
+
+  incus config set core.storage_buckets_address "127.0.0.1:$(local_tcp_port)"
+  incus storage bucket create "${bucketPool}" backups
+
+  incus init testimage c1
+  incus storage volume create "${poolName}" vol1
+
+  # Check validation of the bucket location.
+  ! incus export c1 --bucket "${bucketPool}/backups" || false
+  ! incus export c1 c1.tar.gz --bucket "${bucketPool}/missing" || false
+  ! incus export c1 c1.tar.gz --bucket "${bucketPool}/backups" --keep || false
+
+  # Upload the backups to the bucket.
+  incus export c1 instances/c1.tar.gz --bucket "${bucketPool}/backups"
+  incus storage volume export "${poolName}" vol1 volumes/vol1.tar.gz --bucket "${bucketPool}/backups"
+
+  # Uploaded backups aren't kept on the server.
+  [ "$(incus query /1.0/instances/c1/backups | jq '.[]' | wc -l)" -eq 0 ]
+  [ "$(incus query /1.0/storage-pools/"${poolName}"/volumes/custom/vol1/backups | jq '.[]' | wc -l)" -eq 0 ]
+
+  # Import the backups from the bucket.
+  incus import instances/c1.tar.gz c2 --bucket "${bucketPool}/backups"
+  incus info c2
+  incus storage volume import "${poolName}" volumes/vol1.tar.gz vol2 --bucket "${bucketPool}/backups"
+  incus storage volume show "${poolName}" vol2
+
+  ! incus import instances/missing.tar.gz c3 --bucket "${bucketPool}/backups" || false
+
+  # Cleanup.
+  incus delete -f c1 c2
+  incus storage volume delete "${poolName}" vol1
+  incus storage volume delete "${poolName}" vol2
+  incus storage bucket delete "${bucketPool}" backups
+  incus config unset core.storage_buckets_address
+
+  if [ "$incus_backend" = "dir" ]; then
+    incus storage delete s3
+    umount "${TEST_DIR}/backup-bucket"
+    rmdir "${TEST_DIR}/backup-bucket"
+
+    # shellcheck disable=SC2154
+    deconfigure_loop_device "${loop_file_1}" "${loop_device_1}"
+  fi
+}
+
 test_backup_export_import_recover() {
   (
     set -e
@@ -1016,3 +1151,54 @@ test_backup_export_import_instance_only() {
   rm "${INCUS_DIR}/c1.tar.gz"
   incus delete -f c1
 }
+
+test_backup_export_import_incremental() {
+  incus_backend=$(storage_backend "$INCUS_DIR")
+  if [ "$incus_backend" != "zfs" ]; then
+    echo "==> SKIP: Incremental backups require the ZFS storage driver"
+    return
+  fi
+
+  ensure_import_testimage
+  ensure_has_localhost_remote "${INCUS_ADDR}"
+
+  incus init testimage c1
+
+  # Start a chain with a full export that's kept on the server.
+  incus export c1 "${INCUS_DIR}/c1-full.tar.gz" --optimized-storage --instance-only --keep
+  [ "$(incus query /1.0/instances/c1/backups | jq -r '.[0]')" = "/1.0/instances/c1/backups/backup0" ]
+
+  # Export the changes since the full export.
+  echo "first" > "${INCUS_DIR}/first.txt"
+  incus file push "${INCUS_DIR}/first.txt" c1/root/first.txt
+  incus export c1 "${INCUS_DIR}/c1-incr1.tar.gz" --incremental-from backup0
+  [ "$(incus query /1.0/instances/c1/backups/backup1 | jq -r '.incremental_from')" = "backup0" ]
+  ! incus query /1.0/instances/c1/backups/backup0 || false
+
+  # Basing an incremental backup on a missing backup must fail.
+  ! incus export c1 "${INCUS_DIR}/c1-invalid.tar.gz" --incremental-from backup0 || false
+
+  echo "second" > "${INCUS_DIR}/second.txt"
+  incus file push "${INCUS_DIR}/second.txt" c1/root/second.txt
+  incus export c1 "${INCUS_DIR}/c1-incr2.tar.gz" --incremental-from backup1
+
+  # Incremental exports can't be used to create a new instance.
+  incus delete -f c1
+  ! incus import "${INCUS_DIR}/c1-incr1.tar.gz" || false
+
+  # Restore the chain.
+  incus import "${INCUS_DIR}/c1-full.tar.gz"
+  ! incus file pull c1/root/first.txt - || false
+  incus import "${INCUS_DIR}/c1-incr1.tar.gz" c1
+  [ "$(incus file pull c1/root/first.txt -)" = "first" ]
+  ! incus file pull c1/root/second.txt - || false
+  incus import "${INCUS_DIR}/c1-incr2.tar.gz" c1
+  [ "$(incus file pull c1/root/second.txt -)" = "second" ]
+
+  # Applying an incremental export out of order must fail.
+  ! incus import "${INCUS_DIR}/c1-incr1.tar.gz" c1 || false
+
+  incus start c1
+  incus delete -f c1
+  rm -f "${INCUS_DIR}"/c1-*.tar.gz "${INCUS_DIR}/first.txt" "${INCUS_DIR}/second.txt"
+}
@@ -0,0 +1,113 @@
+test_clustering_instance_placement_rules() {
+  # shellcheck disable=2039,3043,SC2034
+  local INCUS_DIR
+
+  setup_clustering_bridge
+  prefix="inc$$"
+  bridge="${prefix}"
+
+  # The random storage backend is not supported in clustering tests,
+  # since we need to have the same storage driver on all nodes, so use the driver chosen for the standalone pool.
+  poolDriver=$(incus storage show "$(incus profile device get default root pool)" | awk '/^driver:/ {print $2}')
+
+  # Spawn first node
+  setup_clustering_netns 1
+  INCUS_ONE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  chmod +x "${INCUS_ONE_DIR}"
+  ns1="${prefix}1"
+  spawn_incus_and_bootstrap_cluster "${ns1}" "${bridge}" "${INCUS_ONE_DIR}" "${poolDriver}"
+
+  # The state of the preseeded storage pool shows up as CREATED
+  INCUS_DIR="${INCUS_ONE_DIR}" incus storage list | grep data | grep -q CREATED
+
+  # Add a newline at the end of each line. YAML has weird rules.
+  cert=$(sed ':a;N;$!ba;s/\n/\n\n/g' "${INCUS_ONE_DIR}/cluster.crt")
+
+  # Spawn a second node
+  setup_clustering_netns 2
+  INCUS_TWO_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  chmod +x "${INCUS_TWO_DIR}"
+  ns2="${prefix}2"
+  spawn_incus_and_join_cluster "${ns2}" "${bridge}" "${cert}" 2 1 "${INCUS_TWO_DIR}" "${INCUS_ONE_DIR}" "${poolDriver}"
+
+  # Spawn a third node
+  setup_clustering_netns 3
+  INCUS_THREE_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  chmod +x "${INCUS_THREE_DIR}"
+  ns3="${prefix}3"
+  spawn_incus_and_join_cluster "${ns3}" "${bridge}" "${cert}" 3 1 "${INCUS_THREE_DIR}" "${INCUS_ONE_DIR}" "${poolDriver}"
+
+  INCUS_DIR="${INCUS_ONE_DIR}" ensure_import_testimage
+
+  # Check the placement keys are validated.
+  ! INCUS_DIR="${INCUS_ONE_DIR}" incus init testimage c1 -c placement.group=foo_bar || false
+  ! INCUS_DIR="${INCUS_ONE_DIR}" incus init testimage c1 -c placement.anti_affinity=foo,-bar || false
+
+  # Check that anti-affinity places each instance of the group on a different member.
+  INCUS_DIR="${INCUS_ONE_DIR}" incus profile create web
+  INCUS_DIR="${INCUS_ONE_DIR}" incus profile show default | incus profile edit web
+  INCUS_DIR="${INCUS_ONE_DIR}" incus profile set web placement.group=web placement.anti_affinity=web
+  INCUS_DIR="${INCUS_ONE_DIR}" incus init testimage web1 -p web
+  INCUS_DIR="${INCUS_TWO_DIR}" incus init testimage web2 -p web
+  INCUS_DIR="${INCUS_THREE_DIR}" incus init testimage web3 -p web
+  [ "$(INCUS_DIR="${INCUS_ONE_DIR}" incus list web -c L -f csv | sort -u | wc -l)" = "3" ]
+
+  # No member is left for a fourth instance.
+  ! INCUS_DIR="${INCUS_ONE_DIR}" incus init testimage web4 -p web || false
+
+  # Explicit targeting bypasses the placement rules.
+  INCUS_DIR="${INCUS_ONE_DIR}" incus init testimage web4 -p web --target node1
+  INCUS_DIR="${INCUS_ONE_DIR}" incus delete web4
+
+  # Check that affinity places the instance with the instances of the group.
+  location="$(INCUS_DIR="${INCUS_ONE_DIR}" incus list web2 -c L -f csv)"
+  INCUS_DIR="${INCUS_ONE_DIR}" incus init testimage cache1 -c placement.group=cache -c placement.affinity=cache --target "${location}"
+  INCUS_DIR="${INCUS_ONE_DIR}" incus init testimage cache2 -c placement.group=cache -c placement.affinity=cache
+  INCUS_DIR="${INCUS_ONE_DIR}" incus info cache2 | grep -q "Location: ${location}"
+  INCUS_DIR="${INCUS_ONE_DIR}" incus delete cache1 cache2
+
+  # Check that evacuation doesn't move instances onto a member hosting another instance of the group.
+  INCUS_DIR="${INCUS_ONE_DIR}" incus config set web1 cluster.evacuate=migrate
+  location="$(INCUS_DIR="${INCUS_ONE_DIR}" incus list web1 -c L -f csv)"
+  INCUS_DIR="${INCUS_ONE_DIR}" incus cluster evacuate "${location}" --force
+  INCUS_DIR="${INCUS_ONE_DIR}" incus info web1 | grep -q "Location: ${location}"
+  INCUS_DIR="${INCUS_ONE_DIR}" incus cluster restore "${location}" --force
+  INCUS_DIR="${INCUS_ONE_DIR}" incus delete web1 web2 web3
+  INCUS_DIR="${INCUS_ONE_DIR}" incus profile delete web
+
+  # Check that spread alternates between the cluster groups.
+  INCUS_DIR="${INCUS_ONE_DIR}" incus cluster group create zone1
+  INCUS_DIR="${INCUS_ONE_DIR}" incus cluster group create zone2
+  INCUS_DIR="${INCUS_ONE_DIR}" incus cluster group assign node1 default,zone1
+  INCUS_DIR="${INCUS_ONE_DIR}" incus cluster group assign node2 default,zone1
+  INCUS_DIR="${INCUS_ONE_DIR}" incus cluster group assign node3 default,zone2
+  INCUS_DIR="${INCUS_ONE_DIR}" incus init testimage db1 -c placement.group=db -c placement.spread=zone1,zone2 --target node1
+  INCUS_DIR="${INCUS_ONE_DIR}" incus init testimage db2 -c placement.group=db -c placement.spread=zone1,zone2
+  INCUS_DIR="${INCUS_ONE_DIR}" incus info db2 | grep -q "Location: node3"
+  INCUS_DIR="${INCUS_ONE_DIR}" incus init testimage db3 -c placement.group=db -c placement.spread=zone1
+  INCUS_DIR="${INCUS_ONE_DIR}" incus info db3 | grep -q "Location: node2"
+  INCUS_DIR="${INCUS_ONE_DIR}" incus delete db1 db2 db3
+
+  # Delete the storage pool
+  printf 'config: {}\ndevices: {}' | INCUS_DIR="${INCUS_ONE_DIR}" incus profile edit default
+  INCUS_DIR="${INCUS_ONE_DIR}" incus storage delete data
+
+  # Shut down cluster
+  INCUS_DIR="${INCUS_ONE_DIR}" incus admin shutdown
+  INCUS_DIR="${INCUS_TWO_DIR}" incus admin shutdown
+  INCUS_DIR="${INCUS_THREE_DIR}" incus admin shutdown
+  sleep 0.5
+  rm -f "${INCUS_ONE_DIR}/unix.socket"
+  rm -f "${INCUS_TWO_DIR}/unix.socket"
+  rm -f "${INCUS_THREE_DIR}/unix.socket"
+
+  teardown_clustering_netns
+  teardown_clustering_bridge
+
+  kill_incus "${INCUS_ONE_DIR}"
+  kill_incus "${INCUS_TWO_DIR}"
+  kill_incus "${INCUS_THREE_DIR}"
+
+  # shellcheck disable=SC2034
+  INCUS_NETNS=
+}
@@ -12,6 +12,27 @@ test_container_oci() {
 
   incus launch docker:caddy caddy --network=inct$$
   incus info caddy | grep -q RUNNING
+
+  # Publish as an OCI image layout.
+  incus config set caddy environment.INCUS_TEST=oci
+  incus exec caddy -- rm /etc/caddy/Caddyfile
+  incus publish caddy --force --format=oci --output="${TEST_DIR}/caddy.tar"
+  tar -tf "${TEST_DIR}/caddy.tar" | grep -qx index.json
+  tar -tf "${TEST_DIR}/caddy.tar" | grep -qx oci-layout
+
+  # The layers of the source image are kept, the changes being in an additional layer.
+  mkdir "${TEST_DIR}/caddy"
+  tar -xf "${TEST_DIR}/caddy.tar" -C "${TEST_DIR}/caddy"
+  manifest="$(jq -r '.manifests[0].digest' "${TEST_DIR}/caddy/index.json" | cut -d: -f2)"
+  [ "$(jq -r '.layers | length' "${TEST_DIR}/caddy/blobs/sha256/${manifest}")" -gt 1 ]
+  changes="$(jq -r '.layers[-1].digest' "${TEST_DIR}/caddy/blobs/sha256/${manifest}" | cut -d: -f2)"
+  tar -tzf "${TEST_DIR}/caddy/blobs/sha256/${changes}" | grep -qx etc/caddy/.wh.Caddyfile
+  ! tar -tzf "${TEST_DIR}/caddy/blobs/sha256/${changes}" | grep -qx usr/bin/caddy || false
+  rm -rf "${TEST_DIR}/caddy"
+  ! incus publish caddy --format=oci || false
+  ! incus publish caddy --format=invalid || false
+  rm -f "${TEST_DIR}/caddy.tar"
+
   incus delete -f caddy
 
   incus network delete inct$$
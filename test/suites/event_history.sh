@@ -0,0 +1,36 @@
+test_event_history() {
+  stdbuf -oL incus monitor --type=lifecycle --format=json > "${TEST_DIR}/event_history.log" &
+  monitorPID=$!
+  sleep 1
+
+  incus profile create event-history-1
+  sleep 1
+  kill -9 "${monitorPID}" || true
+
+  # Get the identifier of the last event received before the disconnection.
+  id="$(jq -r 'select(.metadata.action == "profile-created") | .id' "${TEST_DIR}/event_history.log" | tail -n1)"
+  [ -n "${id}" ] && [ "${id}" != "null" ]
+
+  # Generate events while no client is connected.
+  incus profile create event-history-2
+  incus profile delete event-history-1
+
+  echo "==> Checking the missed events are replayed..."
+  timeout 5 incus monitor --type=lifecycle --format=json --since="${id}" > "${TEST_DIR}/event_history.log" || true
+  ! jq -e 'select(.metadata.source == "/1.0/profiles/event-history-1" and .metadata.action == "profile-created")' "${TEST_DIR}/event_history.log" || false
+  jq -e 'select(.metadata.source == "/1.0/profiles/event-history-2" and .metadata.action == "profile-created")' "${TEST_DIR}/event_history.log"
+  jq -e 'select(.metadata.source == "/1.0/profiles/event-history-1" and .metadata.action == "profile-deleted")' "${TEST_DIR}/event_history.log"
+
+  echo "==> Checking the history survives a restart..."
+  shutdown_incus "${INCUS_DIR}"
+  respawn_incus "${INCUS_DIR}" true
+  timeout 5 incus monitor --type=lifecycle --format=json --since="${id}" > "${TEST_DIR}/event_history.log" || true
+  jq -e 'select(.metadata.source == "/1.0/profiles/event-history-2" and .metadata.action == "profile-created")' "${TEST_DIR}/event_history.log"
+
+  echo "==> Checking invalid cursors are rejected..."
+  ! incus monitor --since=99999999999 || false
+  ! incus query "/1.0/events?since=foo" || false
+
+  incus profile delete event-history-2
+  rm -f "${TEST_DIR}/event_history.log"
+}
@@ -13,6 +13,17 @@ test_metrics() {
   # c2 metrics should not exist as it's not running
   ! incus query "/1.0/metrics" | grep "name=\"c2\"" || false
 
+  # project and storage pool metrics should be reported
+  incus query "/1.0/metrics" | grep "incus_project_usage{project=\"default\",resource=\"instances\"} 2"
+  incus query "/1.0/metrics" | grep "incus_storage_pool_space_bytes{driver=\"$(storage_backend "$INCUS_DIR")\""
+
+  # limits should only be reported once set
+  ! incus query "/1.0/metrics" | grep "incus_project_limit{project=\"default\",resource=\"instances\"}" || false
+  incus project set default limits.instances=10
+  sleep 9
+  incus query "/1.0/metrics" | grep "incus_project_limit{project=\"default\",resource=\"instances\"} 10"
+  incus project unset default limits.instances
+
   # create new certificate
   gen_cert_and_key "${TEST_DIR}/metrics.key" "${TEST_DIR}/metrics.crt" "metrics.local"
 
@@ -107,6 +107,57 @@ EOF
  incus network acl rule remove testacl ingress --force # Remove all ingress rules.
  incus network acl show testacl | grep 'ingress: \[\]' # Check all ingress rules removed.
 
+ # ACL rule limits.
+ ! incus network acl rule add testacl ingress action=allow rate_limit=foo || false # Invalid rate format
+ ! incus network acl rule add testacl ingress action=allow rate_limit=0/second || false # Invalid rate count
+ ! incus network acl rule add testacl ingress action=allow connection_rate=10/week || false # Invalid rate unit
+ ! incus network acl rule add testacl ingress action=allow connection_limit=0 || false # Invalid connection limit
+ ! incus network acl rule add testacl ingress action=allow-stateless connection_limit=10 || false # Invalid action
+ ! incus network acl rule add testacl ingress action=drop rate_limit=10/second connection_limit=10 || false # Multiple limits on drop
+ ! incus network acl rule add testacl ingress action=allow connection_rate=10Mbit/second || false # Bandwidth connection rate
+ incus network acl rule add testacl ingress action=allow protocol=tcp destination_port=22 connection_rate=10/minute connection_limit=20
+ incus network acl rule add testacl ingress action=drop protocol=tcp destination_port=80 rate_limit=100/second
+ incus network acl show testacl | grep 'connection_limit: "20"'
+ incus network acl rule add testacl egress action=allow rate_limit=10Mbit/second
+ incus network acl show testacl | grep "rate_limit: 100/second"
+ incus network acl show testacl | grep "rate_limit: 10Mbit/second"
+
+ firewallDriver=$(incus info | awk -F ":" '/firewall:/{gsub(/ /, "", $0); print $2}')
+ if [ "$firewallDriver" = "nftables" ]; then
+   incus network create "inct$$" ipv4.address=192.0.2.1/24 ipv6.address=none security.acls=testacl
+   nft list chain inet incus "acl.inct$$" | grep "ct state new limit rate 10/minute.* ct count 20 accept"
+   nft list chain inet incus "acl.inct$$" | grep "limit rate over 100/second.* drop"
+   nft list chain inet incus "acl.inct$$" | grep "limit rate over .*bytes/second drop"
+
+   # Rate limits must apply before established connections are accepted.
+   nft list chain inet incus "acl.inct$$" | sed '/ct state established,related accept/q' | grep "limit rate over 100/second"
+   incus network delete "inct$$"
+ fi
+
+ incus network acl rule remove testacl ingress --force
+ incus network acl rule remove testacl egress --force
+
+ # ACL rule domain names.
+ ! incus network acl rule add testacl ingress action=allow source=example.com || false # Not allowed in ingress rules
+ ! incus network acl rule add testacl egress action=allow source=example.com || false # Not allowed as source
+ ! incus network acl rule add testacl egress action=allow destination=-example.com || false # Invalid label
+ ! incus network acl rule add testacl egress action=allow destination=example.123 || false # Numeric top level domain
+
+ dnsmasqVersion=$(dnsmasq --version | awk 'NR==1 {print $3}')
+ if [ "$firewallDriver" = "nftables" ] && [ "$(printf '2.87\n%s\n' "${dnsmasqVersion}" | sort -V | head -n1)" = "2.87" ]; then
+   incus network acl rule add testacl egress action=allow protocol=tcp destination=*.debian.org,example.com destination_port=443
+   incus network acl show testacl | grep 'destination: \*.debian.org,example.com'
+
+   incus network create "inct$$" ipv4.address=192.0.2.1/24 ipv6.address=none security.acls=testacl
+   nft list table inet incus | grep -A3 "set dns_" | grep -q "timeout 10m"
+   grep -q "^max-ttl=600$" "${INCUS_DIR}/networks/inct$$/dnsmasq.nftset"
+   incus network delete "inct$$"
+ else
+   ! incus network acl rule add testacl egress action=allow destination=example.com || false # Requires nftables and dnsmasq >= 2.87
+ fi
+
+ incus network acl rule remove testacl egress --force
+
  # ACL rename.
  ! incus network acl rename testacl 192.168.1.1 || false # Don't allow non-hostname compatible names.
  incus network acl rename testacl testacl2
@@ -0,0 +1,69 @@
+test_network_address_set() {
+  ensure_has_localhost_remote "${INCUS_ADDR}"
+
+  # Check basic address set creation, listing, deletion and project namespacing support.
+  ! incus network address-set create 192.168.1.1 || false # Don't allow non-hostname compatible names.
+  incus network address-set create testset
+  incus project create testproj -c features.networks=true
+  incus network address-set create testset --project testproj
+  incus project show testproj | grep testset # Check project sees testset using it.
+  incus network address-set ls | grep testset
+  incus network address-set ls --project testproj | grep testset
+  incus network address-set delete testset --project testproj
+  ! incus network address-set ls --project testproj | grep testset || false
+  incus project delete testproj
+
+  # Address management.
+  incus network address-set add testset 192.0.2.10 192.0.2.0/28 2001:db8::/64
+  incus network address-set show testset | grep -- "- 192.0.2.10"
+  incus network address-set show testset | grep -- "- 2001:db8::/64"
+  ! incus network address-set add testset 192.0.2.10 || false # Duplicate address.
+  ! incus network address-set add testset foo || false # Invalid address.
+  ! incus network address-set add testset 192.0.2.1/24 || false # Host bits set.
+  incus network address-set remove testset 192.0.2.0/28
+  ! incus network address-set show testset | grep -- "- 192.0.2.0/28" || false
+  ! incus network address-set remove testset 192.0.2.0/28 || false # Address not in set.
+
+  # Address set patch keeps the existing addresses.
+  incus query -X PATCH -d "{\\\"config\\\": {\\\"user.mykey\\\": \\\"foo\\\"}}" /1.0/network-address-sets/testset
+  incus network address-set show testset | grep "user.mykey: foo"
+  incus network address-set show testset | grep -- "- 192.0.2.10"
+
+  # Address set usage from ACL rules.
+  incus network acl create testacl
+  ! incus network acl rule add testacl egress action=allow destination='$missing' || false # Missing address set.
+  incus network acl rule add testacl egress action=allow destination='$testset' protocol=tcp destination_port=443
+  incus network acl rule add testacl ingress action=allow source='$testset,192.0.2.20'
+  incus network address-set show testset | grep "/1.0/network-acls/testacl"
+  ! incus network address-set rename testset testset2 || false # In use.
+  ! incus network address-set delete testset || false # In use.
+
+  firewallDriver=$(incus info | awk -F ":" '/firewall:/{gsub(/ /, "", $0); print $2}')
+  if [ "$firewallDriver" = "nftables" ]; then
+    incus network create "inct$$" ipv4.address=192.0.2.1/24 ipv6.address=none security.acls=testacl
+    setID=$(nft list sets inet | awk '/set addrset_[0-9]+_ip4/{print $2; exit}')
+    nft list set inet incus "${setID}" | grep "192.0.2.10"
+    nft list chain inet incus "acl.inct$$" | grep "@${setID}"
+
+    # Updating the address set refreshes the firewall set.
+    incus network address-set add testset 192.0.2.11
+    nft list set inet incus "${setID}" | grep "192.0.2.11"
+    incus network delete "inct$$"
+  fi
+
+  incus network acl delete testacl
+  incus network address-set show testset | grep 'used_by: \[\]'
+
+  # Address set rename.
+  incus network address-set rename testset testset2
+  incus network address-set show testset2
+
+  # Address set custom config.
+  incus network address-set set testset2 user.somekey foo
+  incus network address-set get testset2 user.somekey | grep foo
+  ! incus network address-set set testset2 non.userkey || false
+  incus network address-set unset testset2 user.somekey
+  ! incus network address-set get testset2 user.somekey | grep foo || false
+
+  incus network address-set delete testset2
+}
@@ -0,0 +1,88 @@
+test_network_bridge_evpn() {
+  ensure_import_testimage
+
+  # Spawn a second daemon to peer with.
+  INCUS_EVPN_DIR=$(mktemp -d -p "${TEST_DIR}" XXX)
+  chmod +x "${INCUS_EVPN_DIR}"
+  spawn_incus "${INCUS_EVPN_DIR}" true
+
+  # Setup the BGP servers on separate loopback addresses.
+  incus config set core.bgp_address=127.0.0.2:179 core.bgp_asn=65001 core.bgp_routerid=127.0.0.2
+  INCUS_DIR="${INCUS_EVPN_DIR}" incus config set core.bgp_address=127.0.0.3:179 core.bgp_asn=65002 core.bgp_routerid=127.0.0.3
+
+  # Check validation.
+  ! incus network create evpntoolong ipv4.address=none ipv6.address=none bgp.evpn.vni=1000 || false
+  ! incus network create evpna ipv4.address=none ipv6.address=none bgp.evpn.vni=16777216 || false
+  ! incus network create evpna ipv4.address=none ipv6.address=none bgp.evpn.vni=1000 bgp.evpn.local=2001:db8::1 || false
+  ! incus network create evpna ipv4.address=none ipv6.address=none bgp.evpn.vni=1000 bridge.driver=openvswitch || false
+  ! incus network create evpna ipv4.address=none ipv6.address=none bgp.evpn.vni=1000 || false
+
+  # Create the EVPN bridges, using separate VXLAN ports as both live on the same host.
+  incus network create evpna ipv4.address=none ipv6.address=none \
+    bgp.peers.b.address=127.0.0.3 bgp.peers.b.asn=65002 \
+    bgp.evpn.vni=1000 bgp.evpn.local=127.0.0.2
+  INCUS_DIR="${INCUS_EVPN_DIR}" incus network create evpnb ipv4.address=none ipv6.address=none \
+    bgp.peers.a.address=127.0.0.2 bgp.peers.a.asn=65001 \
+    bgp.evpn.vni=1000 bgp.evpn.local=127.0.0.3 bgp.evpn.port=4790
+
+  # Check the VXLAN interfaces.
+  ip -d link show evpna-evpn | grep "vxlan id 1000"
+  ip -d link show evpna-evpn | grep "master evpna"
+  ip -d link show evpnb-evpn | grep "dstport 4790"
+  [ "$(cat /sys/class/net/evpna/mtu)" = "1400" ]
+
+  # Check the local tunnel endpoints are announced.
+  incus query /internal/debug/bgp | jq -e '.evpn_routes[] | select(.vni == 1000 and .vtep == "127.0.0.2")'
+
+  # Check the remote tunnel endpoints get installed.
+  for _ in $(seq 30); do
+    bridge fdb show dev evpna-evpn | grep -q "00:00:00:00:00:00 dst 127.0.0.3" && break
+    sleep 1
+  done
+
+  bridge fdb show dev evpna-evpn | grep "00:00:00:00:00:00 dst 127.0.0.3"
+  bridge fdb show dev evpnb-evpn | grep "00:00:00:00:00:00 dst 127.0.0.2"
+  incus query /internal/debug/bgp | jq -e '.evpn_remote_routes[] | select(.vni == 1000 and .vtep == "127.0.0.3")'
+
+  # Check instance MAC addresses are advertised.
+  incus init testimage c1 -n evpna
+  incus config device set c1 eth0 hwaddr=00:16:3e:00:10:01
+  incus start c1
+
+  for _ in $(seq 30); do
+    bridge fdb show dev evpnb-evpn | grep -q "00:16:3e:00:10:01 dst 127.0.0.2" && break
+    sleep 1
+  done
+
+  bridge fdb show dev evpnb-evpn | grep "00:16:3e:00:10:01 dst 127.0.0.2"
+
+  # Check stopping the instance withdraws its MAC address.
+  incus stop -f c1
+
+  for _ in $(seq 30); do
+    ! bridge fdb show dev evpnb-evpn | grep -q "00:16:3e:00:10:01" && break
+    sleep 1
+  done
+
+  ! bridge fdb show dev evpnb-evpn | grep "00:16:3e:00:10:01" || false
+  incus delete c1
+
+  # Check deleting the network withdraws its tunnel endpoint.
+  incus network delete evpna
+  ! ip link show evpna-evpn || false
+  ! incus query /internal/debug/bgp | jq -e '.evpn_routes[] | select(.vni == 1000)' || false
+
+  for _ in $(seq 30); do
+    ! bridge fdb show dev evpnb-evpn | grep -q "dst 127.0.0.2" && break
+    sleep 1
+  done
+
+  ! bridge fdb show dev evpnb-evpn | grep "dst 127.0.0.2" || false
+
+  # Cleanup.
+  INCUS_DIR="${INCUS_EVPN_DIR}" incus network delete evpnb
+  incus config unset core.bgp_address
+  incus config unset core.bgp_asn
+  incus config unset core.bgp_routerid
+  kill_incus "${INCUS_EVPN_DIR}"
+}
@@ -0,0 +1,87 @@
+test_network_wireguard() {
+  if ! command -v wg >/dev/null 2>&1; then
+    export TEST_UNMET_REQUIREMENT="wg command not found"
+    return
+  fi
+
+  netName=incwg$$
+  nsName="incwg$$"
+  listenPort="$(local_tcp_port)"
+
+  # Check validation of the network config.
+  ! incus network create "${netName}" --type=wireguard wireguard.ipv4.address=invalid || false
+  ! incus network create "${netName}" --type=wireguard wireguard.listen_port=100000 || false
+
+  incus network create "${netName}" --type=wireguard \
+        wireguard.ipv4.address=10.250.0.1/24 \
+        wireguard.listen_port="${listenPort}"
+
+  # Check the interface and generated key.
+  ip link show "${netName}" | grep -F "mtu 1420"
+  localKey="$(incus query "/1.0/networks/${netName}/state" | jq -r .wireguard.public_key)"
+  [ -n "${localKey}" ]
+  [ "$(incus query "/1.0/networks/${netName}/state" | jq -r .wireguard.listen_port)" = "${listenPort}" ]
+  incus network info "${netName}" | grep -F "${localKey}"
+
+  # Setup a remote WireGuard endpoint in a separate network namespace.
+  ip netns add "${nsName}"
+  ip link add "${nsName}a" type veth peer name "${nsName}b"
+  ip link set "${nsName}b" netns "${nsName}"
+  ip addr add 192.0.2.1/30 dev "${nsName}a"
+  ip link set "${nsName}a" up
+  ip netns exec "${nsName}" ip addr add 192.0.2.2/30 dev "${nsName}b"
+  ip netns exec "${nsName}" ip link set "${nsName}b" up
+
+  wg genkey > "${TEST_DIR}/wg.key"
+  remoteKey="$(wg pubkey < "${TEST_DIR}/wg.key")"
+  ip netns exec "${nsName}" ip link add wg0 type wireguard
+  ip netns exec "${nsName}" wg set wg0 private-key "${TEST_DIR}/wg.key" listen-port 51820 peer "${localKey}" allowed-ips 10.250.0.1/32 endpoint "192.0.2.1:${listenPort}"
+  ip netns exec "${nsName}" ip addr add 10.250.0.2/24 dev wg0
+  ip netns exec "${nsName}" ip addr add 10.251.0.1/24 dev lo
+  ip netns exec "${nsName}" ip link set wg0 up
+
+  # Check validation of the peers.
+  ! incus network peer create "${netName}" remote1 --type=wireguard wireguard.public_key=invalid || false
+  ! incus network peer create "${netName}" remote1 --type=wireguard wireguard.public_key="${remoteKey}" wireguard.allowed_ips=invalid || false
+  ! incus network peer create "${netName}" remote1 foo --type=local || false
+
+  # Add the remote endpoint as a peer.
+  incus network peer create "${netName}" remote1 --type=wireguard \
+        wireguard.public_key="${remoteKey}" \
+        wireguard.endpoint=192.0.2.2:51820 \
+        wireguard.allowed_ips=10.250.0.2/32,10.251.0.0/24
+  incus network peer show "${netName}" remote1 | grep -F "type: wireguard"
+  incus network peer show "${netName}" remote1 | grep -F "status: Created"
+
+  # Check the peer subnets are routed through the interface.
+  ip route show dev "${netName}" | grep -F "10.251.0.0/24"
+  wg show "${netName}" peers | grep -F "${remoteKey}"
+
+  # Check that traffic flows through the overlay.
+  ping -c1 -W5 10.250.0.2
+  ping -c1 -W5 10.251.0.1
+  [ "$(incus query "/1.0/networks/${netName}/state" | jq -r '.wireguard.peers[0].name')" = "remote1" ]
+
+  # Check that overlapping peers are rejected.
+  ! incus network peer create "${netName}" remote2 --type=wireguard wireguard.public_key="$(wg genkey | wg pubkey)" wireguard.allowed_ips=10.251.0.128/25 || false
+
+  # Check that peer updates are applied.
+  incus network peer set "${netName}" remote1 wireguard.allowed_ips=10.250.0.2/32
+  ! ip route show dev "${netName}" | grep -F "10.251.0.0/24" || false
+
+  # Check that the config survives a restart of the network.
+  incus network set "${netName}" mtu=1400
+  ip link show "${netName}" | grep -F "mtu 1400"
+  wg show "${netName}" peers | grep -F "${remoteKey}"
+  [ "$(incus query "/1.0/networks/${netName}/state" | jq -r .wireguard.public_key)" = "${localKey}" ]
+
+  # Cleanup.
+  incus network peer delete "${netName}" remote1
+  ! wg show "${netName}" peers | grep -F "${remoteKey}" || false
+  incus network delete "${netName}"
+  ! ip link show "${netName}" || false
+
+  ip link delete "${nsName}a"
+  ip netns delete "${nsName}"
+  rm -f "${TEST_DIR}/wg.key"
+}
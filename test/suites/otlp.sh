@@ -0,0 +1,55 @@
+test_otlp() {
+  port="$(local_tcp_port)"
+
+  # Start a minimal OTLP collector stand-in recording the requests it receives.
+  cat > "${TEST_DIR}/otlp-listener.sh" << EOF
+#!/bin/sh
+timeout 1 cat >> "${TEST_DIR}/otlp.log"
+printf 'HTTP/1.1 200 OK\r\nContent-Length: 0\r\nConnection: close\r\n\r\n'
+EOF
+  chmod +x "${TEST_DIR}/otlp-listener.sh"
+  socat TCP-LISTEN:"${port}",reuseaddr,fork EXEC:"${TEST_DIR}/otlp-listener.sh" &
+  listenerPID=$!
+
+  echo "==> Checking the configuration is validated..."
+  ! incus config set otlp.api.headers=foo || false
+  ! incus config set otlp.types=audit || false
+  ! incus config set otlp.metrics.interval=-1 || false
+
+  echo "==> Checking the export..."
+  incus config set otlp.api.url="http://127.0.0.1:${port}" otlp.api.headers="Authorization=Bearer s3cr3t" otlp.metrics.interval=1
+  incus profile create otlp-profile
+
+  for _ in $(seq 10); do
+    grep -q "POST /v1/logs" "${TEST_DIR}/otlp.log" && grep -q "POST /v1/traces" "${TEST_DIR}/otlp.log" && grep -q "POST /v1/metrics" "${TEST_DIR}/otlp.log" && break
+    sleep 1
+  done
+
+  grep -q "Authorization: Bearer s3cr3t" "${TEST_DIR}/otlp.log"
+  grep -q '"stringValue":"profile-created"' "${TEST_DIR}/otlp.log"
+  grep -q '"name":"POST /1.0/profiles"' "${TEST_DIR}/otlp.log"
+  grep -q '"name":"incus_go_goroutines"' "${TEST_DIR}/otlp.log"
+
+  echo "==> Checking the trace context of the client is honored..."
+  traceID="4bf92f3577b34da6a3ce929d0e0e4736"
+  curl -s --unix-socket "${INCUS_DIR}/unix.socket" -H "traceparent: 00-${traceID}-00f067aa0ba902b7-01" incus/1.0 > /dev/null
+
+  for _ in $(seq 10); do
+    grep -q "\"traceId\":\"${traceID}\"" "${TEST_DIR}/otlp.log" && break
+    sleep 1
+  done
+
+  grep -q "\"traceId\":\"${traceID}\",\"spanId\":\"[0-9a-f]*\",\"parentSpanId\":\"00f067aa0ba902b7\"" "${TEST_DIR}/otlp.log"
+
+  echo "==> Checking the export can be disabled..."
+  incus config unset otlp.api.url
+  incus config unset otlp.api.headers
+  incus config unset otlp.metrics.interval
+  : > "${TEST_DIR}/otlp.log"
+  incus profile delete otlp-profile
+  sleep 2
+  ! grep -q "POST /v1/" "${TEST_DIR}/otlp.log" || false
+
+  kill -9 "${listenerPID}" || true
+  rm -f "${TEST_DIR}/otlp.log" "${TEST_DIR}/otlp-listener.sh"
+}
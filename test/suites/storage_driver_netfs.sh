@@ -0,0 +1,61 @@
+test_storage_driver_netfs() {
+  # shellcheck disable=2039,3043
+  local incus_backend export_dir
+
+  incus_backend=$(storage_backend "$INCUS_DIR")
+  if [ "$incus_backend" != "dir" ] || ! command -v exportfs >/dev/null 2>&1; then
+    echo "==> SKIP: netfs tests require the dir backend and a kernel NFS server"
+    return
+  fi
+
+  # Export an empty directory over NFS on loopback.
+  export_dir=$(mktemp -d -p "${TEST_DIR}" XXX)
+  chmod +x "${export_dir}"
+  exportfs -o rw,no_root_squash,no_subtree_check,fsid=1000 "127.0.0.1:${export_dir}"
+
+  # Invalid sources are rejected.
+  ! incus storage create netfs netfs source="127.0.0.1" || false
+  ! incus storage create netfs netfs source="127.0.0.1:${export_dir}" netfs.protocol=smb || false
+
+  # Simple create/delete attempt
+  incus storage create netfs netfs source="127.0.0.1:${export_dir}"
+  [ "$(incus storage get netfs netfs.source)" = "127.0.0.1:${export_dir}" ]
+  [ "$(incus storage get netfs netfs.protocol)" = "nfs" ]
+  incus storage delete netfs
+
+  incus storage create netfs netfs source="127.0.0.1:${export_dir}"
+
+  # Creation, rename and deletion
+  incus storage volume create netfs vol1
+  incus storage volume rename netfs vol1 vol2
+  incus storage volume copy netfs/vol2 netfs/vol1
+  incus storage volume delete netfs vol1
+  incus storage volume delete netfs vol2
+
+  # Block volumes aren't supported.
+  ! incus storage volume create netfs vol1 --type=block || false
+
+  # Snapshots
+  incus storage volume create netfs vol1
+  incus storage volume snapshot create netfs vol1
+  incus storage volume snapshot create netfs vol1 blah1
+  incus storage volume snapshot rename netfs vol1 blah1 blah2
+  incus storage volume snapshot restore netfs vol1 blah2
+  incus storage volume snapshot delete netfs vol1 snap0
+  incus storage volume snapshot delete netfs vol1 blah2
+  incus storage volume delete netfs vol1
+
+  # Containers
+  ensure_import_testimage
+  incus init testimage c1 -s netfs
+  incus start c1
+  incus exec c1 -- touch /root/foo
+  incus snapshot create c1
+  incus stop c1 --force
+  incus delete c1
+
+  # Cleanup
+  incus storage delete netfs
+  exportfs -u "127.0.0.1:${export_dir}"
+  rm -rf "${export_dir}"
+}
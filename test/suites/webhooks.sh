@@ -0,0 +1,55 @@
+test_webhooks() {
+  port="$(local_tcp_port)"
+
+  # Start a minimal HTTP listener recording the requests it receives.
+  cat > "${TEST_DIR}/webhooks-listener.sh" << EOF
+#!/bin/sh
+timeout 1 cat >> "${TEST_DIR}/webhooks.log"
+printf 'HTTP/1.1 200 OK\r\nContent-Length: 0\r\nConnection: close\r\n\r\n'
+EOF
+  chmod +x "${TEST_DIR}/webhooks-listener.sh"
+  socat TCP-LISTEN:"${port}",reuseaddr,fork EXEC:"${TEST_DIR}/webhooks-listener.sh" &
+  listenerPID=$!
+
+  echo "==> Checking the webhook configuration is validated..."
+  ! incus webhook create invalid || false
+  ! incus webhook create invalid url=ftp://127.0.0.1/ || false
+  ! incus webhook create invalid url="http://127.0.0.1:${port}/" types=logging || false
+  ! incus webhook create invalid url="http://127.0.0.1:${port}/" foo=bar || false
+
+  echo "==> Checking webhook management..."
+  incus webhook create hook1 url="http://127.0.0.1:${port}/" secret=s3cr3t actions=profile-created projects=default
+  ! incus webhook create hook1 url="http://127.0.0.1:${port}/" || false
+  incus webhook list | grep -q hook1
+  [ "$(incus webhook get hook1 secret)" = "s3cr3t" ]
+  incus webhook set hook1 user.foo=bar
+  [ "$(incus webhook get hook1 user.foo)" = "bar" ]
+  incus webhook unset hook1 user.foo
+  [ "$(incus webhook get hook1 user.foo)" = "" ]
+  incus webhook show hook1 | grep -q "actions: profile-created"
+
+  echo "==> Checking the events are delivered..."
+  incus profile create webhooks-profile
+  incus profile set webhooks-profile user.foo=bar
+
+  for _ in $(seq 10); do
+    grep -q "profile-created" "${TEST_DIR}/webhooks.log" && break
+    sleep 1
+  done
+
+  grep -q "X-Incus-Signature: sha256=" "${TEST_DIR}/webhooks.log"
+  grep -q "X-Incus-Event: lifecycle" "${TEST_DIR}/webhooks.log"
+  grep -q '"source":"/1.0/profiles/webhooks-profile"' "${TEST_DIR}/webhooks.log"
+  ! grep -q "profile-updated" "${TEST_DIR}/webhooks.log" || false
+
+  echo "==> Checking webhook rename and deletion..."
+  incus webhook rename hook1 hook2
+  ! incus webhook show hook1 || false
+  incus webhook show hook2
+  incus webhook delete hook2
+  [ "$(incus query /1.0/webhooks | jq 'length')" = "0" ]
+
+  incus profile delete webhooks-profile
+  kill -9 "${listenerPID}" || true
+  rm -f "${TEST_DIR}/webhooks.log" "${TEST_DIR}/webhooks-listener.sh"
+}